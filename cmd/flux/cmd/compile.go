@@ -48,7 +48,7 @@ func compile(cmd *cobra.Command, args []string) error {
 		Query: script,
 	}
 
-	spec, err := c.Compile(context.Background(), runtime.Default)
+	spec, err := c.Compile(context.Background(), runtime.WithImporter(newImporter()))
 	if err != nil {
 		return err
 	}
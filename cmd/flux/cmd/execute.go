@@ -3,12 +3,22 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/influxdata/flux"
-	"github.com/influxdata/flux/dependencies/filesystem"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/dependencies/http"
 	"github.com/influxdata/flux/dependencies/influxdb"
+	"github.com/influxdata/flux/dependencies/secret"
 	"github.com/influxdata/flux/fluxinit"
+	"github.com/influxdata/flux/json"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/lineprotocol"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/repl"
+	"github.com/influxdata/flux/runtime"
 	"github.com/spf13/cobra"
 )
 
@@ -21,15 +31,75 @@ var executeCmd = &cobra.Command{
 	RunE:  execute,
 }
 
+var executeFlags struct {
+	format       string
+	secrets      string
+	memoryLimit  int64
+	spillReserve int64
+	spillDir     string
+	costBased    bool
+}
+
 func init() {
 	rootCmd.AddCommand(executeCmd)
+	executeCmd.Flags().StringVar(&executeFlags.format, "format", "table", "output format of the results: one of csv, json, table or line")
+	executeCmd.Flags().StringVar(&executeFlags.secrets, "secrets", "", secretsUsage)
+	executeCmd.Flags().Int64Var(&executeFlags.memoryLimit, "memory-limit", 0, "maximum number of bytes the query may allocate, unlimited if 0")
+	executeCmd.Flags().Int64Var(&executeFlags.spillReserve, "spill-reserve", 0, "number of bytes the query may allocate beyond --memory-limit while it spills buffered data to disk, spilling is disabled if 0")
+	executeCmd.Flags().BoolVar(&executeFlags.costBased, "cost-based-planning", false, costBasedPlanningUsage)
+	executeCmd.Flags().StringVar(&executeFlags.spillDir, "spill-dir", "", "directory that buffered data is spilled to, within --fs-root when it is set (default is --fs-root when it is set, otherwise the directory for temporary files)")
+}
+
+// newAllocator returns the allocator for a query with the memory
+// limit and spill options of the execute flags.
+func newAllocator() *memory.Allocator {
+	alloc := &memory.Allocator{}
+	if executeFlags.memoryLimit > 0 {
+		limit := executeFlags.memoryLimit
+		alloc.Limit = &limit
+		if executeFlags.spillReserve > 0 {
+			alloc.Manager = memory.NewSpillManager(executeFlags.spillReserve, executeFlags.spillDir)
+		}
+	}
+	return alloc
+}
+
+const costBasedPlanningUsage = "choose between planner rules that rewrite the same node by the estimated cost of the plan"
+
+// compileOptions returns the options that compile
+// a script with the planning flags of a command.
+func compileOptions(costBased bool) []lang.CompileOption {
+	var opts []lang.CompileOption
+	if costBased {
+		opts = append(opts, lang.WithCostBasedPlanning())
+	}
+	return opts
+}
+
+// encoders maps the name of each output format, other than the
+// human readable table format, to a constructor for its encoder.
+var encoders = map[string]func() flux.MultiResultEncoder{
+	"csv": func() flux.MultiResultEncoder {
+		return csv.NewMultiResultEncoder(csv.DefaultEncoderConfig())
+	},
+	"json": json.NewMultiResultEncoder,
+	"line": lineprotocol.NewMultiResultEncoder,
 }
 
 const DefaultInfluxDBHost = "http://localhost:9999"
 
-func injectDependencies(ctx context.Context) (context.Context, flux.Dependencies) {
+// injectDependencies injects the dependencies of the CLI into the context.
+// The default secret service is used when secrets is nil.
+func injectDependencies(ctx context.Context, secrets secret.Service) (context.Context, flux.Dependencies) {
 	deps := flux.NewDefaultDependencies()
-	deps.Deps.FilesystemService = filesystem.SystemFS
+	deps.Deps.FilesystemService = fileSystem
+	if urlValidator != nil {
+		deps.Deps.URLValidator = urlValidator
+		deps.Deps.HTTPClient = http.NewLimitedDefaultClient(urlValidator)
+	}
+	if secrets != nil {
+		deps.Deps.SecretService = secrets
+	}
 
 	// inject the dependencies to the context.
 	// one useful example is socket.from, kafka.to, and sql.from/sql.to where we need
@@ -48,10 +118,62 @@ func injectDependencies(ctx context.Context) (context.Context, flux.Dependencies
 
 func execute(cmd *cobra.Command, args []string) error {
 	fluxinit.FluxInit()
-	ctx, deps := injectDependencies(context.Background())
-	r := repl.New(ctx, deps)
+	if executeFlags.spillReserve > 0 && executeFlags.memoryLimit <= 0 {
+		return fmt.Errorf("--spill-reserve requires --memory-limit")
+	}
+	var secrets secret.Service
+	if executeFlags.secrets != "" {
+		var err error
+		if secrets, err = newSecretService(executeFlags.secrets); err != nil {
+			return err
+		}
+	}
+	ctx, deps := injectDependencies(context.Background(), secrets)
+	if executeFlags.format != "table" {
+		newEncoder, ok := encoders[executeFlags.format]
+		if !ok {
+			return fmt.Errorf("unknown output format %q", executeFlags.format)
+		}
+		if err := encodeQuery(ctx, args[0], newEncoder()); err != nil {
+			return fmt.Errorf("failed to execute query: %v", err)
+		}
+		return nil
+	}
+
+	opts := []repl.Option{
+		repl.WithImporter(newImporter()),
+		repl.WithAllocator(newAllocator),
+	}
+	if executeFlags.costBased {
+		opts = append(opts, repl.WithPhysicalPlanOptions(plan.WithCostBasedPlanning()))
+	}
+	r := repl.New(ctx, deps, opts...)
 	if err := r.Input(args[0]); err != nil {
 		return fmt.Errorf("failed to execute query: %v", err)
 	}
 	return nil
 }
+
+// encodeQuery compiles and runs the script and
+// writes all of its results to stdout with the encoder.
+func encodeQuery(ctx context.Context, script string, enc flux.MultiResultEncoder) error {
+	q, err := repl.LoadQuery(script)
+	if err != nil {
+		return err
+	}
+	program, err := lang.Compile(q, runtime.WithImporter(newImporter()), time.Now(), compileOptions(executeFlags.costBased)...)
+	if err != nil {
+		return err
+	}
+	query, err := program.Start(ctx, newAllocator())
+	if err != nil {
+		return err
+	}
+	results := flux.NewResultIteratorFromQuery(query)
+	defer results.Release()
+
+	if _, err := enc.Encode(os.Stdout, results); err != nil {
+		return err
+	}
+	return results.Err()
+}
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux/fluxinit"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/repl"
+	"github.com/influxdata/flux/runtime"
+	"github.com/spf13/cobra"
+)
+
+// explainCmd represents the explain command
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Print the query plans for a Flux script",
+	Long: `Print the query plans for a Flux script from string or file (use @ as prefix to the file).
+
+The script is evaluated and planned, but not executed. The initial logical plan,
+the plan after the logical rules were applied and the final physical plan are
+printed along with the rules that rewrote each plan and the rules that did not
+rewrite a node they matched. Each physical node shows its estimated cost, the
+number of rows it is estimated to produce and the resources it runs with.
+
+A source runs on a goroutine of its own, while every other node is run by the
+concurrency workers that the whole plan shares. The memory quota is enforced
+for the whole query and is not divided among the nodes, so the memory of a node
+is the estimate from its cost rather than a limit.`,
+	Args: cobra.ExactArgs(1),
+	RunE: explain,
+}
+
+var explainFlags struct {
+	format    string
+	costBased bool
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+	explainCmd.Flags().StringVar(&explainFlags.format, "format", "text", "output format of the plans: one of text, dot or json")
+	explainCmd.Flags().BoolVar(&explainFlags.costBased, "cost-based-planning", false, costBasedPlanningUsage)
+}
+
+// explainEncoders maps the name of each output format
+// to a function that writes an explanation in that format.
+var explainEncoders = map[string]func(w io.Writer, e *lang.Explanation) error{
+	"text": writeExplainText,
+	"dot":  writeExplainDOT,
+	"json": func(w io.Writer, e *lang.Explanation) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(e)
+	},
+}
+
+func explain(cmd *cobra.Command, args []string) error {
+	encode, ok := explainEncoders[explainFlags.format]
+	if !ok {
+		return fmt.Errorf("unknown output format %q", explainFlags.format)
+	}
+
+	fluxinit.FluxInit()
+	ctx, _ := injectDependencies(context.Background(), nil)
+
+	q, err := repl.LoadQuery(args[0])
+	if err != nil {
+		return err
+	}
+	program, err := lang.Compile(q, runtime.WithImporter(newImporter()), time.Now(), compileOptions(explainFlags.costBased)...)
+	if err != nil {
+		return err
+	}
+	e, err := program.Explain(ctx, &memory.Allocator{})
+	if err != nil {
+		return fmt.Errorf("failed to explain query: %v", err)
+	}
+	return encode(cmd.OutOrStdout(), e)
+}
+
+// explainStage is a single plan within an explanation
+// along with the rules that produced it.
+type explainStage struct {
+	name     string
+	title    string
+	plan     plan.Description
+	rules    []plan.RuleApplication
+	declines []plan.RuleDecline
+}
+
+func explainStages(e *lang.Explanation) []explainStage {
+	return []explainStage{
+		{name: "initial", title: "Initial logical plan", plan: e.Initial},
+		{name: "logical", title: "Logical plan", plan: e.Logical, rules: e.LogicalRules, declines: e.LogicalDeclines},
+		{name: "physical", title: "Physical plan", plan: e.Physical, rules: e.PhysicalRules, declines: e.PhysicalDeclines},
+	}
+}
+
+func writeExplainText(w io.Writer, e *lang.Explanation) error {
+	var b strings.Builder
+	for i, stage := range explainStages(e) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s:\n", stage.title)
+		writeResources(&b, "  ", stage.plan)
+		for _, node := range stage.plan.Nodes {
+			fmt.Fprintf(&b, "  %s (%s)", node.ID, node.Kind)
+			if len(node.Predecessors) > 0 {
+				preds := make([]string, len(node.Predecessors))
+				for i, pred := range node.Predecessors {
+					preds[i] = string(pred)
+				}
+				fmt.Fprintf(&b, " <- %s", strings.Join(preds, ", "))
+			}
+			b.WriteString("\n")
+			if node.Trigger != "" {
+				fmt.Fprintf(&b, "    trigger: %s\n", node.Trigger)
+			}
+			if node.Cost != nil {
+				fmt.Fprintf(&b, "    %s\n", formatEstimate(node))
+			}
+			if node.Resources != nil {
+				fmt.Fprintf(&b, "    %s\n", formatNodeResources(node.Resources))
+			}
+			if node.Details != "" {
+				for _, line := range strings.Split(node.Details, "\n") {
+					fmt.Fprintf(&b, "    // %s\n", line)
+				}
+			}
+		}
+		if i == 0 {
+			continue
+		}
+		b.WriteString("  rules applied:\n")
+		if len(stage.rules) == 0 {
+			b.WriteString("    none\n")
+		}
+		for _, r := range stage.rules {
+			fmt.Fprintf(&b, "    %s: %s -> %s\n", r.Rule, r.Node, r.Result)
+		}
+		if len(stage.declines) > 0 {
+			b.WriteString("  rules not applied:\n")
+		}
+		for _, d := range stage.declines {
+			fmt.Fprintf(&b, "    %s: %s: %s\n", d.Rule, d.Node, d.Reason)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatEstimate formats the estimated cost of
+// the node and the data that it produces.
+func formatEstimate(node plan.NodeDescription) string {
+	c := node.Cost
+	s := fmt.Sprintf("cost: cpu %d, memory %d, network %d, disk %d", c.CPU, c.MEM, c.NET, c.Disk)
+	if st := node.Statistics; st != nil {
+		s += fmt.Sprintf("; estimated %d rows in %d tables", st.Cardinality, st.GroupCardinality)
+	}
+	return s
+}
+
+// formatNodeResources formats the resources available to a single node.
+func formatNodeResources(r *plan.NodeResources) string {
+	if r.Dedicated {
+		return fmt.Sprintf("resources: dedicated goroutine, estimated memory %d", r.Memory)
+	}
+	return fmt.Sprintf("resources: %d shared workers, dispatcher throughput %d, estimated memory %d",
+		r.Concurrency, r.DispatcherThroughput, r.Memory)
+}
+
+func writeResources(b *strings.Builder, indent string, d plan.Description) {
+	fmt.Fprintf(b, "%sresources: concurrency quota %d, dispatcher throughput %d, memory quota %d bytes, priority %d\n",
+		indent, d.Resources.ConcurrencyQuota, d.Resources.DispatcherThroughput, d.Resources.MemoryBytesQuota, d.Resources.Priority)
+}
+
+// writeExplainDOT writes each plan as a cluster within a single graph.
+// Rules that rewrote a node from the previous plan into a node
+// of the next plan are drawn as dashed edges between the clusters.
+func writeExplainDOT(w io.Writer, e *lang.Explanation) error {
+	var b strings.Builder
+	b.WriteString("digraph {\n")
+	b.WriteString("  node [shape=box];\n")
+
+	stages := explainStages(e)
+	for _, stage := range stages {
+		fmt.Fprintf(&b, "  subgraph %s {\n", strconv.Quote("cluster_"+stage.name))
+		var label strings.Builder
+		label.WriteString(stage.title + "\n")
+		writeResources(&label, "", stage.plan)
+		fmt.Fprintf(&b, "    label=%s;\n", strconv.Quote(strings.TrimSpace(label.String())))
+		for _, node := range stage.plan.Nodes {
+			label := fmt.Sprintf("%s\n%s", node.ID, node.Kind)
+			if node.Trigger != "" {
+				label += "\ntrigger: " + node.Trigger
+			}
+			if node.Cost != nil {
+				label += "\n" + formatEstimate(node)
+			}
+			if node.Resources != nil {
+				label += "\n" + formatNodeResources(node.Resources)
+			}
+			fmt.Fprintf(&b, "    %s [label=%s];\n", dotNodeID(stage.name, node.ID), strconv.Quote(label))
+		}
+		for _, node := range stage.plan.Nodes {
+			for _, pred := range node.Predecessors {
+				fmt.Fprintf(&b, "    %s -> %s;\n", dotNodeID(stage.name, pred), dotNodeID(stage.name, node.ID))
+			}
+		}
+		b.WriteString("  }\n")
+	}
+
+	for i := 1; i < len(stages); i++ {
+		prev, stage := stages[i-1], stages[i]
+		for _, r := range stage.rules {
+			fmt.Fprintf(&b, "  // %s: %s -> %s\n", r.Rule, r.Node, r.Result)
+			if hasNode(prev.plan, r.Node) && hasNode(stage.plan, r.Result) {
+				fmt.Fprintf(&b, "  %s -> %s [style=dashed, label=%s];\n",
+					dotNodeID(prev.name, r.Node), dotNodeID(stage.name, r.Result), strconv.Quote(r.Rule))
+			}
+		}
+		for _, d := range stage.declines {
+			fmt.Fprintf(&b, "  // not applied %s: %s: %q\n", d.Rule, d.Node, d.Reason)
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotNodeID returns the quoted DOT id of a node within a stage
+// so that the same node may appear in every plan.
+func dotNodeID(stage string, id plan.NodeID) string {
+	return strconv.Quote(stage + "/" + string(id))
+}
+
+func hasNode(d plan.Description, id plan.NodeID) bool {
+	for _, node := range d.Nodes {
+		if node.ID == id {
+			return true
+		}
+	}
+	return false
+}
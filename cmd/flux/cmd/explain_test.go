@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/plan"
+)
+
+func testExplanation() *lang.Explanation {
+	resources := flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: 1024}
+	logical := plan.Description{
+		Resources: resources,
+		Nodes: []plan.NodeDescription{
+			{ID: "from0", Kind: "from"},
+			{ID: "filter1", Kind: "filter", Predecessors: []plan.NodeID{"from0"}, Details: "true"},
+		},
+	}
+	return &lang.Explanation{
+		Initial: logical,
+		Logical: logical,
+		Physical: plan.Description{
+			Resources: resources,
+			Nodes: []plan.NodeDescription{
+				{
+					ID:         "fromRemote2",
+					Kind:       "fromRemote",
+					Physical:   true,
+					Trigger:    "narrowTransformation",
+					Cost:       &plan.Cost{NET: 10},
+					Statistics: &plan.Statistics{Cardinality: 10, GroupCardinality: 2},
+					Resources:  &plan.NodeResources{Dedicated: true, Concurrency: 1},
+				},
+				{
+					ID:           "join3",
+					Kind:         "join",
+					Physical:     true,
+					Predecessors: []plan.NodeID{"fromRemote2"},
+					Cost:         &plan.Cost{CPU: 10, MEM: 10},
+					Statistics:   &plan.Statistics{Cardinality: 10, GroupCardinality: 2},
+					Resources:    &plan.NodeResources{Concurrency: 1, DispatcherThroughput: 10, Memory: 10},
+				},
+			},
+		},
+		PhysicalRules: []plan.RuleApplication{
+			{Rule: "FromRemoteRule", Node: "from0", Result: "fromRemote2"},
+			{Rule: "RemoveTrivialFilterRule", Node: "filter1", Result: "fromRemote2"},
+		},
+		PhysicalDeclines: []plan.RuleDecline{
+			{Rule: "MergeRemoteFilterRule", Node: "filter1", Reason: "fromRemote2 is not bounded by a range"},
+		},
+	}
+}
+
+func TestWriteExplainText(t *testing.T) {
+	var b bytes.Buffer
+	if err := writeExplainText(&b, testExplanation()); err != nil {
+		t.Fatal(err)
+	}
+	want := `Initial logical plan:
+  resources: concurrency quota 1, dispatcher throughput 0, memory quota 1024 bytes, priority 0
+  from0 (from)
+  filter1 (filter) <- from0
+    // true
+
+Logical plan:
+  resources: concurrency quota 1, dispatcher throughput 0, memory quota 1024 bytes, priority 0
+  from0 (from)
+  filter1 (filter) <- from0
+    // true
+  rules applied:
+    none
+
+Physical plan:
+  resources: concurrency quota 1, dispatcher throughput 0, memory quota 1024 bytes, priority 0
+  fromRemote2 (fromRemote)
+    trigger: narrowTransformation
+    cost: cpu 0, memory 0, network 10, disk 0; estimated 10 rows in 2 tables
+    resources: dedicated goroutine, estimated memory 0
+  join3 (join) <- fromRemote2
+    cost: cpu 10, memory 10, network 0, disk 0; estimated 10 rows in 2 tables
+    resources: 1 shared workers, dispatcher throughput 10, estimated memory 10
+  rules applied:
+    FromRemoteRule: from0 -> fromRemote2
+    RemoveTrivialFilterRule: filter1 -> fromRemote2
+  rules not applied:
+    MergeRemoteFilterRule: filter1: fromRemote2 is not bounded by a range
+`
+	if got := b.String(); want != got {
+		t.Errorf("unexpected output -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestWriteExplainDOT(t *testing.T) {
+	var b bytes.Buffer
+	if err := writeExplainDOT(&b, testExplanation()); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		`"physical/fromRemote2" [label="fromRemote2\nfromRemote\ntrigger: narrowTransformation\ncost: cpu 0, memory 0, network 10, disk 0; estimated 10 rows in 2 tables\nresources: dedicated goroutine, estimated memory 0"];`,
+		`"physical/join3" [label="join3\njoin\ncost: cpu 10, memory 10, network 0, disk 0; estimated 10 rows in 2 tables\nresources: 1 shared workers, dispatcher throughput 10, estimated memory 10"];`,
+		`"logical/from0" -> "physical/fromRemote2" [style=dashed, label="FromRemoteRule"];`,
+		`// not applied MergeRemoteFilterRule: filter1: "fromRemote2 is not bounded by a range"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the graph to contain %s, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestExplainCommand(t *testing.T) {
+	defer func() { explainFlags.format = "text" }()
+	explainFlags.format = "json"
+
+	var b bytes.Buffer
+	explainCmd.SetOutput(&b)
+	defer explainCmd.SetOutput(nil)
+
+	script := `import "array"
+
+array.from(rows: [{_value: 1}])
+	|> filter(fn: (r) => true)`
+	if err := explain(explainCmd, []string{script}); err != nil {
+		t.Fatal(err)
+	}
+
+	var e lang.Explanation
+	if err := json.Unmarshal(b.Bytes(), &e); err != nil {
+		t.Fatal(err)
+	}
+	var removed bool
+	for _, r := range e.PhysicalRules {
+		removed = removed || r.Rule == "RemoveTrivialFilterRule"
+	}
+	if !removed {
+		t.Errorf("expected the trivial filter to be removed, got rules %v", e.PhysicalRules)
+	}
+	for _, node := range e.Physical.Nodes {
+		if node.Kind == "filter" {
+			t.Errorf("unexpected filter node %s in the physical plan", node.ID)
+		}
+	}
+
+	explainFlags.format = "yaml"
+	if err := explain(explainCmd, []string{script}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
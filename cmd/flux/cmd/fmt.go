@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/influxdata/flux/libflux/go/libflux"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/spf13/cobra"
+)
+
+// fmtCmd represents the fmt command
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [path ...]",
+	Short: "Format Flux scripts",
+	Long: `Format Flux scripts.
+
+Without any paths, fmt formats the standard input. Directories are walked
+recursively and every .flux file within them is formatted. By default the
+formatted scripts are printed to the standard output. With --list or --diff,
+fmt exits with code 1 if any script is not formatted, unless --write is also set.
+It exits with code 2 if a script cannot be formatted.`,
+	RunE: format,
+}
+
+var fmtFlags struct {
+	write bool
+	list  bool
+	diff  bool
+}
+
+func init() {
+	rootCmd.AddCommand(fmtCmd)
+	fmtCmd.Flags().BoolVarP(&fmtFlags.write, "write", "w", false, "write the formatted script to the file instead of the standard output")
+	fmtCmd.Flags().BoolVarP(&fmtFlags.list, "list", "l", false, "list the files whose formatting differs from the formatted script")
+	fmtCmd.Flags().BoolVarP(&fmtFlags.diff, "diff", "d", false, "print a unified diff of the changes made by formatting")
+}
+
+func format(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+	if len(args) == 0 {
+		if fmtFlags.write {
+			return fmtError(fmt.Errorf("cannot use --write with the standard input"))
+		}
+		src, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return fmtError(err)
+		}
+		changed, err := formatFile("<standard input>", src, out)
+		if err != nil {
+			return fmtError(err)
+		}
+		if changed && isCheck() {
+			return fmt.Errorf("the standard input is not formatted")
+		}
+		return nil
+	}
+
+	files, err := findFluxFiles(args)
+	if err != nil {
+		return fmtError(err)
+	}
+	var unformatted, failed int
+	for _, path := range files {
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmtError(err)
+		}
+		changed, err := formatFile(path, src, out)
+		if err != nil {
+			fmt.Fprintln(cmd.OutOrStderr(), err)
+			failed++
+			continue
+		}
+		if changed {
+			unformatted++
+		}
+	}
+	if failed > 0 {
+		return fmtError(fmt.Errorf("%d of %d files could not be formatted", failed, len(files)))
+	}
+	if unformatted > 0 && isCheck() {
+		return fmt.Errorf("%d of %d files are not formatted", unformatted, len(files))
+	}
+	return nil
+}
+
+// fmtError wraps an error that keeps a script from being formatted.
+func fmtError(err error) error {
+	return &exitError{code: 2, err: err}
+}
+
+// isCheck reports whether fmt only checks the formatting of the files.
+func isCheck() bool {
+	return (fmtFlags.list || fmtFlags.diff) && !fmtFlags.write
+}
+
+// formatFile formats the source of the file at path and handles the
+// result according to the flags. It reports whether formatting changed the source.
+func formatFile(path string, src []byte, out io.Writer) (bool, error) {
+	res, err := formatSource(path, src)
+	if err != nil {
+		return false, fmt.Errorf("%s: %v", path, err)
+	}
+	changed := !bytes.Equal(src, res)
+
+	if changed {
+		if fmtFlags.list {
+			fmt.Fprintln(out, path)
+		}
+		if fmtFlags.write {
+			if err := replaceFile(path, res); err != nil {
+				return false, err
+			}
+		}
+		if fmtFlags.diff {
+			fmt.Fprint(out, unifiedDiff(path, src, res))
+		}
+	}
+	if !fmtFlags.list && !fmtFlags.write && !fmtFlags.diff {
+		if _, err := out.Write(res); err != nil {
+			return false, err
+		}
+	}
+	return changed, nil
+}
+
+// replaceFile replaces the content of the file at path with data.
+// The data is written to a temporary file in the same directory that
+// is renamed over the file, so the file is never left half written.
+// The file keeps its permissions.
+func replaceFile(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// The temporary file is gone once it is renamed.
+		_ = os.Remove(tmp.Name())
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// formatSource formats the Flux source with the libflux formatter,
+// which keeps the comments within the source. The formatted
+// source ends with a newline.
+func formatSource(name string, src []byte) ([]byte, error) {
+	pkg := libflux.Parse(filepath.Base(name), string(src))
+	defer pkg.Free()
+	if err := pkg.GetError(); err != nil {
+		return nil, err
+	}
+	res, err := pkg.Format()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(res, "\n") {
+		res += "\n"
+	}
+	return []byte(res), nil
+}
+
+// findFluxFiles expands the paths into the list of .flux files they reference.
+// Directories are walked recursively.
+func findFluxFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		if err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && filepath.Ext(path) == ".flux" {
+				files = append(files, path)
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// diffContext is the number of unchanged lines
+// shown around each change in a unified diff.
+const diffContext = 3
+
+// diffLine is a line of a diff with its operation.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// unifiedDiff returns the changes between the original and the formatted
+// source of the file at path in the unified diff format.
+func unifiedDiff(path string, original, formatted []byte) string {
+	dmp := diffmatchpatch.New()
+	a, b, lines := dmp.DiffLinesToRunes(string(original), string(formatted))
+	diffs := dmp.DiffCharsToLines(dmp.DiffMainRunes(a, b, false), lines)
+
+	var all []diffLine
+	for _, d := range diffs {
+		op := byte(' ')
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		}
+		for _, text := range strings.SplitAfter(d.Text, "\n") {
+			if text != "" {
+				all = append(all, diffLine{op: op, text: text})
+			}
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s.orig\n+++ %s\n", path, path)
+	// oldLine and newLine are the numbers of the
+	// lines before the line at index i in each file.
+	oldLine, newLine := 0, 0
+	for i := 0; i < len(all); {
+		if all[i].op == ' ' {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+
+		// Extend the hunk until the unchanged lines that follow
+		// a change are too many to join the next change.
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(all) && j-end <= 2*diffContext; j++ {
+			if all[j].op != ' ' {
+				end = j + 1
+			}
+		}
+		stop := end + diffContext
+		if stop > len(all) {
+			stop = len(all)
+		}
+
+		oldStart, newStart := oldLine-(i-start), newLine-(i-start)
+		var oldCount, newCount int
+		for _, l := range all[start:stop] {
+			if l.op != '+' {
+				oldCount++
+			}
+			if l.op != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+		for _, l := range all[start:stop] {
+			sb.WriteByte(l.op)
+			sb.WriteString(l.text)
+			if !strings.HasSuffix(l.text, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		for _, l := range all[i:stop] {
+			if l.op != '+' {
+				oldLine++
+			}
+			if l.op != '-' {
+				newLine++
+			}
+		}
+		i = stop
+	}
+	return sb.String()
+}
+
+// hunkRange formats the range of lines of a hunk that
+// starts after the given line and spans count lines.
+func hunkRange(after, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", after)
+	}
+	return fmt.Sprintf("%d,%d", after+1, count)
+}
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	lines := func(ls ...string) string {
+		return strings.Join(ls, "\n") + "\n"
+	}
+	for _, tc := range []struct {
+		name      string
+		original  string
+		formatted string
+		want      string
+	}{
+		{
+			name:      "single change",
+			original:  lines("a", "b", "c", "d", "e", "f", "g", "h"),
+			formatted: lines("a", "b", "c", "d", "E", "f", "g", "h"),
+			want: `--- x.flux.orig
++++ x.flux
+@@ -2,7 +2,7 @@
+ b
+ c
+ d
+-e
++E
+ f
+ g
+ h
+`,
+		},
+		{
+			name:      "distant changes",
+			original:  lines("l1", "l2", "l3", "l4", "l5", "l6", "l7", "l8", "l9", "l10", "l11", "l12"),
+			formatted: lines("L1", "l2", "l3", "l4", "l5", "l6", "l7", "l8", "l9", "l10", "l11", "L12"),
+			want: `--- x.flux.orig
++++ x.flux
+@@ -1,4 +1,4 @@
+-l1
++L1
+ l2
+ l3
+ l4
+@@ -9,4 +9,4 @@
+ l9
+ l10
+ l11
+-l12
++L12
+`,
+		},
+		{
+			name:      "missing newline",
+			original:  "x=1",
+			formatted: "x = 1\n",
+			want: `--- x.flux.orig
++++ x.flux
+@@ -1,1 +1,1 @@
+-x=1
+\ No newline at end of file
++x = 1
+`,
+		},
+		{
+			name:      "empty original",
+			original:  "",
+			formatted: "x = 1\n",
+			want: `--- x.flux.orig
++++ x.flux
+@@ -0,0 +1,1 @@
++x = 1
+`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := unifiedDiff("x.flux", []byte(tc.original), []byte(tc.formatted))
+			if tc.want != got {
+				t.Errorf("unexpected diff -want/+got:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestIsCheck(t *testing.T) {
+	defer func() { fmtFlags.list, fmtFlags.diff, fmtFlags.write = false, false, false }()
+	for _, tc := range []struct {
+		list, diff, write bool
+		want              bool
+	}{
+		{want: false},
+		{list: true, want: true},
+		{diff: true, want: true},
+		{list: true, diff: true, want: true},
+		{write: true, want: false},
+		{list: true, write: true, want: false},
+		{diff: true, write: true, want: false},
+	} {
+		fmtFlags.list, fmtFlags.diff, fmtFlags.write = tc.list, tc.diff, tc.write
+		if got := isCheck(); tc.want != got {
+			t.Errorf("unexpected check mode for list %v, diff %v, write %v: want %v, got %v",
+				tc.list, tc.diff, tc.write, tc.want, got)
+		}
+	}
+}
+
+func TestFindFluxFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.flux":     "",
+		"b.txt":      "",
+		"sub/c.flux": "",
+	})
+	defer os.RemoveAll(dir)
+
+	got, err := findFluxFiles([]string{dir, filepath.Join(dir, "b.txt")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(dir, "a.flux"),
+		filepath.Join(dir, "sub/c.flux"),
+		filepath.Join(dir, "b.txt"),
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected files -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	if _, err := findFluxFiles([]string{filepath.Join(dir, "missing.flux")}); err == nil {
+		t.Error("expected an error for a missing path")
+	}
+}
+
+func TestReplaceFile(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"a.flux": "x=1\n"})
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.flux")
+	if err := os.Chmod(path, 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := replaceFile(path, []byte("x = 1\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := ioutil.ReadFile(path); err != nil {
+		t.Fatal(err)
+	} else if want := "x = 1\n"; want != string(got) {
+		t.Errorf("unexpected content -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if mode := info.Mode().Perm(); mode != 0640 {
+		t.Errorf("unexpected mode %v", mode)
+	}
+	if infos, err := ioutil.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(infos) != 1 {
+		t.Errorf("expected the temporary file to be removed, found %d files", len(infos))
+	}
+
+	if err := replaceFile(filepath.Join(dir, "missing.flux"), nil); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestFmtCommand(t *testing.T) {
+	const (
+		formatted   = "x = 1\n"
+		unformatted = "x=1\n"
+	)
+	for _, tc := range []struct {
+		name              string
+		list, diff, write bool
+		files             map[string]string
+		code              int
+		want              func(dir string) string
+		wantFiles         map[string]string
+	}{
+		{
+			name:  "print",
+			files: map[string]string{"a.flux": unformatted},
+			want:  func(string) string { return formatted },
+		},
+		{
+			name:  "list",
+			list:  true,
+			files: map[string]string{"a.flux": formatted, "b.flux": unformatted},
+			code:  1,
+			want: func(dir string) string {
+				return filepath.Join(dir, "b.flux") + "\n"
+			},
+			wantFiles: map[string]string{"b.flux": unformatted},
+		},
+		{
+			name:  "list formatted",
+			list:  true,
+			files: map[string]string{"a.flux": formatted},
+			want:  func(string) string { return "" },
+		},
+		{
+			name:  "diff",
+			diff:  true,
+			files: map[string]string{"a.flux": unformatted},
+			code:  1,
+			want: func(dir string) string {
+				path := filepath.Join(dir, "a.flux")
+				return "--- " + path + ".orig\n+++ " + path + "\n@@ -1,1 +1,1 @@\n-x=1\n+x = 1\n"
+			},
+		},
+		{
+			name:      "write",
+			write:     true,
+			files:     map[string]string{"a.flux": formatted, "b.flux": unformatted},
+			want:      func(string) string { return "" },
+			wantFiles: map[string]string{"a.flux": formatted, "b.flux": formatted},
+		},
+		{
+			name:  "list and write",
+			list:  true,
+			write: true,
+			files: map[string]string{"a.flux": unformatted},
+			want: func(dir string) string {
+				return filepath.Join(dir, "a.flux") + "\n"
+			},
+			wantFiles: map[string]string{"a.flux": formatted},
+		},
+		{
+			name:  "parse error",
+			list:  true,
+			files: map[string]string{"a.flux": "x = (\n"},
+			code:  2,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeFiles(t, tc.files)
+			defer os.RemoveAll(dir)
+
+			fmtFlags.list, fmtFlags.diff, fmtFlags.write = tc.list, tc.diff, tc.write
+			defer func() { fmtFlags.list, fmtFlags.diff, fmtFlags.write = false, false, false }()
+			var b bytes.Buffer
+			fmtCmd.SetOutput(&b)
+			defer fmtCmd.SetOutput(nil)
+
+			err := format(fmtCmd, []string{dir})
+			if got := exitCode(err); tc.code != got {
+				t.Fatalf("unexpected exit code: want %d, got %d (%v)", tc.code, got, err)
+			}
+			if tc.want != nil {
+				if want, got := tc.want(dir), b.String(); want != got {
+					t.Errorf("unexpected output -want/+got:\n%s", cmp.Diff(want, got))
+				}
+			}
+			for name, want := range tc.wantFiles {
+				got, err := ioutil.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if want != string(got) {
+					t.Errorf("unexpected content of %s -want/+got:\n\t- %q\n\t+ %q", name, want, got)
+				}
+			}
+		})
+	}
+}
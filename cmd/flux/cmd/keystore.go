@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/influxdata/flux/dependencies/secret"
+	"github.com/spf13/cobra"
+)
+
+// keystoreCmd represents the keystore command
+var keystoreCmd = &cobra.Command{
+	Use:   "keystore [file]",
+	Short: "Create an encrypted keystore of secrets",
+	Long: `Create an encrypted keystore of secrets that can be used with --secrets keystore:<file>.
+
+The secrets are read from the standard input as KEY=VALUE lines. Blank lines and
+lines that start with # are ignored. The keystore is encrypted with the passphrase
+read from ` + keystorePassphraseEnv + ` and is only readable by its owner.
+An existing file is not overwritten unless --force is set.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		passphrase, ok := os.LookupEnv(keystorePassphraseEnv)
+		if !ok || passphrase == "" {
+			return fmt.Errorf("the passphrase of the keystore is required in %s", keystorePassphraseEnv)
+		}
+		return createKeystore(args[0], os.Stdin, passphrase, keystoreFlags.force)
+	},
+}
+
+var keystoreFlags struct {
+	force bool
+}
+
+func init() {
+	rootCmd.AddCommand(keystoreCmd)
+	keystoreCmd.Flags().BoolVarP(&keystoreFlags.force, "force", "f", false, "overwrite the file if it exists")
+}
+
+// createKeystore encrypts the secrets read from r with the passphrase
+// and writes the keystore to path.
+func createKeystore(path string, r io.Reader, passphrase string, force bool) error {
+	secrets, err := readSecrets(r)
+	if err != nil {
+		return err
+	}
+	data, err := secret.EncryptKeystore(secrets, passphrase)
+	if err != nil {
+		return err
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !force {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(path, flags, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("keystore %s already exists, use --force to overwrite it", path)
+		}
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readSecrets reads the KEY=VALUE lines of r.
+// The value is everything after the first = of the line.
+func readSecrets(r io.Reader) (map[string]string, error) {
+	secrets := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", n)
+		}
+		key := strings.TrimSpace(line[:i])
+		if _, ok := secrets[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicate secret %q", n, key)
+		}
+		secrets[key] = line[i+1:]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(secrets) == 0 {
+		return nil, fmt.Errorf("no secrets to store")
+	}
+	return secrets, nil
+}
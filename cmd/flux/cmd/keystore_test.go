@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux/dependencies/secret"
+)
+
+func TestCreateKeystore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "secrets.json")
+
+	input := `# influxdb
+token=s3cr3t
+password = a=b
+
+`
+	if err := createKeystore(path, strings.NewReader(input), "passphrase", false); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Fatal(err)
+	} else if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("unexpected keystore mode %v", mode)
+	}
+
+	ks, err := secret.OpenKeystore(path, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"token": "s3cr3t", "password": " a=b"} {
+		got, err := ks.LoadSecret(context.Background(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want != got {
+			t.Errorf("unexpected secret %s -want/+got:\n\t- %q\n\t+ %q", key, want, got)
+		}
+	}
+
+	if err := createKeystore(path, strings.NewReader("token=other"), "passphrase", false); err == nil {
+		t.Error("expected an error when the keystore exists")
+	}
+	if err := createKeystore(path, strings.NewReader("token=other"), "passphrase", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := secret.OpenKeystore(path, "wrong"); err == nil {
+		t.Error("expected an error for the wrong passphrase")
+	}
+}
+
+func TestReadSecrets_Errors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"# only a comment",
+		"token",
+		"=value",
+		"token=a\ntoken=b",
+	} {
+		if _, err := readSecrets(strings.NewReader(input)); err == nil {
+			t.Errorf("expected an error for %q", input)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/influxdata/flux/fluxinit"
+	"github.com/influxdata/flux/lsp"
+	"github.com/spf13/cobra"
+)
+
+// lspCmd represents the lsp command
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run a Flux language server",
+	Long:  "Run a Flux language server that speaks the Language Server Protocol over stdin and stdout",
+	Args:  cobra.NoArgs,
+	RunE:  runLSP,
+}
+
+var lspFlags struct {
+	stdlibDir string
+}
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+	lspCmd.Flags().StringVar(&lspFlags.stdlibDir, "stdlib-dir", "", "directory that holds the source of the standard library, used to find the definitions of its members instead of the source embedded in the binary")
+}
+
+func runLSP(cmd *cobra.Command, args []string) error {
+	fluxinit.FluxInit()
+	s := lsp.NewServer()
+	s.StdlibDir = lspFlags.stdlibDir
+	s.FluxPath = fluxPath()
+	return s.Serve(os.Stdin, os.Stdout)
+}
@@ -17,8 +17,8 @@ var replCmd = &cobra.Command{
 	Long:  "Launch a Flux REPL (Read-Eval-Print-Loop)",
 	Run: func(cmd *cobra.Command, args []string) {
 		fluxinit.FluxInit()
-		ctx, deps := injectDependencies(context.Background())
-		r := repl.New(ctx, deps)
+		ctx, deps := injectDependencies(context.Background(), nil)
+		r := repl.New(ctx, deps, repl.WithImporter(newImporter()))
 		r.Run()
 	},
 }
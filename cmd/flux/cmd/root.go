@@ -1,17 +1,104 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 
+	"github.com/influxdata/flux/dependencies/filesystem"
+	"github.com/influxdata/flux/dependencies/url"
+	"github.com/influxdata/flux/runtime"
 	"github.com/spf13/cobra"
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "flux",
-	Short: "A Flux CLI",
-	Long:  `More to come later.`,
+	Use:               "flux",
+	Short:             "A Flux CLI",
+	Long:              `More to come later.`,
+	PersistentPreRunE: setup,
+}
+
+var rootFlags struct {
+	fsRoot    string
+	fluxPath  string
+	urlPolicy string
+}
+
+// fileSystem is the filesystem service used by queries.
+var fileSystem = filesystem.SystemFS
+
+// urlValidator validates the URLs used by queries.
+// The default validator of the dependencies is used when it is nil.
+var urlValidator url.Validator
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&rootFlags.fsRoot, "fs-root", "", "directory that queries are restricted to when they read and write files, all paths are relative to it")
+	rootCmd.PersistentFlags().StringVar(&rootFlags.fluxPath, "fluxpath", "", "list of directories searched for imported packages that are not part of the standard library, defaults to $"+runtime.FluxPathEnv)
+	rootCmd.PersistentFlags().StringVar(&rootFlags.urlPolicy, "url-policy", "", "JSON file with the allow and deny lists of the URLs that queries may connect to, for all functions and by function name")
+}
+
+func setup(cmd *cobra.Command, args []string) error {
+	if err := setupFilesystem(cmd, args); err != nil {
+		return err
+	}
+	return setupURLPolicy(cmd, args)
+}
+
+func setupFilesystem(cmd *cobra.Command, args []string) error {
+	if rootFlags.fsRoot == "" {
+		return nil
+	}
+	fs, err := filesystem.NewRootFS(rootFlags.fsRoot)
+	if err != nil {
+		return fmt.Errorf("invalid filesystem root: %v", err)
+	}
+	fileSystem = fs
+	return nil
+}
+
+func setupURLPolicy(cmd *cobra.Command, args []string) error {
+	if rootFlags.urlPolicy == "" {
+		return nil
+	}
+	v, err := loadURLPolicy(rootFlags.urlPolicy)
+	if err != nil {
+		return fmt.Errorf("invalid url policy: %v", err)
+	}
+	urlValidator = v
+	return nil
+}
+
+// loadURLPolicy creates the validator for the url.Policy in the JSON file.
+func loadURLPolicy(path string) (*url.PolicyValidator, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var policy url.Policy
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&policy); err != nil {
+		return nil, err
+	}
+	return url.NewPolicyValidator(policy)
+}
+
+// fluxPath returns the directories of the --fluxpath flag,
+// or of the FLUXPATH environment variable if it is not set.
+func fluxPath() []string {
+	if rootFlags.fluxPath != "" {
+		return runtime.SplitFluxPath(rootFlags.fluxPath)
+	}
+	return runtime.FluxPath()
+}
+
+// newImporter returns an importer for the packages in the directories of the Flux path.
+// Flux must be initialized before calling it.
+func newImporter() *runtime.ModuleImporter {
+	return runtime.NewModuleImporter(fluxPath())
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -19,6 +106,27 @@ var rootCmd = &cobra.Command{
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitError is an error that makes the command exit with a specific code.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+// exitCode returns the code that the command exits with for the error.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if e, ok := err.(*exitError); ok {
+		return e.code
 	}
+	return 1
 }
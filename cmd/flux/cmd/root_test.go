@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fluxurl "github.com/influxdata/flux/dependencies/url"
+)
+
+func TestLoadURLPolicy(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"policy.json": `{
+	"schemes": ["http", "https"],
+	"denyHosts": ["internal.example.com"],
+	"functions": {
+		"http.post": {"allowHosts": ["hooks.example.com"]}
+	}
+}`,
+		"unknown.json": `{"denyHost": ["internal.example.com"]}`,
+		"invalid.json": `{"allowNetworks": ["10.0.0.0/33"]}`,
+	})
+	defer os.RemoveAll(dir)
+
+	v, err := loadURLPolicy(filepath.Join(dir, "policy.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		function string
+		url      string
+		denied   bool
+	}{
+		{url: "https://example.com"},
+		{url: "ftp://example.com", denied: true},
+		{url: "https://internal.example.com", denied: true},
+		{function: "http.post", url: "https://hooks.example.com"},
+		{function: "http.post", url: "https://example.com", denied: true},
+	} {
+		u, err := url.Parse(tc.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = fluxurl.ForFunction(v, tc.function).Validate(u)
+		if denied := err != nil; tc.denied != denied {
+			t.Errorf("unexpected result for %s %s: want denied %v, got %v", tc.function, tc.url, tc.denied, err)
+		}
+	}
+
+	for _, name := range []string{"unknown.json", "invalid.json", "missing.json"} {
+		if _, err := loadURLPolicy(filepath.Join(dir, name)); err == nil {
+			t.Errorf("expected an error for %s", name)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/influxdata/flux/dependencies/secret"
+	"go.uber.org/zap"
+)
+
+// keystorePassphraseEnv is the environment variable
+// that holds the passphrase of keystore secret backends.
+const keystorePassphraseEnv = "FLUX_KEYSTORE_PASSPHRASE"
+
+const secretsUsage = `comma separated list of secret backends that are tried in order: ` +
+	`env, dir:<path> or keystore:<path>. The passphrase of a keystore is read from ` + keystorePassphraseEnv +
+	` and a keystore is created with the keystore command`
+
+// newSecretService creates the secret service described by the --secrets flag.
+// Every secret that is loaded through the service is logged to stderr.
+func newSecretService(spec string) (secret.Service, error) {
+	var chain secret.ChainedSecretService
+	for _, backend := range strings.Split(spec, ",") {
+		backend = strings.TrimSpace(backend)
+		kind, arg := backend, ""
+		if i := strings.Index(backend, ":"); i >= 0 {
+			kind, arg = backend[:i], backend[i+1:]
+		}
+		switch kind {
+		case "env":
+			chain = append(chain, secret.EnvironmentSecretService{ReportMissing: true})
+		case "dir":
+			if arg == "" {
+				return nil, fmt.Errorf("secret backend %q requires a directory", backend)
+			}
+			chain = append(chain, secret.DirectorySecretService{Dir: arg})
+		case "keystore":
+			if arg == "" {
+				return nil, fmt.Errorf("secret backend %q requires a file", backend)
+			}
+			passphrase, ok := os.LookupEnv(keystorePassphraseEnv)
+			if !ok {
+				return nil, fmt.Errorf("secret backend %q requires the passphrase in %s", backend, keystorePassphraseEnv)
+			}
+			ks, err := secret.OpenKeystore(arg, passphrase)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, ks)
+		default:
+			return nil, fmt.Errorf("unknown secret backend %q", backend)
+		}
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return nil, err
+	}
+	return secret.AuditSecretService{
+		Service: chain,
+		Logger:  logger.With(zap.String("component", "secrets")),
+	}, nil
+}
@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/execute/table"
+	"github.com/influxdata/flux/fluxinit"
+	"github.com/influxdata/flux/internal/token"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/stdlib"
+	"github.com/spf13/cobra"
+)
+
+// testCmd represents the test command
+var testCmd = &cobra.Command{
+	Use:   "test [paths...]",
+	Short: "Run Flux end-to-end tests",
+	Long: `Run the testcase blocks found in _test.flux files.
+
+Each path may be a _test.flux file or a directory. A directory path ending
+in "/..." is searched recursively. When no path is given, the current
+directory is used. A file without testcase blocks is run as a single test
+that fails if the script fails, such as when a testing assertion fails.
+
+The command exits with code 1 if a test fails and with code 2 if the
+tests cannot be run.`,
+	RunE:         test,
+	SilenceUsage: true,
+}
+
+var testFlags struct {
+	run     string
+	verbose bool
+}
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+	testCmd.Flags().StringVar(&testFlags.run, "run", "", "run only the test cases whose name matches the regular expression")
+	testCmd.Flags().BoolVarP(&testFlags.verbose, "verbose", "v", false, "print the name of every test case as it runs")
+}
+
+// testCase is a single testcase statement within a Flux test file.
+type testCase struct {
+	// path is the location of the file on disk.
+	path string
+	// name is the identifier of the testcase statement.
+	name string
+	// pkg is a package that contains the source file
+	// with the testcase and a call to testing.inspect for it.
+	pkg *ast.Package
+}
+
+func test(cmd *cobra.Command, args []string) error {
+	fluxinit.FluxInit()
+
+	var filter *regexp.Regexp
+	if testFlags.run != "" {
+		re, err := regexp.Compile(testFlags.run)
+		if err != nil {
+			return testError(fmt.Errorf("invalid --run pattern: %v", err))
+		}
+		filter = re
+	}
+
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+	files, err := findTestFiles(args)
+	if err != nil {
+		return testError(err)
+	}
+
+	ctx, _ := injectDependencies(context.Background(), nil)
+
+	var passed, failed int
+	for _, path := range files {
+		cases, err := loadTestCases(path)
+		if err != nil {
+			return testError(err)
+		}
+		for _, tc := range cases {
+			if filter != nil && !filter.MatchString(tc.name) {
+				continue
+			}
+			if runTestCase(ctx, tc) {
+				passed++
+			} else {
+				failed++
+			}
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("FAIL\n%d passed, %d failed\n", passed, failed)
+		return fmt.Errorf("%d of %d tests failed", failed, passed+failed)
+	}
+	fmt.Printf("PASS\n%d passed\n", passed)
+	return nil
+}
+
+// testError wraps an error that keeps the tests from running.
+func testError(err error) error {
+	return &exitError{code: 2, err: err}
+}
+
+// findTestFiles expands the paths into the list of _test.flux files they reference.
+// Directories ending in "/..." are walked recursively.
+func findTestFiles(paths []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "...") {
+			root := filepath.Clean(strings.TrimSuffix(path, "..."))
+			if err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				if !info.IsDir() && isTestFile(path) {
+					add(path)
+				}
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			if !isTestFile(path) {
+				return nil, fmt.Errorf("flux test files must use the _test.flux suffix in their file name, found %q", path)
+			}
+			add(path)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "*_test.flux"))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			add(m)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func isTestFile(path string) bool {
+	return strings.HasSuffix(path, "_test.flux")
+}
+
+// loadTestCases parses the file at path and constructs
+// a package for each of the testcase statements it contains.
+// A file without testcase statements is a single test case.
+func loadTestCases(path string) ([]testCase, error) {
+	fset := new(token.FileSet)
+	file, err := parser.ParseFile(fset, path)
+	if err != nil {
+		return nil, err
+	}
+	if ast.Check(file) > 0 {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, ast.GetError(file))
+	}
+	file.Package = &ast.PackageClause{Name: &ast.Identifier{Name: "main"}}
+
+	var cases []testCase
+	for i, stmt := range file.Body {
+		ts, ok := stmt.(*ast.TestStatement)
+		if !ok {
+			continue
+		}
+
+		// Remove every other testcase from the file so the
+		// generated calls only reference this one.
+		f := file.Copy().(*ast.File)
+		body := make([]ast.Statement, 0, len(f.Body))
+		for j, s := range f.Body {
+			if _, ok := s.(*ast.TestStatement); ok && j != i {
+				continue
+			}
+			body = append(body, s)
+		}
+		f.Body = body
+
+		pkg := &ast.Package{
+			Package: "main",
+			Files:   []*ast.File{f},
+		}
+		pkg.Files = append(pkg.Files, stdlib.TestingInspectCalls(pkg))
+		cases = append(cases, testCase{
+			path: path,
+			name: ts.Assignment.ID.Name,
+			pkg:  pkg,
+		})
+	}
+
+	if len(cases) == 0 {
+		// A file without test statements is a script that checks its
+		// results with the testing assertions, such as testing.assertEquals.
+		// It is run as a single test case that fails if the script fails.
+		cases = append(cases, testCase{
+			path: path,
+			name: strings.TrimSuffix(filepath.Base(path), "_test.flux"),
+			pkg: &ast.Package{
+				Package: "main",
+				Files:   []*ast.File{file},
+			},
+		})
+	}
+	return cases, nil
+}
+
+// runTestCase executes the test case and reports whether it passed.
+// Failures are printed along with a diff of the wanted and actual tables.
+func runTestCase(ctx context.Context, tc testCase) bool {
+	name := fmt.Sprintf("%s:%s", tc.path, tc.name)
+	if testFlags.verbose {
+		fmt.Printf("=== RUN   %s\n", name)
+	}
+
+	start := time.Now()
+	results, err := executeTestCase(ctx, tc)
+	elapsed := time.Since(start).Seconds()
+	if err != nil {
+		fmt.Printf("--- FAIL: %s (%.2fs)\n    %v\n", name, elapsed, err)
+		return false
+	}
+
+	if diff, ok := results["diff"].(table.Iterator); ok && len(diff) > 0 {
+		fmt.Printf("--- FAIL: %s (%.2fs)\n", name, elapsed)
+		d := table.Diff(results["want"], results["got"])
+		for _, line := range strings.Split(strings.TrimRight(d, "\n"), "\n") {
+			fmt.Printf("    %s\n", line)
+		}
+		return false
+	}
+
+	if testFlags.verbose {
+		fmt.Printf("--- PASS: %s (%.2fs)\n", name, elapsed)
+	}
+	return true
+}
+
+// executeTestCase runs the testing.inspect call for the test case
+// with the dependencies in the context and returns a copy of the
+// tables for each named result. The test case is compiled with the
+// importer for the Flux path so it can import the local packages
+// that it tests.
+func executeTestCase(ctx context.Context, tc testCase) (map[string]flux.TableIterator, error) {
+	rt := runtime.WithImporter(newImporter())
+	bs, err := json.Marshal(tc.pkg)
+	if err != nil {
+		return nil, err
+	}
+	hdl, err := rt.JSONToHandle(bs)
+	if err != nil {
+		return nil, err
+	}
+	program := lang.CompileAST(hdl, rt, time.Now())
+
+	alloc := &memory.Allocator{}
+	q, err := program.Start(ctx, alloc)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]flux.TableIterator)
+	for res := range q.Results() {
+		// Sort buffers the tables so they can be read
+		// after the query has finished.
+		tables, err := table.Sort(res.Tables())
+		if err != nil {
+			q.Done()
+			return nil, err
+		}
+		results[res.Name()] = tables
+	}
+	q.Done()
+	if err := q.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
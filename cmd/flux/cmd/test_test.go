@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const passingTestCase = `package main
+
+import "testing"
+import "array"
+
+test _pass = () => ({
+	input: array.from(rows: [{_value: 1}]),
+	want: array.from(rows: [{_value: 2}]),
+	fn: (tables=<-) => tables |> map(fn: (r) => ({r with _value: r._value + 1})),
+})
+`
+
+const failingTestCase = `package main
+
+import "testing"
+import "array"
+
+test _fail = () => ({
+	input: array.from(rows: [{_value: 1}]),
+	want: array.from(rows: [{_value: 3}]),
+	fn: (tables=<-) => tables |> map(fn: (r) => ({r with _value: r._value + 1})),
+})
+`
+
+const passingAssertion = `import "testing"
+import "array"
+
+array.from(rows: [{_value: 1}])
+	|> testing.assertEquals(name: "assert", want: array.from(rows: [{_value: 1}]))
+`
+
+const failingAssertion = `import "testing"
+import "array"
+
+array.from(rows: [{_value: 1}])
+	|> testing.assertEquals(name: "assert", want: array.from(rows: [{_value: 2}]))
+`
+
+const localPackage = `package inc
+
+inc = (tables=<-) => tables |> map(fn: (r) => ({r with _value: r._value + 1}))
+`
+
+const localPackageTestCase = `package main
+
+import "testing"
+import "array"
+import "acme/inc"
+
+test _inc = () => ({
+	input: array.from(rows: [{_value: 1}]),
+	want: array.from(rows: [{_value: 2}]),
+	fn: (tables=<-) => tables |> inc.inc(),
+})
+`
+
+// writeFiles writes the files to a temporary directory and returns it.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "flux-cmd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestFindTestFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a_test.flux":     "",
+		"b.flux":          "",
+		"sub/c_test.flux": "",
+	})
+	defer os.RemoveAll(dir)
+
+	for _, tc := range []struct {
+		paths []string
+		want  []string
+	}{
+		{
+			paths: []string{dir},
+			want:  []string{filepath.Join(dir, "a_test.flux")},
+		},
+		{
+			paths: []string{dir + "/..."},
+			want:  []string{filepath.Join(dir, "a_test.flux"), filepath.Join(dir, "sub/c_test.flux")},
+		},
+		{
+			paths: []string{filepath.Join(dir, "a_test.flux"), dir},
+			want:  []string{filepath.Join(dir, "a_test.flux")},
+		},
+	} {
+		got, err := findTestFiles(tc.paths)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(tc.want, got) {
+			t.Errorf("unexpected files for %v -want/+got:\n%s", tc.paths, cmp.Diff(tc.want, got))
+		}
+	}
+
+	if _, err := findTestFiles([]string{filepath.Join(dir, "b.flux")}); err == nil {
+		t.Error("expected an error for a file without the _test.flux suffix")
+	}
+}
+
+func TestTestCommand(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		files map[string]string
+		run   string
+		// fluxPath is the directory within the test
+		// directory that is used as the Flux path.
+		fluxPath string
+		code     int
+	}{
+		{
+			name:  "pass",
+			files: map[string]string{"pass_test.flux": passingTestCase},
+			code:  0,
+		},
+		{
+			name:  "fail",
+			files: map[string]string{"pass_test.flux": passingTestCase, "fail_test.flux": failingTestCase},
+			code:  1,
+		},
+		{
+			name:  "filtered fail",
+			files: map[string]string{"pass_test.flux": passingTestCase, "fail_test.flux": failingTestCase},
+			run:   "pass",
+			code:  0,
+		},
+		{
+			name:  "assertion pass",
+			files: map[string]string{"assert_test.flux": passingAssertion},
+			code:  0,
+		},
+		{
+			name:  "assertion fail",
+			files: map[string]string{"assert_test.flux": failingAssertion},
+			code:  1,
+		},
+		{
+			name: "local package",
+			files: map[string]string{
+				"inc_test.flux":             localPackageTestCase,
+				"modules/acme/inc/inc.flux": localPackage,
+			},
+			fluxPath: "modules",
+			code:     0,
+		},
+		{
+			name:  "parse error",
+			files: map[string]string{"error_test.flux": "test _broken = () => ({"},
+			code:  2,
+		},
+		{
+			name:  "invalid pattern",
+			files: map[string]string{"pass_test.flux": passingTestCase},
+			run:   "(",
+			code:  2,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeFiles(t, tc.files)
+			defer os.RemoveAll(dir)
+
+			testFlags.run = tc.run
+			defer func() { testFlags.run = "" }()
+			if tc.fluxPath != "" {
+				rootFlags.fluxPath = filepath.Join(dir, tc.fluxPath)
+				defer func() { rootFlags.fluxPath = "" }()
+			}
+
+			err := test(testCmd, []string{dir})
+			if got := exitCode(err); tc.code != got {
+				t.Errorf("unexpected exit code: want %d, got %d (%v)", tc.code, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/influxdata/flux/ast"
+	fluxpromql "github.com/influxdata/flux/promql"
+	"github.com/influxdata/promql/v2"
+	"github.com/spf13/cobra"
+)
+
+// transpileCmd represents the transpile command
+var transpileCmd = &cobra.Command{
+	Use:   "transpile",
+	Short: "Transpile a query from another language into Flux",
+	Long:  "Transpile a query from another language into a Flux script from string or file (use @ as prefix to the file)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  transpile,
+}
+
+var transpileFlags struct {
+	from   string
+	bucket string
+	org    string
+	host   string
+	start  string
+	end    string
+	step   time.Duration
+}
+
+func init() {
+	rootCmd.AddCommand(transpileCmd)
+	transpileCmd.Flags().StringVar(&transpileFlags.from, "from", "promql", "language of the query, only promql is supported")
+	transpileCmd.Flags().StringVar(&transpileFlags.bucket, "bucket", "prometheus", "bucket that the transpiled query reads from")
+	transpileCmd.Flags().StringVar(&transpileFlags.org, "org", "", "organization that the transpiled query reads from")
+	transpileCmd.Flags().StringVar(&transpileFlags.host, "host", "", "influxdb host that the transpiled query reads from")
+	transpileCmd.Flags().StringVar(&transpileFlags.start, "start", "", "RFC3339 start time of the query, defaults to the end time for instant queries and an hour before it for range queries")
+	transpileCmd.Flags().StringVar(&transpileFlags.end, "end", "", "RFC3339 end time of the query, defaults to now")
+	transpileCmd.Flags().DurationVar(&transpileFlags.step, "step", 0, "resolution of a range query, an instant query at the end time is transpiled when zero")
+}
+
+func transpile(cmd *cobra.Command, args []string) error {
+	if transpileFlags.from != "promql" {
+		return fmt.Errorf("cannot transpile from %q, only promql is supported", transpileFlags.from)
+	}
+
+	querySource := args[0]
+
+	var query string
+	if querySource[0] == '@' {
+		queryBytes, err := ioutil.ReadFile(querySource[1:])
+		if err != nil {
+			return err
+		}
+		query = string(queryBytes)
+	} else {
+		query = querySource
+	}
+
+	end := time.Now().UTC()
+	if transpileFlags.end != "" {
+		t, err := time.Parse(time.RFC3339Nano, transpileFlags.end)
+		if err != nil {
+			return fmt.Errorf("invalid end time: %v", err)
+		}
+		end = t
+	}
+	start := end.Add(-time.Hour)
+	if transpileFlags.step == 0 {
+		start = end
+	}
+	if transpileFlags.start != "" {
+		t, err := time.Parse(time.RFC3339Nano, transpileFlags.start)
+		if err != nil {
+			return fmt.Errorf("invalid start time: %v", err)
+		}
+		start = t
+	}
+	if start.After(end) {
+		return fmt.Errorf("start time %s is after end time %s", start.Format(time.RFC3339Nano), end.Format(time.RFC3339Nano))
+	}
+
+	expr, err := promql.ParseExpr(query)
+	if err != nil {
+		return fmt.Errorf("invalid promql query: %v", err)
+	}
+	t := &fluxpromql.Transpiler{
+		Bucket:     transpileFlags.bucket,
+		Org:        transpileFlags.org,
+		Host:       transpileFlags.host,
+		Start:      start,
+		End:        end,
+		Resolution: transpileFlags.step,
+	}
+	file, err := t.Transpile(expr)
+	if err != nil {
+		return err
+	}
+	fmt.Println(ast.Format(file))
+	return nil
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/influxdata/flux/fluxinit"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/repl"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/vet"
+	"github.com/spf13/cobra"
+)
+
+// vetCmd represents the vet command
+var vetCmd = &cobra.Command{
+	Use:   "vet [script]",
+	Short: "Report likely mistakes in a Flux script",
+	Long: `Report likely mistakes in a Flux script from string or file (use @ as prefix to the file).
+
+The script is analyzed and planned, but not executed. Each issue is printed with
+its location, the rule that reported it and a suggested fix. vet exits with an
+error if any issue is found. Use --list to print the available rules.
+
+The deprecated implementations of fill, drop, keep, rename and duplicate are
+selected by the planner of the application that runs Flux, not by the script.
+This command does not register such a planner rule, so the
+deprecated-implementation rule does not report them here.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: vetScript,
+}
+
+var vetFlags struct {
+	enable  []string
+	disable []string
+	list    bool
+}
+
+func init() {
+	rootCmd.AddCommand(vetCmd)
+	vetCmd.Flags().StringSliceVar(&vetFlags.enable, "enable", nil, "run only the listed rules")
+	vetCmd.Flags().StringSliceVar(&vetFlags.disable, "disable", nil, "do not run the listed rules")
+	vetCmd.Flags().BoolVar(&vetFlags.list, "list", false, "list the available rules")
+}
+
+func vetScript(cmd *cobra.Command, args []string) error {
+	fluxinit.FluxInit()
+
+	var opts []vet.Option
+	if len(vetFlags.enable) > 0 {
+		opts = append(opts, vet.OnlyRules(vetFlags.enable...))
+	}
+	if len(vetFlags.disable) > 0 {
+		opts = append(opts, vet.DisableRules(vetFlags.disable...))
+	}
+	checker, err := vet.New(opts...)
+	if err != nil {
+		return err
+	}
+
+	if vetFlags.list {
+		for _, rule := range checker.Rules() {
+			fmt.Printf("%-26s %s\n", rule.Name(), rule.Doc())
+		}
+		return nil
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("a script is required")
+	}
+
+	q, err := repl.LoadQuery(args[0])
+	if err != nil {
+		return err
+	}
+	astPkg, err := runtime.Parse(q)
+	if err != nil {
+		return err
+	}
+	semPkg, err := newImporter().Analyze(astPkg)
+	if err != nil {
+		return err
+	}
+	issues := checker.CheckSemantic(semPkg)
+
+	ctx, _ := injectDependencies(context.Background(), nil)
+	program, err := lang.Compile(q, runtime.WithImporter(newImporter()), time.Now())
+	if err != nil {
+		return err
+	}
+	if ps, err := program.LogicalPlan(ctx, &memory.Allocator{}); err != nil {
+		// The issues found so far may be why the script cannot be planned.
+		fmt.Fprintf(os.Stderr, "skipping the plan rules, the script could not be planned: %v\n", err)
+	} else {
+		issues = append(issues, checker.CheckPlan(ps)...)
+	}
+
+	for _, issue := range issues {
+		fmt.Println(issue)
+		if issue.Fix != "" {
+			fmt.Printf("\t%s\n", issue.Fix)
+		}
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("%d issues found", len(issues))
+	}
+	return nil
+}
@@ -0,0 +1,1039 @@
+// Generated by tmpl
+// https://github.com/benbjohnson/tmpl
+//
+// DO NOT EDIT!
+// Source: vectorized.gen.go.tmpl
+
+package compiler
+
+import (
+	"math"
+
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/semantic"
+)
+
+// vectorBinaryKernels contains the kernels for the binary
+// expressions that can be evaluated on two vectors of the same type.
+var vectorBinaryKernels = map[vectorKernelSignature]vectorBinaryKernel{
+	{Operator: ast.EqualOperator, Nature: semantic.Int}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorEqualInts(l.(*array.Int64), r.(*array.Int64), mem), nil
+		},
+	},
+	{Operator: ast.NotEqualOperator, Nature: semantic.Int}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorNotEqualInts(l.(*array.Int64), r.(*array.Int64), mem), nil
+		},
+	},
+	{Operator: ast.LessThanOperator, Nature: semantic.Int}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorLessThanInts(l.(*array.Int64), r.(*array.Int64), mem), nil
+		},
+	},
+	{Operator: ast.LessThanEqualOperator, Nature: semantic.Int}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorLessThanEqualInts(l.(*array.Int64), r.(*array.Int64), mem), nil
+		},
+	},
+	{Operator: ast.GreaterThanOperator, Nature: semantic.Int}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorGreaterThanInts(l.(*array.Int64), r.(*array.Int64), mem), nil
+		},
+	},
+	{Operator: ast.GreaterThanEqualOperator, Nature: semantic.Int}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorGreaterThanEqualInts(l.(*array.Int64), r.(*array.Int64), mem), nil
+		},
+	},
+	{Operator: ast.AdditionOperator, Nature: semantic.Int}: {
+		t: semantic.BasicInt,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorAddInts(l.(*array.Int64), r.(*array.Int64), mem)
+		},
+	},
+	{Operator: ast.SubtractionOperator, Nature: semantic.Int}: {
+		t: semantic.BasicInt,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorSubtractInts(l.(*array.Int64), r.(*array.Int64), mem)
+		},
+	},
+	{Operator: ast.MultiplicationOperator, Nature: semantic.Int}: {
+		t: semantic.BasicInt,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorMultiplyInts(l.(*array.Int64), r.(*array.Int64), mem)
+		},
+	},
+	{Operator: ast.DivisionOperator, Nature: semantic.Int}: {
+		t: semantic.BasicInt,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorDivideInts(l.(*array.Int64), r.(*array.Int64), mem)
+		},
+	},
+	{Operator: ast.ModuloOperator, Nature: semantic.Int}: {
+		t: semantic.BasicInt,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorModuloInts(l.(*array.Int64), r.(*array.Int64), mem)
+		},
+	},
+	{Operator: ast.EqualOperator, Nature: semantic.UInt}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorEqualUints(l.(*array.Uint64), r.(*array.Uint64), mem), nil
+		},
+	},
+	{Operator: ast.NotEqualOperator, Nature: semantic.UInt}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorNotEqualUints(l.(*array.Uint64), r.(*array.Uint64), mem), nil
+		},
+	},
+	{Operator: ast.LessThanOperator, Nature: semantic.UInt}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorLessThanUints(l.(*array.Uint64), r.(*array.Uint64), mem), nil
+		},
+	},
+	{Operator: ast.LessThanEqualOperator, Nature: semantic.UInt}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorLessThanEqualUints(l.(*array.Uint64), r.(*array.Uint64), mem), nil
+		},
+	},
+	{Operator: ast.GreaterThanOperator, Nature: semantic.UInt}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorGreaterThanUints(l.(*array.Uint64), r.(*array.Uint64), mem), nil
+		},
+	},
+	{Operator: ast.GreaterThanEqualOperator, Nature: semantic.UInt}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorGreaterThanEqualUints(l.(*array.Uint64), r.(*array.Uint64), mem), nil
+		},
+	},
+	{Operator: ast.AdditionOperator, Nature: semantic.UInt}: {
+		t: semantic.BasicUint,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorAddUints(l.(*array.Uint64), r.(*array.Uint64), mem)
+		},
+	},
+	{Operator: ast.SubtractionOperator, Nature: semantic.UInt}: {
+		t: semantic.BasicUint,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorSubtractUints(l.(*array.Uint64), r.(*array.Uint64), mem)
+		},
+	},
+	{Operator: ast.MultiplicationOperator, Nature: semantic.UInt}: {
+		t: semantic.BasicUint,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorMultiplyUints(l.(*array.Uint64), r.(*array.Uint64), mem)
+		},
+	},
+	{Operator: ast.DivisionOperator, Nature: semantic.UInt}: {
+		t: semantic.BasicUint,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorDivideUints(l.(*array.Uint64), r.(*array.Uint64), mem)
+		},
+	},
+	{Operator: ast.ModuloOperator, Nature: semantic.UInt}: {
+		t: semantic.BasicUint,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorModuloUints(l.(*array.Uint64), r.(*array.Uint64), mem)
+		},
+	},
+	{Operator: ast.EqualOperator, Nature: semantic.Float}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorEqualFloats(l.(*array.Float64), r.(*array.Float64), mem), nil
+		},
+	},
+	{Operator: ast.NotEqualOperator, Nature: semantic.Float}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorNotEqualFloats(l.(*array.Float64), r.(*array.Float64), mem), nil
+		},
+	},
+	{Operator: ast.LessThanOperator, Nature: semantic.Float}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorLessThanFloats(l.(*array.Float64), r.(*array.Float64), mem), nil
+		},
+	},
+	{Operator: ast.LessThanEqualOperator, Nature: semantic.Float}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorLessThanEqualFloats(l.(*array.Float64), r.(*array.Float64), mem), nil
+		},
+	},
+	{Operator: ast.GreaterThanOperator, Nature: semantic.Float}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorGreaterThanFloats(l.(*array.Float64), r.(*array.Float64), mem), nil
+		},
+	},
+	{Operator: ast.GreaterThanEqualOperator, Nature: semantic.Float}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorGreaterThanEqualFloats(l.(*array.Float64), r.(*array.Float64), mem), nil
+		},
+	},
+	{Operator: ast.AdditionOperator, Nature: semantic.Float}: {
+		t: semantic.BasicFloat,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorAddFloats(l.(*array.Float64), r.(*array.Float64), mem)
+		},
+	},
+	{Operator: ast.SubtractionOperator, Nature: semantic.Float}: {
+		t: semantic.BasicFloat,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorSubtractFloats(l.(*array.Float64), r.(*array.Float64), mem)
+		},
+	},
+	{Operator: ast.MultiplicationOperator, Nature: semantic.Float}: {
+		t: semantic.BasicFloat,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorMultiplyFloats(l.(*array.Float64), r.(*array.Float64), mem)
+		},
+	},
+	{Operator: ast.DivisionOperator, Nature: semantic.Float}: {
+		t: semantic.BasicFloat,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorDivideFloats(l.(*array.Float64), r.(*array.Float64), mem)
+		},
+	},
+	{Operator: ast.ModuloOperator, Nature: semantic.Float}: {
+		t: semantic.BasicFloat,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorModuloFloats(l.(*array.Float64), r.(*array.Float64), mem)
+		},
+	},
+	{Operator: ast.EqualOperator, Nature: semantic.Bool}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorEqualBooleans(l.(*array.Boolean), r.(*array.Boolean), mem), nil
+		},
+	},
+	{Operator: ast.NotEqualOperator, Nature: semantic.Bool}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorNotEqualBooleans(l.(*array.Boolean), r.(*array.Boolean), mem), nil
+		},
+	},
+	{Operator: ast.EqualOperator, Nature: semantic.String}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorEqualStrings(l.(*array.Binary), r.(*array.Binary), mem), nil
+		},
+	},
+	{Operator: ast.NotEqualOperator, Nature: semantic.String}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorNotEqualStrings(l.(*array.Binary), r.(*array.Binary), mem), nil
+		},
+	},
+	{Operator: ast.LessThanOperator, Nature: semantic.String}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorLessThanStrings(l.(*array.Binary), r.(*array.Binary), mem), nil
+		},
+	},
+	{Operator: ast.LessThanEqualOperator, Nature: semantic.String}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorLessThanEqualStrings(l.(*array.Binary), r.(*array.Binary), mem), nil
+		},
+	},
+	{Operator: ast.GreaterThanOperator, Nature: semantic.String}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorGreaterThanStrings(l.(*array.Binary), r.(*array.Binary), mem), nil
+		},
+	},
+	{Operator: ast.GreaterThanEqualOperator, Nature: semantic.String}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorGreaterThanEqualStrings(l.(*array.Binary), r.(*array.Binary), mem), nil
+		},
+	},
+	{Operator: ast.EqualOperator, Nature: semantic.Time}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorEqualTimes(l.(*array.Int64), r.(*array.Int64), mem), nil
+		},
+	},
+	{Operator: ast.NotEqualOperator, Nature: semantic.Time}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorNotEqualTimes(l.(*array.Int64), r.(*array.Int64), mem), nil
+		},
+	},
+	{Operator: ast.LessThanOperator, Nature: semantic.Time}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorLessThanTimes(l.(*array.Int64), r.(*array.Int64), mem), nil
+		},
+	},
+	{Operator: ast.LessThanEqualOperator, Nature: semantic.Time}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorLessThanEqualTimes(l.(*array.Int64), r.(*array.Int64), mem), nil
+		},
+	},
+	{Operator: ast.GreaterThanOperator, Nature: semantic.Time}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorGreaterThanTimes(l.(*array.Int64), r.(*array.Int64), mem), nil
+		},
+	},
+	{Operator: ast.GreaterThanEqualOperator, Nature: semantic.Time}: {
+		t: semantic.BasicBool,
+		fn: func(l, r array.Interface, mem memory.Allocator) (array.Interface, error) {
+			return vectorGreaterThanEqualTimes(l.(*array.Int64), r.(*array.Int64), mem), nil
+		},
+	},
+}
+
+func vectorEqualInts(l, r *array.Int64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) == r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorNotEqualInts(l, r *array.Int64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) != r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorLessThanInts(l, r *array.Int64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) < r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorLessThanEqualInts(l, r *array.Int64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) <= r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorGreaterThanInts(l, r *array.Int64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) > r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorGreaterThanEqualInts(l, r *array.Int64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) >= r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorAddInts(l, r *array.Int64, mem memory.Allocator) (*array.Int64, error) {
+	n := l.Len()
+	b := array.NewInt64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		lv, rv := l.Value(i), r.Value(i)
+
+		b.Append(lv + rv)
+	}
+	return b.NewInt64Array(), nil
+}
+
+func vectorSubtractInts(l, r *array.Int64, mem memory.Allocator) (*array.Int64, error) {
+	n := l.Len()
+	b := array.NewInt64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		lv, rv := l.Value(i), r.Value(i)
+
+		b.Append(lv - rv)
+	}
+	return b.NewInt64Array(), nil
+}
+
+func vectorMultiplyInts(l, r *array.Int64, mem memory.Allocator) (*array.Int64, error) {
+	n := l.Len()
+	b := array.NewInt64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		lv, rv := l.Value(i), r.Value(i)
+
+		b.Append(lv * rv)
+	}
+	return b.NewInt64Array(), nil
+}
+
+func vectorDivideInts(l, r *array.Int64, mem memory.Allocator) (*array.Int64, error) {
+	n := l.Len()
+	b := array.NewInt64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		lv, rv := l.Value(i), r.Value(i)
+		if rv == 0 {
+			b.Release()
+			return nil, errors.New(codes.FailedPrecondition, "cannot divide by zero")
+		}
+		b.Append(lv / rv)
+	}
+	return b.NewInt64Array(), nil
+}
+
+func vectorModuloInts(l, r *array.Int64, mem memory.Allocator) (*array.Int64, error) {
+	n := l.Len()
+	b := array.NewInt64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		lv, rv := l.Value(i), r.Value(i)
+		if rv == 0 {
+			b.Release()
+			return nil, errors.New(codes.FailedPrecondition, "cannot mod zero")
+		}
+		b.Append(lv % rv)
+	}
+	return b.NewInt64Array(), nil
+}
+
+func vectorNegateInts(arr *array.Int64, mem memory.Allocator) *array.Int64 {
+	n := arr.Len()
+	b := array.NewInt64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if arr.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(-arr.Value(i))
+	}
+	return b.NewInt64Array()
+}
+
+func vectorEqualUints(l, r *array.Uint64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) == r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorNotEqualUints(l, r *array.Uint64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) != r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorLessThanUints(l, r *array.Uint64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) < r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorLessThanEqualUints(l, r *array.Uint64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) <= r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorGreaterThanUints(l, r *array.Uint64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) > r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorGreaterThanEqualUints(l, r *array.Uint64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) >= r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorAddUints(l, r *array.Uint64, mem memory.Allocator) (*array.Uint64, error) {
+	n := l.Len()
+	b := array.NewUint64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		lv, rv := l.Value(i), r.Value(i)
+
+		b.Append(lv + rv)
+	}
+	return b.NewUint64Array(), nil
+}
+
+func vectorSubtractUints(l, r *array.Uint64, mem memory.Allocator) (*array.Uint64, error) {
+	n := l.Len()
+	b := array.NewUint64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		lv, rv := l.Value(i), r.Value(i)
+
+		b.Append(lv - rv)
+	}
+	return b.NewUint64Array(), nil
+}
+
+func vectorMultiplyUints(l, r *array.Uint64, mem memory.Allocator) (*array.Uint64, error) {
+	n := l.Len()
+	b := array.NewUint64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		lv, rv := l.Value(i), r.Value(i)
+
+		b.Append(lv * rv)
+	}
+	return b.NewUint64Array(), nil
+}
+
+func vectorDivideUints(l, r *array.Uint64, mem memory.Allocator) (*array.Uint64, error) {
+	n := l.Len()
+	b := array.NewUint64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		lv, rv := l.Value(i), r.Value(i)
+		if rv == 0 {
+			b.Release()
+			return nil, errors.New(codes.FailedPrecondition, "cannot divide by zero")
+		}
+		b.Append(lv / rv)
+	}
+	return b.NewUint64Array(), nil
+}
+
+func vectorModuloUints(l, r *array.Uint64, mem memory.Allocator) (*array.Uint64, error) {
+	n := l.Len()
+	b := array.NewUint64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		lv, rv := l.Value(i), r.Value(i)
+		if rv == 0 {
+			b.Release()
+			return nil, errors.New(codes.FailedPrecondition, "cannot mod zero")
+		}
+		b.Append(lv % rv)
+	}
+	return b.NewUint64Array(), nil
+}
+
+func vectorEqualFloats(l, r *array.Float64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) == r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorNotEqualFloats(l, r *array.Float64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) != r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorLessThanFloats(l, r *array.Float64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) < r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorLessThanEqualFloats(l, r *array.Float64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) <= r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorGreaterThanFloats(l, r *array.Float64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) > r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorGreaterThanEqualFloats(l, r *array.Float64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) >= r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorAddFloats(l, r *array.Float64, mem memory.Allocator) (*array.Float64, error) {
+	n := l.Len()
+	b := array.NewFloat64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		lv, rv := l.Value(i), r.Value(i)
+
+		b.Append(lv + rv)
+	}
+	return b.NewFloat64Array(), nil
+}
+
+func vectorSubtractFloats(l, r *array.Float64, mem memory.Allocator) (*array.Float64, error) {
+	n := l.Len()
+	b := array.NewFloat64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		lv, rv := l.Value(i), r.Value(i)
+
+		b.Append(lv - rv)
+	}
+	return b.NewFloat64Array(), nil
+}
+
+func vectorMultiplyFloats(l, r *array.Float64, mem memory.Allocator) (*array.Float64, error) {
+	n := l.Len()
+	b := array.NewFloat64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		lv, rv := l.Value(i), r.Value(i)
+
+		b.Append(lv * rv)
+	}
+	return b.NewFloat64Array(), nil
+}
+
+func vectorDivideFloats(l, r *array.Float64, mem memory.Allocator) (*array.Float64, error) {
+	n := l.Len()
+	b := array.NewFloat64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		lv, rv := l.Value(i), r.Value(i)
+		if rv == 0 {
+			b.Release()
+			return nil, errors.New(codes.FailedPrecondition, "cannot divide by zero")
+		}
+		b.Append(lv / rv)
+	}
+	return b.NewFloat64Array(), nil
+}
+
+func vectorModuloFloats(l, r *array.Float64, mem memory.Allocator) (*array.Float64, error) {
+	n := l.Len()
+	b := array.NewFloat64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		lv, rv := l.Value(i), r.Value(i)
+		if rv == 0 {
+			b.Release()
+			return nil, errors.New(codes.FailedPrecondition, "cannot mod zero")
+		}
+		b.Append(math.Mod(lv, rv))
+	}
+	return b.NewFloat64Array(), nil
+}
+
+func vectorNegateFloats(arr *array.Float64, mem memory.Allocator) *array.Float64 {
+	n := arr.Len()
+	b := array.NewFloat64Builder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if arr.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(-arr.Value(i))
+	}
+	return b.NewFloat64Array()
+}
+
+func vectorEqualBooleans(l, r *array.Boolean, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) == r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorNotEqualBooleans(l, r *array.Boolean, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) != r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorEqualStrings(l, r *array.Binary, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.ValueString(i) == r.ValueString(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorNotEqualStrings(l, r *array.Binary, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.ValueString(i) != r.ValueString(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorLessThanStrings(l, r *array.Binary, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.ValueString(i) < r.ValueString(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorLessThanEqualStrings(l, r *array.Binary, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.ValueString(i) <= r.ValueString(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorGreaterThanStrings(l, r *array.Binary, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.ValueString(i) > r.ValueString(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorGreaterThanEqualStrings(l, r *array.Binary, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.ValueString(i) >= r.ValueString(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorEqualTimes(l, r *array.Int64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) == r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorNotEqualTimes(l, r *array.Int64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) != r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorLessThanTimes(l, r *array.Int64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) < r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorLessThanEqualTimes(l, r *array.Int64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) <= r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorGreaterThanTimes(l, r *array.Int64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) > r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
+
+func vectorGreaterThanEqualTimes(l, r *array.Int64, mem memory.Allocator) *array.Boolean {
+	n := l.Len()
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i := 0; i < n; i++ {
+		if l.IsNull(i) || r.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		b.Append(l.Value(i) >= r.Value(i))
+	}
+	return b.NewBooleanArray()
+}
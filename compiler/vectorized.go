@@ -0,0 +1,632 @@
+package compiler
+
+import (
+	"context"
+	"sort"
+
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/bitutil"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/arrowutil"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+//go:generate -command tmpl ../gotool.sh github.com/benbjohnson/tmpl
+//go:generate tmpl -data=@../internal/types.tmpldata -o vectorized.gen.go vectorized.gen.go.tmpl
+
+// VectorFunc is a function that has been compiled into kernels
+// that evaluate every row of a flux.ColReader at once.
+type VectorFunc interface {
+	// Type returns the type of the value produced for each row.
+	Type() semantic.MonoType
+	// Eval evaluates the function for every row of the column reader.
+	// The caller must release the returned vector.
+	Eval(ctx context.Context, cr flux.ColReader, mem memory.Allocator) (*Vector, error)
+}
+
+// Vector holds the value of a function for each row of a column reader.
+// Basic values are held in an arrow array and records hold a vector
+// for each of their properties.
+type Vector struct {
+	Type    semantic.MonoType
+	Values  array.Interface
+	Labels  []string
+	Columns []*Vector
+}
+
+// Len returns the number of rows in the vector.
+func (v *Vector) Len() int {
+	if v.Values != nil {
+		return v.Values.Len()
+	}
+	if len(v.Columns) > 0 {
+		return v.Columns[0].Len()
+	}
+	return 0
+}
+
+// Value returns the value of the vector for row i.
+func (v *Vector) Value(i int) values.Value {
+	if v.Type.Nature() == semantic.Object {
+		obj := values.NewObject(v.Type)
+		for k, label := range v.Labels {
+			obj.Set(label, v.Columns[k].Value(i))
+		}
+		return obj
+	}
+	if v.Values.IsNull(i) {
+		return values.NewNull(v.Type)
+	}
+	switch arr := v.Values.(type) {
+	case *array.Int64:
+		if v.Type.Nature() == semantic.Time {
+			return values.NewTime(values.Time(arr.Value(i)))
+		}
+		return values.NewInt(arr.Value(i))
+	case *array.Uint64:
+		return values.NewUInt(arr.Value(i))
+	case *array.Float64:
+		return values.NewFloat(arr.Value(i))
+	case *array.Binary:
+		return values.NewString(arr.ValueString(i))
+	case *array.Boolean:
+		return values.NewBool(arr.Value(i))
+	default:
+		panic(errors.Newf(codes.Internal, "unsupported vector type: %s", v.Type))
+	}
+}
+
+// Release releases the arrays held by the vector.
+func (v *Vector) Release() {
+	if v.Values != nil {
+		v.Values.Release()
+	}
+	for _, c := range v.Columns {
+		c.Release()
+	}
+}
+
+// CompileVector compiles a function of a single record parameter into
+// kernels that evaluate the function for every row of a column reader
+// with the given columns at once.
+//
+// Only a subset of functions can be vectorized. The function must return
+// either a record or a single expression. The expressions may contain literals,
+// members of the record, basic values from the scope, and arithmetic,
+// comparison, logical and unary operators on values of the same type.
+// An error with the Unimplemented code is returned for any other function
+// so the caller can fall back to evaluating each row with Compile.
+func CompileVector(scope Scope, f *semantic.FunctionExpression, cols []flux.ColMeta) (VectorFunc, error) {
+	if f.Parameters == nil || len(f.Parameters.List) != 1 || f.Parameters.Pipe != nil || f.Defaults != nil {
+		return nil, errors.New(codes.Unimplemented, "only functions of a single record can be vectorized")
+	}
+	if f.Block == nil || len(f.Block.Body) != 1 {
+		return nil, errors.New(codes.Unimplemented, "only functions that return a single expression can be vectorized")
+	}
+	ret, ok := f.Block.Body[0].(*semantic.ReturnStatement)
+	if !ok {
+		return nil, errors.New(codes.Unimplemented, "only functions that return a single expression can be vectorized")
+	}
+
+	c := &vectorCompiler{
+		scope: scope,
+		param: f.Parameters.List[0].Key.Name,
+		cols:  cols,
+	}
+	if obj, ok := ret.Argument.(*semantic.ObjectExpression); ok {
+		return c.compileRecord(obj)
+	}
+	e, err := c.compile(ret.Argument)
+	if err != nil {
+		return nil, err
+	}
+	return vectorFunc{e: e}, nil
+}
+
+// vectorEvaluator evaluates an expression for every row of a column reader.
+// The caller must release the returned array.
+type vectorEvaluator interface {
+	Type() semantic.MonoType
+	EvalVector(ctx context.Context, cr flux.ColReader, mem memory.Allocator) (array.Interface, error)
+}
+
+type vectorKernelSignature struct {
+	Operator ast.OperatorKind
+	Nature   semantic.Nature
+}
+
+type vectorBinaryKernel struct {
+	t  semantic.MonoType
+	fn func(l, r array.Interface, mem memory.Allocator) (array.Interface, error)
+}
+
+type vectorCompiler struct {
+	scope Scope
+	param string
+	cols  []flux.ColMeta
+}
+
+func (c *vectorCompiler) compileRecord(obj *semantic.ObjectExpression) (VectorFunc, error) {
+	properties := make(map[string]vectorEvaluator, len(obj.Properties))
+	for _, p := range obj.Properties {
+		e, err := c.compile(p.Value)
+		if err != nil {
+			return nil, err
+		}
+		properties[p.Key.Key()] = e
+	}
+
+	f := &recordVectorFunc{}
+	if obj.With != nil {
+		if obj.With.Name != c.param {
+			return nil, errors.Newf(codes.Unimplemented, "cannot vectorize a record that extends %q", obj.With.Name)
+		}
+		for j, col := range c.cols {
+			if _, ok := properties[col.Label]; ok {
+				continue
+			}
+			f.labels = append(f.labels, col.Label)
+			f.columns = append(f.columns, &columnVectorEvaluator{
+				t: flux.SemanticType(col.Type),
+				j: j,
+			})
+		}
+	}
+
+	labels := make([]string, 0, len(properties))
+	for label := range properties {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		f.labels = append(f.labels, label)
+		f.columns = append(f.columns, properties[label])
+	}
+
+	props := make([]semantic.PropertyType, len(f.labels))
+	for i, label := range f.labels {
+		props[i] = semantic.PropertyType{
+			Key:   []byte(label),
+			Value: f.columns[i].Type(),
+		}
+	}
+	f.t = semantic.NewObjectType(props)
+	return f, nil
+}
+
+func (c *vectorCompiler) compile(n semantic.Expression) (vectorEvaluator, error) {
+	switch n := n.(type) {
+	case *semantic.MemberExpression:
+		if id, ok := n.Object.(*semantic.IdentifierExpression); !ok || id.Name != c.param {
+			return nil, errors.New(codes.Unimplemented, "only members of the record can be vectorized")
+		}
+		for j, col := range c.cols {
+			if col.Label == n.Property {
+				return &columnVectorEvaluator{
+					t: flux.SemanticType(col.Type),
+					j: j,
+				}, nil
+			}
+		}
+		return nil, errors.Newf(codes.Unimplemented, "cannot vectorize missing column %q", n.Property)
+	case *semantic.IdentifierExpression:
+		if n.Name == c.param || c.scope == nil {
+			return nil, errors.Newf(codes.Unimplemented, "cannot vectorize identifier %q", n.Name)
+		}
+		v, ok := c.scope.Lookup(n.Name)
+		if !ok {
+			return nil, errors.Newf(codes.Unimplemented, "cannot vectorize identifier %q", n.Name)
+		}
+		return newConstVectorEvaluator(v)
+	case *semantic.IntegerLiteral:
+		return newConstVectorEvaluator(values.NewInt(n.Value))
+	case *semantic.UnsignedIntegerLiteral:
+		return newConstVectorEvaluator(values.NewUInt(n.Value))
+	case *semantic.FloatLiteral:
+		return newConstVectorEvaluator(values.NewFloat(n.Value))
+	case *semantic.StringLiteral:
+		return newConstVectorEvaluator(values.NewString(n.Value))
+	case *semantic.BooleanLiteral:
+		return newConstVectorEvaluator(values.NewBool(n.Value))
+	case *semantic.DateTimeLiteral:
+		return newConstVectorEvaluator(values.NewTime(values.ConvertTime(n.Value)))
+	case *semantic.BinaryExpression:
+		l, err := c.compile(n.Left)
+		if err != nil {
+			return nil, err
+		}
+		r, err := c.compile(n.Right)
+		if err != nil {
+			return nil, err
+		}
+		if l.Type().Nature() != r.Type().Nature() {
+			return nil, errors.Newf(codes.Unimplemented, "cannot vectorize %v %v %v", l.Type(), n.Operator, r.Type())
+		}
+		kernel, ok := vectorBinaryKernels[vectorKernelSignature{
+			Operator: n.Operator,
+			Nature:   l.Type().Nature(),
+		}]
+		if !ok {
+			return nil, errors.Newf(codes.Unimplemented, "cannot vectorize %v %v %v", l.Type(), n.Operator, r.Type())
+		}
+		return &binaryVectorEvaluator{
+			kernel: kernel,
+			left:   l,
+			right:  r,
+		}, nil
+	case *semantic.LogicalExpression:
+		l, err := c.compile(n.Left)
+		if err != nil {
+			return nil, err
+		}
+		r, err := c.compile(n.Right)
+		if err != nil {
+			return nil, err
+		}
+		if l.Type().Nature() != semantic.Bool || r.Type().Nature() != semantic.Bool {
+			return nil, errors.Newf(codes.Unimplemented, "cannot vectorize %v %v %v", l.Type(), n.Operator, r.Type())
+		}
+		return &logicalVectorEvaluator{
+			operator: n.Operator,
+			left:     l,
+			right:    r,
+		}, nil
+	case *semantic.UnaryExpression:
+		e, err := c.compile(n.Argument)
+		if err != nil {
+			return nil, err
+		}
+		switch nature := e.Type().Nature(); {
+		case n.Operator == ast.ExistsOperator,
+			n.Operator == ast.AdditionOperator && (nature == semantic.Int || nature == semantic.UInt || nature == semantic.Float),
+			n.Operator == ast.SubtractionOperator && (nature == semantic.Int || nature == semantic.Float),
+			n.Operator == ast.NotOperator && nature == semantic.Bool:
+			return &unaryVectorEvaluator{
+				op:   n.Operator,
+				node: e,
+			}, nil
+		default:
+			return nil, errors.Newf(codes.Unimplemented, "cannot vectorize %v %v", n.Operator, e.Type())
+		}
+	default:
+		return nil, errors.Newf(codes.Unimplemented, "cannot vectorize %s", n.NodeType())
+	}
+}
+
+type vectorFunc struct {
+	e vectorEvaluator
+}
+
+func (f vectorFunc) Type() semantic.MonoType {
+	return f.e.Type()
+}
+
+func (f vectorFunc) Eval(ctx context.Context, cr flux.ColReader, mem memory.Allocator) (*Vector, error) {
+	arr, err := f.e.EvalVector(ctx, cr, mem)
+	if err != nil {
+		return nil, err
+	}
+	return &Vector{
+		Type:   f.e.Type(),
+		Values: arr,
+	}, nil
+}
+
+type recordVectorFunc struct {
+	t       semantic.MonoType
+	labels  []string
+	columns []vectorEvaluator
+}
+
+func (f *recordVectorFunc) Type() semantic.MonoType {
+	return f.t
+}
+
+func (f *recordVectorFunc) Eval(ctx context.Context, cr flux.ColReader, mem memory.Allocator) (*Vector, error) {
+	v := &Vector{
+		Type:    f.t,
+		Labels:  f.labels,
+		Columns: make([]*Vector, 0, len(f.columns)),
+	}
+	for _, e := range f.columns {
+		arr, err := e.EvalVector(ctx, cr, mem)
+		if err != nil {
+			v.Release()
+			return nil, err
+		}
+		v.Columns = append(v.Columns, &Vector{
+			Type:   e.Type(),
+			Values: arr,
+		})
+	}
+	return v, nil
+}
+
+type columnVectorEvaluator struct {
+	t semantic.MonoType
+	j int
+}
+
+func (e *columnVectorEvaluator) Type() semantic.MonoType {
+	return e.t
+}
+
+func (e *columnVectorEvaluator) EvalVector(ctx context.Context, cr flux.ColReader, mem memory.Allocator) (array.Interface, error) {
+	var arr array.Interface
+	switch e.t.Nature() {
+	case semantic.Int:
+		arr = cr.Ints(e.j)
+	case semantic.UInt:
+		arr = cr.UInts(e.j)
+	case semantic.Float:
+		arr = cr.Floats(e.j)
+	case semantic.String:
+		arr = cr.Strings(e.j)
+	case semantic.Bool:
+		arr = cr.Bools(e.j)
+	case semantic.Time:
+		arr = cr.Times(e.j)
+	default:
+		return nil, errors.Newf(codes.Internal, "unsupported column type: %s", e.t)
+	}
+	arr.Retain()
+	return arr, nil
+}
+
+type constVectorEvaluator struct {
+	v values.Value
+}
+
+func newConstVectorEvaluator(v values.Value) (vectorEvaluator, error) {
+	if v.IsNull() {
+		return nil, errors.New(codes.Unimplemented, "cannot vectorize a null value")
+	}
+	switch v.Type().Nature() {
+	case semantic.Int, semantic.UInt, semantic.Float, semantic.String, semantic.Bool, semantic.Time:
+		return &constVectorEvaluator{v: v}, nil
+	default:
+		return nil, errors.Newf(codes.Unimplemented, "cannot vectorize a value of type %v", v.Type())
+	}
+}
+
+func (e *constVectorEvaluator) Type() semantic.MonoType {
+	return e.v.Type()
+}
+
+func (e *constVectorEvaluator) EvalVector(ctx context.Context, cr flux.ColReader, mem memory.Allocator) (array.Interface, error) {
+	return arrow.Repeat(e.v, cr.Len(), mem), nil
+}
+
+type binaryVectorEvaluator struct {
+	kernel      vectorBinaryKernel
+	left, right vectorEvaluator
+}
+
+func (e *binaryVectorEvaluator) Type() semantic.MonoType {
+	return e.kernel.t
+}
+
+func (e *binaryVectorEvaluator) EvalVector(ctx context.Context, cr flux.ColReader, mem memory.Allocator) (array.Interface, error) {
+	l, err := e.left.EvalVector(ctx, cr, mem)
+	if err != nil {
+		return nil, err
+	}
+	defer l.Release()
+
+	r, err := e.right.EvalVector(ctx, cr, mem)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Release()
+	return e.kernel.fn(l, r, mem)
+}
+
+// logicalVectorEvaluator evaluates the logical operators with the
+// same semantics as the logicalEvaluator. The right side is only
+// evaluated for the rows that the left side does not decide.
+type logicalVectorEvaluator struct {
+	operator    ast.LogicalOperatorKind
+	left, right vectorEvaluator
+}
+
+func (e *logicalVectorEvaluator) Type() semantic.MonoType {
+	return semantic.BasicBool
+}
+
+func (e *logicalVectorEvaluator) EvalVector(ctx context.Context, cr flux.ColReader, mem memory.Allocator) (array.Interface, error) {
+	if e.operator != ast.AndOperator && e.operator != ast.OrOperator {
+		return nil, errors.Newf(codes.Internal, "unknown logical operator %v", e.operator)
+	}
+
+	l, err := e.left.EvalVector(ctx, cr, mem)
+	if err != nil {
+		return nil, err
+	}
+	defer l.Release()
+	lv := l.(*array.Boolean)
+	n := lv.Len()
+
+	// Mark the rows where the right side decides the result.
+	bitset := memory.NewResizableBuffer(mem)
+	defer bitset.Release()
+	bitset.Resize(n)
+	for i := 0; i < n; i++ {
+		bitutil.SetBitTo(bitset.Buf(), i, !e.decided(lv, i))
+	}
+
+	var rv *array.Boolean
+	switch m := bitutil.CountSetBits(bitset.Buf(), 0, n); m {
+	case 0:
+	case n:
+		r, err := e.right.EvalVector(ctx, cr, mem)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Release()
+		rv = r.(*array.Boolean)
+	default:
+		rows := &selectedRows{
+			ColReader: cr,
+			bitset:    bitset.Bytes(),
+			n:         m,
+			mem:       mem,
+			cols:      make([]array.Interface, len(cr.Cols())),
+		}
+		defer rows.Release()
+		r, err := e.right.EvalVector(ctx, rows, mem)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Release()
+		rv = r.(*array.Boolean)
+	}
+
+	b := array.NewBooleanBuilder(mem)
+	b.Resize(n)
+	for i, k := 0, 0; i < n; i++ {
+		if !bitutil.BitIsSet(bitset.Buf(), i) {
+			b.Append(e.operator == ast.OrOperator)
+			continue
+		}
+		if rv.IsNull(k) {
+			b.AppendNull()
+		} else {
+			b.Append(rv.Value(k))
+		}
+		k++
+	}
+	return b.NewBooleanArray(), nil
+}
+
+// decided reports whether the value of the left side for row i
+// decides the result without the right side.
+func (e *logicalVectorEvaluator) decided(l *array.Boolean, i int) bool {
+	if e.operator == ast.AndOperator {
+		return l.IsNull(i) || !l.Value(i)
+	}
+	return l.IsValid(i) && l.Value(i)
+}
+
+// selectedRows is a column reader with the rows of another column
+// reader that are set in the bitset. Each column is filtered
+// the first time it is read and released with the reader.
+type selectedRows struct {
+	flux.ColReader
+	bitset []byte
+	n      int
+	mem    memory.Allocator
+	cols   []array.Interface
+}
+
+func (r *selectedRows) Len() int {
+	return r.n
+}
+
+func (r *selectedRows) column(j int, arr array.Interface) array.Interface {
+	if r.cols[j] == nil {
+		r.cols[j] = arrowutil.Filter(arr, r.bitset, r.mem)
+	}
+	return r.cols[j]
+}
+
+func (r *selectedRows) Bools(j int) *array.Boolean {
+	return r.column(j, r.ColReader.Bools(j)).(*array.Boolean)
+}
+
+func (r *selectedRows) Ints(j int) *array.Int64 {
+	return r.column(j, r.ColReader.Ints(j)).(*array.Int64)
+}
+
+func (r *selectedRows) UInts(j int) *array.Uint64 {
+	return r.column(j, r.ColReader.UInts(j)).(*array.Uint64)
+}
+
+func (r *selectedRows) Floats(j int) *array.Float64 {
+	return r.column(j, r.ColReader.Floats(j)).(*array.Float64)
+}
+
+func (r *selectedRows) Strings(j int) *array.Binary {
+	return r.column(j, r.ColReader.Strings(j)).(*array.Binary)
+}
+
+func (r *selectedRows) Times(j int) *array.Int64 {
+	return r.column(j, r.ColReader.Times(j)).(*array.Int64)
+}
+
+func (r *selectedRows) Retain() {
+	for _, arr := range r.cols {
+		if arr != nil {
+			arr.Retain()
+		}
+	}
+}
+
+func (r *selectedRows) Release() {
+	for _, arr := range r.cols {
+		if arr != nil {
+			arr.Release()
+		}
+	}
+}
+
+type unaryVectorEvaluator struct {
+	op   ast.OperatorKind
+	node vectorEvaluator
+}
+
+func (e *unaryVectorEvaluator) Type() semantic.MonoType {
+	if e.op == ast.ExistsOperator {
+		return semantic.BasicBool
+	}
+	return e.node.Type()
+}
+
+func (e *unaryVectorEvaluator) EvalVector(ctx context.Context, cr flux.ColReader, mem memory.Allocator) (array.Interface, error) {
+	v, err := e.node.EvalVector(ctx, cr, mem)
+	if err != nil {
+		return nil, err
+	}
+	if e.op == ast.AdditionOperator {
+		// Do nothing.
+		return v, nil
+	}
+	defer v.Release()
+
+	switch e.op {
+	case ast.ExistsOperator:
+		n := v.Len()
+		b := array.NewBooleanBuilder(mem)
+		b.Resize(n)
+		for i := 0; i < n; i++ {
+			b.Append(v.IsValid(i))
+		}
+		return b.NewBooleanArray(), nil
+	case ast.NotOperator:
+		arr := v.(*array.Boolean)
+		n := arr.Len()
+		b := array.NewBooleanBuilder(mem)
+		b.Resize(n)
+		for i := 0; i < n; i++ {
+			if arr.IsNull(i) {
+				b.AppendNull()
+				continue
+			}
+			b.Append(!arr.Value(i))
+		}
+		return b.NewBooleanArray(), nil
+	case ast.SubtractionOperator:
+		switch arr := v.(type) {
+		case *array.Int64:
+			return vectorNegateInts(arr, mem), nil
+		case *array.Float64:
+			return vectorNegateFloats(arr, mem), nil
+		}
+	}
+	return nil, errors.Newf(codes.Internal, "unsupported unary operator %v for %v", e.op, e.node.Type())
+}
@@ -0,0 +1,329 @@
+package compiler
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	stdarrow "github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/semantic"
+)
+
+// newArray builds an array of the nature from the values, nil is a null.
+func newArray(t *testing.T, nature semantic.Nature, vs ...interface{}) array.Interface {
+	t.Helper()
+	var b array.Builder
+	switch nature {
+	case semantic.Int, semantic.Time:
+		b = array.NewInt64Builder(memory.DefaultAllocator)
+	case semantic.UInt:
+		b = array.NewUint64Builder(memory.DefaultAllocator)
+	case semantic.Float:
+		b = array.NewFloat64Builder(memory.DefaultAllocator)
+	case semantic.String:
+		b = array.NewBinaryBuilder(memory.DefaultAllocator, stdarrow.BinaryTypes.String)
+	case semantic.Bool:
+		b = array.NewBooleanBuilder(memory.DefaultAllocator)
+	default:
+		t.Fatalf("unsupported nature %v", nature)
+	}
+	for _, v := range vs {
+		if v == nil {
+			b.AppendNull()
+			continue
+		}
+		switch b := b.(type) {
+		case *array.Int64Builder:
+			b.Append(v.(int64))
+		case *array.Uint64Builder:
+			b.Append(v.(uint64))
+		case *array.Float64Builder:
+			b.Append(v.(float64))
+		case *array.BinaryBuilder:
+			b.AppendString(v.(string))
+		case *array.BooleanBuilder:
+			b.Append(v.(bool))
+		}
+	}
+	return b.NewArray()
+}
+
+// arrayValues returns the values of the array, nil is a null.
+func arrayValues(arr array.Interface) []interface{} {
+	vs := make([]interface{}, arr.Len())
+	for i := range vs {
+		if arr.IsNull(i) {
+			continue
+		}
+		switch arr := arr.(type) {
+		case *array.Int64:
+			vs[i] = arr.Value(i)
+		case *array.Uint64:
+			vs[i] = arr.Value(i)
+		case *array.Float64:
+			vs[i] = arr.Value(i)
+		case *array.Binary:
+			vs[i] = arr.ValueString(i)
+		case *array.Boolean:
+			vs[i] = arr.Value(i)
+		}
+	}
+	return vs
+}
+
+func TestVectorBinaryKernels(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		operator ast.OperatorKind
+		nature   semantic.Nature
+		l, r     []interface{}
+		want     []interface{}
+		wantErr  string
+	}{
+		{
+			name:     "add ints",
+			operator: ast.AdditionOperator,
+			nature:   semantic.Int,
+			l:        []interface{}{int64(1), int64(-2), nil},
+			r:        []interface{}{int64(2), int64(5), int64(3)},
+			want:     []interface{}{int64(3), int64(3), nil},
+		},
+		{
+			name:     "subtract uints",
+			operator: ast.SubtractionOperator,
+			nature:   semantic.UInt,
+			l:        []interface{}{uint64(5), nil},
+			r:        []interface{}{uint64(2), uint64(1)},
+			want:     []interface{}{uint64(3), nil},
+		},
+		{
+			name:     "divide ints",
+			operator: ast.DivisionOperator,
+			nature:   semantic.Int,
+			l:        []interface{}{int64(7), int64(-7)},
+			r:        []interface{}{int64(2), int64(2)},
+			want:     []interface{}{int64(3), int64(-3)},
+		},
+		{
+			name:     "divide ints by zero",
+			operator: ast.DivisionOperator,
+			nature:   semantic.Int,
+			l:        []interface{}{int64(1), int64(2)},
+			r:        []interface{}{int64(1), int64(0)},
+			wantErr:  "cannot divide by zero",
+		},
+		{
+			name:     "divide by a null zero",
+			operator: ast.DivisionOperator,
+			nature:   semantic.Float,
+			l:        []interface{}{nil, 3.0},
+			r:        []interface{}{0.0, 2.0},
+			want:     []interface{}{nil, 1.5},
+		},
+		{
+			name:     "modulo floats",
+			operator: ast.ModuloOperator,
+			nature:   semantic.Float,
+			l:        []interface{}{5.5, -5.5},
+			r:        []interface{}{2.0, 2.0},
+			want:     []interface{}{math.Mod(5.5, 2), math.Mod(-5.5, 2)},
+		},
+		{
+			name:     "modulo uints by zero",
+			operator: ast.ModuloOperator,
+			nature:   semantic.UInt,
+			l:        []interface{}{uint64(1)},
+			r:        []interface{}{uint64(0)},
+			wantErr:  "cannot mod zero",
+		},
+		{
+			name:     "less than strings",
+			operator: ast.LessThanOperator,
+			nature:   semantic.String,
+			l:        []interface{}{"a", "b", nil},
+			r:        []interface{}{"b", "a", "c"},
+			want:     []interface{}{true, false, nil},
+		},
+		{
+			name:     "not equal bools",
+			operator: ast.NotEqualOperator,
+			nature:   semantic.Bool,
+			l:        []interface{}{true, true, false},
+			r:        []interface{}{true, false, nil},
+			want:     []interface{}{false, true, nil},
+		},
+		{
+			name:     "greater than equal times",
+			operator: ast.GreaterThanEqualOperator,
+			nature:   semantic.Time,
+			l:        []interface{}{int64(1), int64(2), int64(3)},
+			r:        []interface{}{int64(2), int64(2), int64(2)},
+			want:     []interface{}{false, true, true},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			kernel, ok := vectorBinaryKernels[vectorKernelSignature{Operator: tc.operator, Nature: tc.nature}]
+			if !ok {
+				t.Fatalf("missing kernel for %v %v", tc.operator, tc.nature)
+			}
+			l, r := newArray(t, tc.nature, tc.l...), newArray(t, tc.nature, tc.r...)
+			defer l.Release()
+			defer r.Release()
+
+			mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+			defer mem.AssertSize(t, 0)
+			got, err := kernel.fn(l, r, mem)
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Fatalf("unexpected error: want %q, got %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer got.Release()
+			if !cmp.Equal(tc.want, arrayValues(got)) {
+				t.Errorf("unexpected values -want/+got:\n%s", cmp.Diff(tc.want, arrayValues(got)))
+			}
+		})
+	}
+}
+
+func TestVectorNegate(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	ints := newArray(t, semantic.Int, int64(1), nil, int64(-3)).(*array.Int64)
+	defer ints.Release()
+	gotInts := vectorNegateInts(ints, mem)
+	defer gotInts.Release()
+	if want, got := []interface{}{int64(-1), nil, int64(3)}, arrayValues(gotInts); !cmp.Equal(want, got) {
+		t.Errorf("unexpected ints -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	floats := newArray(t, semantic.Float, 1.5, nil).(*array.Float64)
+	defer floats.Release()
+	gotFloats := vectorNegateFloats(floats, mem)
+	defer gotFloats.Release()
+	if want, got := []interface{}{-1.5, nil}, arrayValues(gotFloats); !cmp.Equal(want, got) {
+		t.Errorf("unexpected floats -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+// positiveEvaluator reports whether the int column j is positive
+// and records the rows of every column reader it evaluates.
+type positiveEvaluator struct {
+	j    int
+	rows [][]interface{}
+}
+
+func (e *positiveEvaluator) Type() semantic.MonoType {
+	return semantic.BasicBool
+}
+
+func (e *positiveEvaluator) EvalVector(ctx context.Context, cr flux.ColReader, mem memory.Allocator) (array.Interface, error) {
+	vs := cr.Ints(e.j)
+	e.rows = append(e.rows, arrayValues(vs))
+	b := array.NewBooleanBuilder(mem)
+	for i := 0; i < vs.Len(); i++ {
+		if vs.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		if vs.Value(i) == 0 {
+			b.Release()
+			return nil, errors.New(codes.FailedPrecondition, "zero")
+		}
+		b.Append(vs.Value(i) > 0)
+	}
+	return b.NewArray(), nil
+}
+
+func TestLogicalVectorEvaluator(t *testing.T) {
+	cols := []flux.ColMeta{
+		{Label: "ok", Type: flux.TBool},
+		{Label: "n", Type: flux.TInt},
+	}
+	for _, tc := range []struct {
+		name     string
+		operator ast.LogicalOperatorKind
+		ok       []interface{}
+		n        []interface{}
+		want     []interface{}
+		wantRows [][]interface{}
+	}{
+		{
+			name:     "and",
+			operator: ast.AndOperator,
+			ok:       []interface{}{true, false, nil, true, true},
+			n:        []interface{}{int64(1), int64(0), int64(0), int64(-1), nil},
+			want:     []interface{}{true, false, false, false, nil},
+			wantRows: [][]interface{}{{int64(1), int64(-1), nil}},
+		},
+		{
+			name:     "or",
+			operator: ast.OrOperator,
+			ok:       []interface{}{true, false, nil, true},
+			n:        []interface{}{int64(0), int64(1), int64(-1), int64(0)},
+			want:     []interface{}{true, true, false, true},
+			wantRows: [][]interface{}{{int64(1), int64(-1)}},
+		},
+		{
+			name:     "left decides every row",
+			operator: ast.AndOperator,
+			ok:       []interface{}{false, nil},
+			n:        []interface{}{int64(0), int64(0)},
+			want:     []interface{}{false, false},
+		},
+		{
+			name:     "right decides every row",
+			operator: ast.OrOperator,
+			ok:       []interface{}{false, nil},
+			n:        []interface{}{int64(1), nil},
+			want:     []interface{}{true, nil},
+			wantRows: [][]interface{}{{int64(1), nil}},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cr := &arrow.TableBuffer{
+				Columns: cols,
+				Values: []array.Interface{
+					newArray(t, semantic.Bool, tc.ok...),
+					newArray(t, semantic.Int, tc.n...),
+				},
+			}
+			defer cr.Release()
+
+			right := &positiveEvaluator{j: 1}
+			e := &logicalVectorEvaluator{
+				operator: tc.operator,
+				left:     &columnVectorEvaluator{t: semantic.BasicBool, j: 0},
+				right:    right,
+			}
+			mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+			defer mem.AssertSize(t, 0)
+			got, err := e.EvalVector(context.Background(), cr, mem)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer got.Release()
+			if !cmp.Equal(tc.want, arrayValues(got)) {
+				t.Errorf("unexpected values -want/+got:\n%s", cmp.Diff(tc.want, arrayValues(got)))
+			}
+			if !cmp.Equal(tc.wantRows, right.rows) {
+				t.Errorf("unexpected rows for the right side -want/+got:\n%s", cmp.Diff(tc.wantRows, right.rows))
+			}
+		})
+	}
+}
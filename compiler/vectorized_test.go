@@ -0,0 +1,221 @@
+package compiler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/compiler"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+func TestCompileVector(t *testing.T) {
+	cols := []flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+		{Label: "n", Type: flux.TInt},
+		{Label: "m", Type: flux.TInt},
+		{Label: "host", Type: flux.TString},
+		{Label: "ok", Type: flux.TBool},
+	}
+	data := [][]interface{}{
+		{execute.Time(1), 2.0, int64(4), int64(2), "a", true},
+		{execute.Time(2), nil, int64(-3), int64(5), "b", false},
+		{execute.Time(3), 0.5, nil, int64(1), nil, nil},
+		{execute.Time(4), 1.5, int64(7), nil, "a", true},
+	}
+
+	testCases := []struct {
+		name          string
+		fn            string
+		unimplemented bool
+		wantErr       bool
+	}{
+		{
+			name: "member",
+			fn:   `(r) => r._value`,
+		},
+		{
+			name: "arithmetic",
+			fn:   `(r) => r._value * 2.0 + 1.0`,
+		},
+		{
+			name: "integer arithmetic",
+			fn:   `(r) => r.n - r.m * 3 % 2`,
+		},
+		{
+			name:    "divide by zero",
+			fn:      `(r) => r.n / (r.m - r.m)`,
+			wantErr: true,
+		},
+		{
+			name: "comparison",
+			fn:   `(r) => r._value > 1.0`,
+		},
+		{
+			name: "time comparison",
+			fn:   `(r) => r._time >= 1970-01-01T00:00:00.000000002Z`,
+		},
+		{
+			name: "string equality",
+			fn:   `(r) => r.host == "a"`,
+		},
+		{
+			name: "logical",
+			fn:   `(r) => r.host == "a" and r._value > 1.0 or not r.ok`,
+		},
+		{
+			name: "and short circuit",
+			fn:   `(r) => r.m != 1 and r.n % (r.m - 1) == 0`,
+		},
+		{
+			name: "or short circuit",
+			fn:   `(r) => r.m == 1 or r.n / (r.m - 1) > 0`,
+		},
+		{
+			name: "unary",
+			fn:   `(r) => -r.n`,
+		},
+		{
+			name: "exists",
+			fn:   `(r) => exists r._value`,
+		},
+		{
+			name: "record",
+			fn:   `(r) => ({_time: r._time, _value: r._value / 2.0})`,
+		},
+		{
+			name: "record with",
+			fn:   `(r) => ({r with _value: r._value * 10.0, doubled: r.n * 2})`,
+		},
+		{
+			name:          "function call",
+			fn:            `(r) => string(v: r.n)`,
+			unimplemented: true,
+		},
+		{
+			name:          "mixed types",
+			fn:            `(r) => float(v: r.n) + r._value`,
+			unimplemented: true,
+		},
+		{
+			name:          "missing column",
+			fn:            `(r) => r.missing == 1`,
+			unimplemented: true,
+		},
+		{
+			name: "block",
+			fn: `(r) => { x = r.n
+				return x + 1 }`,
+			unimplemented: true,
+		},
+	}
+
+	properties := make([]semantic.PropertyType, len(cols))
+	for i, c := range cols {
+		properties[i] = semantic.PropertyType{
+			Key:   []byte(c.Label),
+			Value: flux.SemanticType(c.Type),
+		}
+	}
+	recordType := semantic.NewObjectType(properties)
+	inType := semantic.NewObjectType([]semantic.PropertyType{
+		{Key: []byte("r"), Value: recordType},
+	})
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			pkg, err := runtime.AnalyzeSource(tc.fn)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			stmt := pkg.Files[0].Body[0].(*semantic.ExpressionStatement)
+			fn := stmt.Expression.(*semantic.FunctionExpression)
+			vf, err := compiler.CompileVector(nil, fn, cols)
+			if err != nil {
+				if !tc.unimplemented {
+					t.Fatalf("unexpected error: %s", err)
+				} else if got, want := errors.Code(err), codes.Unimplemented; got != want {
+					t.Fatalf("unexpected error code -want/+got:\n\t- %s\n\t+ %s", want, got)
+				}
+				return
+			} else if tc.unimplemented {
+				t.Fatal("expected error")
+			}
+
+			rf, err := compiler.Compile(nil, fn, inType)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			tbl := &executetest.Table{
+				ColMeta: cols,
+				Data:    data,
+			}
+			mem := &memory.Allocator{}
+			if err := tbl.Do(func(cr flux.ColReader) error {
+				vec, err := vf.Eval(context.Background(), cr, mem)
+				if err != nil {
+					if !tc.wantErr {
+						t.Fatalf("unexpected error: %s", err)
+					}
+					return nil
+				} else if tc.wantErr {
+					t.Fatal("expected error")
+				}
+				defer vec.Release()
+
+				if got, want := vec.Len(), cr.Len(); got != want {
+					t.Fatalf("unexpected length -want/+got:\n\t- %d\n\t+ %d", want, got)
+				}
+				for i := 0; i < cr.Len(); i++ {
+					record := values.NewObject(recordType)
+					for j, c := range cols {
+						record.Set(c.Label, execute.ValueForRow(cr, i, j))
+					}
+					args := values.NewObject(inType)
+					args.Set("r", record)
+
+					v, err := rf.Eval(context.Background(), args)
+					if err != nil {
+						t.Fatalf("unexpected error: %s", err)
+					}
+					want, got := valuesForVector(v), valuesForVector(vec.Value(i))
+					if !cmp.Equal(want, got, CmpOptions...) {
+						t.Errorf("unexpected value for row %d -want/+got:\n%s", i, cmp.Diff(want, got, CmpOptions...))
+					}
+				}
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := mem.Allocated(); got != 0 {
+				t.Errorf("expected all memory to be released, %d bytes are still allocated", got)
+			}
+		})
+	}
+}
+
+// valuesForVector returns the values of a vector row so they can be compared
+// without depending on the order of the properties in a record type.
+func valuesForVector(v values.Value) interface{} {
+	if v.IsNull() || v.Type().Nature() != semantic.Object {
+		return v
+	}
+	m := make(map[string]values.Value)
+	v.Object().Range(func(k string, v values.Value) {
+		m[k] = v
+	})
+	return m
+}
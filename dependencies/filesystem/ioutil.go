@@ -1,6 +1,11 @@
 package filesystem
 
-import "io/ioutil"
+import (
+	"io/ioutil"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
 
 // ReadFile will open the file from the service and read
 // the entire contents.
@@ -12,3 +17,23 @@ func ReadFile(fs Service, filename string) ([]byte, error) {
 	defer func() { _ = f.Close() }()
 	return ioutil.ReadAll(f)
 }
+
+// Remove will remove the file from the service.
+// The service must implement the Remover interface.
+func Remove(fs Service, fpath string) error {
+	r, ok := fs.(Remover)
+	if !ok {
+		return errors.New(codes.Unimplemented, "filesystem service does not support removing files")
+	}
+	return r.Remove(fpath)
+}
+
+// TempDir returns the directory for temporary files of the service
+// and reports false if the service does not implement TempDirer.
+func TempDir(fs Service) (string, bool) {
+	t, ok := fs.(TempDirer)
+	if !ok {
+		return "", false
+	}
+	return t.TempDir(), true
+}
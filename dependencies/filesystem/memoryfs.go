@@ -0,0 +1,172 @@
+package filesystem
+
+import (
+	"io"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// MemoryFS is a Service that keeps its files in memory.
+// It is meant to be used in tests.
+type MemoryFS struct {
+	mu    sync.RWMutex
+	files map[string]memoryFileData
+}
+
+type memoryFileData struct {
+	data    []byte
+	modTime time.Time
+}
+
+// NewMemoryFS creates an empty MemoryFS.
+func NewMemoryFS() *MemoryFS {
+	return &MemoryFS{files: make(map[string]memoryFileData)}
+}
+
+// WriteFile stores a file with the data.
+func (fs *MemoryFS) WriteFile(fpath string, data []byte) {
+	cp := make([]byte, len(data))
+	copy(cp, data)
+	fs.mu.Lock()
+	fs.files[path.Clean(fpath)] = memoryFileData{data: cp, modTime: time.Now()}
+	fs.mu.Unlock()
+}
+
+func (fs *MemoryFS) Open(fpath string) (File, error) {
+	fpath = path.Clean(fpath)
+	fs.mu.RLock()
+	fd, ok := fs.files[fpath]
+	fs.mu.RUnlock()
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: fpath, Err: os.ErrNotExist}
+	}
+	return &memoryFile{
+		name:    fpath,
+		data:    fd.data,
+		modTime: fd.modTime,
+	}, nil
+}
+
+// Create creates a file that is stored when it is closed.
+func (fs *MemoryFS) Create(fpath string) (File, error) {
+	return &memoryFile{
+		fs:      fs,
+		name:    path.Clean(fpath),
+		modTime: time.Now(),
+	}, nil
+}
+
+func (fs *MemoryFS) Stat(fpath string) (os.FileInfo, error) {
+	fpath = path.Clean(fpath)
+	fs.mu.RLock()
+	fd, ok := fs.files[fpath]
+	fs.mu.RUnlock()
+	if !ok {
+		return nil, &os.PathError{Op: "stat", Path: fpath, Err: os.ErrNotExist}
+	}
+	return memoryFileInfo{name: path.Base(fpath), size: int64(len(fd.data)), modTime: fd.modTime}, nil
+}
+
+func (fs *MemoryFS) Remove(fpath string) error {
+	fpath = path.Clean(fpath)
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if _, ok := fs.files[fpath]; !ok {
+		return &os.PathError{Op: "remove", Path: fpath, Err: os.ErrNotExist}
+	}
+	delete(fs.files, fpath)
+	return nil
+}
+
+// memoryFile is a file of a MemoryFS. Files that were
+// created have an fs and are stored in it when they are closed.
+type memoryFile struct {
+	fs      *MemoryFS
+	name    string
+	data    []byte
+	offset  int64
+	modTime time.Time
+	closed  bool
+}
+
+func (f *memoryFile) Read(p []byte) (int, error) {
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	if f.offset >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[f.offset:])
+	f.offset += int64(n)
+	return n, nil
+}
+
+func (f *memoryFile) Write(p []byte) (int, error) {
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	if f.fs == nil {
+		return 0, errors.Newf(codes.PermissionDenied, "file %q is not open for writing", f.name)
+	}
+	if end := f.offset + int64(len(p)); end > int64(len(f.data)) {
+		data := make([]byte, end)
+		copy(data, f.data)
+		f.data = data
+	}
+	n := copy(f.data[f.offset:], p)
+	f.offset += int64(n)
+	return n, nil
+}
+
+func (f *memoryFile) Seek(offset int64, whence int) (int64, error) {
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += int64(len(f.data))
+	default:
+		return 0, errors.Newf(codes.Invalid, "invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, errors.New(codes.Invalid, "negative offset")
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *memoryFile) Stat() (os.FileInfo, error) {
+	return memoryFileInfo{name: path.Base(f.name), size: int64(len(f.data)), modTime: f.modTime}, nil
+}
+
+func (f *memoryFile) Close() error {
+	if f.closed {
+		return os.ErrClosed
+	}
+	f.closed = true
+	if f.fs != nil {
+		f.fs.WriteFile(f.name, f.data)
+	}
+	return nil
+}
+
+type memoryFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func (fi memoryFileInfo) Name() string       { return fi.name }
+func (fi memoryFileInfo) Size() int64        { return fi.size }
+func (fi memoryFileInfo) Mode() os.FileMode  { return 0644 }
+func (fi memoryFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi memoryFileInfo) IsDir() bool        { return false }
+func (fi memoryFileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,59 @@
+package filesystem_test
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/influxdata/flux/dependencies/filesystem"
+)
+
+func TestMemoryFS(t *testing.T) {
+	fs := filesystem.NewMemoryFS()
+	if _, err := fs.Open("/hello.txt"); !os.IsNotExist(err) {
+		t.Fatalf("expected not exist error, got: %v", err)
+	}
+
+	f, err := fs.Create("/hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(f, "Hello, World!"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Seek(7, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(f, "Flux!!"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := filesystem.ReadFile(fs, "/hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "Hello, Flux!!"; got != want {
+		t.Fatalf("unexpected file contents -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+
+	fi, err := fs.Stat("/hello.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fi.Name(), "hello.txt"; got != want {
+		t.Errorf("unexpected name -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+	if got, want := fi.Size(), int64(len("Hello, Flux!!")); got != want {
+		t.Errorf("unexpected size -want/+got:\n\t- %d\n\t+ %d", want, got)
+	}
+
+	if err := filesystem.Remove(fs, "/hello.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Stat("/hello.txt"); !os.IsNotExist(err) {
+		t.Fatalf("expected not exist error, got: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package filesystem
+
+import (
+	"os"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// ReadOnly wraps a Service so that files can be read but not created or removed.
+func ReadOnly(fs Service) Service {
+	return readOnlyFS{fs: fs}
+}
+
+type readOnlyFS struct {
+	fs Service
+}
+
+func (fs readOnlyFS) Open(fpath string) (File, error) {
+	f, err := fs.fs.Open(fpath)
+	if err != nil {
+		return nil, err
+	}
+	return readOnlyFile{File: f}, nil
+}
+
+func (fs readOnlyFS) Create(fpath string) (File, error) {
+	return nil, errReadOnly(fpath)
+}
+
+func (fs readOnlyFS) Stat(fpath string) (os.FileInfo, error) {
+	return fs.fs.Stat(fpath)
+}
+
+func (fs readOnlyFS) Remove(fpath string) error {
+	return errReadOnly(fpath)
+}
+
+// readOnlyFile rejects writes to a file in case
+// the wrapped service opened it for writing.
+type readOnlyFile struct {
+	File
+}
+
+func (f readOnlyFile) Write(p []byte) (int, error) {
+	return 0, errors.New(codes.PermissionDenied, "filesystem is read-only")
+}
+
+func errReadOnly(fpath string) error {
+	return errors.Newf(codes.PermissionDenied, "cannot modify %q: filesystem is read-only", fpath)
+}
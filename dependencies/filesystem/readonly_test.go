@@ -0,0 +1,37 @@
+package filesystem_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+func TestReadOnly(t *testing.T) {
+	mfs := filesystem.NewMemoryFS()
+	mfs.WriteFile("/data/a.csv", []byte("Hello, World!"))
+	fs := filesystem.ReadOnly(mfs)
+
+	data, err := filesystem.ReadFile(fs, "/data/a.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "Hello, World!"; got != want {
+		t.Fatalf("unexpected file contents -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+	if _, err := fs.Stat("/data/a.csv"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fs.Create("/data/b.csv"); errors.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected permission denied, got: %v", err)
+	}
+	if err := filesystem.Remove(fs, "/data/a.csv"); errors.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected permission denied, got: %v", err)
+	}
+	if _, err := mfs.Stat("/data/b.csv"); !os.IsNotExist(err) {
+		t.Errorf("expected file not to be created, got: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// NewRootFS creates a Service that only gives access to the files
+// inside of the root directory. Every path is relative to the root,
+// including absolute paths, and paths that escape the root with ".."
+// or with a symbolic link to a file outside of the root are rejected.
+func NewRootFS(root string) (Service, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	root, err = filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return nil, errors.Newf(codes.Invalid, "filesystem root %q is not a directory", root)
+	}
+	return rootFS{root: root}, nil
+}
+
+type rootFS struct {
+	root string
+}
+
+func (fs rootFS) Open(fpath string) (File, error) {
+	p, err := fs.resolve(fpath)
+	if err != nil {
+		return nil, err
+	}
+	return SystemFS.Open(p)
+}
+
+func (fs rootFS) Create(fpath string) (File, error) {
+	p, err := fs.resolve(fpath)
+	if err != nil {
+		return nil, err
+	}
+	return SystemFS.Create(p)
+}
+
+func (fs rootFS) Stat(fpath string) (os.FileInfo, error) {
+	p, err := fs.resolve(fpath)
+	if err != nil {
+		return nil, err
+	}
+	return SystemFS.Stat(p)
+}
+
+func (fs rootFS) Remove(fpath string) error {
+	p, err := fs.resolve(fpath)
+	if err != nil {
+		return err
+	}
+	return Remove(SystemFS, p)
+}
+
+// TempDir returns the root itself since the
+// temporary files must be inside of the root too.
+func (fs rootFS) TempDir() string {
+	return "/"
+}
+
+// maxLinks is the number of symbolic links that resolve
+// follows before it decides that the links form a loop.
+const maxLinks = 255
+
+// resolve returns the path of the file on the system
+// after it has checked that the file is inside of the root.
+func (fs rootFS) resolve(fpath string) (string, error) {
+	rel := strings.TrimLeft(filepath.FromSlash(fpath), string(filepath.Separator))
+	rel = filepath.Clean(rel)
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fs.outside(fpath)
+	}
+
+	// Walk the path one component at a time and check the target of
+	// every symbolic link before it is followed. The target is checked
+	// even when it does not exist, so a dangling link cannot be used
+	// to create a file outside of the root.
+	real, rest := fs.root, splitPath(rel)
+	for links := 0; len(rest) > 0; {
+		next := filepath.Join(real, rest[0])
+		fi, err := os.Lstat(next)
+		if os.IsNotExist(err) {
+			// Nothing below a missing directory can be a link.
+			return filepath.Join(append([]string{next}, rest[1:]...)...), nil
+		} else if err != nil {
+			return "", err
+		}
+		if fi.Mode()&os.ModeSymlink == 0 {
+			real, rest = next, rest[1:]
+			continue
+		}
+
+		if links++; links > maxLinks {
+			return "", errors.Newf(codes.Invalid, "too many symbolic links in path %q", fpath)
+		}
+		target, err := os.Readlink(next)
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(real, target)
+		}
+		target = filepath.Clean(target)
+		if !fs.contains(target) {
+			return "", fs.outside(fpath)
+		}
+		trel, err := filepath.Rel(fs.root, target)
+		if err != nil {
+			return "", err
+		}
+		real, rest = fs.root, append(splitPath(trel), rest[1:]...)
+	}
+	return real, nil
+}
+
+// contains reports if the cleaned absolute path is inside of the root.
+func (fs rootFS) contains(p string) bool {
+	return p == fs.root || strings.HasPrefix(p, strings.TrimSuffix(fs.root, string(filepath.Separator))+string(filepath.Separator))
+}
+
+// splitPath splits a cleaned relative path into its components.
+func splitPath(rel string) []string {
+	if rel == "." {
+		return nil
+	}
+	return strings.Split(rel, string(filepath.Separator))
+}
+
+func (fs rootFS) outside(fpath string) error {
+	return errors.Newf(codes.PermissionDenied, "path %q is outside of the filesystem root", fpath)
+}
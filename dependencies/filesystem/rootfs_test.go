@@ -0,0 +1,169 @@
+package filesystem_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+func TestRootFS(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "flux-rootfs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(tmpdir) }()
+
+	root := filepath.Join(tmpdir, "root")
+	if err := os.MkdirAll(filepath.Join(root, "data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "data", "a.csv"), []byte("inside"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(tmpdir, "secret.txt"), []byte("outside"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(tmpdir, "secret.txt"), filepath.Join(root, "escape.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(tmpdir, filepath.Join(root, "parent")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "data", "a.csv"), filepath.Join(root, "link.csv")); err != nil {
+		t.Fatal(err)
+	}
+
+	fs, err := filesystem.NewRootFS(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fpath := range []string{"data/a.csv", "/data/a.csv", "data/../data/a.csv", "link.csv"} {
+		data, err := filesystem.ReadFile(fs, fpath)
+		if err != nil {
+			t.Fatalf("unexpected error reading %q: %v", fpath, err)
+		}
+		if got, want := string(data), "inside"; got != want {
+			t.Fatalf("unexpected file contents of %q -want/+got:\n\t- %q\n\t+ %q", fpath, want, got)
+		}
+	}
+
+	for _, fpath := range []string{"../secret.txt", "/../secret.txt", "data/../../secret.txt", "escape.txt", "parent/secret.txt", "parent/new.txt"} {
+		if _, err := fs.Open(fpath); errors.Code(err) != codes.PermissionDenied {
+			t.Errorf("expected permission denied opening %q, got: %v", fpath, err)
+		}
+		if _, err := fs.Create(fpath); errors.Code(err) != codes.PermissionDenied {
+			t.Errorf("expected permission denied creating %q, got: %v", fpath, err)
+		}
+	}
+
+	f, err := fs.Create("data/b.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(f, "created"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(root, "data", "b.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "created"; got != want {
+		t.Fatalf("unexpected file contents -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+	if err := filesystem.Remove(fs, "data/b.csv"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Stat("data/b.csv"); !os.IsNotExist(err) {
+		t.Fatalf("expected file to be removed, got: %v", err)
+	}
+}
+
+func TestRootFS_DanglingLink(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "flux-rootfs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(tmpdir) }()
+
+	root := filepath.Join(tmpdir, "root")
+	if err := os.Mkdir(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(tmpdir, "outside.txt"), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../missing", filepath.Join(root, "dir")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("data/new.csv", filepath.Join(root, "inside")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(root, "data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	fs, err := filesystem.NewRootFS(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fpath := range []string{"link", "dir/new.txt"} {
+		if _, err := fs.Create(fpath); errors.Code(err) != codes.PermissionDenied {
+			t.Errorf("expected permission denied creating %q, got: %v", fpath, err)
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(tmpdir, "outside.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected no file outside of the root, got: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(tmpdir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("expected no directory outside of the root, got: %v", err)
+	}
+
+	f, err := fs.Create("inside")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "data", "new.csv")); err != nil {
+		t.Fatalf("expected the file to be created inside of the root: %v", err)
+	}
+}
+
+func TestRootFS_TempDir(t *testing.T) {
+	root, err := ioutil.TempDir("", "flux-rootfs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(root) }()
+
+	fs, err := filesystem.NewRootFS(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, ok := filesystem.TempDir(fs)
+	if !ok {
+		t.Fatal("expected the root filesystem to have a directory for temporary files")
+	}
+	f, err := fs.Create(filepath.Join(dir, "spill"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "spill")); err != nil {
+		t.Fatalf("expected the temporary file to be inside of the root: %v", err)
+	}
+}
@@ -18,3 +18,15 @@ type Service interface {
 	Create(fpath string) (File, error)
 	Stat(fpath string) (os.FileInfo, error)
 }
+
+// Remover is implemented by a Service that can remove files.
+type Remover interface {
+	Remove(fpath string) error
+}
+
+// TempDirer is implemented by a Service that has a directory
+// for temporary files, such as the files that buffered data
+// is spilled to when a query goes over its memory limit.
+type TempDirer interface {
+	TempDir() string
+}
@@ -27,3 +27,11 @@ func (systemFS) Create(fpath string) (File, error) {
 func (systemFS) Stat(fpath string) (os.FileInfo, error) {
 	return os.Stat(fpath)
 }
+
+func (systemFS) Remove(fpath string) error {
+	return os.Remove(fpath)
+}
+
+func (systemFS) TempDir() string {
+	return os.TempDir()
+}
@@ -102,6 +102,28 @@ func TestSystemFS_Stat(t *testing.T) {
 	}
 }
 
+func TestSystemFS_Remove(t *testing.T) {
+	tmpfile, err := ioutil.TempFile("", "flux-systemfs-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(tmpfile.Name()) }()
+	defer func() { _ = tmpfile.Close() }()
+
+	if err := tmpfile.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := filesystem.SystemFS
+	if err := filesystem.Remove(fs, tmpfile.Name()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(tmpfile.Name()); !os.IsNotExist(err) {
+		t.Fatalf("expected file to be removed, got error: %v", err)
+	}
+}
+
 func TestReadFile(t *testing.T) {
 	tmpfile, err := ioutil.TempFile("", "flux-systemfs-test")
 	if err != nil {
@@ -1,10 +1,13 @@
 package http
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net"
 	"net/http"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/influxdata/flux/dependencies/url"
@@ -61,29 +64,96 @@ func checkRedirect(validator url.Validator) func(req *http.Request, via []*http.
 			return errors.New("stopped after 10 redirects")
 		}
 
-		return validator.Validate(req.URL)
+		return url.ForFunction(validator, url.FunctionFromContext(req.Context())).Validate(req.URL)
+	}
+}
+
+// dialContext validates the address of every connection when the validator
+// implements url.DialValidator. The address is checked after the host has been
+// resolved so that the DNS server cannot change it once the URL was validated.
+func dialContext(dialer *net.Dialer, validator url.Validator) func(ctx context.Context, network, address string) (net.Conn, error) {
+	v, ok := validator.(url.DialValidator)
+	if !ok {
+		return dialer.DialContext
+	}
+	d := *dialer
+	d.Control = func(network, address string, _ syscall.RawConn) error {
+		return v.ValidateDial(network, address)
+	}
+	return d.DialContext
+}
+
+// newTransport creates a transport that dials the connections
+// validated by the validator.
+func newTransport(validator url.Validator) *http.Transport {
+	// These defaults are copied from http.DefaultTransport.
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: dialContext(&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			// DualStack is deprecated
+		}, validator),
+		MaxIdleConns:          100,
+		IdleConnTimeout:       10 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		// Fields below are NOT part of Go's defaults
+		MaxIdleConnsPerHost: 100,
+	}
+}
+
+// functionTransport sends each request with the transport of the function
+// recorded with url.WithFunction in the request context. Every function has
+// its own pool of connections so that a connection dialed with the rules of
+// one function is never reused by a function with other rules.
+type functionTransport struct {
+	validator url.Validator
+
+	mu         sync.Mutex
+	transports map[string]*http.Transport
+}
+
+func (t *functionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.transport(url.FunctionFromContext(req.Context())).RoundTrip(req)
+}
+
+func (t *functionTransport) transport(name string) *http.Transport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tr, ok := t.transports[name]
+	if !ok {
+		tr = newTransport(url.ForFunction(t.validator, name))
+		t.transports[name] = tr
+	}
+	return tr
+}
+
+// CloseIdleConnections closes the idle connections of every function.
+func (t *functionTransport) CloseIdleConnections() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, tr := range t.transports {
+		tr.CloseIdleConnections()
 	}
 }
 
 // NewDefaultClient creates a client with sane defaults.
+// When the validator has rules for each function, the connections
+// are pooled by the function that sends the requests.
 func NewDefaultClient(urlValidator url.Validator) *http.Client {
-	// These defaults are copied from http.DefaultTransport.
+	var transport http.RoundTripper
+	if _, ok := urlValidator.(url.FunctionValidator); ok {
+		transport = &functionTransport{
+			validator:  urlValidator,
+			transports: make(map[string]*http.Transport),
+		}
+	} else {
+		transport = newTransport(urlValidator)
+	}
 	return &http.Client{
 		CheckRedirect: checkRedirect(urlValidator),
-		Transport: &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-				// DualStack is deprecated
-			}).DialContext,
-			MaxIdleConns:          100,
-			IdleConnTimeout:       10 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-			// Fields below are NOT part of Go's defaults
-			MaxIdleConnsPerHost: 100,
-		},
+		Transport:     transport,
 	}
 }
 
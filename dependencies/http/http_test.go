@@ -88,3 +88,71 @@ func TestInvalidRedirects(t *testing.T) {
 
 	})
 }
+
+func TestDialValidation(t *testing.T) {
+	validator, err := depsUrl.NewPolicyValidator(depsUrl.Policy{
+		Rules: depsUrl.Rules{
+			DenyNetworks: []string{"127.0.0.0/8", "::1/128"},
+		},
+		Functions: map[string]depsUrl.Rules{
+			"http.post": {},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewDefaultClient(validator)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	t.Run("denied address is rejected when dialed", func(t *testing.T) {
+		// The request is sent without validating its url first,
+		// like a host name that resolves to a different address
+		// after it has been validated.
+		req, err := http.NewRequest("GET", ts.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = client.Do(req)
+		if err == nil {
+			t.Fatal("Client did not error")
+		}
+		if !strings.Contains(err.Error(), "it connects to the denied address 127.0.0.1") {
+			t.Fatal(err)
+		}
+	})
+	t.Run("rules of the function are used", func(t *testing.T) {
+		req, err := http.NewRequest("GET", ts.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = req.WithContext(depsUrl.WithFunction(req.Context(), "http.post"))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("unexpected status code: %d", resp.StatusCode)
+		}
+	})
+	t.Run("connections are not shared between functions", func(t *testing.T) {
+		// The connection of the previous request is idle and
+		// would be reused if the functions shared a pool.
+		req, err := http.NewRequest("GET", ts.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req = req.WithContext(depsUrl.WithFunction(req.Context(), "http.get"))
+		_, err = client.Do(req)
+		if err == nil {
+			t.Fatal("Client did not error")
+		}
+		if !strings.Contains(err.Error(), "it connects to the denied address 127.0.0.1") {
+			t.Fatal(err)
+		}
+	})
+}
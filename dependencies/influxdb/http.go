@@ -18,9 +18,11 @@ import (
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/csv"
 	"github.com/influxdata/flux/dependencies/http"
+	fluxurl "github.com/influxdata/flux/dependencies/url"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values"
+	protocol "github.com/influxdata/line-protocol"
 )
 
 // HttpProvider is an implementation of the Provider that
@@ -32,8 +34,16 @@ type HttpProvider struct {
 
 var _ Provider = HttpProvider{}
 
+// The names of the functions that send the requests
+// of each method, whose rules validate the host.
+const (
+	fromFunction        = "influxdb.from"
+	cardinalityFunction = "influxdb.cardinality"
+	toFunction          = "influxdb.to"
+)
+
 func (h HttpProvider) ReaderFor(ctx context.Context, conf Config, bounds flux.Bounds, predicateSet PredicateSet) (Reader, error) {
-	c, err := h.clientFor(ctx, conf)
+	c, err := h.clientFor(ctx, conf, fromFunction)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +64,7 @@ func (h HttpProvider) SeriesCardinalityReaderFor(ctx context.Context, conf Confi
 	}
 
 	// Retrieve the client and create the http reader.
-	c, err := h.clientFor(ctx, conf)
+	c, err := h.clientFor(ctx, conf, cardinalityFunction)
 	if err != nil {
 		return nil, err
 	}
@@ -65,7 +75,66 @@ func (h HttpProvider) SeriesCardinalityReaderFor(ctx context.Context, conf Confi
 	}, nil
 }
 
-func (h HttpProvider) clientFor(ctx context.Context, conf Config) (*HttpClient, error) {
+var _ AggregateProvider = HttpProvider{}
+
+func (h HttpProvider) WindowAggregateReaderFor(ctx context.Context, conf Config, bounds flux.Bounds, predicateSet PredicateSet, window Window, aggregate Aggregate) (Reader, error) {
+	if !aggregate.IsValid() {
+		return nil, errors.Newf(codes.Unimplemented, "aggregate %q is not supported by the influxdb window aggregate reader", aggregate)
+	} else if !window.Every.IsPositive() || !window.Period.IsPositive() {
+		return nil, errors.New(codes.Invalid, "window every and period must be positive")
+	}
+
+	c, err := h.clientFor(ctx, conf, fromFunction)
+	if err != nil {
+		return nil, err
+	}
+	return windowAggregateHttpReader{
+		filteredHttpReader: filteredHttpReader{
+			HttpClient:   c,
+			Bounds:       bounds,
+			PredicateSet: predicateSet,
+		},
+		Window:    window,
+		Aggregate: aggregate,
+	}, nil
+}
+
+func (h HttpProvider) GroupAggregateReaderFor(ctx context.Context, conf Config, bounds flux.Bounds, predicateSet PredicateSet, group Group, aggregate Aggregate) (Reader, error) {
+	if !aggregate.IsValid() {
+		return nil, errors.Newf(codes.Unimplemented, "aggregate %q is not supported by the influxdb group aggregate reader", aggregate)
+	} else if group.Mode != flux.GroupModeBy && group.Mode != flux.GroupModeExcept {
+		return nil, errors.New(codes.Invalid, "group mode must be one of by or except")
+	}
+
+	c, err := h.clientFor(ctx, conf, fromFunction)
+	if err != nil {
+		return nil, err
+	}
+	return groupAggregateHttpReader{
+		filteredHttpReader: filteredHttpReader{
+			HttpClient:   c,
+			Bounds:       bounds,
+			PredicateSet: predicateSet,
+		},
+		Group:     group,
+		Aggregate: aggregate,
+	}, nil
+}
+
+var _ WriterProvider = HttpProvider{}
+
+func (h HttpProvider) WriterFor(ctx context.Context, conf Config) (Writer, error) {
+	c, err := h.clientFor(ctx, conf, toFunction)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Config.Bucket.IsValid() {
+		return nil, errors.New(codes.Invalid, "influxdb writer requires one of bucket or bucketID to be specified")
+	}
+	return newHttpWriter(ctx, c, DefaultWriteBatchSize), nil
+}
+
+func (h HttpProvider) clientFor(ctx context.Context, conf Config, function string) (*HttpClient, error) {
 	deps := flux.GetDependencies(ctx)
 	httpc, err := deps.HTTPClient()
 	if err != nil {
@@ -81,19 +150,20 @@ func (h HttpProvider) clientFor(ctx context.Context, conf Config) (*HttpClient,
 	if conf.Host == "" {
 		conf.Host = h.DefaultConfig.Host
 	}
-	if err := h.validateHost(deps, conf.Host); err != nil {
+	if err := h.validateHost(deps, conf.Host, function); err != nil {
 		return nil, err
 	}
 	if conf.Token == "" {
 		conf.Token = h.DefaultConfig.Token
 	}
 	return &HttpClient{
-		Client: httpc,
-		Config: conf,
+		Client:   httpc,
+		Config:   conf,
+		Function: function,
 	}, nil
 }
 
-func (h HttpProvider) validateHost(deps flux.Dependencies, host string) error {
+func (h HttpProvider) validateHost(deps flux.Dependencies, host, function string) error {
 	if host == "" {
 		return errors.New(codes.Invalid, "influxdb provider requires a host to be specified")
 	}
@@ -107,13 +177,16 @@ func (h HttpProvider) validateHost(deps flux.Dependencies, host string) error {
 	if err != nil {
 		return err
 	}
-	return validator.Validate(u)
+	return fluxurl.ForFunction(validator, function).Validate(u)
 }
 
 // HttpClient is an http client for reading from an influxdb instance.
 type HttpClient struct {
 	Client http.Client
 	Config Config
+	// Function is the name of the Flux function that sends the requests.
+	// The addresses dialed for them are validated with its rules.
+	Function string
 }
 
 // Query will create a new http.Request, send it to the server, then
@@ -138,6 +211,60 @@ func (h *HttpClient) Query(ctx context.Context, f func(table flux.Table) error,
 	return h.processResult(resp.Body, f, mem)
 }
 
+// Write will send the line protocol in the body to the write
+// endpoint of the influxdb instance.
+func (h *HttpClient) Write(ctx context.Context, body []byte) error {
+	u, err := url.Parse(h.Config.Host)
+	if err != nil {
+		return err
+	}
+	u.Path += "/api/v2/write"
+
+	params := make(url.Values)
+	if org := h.Config.Org; org.ID != "" {
+		params.Set("orgID", org.ID)
+	} else if org.Name != "" {
+		params.Set("org", org.Name)
+	}
+	if bucket := h.Config.Bucket; bucket.ID != "" {
+		params.Set("bucketID", bucket.ID)
+	} else {
+		params.Set("bucket", bucket.Name)
+	}
+	params.Set("precision", "ns")
+	u.RawQuery = params.Encode()
+
+	req, err := stdhttp.NewRequest("POST", u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if token := h.Config.Token; token != "" {
+		req.Header.Set("Authorization", "Token "+token)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	resp, err := h.Client.Do(req.WithContext(fluxurl.WithFunction(ctx, h.Function)))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		return nil
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Newf(codes.Invalid, "error when reading response body: %s", err)
+	}
+	if err := h.parseError(data); err != nil {
+		if _, ok := err.(*json.SyntaxError); !ok {
+			return err
+		}
+	}
+	return errors.Newf(codes.Unknown, "influxdb write failed with status %s", resp.Status)
+}
+
 // newFile constructs a new ast.File with the default values filled in.
 func (h *HttpClient) newFile(imports map[string]*ast.ImportDeclaration) ast.File {
 	file := ast.File{
@@ -228,7 +355,7 @@ func (h *HttpClient) newRequest(ctx context.Context, file *ast.File, now time.Ti
 		req.Header.Set("Authorization", "Token "+token)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	return req.WithContext(ctx), nil
+	return req.WithContext(fluxurl.WithFunction(ctx, h.Function)), nil
 }
 
 // newRequestBody will produce a new request body for the http client
@@ -327,6 +454,13 @@ type filteredHttpReader struct {
 
 func (h filteredHttpReader) Read(ctx context.Context, f func(flux.Table) error, mem memory.Allocator) error {
 	imports := make(map[string]*ast.ImportDeclaration)
+	query := h.query(imports)
+	return h.read(ctx, f, imports, query, mem)
+}
+
+// query will construct the expression that reads and filters
+// the data from the bucket.
+func (h filteredHttpReader) query(imports map[string]*ast.ImportDeclaration) ast.Expression {
 	query := &ast.PipeExpression{
 		Argument: &ast.CallExpression{
 			Callee: &ast.Identifier{Name: "from"},
@@ -356,19 +490,14 @@ func (h filteredHttpReader) Read(ctx context.Context, f func(flux.Table) error,
 				Value: ast.StringLiteralFromValue("keep"),
 			})
 		}
-		query = &ast.PipeExpression{
-			Argument: query,
-			Call: &ast.CallExpression{
-				Callee: &ast.Identifier{Name: "filter"},
-				Arguments: []ast.Expression{
-					&ast.ObjectExpression{
-						Properties: params,
-					},
-				},
-			},
-		}
+		query = pipeCall(query, "filter", params)
 	}
+	return query
+}
 
+// read will send the query to the influxdb instance
+// and pass each table in the result to the function.
+func (h filteredHttpReader) read(ctx context.Context, f func(flux.Table) error, imports map[string]*ast.ImportDeclaration, query ast.Expression, mem memory.Allocator) error {
 	file := h.newFile(imports)
 	file.Body = []ast.Statement{
 		&ast.ExpressionStatement{Expression: query},
@@ -376,6 +505,110 @@ func (h filteredHttpReader) Read(ctx context.Context, f func(flux.Table) error,
 	return h.Query(ctx, f, &file, h.Bounds.Now, mem)
 }
 
+type windowAggregateHttpReader struct {
+	filteredHttpReader
+	Window    Window
+	Aggregate Aggregate
+}
+
+func (h windowAggregateHttpReader) Read(ctx context.Context, f func(flux.Table) error, mem memory.Allocator) error {
+	imports := make(map[string]*ast.ImportDeclaration)
+	properties := []*ast.Property{
+		{
+			Key:   &ast.Identifier{Name: "every"},
+			Value: durationToAST(h.Window.Every),
+		},
+		{
+			Key:   &ast.Identifier{Name: "period"},
+			Value: durationToAST(h.Window.Period),
+		},
+	}
+	if !h.Window.Offset.IsZero() {
+		properties = append(properties, &ast.Property{
+			Key:   &ast.Identifier{Name: "offset"},
+			Value: durationToAST(h.Window.Offset),
+		})
+	}
+	if h.Window.CreateEmpty {
+		properties = append(properties, &ast.Property{
+			Key:   &ast.Identifier{Name: "createEmpty"},
+			Value: ast.BooleanLiteralFromValue(true),
+		})
+	}
+	query := pipeCall(h.query(imports), "window", properties)
+	query = pipeCall(query, string(h.Aggregate), nil)
+	return h.read(ctx, f, imports, query, mem)
+}
+
+type groupAggregateHttpReader struct {
+	filteredHttpReader
+	Group     Group
+	Aggregate Aggregate
+}
+
+func (h groupAggregateHttpReader) Read(ctx context.Context, f func(flux.Table) error, mem memory.Allocator) error {
+	imports := make(map[string]*ast.ImportDeclaration)
+	columns := &ast.ArrayExpression{
+		Elements: make([]ast.Expression, len(h.Group.Columns)),
+	}
+	for i, col := range h.Group.Columns {
+		columns.Elements[i] = ast.StringLiteralFromValue(col)
+	}
+	mode := "by"
+	if h.Group.Mode == flux.GroupModeExcept {
+		mode = "except"
+	}
+	query := pipeCall(h.query(imports), "group", []*ast.Property{
+		{
+			Key:   &ast.Identifier{Name: "columns"},
+			Value: columns,
+		},
+		{
+			Key:   &ast.Identifier{Name: "mode"},
+			Value: ast.StringLiteralFromValue(mode),
+		},
+	})
+	query = pipeCall(query, string(h.Aggregate), nil)
+	return h.read(ctx, f, imports, query, mem)
+}
+
+// pipeCall will pipe the argument into a call of the named function
+// with the properties as its parameters.
+func pipeCall(argument ast.Expression, name string, properties []*ast.Property) *ast.PipeExpression {
+	call := &ast.CallExpression{
+		Callee: &ast.Identifier{Name: name},
+	}
+	if len(properties) > 0 {
+		call.Arguments = []ast.Expression{
+			&ast.ObjectExpression{
+				Properties: properties,
+			},
+		}
+	}
+	return &ast.PipeExpression{
+		Argument: argument,
+		Call:     call,
+	}
+}
+
+// durationToAST will convert a duration into a duration literal
+// that is negated when the duration is negative.
+func durationToAST(d flux.Duration) ast.Expression {
+	if d.IsZero() {
+		return &ast.DurationLiteral{
+			Values: []ast.Duration{{Magnitude: 0, Unit: ast.SecondUnit}},
+		}
+	}
+	var lit ast.Expression = &ast.DurationLiteral{Values: d.AsValues()}
+	if d.IsNegative() {
+		lit = &ast.UnaryExpression{
+			Operator: ast.SubtractionOperator,
+			Argument: lit,
+		}
+	}
+	return lit
+}
+
 type seriesCardinalityHttpReader struct {
 	*HttpClient
 	Bounds       flux.Bounds
@@ -441,3 +674,64 @@ func (h seriesCardinalityHttpReader) Read(ctx context.Context, f func(flux.Table
 	}
 	return h.Query(ctx, f, &file, h.Bounds.Now, mem)
 }
+
+// DefaultWriteBatchSize is the maximum number of lines
+// sent to influxdb with a single write request.
+const DefaultWriteBatchSize = 5000
+
+// httpWriter encodes metrics as line protocol and sends
+// them to the write endpoint in batches.
+type httpWriter struct {
+	*HttpClient
+	ctx       context.Context
+	buf       bytes.Buffer
+	enc       *protocol.Encoder
+	lines     int
+	batchSize int
+}
+
+func newHttpWriter(ctx context.Context, c *HttpClient, batchSize int) *httpWriter {
+	w := &httpWriter{
+		HttpClient: c,
+		ctx:        ctx,
+		batchSize:  batchSize,
+	}
+	w.enc = protocol.NewEncoder(&w.buf)
+	w.enc.FailOnFieldErr(true)
+	w.enc.SetFieldSortOrder(protocol.SortFields)
+	w.enc.SetFieldTypeSupport(protocol.UintSupport)
+	return w
+}
+
+func (h *httpWriter) Write(m protocol.Metric) error {
+	if _, err := h.enc.Encode(m); err != nil {
+		return errors.Wrap(err, codes.Invalid, "failed to encode metric as line protocol")
+	}
+	h.lines++
+	if h.lines >= h.batchSize {
+		return h.flush()
+	}
+	return nil
+}
+
+func (h *httpWriter) Flush() error {
+	return h.flush()
+}
+
+func (h *httpWriter) Close() error {
+	h.buf.Reset()
+	h.lines = 0
+	return nil
+}
+
+// flush sends the buffered lines to influxdb.
+func (h *httpWriter) flush() error {
+	if h.lines == 0 {
+		return nil
+	}
+	defer func() {
+		h.buf.Reset()
+		h.lines = 0
+	}()
+	return h.HttpClient.Write(h.ctx, h.buf.Bytes())
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/interpreter"
+	protocol "github.com/influxdata/line-protocol"
 )
 
 type key int
@@ -75,6 +76,53 @@ func (ps PredicateSet) Copy() PredicateSet {
 	return nps
 }
 
+// Aggregate is the name of an aggregate or selector function
+// that is applied by the influxdb instance.
+type Aggregate string
+
+const (
+	MeanAggregate  Aggregate = "mean"
+	SumAggregate   Aggregate = "sum"
+	CountAggregate Aggregate = "count"
+	MinAggregate   Aggregate = "min"
+	MaxAggregate   Aggregate = "max"
+	FirstAggregate Aggregate = "first"
+	LastAggregate  Aggregate = "last"
+)
+
+// IsValid will return true if the aggregate is one
+// of the known aggregates.
+func (a Aggregate) IsValid() bool {
+	switch a {
+	case MeanAggregate, SumAggregate, CountAggregate, MinAggregate, MaxAggregate, FirstAggregate, LastAggregate:
+		return true
+	default:
+		return false
+	}
+}
+
+// Window defines the windows that the points of
+// each series are grouped into before aggregating.
+type Window struct {
+	Every       flux.Duration
+	Period      flux.Duration
+	Offset      flux.Duration
+	CreateEmpty bool
+}
+
+// Group defines how series are grouped together before aggregating.
+type Group struct {
+	Mode    flux.GroupMode
+	Columns []string
+}
+
+// Copy produces a deep copy of the Group.
+func (g *Group) Copy() Group {
+	ng := *g
+	ng.Columns = append([]string(nil), g.Columns...)
+	return ng
+}
+
 // Provider is an interface for creating a Reader that will read
 // data from an influxdb instance.
 //
@@ -93,6 +141,49 @@ type Provider interface {
 	SeriesCardinalityReaderFor(ctx context.Context, conf Config, bounds flux.Bounds, predicateSet PredicateSet) (Reader, error)
 }
 
+// AggregateProvider is implemented by a Provider that can also create
+// Readers that apply an aggregate within the influxdb instance.
+type AggregateProvider interface {
+	// WindowAggregateReaderFor will return a Reader that groups the points
+	// of each series into windows and applies the aggregate to each window.
+	WindowAggregateReaderFor(ctx context.Context, conf Config, bounds flux.Bounds, predicateSet PredicateSet, window Window, aggregate Aggregate) (Reader, error)
+
+	// GroupAggregateReaderFor will return a Reader that groups the series
+	// and applies the aggregate to each group.
+	GroupAggregateReaderFor(ctx context.Context, conf Config, bounds flux.Bounds, predicateSet PredicateSet, group Group, aggregate Aggregate) (Reader, error)
+}
+
+// GetAggregateProvider will return the Provider for the current context
+// if it is also an AggregateProvider. It returns an error with the code
+// codes.Unimplemented otherwise.
+func GetAggregateProvider(ctx context.Context) (AggregateProvider, error) {
+	ap, ok := GetProvider(ctx).(AggregateProvider)
+	if !ok {
+		return nil, errors.New(codes.Unimplemented, "influxdb aggregate reader has not been implemented")
+	}
+	return ap, nil
+}
+
+// WriterProvider is implemented by a Provider that can also create
+// a Writer that will write data to an influxdb instance.
+type WriterProvider interface {
+	// WriterFor will construct a Writer using the given configuration parameters.
+	// If the parameters are their zero values, appropriate defaults may be used
+	// or an error may be returned if the implementation does not have a default.
+	WriterFor(ctx context.Context, conf Config) (Writer, error)
+}
+
+// GetWriterProvider will return the Provider for the current context
+// if it is also a WriterProvider. It returns an error with the code
+// codes.Unimplemented otherwise.
+func GetWriterProvider(ctx context.Context) (WriterProvider, error) {
+	wp, ok := GetProvider(ctx).(WriterProvider)
+	if !ok {
+		return nil, errors.New(codes.Unimplemented, "influxdb writer has not been implemented")
+	}
+	return wp, nil
+}
+
 // Reader reads tables from an influxdb instance.
 type Reader interface {
 	// Read will produce flux.Table values using the memory.Allocator
@@ -100,6 +191,21 @@ type Reader interface {
 	Read(ctx context.Context, f func(flux.Table) error, mem memory.Allocator) error
 }
 
+// Writer writes metrics to an influxdb instance.
+type Writer interface {
+	// Write will write the metric to the influxdb instance.
+	// A Writer may buffer metrics before writing them, but the
+	// metric may be reused by the caller once Write returns.
+	Write(m protocol.Metric) error
+
+	// Flush will write any buffered metrics.
+	Flush() error
+
+	// Close will release the resources held by the Writer.
+	// Metrics that were not flushed are discarded.
+	Close() error
+}
+
 // UnimplementedProvider provides default implementations for a Provider.
 // This implements all of the Provider methods by returning an error
 // with the code codes.Unimplemented.
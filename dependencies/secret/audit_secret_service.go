@@ -0,0 +1,30 @@
+package secret
+
+import (
+	"context"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"go.uber.org/zap"
+)
+
+func (ass AuditSecretService) LoadSecret(ctx context.Context, k string) (string, error) {
+	v, err := ass.Service.LoadSecret(ctx, k)
+	// Only the key is logged, never the value of the secret.
+	switch {
+	case err == nil:
+		ass.Logger.Info("Loaded secret", zap.String("key", k))
+	case errors.Code(err) == codes.NotFound:
+		ass.Logger.Info("Secret not found", zap.String("key", k))
+	default:
+		ass.Logger.Warn("Failed to load secret", zap.String("key", k), zap.Error(err))
+	}
+	return v, err
+}
+
+// Secret service that logs the key of every secret
+// that is loaded through the service it wraps.
+type AuditSecretService struct {
+	Service Service
+	Logger  *zap.Logger
+}
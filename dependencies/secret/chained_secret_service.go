@@ -0,0 +1,26 @@
+package secret
+
+import (
+	"context"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+func (css ChainedSecretService) LoadSecret(ctx context.Context, k string) (string, error) {
+	for _, s := range css {
+		v, err := s.LoadSecret(ctx, k)
+		if err == nil {
+			return v, nil
+		}
+		if errors.Code(err) != codes.NotFound {
+			return "", err
+		}
+	}
+	return "", errors.Newf(codes.NotFound, "secret key %q not found", k)
+}
+
+// Secret service that tries each of its services in order
+// and returns the first secret that is found.
+// Any error other than a not found error stops the lookup.
+type ChainedSecretService []Service
@@ -0,0 +1,37 @@
+package secret
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+func (dss DirectorySecretService) LoadSecret(ctx context.Context, k string) (string, error) {
+	// Keys name a file directly inside the directory,
+	// so they cannot be used to read any other file.
+	if k == "" || k == "." || k == ".." || strings.ContainsAny(k, `/\`) {
+		return "", errors.Newf(codes.Invalid, "invalid secret key %q", k)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dss.Dir, k))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", errors.Newf(codes.NotFound, "secret key %q not found", k)
+		}
+		return "", errors.Wrapf(err, codes.Internal, "failed to read secret key %q", k)
+	}
+	// Editors and tools such as kubectl commonly
+	// leave a trailing newline at the end of the file.
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// Secret service that reads each secret from the file named
+// by its key inside of a directory, like Docker and Kubernetes
+// mount their secrets.
+type DirectorySecretService struct {
+	Dir string
+}
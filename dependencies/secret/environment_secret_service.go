@@ -3,12 +3,23 @@ package secret
 import (
 	"context"
 	"os"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
 )
 
 func (ess EnvironmentSecretService) LoadSecret(ctx context.Context, k string) (string, error) {
-	return os.Getenv(k), nil
+	v, ok := os.LookupEnv(k)
+	if !ok && ess.ReportMissing {
+		return "", errors.Newf(codes.NotFound, "secret key %q not found", k)
+	}
+	return v, nil
 }
 
 // Secret service that retrieve the system environment variables.
 type EnvironmentSecretService struct {
+	// ReportMissing makes a missing variable a not found error
+	// instead of an empty secret, so that a ChainedSecretService
+	// continues with its next service.
+	ReportMissing bool
 }
@@ -0,0 +1,148 @@
+package secret
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"golang.org/x/crypto/scrypt"
+	"gopkg.in/yaml.v2"
+)
+
+// The key of a keystore is derived from its passphrase with scrypt
+// using the parameters recommended for interactive logins.
+const (
+	keystoreVersion = 1
+	keystoreKDF     = "scrypt"
+	scryptN         = 1 << 15
+	scryptR         = 8
+	scryptP         = 1
+	keystoreKeyLen  = 32
+	keystoreSaltLen = 16
+)
+
+// keystore is the document stored in a keystore file.
+// The ciphertext is the AES-256-GCM encryption of a JSON object
+// that maps each secret key to its value.
+type keystore struct {
+	Version    int    `json:"version" yaml:"version"`
+	KDF        string `json:"kdf" yaml:"kdf"`
+	Salt       string `json:"salt" yaml:"salt"`
+	Nonce      string `json:"nonce" yaml:"nonce"`
+	Ciphertext string `json:"ciphertext" yaml:"ciphertext"`
+}
+
+func (kss *KeystoreSecretService) LoadSecret(ctx context.Context, k string) (string, error) {
+	v, ok := kss.secrets[k]
+	if !ok {
+		return "", errors.Newf(codes.NotFound, "secret key %q not found", k)
+	}
+	return v, nil
+}
+
+// Secret service that reads secrets from an encrypted keystore.
+// The keystore is decrypted once when the service is created.
+type KeystoreSecretService struct {
+	secrets map[string]string
+}
+
+// OpenKeystore reads the keystore file at path and unlocks it with the passphrase.
+func OpenKeystore(path, passphrase string) (*KeystoreSecretService, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, codes.Invalid, "failed to read keystore %q", path)
+	}
+	return NewKeystoreSecretService(data, passphrase)
+}
+
+// NewKeystoreSecretService unlocks a keystore with the passphrase.
+// The keystore may be either a JSON or a YAML document.
+func NewKeystoreSecretService(data []byte, passphrase string) (*KeystoreSecretService, error) {
+	// YAML is a superset of JSON so both formats are read the same way.
+	var ks keystore
+	if err := yaml.Unmarshal(data, &ks); err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "invalid keystore")
+	}
+	if ks.Version != keystoreVersion {
+		return nil, errors.Newf(codes.Invalid, "unsupported keystore version %d", ks.Version)
+	}
+	if ks.KDF != keystoreKDF {
+		return nil, errors.Newf(codes.Invalid, "unsupported keystore kdf %q", ks.KDF)
+	}
+	salt, err := base64.StdEncoding.DecodeString(ks.Salt)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "invalid keystore salt")
+	}
+	nonce, err := base64.StdEncoding.DecodeString(ks.Nonce)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "invalid keystore nonce")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(ks.Ciphertext)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "invalid keystore ciphertext")
+	}
+
+	aead, err := newKeystoreCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New(codes.Invalid, "invalid keystore nonce")
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New(codes.Unauthenticated, "failed to unlock keystore: wrong passphrase or corrupted keystore")
+	}
+	var secrets map[string]string
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "invalid keystore secrets")
+	}
+	return &KeystoreSecretService{secrets: secrets}, nil
+}
+
+// EncryptKeystore encrypts the secrets with the passphrase
+// and returns the keystore as a JSON document.
+func EncryptKeystore(secrets map[string]string, passphrase string) ([]byte, error) {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, keystoreSaltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	aead, err := newKeystoreCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(keystore{
+		Version:    keystoreVersion,
+		KDF:        keystoreKDF,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, plaintext, nil)),
+	}, "", "  ")
+}
+
+func newKeystoreCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keystoreKeyLen)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Internal, "failed to derive keystore key")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Internal, "failed to create keystore cipher")
+	}
+	return cipher.NewGCM(block)
+}
@@ -1,10 +1,21 @@
 package secret_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/secret"
+	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/mock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestSecret_Service(t *testing.T) {
@@ -21,3 +32,153 @@ func TestSecret_Service(t *testing.T) {
 		t.Error("secret service should have errored on key lookup")
 	}
 }
+
+func TestEnvironmentSecretService(t *testing.T) {
+	if err := os.Setenv("FLUX_TEST_SECRET", "val"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("FLUX_TEST_SECRET")
+
+	ss := secret.EnvironmentSecretService{}
+	val, err := ss.LoadSecret(context.Background(), "FLUX_TEST_SECRET")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != "val" {
+		t.Errorf("unexpected secret want: %q got: %q", "val", val)
+	}
+	if val, err := ss.LoadSecret(context.Background(), "FLUX_TEST_SECRET_MISSING"); err != nil || val != "" {
+		t.Errorf("expected an empty secret, got: %q, %v", val, err)
+	}
+
+	ss = secret.EnvironmentSecretService{ReportMissing: true}
+	if _, err := ss.LoadSecret(context.Background(), "FLUX_TEST_SECRET_MISSING"); errors.Code(err) != codes.NotFound {
+		t.Errorf("expected not found error, got: %v", err)
+	}
+}
+
+func TestDirectorySecretService(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "token"), []byte("s3cr3t\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ss := secret.DirectorySecretService{Dir: dir}
+	val, err := ss.LoadSecret(context.Background(), "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != "s3cr3t" {
+		t.Errorf("unexpected secret want: %q got: %q", "s3cr3t", val)
+	}
+	if _, err := ss.LoadSecret(context.Background(), "missing"); errors.Code(err) != codes.NotFound {
+		t.Errorf("expected not found error, got: %v", err)
+	}
+	for _, k := range []string{"", "..", "../token", "sub/token"} {
+		if _, err := ss.LoadSecret(context.Background(), k); errors.Code(err) != codes.Invalid {
+			t.Errorf("expected invalid error for key %q, got: %v", k, err)
+		}
+	}
+}
+
+func TestKeystoreSecretService(t *testing.T) {
+	data, err := secret.EncryptKeystore(map[string]string{"token": "s3cr3t"}, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The same keystore written as YAML.
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	var yamlData bytes.Buffer
+	for _, k := range []string{"version", "kdf", "salt", "nonce", "ciphertext"} {
+		fmt.Fprintf(&yamlData, "%s: %v\n", k, doc[k])
+	}
+
+	for name, data := range map[string][]byte{"json": data, "yaml": yamlData.Bytes()} {
+		data := data
+		t.Run(name, func(t *testing.T) {
+			ss, err := secret.NewKeystoreSecretService(data, "passphrase")
+			if err != nil {
+				t.Fatal(err)
+			}
+			val, err := ss.LoadSecret(context.Background(), "token")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if val != "s3cr3t" {
+				t.Errorf("unexpected secret want: %q got: %q", "s3cr3t", val)
+			}
+			if _, err := ss.LoadSecret(context.Background(), "missing"); errors.Code(err) != codes.NotFound {
+				t.Errorf("expected not found error, got: %v", err)
+			}
+		})
+	}
+
+	if _, err := secret.NewKeystoreSecretService(data, "wrong"); errors.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected unauthenticated error, got: %v", err)
+	}
+}
+
+func TestChainedSecretService(t *testing.T) {
+	ss := secret.ChainedSecretService{
+		mock.SecretService{"a": "first"},
+		mock.SecretService{"a": "second", "b": "second"},
+	}
+	for k, want := range map[string]string{"a": "first", "b": "second"} {
+		val, err := ss.LoadSecret(context.Background(), k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if val != want {
+			t.Errorf("unexpected secret for key %q want: %q got: %q", k, want, val)
+		}
+	}
+	if _, err := ss.LoadSecret(context.Background(), "c"); errors.Code(err) != codes.NotFound {
+		t.Errorf("expected not found error, got: %v", err)
+	}
+
+	// Errors other than not found stop the lookup.
+	ss = secret.ChainedSecretService{
+		secret.DirectorySecretService{},
+		mock.SecretService{"../a": "val"},
+	}
+	if _, err := ss.LoadSecret(context.Background(), "../a"); errors.Code(err) != codes.Invalid {
+		t.Errorf("expected invalid error, got: %v", err)
+	}
+}
+
+func TestAuditSecretService(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	ss := secret.AuditSecretService{
+		Service: mock.SecretService{"key": "val"},
+		Logger:  zap.New(core),
+	}
+	if _, err := ss.LoadSecret(context.Background(), "key"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ss.LoadSecret(context.Background(), "missing"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("unexpected number of log entries want: 2 got: %d", len(entries))
+	}
+	for i, want := range []string{"key", "missing"} {
+		if got := entries[i].ContextMap()["key"]; got != want {
+			t.Errorf("unexpected key in log entry %d want: %q got: %q", i, want, got)
+		}
+		for _, f := range entries[i].Context {
+			if f.String == "val" {
+				t.Errorf("log entry %d contains the secret value", i)
+			}
+		}
+	}
+}
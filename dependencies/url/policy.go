@@ -0,0 +1,300 @@
+package url
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// FunctionValidator is a Validator whose rules depend
+// on the function that uses the URL.
+type FunctionValidator interface {
+	Validator
+	// ForFunction returns the validator for the URLs
+	// used by the named function, such as "sql.from".
+	ForFunction(name string) Validator
+}
+
+// ForFunction returns the validator for the URLs used by the named function.
+// Validators that do not implement FunctionValidator are returned unchanged.
+func ForFunction(v Validator, name string) Validator {
+	if fv, ok := v.(FunctionValidator); ok {
+		return fv.ForFunction(name)
+	}
+	return v
+}
+
+// DialValidator is a Validator that also validates the address
+// of each connection when it is dialed. The address has already
+// been resolved so it cannot be changed by the DNS server
+// after the URL was validated.
+type DialValidator interface {
+	Validator
+	ValidateDial(network, address string) error
+}
+
+type functionKey struct{}
+
+// WithFunction returns a context that records the function that
+// makes the requests sent with it. The HTTP dependency uses it
+// to validate the dialed addresses with the rules of that function.
+func WithFunction(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, functionKey{}, name)
+}
+
+// FunctionFromContext returns the function recorded with WithFunction.
+func FunctionFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(functionKey{}).(string)
+	return name
+}
+
+// Rules restricts the URLs that can be used.
+// Deny lists take precedence over allow lists and
+// an empty allow list allows everything.
+type Rules struct {
+	// Schemes is the list of allowed schemes.
+	Schemes []string `json:"schemes,omitempty"`
+	// Ports is the list of allowed ports.
+	Ports []int `json:"ports,omitempty"`
+	// AllowHosts and DenyHosts are lists of host name patterns
+	// such as "*.example.com". The patterns use the syntax of path.Match.
+	AllowHosts []string `json:"allowHosts,omitempty"`
+	DenyHosts  []string `json:"denyHosts,omitempty"`
+	// AllowNetworks and DenyNetworks are lists of CIDR blocks that
+	// the addresses of the host are checked against.
+	AllowNetworks []string `json:"allowNetworks,omitempty"`
+	DenyNetworks  []string `json:"denyNetworks,omitempty"`
+}
+
+// Policy is the configuration of a PolicyValidator.
+type Policy struct {
+	Rules
+	// Functions replaces the rules for the named functions.
+	Functions map[string]Rules `json:"functions,omitempty"`
+}
+
+// PolicyValidator validates URLs with the allow and deny lists of a Policy.
+// Host names are resolved when a URL is validated and the rules for
+// networks and ports are checked again when the connection is dialed.
+type PolicyValidator struct {
+	rules     *rules
+	functions map[string]*rules
+}
+
+// NewPolicyValidator creates a PolicyValidator for the policy.
+func NewPolicyValidator(p Policy) (*PolicyValidator, error) {
+	r, err := compileRules(p.Rules)
+	if err != nil {
+		return nil, err
+	}
+	v := &PolicyValidator{
+		rules:     r,
+		functions: make(map[string]*rules, len(p.Functions)),
+	}
+	for name, fr := range p.Functions {
+		r, err := compileRules(fr)
+		if err != nil {
+			return nil, errors.Wrapf(err, codes.Invalid, "invalid rules for function %q", name)
+		}
+		v.functions[name] = r
+	}
+	return v, nil
+}
+
+func (v *PolicyValidator) Validate(u *url.URL) error {
+	return v.rules.Validate(u)
+}
+
+func (v *PolicyValidator) ValidateDial(network, address string) error {
+	return v.rules.ValidateDial(network, address)
+}
+
+func (v *PolicyValidator) ForFunction(name string) Validator {
+	if r, ok := v.functions[name]; ok {
+		return r
+	}
+	return v.rules
+}
+
+// rules are the compiled Rules. They are the
+// validator for the URLs of a single function.
+type rules struct {
+	schemes       map[string]bool
+	ports         map[int]bool
+	allowHosts    []string
+	denyHosts     []string
+	allowNetworks []*net.IPNet
+	denyNetworks  []*net.IPNet
+}
+
+func compileRules(r Rules) (*rules, error) {
+	c := &rules{}
+	if len(r.Schemes) > 0 {
+		c.schemes = make(map[string]bool, len(r.Schemes))
+		for _, s := range r.Schemes {
+			c.schemes[strings.ToLower(s)] = true
+		}
+	}
+	if len(r.Ports) > 0 {
+		c.ports = make(map[int]bool, len(r.Ports))
+		for _, p := range r.Ports {
+			c.ports[p] = true
+		}
+	}
+	var err error
+	if c.allowHosts, err = compileHosts(r.AllowHosts); err != nil {
+		return nil, err
+	}
+	if c.denyHosts, err = compileHosts(r.DenyHosts); err != nil {
+		return nil, err
+	}
+	if c.allowNetworks, err = compileNetworks(r.AllowNetworks); err != nil {
+		return nil, err
+	}
+	if c.denyNetworks, err = compileNetworks(r.DenyNetworks); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func compileHosts(patterns []string) ([]string, error) {
+	hosts := make([]string, len(patterns))
+	for i, p := range patterns {
+		p = strings.ToLower(p)
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, errors.Newf(codes.Invalid, "invalid host pattern %q", p)
+		}
+		hosts[i] = p
+	}
+	return hosts, nil
+}
+
+func compileNetworks(cidrs []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, block, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Newf(codes.Invalid, "invalid network %q", cidr)
+		}
+		networks[i] = block
+	}
+	return networks, nil
+}
+
+// defaultPorts are the ports used by URLs that do not specify one.
+var defaultPorts = map[string]int{
+	"http":  80,
+	"https": 443,
+	"ws":    80,
+	"wss":   443,
+}
+
+func (r *rules) Validate(u *url.URL) error {
+	scheme := strings.ToLower(u.Scheme)
+	if r.schemes != nil && !r.schemes[scheme] {
+		return errors.Newf(codes.Invalid, "url is not valid, scheme %q is not allowed", u.Scheme)
+	}
+
+	if u.Port() != "" {
+		port, err := strconv.Atoi(u.Port())
+		if err != nil {
+			return errors.Newf(codes.Invalid, "url is not valid, invalid port %q", u.Port())
+		}
+		if err := r.validatePort(port); err != nil {
+			return err
+		}
+	} else if port, ok := defaultPorts[scheme]; ok {
+		if err := r.validatePort(port); err != nil {
+			return err
+		}
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if matchHost(r.denyHosts, host) {
+		return errors.Newf(codes.Invalid, "url is not valid, host %q is denied", host)
+	}
+	if len(r.allowHosts) > 0 && !matchHost(r.allowHosts, host) {
+		return errors.Newf(codes.Invalid, "url is not valid, host %q is not allowed", host)
+	}
+
+	if len(r.allowNetworks) == 0 && len(r.denyNetworks) == 0 {
+		return nil
+	}
+	ips, err := lookupIP(host)
+	if err != nil {
+		return err
+	}
+	for _, ip := range ips {
+		if err := r.validateIP(ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *rules) ValidateDial(network, address string) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Wrapf(err, codes.Invalid, "invalid address %q", address)
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return errors.Newf(codes.Invalid, "invalid port %q", port)
+	}
+	if err := r.validatePort(p); err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return errors.Newf(codes.Invalid, "invalid address %q", address)
+	}
+	return r.validateIP(ip)
+}
+
+func (r *rules) validatePort(port int) error {
+	if r.ports != nil && !r.ports[port] {
+		return errors.Newf(codes.Invalid, "url is not valid, port %d is not allowed", port)
+	}
+	return nil
+}
+
+func (r *rules) validateIP(ip net.IP) error {
+	if containsIP(r.denyNetworks, ip) {
+		return errors.Newf(codes.Invalid, "url is not valid, it connects to the denied address %s", ip)
+	}
+	if len(r.allowNetworks) > 0 && !containsIP(r.allowNetworks, ip) {
+		return errors.Newf(codes.Invalid, "url is not valid, it connects to the address %s that is not allowed", ip)
+	}
+	return nil
+}
+
+func matchHost(patterns []string, host string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, host); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, n := range networks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func lookupIP(host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	return net.LookupIP(host)
+}
@@ -0,0 +1,111 @@
+package url_test
+
+import (
+	nurl "net/url"
+	"testing"
+
+	"github.com/influxdata/flux/dependencies/url"
+)
+
+func TestPolicyValidator(t *testing.T) {
+	v, err := url.NewPolicyValidator(url.Policy{
+		Rules: url.Rules{
+			Schemes:    []string{"http", "https"},
+			Ports:      []int{80, 443, 8086},
+			AllowHosts: []string{"*.example.com", "1.1.1.1"},
+			DenyHosts:  []string{"internal.example.com"},
+		},
+		Functions: map[string]url.Rules{
+			"http.post": {
+				DenyNetworks: []string{"10.0.0.0/8", "127.0.0.0/8"},
+			},
+			"sql.from": {
+				Schemes:       []string{"postgres"},
+				AllowNetworks: []string{"10.0.0.0/8"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		function string
+		url      string
+		valid    bool
+	}{
+		{url: "https://api.example.com", valid: true},
+		{url: "http://api.example.com:8086/query", valid: true},
+		{url: "http://1.1.1.1", valid: true},
+		{url: "ftp://api.example.com", valid: false},
+		{url: "http://api.example.com:9999", valid: false},
+		{url: "http://example.org", valid: false},
+		{url: "http://internal.example.com", valid: false},
+		{url: "http://10.0.0.1", valid: false},
+		{function: "http.post", url: "http://1.1.1.1", valid: true},
+		{function: "http.post", url: "http://10.0.0.1", valid: false},
+		{function: "http.post", url: "http://127.0.0.1:8086", valid: false},
+		{function: "sql.from", url: "postgres://10.0.0.1:5432/db", valid: true},
+		{function: "sql.from", url: "postgres://1.1.1.1:5432/db", valid: false},
+		{function: "sql.from", url: "https://10.0.0.1", valid: false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.function+" "+tc.url, func(t *testing.T) {
+			u, err := nurl.Parse(tc.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = url.ForFunction(v, tc.function).Validate(u)
+			if tc.valid && err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			} else if !tc.valid && err == nil {
+				t.Error("expected validation error got nil")
+			}
+		})
+	}
+}
+
+func TestPolicyValidator_ValidateDial(t *testing.T) {
+	v, err := url.NewPolicyValidator(url.Policy{
+		Rules: url.Rules{
+			Ports:        []int{443},
+			DenyNetworks: []string{"127.0.0.0/8", "::1/128"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		address string
+		valid   bool
+	}{
+		{address: "1.1.1.1:443", valid: true},
+		{address: "1.1.1.1:80", valid: false},
+		{address: "127.0.0.1:443", valid: false},
+		{address: "[::1]:443", valid: false},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.address, func(t *testing.T) {
+			err := v.ValidateDial("tcp", tc.address)
+			if tc.valid && err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			} else if !tc.valid && err == nil {
+				t.Error("expected validation error got nil")
+			}
+		})
+	}
+}
+
+func TestNewPolicyValidator_Invalid(t *testing.T) {
+	for _, p := range []url.Policy{
+		{Rules: url.Rules{AllowNetworks: []string{"10.0.0.0"}}},
+		{Rules: url.Rules{DenyHosts: []string{"[example.com"}}},
+		{Functions: map[string]url.Rules{"sql.from": {DenyNetworks: []string{"nope"}}}},
+	} {
+		if _, err := url.NewPolicyValidator(p); err == nil {
+			t.Errorf("expected error for policy %+v", p)
+		}
+	}
+}
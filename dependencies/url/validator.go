@@ -37,6 +37,18 @@ func (PrivateIPValidator) Validate(u *url.URL) error {
 	return nil
 }
 
+// ValidateDial validates that a connection is not dialed to a private IP.
+func (PrivateIPValidator) ValidateDial(network, address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip != nil && isPrivateIP(ip) {
+		return errors.New(codes.Invalid, "url is not valid, it connects to a private IP")
+	}
+	return nil
+}
+
 // privateIPBlocks is a list of IP ranges that are defined as private.
 var privateIPBlocks []*net.IPNet
 
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/metadata"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -26,9 +28,15 @@ type ScheduleFunc func(ctx context.Context, throughput int)
 
 // poolDispatcher implements Dispatcher using a pool of goroutines.
 type poolDispatcher struct {
+	// scheduled and maxQueueDepth are updated atomically
+	// and are kept first for 64-bit alignment.
+	scheduled     int64
+	maxQueueDepth int64
+
 	work chan ScheduleFunc
 
-	throughput int
+	throughput  int
+	concurrency int
 
 	mu      sync.Mutex
 	closed  bool
@@ -53,11 +61,37 @@ func newPoolDispatcher(throughput int, logger *zap.Logger) *poolDispatcher {
 func (d *poolDispatcher) Schedule(fn ScheduleFunc) {
 	select {
 	case d.work <- fn:
+		atomic.AddInt64(&d.scheduled, 1)
+		d.observeQueueDepth(int64(len(d.work)))
 	case <-d.closing:
 	}
 }
 
+// observeQueueDepth records the number of functions waiting for a worker
+// if it is the largest seen so far.
+func (d *poolDispatcher) observeQueueDepth(depth int64) {
+	for {
+		max := atomic.LoadInt64(&d.maxQueueDepth)
+		if depth <= max || atomic.CompareAndSwapInt64(&d.maxQueueDepth, max, depth) {
+			return
+		}
+	}
+}
+
+// Metadata reports the configuration of the dispatcher
+// and how much work was waiting for its workers.
+func (d *poolDispatcher) Metadata() metadata.Metadata {
+	md := make(metadata.Metadata)
+	md.Add("flux/dispatcher-concurrency", d.concurrency)
+	md.Add("flux/dispatcher-throughput", d.throughput)
+	md.Add("flux/dispatcher-queue-capacity", cap(d.work))
+	md.Add("flux/dispatcher-max-queue-depth", int(atomic.LoadInt64(&d.maxQueueDepth)))
+	md.Add("flux/dispatcher-scheduled", int(atomic.LoadInt64(&d.scheduled)))
+	return md
+}
+
 func (d *poolDispatcher) Start(n int, ctx context.Context) {
+	d.concurrency = n
 	d.wg.Add(n)
 	for i := 0; i < n; i++ {
 		go func() {
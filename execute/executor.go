@@ -79,8 +79,11 @@ func (e *executor) Execute(ctx context.Context, p *plan.Spec, a *memory.Allocato
 }
 
 func validatePlan(p *plan.Spec) error {
-	if p.Resources.ConcurrencyQuota == 0 {
-		return errors.New(codes.Invalid, "plan must have a non-zero concurrency quota")
+	if p.Resources.ConcurrencyQuota <= 0 {
+		return errors.New(codes.Invalid, "plan must have a positive concurrency quota")
+	}
+	if p.Resources.DispatcherThroughput < 0 {
+		return errors.New(codes.Invalid, "plan must not have a negative dispatcher throughput")
 	}
 	return nil
 }
@@ -89,13 +92,16 @@ func (e *executor) createExecutionState(ctx context.Context, p *plan.Spec, a *me
 	if err := validatePlan(p); err != nil {
 		return nil, errors.Wrap(err, codes.Invalid, "invalid plan")
 	}
+	throughput := p.Resources.DispatcherThroughput
+	if throughput == 0 {
+		throughput = plan.DefaultDispatcherThroughput
+	}
 	es := &executionState{
-		p:         p,
-		alloc:     a,
-		resources: p.Resources,
-		results:   make(map[string]flux.Result),
-		// TODO(nathanielc): Have the planner specify the dispatcher throughput
-		dispatcher: newPoolDispatcher(10, e.logger),
+		p:          p,
+		alloc:      a,
+		resources:  p.Resources,
+		results:    make(map[string]flux.Result),
+		dispatcher: newPoolDispatcher(throughput, e.logger),
 	}
 	v := &createExecutionNodeVisitor{
 		ctx:   ctx,
@@ -109,8 +115,8 @@ func (e *executor) createExecutionState(ctx context.Context, p *plan.Spec, a *me
 
 	// Only sources can be a MetadataNode at the moment so allocate enough
 	// space for all of them to report metadata. Not all of them will necessarily
-	// report metadata.
-	es.metaCh = make(chan metadata.Metadata, len(es.sources))
+	// report metadata. The dispatcher always reports its own metadata.
+	es.metaCh = make(chan metadata.Metadata, len(es.sources)+1)
 
 	return v.es, nil
 }
@@ -289,6 +295,9 @@ func (es *executionState) do(ctx context.Context) {
 		}(src)
 	}
 
+	// The metadata channel is closed once the sources
+	// and the dispatcher have reported their metadata.
+	wg.Add(1)
 	go func() {
 		defer close(es.metaCh)
 		wg.Wait()
@@ -296,6 +305,7 @@ func (es *executionState) do(ctx context.Context) {
 
 	es.dispatcher.Start(es.resources.ConcurrencyQuota, ctx)
 	go func() {
+		defer wg.Done()
 		// Wait for all transports to finish
 		for _, t := range es.transports {
 			select {
@@ -313,6 +323,7 @@ func (es *executionState) do(ctx context.Context) {
 		if err != nil {
 			es.abort(err)
 		}
+		es.metaCh <- es.dispatcher.Metadata()
 	}()
 }
 
@@ -14,6 +14,7 @@ import (
 	_ "github.com/influxdata/flux/fluxinit/static"
 	"github.com/influxdata/flux/interpreter"
 	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/metadata"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/plan/plantest"
 	"github.com/influxdata/flux/runtime"
@@ -758,3 +759,68 @@ func TestExecutor_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestExecutor_DispatcherMetadata(t *testing.T) {
+	spec := plantest.CreatePlanSpec(&plantest.PlanSpec{
+		Nodes: []plan.Node{
+			plan.CreatePhysicalNode("from-test", executetest.NewFromProcedureSpec(
+				[]*executetest.Table{{
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{execute.Time(0), 1.0},
+						{execute.Time(1), 2.0},
+					},
+				}},
+			)),
+			plan.CreatePhysicalNode("to-test", &executetest.ToProcedureSpec{}),
+			plan.CreatePhysicalNode("yield", executetest.NewYieldProcedureSpec("_result")),
+		},
+		Edges: [][2]int{
+			{0, 1},
+			{1, 2},
+		},
+		Resources: flux.ResourceManagement{
+			ConcurrencyQuota:     2,
+			MemoryBytesQuota:     math.MaxInt64,
+			DispatcherThroughput: 25,
+		},
+		Now: time.Now(),
+	})
+
+	exe := execute.NewExecutor(zaptest.NewLogger(t))
+	ctx := executetest.NewTestExecuteDependencies().Inject(context.Background())
+	results, metaCh, err := exe.Execute(ctx, spec, executetest.UnlimitedAllocator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if err := r.Tables().Do(func(flux.Table) error { return nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	md := make(metadata.Metadata)
+	for m := range metaCh {
+		md.AddAll(m)
+	}
+	for key, want := range map[string]interface{}{
+		"flux/dispatcher-concurrency": 2,
+		"flux/dispatcher-throughput":  25,
+	} {
+		if got := md[key]; !cmp.Equal([]interface{}{want}, got) {
+			t.Errorf("unexpected %s metadata -want/+got:\n%s", key, cmp.Diff([]interface{}{want}, got))
+		}
+	}
+	for _, key := range []string{
+		"flux/dispatcher-queue-capacity",
+		"flux/dispatcher-max-queue-depth",
+		"flux/dispatcher-scheduled",
+	} {
+		if _, ok := md[key]; !ok {
+			t.Errorf("missing %s metadata", key)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package execute
 import (
 	"context"
 
+	"github.com/apache/arrow/go/arrow/array"
+	arrowmem "github.com/apache/arrow/go/arrow/memory"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/compiler"
@@ -79,8 +81,21 @@ func (f *dynamicFn) prepare(cols []flux.ColMeta, extraTypes map[string]semantic.
 	}, nil
 }
 
+// prepareVector compiles the function into kernels that evaluate
+// a whole column reader at once. It returns nil if the function
+// cannot be vectorized or if it does not return the given nature
+// so the function will be evaluated for each row instead.
+func (f *dynamicFn) prepareVector(cols []flux.ColMeta, nature semantic.Nature) compiler.VectorFunc {
+	vfn, err := compiler.CompileVector(f.scope, f.fn, cols)
+	if err != nil || vfn.Type().Nature() != nature {
+		return nil
+	}
+	return vfn
+}
+
 type preparedFn struct {
 	fn         compiler.Func
+	vfn        compiler.VectorFunc
 	recordName string
 	arg0       values.Object
 	args       values.Object
@@ -91,6 +106,12 @@ func (f *preparedFn) returnType() semantic.MonoType {
 	return f.fn.Type()
 }
 
+// Vectorized reports whether the function can be evaluated
+// for a whole column reader at once with EvalBatch.
+func (f *preparedFn) Vectorized() bool {
+	return f.vfn != nil
+}
+
 func ConvertToKind(t flux.ColType) semantic.Nature {
 	// TODO make this an array lookup.
 	switch t {
@@ -211,6 +232,7 @@ func (f *RowPredicateFn) Prepare(cols []flux.ColMeta) (*RowPredicatePreparedFn,
 	} else if fn.returnType().Nature() != semantic.Bool {
 		return nil, errors.New(codes.Invalid, "row predicate function does not evaluate to a boolean")
 	}
+	fn.vfn = f.prepareVector(cols, semantic.Bool)
 	return &RowPredicatePreparedFn{
 		rowFn: rowFn{preparedFn: fn},
 	}, nil
@@ -251,6 +273,17 @@ func (f *RowPredicatePreparedFn) Eval(ctx context.Context, record values.Object)
 	return !v.IsNull() && v.Bool(), nil
 }
 
+// EvalBatch evaluates the predicate for every row of the column reader.
+// A null value in the returned array should be treated as false.
+// It must only be called when the function is Vectorized.
+func (f *RowPredicatePreparedFn) EvalBatch(ctx context.Context, cr flux.ColReader, mem arrowmem.Allocator) (*array.Boolean, error) {
+	v, err := f.vfn.Eval(ctx, cr, mem)
+	if err != nil {
+		return nil, err
+	}
+	return v.Values.(*array.Boolean), nil
+}
+
 type RowMapFn struct {
 	dynamicFn
 }
@@ -268,6 +301,7 @@ func (f *RowMapFn) Prepare(cols []flux.ColMeta) (*RowMapPreparedFn, error) {
 	} else if k := fn.returnType().Nature(); k != semantic.Object {
 		return nil, errors.Newf(codes.Invalid, "map function must return an object, got %s", k.String())
 	}
+	fn.vfn = f.prepareVector(cols, semantic.Object)
 	return &RowMapPreparedFn{
 		rowFn: rowFn{preparedFn: fn},
 	}, nil
@@ -289,6 +323,14 @@ func (f *RowMapPreparedFn) Eval(ctx context.Context, row int, cr flux.ColReader)
 	return v.Object(), nil
 }
 
+// EvalBatch evaluates the map function for every row of the column reader.
+// The returned vector holds a column for each property of the returned record
+// and must be released by the caller.
+// It must only be called when the function is Vectorized.
+func (f *RowMapPreparedFn) EvalBatch(ctx context.Context, cr flux.ColReader, mem arrowmem.Allocator) (*compiler.Vector, error) {
+	return f.vfn.Eval(ctx, cr, mem)
+}
+
 type RowReduceFn struct {
 	dynamicFn
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/arrow"
 	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
 )
@@ -17,6 +18,10 @@ type BufferedBuilder struct {
 	Columns   []flux.ColMeta
 	Buffers   []*arrow.TableBuffer
 	Allocator memory.Allocator
+
+	// spilled holds the buffers that were written
+	// to disk by Spill in the order they were written.
+	spilled []*SpillFile
 }
 
 // NewBufferedBuilder constructs a new BufferedBuilder.
@@ -73,7 +78,7 @@ func (b *BufferedBuilder) appendBuffer(cr flux.ColReader, mem memory.Allocator)
 			// This column existed in a previous table, but
 			// doesn't exist in this one so we need to generate
 			// a null buffer.
-			buffer.Values[j] = b.newNullColumn(c.Type, cr.Len(), mem)
+			buffer.Values[j] = newNullColumn(c.Type, cr.Len(), mem)
 			continue
 		}
 		buffer.Values[j] = Values(cr, idx)
@@ -103,7 +108,7 @@ func (b *BufferedBuilder) normalizeTableSchema(cols []flux.ColMeta, mem memory.A
 			b.Columns = append(b.Columns, c)
 			for _, buf := range b.Buffers {
 				buf.Columns = append(buf.Columns, c)
-				buf.Values = append(buf.Values, b.newNullColumn(c.Type, buf.Len(), mem))
+				buf.Values = append(buf.Values, newNullColumn(c.Type, buf.Len(), mem))
 			}
 			continue
 		}
@@ -119,7 +124,7 @@ func (b *BufferedBuilder) normalizeTableSchema(cols []flux.ColMeta, mem memory.A
 // newNullColumn will construct a new column with only null values
 // for the entire size. The resulting array will match the column
 // type that is passed in.
-func (b *BufferedBuilder) newNullColumn(typ flux.ColType, l int, mem memory.Allocator) array.Interface {
+func newNullColumn(typ flux.ColType, l int, mem memory.Allocator) array.Interface {
 	builder := arrow.NewBuilder(typ, mem)
 	builder.Resize(l)
 	for i := 0; i < l; i++ {
@@ -136,12 +141,44 @@ func (b *BufferedBuilder) getAllocator() memory.Allocator {
 	return mem
 }
 
+// Spill writes the buffers that are in memory to a file
+// within dir using the filesystem service and releases them. The table that is constructed
+// by this builder will read the spilled buffers before
+// the ones that are still in memory.
+func (b *BufferedBuilder) Spill(fs filesystem.Service, dir string) error {
+	if len(b.Buffers) == 0 {
+		return nil
+	}
+
+	buffers := make([]flux.ColReader, 0, len(b.Buffers))
+	for _, buf := range b.Buffers {
+		buffers = append(buffers, buf)
+	}
+	f, err := WriteSpillFile(fs, dir, b.Columns, buffers, b.getAllocator())
+	if err != nil {
+		return err
+	}
+	b.spilled = append(b.spilled, f)
+
+	for _, buf := range b.Buffers {
+		buf.Release()
+	}
+	b.Buffers = nil
+	return nil
+}
+
 func (b *BufferedBuilder) Table() (flux.Table, error) {
 	buffers := make([]flux.ColReader, 0, len(b.Buffers))
 	for _, buf := range b.Buffers {
 		buffers = append(buffers, buf)
 	}
 	b.Buffers = nil
+
+	if len(b.spilled) > 0 {
+		files := b.spilled
+		b.spilled = nil
+		return newSpilledTable(b.GroupKey, b.Columns, files, buffers, b.getAllocator()), nil
+	}
 	return &BufferedTable{
 		GroupKey: b.GroupKey,
 		Columns:  b.Columns,
@@ -153,4 +190,7 @@ func (b *BufferedBuilder) Release() {
 	for _, buf := range b.Buffers {
 		buf.Release()
 	}
+	for _, f := range b.spilled {
+		_ = f.Remove()
+	}
 }
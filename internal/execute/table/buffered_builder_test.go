@@ -1,9 +1,12 @@
 package table_test
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/filesystem"
 	"github.com/influxdata/flux/execute/table/static"
 	"github.com/influxdata/flux/internal/execute/table"
 	"github.com/influxdata/flux/memory"
@@ -183,3 +186,104 @@ func TestBufferedBuilder_AppendTable(t *testing.T) {
 		})
 	}
 }
+
+func TestBufferedBuilder_Spill(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		in   static.TableGroup
+		want static.Table
+	}{
+		{
+			name: "TwoTables",
+			in: static.TableGroup{
+				static.StringKey("_measurement", "m0"),
+				static.StringKey("_field", "f0"),
+				static.Table{
+					static.Times("_time", "2020-01-01T00:00:00Z", 10, 20, 30),
+					static.Ints("_value", 4, 8, nil, 7),
+					static.Strings("t0", "a", "b", "c", nil),
+				},
+				static.Table{
+					static.Times("_time", "2020-01-01T00:00:40Z", 10, 20),
+					static.Ints("_value", 3, 1, 9),
+					static.Strings("t0", "d", nil, "e"),
+				},
+			},
+			want: static.Table{
+				static.StringKey("_measurement", "m0"),
+				static.StringKey("_field", "f0"),
+				static.Times("_time", "2020-01-01T00:00:00Z", 10, 20, 30, 40, 50, 60),
+				static.Ints("_value", 4, 8, nil, 7, 3, 1, 9),
+				static.Strings("t0", "a", "b", "c", nil, "d", nil, "e"),
+			},
+		},
+		{
+			name: "BackfillNulls",
+			in: static.TableGroup{
+				static.StringKey("_measurement", "m0"),
+				static.Table{
+					static.Times("_time", "2020-01-01T00:00:00Z", 10, 20),
+					static.Floats("f0", 3, 8, 2),
+				},
+				static.Table{
+					static.Times("_time", "2020-01-01T00:00:00Z", 10, 20),
+					static.Floats("f0", 18, 2, 7),
+					static.Uints("f1", 5, 9, 2),
+					static.Booleans("f2", true, nil, false),
+				},
+			},
+			want: static.Table{
+				static.StringKey("_measurement", "m0"),
+				static.Times("_time", "2020-01-01T00:00:00Z", 10, 20, "2020-01-01T00:00:00Z", 10, 20),
+				static.Floats("f0", 3, 8, 2, 18, 2, 7),
+				static.Uints("f1", nil, nil, nil, 5, 9, 2),
+				static.Booleans("f2", nil, nil, nil, true, nil, false),
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "flux-spill-test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			mem := &memory.Allocator{}
+			var b *table.BufferedBuilder
+			if err := tt.in.Do(func(tbl flux.Table) error {
+				if b == nil {
+					b = table.NewBufferedBuilder(tbl.Key(), mem)
+				}
+				if err := b.AppendTable(tbl); err != nil {
+					return err
+				}
+				return b.Spill(filesystem.SystemFS, dir)
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(b.Buffers) != 0 {
+				t.Fatalf("expected buffers to be released after spilling, found %d", len(b.Buffers))
+			}
+
+			out, err := b.Table()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			want, got := tt.want, table.Iterator{out}
+
+			if diff := table.Diff(want, got); diff != "" {
+				t.Fatalf("unexpected diff -want/+got:\n%s", diff)
+			}
+
+			if files, err := ioutil.ReadDir(dir); err != nil {
+				t.Fatal(err)
+			} else if len(files) != 0 {
+				t.Errorf("expected spill files to be removed, found %d", len(files))
+			}
+			if got := mem.Allocated(); got != 0 {
+				t.Errorf("expected all memory to be released, %d bytes still allocated", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,343 @@
+package table
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	arrowpkg "github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/arrow"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// SpillFile is a file on disk that holds buffered table data
+// that was written out to free memory.
+//
+// The file is an arrow IPC stream where every record has
+// the columns the SpillFile was written with.
+type SpillFile struct {
+	fs   filesystem.Service
+	path string
+	cols []flux.ColMeta
+	n    int
+}
+
+// spillID is used to generate unique names for spill files.
+var spillID uint64
+
+// WriteSpillFile writes the buffers to a new file within dir
+// using the filesystem service. The service must implement
+// filesystem.Remover so the file can be removed after it is read.
+// Every buffer must have the columns in cols.
+// The buffers are not released.
+func WriteSpillFile(fs filesystem.Service, dir string, cols []flux.ColMeta, buffers []flux.ColReader, mem memory.Allocator) (_ *SpillFile, err error) {
+	fields := make([]arrowpkg.Field, len(cols))
+	for j, c := range cols {
+		fields[j] = arrowpkg.Field{
+			Name:     c.Label,
+			Type:     spillDataType(c.Type),
+			Nullable: true,
+		}
+	}
+	schema := arrowpkg.NewSchema(fields, nil)
+
+	name := fmt.Sprintf("flux-spill-%d-%d", os.Getpid(), atomic.AddUint64(&spillID, 1))
+	fpath := filepath.Join(dir, name)
+	f, err := fs.Create(fpath)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Internal, "could not create spill file")
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = errors.Wrap(cerr, codes.Internal, "could not write spill file")
+		}
+		if err != nil {
+			_ = filesystem.Remove(fs, fpath)
+		}
+	}()
+
+	w := ipc.NewWriter(f, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+	n := 0
+	for _, cr := range buffers {
+		vs := make([]array.Interface, len(cols))
+		for j, c := range cols {
+			idx := execute.ColIdx(c.Label, cr.Cols())
+			if idx < 0 {
+				return nil, errors.Newf(codes.Internal, "spilled buffer is missing column %q", c.Label)
+			}
+			if c.Type == flux.TString {
+				// The arrow writer expects string columns
+				// to use string arrays instead of binary arrays.
+				vs[j] = array.NewStringData(cr.Strings(idx).Data())
+				continue
+			}
+			vs[j] = Values(cr, idx)
+			vs[j].Retain()
+		}
+		rec := array.NewRecord(schema, vs, int64(cr.Len()))
+		for _, arr := range vs {
+			arr.Release()
+		}
+		err := w.Write(rec)
+		rec.Release()
+		if err != nil {
+			return nil, errors.Wrap(err, codes.Internal, "could not write spill file")
+		}
+		n += cr.Len()
+	}
+	if err := w.Close(); err != nil {
+		return nil, errors.Wrap(err, codes.Internal, "could not write spill file")
+	}
+	return &SpillFile{
+		fs:   fs,
+		path: fpath,
+		cols: cols,
+		n:    n,
+	}, nil
+}
+
+// spillDataType returns the arrow data type that is
+// used to store a column of the given type.
+func spillDataType(typ flux.ColType) arrowpkg.DataType {
+	switch typ {
+	case flux.TInt, flux.TTime:
+		return arrowpkg.PrimitiveTypes.Int64
+	case flux.TUInt:
+		return arrowpkg.PrimitiveTypes.Uint64
+	case flux.TFloat:
+		return arrowpkg.PrimitiveTypes.Float64
+	case flux.TString:
+		return arrowpkg.BinaryTypes.String
+	case flux.TBool:
+		return arrowpkg.FixedWidthTypes.Boolean
+	default:
+		panic(errors.Newf(codes.Internal, "unimplemented column type: %s", typ))
+	}
+}
+
+// Cols returns the columns that were written to the file.
+func (f *SpillFile) Cols() []flux.ColMeta {
+	return f.cols
+}
+
+// Len returns the number of rows within the file.
+func (f *SpillFile) Len() int {
+	return f.n
+}
+
+// Open opens the file for reading. Each buffer that is read
+// will have the given group key.
+func (f *SpillFile) Open(key flux.GroupKey, mem memory.Allocator) (*SpillReader, error) {
+	fh, err := f.fs.Open(f.path)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Internal, "could not open spill file")
+	}
+	r, err := ipc.NewReader(fh, ipc.WithAllocator(mem))
+	if err != nil {
+		_ = fh.Close()
+		return nil, errors.Wrap(err, codes.Internal, "could not read spill file")
+	}
+	return &SpillReader{
+		f:    fh,
+		r:    r,
+		key:  key,
+		cols: f.cols,
+	}, nil
+}
+
+// Remove deletes the file from disk.
+func (f *SpillFile) Remove() error {
+	return filesystem.Remove(f.fs, f.path)
+}
+
+// SpillReader reads the buffers from a SpillFile.
+type SpillReader struct {
+	f    filesystem.File
+	r    *ipc.Reader
+	key  flux.GroupKey
+	cols []flux.ColMeta
+}
+
+// Read returns the next buffer in the file.
+// It returns io.EOF when there are no more buffers.
+// The caller is responsible for releasing the buffer.
+func (r *SpillReader) Read() (*arrow.TableBuffer, error) {
+	if !r.r.Next() {
+		if err := r.r.Err(); err != nil {
+			return nil, errors.Wrap(err, codes.Internal, "could not read spill file")
+		}
+		return nil, io.EOF
+	}
+
+	// The record is only valid until the next call to Next
+	// so the arrays are retained by the buffer.
+	rec := r.r.Record()
+	buf := &arrow.TableBuffer{
+		GroupKey: r.key,
+		Columns:  r.cols,
+		Values:   make([]array.Interface, len(r.cols)),
+	}
+	for j := range r.cols {
+		arr := rec.Column(j)
+		if s, ok := arr.(*array.String); ok {
+			// Flux reads strings as binary arrays, but the
+			// arrow reader constructs them as string arrays.
+			buf.Values[j] = array.NewBinaryData(s.Data())
+			continue
+		}
+		arr.Retain()
+		buf.Values[j] = arr
+	}
+	return buf, nil
+}
+
+// Close closes the underlying file.
+func (r *SpillReader) Close() error {
+	r.r.Release()
+	return r.f.Close()
+}
+
+// spilledTable is a table that reads the spilled files
+// from disk before reading the buffers in memory.
+type spilledTable struct {
+	used  int32
+	empty bool
+	key   flux.GroupKey
+	cols  []flux.ColMeta
+	files []*SpillFile
+	bufs  []flux.ColReader
+	mem   memory.Allocator
+}
+
+func newSpilledTable(key flux.GroupKey, cols []flux.ColMeta, files []*SpillFile, bufs []flux.ColReader, mem memory.Allocator) *spilledTable {
+	empty := true
+	for _, file := range files {
+		if file.Len() > 0 {
+			empty = false
+		}
+	}
+	for _, buf := range bufs {
+		if buf.Len() > 0 {
+			empty = false
+		}
+	}
+	return &spilledTable{
+		empty: empty,
+		key:   key,
+		cols:  cols,
+		files: files,
+		bufs:  bufs,
+		mem:   mem,
+	}
+}
+
+func (t *spilledTable) Key() flux.GroupKey {
+	return t.key
+}
+
+func (t *spilledTable) Cols() []flux.ColMeta {
+	return t.cols
+}
+
+func (t *spilledTable) Do(f func(flux.ColReader) error) error {
+	if !atomic.CompareAndSwapInt32(&t.used, 0, 1) {
+		return errors.New(codes.Internal, "table already read")
+	}
+	defer t.release()
+
+	for _, file := range t.files {
+		if err := t.readFile(file, f); err != nil {
+			return err
+		}
+	}
+
+	for i, cr := range t.bufs {
+		t.bufs[i] = nil
+		err := f(cr)
+		cr.Release()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *spilledTable) readFile(file *SpillFile, f func(flux.ColReader) error) error {
+	r, err := file.Open(t.key, t.mem)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+
+	for {
+		buf, err := r.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		buf = normalizeBuffer(buf, t.cols, t.mem)
+		err = f(buf)
+		buf.Release()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (t *spilledTable) Done() {
+	if atomic.CompareAndSwapInt32(&t.used, 0, 1) {
+		t.release()
+	}
+}
+
+func (t *spilledTable) Empty() bool {
+	return t.empty
+}
+
+// release removes the spilled files and releases
+// any buffers that were not read.
+func (t *spilledTable) release() {
+	for _, file := range t.files {
+		_ = file.Remove()
+	}
+	for _, buf := range t.bufs {
+		if buf != nil {
+			buf.Release()
+		}
+	}
+	t.bufs = nil
+}
+
+// normalizeBuffer ensures the buffer has every column in cols.
+// Columns that were added after the buffer was spilled are filled with nulls.
+func normalizeBuffer(buf *arrow.TableBuffer, cols []flux.ColMeta, mem memory.Allocator) *arrow.TableBuffer {
+	if len(buf.Columns) == len(cols) {
+		return buf
+	}
+	nbuf := &arrow.TableBuffer{
+		GroupKey: buf.GroupKey,
+		Columns:  cols,
+		Values:   make([]array.Interface, len(cols)),
+	}
+	for j, c := range cols {
+		idx := execute.ColIdx(c.Label, buf.Columns)
+		if idx < 0 {
+			nbuf.Values[j] = newNullColumn(c.Type, buf.Len(), mem)
+			continue
+		}
+		nbuf.Values[j] = buf.Values[idx]
+		nbuf.Values[j].Retain()
+	}
+	buf.Release()
+	return nbuf
+}
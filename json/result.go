@@ -0,0 +1,181 @@
+// Package json implements an encoder that writes Flux results
+// as newline delimited JSON objects.
+package json
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/iocounter"
+)
+
+const (
+	resultLabel = "result"
+	tableLabel  = "table"
+	rowLabel    = "row"
+	errorLabel  = "error"
+)
+
+// ResultEncoder encodes a result as newline delimited JSON.
+// Each row of each table is written as a single JSON object
+// with the name of the result, the index of the table within
+// the result, and the row itself as an object with one property
+// for every column. The columns are nested so that they never
+// collide with the result and table properties.
+//
+// Null values and floats that cannot be represented in JSON,
+// such as NaN and infinities, are encoded as null.
+type ResultEncoder struct{}
+
+// NewResultEncoder creates a new JSON result encoder.
+func NewResultEncoder() *ResultEncoder {
+	return &ResultEncoder{}
+}
+
+type jsonEncoderError struct {
+	err error
+}
+
+func (e *jsonEncoderError) Error() string {
+	return fmt.Sprintf("json encoder error: %s", e.err.Error())
+}
+
+func (e *jsonEncoderError) IsEncoderError() bool {
+	return true
+}
+
+func (e *jsonEncoderError) Unwrap() error {
+	return e.err
+}
+
+func wrapEncodingError(err error) error {
+	if err == nil {
+		return err
+	}
+	return &jsonEncoderError{err: err}
+}
+
+func (e *ResultEncoder) Encode(w io.Writer, result flux.Result) (int64, error) {
+	writeCounter := &iocounter.Writer{Writer: w}
+	writer := bufio.NewWriter(writeCounter)
+
+	resultName, err := json.Marshal(result.Name())
+	if err != nil {
+		return 0, wrapEncodingError(err)
+	}
+
+	tableID := 0
+	err = result.Tables().Do(func(tbl flux.Table) error {
+		// Precompute the prefix of each row along with
+		// the encoded property names for each column.
+		prefix := fmt.Sprintf(`{"%s":%s,"%s":%d,"%s":{`, resultLabel, resultName, tableLabel, tableID, rowLabel)
+		cols := tbl.Cols()
+		labels := make([][]byte, len(cols))
+		for j, c := range cols {
+			label, err := json.Marshal(c.Label)
+			if err != nil {
+				return wrapEncodingError(err)
+			}
+			if j > 0 {
+				labels[j] = append(labels[j], ',')
+			}
+			labels[j] = append(append(labels[j], label...), ':')
+		}
+
+		var buf []byte
+		if err := tbl.Do(func(cr flux.ColReader) error {
+			l := cr.Len()
+			for i := 0; i < l; i++ {
+				buf = append(buf[:0], prefix...)
+				for j, c := range cols {
+					buf = append(buf, labels[j]...)
+					v, err := appendValue(buf, i, j, c.Type, cr)
+					if err != nil {
+						return wrapEncodingError(err)
+					}
+					buf = v
+				}
+				buf = append(buf, '}', '}', '\n')
+				if _, err := writer.Write(buf); err != nil {
+					return wrapEncodingError(err)
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		tableID++
+		return wrapEncodingError(writer.Flush())
+	})
+	if err != nil {
+		return writeCounter.Count(), err
+	}
+	return writeCounter.Count(), wrapEncodingError(writer.Flush())
+}
+
+// EncodeError writes the error as a JSON object with a single error property.
+func (e *ResultEncoder) EncodeError(w io.Writer, err error) error {
+	msg, jerr := json.Marshal(err.Error())
+	if jerr != nil {
+		return jerr
+	}
+	_, werr := fmt.Fprintf(w, `{"%s":%s}`+"\n", errorLabel, msg)
+	return werr
+}
+
+func appendValue(buf []byte, i, j int, typ flux.ColType, cr flux.ColReader) ([]byte, error) {
+	switch typ {
+	case flux.TBool:
+		if vs := cr.Bools(j); vs.IsValid(i) {
+			return strconv.AppendBool(buf, vs.Value(i)), nil
+		}
+	case flux.TInt:
+		if vs := cr.Ints(j); vs.IsValid(i) {
+			return strconv.AppendInt(buf, vs.Value(i), 10), nil
+		}
+	case flux.TUInt:
+		if vs := cr.UInts(j); vs.IsValid(i) {
+			return strconv.AppendUint(buf, vs.Value(i), 10), nil
+		}
+	case flux.TFloat:
+		if vs := cr.Floats(j); vs.IsValid(i) {
+			v := vs.Value(i)
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				break
+			}
+			return strconv.AppendFloat(buf, v, 'f', -1, 64), nil
+		}
+	case flux.TString:
+		if vs := cr.Strings(j); vs.IsValid(i) {
+			s, err := json.Marshal(vs.ValueString(i))
+			if err != nil {
+				return nil, err
+			}
+			return append(buf, s...), nil
+		}
+	case flux.TTime:
+		if vs := cr.Times(j); vs.IsValid(i) {
+			buf = append(buf, '"')
+			buf = execute.Time(vs.Value(i)).Time().AppendFormat(buf, time.RFC3339Nano)
+			return append(buf, '"'), nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %v", typ)
+	}
+	return append(buf, "null"...), nil
+}
+
+// NewMultiResultEncoder creates a MultiResultEncoder that writes
+// the rows of every result as newline delimited JSON.
+func NewMultiResultEncoder() flux.MultiResultEncoder {
+	return &flux.DelimitedMultiResultEncoder{
+		Encoder: NewResultEncoder(),
+	}
+}
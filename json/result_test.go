@@ -0,0 +1,177 @@
+package json_test
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/andreyvit/diff"
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/json"
+	"github.com/influxdata/flux/values"
+)
+
+func TestMultiResultEncoder(t *testing.T) {
+	testCases := []struct {
+		name    string
+		results flux.ResultIterator
+		encoded []byte
+		err     error
+	}{
+		{
+			name: "single result",
+			results: flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+				Nm: "_result",
+				Tbls: []*executetest.Table{{
+					KeyCols: []string{"_measurement", "host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{values.ConvertTime(time.Date(2018, 4, 17, 0, 0, 0, 0, time.UTC)), "cpu", "A", 42.0},
+						{values.ConvertTime(time.Date(2018, 4, 17, 0, 0, 1, 0, time.UTC)), "cpu", "A", 43.5},
+					},
+				}},
+			}}),
+			encoded: []byte(`{"result":"_result","table":0,"row":{"_time":"2018-04-17T00:00:00Z","_measurement":"cpu","host":"A","_value":42}}
+{"result":"_result","table":0,"row":{"_time":"2018-04-17T00:00:01Z","_measurement":"cpu","host":"A","_value":43.5}}
+`),
+		},
+		{
+			name: "multiple results and tables",
+			results: flux.NewSliceResultIterator([]flux.Result{
+				&executetest.Result{
+					Nm: "a",
+					Tbls: []*executetest.Table{
+						{
+							KeyCols: []string{"t0"},
+							ColMeta: []flux.ColMeta{
+								{Label: "t0", Type: flux.TString},
+								{Label: "_value", Type: flux.TInt},
+							},
+							Data: [][]interface{}{
+								{"x", int64(1)},
+							},
+						},
+						{
+							KeyCols: []string{"t0"},
+							ColMeta: []flux.ColMeta{
+								{Label: "t0", Type: flux.TString},
+								{Label: "_value", Type: flux.TInt},
+							},
+							Data: [][]interface{}{
+								{"y", int64(2)},
+							},
+						},
+					},
+				},
+				&executetest.Result{
+					Nm: "b",
+					Tbls: []*executetest.Table{{
+						ColMeta: []flux.ColMeta{
+							{Label: "_value", Type: flux.TBool},
+						},
+						Data: [][]interface{}{
+							{true},
+						},
+					}},
+				},
+			}),
+			encoded: []byte(`{"result":"a","table":0,"row":{"t0":"x","_value":1}}
+{"result":"a","table":1,"row":{"t0":"y","_value":2}}
+{"result":"b","table":0,"row":{"_value":true}}
+`),
+		},
+		{
+			name: "nulls and special values",
+			results: flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+				Nm: "_result",
+				Tbls: []*executetest.Table{{
+					ColMeta: []flux.ColMeta{
+						{Label: "s", Type: flux.TString},
+						{Label: "u", Type: flux.TUInt},
+						{Label: "f", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{"a \"quoted\" string", uint64(7), math.NaN()},
+						{nil, nil, nil},
+					},
+				}},
+			}}),
+			encoded: []byte(`{"result":"_result","table":0,"row":{"s":"a \"quoted\" string","u":7,"f":null}}
+{"result":"_result","table":0,"row":{"s":null,"u":null,"f":null}}
+`),
+		},
+		{
+			name: "columns named like the labels",
+			results: flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+				Nm: "_result",
+				Tbls: []*executetest.Table{{
+					ColMeta: []flux.ColMeta{
+						{Label: "result", Type: flux.TString},
+						{Label: "table", Type: flux.TInt},
+					},
+					Data: [][]interface{}{
+						{"mean", int64(3)},
+					},
+				}},
+			}}),
+			encoded: []byte(`{"result":"_result","table":0,"row":{"result":"mean","table":3}}
+`),
+		},
+		{
+			name: "error results",
+			results: flux.NewSliceResultIterator([]flux.Result{
+				&executetest.Result{
+					Nm: "_result",
+					Tbls: []*executetest.Table{{
+						ColMeta: []flux.ColMeta{
+							{Label: "_value", Type: flux.TInt},
+						},
+						Data: [][]interface{}{
+							{int64(1)},
+						},
+					}},
+				},
+				&executetest.Result{
+					Nm:  "mean",
+					Err: errors.New("test error"),
+				},
+			}),
+			encoded: []byte(`{"result":"_result","table":0,"row":{"_value":1}}
+{"error":"test error"}
+`),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			encoder := json.NewMultiResultEncoder()
+			var got bytes.Buffer
+			n, err := encoder.Encode(&got, tc.results)
+			if err != nil && tc.err != nil {
+				if err.Error() != tc.err.Error() {
+					t.Errorf("unexpected error want: %s\n got: %s\n", tc.err.Error(), err.Error())
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error want: none\n got: %s\n", err.Error())
+			} else if tc.err != nil {
+				t.Errorf("unexpected error want: %s\n got: none", tc.err.Error())
+			}
+
+			if g, w := got.String(), string(tc.encoded); g != w {
+				t.Errorf("unexpected encoding -want/+got:\n%s", diff.LineDiff(w, g))
+			}
+			if g, w := n, int64(len(tc.encoded)); g != w {
+				t.Errorf("unexpected encoding count -want/+got:\n%s", cmp.Diff(w, g))
+			}
+		})
+	}
+}
@@ -68,6 +68,23 @@ func WithPhysPlanOpts(popts ...plan.PhysicalOption) CompileOption {
 		o.planOptions.physical = append(o.planOptions.physical, popts...)
 	}
 }
+
+// WithResources overrides the concurrency quota and dispatcher throughput
+// that the planner would otherwise derive from the shape of the plan.
+func WithResources(r plan.Resources) CompileOption {
+	return func(o *compileOptions) {
+		o.planOptions.physical = append(o.planOptions.physical, plan.WithResources(r))
+	}
+}
+
+// WithCostBasedPlanning makes the physical planner choose between
+// rules that rewrite the same node by the estimated cost of the plan.
+func WithCostBasedPlanning() CompileOption {
+	return func(o *compileOptions) {
+		o.planOptions.physical = append(o.planOptions.physical, plan.WithCostBasedPlanning())
+	}
+}
+
 func WithExtern(extern flux.ASTHandle) CompileOption {
 	return func(o *compileOptions) {
 		o.extern = extern
@@ -143,19 +160,45 @@ func CompileTableObject(ctx context.Context, to *flux.TableObject, now time.Time
 func buildPlan(ctx context.Context, spec *flux.Spec, opts *compileOptions) (*plan.Spec, error) {
 	s, _ := opentracing.StartSpanFromContext(ctx, "plan")
 	defer s.Finish()
-	pb := plan.PlannerBuilder{}
-
-	planOptions := opts.planOptions
+	return planSpec(ctx, spec, opts.planOptions.logical, opts.planOptions.physical, physicalStage, nil)
+}
 
-	lopts := planOptions.logical
-	popts := planOptions.physical
+// planStage is a stage of planning a spec.
+type planStage int
 
-	pb.AddLogicalOptions(lopts...)
-	pb.AddPhysicalOptions(popts...)
+const (
+	// initialStage creates the initial plan from the spec.
+	initialStage planStage = iota
+	// logicalStage applies the logical rules.
+	logicalStage
+	// physicalStage applies the physical rules.
+	physicalStage
+)
 
-	ps, err := pb.Build().Plan(ctx, spec)
-	if err != nil {
-		return nil, err
+// planSpec plans the spec with the planner options up to and including
+// the last stage. If it is not nil, observe is called with the plan
+// after each stage.
+func planSpec(ctx context.Context, spec *flux.Spec, lopts []plan.LogicalOption, popts []plan.PhysicalOption, last planStage, observe func(planStage, *plan.Spec) error) (*plan.Spec, error) {
+	lp := plan.NewLogicalPlanner(lopts...)
+	var ps *plan.Spec
+	for stage := initialStage; stage <= last; stage++ {
+		var err error
+		switch stage {
+		case initialStage:
+			ps, err = lp.CreateInitialPlan(spec)
+		case logicalStage:
+			ps, err = lp.Plan(ctx, ps)
+		case physicalStage:
+			ps, err = plan.NewPhysicalPlanner(popts...).Plan(ctx, ps)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if observe != nil {
+			if err := observe(stage, ps); err != nil {
+				return nil, err
+			}
+		}
 	}
 	return ps, nil
 }
@@ -490,6 +533,102 @@ func (p *AstProgram) Start(ctx context.Context, alloc *memory.Allocator) (flux.Q
 	return p.Program.Start(cctx, alloc)
 }
 
+// Explanation describes how a program was planned.
+type Explanation struct {
+	// Initial is the plan created from the query specification.
+	Initial plan.Description `json:"initial"`
+	// Logical is the plan after the logical rules were applied.
+	Logical plan.Description `json:"logical"`
+	// Physical is the plan that would be executed.
+	Physical plan.Description `json:"physical"`
+
+	// LogicalRules and PhysicalRules are the rules applied
+	// by each planner in the order they were applied.
+	LogicalRules  []plan.RuleApplication `json:"logicalRules"`
+	PhysicalRules []plan.RuleApplication `json:"physicalRules"`
+
+	// LogicalDeclines and PhysicalDeclines are the rules that matched
+	// a node, but did not rewrite it, along with the reason why.
+	LogicalDeclines  []plan.RuleDecline `json:"logicalDeclines"`
+	PhysicalDeclines []plan.RuleDecline `json:"physicalDeclines"`
+}
+
+// Explain evaluates and plans the program without executing it.
+// It describes the plan after each stage of planning.
+func (p *AstProgram) Explain(ctx context.Context, alloc *memory.Allocator) (*Explanation, error) {
+	ctx, sp, err := p.evalSpec(ctx, alloc, "explaining")
+	if err != nil {
+		return nil, err
+	}
+	e, err := explainPlan(ctx, sp, p.opts)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Inherit, "error in building plan while explaining program")
+	}
+	return e, nil
+}
+
+// explainPlan plans the spec in the same way as buildPlan
+// and describes the plan after each planner.
+func explainPlan(ctx context.Context, spec *flux.Spec, opts *compileOptions) (*Explanation, error) {
+	var logicalRules, physicalRules plan.RuleTrace
+	lopts := append([]plan.LogicalOption{}, opts.planOptions.logical...)
+	lopts = append(lopts, plan.WithLogicalRuleTrace(&logicalRules))
+	popts := append([]plan.PhysicalOption{}, opts.planOptions.physical...)
+	popts = append(popts, plan.WithPhysicalRuleTrace(&physicalRules))
+
+	e := new(Explanation)
+	descriptions := map[planStage]*plan.Description{
+		initialStage:  &e.Initial,
+		logicalStage:  &e.Logical,
+		physicalStage: &e.Physical,
+	}
+	if _, err := planSpec(ctx, spec, lopts, popts, physicalStage, func(stage planStage, ps *plan.Spec) (err error) {
+		*descriptions[stage], err = plan.Describe(ps)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	e.LogicalRules = logicalRules.Applications
+	e.PhysicalRules = physicalRules.Applications
+	e.LogicalDeclines = logicalRules.Declines
+	e.PhysicalDeclines = physicalRules.Declines
+	return e, nil
+}
+
+// LogicalPlan evaluates the program and returns its plan after the
+// logical planner rules were applied, without executing it.
+func (p *AstProgram) LogicalPlan(ctx context.Context, alloc *memory.Allocator) (*plan.Spec, error) {
+	ctx, sp, err := p.evalSpec(ctx, alloc, "planning")
+	if err != nil {
+		return nil, err
+	}
+	ps, err := planSpec(ctx, sp, p.opts.planOptions.logical, nil, logicalStage, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Inherit, "error in building plan while planning program")
+	}
+	return ps, nil
+}
+
+// evalSpec evaluates the program to plan it without executing it.
+// It returns the spec and the context to plan it with. The action
+// describes why the program is planned in the errors.
+func (p *AstProgram) evalSpec(ctx context.Context, alloc *memory.Allocator, action string) (context.Context, *flux.Spec, error) {
+	deps := execute.NewExecutionDependencies(alloc, &p.Now, p.Logger)
+	ctx = deps.Inject(ctx)
+	nextPlanNodeID := new(int)
+	ctx = context.WithValue(ctx, plan.NextPlanNodeIDKey, nextPlanNodeID)
+
+	sp, scope, err := p.getSpec(ctx, alloc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := p.updateOpts(scope); err != nil {
+		return nil, nil, errors.Wrapf(err, codes.Inherit, "error in reading options while %s program", action)
+	}
+	return ctx, sp, nil
+}
+
 func (p *AstProgram) updateProfilers(ctx context.Context, scope values.Scope) error {
 	if execute.HaveExecutionDependencies(ctx) {
 		deps := execute.GetExecutionDependencies(ctx)
@@ -514,6 +653,13 @@ func (p *AstProgram) updateOpts(scope values.Scope) error {
 	if po != nil {
 		p.opts.planOptions.physical = append(p.opts.planOptions.physical, po)
 	}
+	r, err := getPlanResources(pkg)
+	if err != nil {
+		return err
+	}
+	if r != (plan.Resources{}) {
+		p.opts.planOptions.physical = append(p.opts.planOptions.physical, plan.WithResources(r))
+	}
 	return nil
 }
 
@@ -551,6 +697,40 @@ func getPlanOptions(plannerPkg values.Package) (plan.LogicalOption, plan.Physica
 	return plan.RemoveLogicalRules(ls...), plan.RemovePhysicalRules(ps...), nil
 }
 
+// getPlanResources reads the resources set by the planner options.
+func getPlanResources(plannerPkg values.Package) (plan.Resources, error) {
+	if plannerPkg.Type().Nature() != semantic.Object {
+		return plan.Resources{}, nil
+	}
+	concurrency, err := getIntOptionValue(plannerPkg.Object(), "concurrencyQuota")
+	if err != nil {
+		return plan.Resources{}, err
+	}
+	throughput, err := getIntOptionValue(plannerPkg.Object(), "dispatcherThroughput")
+	if err != nil {
+		return plan.Resources{}, err
+	}
+	return plan.Resources{
+		ConcurrencyQuota:     concurrency,
+		DispatcherThroughput: throughput,
+	}, nil
+}
+
+func getIntOptionValue(pkg values.Object, optionName string) (int, error) {
+	value, ok := pkg.Get(optionName)
+	if !ok || value.IsNull() {
+		// No value in package.
+		return 0, nil
+	}
+	if value.Type().Nature() != semantic.Int {
+		return 0, errors.Newf(codes.Invalid, "option %q must be an integer, got %v", optionName, value.Type().Nature())
+	}
+	if v := value.Int(); v < 0 {
+		return 0, errors.Newf(codes.Invalid, "option %q must not be negative, got %d", optionName, v)
+	}
+	return int(value.Int()), nil
+}
+
 func getOptionValues(pkg values.Object, optionName string) ([]string, error) {
 	value, ok := pkg.Get(optionName)
 	if !ok {
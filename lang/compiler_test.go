@@ -211,6 +211,97 @@ func TestCompilationError(t *testing.T) {
 	}
 }
 
+func TestAstProgram_LogicalPlan(t *testing.T) {
+	program, err := lang.Compile(`
+from(bucket: "telegraf")
+	|> range(start: -5m)
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> yield(name: "cpu")
+`, runtime.Default, time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := executetest.NewTestExecuteDependencies().Inject(context.Background())
+	ps, err := program.LogicalPlan(ctx, &memory.Allocator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var kinds []plan.ProcedureKind
+	if err := ps.TopDownWalk(func(node plan.Node) error {
+		kinds = append(kinds, node.Kind())
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// The range and filter are only merged into
+	// the source by the physical planner rules.
+	want := []plan.ProcedureKind{universe.YieldKind, universe.FilterKind, universe.RangeKind, influxdb.FromKind}
+	if !cmp.Equal(want, kinds) {
+		t.Errorf("unexpected plan nodes -want/+got:\n%s", cmp.Diff(want, kinds))
+	}
+}
+
+func TestAstProgram_Explain(t *testing.T) {
+	program, err := lang.Compile(`
+from(bucket: "telegraf")
+	|> range(start: -5m)
+	|> window(every: 1m)
+	|> mean(column: "usage")
+`, runtime.Default, time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := executetest.NewTestExecuteDependencies().Inject(context.Background())
+	e, err := program.Explain(ctx, &memory.Allocator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kinds := func(d plan.Description) []plan.ProcedureKind {
+		var kinds []plan.ProcedureKind
+		for _, node := range d.Nodes {
+			kinds = append(kinds, node.Kind)
+		}
+		return kinds
+	}
+	want := []plan.ProcedureKind{influxdb.FromKind, universe.RangeKind, universe.WindowKind, universe.MeanKind, universe.YieldKind}
+	if got := kinds(e.Initial); !cmp.Equal(want, got) {
+		t.Errorf("unexpected initial plan -want/+got:\n%s", cmp.Diff(want, got))
+	}
+	want = []plan.ProcedureKind{influxdb.FromRemoteKind, universe.WindowKind, universe.MeanKind, universe.YieldKind}
+	if got := kinds(e.Physical); !cmp.Equal(want, got) {
+		t.Errorf("unexpected physical plan -want/+got:\n%s", cmp.Diff(want, got))
+	}
+	for _, node := range e.Physical.Nodes {
+		if node.Cost == nil || node.Statistics == nil {
+			t.Errorf("physical node %s has no estimates", node.ID)
+		}
+	}
+
+	var merged bool
+	for _, r := range e.PhysicalRules {
+		merged = merged || r.Rule == "influxdata/influxdb.MergeRemoteRangeRule"
+	}
+	if !merged {
+		t.Errorf("expected the range to be merged into from, got rules %v", e.PhysicalRules)
+	}
+
+	// The aggregate is not pushed down because it is not applied to _value.
+	if len(e.PhysicalDeclines) != 1 {
+		t.Fatalf("expected one declined rule, got %v", e.PhysicalDeclines)
+	}
+	d := e.PhysicalDeclines[0]
+	if want, got := "influxdata/influxdb.MergeRemoteWindowAggregateRule", d.Rule; want != got {
+		t.Errorf("unexpected declined rule -want/+got:\n\t- %s\n\t+ %s", want, got)
+	}
+	if want, got := "only aggregates of the _value column are applied remotely", d.Reason; want != got {
+		t.Errorf("unexpected reason -want/+got:\n\t- %s\n\t+ %s", want, got)
+	}
+}
+
 func TestASTCompiler(t *testing.T) {
 	testcases := []struct {
 		name         string
@@ -239,7 +330,7 @@ csv.from(csv: "foo,bar") |> range(start: 2017-10-10T00:00:00Z)
 					{0, 1},
 					{1, 2},
 				},
-				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64},
+				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64, DispatcherThroughput: 10},
 				Now:       parser.MustParseTime("2017-10-10T00:01:00Z").Value,
 			},
 		},
@@ -260,7 +351,7 @@ csv.from(csv: "foo,bar") |> range(start: 2017-10-10T00:00:00Z)
 					{0, 1},
 					{1, 2},
 				},
-				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64},
+				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64, DispatcherThroughput: 10},
 				Now:       parser.MustParseTime("2018-10-10T00:00:00Z").Value,
 			},
 		},
@@ -294,7 +385,7 @@ csv.from(csv: "foo,bar") |> range(start: 2017-10-10T00:00:00Z)
 					{0, 1},
 					{1, 2},
 				},
-				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64},
+				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64, DispatcherThroughput: 10},
 				Now:       parser.MustParseTime("2018-10-10T00:00:00Z").Value,
 			},
 		},
@@ -457,7 +548,7 @@ func TestCompileOptions(t *testing.T) {
 			{0, 1},
 			{1, 2},
 		},
-		Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64},
+		Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64, DispatcherThroughput: 10},
 		Now:       parser.MustParseTime("2018-10-10T00:00:00Z").Value,
 	})
 
@@ -504,7 +595,7 @@ from(bucket: "bkt") |> range(start: 0) |> filter(fn: (r) => r._value > 0) |> cou
 				Edges: [][2]int{
 					{0, 1},
 				},
-				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64},
+				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64, DispatcherThroughput: 10},
 				Now:       nowFn(),
 			}),
 		},
@@ -526,7 +617,7 @@ from(bucket: "bkt") |> range(start: 0) |> filter(fn: (r) => r._value > 0) |> cou
 					{0, 1},
 					{1, 2},
 				},
-				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64},
+				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64, DispatcherThroughput: 10},
 				Now:       nowFn(),
 			}),
 		},
@@ -551,7 +642,7 @@ from(bucket: "bkt") |> range(start: 0) |> filter(fn: (r) => r._value > 0) |> cou
 					{1, 2},
 					{2, 3},
 				},
-				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64},
+				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64, DispatcherThroughput: 20},
 				Now:       nowFn(),
 			}),
 		},
@@ -576,7 +667,7 @@ from(bucket: "bkt") |> range(start: 0) |> filter(fn: (r) => r._value > 0) |> cou
 					{1, 2},
 					{2, 3},
 				},
-				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64},
+				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64, DispatcherThroughput: 20},
 				Now:       nowFn(),
 			}),
 		},
@@ -597,7 +688,7 @@ from(bucket: "bkt") |> range(start: 0) |> filter(fn: (r) => r._value > 0) |> cou
 				Edges: [][2]int{
 					{0, 1},
 				},
-				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64},
+				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64, DispatcherThroughput: 10},
 				Now:       nowFn(),
 			}),
 		},
@@ -618,7 +709,7 @@ from(bucket: "bkt") |> range(start: 0) |> filter(fn: (r) => r._value > 0) |> cou
 				Edges: [][2]int{
 					{0, 1},
 				},
-				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64},
+				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64, DispatcherThroughput: 10},
 				Now:       nowFn(),
 			}),
 		},
@@ -684,7 +775,7 @@ from(bucket: "bkt") |> range(start: 0) |> filter(fn: (r) => r._value > 0) |> cou
 				Edges: [][2]int{
 					{0, 1},
 				},
-				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64},
+				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64, DispatcherThroughput: 10},
 				Now:       nowFn(),
 			}),
 		},
@@ -709,10 +800,42 @@ from(bucket: "bkt") |> range(start: 0) |> filter(fn: (r) => r._value > 0) |> cou
 					{1, 2},
 					{2, 3},
 				},
-				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64},
+				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64, DispatcherThroughput: 20},
+				Now:       nowFn(),
+			}),
+		},
+		{
+			name: "set resources",
+			files: []string{`
+import "planner"
+
+option planner.concurrencyQuota = 4
+option planner.dispatcherThroughput = 50
+
+from(bucket: "bkt") |> range(start: 0) |> filter(fn: (r) => r._value > 0) |> count()`},
+			want: plantest.CreatePlanSpec(&plantest.PlanSpec{
+				Nodes: []plan.Node{
+					&plan.PhysicalPlanNode{Spec: &influxdb.FromRemoteProcedureSpec{}},
+					&plan.PhysicalPlanNode{Spec: &universe.YieldProcedureSpec{}},
+				},
+				Edges: [][2]int{
+					{0, 1},
+				},
+				Resources: flux.ResourceManagement{ConcurrencyQuota: 4, MemoryBytesQuota: math.MaxInt64, DispatcherThroughput: 50},
 				Now:       nowFn(),
 			}),
 		},
+		{
+			name: "concurrency quota must not be negative",
+			files: []string{`
+import "planner"
+
+option planner.concurrencyQuota = -1
+
+// remember to return streaming data
+from(bucket: "does_not_matter")`},
+			wantErr: `option "concurrencyQuota" must not be negative, got -1`,
+		},
 		{
 			name: "multiple files - splitting options setting",
 			files: []string{
@@ -738,7 +861,7 @@ option planner.disableLogicalRules = ["removeCountRule"]`},
 					{1, 2},
 					{2, 3},
 				},
-				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64},
+				Resources: flux.ResourceManagement{ConcurrencyQuota: 1, MemoryBytesQuota: math.MaxInt64, DispatcherThroughput: 20},
 				Now:       nowFn(),
 			}),
 		},
@@ -2,13 +2,14 @@ extern crate serde_aux;
 extern crate serde_derive;
 
 use core::parser::Parser;
+use core::semantic::bootstrap::build_polytype;
 use core::semantic::check;
 use core::semantic::env::Environment;
 use core::semantic::flatbuffers::semantic_generated::fbsemantic as fb;
 use core::semantic::flatbuffers::types::{build_env, build_type};
 use core::semantic::fresh::Fresher;
 use core::semantic::nodes::{infer_pkg_types, inject_pkg_types, Package};
-use core::semantic::sub::Substitution;
+use core::semantic::sub::{Substitutable, Substitution};
 
 pub use core::ast;
 pub use core::formatter;
@@ -169,6 +170,41 @@ pub unsafe extern "C" fn flux_ast_marshal_json(
     None
 }
 
+/// flux_ast_format formats the file of the given AST package as Flux
+/// source. The comments attached to the AST are kept in the output.
+/// The package must contain exactly one file.
+///
+/// # Safety
+///
+/// This function is unsafe because it dereferences raw pointers passed
+/// in as parameters. For example, if that pointer is NULL, undefined behavior
+/// could occur.
+#[no_mangle]
+pub unsafe extern "C" fn flux_ast_format(
+    ast_pkg: *const ast::Package,
+    buf: *mut flux_buffer_t,
+) -> Option<Box<ErrorHandle>> {
+    let ast_pkg = &*ast_pkg;
+    if ast_pkg.files.len() != 1 {
+        let err = Error::from(format!(
+            "expected exactly one file to format, found {}",
+            ast_pkg.files.len()
+        ));
+        return Some(Box::new(ErrorHandle { err: Box::new(err) }));
+    }
+    let data = match formatter::convert_to_string(&ast_pkg.files[0]) {
+        Ok(s) => s.into_bytes(),
+        Err(err) => {
+            let errh = ErrorHandle { err: Box::new(err) };
+            return Some(Box::new(errh));
+        }
+    };
+
+    (*buf).len = data.len();
+    (*buf).data = Box::into_raw(data.into_boxed_slice()) as *mut u8;
+    None
+}
+
 /// flux_ast_marshal_fb serializes the given AST package to a flatbuffer.
 ///
 /// # Safety
@@ -379,21 +415,28 @@ pub unsafe extern "C" fn flux_find_var_type(
 
 pub struct SemanticAnalyzer {
     f: Fresher,
+    prelude: Environment,
     env: Environment,
     imports: Environment,
 }
 
 fn new_semantic_analyzer() -> Result<SemanticAnalyzer, core::Error> {
-    let env = match prelude() {
-        Some(prelude) => Environment::new(prelude),
+    let prelude = match prelude() {
+        Some(prelude) => prelude,
         None => return Err(core::Error::from("missing prelude")),
     };
+    let env = Environment::new(prelude.clone());
     let imports = match imports() {
         Some(imports) => imports,
         None => return Err(core::Error::from("missing stdlib imports")),
     };
     let f = fresher();
-    Ok(SemanticAnalyzer { f, env, imports })
+    Ok(SemanticAnalyzer {
+        f,
+        prelude,
+        env,
+        imports,
+    })
 }
 
 impl SemanticAnalyzer {
@@ -432,6 +475,34 @@ impl SemanticAnalyzer {
         self.env = env;
         Ok(inject_pkg_types(sem_pkg, &sub))
     }
+
+    // analyze_package analyzes a package that is not part of the standard library
+    // and makes its type available to later packages that import it from the given path.
+    fn analyze_package(
+        &mut self,
+        path: &str,
+        ast_pkg: ast::Package,
+    ) -> Result<core::semantic::nodes::Package, core::Error> {
+        let errs = ast::check::check(ast::walk::Node::Package(&ast_pkg));
+        if !errs.is_empty() {
+            return Err(core::Error::from(format!("{}", &errs[0])));
+        }
+
+        let mut sem_pkg = core::semantic::convert::convert_with(ast_pkg, &mut self.f)?;
+        check::check(&sem_pkg)?;
+
+        // A package only sees the prelude and the packages it imports,
+        // not the values defined by previously analyzed snippets.
+        let env = Environment::new(self.prelude.clone());
+        let (env, sub) = infer_pkg_types(&mut sem_pkg, env, &mut self.f, &self.imports)?;
+        let env = env.apply(&sub);
+        let pkg_type = match build_polytype(env.values, &mut self.f) {
+            Ok(pkg_type) => pkg_type,
+            Err(err) => return Err(core::Error::from(err.msg)),
+        };
+        self.imports.add(path.to_owned(), pkg_type);
+        Ok(inject_pkg_types(sem_pkg, &sub))
+    }
 }
 
 /// Create a new semantic analyzer.
@@ -485,6 +556,44 @@ pub unsafe extern "C" fn flux_analyze_with(
     None
 }
 
+/// flux_analyze_package_with analyzes the package at the given import path
+/// using the analyzer and makes it available to later calls that import it.
+///
+/// # Safety
+///
+/// Ths function is unsafe because it dereferences raw pointers.
+#[no_mangle]
+#[allow(clippy::boxed_local)]
+pub unsafe extern "C" fn flux_analyze_package_with(
+    analyzer: *mut Result<SemanticAnalyzer, core::Error>,
+    cpath: *const c_char,
+    ast_pkg: Box<ast::Package>,
+    out_sem_pkg: *mut Option<Box<semantic::nodes::Package>>,
+) -> Option<Box<ErrorHandle>> {
+    let ast_pkg = *ast_pkg;
+    let path = String::from_utf8(CStr::from_ptr(cpath).to_bytes().to_vec()).unwrap();
+    let analyzer = match &mut *analyzer {
+        Ok(a) => a,
+        Err(err) => {
+            let errh = ErrorHandle {
+                err: Box::new(err.to_owned()),
+            };
+            return Some(Box::new(errh));
+        }
+    };
+
+    let sem_pkg = Box::new(match analyzer.analyze_package(&path, ast_pkg) {
+        Ok(sem_pkg) => sem_pkg,
+        Err(err) => {
+            let errh = ErrorHandle { err: Box::new(err) };
+            return Some(Box::new(errh));
+        }
+    });
+
+    *out_sem_pkg = Some(sem_pkg);
+    None
+}
+
 /// analyze consumes the given AST package and returns a semantic package
 /// that has been type-inferred.  This function is aware of the standard library
 /// and prelude.
@@ -145,6 +145,34 @@ func (p *Analyzer) Analyze(astPkg *ASTPkg) (*SemanticPkg, error) {
 	return pkg, nil
 }
 
+// AnalyzePackage analyzes a package that is not part of the standard library.
+// The type of the package is added to the analyzer so that packages and
+// snippets analyzed afterwards can import it from the given path.
+//
+// Like Analyze, AnalyzePackage will consume the AST.
+func (p *Analyzer) AnalyzePackage(path string, astPkg *ASTPkg) (*SemanticPkg, error) {
+	var semPkg *C.struct_flux_semantic_pkg_t
+	defer func() {
+		// See the equivalent defer in Analyze for why this is needed.
+		astPkg.ptr = nil
+	}()
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+	if err := C.flux_analyze_package_with(p.ptr, cpath, astPkg.ptr, &semPkg); err != nil {
+		defer C.flux_free_error(err)
+		cstr := C.flux_error_str(err)
+		defer C.flux_free_bytes(cstr)
+
+		str := C.GoString(cstr)
+		return nil, errors.New(codes.Invalid, str)
+	}
+	runtime.KeepAlive(p)
+
+	pkg := &SemanticPkg{ptr: semPkg}
+	runtime.SetFinalizer(pkg, free)
+	return pkg, nil
+}
+
 // Free frees the memory allocated by Rust for the semantic graph.
 func (p *Analyzer) Free() {
 	if p.ptr != nil {
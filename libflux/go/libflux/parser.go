@@ -66,6 +66,26 @@ func (p *ASTPkg) MarshalJSON() ([]byte, error) {
 	return data, nil
 }
 
+// Format formats the file of the package as Flux source.
+// The comments within the source are kept.
+// The package must contain exactly one file.
+func (p *ASTPkg) Format() (string, error) {
+	var buf C.struct_flux_buffer_t
+	if err := C.flux_ast_format(p.ptr, &buf); err != nil {
+		defer C.flux_free_error(err)
+		cstr := C.flux_error_str(err)
+		defer C.flux_free_bytes(cstr)
+
+		str := C.GoString(cstr)
+		return "", errors.Newf(codes.Invalid, "could not format AST: %v", str)
+	}
+	runtime.KeepAlive(p)
+	defer C.flux_free_bytes(buf.data)
+
+	data := C.GoBytes(unsafe.Pointer(buf.data), C.int(buf.len))
+	return string(data), nil
+}
+
 func (p *ASTPkg) MarshalFB() ([]byte, error) {
 	var buf C.struct_flux_buffer_t
 	if err := C.flux_ast_marshal_fb(p.ptr, &buf); err != nil {
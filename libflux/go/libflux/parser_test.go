@@ -55,6 +55,38 @@ func TestASTPkg_GetError(t *testing.T) {
 
 }
 
+func TestASTPkg_Format(t *testing.T) {
+	src := `// double returns twice the value.
+double  = (v)=>v*2
+
+x = double(v:1) // attach to y
+y=  x`
+	pkg := libflux.Parse("main.flux", src)
+	defer pkg.Free()
+	got, err := pkg.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `// double returns twice the value.
+double = (v) => v * 2
+x = double(v: 1)
+
+// attach to y
+y = x`
+	if want != got {
+		t.Errorf("unexpected formatted source -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	merged := libflux.ParseString(`x = 1`)
+	defer merged.Free()
+	if err := libflux.MergePackages(merged, libflux.ParseString(`y = 2`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := merged.Format(); err == nil {
+		t.Error("expected an error formatting a package with two files")
+	}
+}
+
 func TestMergePackages(t *testing.T) {
 	outPkg := libflux.ParseString(`
 package foo
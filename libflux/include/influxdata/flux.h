@@ -72,6 +72,14 @@ struct flux_error_t *flux_merge_ast_pkgs(struct flux_ast_pkg_t *, struct flux_as
 // using flux_free_error if it is non-null.
 struct flux_error_t *flux_ast_marshal_json(struct flux_ast_pkg_t *, struct flux_buffer_t *);
 
+// flux_ast_format will format the single file of the given AST package
+// as Flux source and fill in the given buffer with it. Comments attached
+// to the AST are kept. If successful, memory will be allocated for the data
+// within the buffer and it is the caller's responsibility to free this
+// data. If an error happens it will be returned. The error must be freed
+// using flux_free_error if it is non-null.
+struct flux_error_t *flux_ast_format(struct flux_ast_pkg_t *, struct flux_buffer_t *);
+
 // flux_ast_marshal_fb will marshal the given AST as a flatbuffer into
 // the given buffer. If successful, memory will be allocated for the data
 // within the buffer and it is the caller's responsibility to free this
@@ -102,6 +110,11 @@ void flux_free_semantic_analyzer(struct flux_semantic_analyzer_t *);
 // a semantic graph for that snippet.
 struct flux_error_t *flux_analyze_with(struct flux_semantic_analyzer_t *, struct flux_ast_pkg_t *, struct flux_semantic_pkg_t **);
 
+// flux_analyze_package_with will analyze the ast package at the given import path using the
+// flux_semantic_analyzer_t and produce a semantic graph for it. The type of the package is
+// added to the analyzer so it can be imported by snippets and packages analyzed afterwards.
+struct flux_error_t *flux_analyze_package_with(struct flux_semantic_analyzer_t *, const char *, struct flux_ast_pkg_t *, struct flux_semantic_pkg_t **);
+
 // flux_analyze analyzes the given AST and will populate the second pointer argument with
 // a pointer to the resulting semantic graph.
 // It is the caller's responsibility to free the resulting semantic graph with a call to flux_free_semantic_pkg().
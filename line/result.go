@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/execute"
@@ -33,17 +34,31 @@ type TimeProvider interface {
 	CurrentTime() values.Time
 }
 
+// NowTimeProvider provides wall clock time.
+type NowTimeProvider struct{}
+
+func (*NowTimeProvider) CurrentTime() values.Time {
+	return values.ConvertTime(time.Now())
+}
+
 // ResultDecoderConfig is the configuration for a result decoder.
 type ResultDecoderConfig struct {
 	Separator    byte
 	TimeProvider TimeProvider
+	// Allocator is used for the memory of the table.
+	// The memory is not tracked when it is nil.
+	Allocator *memory.Allocator
 }
 
 func (rd *ResultDecoder) Do(f func(flux.Table) error) error {
 	timeCol := flux.ColMeta{Label: "_time", Type: flux.TTime}
 	valueCol := flux.ColMeta{Label: "_value", Type: flux.TString}
 	key := execute.NewGroupKey(nil, nil)
-	builder := execute.NewColListTableBuilder(key, &memory.Allocator{})
+	alloc := rd.config.Allocator
+	if alloc == nil {
+		alloc = &memory.Allocator{}
+	}
+	builder := execute.NewColListTableBuilder(key, alloc)
 	timeIdx, err := builder.AddCol(timeCol)
 	if err != nil {
 		return err
@@ -0,0 +1,321 @@
+package lineprotocol
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/line"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
+)
+
+// ResultDecoder decodes line protocol into a single result.
+//
+// Tables use the same layout as data read from InfluxDB.
+// Each field of a series becomes a table grouped by _measurement, the tags and _field,
+// with the value of the field in the _value column.
+// Points without a timestamp are given the time of the TimeProvider.
+// Empty lines and comments are skipped.
+type ResultDecoder struct {
+	r     io.Reader
+	tp    line.TimeProvider
+	alloc *memory.Allocator
+}
+
+// NewResultDecoder creates a new line protocol result decoder
+// that uses the allocator for the memory of the tables.
+// The memory is not tracked when the allocator is nil.
+func NewResultDecoder(tp line.TimeProvider, alloc *memory.Allocator) *ResultDecoder {
+	if alloc == nil {
+		alloc = &memory.Allocator{}
+	}
+	return &ResultDecoder{tp: tp, alloc: alloc}
+}
+
+func (d *ResultDecoder) Decode(r io.Reader) (flux.Result, error) {
+	d.r = r
+	return d, nil
+}
+
+func (*ResultDecoder) Name() string {
+	return "_result"
+}
+
+func (d *ResultDecoder) Tables() flux.TableIterator {
+	return d
+}
+
+func (d *ResultDecoder) Do(f func(flux.Table) error) error {
+	// Tables are produced in the order their series first appear.
+	var (
+		order []*series
+		index = make(map[string]*series)
+	)
+
+	br := bufio.NewReader(d.r)
+	for n := 1; ; n++ {
+		s, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if text := strings.TrimSpace(s); text != "" && !strings.HasPrefix(text, "#") {
+			p, perr := parsePoint(text)
+			if perr != nil {
+				return errors.Wrapf(perr, codes.Invalid, "invalid line protocol at line %d", n)
+			}
+			if !p.hasTime {
+				p.time = d.tp.CurrentTime()
+			}
+			for _, fv := range p.fields {
+				key := p.seriesKey(fv.key)
+				ser, ok := index[key]
+				if !ok {
+					ser = &series{
+						measurement: p.measurement,
+						tags:        p.tags,
+						field:       fv.key,
+						typ:         flux.ColumnType(fv.value.Type()),
+					}
+					index[key] = ser
+					order = append(order, ser)
+				} else if typ := flux.ColumnType(fv.value.Type()); typ != ser.typ {
+					return errors.Newf(codes.Invalid, "field %q of measurement %q has conflicting types %v and %v", fv.key, p.measurement, ser.typ, typ)
+				}
+				ser.times = append(ser.times, p.time)
+				ser.values = append(ser.values, fv.value)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+
+	for _, ser := range order {
+		tbl, err := ser.table(d.alloc)
+		if err != nil {
+			return err
+		}
+		if err := f(tbl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// series holds the points of a single field of a series.
+type series struct {
+	measurement string
+	tags        []tag
+	field       string
+	typ         flux.ColType
+	times       []values.Time
+	values      []values.Value
+}
+
+func (s *series) table(alloc *memory.Allocator) (flux.Table, error) {
+	keyCols := make([]flux.ColMeta, 0, len(s.tags)+2)
+	keyValues := make([]values.Value, 0, len(s.tags)+2)
+	keyCols = append(keyCols, flux.ColMeta{Label: measurementLabel, Type: flux.TString})
+	keyValues = append(keyValues, values.NewString(s.measurement))
+	for _, t := range s.tags {
+		keyCols = append(keyCols, flux.ColMeta{Label: t.key, Type: flux.TString})
+		keyValues = append(keyValues, values.NewString(t.value))
+	}
+	keyCols = append(keyCols, flux.ColMeta{Label: fieldLabel, Type: flux.TString})
+	keyValues = append(keyValues, values.NewString(s.field))
+
+	builder := execute.NewColListTableBuilder(execute.NewGroupKey(keyCols, keyValues), alloc)
+	timeIdx, err := builder.AddCol(flux.ColMeta{Label: timeLabel, Type: flux.TTime})
+	if err != nil {
+		return nil, err
+	}
+	if err := execute.AddTableKeyCols(builder.Key(), builder); err != nil {
+		return nil, err
+	}
+	valueIdx, err := builder.AddCol(flux.ColMeta{Label: valueLabel, Type: s.typ})
+	if err != nil {
+		return nil, err
+	}
+	for i := range s.times {
+		if err := builder.AppendTime(timeIdx, s.times[i]); err != nil {
+			return nil, err
+		}
+		if err := execute.AppendKeyValues(builder.Key(), builder); err != nil {
+			return nil, err
+		}
+		if err := builder.AppendValue(valueIdx, s.values[i]); err != nil {
+			return nil, err
+		}
+	}
+	return builder.Table()
+}
+
+type tag struct {
+	key, value string
+}
+
+type pointField struct {
+	key   string
+	value values.Value
+}
+
+type point struct {
+	measurement string
+	tags        []tag
+	fields      []pointField
+	time        values.Time
+	hasTime     bool
+}
+
+// seriesKey identifies the series of the point and the field.
+func (p *point) seriesKey(field string) string {
+	var b strings.Builder
+	b.WriteString(strconv.Quote(p.measurement))
+	for _, t := range p.tags {
+		b.WriteString(",")
+		b.WriteString(strconv.Quote(t.key))
+		b.WriteString("=")
+		b.WriteString(strconv.Quote(t.value))
+	}
+	b.WriteString(" ")
+	b.WriteString(strconv.Quote(field))
+	return b.String()
+}
+
+// parsePoint parses a single line of line protocol.
+func parsePoint(s string) (*point, error) {
+	p := new(point)
+	var i int
+	p.measurement, i = scan(s, 0, ", ", ", ")
+	if p.measurement == "" {
+		return nil, errors.New(codes.Invalid, "missing measurement")
+	}
+
+	// Tags follow the measurement up to the first unescaped space.
+	for i < len(s) && s[i] == ',' {
+		var t tag
+		t.key, i = scan(s, i+1, "=", ",= ")
+		if i >= len(s) || s[i] != '=' || t.key == "" {
+			return nil, errors.New(codes.Invalid, "missing tag key")
+		}
+		t.value, i = scan(s, i+1, ", ", ",= ")
+		if t.value == "" {
+			return nil, errors.Newf(codes.Invalid, "missing value for tag %q", t.key)
+		}
+		p.tags = append(p.tags, t)
+	}
+	sort.SliceStable(p.tags, func(i, j int) bool {
+		return p.tags[i].key < p.tags[j].key
+	})
+
+	if i >= len(s) || s[i] != ' ' {
+		return nil, errors.New(codes.Invalid, "missing fields")
+	}
+	for {
+		var (
+			fv  pointField
+			err error
+		)
+		fv.key, i = scan(s, i+1, "=", ",= ")
+		if i >= len(s) || s[i] != '=' || fv.key == "" {
+			return nil, errors.New(codes.Invalid, "missing field key")
+		}
+		if fv.value, i, err = parseFieldValue(s, i+1); err != nil {
+			return nil, errors.Wrapf(err, codes.Invalid, "invalid value for field %q", fv.key)
+		}
+		p.fields = append(p.fields, fv)
+		if i >= len(s) || s[i] != ',' {
+			break
+		}
+	}
+
+	if i < len(s) {
+		ts := strings.TrimSpace(s[i:])
+		ns, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return nil, errors.Newf(codes.Invalid, "invalid timestamp %q", ts)
+		}
+		p.time, p.hasTime = values.Time(ns), true
+	}
+	return p, nil
+}
+
+// parseFieldValue parses the field value that starts at i
+// and returns the index following it.
+func parseFieldValue(s string, i int) (values.Value, int, error) {
+	if i < len(s) && s[i] == '"' {
+		var b strings.Builder
+		for j := i + 1; j < len(s); j++ {
+			switch c := s[j]; {
+			case c == '\\' && j+1 < len(s) && (s[j+1] == '"' || s[j+1] == '\\'):
+				b.WriteByte(s[j+1])
+				j++
+			case c == '"':
+				return values.NewString(b.String()), j + 1, nil
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return nil, len(s), errors.New(codes.Invalid, "unterminated string")
+	}
+
+	end := i
+	for end < len(s) && s[end] != ',' && s[end] != ' ' {
+		end++
+	}
+	raw := s[i:end]
+	switch {
+	case raw == "":
+		return nil, end, errors.New(codes.Invalid, "missing value")
+	case strings.HasSuffix(raw, "i"):
+		n, err := strconv.ParseInt(raw[:len(raw)-1], 10, 64)
+		if err != nil {
+			return nil, end, errors.Newf(codes.Invalid, "invalid integer %q", raw)
+		}
+		return values.NewInt(n), end, nil
+	case strings.HasSuffix(raw, "u"):
+		n, err := strconv.ParseUint(raw[:len(raw)-1], 10, 64)
+		if err != nil {
+			return nil, end, errors.Newf(codes.Invalid, "invalid unsigned integer %q", raw)
+		}
+		return values.NewUInt(n), end, nil
+	}
+	switch raw {
+	case "t", "T", "true", "True", "TRUE":
+		return values.NewBool(true), end, nil
+	case "f", "F", "false", "False", "FALSE":
+		return values.NewBool(false), end, nil
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, end, errors.Newf(codes.Invalid, "invalid float %q", raw)
+	}
+	return values.NewFloat(f), end, nil
+}
+
+// scan reads from i up to the first unescaped byte of stop and returns
+// the unescaped text along with the index of the stop byte.
+// A backslash escapes the bytes of escapes and is kept before any other byte.
+func scan(s string, i int, stop, escapes string) (string, int) {
+	var b strings.Builder
+	for ; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && strings.IndexByte(escapes, s[i+1]) >= 0 {
+			b.WriteByte(s[i+1])
+			i++
+			continue
+		}
+		if strings.IndexByte(stop, c) >= 0 {
+			break
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), i
+}
@@ -0,0 +1,204 @@
+package lineprotocol_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lineprotocol"
+	"github.com/influxdata/flux/mock"
+)
+
+func TestResultDecoder(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []*executetest.Table
+		err   error
+	}{
+		{
+			name: "series and fields",
+			input: `cpu,host=a,region=west usage=0.5,idle=true 10
+# comments are skipped
+
+cpu,region=west,host=a usage=0.75 20
+cpu,host=b usage=1 30
+`,
+			want: []*executetest.Table{
+				{
+					KeyCols: []string{"_measurement", "host", "region", "_field"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+						{Label: "region", Type: flux.TString},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{execute.Time(10), "usage", "cpu", "a", "west", 0.5},
+						{execute.Time(20), "usage", "cpu", "a", "west", 0.75},
+					},
+				},
+				{
+					KeyCols: []string{"_measurement", "host", "region", "_field"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+						{Label: "region", Type: flux.TString},
+						{Label: "_value", Type: flux.TBool},
+					},
+					Data: [][]interface{}{
+						{execute.Time(10), "idle", "cpu", "a", "west", true},
+					},
+				},
+				{
+					KeyCols: []string{"_measurement", "host", "_field"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{execute.Time(30), "usage", "cpu", "b", 1.0},
+					},
+				},
+			},
+		},
+		{
+			name:  "field types and escapes",
+			input: "my\\ measurement,tag\\=key=a\\,b i=-3i,u=4u,s=\"say \\\"hi\\\"\",f=F 5\n",
+			want: []*executetest.Table{
+				{
+					KeyCols: []string{"_measurement", "tag=key", "_field"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "tag=key", Type: flux.TString},
+						{Label: "_value", Type: flux.TInt},
+					},
+					Data: [][]interface{}{
+						{execute.Time(5), "i", "my measurement", "a,b", int64(-3)},
+					},
+				},
+				{
+					KeyCols: []string{"_measurement", "tag=key", "_field"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "tag=key", Type: flux.TString},
+						{Label: "_value", Type: flux.TUInt},
+					},
+					Data: [][]interface{}{
+						{execute.Time(5), "u", "my measurement", "a,b", uint64(4)},
+					},
+				},
+				{
+					KeyCols: []string{"_measurement", "tag=key", "_field"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "tag=key", Type: flux.TString},
+						{Label: "_value", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(5), "s", "my measurement", "a,b", `say "hi"`},
+					},
+				},
+				{
+					KeyCols: []string{"_measurement", "tag=key", "_field"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_field", Type: flux.TString},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "tag=key", Type: flux.TString},
+						{Label: "_value", Type: flux.TBool},
+					},
+					Data: [][]interface{}{
+						{execute.Time(5), "f", "my measurement", "a,b", false},
+					},
+				},
+			},
+		},
+		{
+			name:  "missing timestamps",
+			input: "mem free=1i\nmem free=2i\n",
+			want: []*executetest.Table{{
+				KeyCols: []string{"_measurement", "_field"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_field", Type: flux.TString},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "_value", Type: flux.TInt},
+				},
+				Data: [][]interface{}{
+					{execute.Time(0), "free", "mem", int64(1)},
+					{execute.Time(1), "free", "mem", int64(2)},
+				},
+			}},
+		},
+		{
+			name:  "conflicting types",
+			input: "mem free=1i 1\nmem free=2 2\n",
+			err:   errors.New(`field "free" of measurement "mem" has conflicting types int and float`),
+		},
+		{
+			name:  "missing fields",
+			input: "mem 1\n",
+			err:   errors.New("invalid line protocol at line 1: missing field key"),
+		},
+		{
+			name:  "invalid timestamp",
+			input: "mem free=1i 1\nmem free=2i yesterday\n",
+			err:   errors.New(`invalid line protocol at line 2: invalid timestamp "yesterday"`),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dec := lineprotocol.NewResultDecoder(&mock.AscendingTimeProvider{}, nil)
+			result, err := dec.Decode(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []*executetest.Table
+			err = result.Tables().Do(func(tbl flux.Table) error {
+				cp, err := executetest.ConvertTable(tbl)
+				if err != nil {
+					return err
+				}
+				got = append(got, cp)
+				return nil
+			})
+			if tc.err != nil {
+				if err == nil {
+					t.Fatalf("expected error %q", tc.err)
+				}
+				if got, want := err.Error(), tc.err.Error(); got != want {
+					t.Fatalf("unexpected error -want/+got:\n- %q\n+ %q", want, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			executetest.NormalizeTables(got)
+			executetest.NormalizeTables(tc.want)
+			if !cmp.Equal(tc.want, got) {
+				t.Errorf("unexpected tables -want/+got:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
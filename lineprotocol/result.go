@@ -0,0 +1,264 @@
+// Package lineprotocol implements an encoder that writes Flux results
+// using the InfluxDB line protocol.
+package lineprotocol
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/iocounter"
+	protocol "github.com/influxdata/line-protocol"
+)
+
+const (
+	measurementLabel = "_measurement"
+	fieldLabel       = "_field"
+	startLabel       = execute.DefaultStartColLabel
+	stopLabel        = execute.DefaultStopColLabel
+	timeLabel        = execute.DefaultTimeColLabel
+	valueLabel       = execute.DefaultValueColLabel
+)
+
+// ResultEncoder encodes a result as line protocol.
+//
+// Tables are converted with the same conventions used when writing to InfluxDB.
+// The measurement is read from the _measurement column and the timestamp from the _time column.
+// String columns in the group key, other than _measurement, _field, _start and _stop, become tags.
+// If the table has a _field column, each row produces a single field
+// named by _field with the value of the _value column.
+// Otherwise, every other column that is not part of the group key becomes a field.
+// Null tags and fields are omitted.
+type ResultEncoder struct{}
+
+// NewResultEncoder creates a new line protocol result encoder.
+func NewResultEncoder() *ResultEncoder {
+	return &ResultEncoder{}
+}
+
+type lineProtocolEncoderError struct {
+	err error
+}
+
+func (e *lineProtocolEncoderError) Error() string {
+	return fmt.Sprintf("line protocol encoder error: %s", e.err.Error())
+}
+
+func (e *lineProtocolEncoderError) IsEncoderError() bool {
+	return true
+}
+
+func (e *lineProtocolEncoderError) Unwrap() error {
+	return e.err
+}
+
+func wrapEncodingError(err error) error {
+	if err == nil {
+		return err
+	}
+	return &lineProtocolEncoderError{err: err}
+}
+
+func (e *ResultEncoder) Encode(w io.Writer, result flux.Result) (int64, error) {
+	writeCounter := &iocounter.Writer{Writer: w}
+	enc := protocol.NewEncoder(writeCounter)
+	enc.FailOnFieldErr(true)
+	enc.SetFieldSortOrder(protocol.SortFields)
+	enc.SetFieldTypeSupport(protocol.UintSupport)
+
+	err := result.Tables().Do(func(tbl flux.Table) error {
+		return EncodeTable(tbl, func(m protocol.Metric) error {
+			_, err := enc.Encode(m)
+			return wrapEncodingError(err)
+		})
+	})
+	return writeCounter.Count(), err
+}
+
+// EncodeError writes the error as a comment, which is the only
+// way that line protocol can carry text besides metrics.
+// Each line of the error message is written as its own comment.
+func (e *ResultEncoder) EncodeError(w io.Writer, err error) error {
+	var buf bytes.Buffer
+	for _, line := range strings.Split(err.Error(), "\n") {
+		buf.WriteString("# error: ")
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	_, werr := w.Write(buf.Bytes())
+	return werr
+}
+
+// NewMultiResultEncoder creates a MultiResultEncoder that writes
+// the rows of every result as line protocol.
+func NewMultiResultEncoder() flux.MultiResultEncoder {
+	return &flux.DelimitedMultiResultEncoder{
+		Encoder: NewResultEncoder(),
+	}
+}
+
+// EncodeTable converts each row of the table into a metric
+// and passes it to the function f.
+// The metric is reused between calls and must not be retained by f.
+// Rows that do not produce any fields are skipped.
+func EncodeTable(tbl flux.Table, f func(m protocol.Metric) error) error {
+	cols := tbl.Cols()
+	measurementIdx := execute.ColIdx(measurementLabel, cols)
+	if measurementIdx < 0 {
+		return errors.Newf(codes.FailedPrecondition, "table is missing the %q column", measurementLabel)
+	} else if cols[measurementIdx].Type != flux.TString {
+		return errors.Newf(codes.FailedPrecondition, "column %q must be of type %s", measurementLabel, flux.TString)
+	}
+	timeIdx := execute.ColIdx(timeLabel, cols)
+	if timeIdx < 0 {
+		return errors.Newf(codes.FailedPrecondition, "table is missing the %q column", timeLabel)
+	} else if cols[timeIdx].Type != flux.TTime {
+		return errors.Newf(codes.FailedPrecondition, "column %q must be of type %s", timeLabel, flux.TTime)
+	}
+
+	fieldIdx, valueIdx := execute.ColIdx(fieldLabel, cols), -1
+	if fieldIdx >= 0 {
+		if cols[fieldIdx].Type != flux.TString {
+			return errors.Newf(codes.FailedPrecondition, "column %q must be of type %s", fieldLabel, flux.TString)
+		}
+		valueIdx = execute.ColIdx(valueLabel, cols)
+		if valueIdx < 0 {
+			return errors.Newf(codes.FailedPrecondition, "table has a %q column but is missing the %q column", fieldLabel, valueLabel)
+		}
+	}
+
+	// Partition the remaining columns into tags and fields.
+	key := tbl.Key()
+	var tagIdxs, fieldIdxs []int
+	for j, c := range cols {
+		switch c.Label {
+		case measurementLabel, timeLabel, fieldLabel, startLabel, stopLabel:
+			continue
+		}
+		if key.HasCol(c.Label) {
+			if c.Type == flux.TString {
+				tagIdxs = append(tagIdxs, j)
+			}
+			continue
+		}
+		if fieldIdx < 0 {
+			fieldIdxs = append(fieldIdxs, j)
+		}
+	}
+	sort.Slice(tagIdxs, func(i, j int) bool {
+		return cols[tagIdxs[i]].Label < cols[tagIdxs[j]].Label
+	})
+
+	m := &metric{}
+	return tbl.Do(func(cr flux.ColReader) error {
+		l := cr.Len()
+		for i := 0; i < l; i++ {
+			m.tags = m.tags[:0]
+			m.fields = m.fields[:0]
+
+			measurements := cr.Strings(measurementIdx)
+			if !measurements.IsValid(i) {
+				return errors.Newf(codes.FailedPrecondition, "null value in the %q column", measurementLabel)
+			}
+			m.name = measurements.ValueString(i)
+
+			times := cr.Times(timeIdx)
+			if !times.IsValid(i) {
+				return errors.Newf(codes.FailedPrecondition, "null value in the %q column", timeLabel)
+			}
+			m.t = execute.Time(times.Value(i)).Time()
+
+			for _, j := range tagIdxs {
+				if vs := cr.Strings(j); vs.IsValid(i) {
+					m.tags = append(m.tags, &protocol.Tag{Key: cols[j].Label, Value: vs.ValueString(i)})
+				}
+			}
+
+			if fieldIdx >= 0 {
+				if fields := cr.Strings(fieldIdx); fields.IsValid(i) {
+					if v, ok := fieldValue(cr, i, valueIdx); ok {
+						m.fields = append(m.fields, &protocol.Field{Key: fields.ValueString(i), Value: v})
+					}
+				}
+			} else {
+				for _, j := range fieldIdxs {
+					if v, ok := fieldValue(cr, i, j); ok {
+						m.fields = append(m.fields, &protocol.Field{Key: cols[j].Label, Value: v})
+					}
+				}
+			}
+
+			if len(m.fields) == 0 {
+				continue
+			}
+			if err := f(m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// fieldValue returns the value in the column as a type
+// supported by the line protocol encoder.
+// Time values are written as RFC3339 strings.
+func fieldValue(cr flux.ColReader, i, j int) (interface{}, bool) {
+	switch typ := cr.Cols()[j].Type; typ {
+	case flux.TBool:
+		if vs := cr.Bools(j); vs.IsValid(i) {
+			return vs.Value(i), true
+		}
+	case flux.TInt:
+		if vs := cr.Ints(j); vs.IsValid(i) {
+			return vs.Value(i), true
+		}
+	case flux.TUInt:
+		if vs := cr.UInts(j); vs.IsValid(i) {
+			return vs.Value(i), true
+		}
+	case flux.TFloat:
+		if vs := cr.Floats(j); vs.IsValid(i) {
+			return vs.Value(i), true
+		}
+	case flux.TString:
+		if vs := cr.Strings(j); vs.IsValid(i) {
+			return vs.ValueString(i), true
+		}
+	case flux.TTime:
+		if vs := cr.Times(j); vs.IsValid(i) {
+			return execute.Time(vs.Value(i)).Time().Format(time.RFC3339Nano), true
+		}
+	}
+	return nil, false
+}
+
+// metric implements the protocol.Metric interface.
+type metric struct {
+	name   string
+	tags   []*protocol.Tag
+	fields []*protocol.Field
+	t      time.Time
+}
+
+func (m *metric) Time() time.Time {
+	return m.t
+}
+
+func (m *metric) Name() string {
+	return m.name
+}
+
+func (m *metric) TagList() []*protocol.Tag {
+	return m.tags
+}
+
+func (m *metric) FieldList() []*protocol.Field {
+	return m.fields
+}
@@ -0,0 +1,159 @@
+package lineprotocol_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andreyvit/diff"
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lineprotocol"
+	"github.com/influxdata/flux/values"
+)
+
+func TestMultiResultEncoder(t *testing.T) {
+	testCases := []struct {
+		name    string
+		results flux.ResultIterator
+		encoded []byte
+		err     error
+	}{
+		{
+			name: "field and value columns",
+			results: flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+				Nm: "_result",
+				Tbls: []*executetest.Table{{
+					KeyCols: []string{"_start", "_stop", "_measurement", "_field", "host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_start", Type: flux.TTime},
+						{Label: "_stop", Type: flux.TTime},
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "_field", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{
+							values.ConvertTime(time.Date(2018, 4, 17, 0, 0, 0, 0, time.UTC)),
+							values.ConvertTime(time.Date(2018, 4, 17, 0, 5, 0, 0, time.UTC)),
+							values.ConvertTime(time.Date(2018, 4, 17, 0, 0, 0, 0, time.UTC)),
+							"cpu",
+							"usage_user",
+							"A",
+							42.0,
+						},
+						{
+							values.ConvertTime(time.Date(2018, 4, 17, 0, 0, 0, 0, time.UTC)),
+							values.ConvertTime(time.Date(2018, 4, 17, 0, 5, 0, 0, time.UTC)),
+							values.ConvertTime(time.Date(2018, 4, 17, 0, 0, 1, 0, time.UTC)),
+							"cpu",
+							"usage_user",
+							"A",
+							nil,
+						},
+					},
+				}},
+			}}),
+			encoded: []byte("cpu,host=A usage_user=42 1523923200000000000\n"),
+		},
+		{
+			name: "pivoted columns",
+			results: flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+				Nm: "_result",
+				Tbls: []*executetest.Table{{
+					KeyCols: []string{"_measurement", "region", "host"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "region", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+						{Label: "used", Type: flux.TInt},
+						{Label: "free", Type: flux.TUInt},
+						{Label: "status", Type: flux.TString},
+						{Label: "ok", Type: flux.TBool},
+					},
+					Data: [][]interface{}{
+						{
+							values.ConvertTime(time.Date(2018, 4, 17, 0, 0, 0, 0, time.UTC)),
+							"mem",
+							"west",
+							"A",
+							int64(10),
+							uint64(20),
+							"healthy",
+							true,
+						},
+					},
+				}},
+			}}),
+			encoded: []byte("mem,host=A,region=west free=20u,ok=true,status=\"healthy\",used=10i 1523923200000000000\n"),
+		},
+		{
+			name: "error results",
+			results: flux.NewSliceResultIterator([]flux.Result{
+				&executetest.Result{
+					Nm: "_result",
+					Tbls: []*executetest.Table{{
+						ColMeta: []flux.ColMeta{
+							{Label: "_time", Type: flux.TTime},
+							{Label: "_measurement", Type: flux.TString},
+							{Label: "_value", Type: flux.TInt},
+						},
+						Data: [][]interface{}{
+							{values.ConvertTime(time.Date(2018, 4, 17, 0, 0, 0, 0, time.UTC)), "cpu", int64(1)},
+						},
+					}},
+				},
+				&executetest.Result{
+					Nm:  "mean",
+					Err: errors.New("test error\nsecond line"),
+				},
+			}),
+			encoded: []byte("cpu _value=1i 1523923200000000000\n# error: test error\n# error: second line\n"),
+		},
+		{
+			name: "missing measurement",
+			results: flux.NewSliceResultIterator([]flux.Result{&executetest.Result{
+				Nm: "_result",
+				Tbls: []*executetest.Table{{
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+					},
+					Data: [][]interface{}{
+						{values.ConvertTime(time.Date(2018, 4, 17, 0, 0, 0, 0, time.UTC)), 1.0},
+					},
+				}},
+			}}),
+			err: errors.New(`table is missing the "_measurement" column`),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			encoder := lineprotocol.NewMultiResultEncoder()
+			var got bytes.Buffer
+			n, err := encoder.Encode(&got, tc.results)
+			if err != nil && tc.err != nil {
+				if err.Error() != tc.err.Error() {
+					t.Errorf("unexpected error want: %s\n got: %s\n", tc.err.Error(), err.Error())
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error want: none\n got: %s\n", err.Error())
+			} else if tc.err != nil {
+				t.Errorf("unexpected error want: %s\n got: none", tc.err.Error())
+			}
+
+			if g, w := got.String(), string(tc.encoded); g != w {
+				t.Errorf("unexpected encoding -want/+got:\n%s", diff.LineDiff(w, g))
+			}
+			if g, w := n, int64(len(tc.encoded)); g != w {
+				t.Errorf("unexpected encoding count -want/+got:\n%s", cmp.Diff(w, g))
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package lsp
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/libflux/go/libflux"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+)
+
+// analyze parses and type checks the document
+// and records the diagnostics that were found.
+func (s *Server) analyze(d *document) {
+	d.ast, d.sem, d.diagnostics = nil, nil, nil
+	d.importer = runtime.StdLib()
+
+	hdl := libflux.Parse("", d.text)
+	pkg, err := toAST(hdl)
+	if err != nil {
+		hdl.Free()
+		d.diagnostics = []Diagnostic{newDiagnostic(Range{}, err.Error())}
+		return
+	}
+	d.ast = pkg
+	if ast.Check(pkg) > 0 {
+		hdl.Free()
+		d.diagnostics = d.parseDiagnostics()
+		return
+	}
+
+	var sem *semantic.Package
+	if len(s.FluxPath) > 0 {
+		imp := runtime.NewModuleImporter(s.FluxPath)
+		d.importer = imp
+		sem, err = imp.Analyze(hdl)
+	} else {
+		sem, err = runtime.AnalyzePackage(hdl)
+	}
+	if err != nil {
+		d.diagnostics = d.analysisDiagnostics(err)
+		return
+	}
+	d.sem = sem
+	d.types = make(map[string]semantic.MonoType)
+	for _, f := range sem.Files {
+		for _, stmt := range f.Body {
+			if a, ok := stmt.(*semantic.NativeVariableAssignment); ok {
+				d.types[a.Identifier.Name] = a.Init.TypeOf()
+			}
+		}
+	}
+}
+
+func toAST(hdl *libflux.ASTPkg) (*ast.Package, error) {
+	bs, err := hdl.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	node, err := ast.UnmarshalNode(bs)
+	if err != nil {
+		return nil, err
+	}
+	pkg, ok := node.(*ast.Package)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "expected a package, got %s", node.Type())
+	}
+	return pkg, nil
+}
+
+func newDiagnostic(rng Range, msg string) Diagnostic {
+	return Diagnostic{
+		Range:    rng,
+		Severity: SeverityError,
+		Source:   "flux",
+		Message:  msg,
+	}
+}
+
+// parseDiagnostics returns a diagnostic for each
+// syntax error annotated on the nodes of the AST.
+func (d *document) parseDiagnostics() []Diagnostic {
+	var diagnostics []Diagnostic
+	ast.Visit(d.ast, func(n ast.Node) {
+		for _, err := range n.Errs() {
+			diagnostics = append(diagnostics, newDiagnostic(d.toRange(n.Location()), err.Msg))
+		}
+	})
+	return diagnostics
+}
+
+// errorLocation matches the location that precedes
+// each message of a semantic analysis error.
+var errorLocation = regexp.MustCompile(`@(\d+):(\d+)-(\d+):(\d+): `)
+
+// analysisDiagnostics converts the error returned by semantic
+// analysis into diagnostics. The error reports each problem
+// as a message prefixed by its location in the source.
+func (d *document) analysisDiagnostics(err error) []Diagnostic {
+	msg := err.Error()
+	matches := errorLocation.FindAllStringSubmatchIndex(msg, -1)
+	if len(matches) == 0 {
+		return []Diagnostic{newDiagnostic(Range{}, msg)}
+	}
+
+	diagnostics := make([]Diagnostic, 0, len(matches))
+	for i, m := range matches {
+		end := len(msg)
+		if i+1 < len(matches) {
+			// The next message starts at the line that holds the next location.
+			end = matches[i+1][0]
+			if nl := strings.LastIndexByte(msg[m[1]:end], '\n'); nl >= 0 {
+				end = m[1] + nl
+			}
+		}
+		var n [4]int
+		for j := range n {
+			n[j], _ = strconv.Atoi(msg[m[2+2*j]:m[3+2*j]])
+		}
+		loc := ast.SourceLocation{
+			Start: ast.Position{Line: n[0], Column: n[1]},
+			End:   ast.Position{Line: n[2], Column: n[3]},
+		}
+		diagnostics = append(diagnostics, newDiagnostic(d.toRange(loc), strings.TrimSpace(msg[m[1]:end])))
+	}
+	return diagnostics
+}
+
+// hover describes the type of the expression
+// or binding at the position.
+func (d *document) hover(p Position) *Hover {
+	if d.sem == nil {
+		return nil
+	}
+	v := &hoverVisitor{pos: d.fromPosition(p)}
+	semantic.Walk(v, d.sem)
+	if v.label == "" {
+		return nil
+	}
+	rng := d.toRange(v.loc)
+	return &Hover{
+		Contents: markupContent{
+			Kind:  "markdown",
+			Value: "```flux\n" + v.label + ": " + v.typ + "\n```",
+		},
+		Range: &rng,
+	}
+}
+
+// hoverVisitor finds the innermost node that contains
+// the position and that has a name and a type.
+type hoverVisitor struct {
+	pos ast.Position
+	// funcs holds the functions that enclose the visited node.
+	funcs []*semantic.FunctionExpression
+
+	label string
+	typ   string
+	loc   ast.SourceLocation
+}
+
+func (v *hoverVisitor) Visit(node semantic.Node) semantic.Visitor {
+	if !contains(node.Location(), v.pos) {
+		switch node.(type) {
+		case *semantic.Package, *semantic.File:
+			// The location of a package or file
+			// may not cover all of its statements.
+		default:
+			return nil
+		}
+	}
+
+	switch n := node.(type) {
+	case *semantic.FunctionExpression:
+		v.funcs = append(v.funcs, n)
+	case *semantic.IdentifierExpression:
+		v.set(n.Name, n.TypeOf().CanonicalString(), n.Location())
+	case *semantic.MemberExpression:
+		v.set(n.Property, n.TypeOf().CanonicalString(), n.Location())
+	case *semantic.NativeVariableAssignment:
+		if contains(n.Identifier.Location(), v.pos) {
+			typ := n.Init.TypeOf().CanonicalString()
+			if !n.Typ.IsNil() && n.Typ.NumVars() > 0 {
+				typ = n.Typ.CanonicalString()
+			}
+			v.set(n.Identifier.Name, typ, n.Identifier.Location())
+		}
+	case *semantic.FunctionParameter:
+		if len(v.funcs) > 0 && contains(n.Key.Location(), v.pos) {
+			typ := argumentType(v.funcs[len(v.funcs)-1].TypeOf(), n.Key.Name)
+			v.set(n.Key.Name, typ, n.Key.Location())
+		}
+	}
+	return v
+}
+
+func (v *hoverVisitor) Done(node semantic.Node) {
+	if fn, ok := node.(*semantic.FunctionExpression); ok && len(v.funcs) > 0 && v.funcs[len(v.funcs)-1] == fn {
+		v.funcs = v.funcs[:len(v.funcs)-1]
+	}
+}
+
+func (v *hoverVisitor) set(label, typ string, loc ast.SourceLocation) {
+	if v.label != "" && !smaller(loc, v.loc) {
+		return
+	}
+	v.label, v.typ, v.loc = label, typ, loc
+}
+
+// argumentType returns the type of the named argument
+// of the function type as a string.
+func argumentType(fn semantic.MonoType, name string) string {
+	args, err := fn.SortedArguments()
+	if err != nil {
+		return ""
+	}
+	for _, arg := range args {
+		if string(arg.Name()) != name {
+			continue
+		}
+		typ, err := arg.TypeOf()
+		if err != nil {
+			return ""
+		}
+		return typ.CanonicalString()
+	}
+	return ""
+}
+
+// importPath returns the path of the package imported
+// with the given name by the document.
+func (d *document) importPath(name string) (string, bool) {
+	if d.ast == nil {
+		return "", false
+	}
+	for _, f := range d.ast.Files {
+		for _, imp := range f.Imports {
+			if imp.Path != nil && importName(imp) == name {
+				return imp.Path.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// importName returns the name that an import declaration binds.
+func importName(imp *ast.ImportDeclaration) string {
+	if imp.As != nil && imp.As.Name != "" {
+		return imp.As.Name
+	}
+	if imp.Path == nil {
+		return ""
+	}
+	p := imp.Path.Value
+	return p[strings.LastIndexByte(p, '/')+1:]
+}
+
+// importPackage returns the package imported from the path.
+func (d *document) importPackage(path string) (*interpreter.Package, error) {
+	imp := d.importer
+	if imp == nil {
+		imp = runtime.StdLib()
+	}
+	return imp.ImportPackageObject(path)
+}
@@ -0,0 +1,120 @@
+package lsp
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/complete"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+var (
+	// memberPrefix matches a member expression that
+	// is being written, such as `strings.to`.
+	memberPrefix = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\.([A-Za-z_][A-Za-z0-9_]*)?$`)
+	// identifierPrefix matches an identifier that is being written.
+	identifierPrefix = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// completion returns the completion items for the position.
+// After the name of an imported package and a dot, the items
+// are the members of the package. Otherwise they are the names
+// in scope: the prelude, the imports and the bindings of the document.
+func (d *document) completion(p Position) []CompletionItem {
+	prefix := d.prefix(p)
+	if m := memberPrefix.FindStringSubmatch(prefix); m != nil {
+		path, ok := d.importPath(m[1])
+		if !ok {
+			return nil
+		}
+		pkg, err := d.importPackage(path)
+		if err != nil {
+			return nil
+		}
+		scope := values.NewScope()
+		pkg.Range(scope.Set)
+		return completionItems(complete.NewCompleter(scope), m[2])
+	}
+
+	word := identifierPrefix.FindString(prefix)
+	items := completionItems(complete.NewCompleter(runtime.Prelude()), word)
+	if d.ast == nil {
+		return items
+	}
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		seen[item.Label] = true
+	}
+	for _, b := range d.visibleBindings(d.fromPosition(p)) {
+		if seen[b.name] || !strings.HasPrefix(b.name, word) {
+			continue
+		}
+		seen[b.name] = true
+		item := CompletionItem{Label: b.name, Kind: KindVariable}
+		if b.importPath != "" {
+			item.Kind, item.Detail = KindModule, b.importPath
+		} else if typ, ok := d.types[b.name]; ok {
+			item.Detail = typ.String()
+			if typ.Nature() == semantic.Function {
+				item.Kind = KindFunction
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// completionItems returns an item for each name known
+// to the completer that starts with the prefix.
+func completionItems(c complete.Completer, prefix string) []CompletionItem {
+	var items []CompletionItem
+	for _, name := range c.Names() {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		v, err := c.Value(name)
+		if err != nil {
+			continue
+		}
+		item := CompletionItem{
+			Label:  name,
+			Kind:   KindVariable,
+			Detail: v.Type().String(),
+		}
+		if s, err := c.FunctionSuggestion(name); err == nil {
+			item.Kind = KindFunction
+			item.Detail = "(" + formatParams(s.Params) + ")"
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// formatParams formats the parameters of a function
+// suggestion in the order of their names.
+func formatParams(params map[string]string) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		names[i] = name + ": " + params[name]
+	}
+	return strings.Join(names, ", ")
+}
+
+// visibleBindings returns the bindings of the document
+// that are in scope at the position, innermost first.
+func (d *document) visibleBindings(pos ast.Position) []binding {
+	scopes := d.nodePath(pos)
+	var bs []binding
+	for i := len(scopes) - 1; i >= 0; i-- {
+		bs = append(bs, bindings(scopes[i])...)
+	}
+	return bs
+}
@@ -0,0 +1,76 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// conn reads and writes JSON-RPC messages framed
+// with the headers of the base protocol.
+type conn struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{r: bufio.NewReader(r), w: w}
+}
+
+// read reads the content of the next message.
+// It returns io.EOF when there are no more messages.
+func (c *conn) read() ([]byte, error) {
+	length := -1
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" && length < 0 {
+				return nil, io.EOF
+			}
+			return nil, errors.Wrap(err, codes.Invalid, "failed to read message header")
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		i := strings.IndexByte(line, ':')
+		if i < 0 {
+			return nil, errors.Newf(codes.Invalid, "invalid message header %q", line)
+		}
+		name, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		if strings.EqualFold(name, "Content-Length") {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return nil, errors.Newf(codes.Invalid, "invalid content length %q", value)
+			}
+			length = n
+		}
+	}
+	if length < 0 {
+		return nil, errors.New(codes.Invalid, "message is missing the Content-Length header")
+	}
+	content := make([]byte, length)
+	if _, err := io.ReadFull(c.r, content); err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "failed to read message content")
+	}
+	return content, nil
+}
+
+// write writes v as the content of a message.
+func (c *conn) write(v interface{}) error {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	header := "Content-Length: " + strconv.Itoa(len(content)) + "\r\n\r\n"
+	if _, err := io.WriteString(c.w, header); err != nil {
+		return err
+	}
+	_, err = c.w.Write(content)
+	return err
+}
@@ -0,0 +1,351 @@
+package lsp
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/internal/token"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/runtime"
+)
+
+// preludePackages are the packages whose
+// members are in scope in every document.
+var preludePackages = []string{
+	"universe",
+	"influxdata/influxdb",
+}
+
+// binding is a name bound by a declaration.
+type binding struct {
+	name string
+	// id is the identifier of the declaration.
+	id *ast.Identifier
+	// importPath is set when the binding is an import.
+	importPath string
+}
+
+// definition returns the location where the identifier
+// at the position is defined.
+func (s *Server) definition(d *document, p Position) *Location {
+	if d.ast == nil {
+		return nil
+	}
+	path := d.nodePath(d.fromPosition(p))
+	if len(path) < 2 {
+		return nil
+	}
+	id, ok := path[len(path)-1].(*ast.Identifier)
+	if !ok {
+		return nil
+	}
+	scopes := path[:len(path)-1]
+
+	switch parent := path[len(path)-2].(type) {
+	case *ast.VariableAssignment:
+		if parent.ID == id {
+			return d.location(id)
+		}
+	case *ast.BuiltinStatement, *ast.ImportDeclaration:
+		return d.location(id)
+	case *ast.Property:
+		if parent.Key == ast.PropertyKey(id) {
+			if len(path) >= 3 {
+				if _, ok := path[len(path)-3].(*ast.FunctionExpression); ok {
+					return d.location(id)
+				}
+			}
+			if parent.Value != nil {
+				// The key of a property is not a reference
+				// unless the property has no value.
+				return nil
+			}
+		}
+	case *ast.MemberExpression:
+		if parent.Property == ast.PropertyKey(id) {
+			obj, ok := parent.Object.(*ast.Identifier)
+			if !ok {
+				return nil
+			}
+			b, ok := lookup(scopes, obj.Name)
+			if !ok || b.importPath == "" {
+				return nil
+			}
+			return s.packageDefinition(b.importPath, id.Name)
+		}
+	}
+
+	if b, ok := lookup(scopes, id.Name); ok {
+		return d.location(b.id)
+	}
+	for _, pkg := range preludePackages {
+		if loc := s.packageDefinition(pkg, id.Name); loc != nil {
+			return loc
+		}
+	}
+	return nil
+}
+
+func (d *document) location(id *ast.Identifier) *Location {
+	return &Location{
+		URI:   d.uri,
+		Range: d.toRange(id.Location()),
+	}
+}
+
+// lookup finds the binding for the name in the scopes
+// that enclose a reference, from the innermost scope outwards.
+func lookup(scopes []ast.Node, name string) (binding, bool) {
+	for i := len(scopes) - 1; i >= 0; i-- {
+		for _, b := range bindings(scopes[i]) {
+			if b.name == name {
+				return b, true
+			}
+		}
+	}
+	return binding{}, false
+}
+
+// bindings returns the names bound within
+// the scope introduced by the node.
+func bindings(node ast.Node) []binding {
+	var bs []binding
+	switch n := node.(type) {
+	case *ast.FunctionExpression:
+		for _, p := range n.Params {
+			if id, ok := p.Key.(*ast.Identifier); ok {
+				bs = append(bs, binding{name: id.Name, id: id})
+			}
+		}
+	case *ast.Block:
+		bs = statementBindings(n.Body)
+	case *ast.File:
+		bs = statementBindings(n.Body)
+		for _, imp := range n.Imports {
+			if imp.Path == nil {
+				continue
+			}
+			id := imp.As
+			if id == nil {
+				id = &ast.Identifier{BaseNode: imp.Path.BaseNode}
+			}
+			bs = append(bs, binding{
+				name:       importName(imp),
+				id:         id,
+				importPath: imp.Path.Value,
+			})
+		}
+	}
+	return bs
+}
+
+func statementBindings(body []ast.Statement) []binding {
+	var bs []binding
+	for _, stmt := range body {
+		if id := declaredIdentifier(stmt); id != nil {
+			bs = append(bs, binding{name: id.Name, id: id})
+		}
+	}
+	return bs
+}
+
+// declaredIdentifier returns the identifier
+// declared by the statement, if any.
+func declaredIdentifier(stmt ast.Statement) *ast.Identifier {
+	switch s := stmt.(type) {
+	case *ast.VariableAssignment:
+		return s.ID
+	case *ast.OptionStatement:
+		if a, ok := s.Assignment.(*ast.VariableAssignment); ok {
+			return a.ID
+		}
+	case *ast.BuiltinStatement:
+		return s.ID
+	}
+	return nil
+}
+
+// nodePath returns the nodes from the root of the AST
+// to the innermost node that contains the position.
+func (d *document) nodePath(pos ast.Position) []ast.Node {
+	v := &pathVisitor{pos: pos}
+	ast.Walk(v, d.ast)
+	return v.path
+}
+
+type pathVisitor struct {
+	pos   ast.Position
+	stack []ast.Node
+	path  []ast.Node
+}
+
+func (v *pathVisitor) Visit(node ast.Node) ast.Visitor {
+	switch node.(type) {
+	case *ast.Package, *ast.File:
+		// The location of a package or file
+		// may not cover all of its statements.
+	default:
+		if !contains(node.Location(), v.pos) {
+			return nil
+		}
+	}
+	v.stack = append(v.stack, node)
+	if len(v.stack) > len(v.path) {
+		v.path = append(v.path[:0], v.stack...)
+	}
+	return v
+}
+
+func (v *pathVisitor) Done(node ast.Node) {
+	if n := len(v.stack); n > 0 && v.stack[n-1] == node {
+		v.stack = v.stack[:n-1]
+	}
+}
+
+// packageDefinition returns the location of the declaration of
+// the named member of the package with the import path.
+// Packages are looked up in the standard library and then
+// in the directories of the Flux path.
+func (s *Server) packageDefinition(importPath, name string) *Location {
+	defs, ok := s.packages[importPath]
+	if !ok {
+		defs = s.loadDefinitions(importPath)
+		s.packages[importPath] = defs
+	}
+	loc, ok := defs[name]
+	if !ok {
+		return nil
+	}
+	return &loc
+}
+
+// loadDefinitions parses the source of the package with
+// the import path and returns the locations of its members.
+func (s *Server) loadDefinitions(importPath string) map[string]Location {
+	var dirs []string
+	if s.StdlibDir != "" {
+		dirs = append(dirs, s.StdlibDir)
+	} else if pkg := runtime.StdlibPackage(importPath); pkg != nil {
+		return s.builtinDefinitions(importPath, pkg)
+	}
+	dirs = append(dirs, s.FluxPath...)
+
+	for _, dir := range dirs {
+		dir = filepath.Join(dir, filepath.FromSlash(importPath))
+		if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+			continue
+		}
+		pkgs, err := parser.ParseDir(new(token.FileSet), dir)
+		if err != nil {
+			return nil
+		}
+		defs := make(map[string]Location)
+		for name, pkg := range pkgs {
+			if strings.HasSuffix(name, "_test") {
+				continue
+			}
+			for _, f := range pkg.Files {
+				fpath := filepath.Join(dir, f.Name)
+				src, err := ioutil.ReadFile(fpath)
+				if err != nil {
+					continue
+				}
+				addDefinitions(defs, fpath, string(src), f)
+			}
+		}
+		return defs
+	}
+	return nil
+}
+
+// builtinDefinitions writes the source of the builtin package that
+// is embedded in the binary to the cache directory and returns the
+// locations of its members in the written files.
+func (s *Server) builtinDefinitions(importPath string, pkg *ast.Package) map[string]Location {
+	dir := s.CacheDir
+	if dir == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			return nil
+		}
+		dir = filepath.Join(cache, "flux")
+	}
+	dir = filepath.Join(dir, "stdlib", filepath.FromSlash(importPath))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil
+	}
+
+	defs := make(map[string]Location)
+	for _, f := range pkg.Files {
+		if f.Loc == nil {
+			continue
+		}
+		// The embedded source starts at the package clause and
+		// leaves out the comments before it, so it is written at the
+		// same position to keep the locations of the AST.
+		src := strings.Repeat("\n", f.Loc.Start.Line-1) +
+			strings.Repeat(" ", f.Loc.Start.Column-1) +
+			f.Loc.Source
+		fpath := filepath.Join(dir, f.Name)
+		if err := writeSource(fpath, src); err != nil {
+			continue
+		}
+		addDefinitions(defs, fpath, src, f)
+	}
+	return defs
+}
+
+// writeSource writes the source to the file unless it already holds it.
+// The file is replaced with a rename so a reader never sees part of it.
+func writeSource(path, src string) error {
+	if old, err := ioutil.ReadFile(path); err == nil && string(old) == src {
+		return nil
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.WriteString(src); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// addDefinitions adds the locations of the members
+// declared in the file with the path and source.
+func addDefinitions(defs map[string]Location, path, src string, f *ast.File) {
+	fd := newDocument(fileURI(path), src)
+	for _, stmt := range f.Body {
+		if id := declaredIdentifier(stmt); id != nil {
+			defs[id.Name] = *fd.location(id)
+		}
+	}
+}
+
+// fileURI returns the file URI for the path.
+func fileURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// Windows paths start with a drive letter.
+		path = "/" + path
+	}
+	u := url.URL{Scheme: "file", Path: path}
+	return u.String()
+}
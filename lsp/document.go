@@ -0,0 +1,141 @@
+package lsp
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/semantic"
+)
+
+// document is a Flux file opened in the client.
+type document struct {
+	uri   string
+	text  string
+	lines []string
+
+	// ast is the parsed file. It is set even
+	// when the file contains syntax errors.
+	ast *ast.Package
+	// sem is the analyzed file. It is nil when the
+	// file contains syntax or type errors.
+	sem *semantic.Package
+	// types holds the types of the top-level bindings from the
+	// last analysis without errors. They are carried over when
+	// the document changes so that they are known while the user
+	// is typing incomplete code.
+	types map[string]semantic.MonoType
+	// importer resolves the packages imported by the file.
+	importer interpreter.Importer
+
+	diagnostics []Diagnostic
+}
+
+func newDocument(uri, text string) *document {
+	return &document{
+		uri:   uri,
+		text:  text,
+		lines: strings.Split(text, "\n"),
+	}
+}
+
+// offset returns the byte offset of the position in the text.
+func (d *document) offset(p Position) int {
+	if p.Line < 0 {
+		return 0
+	}
+	if p.Line >= len(d.lines) {
+		return len(d.text)
+	}
+	offset := 0
+	for _, l := range d.lines[:p.Line] {
+		offset += len(l) + 1
+	}
+	return offset + d.fromPosition(p).Column - 1
+}
+
+// line returns the text of the zero-based line n
+// without the line terminator.
+func (d *document) line(n int) string {
+	if n < 0 || n >= len(d.lines) {
+		return ""
+	}
+	return strings.TrimSuffix(d.lines[n], "\r")
+}
+
+// toPosition converts a position in the Flux source to an LSP position.
+// Flux positions count lines from one and columns in bytes from one,
+// while LSP positions count both from zero and count characters
+// in UTF-16 code units.
+func (d *document) toPosition(p ast.Position) Position {
+	if !p.IsValid() {
+		return Position{}
+	}
+	line := d.line(p.Line - 1)
+	col := p.Column - 1
+	if col > len(line) {
+		col = len(line)
+	}
+	return Position{
+		Line:      p.Line - 1,
+		Character: utf16Len(line[:col]),
+	}
+}
+
+// fromPosition converts an LSP position to a position in the Flux source.
+func (d *document) fromPosition(p Position) ast.Position {
+	line := d.line(p.Line)
+	offset, n := 0, 0
+	for offset < len(line) && n < p.Character {
+		r, size := utf8.DecodeRuneInString(line[offset:])
+		offset += size
+		n += utf16RuneLen(r)
+	}
+	return ast.Position{Line: p.Line + 1, Column: offset + 1}
+}
+
+// toRange converts a location in the Flux source to an LSP range.
+func (d *document) toRange(loc ast.SourceLocation) Range {
+	return Range{
+		Start: d.toPosition(loc.Start),
+		End:   d.toPosition(loc.End),
+	}
+}
+
+// prefix returns the text of the line before the position.
+func (d *document) prefix(p Position) string {
+	pos := d.fromPosition(p)
+	return d.line(p.Line)[:pos.Column-1]
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16RuneLen(r)
+	}
+	return n
+}
+
+func utf16RuneLen(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
+
+// contains reports whether the position is within the location.
+// The end of the location is included so that a position
+// just after an identifier refers to it.
+func contains(loc ast.SourceLocation, p ast.Position) bool {
+	if !loc.IsValid() {
+		return false
+	}
+	return !p.Less(loc.Start) && !loc.End.Less(p)
+}
+
+// smaller reports whether the location a is
+// nested within the location b.
+func smaller(a, b ast.SourceLocation) bool {
+	return !a.Start.Less(b.Start) && !b.End.Less(a.End)
+}
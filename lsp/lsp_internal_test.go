@@ -0,0 +1,171 @@
+package lsp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/ast"
+	_ "github.com/influxdata/flux/stdlib"
+)
+
+func TestConn(t *testing.T) {
+	var buf bytes.Buffer
+	c := newConn(&buf, &buf)
+	if err := c.write(map[string]int{"id": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "Content-Length: 8\r\n\r\n{\"id\":1}", buf.String(); want != got {
+		t.Fatalf("unexpected message -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+
+	c = newConn(strings.NewReader(
+		"Content-Length: 2\r\nContent-Type: application/vscode-jsonrpc; charset=utf-8\r\n\r\n{}"+
+			"Content-Length: 4\r\n\r\nnull",
+	), ioutil.Discard)
+	for _, want := range []string{"{}", "null"} {
+		got, err := c.read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want != string(got) {
+			t.Errorf("unexpected content -want/+got:\n\t- %q\n\t+ %q", want, got)
+		}
+	}
+	if _, err := c.read(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+
+	c = newConn(strings.NewReader("Content-Type: text/plain\r\n\r\n{}"), ioutil.Discard)
+	if _, err := c.read(); err == nil {
+		t.Error("expected an error for a message without a length")
+	}
+}
+
+func TestDocumentPositions(t *testing.T) {
+	d := newDocument("file:///a.flux", "x = 1\r\ns = \"é😀\" + y\n")
+	for _, tc := range []struct {
+		flux ast.Position
+		lsp  Position
+	}{
+		{flux: ast.Position{Line: 1, Column: 1}, lsp: Position{Line: 0, Character: 0}},
+		{flux: ast.Position{Line: 1, Column: 6}, lsp: Position{Line: 0, Character: 5}},
+		// é is two bytes and one UTF-16 code unit,
+		// 😀 is four bytes and two UTF-16 code units.
+		{flux: ast.Position{Line: 2, Column: 8}, lsp: Position{Line: 1, Character: 6}},
+		{flux: ast.Position{Line: 2, Column: 12}, lsp: Position{Line: 1, Character: 8}},
+		{flux: ast.Position{Line: 2, Column: 17}, lsp: Position{Line: 1, Character: 13}},
+	} {
+		if got := d.toPosition(tc.flux); tc.lsp != got {
+			t.Errorf("unexpected LSP position for %v -want/+got:\n\t- %v\n\t+ %v", tc.flux, tc.lsp, got)
+		}
+		if got := d.fromPosition(tc.lsp); tc.flux != got {
+			t.Errorf("unexpected Flux position for %v -want/+got:\n\t- %v\n\t+ %v", tc.lsp, tc.flux, got)
+		}
+	}
+
+	if want, got := "s = \"é😀\" +", d.prefix(Position{Line: 1, Character: 11}); want != got {
+		t.Errorf("unexpected prefix -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+	if want, got := len("x = 1\r\ns = "), d.offset(Position{Line: 1, Character: 4}); want != got {
+		t.Errorf("unexpected offset -want/+got:\n\t- %d\n\t+ %d", want, got)
+	}
+	if want, got := 0, d.offset(Position{Line: -1, Character: 4}); want != got {
+		t.Errorf("unexpected offset of a negative line -want/+got:\n\t- %d\n\t+ %d", want, got)
+	}
+}
+
+func TestCallContext(t *testing.T) {
+	for _, tc := range []struct {
+		text   string
+		callee string
+		arg    string
+		ok     bool
+	}{
+		{text: `strings.toUpper(`, callee: "strings.toUpper", ok: true},
+		{text: `strings.toUpper(v: `, callee: "strings.toUpper", arg: "v", ok: true},
+		{text: `range(start: -1h, stop`, callee: "range", ok: true},
+		{text: "from(bucket: \"b\")\n  |> range(start: -1h, stop: now()", callee: "range", arg: "stop", ok: true},
+		{text: `filter(fn: (r) => r._value > 0, `, callee: "filter", ok: true},
+		{text: `f(a: [1, `, ok: false},
+		{text: `f(a: 1)`, ok: false},
+		{text: `(1 + `, ok: false},
+		{text: `f(s: "(", `, callee: "f", ok: true},
+		{text: `f(s: ")", x: `, callee: "f", arg: "x", ok: true},
+		{text: `f(s: "a(`, callee: "f", arg: "s", ok: true},
+		{text: `f(s: "\"(", `, callee: "f", ok: true},
+		{text: `f(s: "${g(v: ")")}(", `, callee: "f", ok: true},
+		{text: `f(s: "${g(v: `, callee: "g", arg: "v", ok: true},
+		{text: "f(x: 1, // g(\n", callee: "f", ok: true},
+		{text: "f(x: 1) // g(", ok: false},
+	} {
+		callee, arg, ok := callContext(tc.text)
+		if tc.ok != ok || tc.callee != callee || tc.arg != arg {
+			t.Errorf("unexpected call context for %q: want (%q, %q, %v), got (%q, %q, %v)",
+				tc.text, tc.callee, tc.arg, tc.ok, callee, arg, ok)
+		}
+	}
+}
+
+func TestBuiltinDefinitions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lsp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewServer()
+	s.CacheDir = dir
+	loc := s.packageDefinition("strings", "toUpper")
+	if loc == nil {
+		t.Fatal("expected a definition for strings.toUpper")
+	}
+	path := filepath.Join(dir, "stdlib", "strings", "strings.flux")
+	if want := fileURI(path); want != loc.URI {
+		t.Fatalf("unexpected definition URI -want/+got:\n\t- %q\n\t+ %q", want, loc.URI)
+	}
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := strings.Split(string(src), "\n")[loc.Range.Start.Line]
+	if got := line[loc.Range.Start.Character:loc.Range.End.Character]; got != "toUpper" {
+		t.Errorf("expected the definition to cover toUpper, got %q in line %q", got, line)
+	}
+
+	if loc := s.packageDefinition("strings", "missing"); loc != nil {
+		t.Errorf("unexpected definition for a missing member: %v", loc)
+	}
+}
+
+func TestAnalysisDiagnostics(t *testing.T) {
+	d := newDocument("file:///a.flux", "x = 1 + \"a\"\ny = z\n")
+	got := d.analysisDiagnostics(errors.New(
+		"type error @1:5-1:12: expected int but found string\nerror @2:5-2:6: undefined identifier z",
+	))
+	want := []Diagnostic{
+		newDiagnostic(Range{
+			Start: Position{Line: 0, Character: 4},
+			End:   Position{Line: 0, Character: 11},
+		}, "expected int but found string"),
+		newDiagnostic(Range{
+			Start: Position{Line: 1, Character: 4},
+			End:   Position{Line: 1, Character: 5},
+		}, "undefined identifier z"),
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected diagnostics -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	got = d.analysisDiagnostics(errors.New("something went wrong"))
+	want = []Diagnostic{newDiagnostic(Range{}, "something went wrong")}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected diagnostics -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
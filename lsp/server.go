@@ -0,0 +1,248 @@
+// Package lsp implements a Language Server Protocol server for Flux.
+//
+// The server reports parse and type errors as diagnostics and
+// provides completion, signature help, hover and go-to-definition
+// for Flux files opened in an editor. It communicates with the
+// editor using JSON-RPC over a pair of streams, such as stdin
+// and stdout. Flux must be initialized before serving requests.
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// Server is a Language Server Protocol server for Flux.
+type Server struct {
+	// StdlibDir is the directory that holds the source of the
+	// standard library. It is used to find the definitions of
+	// standard library members. When it is empty, the source
+	// embedded in the binary is written to CacheDir instead.
+	StdlibDir string
+	// CacheDir is the directory where the embedded source of the
+	// standard library is written so that editors can open it.
+	// It defaults to a flux directory in the user cache directory.
+	CacheDir string
+	// FluxPath lists the directories searched for imported
+	// packages that are not part of the standard library.
+	FluxPath []string
+
+	docs map[string]*document
+	// packages caches the locations of the members
+	// of packages, keyed by their import path.
+	packages map[string]map[string]Location
+
+	conn     *conn
+	shutdown bool
+}
+
+// NewServer creates a new server.
+func NewServer() *Server {
+	return &Server{
+		docs:     make(map[string]*document),
+		packages: make(map[string]map[string]Location),
+	}
+}
+
+// Serve reads requests from r and writes responses and
+// notifications to w until the client sends the exit
+// notification or r is closed.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.conn = newConn(r, w)
+	for {
+		content, err := s.conn.read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(content, &req); err != nil {
+			if err := s.reply(nil, nil, &responseError{Code: codeParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			if !s.shutdown {
+				return errors.New(codes.Aborted, "exit before shutdown")
+			}
+			return nil
+		}
+
+		result, rerr := s.handle(&req)
+		if req.ID == nil {
+			// Notifications have no response.
+			continue
+		}
+		if err := s.reply(req.ID, result, rerr); err != nil {
+			return err
+		}
+	}
+}
+
+// handle handles a request or a notification and returns its result.
+func (s *Server) handle(req *request) (interface{}, *responseError) {
+	switch req.Method {
+	case "initialize":
+		return initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync: textDocumentSyncFull,
+				CompletionProvider: completionOptions{
+					TriggerCharacters: []string{"."},
+				},
+				SignatureHelpProvider: signatureHelpOptions{
+					TriggerCharacters: []string{"(", ","},
+				},
+				HoverProvider:      true,
+				DefinitionProvider: true,
+			},
+			ServerInfo: serverInfo{Name: "flux"},
+		}, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params didOpenTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		return nil, s.update(params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		var params didChangeTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		// With full synchronization the last change holds the whole text.
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		return nil, s.update(params.TextDocument.URI, text)
+	case "textDocument/didClose":
+		var params didCloseTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+		delete(s.docs, params.TextDocument.URI)
+		return nil, s.publish(params.TextDocument.URI, nil)
+	case "textDocument/completion":
+		d, p, rerr := s.position(req)
+		if rerr != nil || d == nil {
+			return nil, rerr
+		}
+		items := d.completion(p)
+		if items == nil {
+			items = []CompletionItem{}
+		}
+		return completionList{Items: items}, nil
+	case "textDocument/signatureHelp":
+		d, p, rerr := s.position(req)
+		if rerr != nil || d == nil {
+			return nil, rerr
+		}
+		if help := d.signatureHelp(p); help != nil {
+			return help, nil
+		}
+		return nil, nil
+	case "textDocument/hover":
+		d, p, rerr := s.position(req)
+		if rerr != nil || d == nil {
+			return nil, rerr
+		}
+		if hover := d.hover(p); hover != nil {
+			return hover, nil
+		}
+		return nil, nil
+	case "textDocument/definition":
+		d, p, rerr := s.position(req)
+		if rerr != nil || d == nil {
+			return nil, rerr
+		}
+		if loc := s.definition(d, p); loc != nil {
+			return loc, nil
+		}
+		return nil, nil
+	default:
+		if req.ID == nil {
+			// Unknown notifications are ignored.
+			return nil, nil
+		}
+		return nil, &responseError{
+			Code:    codeMethodNotFound,
+			Message: "method not found: " + req.Method,
+		}
+	}
+}
+
+// update replaces the text of the document, analyzes
+// it and publishes the diagnostics that were found.
+func (s *Server) update(uri, text string) *responseError {
+	d := newDocument(uri, text)
+	s.analyze(d)
+	if old, ok := s.docs[uri]; ok && d.types == nil {
+		d.types = old.types
+	}
+	s.docs[uri] = d
+	return s.publish(uri, d.diagnostics)
+}
+
+// publish sends the diagnostics of the document to the client.
+func (s *Server) publish(uri string, diagnostics []Diagnostic) *responseError {
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+	err := s.conn.write(notification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params: publishDiagnosticsParams{
+			URI:         uri,
+			Diagnostics: diagnostics,
+		},
+	})
+	if err != nil {
+		return &responseError{Code: codeInternalError, Message: err.Error()}
+	}
+	return nil
+}
+
+// position decodes the parameters of a request for a position
+// within a document. The document is nil if it is not open.
+func (s *Server) position(req *request) (*document, Position, *responseError) {
+	var params textDocumentPositionParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, Position{}, invalidParams(err)
+	}
+	if p := params.Position; p.Line < 0 || p.Character < 0 {
+		return nil, Position{}, invalidParams(errors.Newf(codes.Invalid, "invalid position %d:%d", p.Line, p.Character))
+	}
+	return s.docs[params.TextDocument.URI], params.Position, nil
+}
+
+func (s *Server) reply(id *json.RawMessage, result interface{}, rerr *responseError) error {
+	resp := response{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   rerr,
+	}
+	if rerr == nil {
+		bs, err := json.Marshal(result)
+		if err != nil {
+			resp.Error = &responseError{Code: codeInternalError, Message: err.Error()}
+		} else {
+			raw := json.RawMessage(bs)
+			resp.Result = &raw
+		}
+	}
+	return s.conn.write(resp)
+}
+
+func invalidParams(err error) *responseError {
+	return &responseError{Code: codeInvalidParams, Message: err.Error()}
+}
@@ -0,0 +1,235 @@
+package lsp_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	_ "github.com/influxdata/flux/fluxinit/static"
+	"github.com/influxdata/flux/lsp"
+)
+
+const testURI = "file:///test.flux"
+
+// message is a JSON-RPC message exchanged with the server.
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int            `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  interface{}     `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   json.RawMessage `json:"error,omitempty"`
+}
+
+// session runs the server with the messages
+// and returns the messages that it sent back.
+func session(t *testing.T, msgs ...message) []message {
+	t.Helper()
+	var in bytes.Buffer
+	for _, m := range msgs {
+		m.JSONRPC = "2.0"
+		bs, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(bs), bs)
+	}
+
+	var out bytes.Buffer
+	if err := lsp.NewServer().Serve(&in, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var replies []message
+	r := textproto.NewReader(bufio.NewReader(&out))
+	for {
+		header, err := r.ReadMIMEHeader()
+		if err == io.EOF {
+			return replies
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		n, err := strconv.Atoi(header.Get("Content-Length"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := make([]byte, n)
+		if _, err := io.ReadFull(r.R, content); err != nil {
+			t.Fatal(err)
+		}
+		var m message
+		if err := json.Unmarshal(content, &m); err != nil {
+			t.Fatal(err)
+		}
+		replies = append(replies, m)
+	}
+}
+
+func request(id int, method string, params interface{}) message {
+	return message{ID: &id, Method: method, Params: params}
+}
+
+func notify(method string, params interface{}) message {
+	return message{Method: method, Params: params}
+}
+
+func at(line, character int) map[string]interface{} {
+	return map[string]interface{}{
+		"textDocument": map[string]string{"uri": testURI},
+		"position":     lsp.Position{Line: line, Character: character},
+	}
+}
+
+func TestServer(t *testing.T) {
+	src := strings.Join([]string{
+		`import "strings"`,
+		``,
+		`greet = (name) => strings.toUpper(v: name)`,
+		`x = greet(name: "flux")`,
+	}, "\n")
+
+	replies := session(t,
+		request(1, "initialize", map[string]interface{}{}),
+		notify("initialized", map[string]interface{}{}),
+		notify("textDocument/didOpen", map[string]interface{}{
+			"textDocument": map[string]interface{}{
+				"uri":        testURI,
+				"languageId": "flux",
+				"version":    1,
+				"text":       `x = 1 + "a"`,
+			},
+		}),
+		notify("textDocument/didChange", map[string]interface{}{
+			"textDocument":   map[string]interface{}{"uri": testURI, "version": 2},
+			"contentChanges": []map[string]string{{"text": src}},
+		}),
+		request(2, "textDocument/hover", at(3, 0)),
+		request(3, "textDocument/definition", at(3, 5)),
+		request(4, "textDocument/definition", at(2, 38)),
+		request(5, "textDocument/signatureHelp", at(2, 37)),
+		request(6, "textDocument/completion", at(2, 26)),
+		request(7, "shutdown", nil),
+		notify("exit", nil),
+	)
+
+	results := make(map[int]json.RawMessage)
+	var diagnostics [][]lsp.Diagnostic
+	for _, m := range replies {
+		if m.Method == "textDocument/publishDiagnostics" {
+			var params struct {
+				Diagnostics []lsp.Diagnostic `json:"diagnostics"`
+			}
+			bs, _ := json.Marshal(m.Params)
+			if err := json.Unmarshal(bs, &params); err != nil {
+				t.Fatal(err)
+			}
+			diagnostics = append(diagnostics, params.Diagnostics)
+			continue
+		}
+		if m.ID == nil {
+			t.Fatalf("unexpected message %v", m)
+		}
+		if m.Error != nil {
+			t.Fatalf("unexpected error for request %d: %s", *m.ID, m.Error)
+		}
+		results[*m.ID] = m.Result
+	}
+
+	if len(diagnostics) != 2 {
+		t.Fatalf("expected diagnostics to be published twice, got %d", len(diagnostics))
+	}
+	if len(diagnostics[0]) != 1 || diagnostics[0][0].Range.Start != (lsp.Position{Line: 0, Character: 4}) {
+		t.Errorf("expected a type error at 0:4, got %v", diagnostics[0])
+	}
+	if len(diagnostics[1]) != 0 {
+		t.Errorf("expected no diagnostics, got %v", diagnostics[1])
+	}
+
+	var hover lsp.Hover
+	decode(t, results[2], &hover)
+	if want := "x: string"; !strings.Contains(hover.Contents.Value, want) {
+		t.Errorf("expected hover to contain %q, got %q", want, hover.Contents.Value)
+	}
+
+	for id, want := range map[int]lsp.Range{
+		3: {Start: lsp.Position{Line: 2, Character: 0}, End: lsp.Position{Line: 2, Character: 5}},
+		4: {Start: lsp.Position{Line: 2, Character: 9}, End: lsp.Position{Line: 2, Character: 13}},
+	} {
+		var loc lsp.Location
+		decode(t, results[id], &loc)
+		if loc.URI != testURI || loc.Range != want {
+			t.Errorf("unexpected definition for request %d: want %v, got %v", id, want, loc)
+		}
+	}
+
+	var help lsp.SignatureHelp
+	decode(t, results[5], &help)
+	if len(help.Signatures) != 1 || help.Signatures[0].Label != "strings.toUpper(v: string) -> string" {
+		t.Errorf("unexpected signature help %v", help)
+	}
+
+	var list struct {
+		Items []lsp.CompletionItem `json:"items"`
+	}
+	decode(t, results[6], &list)
+	found := false
+	for _, item := range list.Items {
+		if item.Label == "toUpper" {
+			found = item.Kind == lsp.KindFunction
+		}
+	}
+	if !found {
+		t.Errorf("expected toUpper function in completion items, got %v", list.Items)
+	}
+}
+
+func TestServer_MethodNotFound(t *testing.T) {
+	replies := session(t,
+		request(1, "workspace/symbol", map[string]interface{}{}),
+		request(2, "shutdown", nil),
+		notify("exit", nil),
+	)
+	if len(replies) != 2 || replies[0].Error == nil {
+		t.Fatalf("expected an error response, got %v", replies)
+	}
+}
+
+func TestServer_InvalidPosition(t *testing.T) {
+	replies := session(t,
+		request(1, "textDocument/hover", at(-1, 0)),
+		request(2, "textDocument/completion", at(0, -1)),
+		request(3, "shutdown", nil),
+		notify("exit", nil),
+	)
+	errs := make(map[int]json.RawMessage)
+	for _, m := range replies {
+		if m.ID != nil {
+			errs[*m.ID] = m.Error
+		}
+	}
+	for _, id := range []int{1, 2} {
+		var rerr struct {
+			Code int `json:"code"`
+		}
+		if errs[id] == nil {
+			t.Fatalf("expected an error response to request %d, got %v", id, replies)
+		}
+		decode(t, errs[id], &rerr)
+		if want, got := -32602, rerr.Code; want != got {
+			t.Errorf("unexpected error code for request %d -want/+got:\n\t- %d\n\t+ %d", id, want, got)
+		}
+	}
+}
+
+func decode(t *testing.T, bs json.RawMessage, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(bs, v); err != nil {
+		t.Fatalf("failed to decode %s: %v", bs, err)
+	}
+}
@@ -0,0 +1,210 @@
+package lsp
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+)
+
+var (
+	// calleeSuffix matches the callee that precedes the
+	// opening parenthesis of a call, such as `strings.toUpper`.
+	calleeSuffix = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+	// argumentName matches the name of a named argument.
+	argumentName = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s*:`)
+)
+
+// callContext finds the innermost call that is still open at the
+// end of the text. It returns the callee and the name of the
+// argument being written, which is empty if there is none yet.
+// Brackets within string literals and comments are ignored.
+func callContext(text string) (callee, arg string, ok bool) {
+	code := codeMask(text)
+	depth := 0
+	argStart := -1
+	for i := len(text) - 1; i >= 0; i-- {
+		if !code[i] {
+			continue
+		}
+		switch text[i] {
+		case ')', ']', '}':
+			depth++
+		case '[', '{':
+			if depth == 0 {
+				// The position is within an array or a record.
+				return "", "", false
+			}
+			depth--
+		case ',':
+			if depth == 0 && argStart < 0 {
+				argStart = i + 1
+			}
+		case '(':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			if argStart < 0 {
+				argStart = i + 1
+			}
+			callee = calleeSuffix.FindString(strings.TrimRight(text[:i], " \t\r\n"))
+			if callee == "" {
+				return "", "", false
+			}
+			if m := argumentName.FindStringSubmatch(text[argStart:]); m != nil {
+				arg = m[1]
+			}
+			return callee, arg, true
+		}
+	}
+	return "", "", false
+}
+
+// codeMask reports for each byte of the text whether it is code
+// rather than part of a string literal or a comment. The expressions
+// interpolated in a string are code.
+func codeMask(text string) []bool {
+	code := make([]bool, len(text))
+	// interpolations holds the depth of the braces within each
+	// open string interpolation, the innermost one last.
+	var interpolations []int
+	inString := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if inString {
+			switch {
+			case c == '\\':
+				i++
+			case c == '"':
+				inString = false
+			case c == '$' && strings.HasPrefix(text[i+1:], "{"):
+				interpolations = append(interpolations, 0)
+				inString = false
+				i++
+			}
+			continue
+		}
+		n := len(interpolations)
+		switch {
+		case c == '"':
+			inString = true
+			continue
+		case strings.HasPrefix(text[i:], "//"):
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '{' && n > 0:
+			interpolations[n-1]++
+		case c == '}' && n > 0:
+			if interpolations[n-1] == 0 {
+				interpolations = interpolations[:n-1]
+				inString = true
+				continue
+			}
+			interpolations[n-1]--
+		}
+		code[i] = true
+	}
+	return code
+}
+
+// signatureHelp describes the signature of
+// the function called at the position.
+func (d *document) signatureHelp(p Position) *SignatureHelp {
+	callee, arg, ok := callContext(d.text[:d.offset(p)])
+	if !ok {
+		return nil
+	}
+	typ, ok := d.calleeType(callee)
+	if !ok || typ.Nature() != semantic.Function {
+		return nil
+	}
+	sig, names, err := signature(callee, typ)
+	if err != nil {
+		return nil
+	}
+	help := &SignatureHelp{Signatures: []SignatureInformation{sig}}
+	for i, name := range names {
+		if name == arg {
+			help.ActiveParameter = i
+		}
+	}
+	return help
+}
+
+// calleeType returns the type of the function named by the callee.
+func (d *document) calleeType(callee string) (semantic.MonoType, bool) {
+	if i := strings.IndexByte(callee, '.'); i >= 0 {
+		path, ok := d.importPath(callee[:i])
+		if !ok {
+			return semantic.MonoType{}, false
+		}
+		name := callee[i+1:]
+		if typ, err := runtime.LookupBuiltinType(path, name); err == nil {
+			return typ, true
+		}
+		pkg, err := d.importPackage(path)
+		if err != nil {
+			return semantic.MonoType{}, false
+		}
+		v, ok := pkg.Get(name)
+		if !ok {
+			return semantic.MonoType{}, false
+		}
+		return v.Type(), true
+	}
+
+	if typ, ok := d.types[callee]; ok {
+		return typ, true
+	}
+	for _, pkg := range preludePackages {
+		if typ, err := runtime.LookupBuiltinType(pkg, callee); err == nil {
+			return typ, true
+		}
+	}
+	if v, ok := runtime.Prelude().Lookup(callee); ok {
+		return v.Type(), true
+	}
+	return semantic.MonoType{}, false
+}
+
+// signature formats the function type as the signature of
+// the named function. It also returns the names of the
+// parameters in the order that they appear in the signature.
+func signature(name string, typ semantic.MonoType) (SignatureInformation, []string, error) {
+	args, err := typ.SortedArguments()
+	if err != nil {
+		return SignatureInformation{}, nil, err
+	}
+	ret, err := typ.ReturnType()
+	if err != nil {
+		return SignatureInformation{}, nil, err
+	}
+
+	sig := SignatureInformation{
+		Parameters: make([]ParameterInformation, 0, len(args)),
+	}
+	names := make([]string, 0, len(args))
+	labels := make([]string, 0, len(args))
+	for _, arg := range args {
+		argTyp, err := arg.TypeOf()
+		if err != nil {
+			return SignatureInformation{}, nil, err
+		}
+		var label string
+		if arg.Optional() {
+			label = "?"
+		} else if arg.Pipe() {
+			label = "<-"
+		}
+		label += string(arg.Name()) + ": " + argTyp.String()
+		sig.Parameters = append(sig.Parameters, ParameterInformation{Label: label})
+		names = append(names, string(arg.Name()))
+		labels = append(labels, label)
+	}
+	sig.Label = name + "(" + strings.Join(labels, ", ") + ") -> " + ret.String()
+	return sig, names, nil
+}
@@ -28,6 +28,10 @@ type Allocator struct {
 	totalAllocated  int64
 	mu              sync.Mutex
 
+	// granted is the amount of memory that the Manager
+	// has added to the limit. It is protected by mu.
+	granted int64
+
 	// Limit is the limit on the amount of memory that this allocator
 	// can assign. If this is null, there is no limit.
 	Limit *int64
@@ -148,6 +152,12 @@ func (a *Allocator) count(size int) error {
 				}
 				// The request for additional memory succeeded so try again.
 			} else if atomic.CompareAndSwapInt64(&a.bytesAllocated, allocated, want) {
+				// ReturnMemory may have lowered the limit after we loaded it.
+				// Give the memory back and try again if it is now exceeded.
+				if size > 0 && want > atomic.LoadInt64(&a.allocationLimit) {
+					atomic.AddInt64(&a.bytesAllocated, int64(-size))
+					continue
+				}
 				c = want
 				break
 			}
@@ -192,9 +202,12 @@ func (a *Allocator) requestMemory(allocated, want int64) error {
 		// Request that additional memory is needed from the manager.
 		need := want - *a.Limit
 		n, err := a.Manager.RequestMemory(need)
-		if err == nil {
+		// A manager that grants nothing would have us ask again forever
+		// so it is treated the same as a failed request.
+		if err == nil && n > 0 {
 			// Increase the limit by the amount the manager gave us.
 			*a.Limit += n
+			a.granted += n
 			atomic.StoreInt64(&a.allocationLimit, *a.Limit)
 			return nil
 		}
@@ -208,6 +221,71 @@ func (a *Allocator) requestMemory(allocated, want int64) error {
 	}, codes.ResourceExhausted)
 }
 
+// ShouldSpill reports whether the consumers of this Allocator
+// should release memory by spilling buffered data to disk.
+// This is only true when the Manager is a SpillManager that
+// has started granting memory from its reserve.
+func (a *Allocator) ShouldSpill() bool {
+	if a == nil {
+		return false
+	}
+	m, ok := a.Manager.(*SpillManager)
+	return ok && m.ShouldSpill()
+}
+
+// SpillDir returns the directory where data should be spilled
+// and whether spilling is enabled for this Allocator.
+// The directory is empty when the data should be spilled to the
+// directory for temporary files of the filesystem service.
+func (a *Allocator) SpillDir() (string, bool) {
+	if a == nil {
+		return "", false
+	}
+	m, ok := a.Manager.(*SpillManager)
+	if !ok {
+		return "", false
+	}
+	return m.SpillDir(), true
+}
+
+// ReturnMemory gives memory that was granted by the Manager
+// and is no longer in use back to the Manager.
+// It should be called after a large amount of memory has been freed,
+// such as after data has been spilled to disk.
+func (a *Allocator) ReturnMemory() {
+	if a == nil || a.Limit == nil || a.Manager == nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	unused := *a.Limit - a.Allocated()
+	if unused > a.granted {
+		unused = a.granted
+	}
+	if unused <= 0 {
+		return
+	}
+	limit := *a.Limit - unused
+	atomic.StoreInt64(&a.allocationLimit, limit)
+
+	// An allocation that loaded the old limit may have been
+	// counted in the meantime. Keep the memory it is using.
+	if allocated := a.Allocated(); allocated > limit {
+		unused -= allocated - limit
+		if unused <= 0 {
+			atomic.StoreInt64(&a.allocationLimit, *a.Limit)
+			return
+		}
+		limit = *a.Limit - unused
+		atomic.StoreInt64(&a.allocationLimit, limit)
+	}
+	*a.Limit = limit
+	a.granted -= unused
+	a.Manager.FreeMemory(unused)
+}
+
 // allocator returns the underlying memory.Allocator that should be used.
 func (a *Allocator) allocator() memory.Allocator {
 	if a.Allocator == nil {
@@ -298,6 +298,27 @@ func TestAllocator_RequestMemory(t *testing.T) {
 	}
 }
 
+func TestAllocator_RequestMemory_NoProgress(t *testing.T) {
+	// The manager succeeds without granting any memory.
+	manager := &MockMemoryManager{
+		Left:      64,
+		RequestFn: func(want int64) int64 { return 0 },
+	}
+	allocator := &memory.Allocator{
+		Limit:   func(v int64) *int64 { return &v }(32),
+		Manager: manager,
+	}
+	if err := allocator.Account(64); err == nil {
+		t.Fatal("expected error")
+	}
+	if want, got := int64(0), allocator.Allocated(); want != got {
+		t.Fatalf("unexpected allocated count -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+	if want, got := int64(32), *allocator.Limit; want != got {
+		t.Fatalf("unexpected allocater limit -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+}
+
 // This test makes a lot of small allocations and has the memory manager
 // only give small amounts of memory so that multiple goroutines are requesting
 // memory from the manager concurrently. This is to ensure that requesting
@@ -341,3 +362,43 @@ func TestAllocator_RequestMemory_Concurrently(t *testing.T) {
 		t.Fatalf("unexpected memory left in the manager -want/+got\n\t- %d\n\t+ %d", want, got)
 	}
 }
+
+// This test allocates and frees memory while the unused memory is
+// returned to the manager concurrently. All of the memory must be
+// given back to the manager once nothing is allocated.
+func TestAllocator_ReturnMemory_Concurrently(t *testing.T) {
+	manager := &MockMemoryManager{
+		Left: 128 * 16,
+	}
+	allocator := &memory.Allocator{
+		Limit:   func(v int64) *int64 { return &v }(0),
+		Manager: manager,
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 128; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 16; i++ {
+				if err := allocator.Account(1); err != nil {
+					t.Errorf("unexpected error: %s", err)
+					return
+				}
+				allocator.ReturnMemory()
+				_ = allocator.Account(-1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	allocator.ReturnMemory()
+	if want, got := int64(0), allocator.Allocated(); want != got {
+		t.Fatalf("unexpected allocated count -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+	if want, got := int64(0), *allocator.Limit; want != got {
+		t.Fatalf("unexpected allocater limit -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+	if want, got := int64(128*16), manager.Left; want != got {
+		t.Fatalf("unexpected memory left in the manager -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+}
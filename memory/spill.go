@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// SpillManager is a Manager that allows an Allocator to exceed its
+// limit instead of failing so that the consumers of the Allocator
+// have a chance to spill their buffered data to disk.
+//
+// When the Allocator requests more memory, the SpillManager first
+// consults the wrapped Manager, if there is one. If that fails, the
+// memory is granted from the reserve and the SpillManager reports
+// that data should be spilled until that memory is freed again.
+// Consumers check Allocator.ShouldSpill at points where it is safe
+// to write their data to disk and call Allocator.ReturnMemory
+// after they have done so.
+//
+// The reserve bounds how far the Allocator can go over its limit.
+// Once the reserve is exhausted, allocations fail with the usual
+// LimitExceededError.
+type SpillManager struct {
+	// Manager is the Manager that is consulted before
+	// memory is granted from the reserve. It may be nil.
+	Manager Manager
+
+	// Reserve is the number of bytes that may be granted
+	// beyond the limit while data is being spilled.
+	Reserve int64
+
+	// Dir is the directory where spilled data is written.
+	// The data is written with the filesystem service of the query
+	// so the directory must be accessible through that service.
+	// If this is empty, the directory for temporary files of
+	// that service is used.
+	Dir string
+
+	mu       sync.Mutex
+	reserved int64
+	managed  int64
+}
+
+// NewSpillManager constructs a SpillManager with the given reserve
+// that writes spilled data to the directory.
+func NewSpillManager(reserve int64, dir string) *SpillManager {
+	return &SpillManager{
+		Reserve: reserve,
+		Dir:     dir,
+	}
+}
+
+func (m *SpillManager) RequestMemory(want int64) (got int64, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Manager != nil {
+		if n, err := m.Manager.RequestMemory(want); err == nil {
+			m.managed += n
+			return n, nil
+		}
+	}
+
+	if m.reserved+want > m.Reserve {
+		return 0, errors.Newf(codes.ResourceExhausted, "spill reserve exhausted: reserve %d bytes, reserved: %d, wanted: %d", m.Reserve, m.reserved, want)
+	}
+	m.reserved += want
+	return want, nil
+}
+
+// FreeMemory returns memory to the reserve first and
+// gives anything remaining back to the wrapped Manager.
+func (m *SpillManager) FreeMemory(bytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := bytes
+	if n > m.reserved {
+		n = m.reserved
+	}
+	m.reserved -= n
+	bytes -= n
+
+	if bytes > m.managed {
+		bytes = m.managed
+	}
+	if bytes > 0 && m.Manager != nil {
+		m.managed -= bytes
+		m.Manager.FreeMemory(bytes)
+	}
+}
+
+// ShouldSpill reports whether any memory has been granted
+// from the reserve and not yet returned.
+func (m *SpillManager) ShouldSpill() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.reserved > 0
+}
+
+// SpillDir returns the directory where spilled data is written.
+// It is empty if the filesystem service of the query decides.
+func (m *SpillManager) SpillDir() string {
+	return m.Dir
+}
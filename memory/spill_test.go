@@ -0,0 +1,95 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/flux/memory"
+)
+
+func TestSpillManager(t *testing.T) {
+	manager := memory.NewSpillManager(64, "")
+	allocator := &memory.Allocator{
+		Limit:   func(v int64) *int64 { return &v }(64),
+		Manager: manager,
+	}
+
+	// Stay within the limit. There should be no need to spill.
+	if err := allocator.Account(48); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if allocator.ShouldSpill() {
+		t.Fatal("unexpected request to spill")
+	}
+
+	// Exceed the limit. The memory should come from the
+	// reserve and the allocator should ask to spill.
+	if err := allocator.Account(32); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !allocator.ShouldSpill() {
+		t.Fatal("expected a request to spill")
+	}
+	if want, got := int64(80), *allocator.Limit; want != got {
+		t.Fatalf("unexpected allocater limit -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+
+	// Exhausting the reserve fails.
+	if err := allocator.Account(64); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// Free the memory as if it had been spilled to disk
+	// and return it. The allocator should be back at its
+	// original limit and no longer need to spill.
+	if err := allocator.Account(-48); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	allocator.ReturnMemory()
+	if allocator.ShouldSpill() {
+		t.Fatal("unexpected request to spill")
+	}
+	if want, got := int64(64), *allocator.Limit; want != got {
+		t.Fatalf("unexpected allocater limit -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+}
+
+func TestSpillManager_WrappedManager(t *testing.T) {
+	wrapped := &MockMemoryManager{
+		Left: 16,
+	}
+	manager := memory.NewSpillManager(64, "")
+	manager.Manager = wrapped
+	allocator := &memory.Allocator{
+		Limit:   func(v int64) *int64 { return &v }(0),
+		Manager: manager,
+	}
+
+	// The wrapped manager can satisfy the request.
+	if err := allocator.Account(16); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if allocator.ShouldSpill() {
+		t.Fatal("unexpected request to spill")
+	}
+
+	// The wrapped manager is out of memory so the reserve is used.
+	if err := allocator.Account(16); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !allocator.ShouldSpill() {
+		t.Fatal("expected a request to spill")
+	}
+
+	// Returning everything gives back the reserve first
+	// and then the memory from the wrapped manager.
+	if err := allocator.Account(-32); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	allocator.ReturnMemory()
+	if allocator.ShouldSpill() {
+		t.Fatal("unexpected request to spill")
+	}
+	if want, got := int64(16), wrapped.Left; want != got {
+		t.Fatalf("unexpected memory left in the manager -want/+got\n\t- %d\n\t+ %d", want, got)
+	}
+}
@@ -1,17 +1,55 @@
 package plan
 
+import "math"
+
+// Statistics are estimates of the data produced by a plan node.
 type Statistics struct {
-	Cardinality      int64
-	GroupCardinality int64
+	Cardinality      int64 `json:"cardinality"`
+	GroupCardinality int64 `json:"groupCardinality"`
+}
+
+// DefaultSourceStatistics are the statistics assumed for the output of a source
+// when it has no better estimate of the data it will produce.
+var DefaultSourceStatistics = Statistics{
+	Cardinality:      1 << 20,
+	GroupCardinality: 1 << 10,
+}
+
+// Selectivity estimates are the fraction of rows that are assumed to remain
+// after the corresponding operation when nothing better is known.
+const (
+	DefaultRangeSelectivity  = 0.5
+	DefaultFilterSelectivity = 0.3
+)
+
+// CombineStatistics returns statistics for the union of the inputs.
+func CombineStatistics(inStats ...Statistics) Statistics {
+	var s Statistics
+	for _, in := range inStats {
+		s.Cardinality += in.Cardinality
+		s.GroupCardinality += in.GroupCardinality
+	}
+	return s
+}
+
+// Scale returns the statistics that result from keeping the given fraction
+// of the rows. The number of groups is assumed to remain the same
+// unless there are fewer rows than groups.
+func (s Statistics) Scale(selectivity float64) Statistics {
+	s.Cardinality = int64(math.Ceil(float64(s.Cardinality) * selectivity))
+	if s.GroupCardinality > s.Cardinality {
+		s.GroupCardinality = s.Cardinality
+	}
+	return s
 }
 
 // Cost stores various dimensions of the cost of a query plan
 type Cost struct {
-	Disk int64
-	CPU  int64
-	GPU  int64
-	MEM  int64
-	NET  int64
+	Disk int64 `json:"disk"`
+	CPU  int64 `json:"cpu"`
+	GPU  int64 `json:"gpu"`
+	MEM  int64 `json:"mem"`
+	NET  int64 `json:"net"`
 }
 
 // Add two cost structures together
@@ -25,9 +63,72 @@ func Add(a Cost, b Cost) Cost {
 	}
 }
 
+// The weights used to combine the dimensions of a cost.
+// Moving data over the network or to and from disk is
+// much slower than processing it in memory.
+const (
+	diskCostWeight = 4
+	netCostWeight  = 8
+)
+
+// Total returns a single value that summarizes the cost
+// so that the cost of different plans can be compared.
+func (c Cost) Total() int64 {
+	return c.Disk*diskCostWeight + c.NET*netCostWeight + c.CPU + c.GPU + c.MEM
+}
+
+// Less reports whether c is cheaper than other.
+func (c Cost) Less(other Cost) bool {
+	return c.Total() < other.Total()
+}
+
+// DefaultCost is embedded in procedure specs that do not estimate their cost.
+// It reports no cost and passes the statistics of its inputs through unchanged.
+// A source with no inputs reports the DefaultSourceStatistics.
 type DefaultCost struct {
 }
 
 func (c DefaultCost) Cost(inStats []Statistics) (Cost, Statistics) {
-	return Cost{}, Statistics{}
+	if len(inStats) == 0 {
+		return Cost{}, DefaultSourceStatistics
+	}
+	return Cost{}, CombineStatistics(inStats...)
+}
+
+// ComputeCost estimates the total cost of executing the plan.
+// The cost of each node is computed from the statistics of its predecessors
+// and the costs of all of the nodes are added together.
+// Nodes that are not physical pass the statistics of their inputs through
+// and do not add to the cost.
+func ComputeCost(plan *Spec) (Cost, error) {
+	var total Cost
+	err := estimateNodes(plan, func(node Node, cost Cost, _ Statistics) {
+		total = Add(total, cost)
+	})
+	if err != nil {
+		return Cost{}, err
+	}
+	return total, nil
+}
+
+// estimateNodes calls f with the estimated cost of each
+// physical node and the statistics of the data it produces.
+func estimateNodes(plan *Spec, f func(node Node, cost Cost, stats Statistics)) error {
+	stats := make(map[Node]Statistics)
+	return plan.BottomUpWalk(func(node Node) error {
+		inStats := make([]Statistics, len(node.Predecessors()))
+		for i, pred := range node.Predecessors() {
+			inStats[i] = stats[pred]
+		}
+
+		spec, ok := node.ProcedureSpec().(PhysicalProcedureSpec)
+		if !ok {
+			stats[node] = CombineStatistics(inStats...)
+			return nil
+		}
+		cost, outStats := spec.Cost(inStats)
+		stats[node] = outStats
+		f(node, cost, outStats)
+		return nil
+	})
 }
@@ -0,0 +1,191 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+)
+
+// costBasedPlanner applies the same rules as the heuristicPlanner,
+// but when more than one rule can rewrite a node it compares the
+// alternatives and keeps the one with the lowest estimated cost.
+//
+// Each alternative is estimated by applying its rewrite to a copy of the
+// node and the nodes it reads from, which are the only nodes a rule may
+// change, and computing the cost of that copy. The estimate does not
+// account for the rewrites that an alternative would enable later on,
+// so the planning time grows with the number of competing rules rather
+// than with the number of plans they could produce. When two alternatives
+// have the same cost, the rule that the heuristicPlanner would have applied
+// first is chosen.
+type costBasedPlanner struct {
+	*heuristicPlanner
+}
+
+func newCostBasedPlanner(hp *heuristicPlanner) *costBasedPlanner {
+	return &costBasedPlanner{heuristicPlanner: hp}
+}
+
+// Plan is a fixed-point query planning algorithm that uses the estimated cost
+// of the plan to choose between rules that compete to rewrite the same node.
+//
+// Plan may change its argument and/or return a new instance of Spec, so the correct way to call Plan is:
+//
+//	plan, err = plan.Plan(plan)
+func (p *costBasedPlanner) Plan(ctx context.Context, inputPlan *Spec) (*Spec, error) {
+	return p.plan(ctx, inputPlan, p.matchRules)
+}
+
+// matchingRules returns the enabled rules whose pattern matches the node
+// in the order the heuristicPlanner would apply them.
+func (p *costBasedPlanner) matchingRules(node Node) []Rule {
+	var rules []Rule
+	for _, kind := range []ProcedureKind{AnyKind, node.Kind()} {
+		for _, rule := range p.rules[kind] {
+			if p.disabledRules[rule.Name()] {
+				continue
+			}
+			if rule.Pattern().Match(node) {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules
+}
+
+// matchRules rewrites the node with the rule that results in the cheapest plan.
+func (p *costBasedPlanner) matchRules(ctx context.Context, _ *Spec, node Node) (Node, bool, error) {
+	rules := p.matchingRules(node)
+	if len(rules) <= 1 {
+		return p.heuristicPlanner.matchRules(ctx, node)
+	}
+
+	var (
+		best     Rule
+		bestCost Cost
+	)
+	for _, rule := range rules {
+		cost, changed, err := p.estimate(ctx, node, rule)
+		if err != nil {
+			if _, ok := err.(errUncopyablePlan); ok {
+				// The alternatives cannot be compared
+				// so plan the node heuristically.
+				return p.heuristicPlanner.matchRules(ctx, node)
+			}
+			return nil, false, err
+		}
+		if changed && (best == nil || cost.Less(bestCost)) {
+			best, bestCost = rule, cost
+		}
+	}
+	if best == nil {
+		return node, false, nil
+	}
+	return p.rewrite(ctx, node, best)
+}
+
+// rewrite uses the rule to rewrite the node and records it in the trace.
+func (p *costBasedPlanner) rewrite(ctx context.Context, node Node, rule Rule) (Node, bool, error) {
+	return p.trace.rewrite(ctx, rule, node)
+}
+
+// estimate uses the rule to rewrite a copy of the node and returns
+// the cost of the rewritten copy and the nodes it reads from.
+// It reports whether the rule changed the copy.
+func (p *costBasedPlanner) estimate(ctx context.Context, node Node, rule Rule) (Cost, bool, error) {
+	cnode, err := copyInputs(node)
+	if err != nil {
+		return Cost{}, false, err
+	}
+
+	newNode, changed, err := p.trace.try(isolateNodeIDs(ctx), rule, cnode)
+	if err != nil || !changed {
+		return Cost{}, false, err
+	}
+	cp := NewPlanSpec()
+	cp.Roots[newNode] = struct{}{}
+	cost, err := ComputeCost(cp)
+	if err != nil {
+		return Cost{}, false, err
+	}
+	return cost, true, nil
+}
+
+// isolateNodeIDs returns a context that generates the same node ids
+// as ctx, without affecting the ids that ctx will generate.
+// This keeps the ids in the chosen plan the same as if the
+// alternatives had never been planned.
+func isolateNodeIDs(ctx context.Context) context.Context {
+	if value := ctx.Value(NextPlanNodeIDKey); value != nil {
+		next := *value.(*int)
+		return context.WithValue(ctx, NextPlanNodeIDKey, &next)
+	}
+	return ctx
+}
+
+// errUncopyablePlan is returned when a plan contains a node that cannot be copied.
+type errUncopyablePlan struct {
+	node Node
+}
+
+func (e errUncopyablePlan) Error() string {
+	return fmt.Sprintf("cannot copy plan node %q of type %T", e.node.ID(), e.node)
+}
+
+// copyInputs copies the node and all of the nodes that it reads from,
+// including their procedure specs, and returns the copy of the node.
+// The successors of the copies that are not part of the copy are the
+// original nodes, so rules that check the successors of a node see
+// the same plan, but the original nodes do not refer to the copies.
+func copyInputs(node Node) (Node, error) {
+	nodes := make(map[Node]Node)
+	var order []Node
+	if err := WalkPredecessors([]Node{node}, func(node Node) error {
+		var cn Node
+		switch n := node.(type) {
+		case *LogicalNode:
+			cn = &LogicalNode{
+				bounds: n.bounds,
+				id:     n.id,
+				Spec:   n.Spec.Copy(),
+				Source: n.Source,
+			}
+		case *PhysicalPlanNode:
+			spec, ok := n.Spec.Copy().(PhysicalProcedureSpec)
+			if !ok {
+				return errUncopyablePlan{node: node}
+			}
+			cn = &PhysicalPlanNode{
+				bounds:        n.bounds,
+				id:            n.id,
+				Spec:          spec,
+				Source:        n.Source,
+				TriggerSpec:   n.TriggerSpec,
+				RequiredAttrs: n.RequiredAttrs,
+				OutputAttrs:   n.OutputAttrs,
+			}
+		default:
+			return errUncopyablePlan{node: node}
+		}
+		nodes[node] = cn
+		order = append(order, node)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// Connect the copies in the same order as the original nodes.
+	for _, n := range order {
+		cn := nodes[n]
+		for _, pred := range n.Predecessors() {
+			cn.AddPredecessors(nodes[pred])
+		}
+		for _, succ := range n.Successors() {
+			if cs, ok := nodes[succ]; ok {
+				cn.AddSuccessors(cs)
+			} else {
+				cn.AddSuccessors(succ)
+			}
+		}
+	}
+	return nodes[node], nil
+}
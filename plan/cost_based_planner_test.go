@@ -0,0 +1,110 @@
+package plan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+)
+
+// mergeSourceRule merges an aggregate into its source
+// and gives the merged node the configured cost.
+type mergeSourceRule struct {
+	name string
+	cost plan.Cost
+}
+
+func (r mergeSourceRule) Name() string {
+	return r.name
+}
+
+func (r mergeSourceRule) Pattern() plan.Pattern {
+	return plan.Pat("agg", plan.Pat("source"))
+}
+
+func (r mergeSourceRule) Rewrite(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+	spec := &costProcedureSpec{kind: plan.ProcedureKind(r.name), cost: r.cost}
+	n, err := plan.MergeToPhysicalNode(node, node.Predecessors()[0], spec)
+	if err != nil {
+		return nil, false, err
+	}
+	return n, true, nil
+}
+
+func TestCostBasedPlanner(t *testing.T) {
+	expensive := mergeSourceRule{name: "expensive", cost: plan.Cost{NET: 1000}}
+	cheap := mergeSourceRule{name: "cheap", cost: plan.Cost{NET: 10}}
+
+	testCases := []struct {
+		name      string
+		rules     []plan.Rule
+		costBased bool
+		want      plan.ProcedureKind
+	}{
+		{
+			name:  "heuristic uses first rule",
+			rules: []plan.Rule{expensive, cheap},
+			want:  "expensive",
+		},
+		{
+			name:      "cheapest rule",
+			rules:     []plan.Rule{expensive, cheap},
+			costBased: true,
+			want:      "cheap",
+		},
+		{
+			name:      "cheapest rule in any order",
+			rules:     []plan.Rule{cheap, expensive},
+			costBased: true,
+			want:      "cheap",
+		},
+		{
+			name:      "single rule",
+			rules:     []plan.Rule{expensive},
+			costBased: true,
+			want:      "expensive",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			//   agg
+			//    |
+			//  source
+			spec := plantest.CreatePlanSpec(&plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("source", &costProcedureSpec{kind: "source"}),
+					plan.CreatePhysicalNode("agg", &costProcedureSpec{kind: "agg", selectivity: 0.1}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+				},
+			})
+
+			opts := []plan.PhysicalOption{
+				plan.OnlyPhysicalRules(tc.rules...),
+				plan.DisableValidation(),
+			}
+			if tc.costBased {
+				opts = append(opts, plan.WithCostBasedPlanning())
+			}
+			pp, err := plan.NewPhysicalPlanner(opts...).Plan(context.Background(), spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []plan.ProcedureKind
+			if err := pp.BottomUpWalk(func(node plan.Node) error {
+				got = append(got, node.Kind())
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if want := []plan.ProcedureKind{tc.want}; !cmp.Equal(want, got) {
+				t.Errorf("unexpected plan -want/+got:\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package plan_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+)
+
+// costProcedureSpec is a procedure spec with a configurable cost.
+// A source produces 100 rows and every other node keeps
+// the given fraction of its input.
+type costProcedureSpec struct {
+	kind        plan.ProcedureKind
+	cost        plan.Cost
+	selectivity float64
+}
+
+func (s *costProcedureSpec) Kind() plan.ProcedureKind {
+	return s.kind
+}
+
+func (s *costProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+
+func (s *costProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	if len(inStats) == 0 {
+		return s.cost, plan.Statistics{Cardinality: 100, GroupCardinality: 10}
+	}
+	in := plan.CombineStatistics(inStats...)
+	cost := s.cost
+	cost.CPU += in.Cardinality
+	return cost, in.Scale(s.selectivity)
+}
+
+func TestComputeCost(t *testing.T) {
+	testCases := []struct {
+		name string
+		plan plantest.PlanSpec
+		want plan.Cost
+	}{
+		{
+			name: "chain",
+			//   2
+			//   |
+			//   1
+			//   |
+			//   0
+			plan: plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("0", &costProcedureSpec{kind: "source", cost: plan.Cost{NET: 10}}),
+					plan.CreatePhysicalNode("1", &costProcedureSpec{kind: "filter", selectivity: 0.5}),
+					plan.CreatePhysicalNode("2", &costProcedureSpec{kind: "filter", selectivity: 0.5}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+				},
+			},
+			want: plan.Cost{NET: 10, CPU: 150},
+		},
+		{
+			name: "join",
+			//     2
+			//    / \
+			//   0   1
+			plan: plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("0", &costProcedureSpec{kind: "source", cost: plan.Cost{Disk: 5}}),
+					plan.CreatePhysicalNode("1", &costProcedureSpec{kind: "source", cost: plan.Cost{Disk: 5}}),
+					plan.CreatePhysicalNode("2", &costProcedureSpec{kind: "join", cost: plan.Cost{MEM: 200}, selectivity: 1}),
+				},
+				Edges: [][2]int{
+					{0, 2},
+					{1, 2},
+				},
+			},
+			want: plan.Cost{Disk: 10, CPU: 200, MEM: 200},
+		},
+		{
+			name: "logical nodes pass statistics through",
+			//   2
+			//   |
+			//   1
+			//   |
+			//   0
+			plan: plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("0", &costProcedureSpec{kind: "source"}),
+					plantest.CreateLogicalMockNode("1"),
+					plan.CreatePhysicalNode("2", &costProcedureSpec{kind: "filter", selectivity: 0.5}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+				},
+			},
+			want: plan.Cost{CPU: 100},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := plan.ComputeCost(plantest.CreatePlanSpec(&tc.plan))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tc.want, got) {
+				t.Errorf("unexpected cost -want/+got:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestStatistics_Scale(t *testing.T) {
+	stats := plan.Statistics{Cardinality: 100, GroupCardinality: 40}
+	if got, want := stats.Scale(0.5), (plan.Statistics{Cardinality: 50, GroupCardinality: 40}); got != want {
+		t.Errorf("unexpected statistics -want/+got:\n%s", cmp.Diff(want, got))
+	}
+	if got, want := stats.Scale(0.25), (plan.Statistics{Cardinality: 25, GroupCardinality: 25}); got != want {
+		t.Errorf("unexpected statistics -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestCost_Less(t *testing.T) {
+	cpu := plan.Cost{CPU: 100}
+	net := plan.Cost{NET: 20}
+	if !cpu.Less(net) {
+		t.Errorf("expected %v to be cheaper than %v", cpu, net)
+	}
+	if net.Less(cpu) {
+		t.Errorf("expected %v to be more expensive than %v", net, cpu)
+	}
+}
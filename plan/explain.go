@@ -0,0 +1,260 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux"
+)
+
+// RuleApplication records a single rewrite made by a planner rule.
+type RuleApplication struct {
+	// Rule is the name of the rule.
+	Rule string `json:"rule"`
+	// Node is the id of the node the rule matched.
+	Node NodeID `json:"node"`
+	// Result is the id of the node that replaced the matched node.
+	// It is the same as Node when the rule rewrote the node in place.
+	Result NodeID `json:"result"`
+}
+
+// RuleDecline records a rule that matched a node but did not rewrite it.
+type RuleDecline struct {
+	// Rule is the name of the rule.
+	Rule string `json:"rule"`
+	// Node is the id of the node the rule matched.
+	Node NodeID `json:"node"`
+	// Reason is the reason the rule gave for not rewriting the node.
+	Reason string `json:"reason"`
+}
+
+// RuleTrace collects the rules that rewrote the plan
+// in the order they were applied.
+type RuleTrace struct {
+	Applications []RuleApplication
+	// Declines are the rules that matched a node, but did not rewrite it
+	// for a reason they gave with Decline. A rule that rewrote the node
+	// on a later pass of the planner is not included.
+	Declines []RuleDecline
+}
+
+func (t *RuleTrace) record(rule Rule, node, newNode Node) {
+	if t == nil {
+		return
+	}
+	t.Applications = append(t.Applications, RuleApplication{
+		Rule:   rule.Name(),
+		Node:   node.ID(),
+		Result: newNode.ID(),
+	})
+	for i, d := range t.Declines {
+		if d.Rule == rule.Name() && d.Node == node.ID() {
+			t.Declines = append(t.Declines[:i], t.Declines[i+1:]...)
+			break
+		}
+	}
+}
+
+func (t *RuleTrace) decline(rule Rule, node Node, reason string) {
+	for i, d := range t.Declines {
+		if d.Rule == rule.Name() && d.Node == node.ID() {
+			t.Declines[i].Reason = reason
+			return
+		}
+	}
+	t.Declines = append(t.Declines, RuleDecline{
+		Rule:   rule.Name(),
+		Node:   node.ID(),
+		Reason: reason,
+	})
+}
+
+// try uses the rule to rewrite the node and records
+// the reason that the rule gave if it did not rewrite it.
+func (t *RuleTrace) try(ctx context.Context, rule Rule, node Node) (Node, bool, error) {
+	if t == nil {
+		return rule.Rewrite(ctx, node)
+	}
+	reason := new(string)
+	newNode, changed, err := rule.Rewrite(context.WithValue(ctx, declineReasonKey, reason), node)
+	if err != nil {
+		return nil, false, err
+	}
+	if !changed && *reason != "" {
+		t.decline(rule, node, *reason)
+	}
+	return newNode, changed, nil
+}
+
+// rewrite uses the rule to rewrite the node and records the outcome.
+func (t *RuleTrace) rewrite(ctx context.Context, rule Rule, node Node) (Node, bool, error) {
+	newNode, changed, err := t.try(ctx, rule, node)
+	if err != nil {
+		return nil, false, err
+	}
+	if changed {
+		t.record(rule, node, newNode)
+	}
+	return newNode, changed, nil
+}
+
+type declineReasonKeyType int
+
+const declineReasonKey declineReasonKeyType = iota
+
+// Decline is used by a rule to report why it did not rewrite a node
+// that matched its pattern, so that the reason can be explained.
+// It returns the values of a Rewrite that does not change the node.
+func Decline(ctx context.Context, node Node, format string, a ...interface{}) (Node, bool, error) {
+	if reason, ok := ctx.Value(declineReasonKey).(*string); ok {
+		*reason = fmt.Sprintf(format, a...)
+	}
+	return node, false, nil
+}
+
+// WithLogicalRuleTrace records every rule applied by the logical planner in the trace.
+func WithLogicalRuleTrace(trace *RuleTrace) LogicalOption {
+	return logicalOption(func(lp *logicalPlanner) {
+		lp.trace = trace
+	})
+}
+
+// WithPhysicalRuleTrace records every rule applied by the physical planner in the trace.
+func WithPhysicalRuleTrace(trace *RuleTrace) PhysicalOption {
+	return physicalOption(func(pp *physicalPlanner) {
+		pp.trace = trace
+	})
+}
+
+// Description is a snapshot of a plan that does not change
+// when the plan is rewritten by later planning stages.
+type Description struct {
+	Resources flux.ResourceManagement `json:"resources"`
+	Nodes     []NodeDescription       `json:"nodes"`
+}
+
+// NodeDescription describes a single node of a plan.
+type NodeDescription struct {
+	ID           NodeID        `json:"id"`
+	Kind         ProcedureKind `json:"kind"`
+	Physical     bool          `json:"physical"`
+	Predecessors []NodeID      `json:"predecessors,omitempty"`
+	// Trigger is the formatted trigger spec of a physical node.
+	Trigger string `json:"trigger,omitempty"`
+	// Details are provided by procedure specs that implement Detailer.
+	Details string `json:"details,omitempty"`
+	// Cost is the estimated cost of executing a physical node,
+	// including the memory it uses, and Statistics estimate
+	// the data that it produces.
+	Cost       *Cost       `json:"cost,omitempty"`
+	Statistics *Statistics `json:"statistics,omitempty"`
+	// Resources are the execution resources available to a physical node.
+	Resources *NodeResources `json:"resources,omitempty"`
+}
+
+// NodeResources describes the execution resources available to a single node.
+// The memory quota of the plan is shared by all of its nodes
+// and is not divided among them, so the memory of a node
+// is the estimate of its cost.
+type NodeResources struct {
+	// Dedicated reports whether the node runs on a goroutine of its own,
+	// as a source does. Other nodes are run by the concurrency workers
+	// that are shared by every transformation of the plan.
+	Dedicated bool `json:"dedicated"`
+	// Concurrency is the number of goroutines that may run the node.
+	Concurrency int `json:"concurrency"`
+	// DispatcherThroughput is the maximum number of messages the node
+	// processes each time a worker schedules it.
+	// It is zero for a node with a dedicated goroutine.
+	DispatcherThroughput int `json:"dispatcherThroughput,omitempty"`
+	// Memory is the estimated memory used by the node
+	// in the units of its cost.
+	Memory int64 `json:"memory"`
+}
+
+// Describe produces a description of the plan.
+// Nodes are listed so that each node follows all of its predecessors.
+func Describe(p *Spec) (Description, error) {
+	d := Description{
+		Resources: p.Resources,
+	}
+	type estimate struct {
+		cost  Cost
+		stats Statistics
+	}
+	estimates := make(map[Node]estimate)
+	if err := estimateNodes(p, func(node Node, cost Cost, stats Statistics) {
+		estimates[node] = estimate{cost: cost, stats: stats}
+	}); err != nil {
+		return Description{}, err
+	}
+	err := p.BottomUpWalk(func(node Node) error {
+		nd := NodeDescription{
+			ID:   node.ID(),
+			Kind: node.Kind(),
+		}
+		for _, pred := range node.Predecessors() {
+			nd.Predecessors = append(nd.Predecessors, pred.ID())
+		}
+		if ppn, ok := node.(*PhysicalPlanNode); ok {
+			nd.Physical = true
+			if ppn.TriggerSpec != nil {
+				nd.Trigger = FormatTriggerSpec(ppn.TriggerSpec)
+			}
+			if e, ok := estimates[node]; ok {
+				nd.Cost, nd.Statistics = &e.cost, &e.stats
+			}
+			nd.Resources = describeResources(p, node, nd.Cost)
+		}
+		if detailer, ok := node.ProcedureSpec().(Detailer); ok {
+			nd.Details = strings.TrimSpace(detailer.PlanDetails())
+		}
+		d.Nodes = append(d.Nodes, nd)
+		return nil
+	})
+	if err != nil {
+		return Description{}, err
+	}
+	return d, nil
+}
+
+// describeResources returns the resources the executor gives to the physical node.
+// Each source runs on a goroutine of its own and every other node
+// is run by the concurrency workers of the plan.
+func describeResources(p *Spec, node Node, cost *Cost) *NodeResources {
+	r := &NodeResources{}
+	if cost != nil {
+		r.Memory = cost.MEM
+	}
+	if len(node.Predecessors()) == 0 {
+		r.Dedicated = true
+		r.Concurrency = 1
+		return r
+	}
+	r.Concurrency = p.Resources.ConcurrencyQuota
+	r.DispatcherThroughput = p.Resources.DispatcherThroughput
+	return r
+}
+
+// FormatTriggerSpec returns a human readable representation of the trigger spec.
+func FormatTriggerSpec(t TriggerSpec) string {
+	switch t := t.(type) {
+	case NarrowTransformationTriggerSpec:
+		return "narrowTransformation"
+	case AfterWatermarkTriggerSpec:
+		return fmt.Sprintf("afterWatermark(allowedLateness: %v)", t.AllowedLateness)
+	case RepeatedTriggerSpec:
+		return fmt.Sprintf("repeated(%s)", FormatTriggerSpec(t.Trigger))
+	case AfterProcessingTimeTriggerSpec:
+		return fmt.Sprintf("afterProcessingTime(duration: %v)", t.Duration)
+	case AfterAtLeastCountTriggerSpec:
+		return fmt.Sprintf("afterAtLeastCount(count: %d)", t.Count)
+	case OrFinallyTriggerSpec:
+		return fmt.Sprintf("orFinally(main: %s, finally: %s)", FormatTriggerSpec(t.Main), FormatTriggerSpec(t.Finally))
+	case nil:
+		return "none"
+	default:
+		return fmt.Sprintf("%T", t)
+	}
+}
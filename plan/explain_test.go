@@ -0,0 +1,160 @@
+package plan_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+	"github.com/influxdata/flux/values"
+)
+
+func TestDescribe(t *testing.T) {
+	source := plantest.CreatePhysicalMockNode("1")
+	source.TriggerSpec = plan.NarrowTransformationTriggerSpec{}
+	spec := plantest.CreatePlanSpec(&plantest.PlanSpec{
+		Nodes: []plan.Node{
+			plantest.CreateLogicalMockNode("0"),
+			source,
+			plantest.CreatePhysicalMockNode("2"),
+		},
+		Edges: [][2]int{
+			{0, 2},
+			{1, 2},
+		},
+		Resources: flux.ResourceManagement{ConcurrencyQuota: 2, MemoryBytesQuota: 1024},
+	})
+
+	got, err := plan.Describe(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := plan.Description{
+		Resources: flux.ResourceManagement{ConcurrencyQuota: 2, MemoryBytesQuota: 1024},
+		Nodes: []plan.NodeDescription{
+			{
+				ID:   "0",
+				Kind: plantest.MockKind,
+			},
+			{
+				ID:       "1",
+				Kind:     plantest.MockKind,
+				Physical: true,
+				Trigger:  "narrowTransformation",
+				Cost:     &plan.Cost{},
+				Statistics: &plan.Statistics{
+					Cardinality:      plan.DefaultSourceStatistics.Cardinality,
+					GroupCardinality: plan.DefaultSourceStatistics.GroupCardinality,
+				},
+				Resources: &plan.NodeResources{Dedicated: true, Concurrency: 1},
+			},
+			{
+				ID:           "2",
+				Kind:         plantest.MockKind,
+				Physical:     true,
+				Predecessors: []plan.NodeID{"0", "1"},
+				Cost:         &plan.Cost{},
+				// Both inputs are estimated as sources
+				// by the spec of the mock nodes.
+				Statistics: &plan.Statistics{
+					Cardinality:      2 * plan.DefaultSourceStatistics.Cardinality,
+					GroupCardinality: 2 * plan.DefaultSourceStatistics.GroupCardinality,
+				},
+				Resources: &plan.NodeResources{Concurrency: 2},
+			},
+		},
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected description -want/+got:\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestFormatTriggerSpec(t *testing.T) {
+	testCases := []struct {
+		trigger plan.TriggerSpec
+		want    string
+	}{
+		{
+			trigger: plan.NarrowTransformationTriggerSpec{},
+			want:    "narrowTransformation",
+		},
+		{
+			trigger: plan.RepeatedTriggerSpec{
+				Trigger: plan.AfterProcessingTimeTriggerSpec{
+					Duration: values.ConvertDurationNsecs(10 * time.Second),
+				},
+			},
+			want: "repeated(afterProcessingTime(duration: 10s))",
+		},
+		{
+			trigger: plan.OrFinallyTriggerSpec{
+				Main:    plan.AfterAtLeastCountTriggerSpec{Count: 5},
+				Finally: plan.AfterWatermarkTriggerSpec{},
+			},
+			want: "orFinally(main: afterAtLeastCount(count: 5), finally: afterWatermark(allowedLateness: 0ns))",
+		},
+	}
+	for _, tc := range testCases {
+		if got := plan.FormatTriggerSpec(tc.trigger); got != tc.want {
+			t.Errorf("unexpected trigger format -want/+got:\n\t- %s\n\t+ %s", tc.want, got)
+		}
+	}
+}
+
+func TestRuleTrace(t *testing.T) {
+	//   agg
+	//    |
+	//  source
+	spec := plantest.CreatePlanSpec(&plantest.PlanSpec{
+		Nodes: []plan.Node{
+			plan.CreatePhysicalNode("source", &costProcedureSpec{kind: "source"}),
+			plan.CreatePhysicalNode("agg", &costProcedureSpec{kind: "agg"}),
+		},
+		Edges: [][2]int{
+			{0, 1},
+		},
+	})
+
+	var trace plan.RuleTrace
+	pp := plan.NewPhysicalPlanner(
+		plan.OnlyPhysicalRules(declineRule{}, mergeSourceRule{name: "merge"}),
+		plan.DisableValidation(),
+		plan.WithPhysicalRuleTrace(&trace),
+	)
+	if _, err := pp.Plan(context.Background(), spec); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []plan.RuleApplication{
+		{Rule: "merge", Node: "agg", Result: "merged_source_agg"},
+	}
+	if !cmp.Equal(want, trace.Applications) {
+		t.Errorf("unexpected rules -want/+got:\n%s", cmp.Diff(want, trace.Applications))
+	}
+
+	wantDeclines := []plan.RuleDecline{
+		{Rule: "decline", Node: "agg", Reason: "agg cannot be rewritten"},
+	}
+	if !cmp.Equal(wantDeclines, trace.Declines) {
+		t.Errorf("unexpected declined rules -want/+got:\n%s", cmp.Diff(wantDeclines, trace.Declines))
+	}
+}
+
+// declineRule matches the same nodes as mergeSourceRule,
+// but never rewrites them.
+type declineRule struct{}
+
+func (declineRule) Name() string {
+	return "decline"
+}
+
+func (declineRule) Pattern() plan.Pattern {
+	return plan.Pat("agg", plan.Pat("source"))
+}
+
+func (declineRule) Rewrite(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+	return plan.Decline(ctx, node, "%s cannot be rewritten", node.ID())
+}
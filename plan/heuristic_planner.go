@@ -10,6 +10,9 @@ import (
 type heuristicPlanner struct {
 	rules         map[ProcedureKind][]Rule
 	disabledRules map[string]bool
+
+	// trace records the rules that rewrote the plan, if it is set.
+	trace *RuleTrace
 }
 
 func newHeuristicPlanner() *heuristicPlanner {
@@ -48,7 +51,7 @@ func (p *heuristicPlanner) matchRules(ctx context.Context, node Node) (Node, boo
 			continue
 		}
 		if rule.Pattern().Match(node) {
-			newNode, changed, err := rule.Rewrite(ctx, node)
+			newNode, changed, err := p.trace.rewrite(ctx, rule, node)
 			if err != nil {
 				return nil, false, err
 			}
@@ -62,7 +65,7 @@ func (p *heuristicPlanner) matchRules(ctx context.Context, node Node) (Node, boo
 			continue
 		}
 		if rule.Pattern().Match(node) {
-			newNode, changed, err := rule.Rewrite(ctx, node)
+			newNode, changed, err := p.trace.rewrite(ctx, rule, node)
 			if err != nil {
 				return nil, false, err
 			}
@@ -81,6 +84,17 @@ func (p *heuristicPlanner) matchRules(ctx context.Context, node Node) (Node, boo
 // Plan may change its argument and/or return a new instance of Spec, so the correct way to call Plan is:
 //     plan, err = plan.Plan(plan)
 func (p *heuristicPlanner) Plan(ctx context.Context, inputPlan *Spec) (*Spec, error) {
+	return p.plan(ctx, inputPlan, func(ctx context.Context, _ *Spec, node Node) (Node, bool, error) {
+		return p.matchRules(ctx, node)
+	})
+}
+
+// matchFunc rewrites a single node of the plan and reports whether it was changed.
+type matchFunc func(ctx context.Context, plan *Spec, node Node) (Node, bool, error)
+
+// plan traverses the plan until a fixed point is reached,
+// using match to rewrite each of the nodes.
+func (p *heuristicPlanner) plan(ctx context.Context, inputPlan *Spec, match matchFunc) (*Spec, error) {
 	for anyChanged := true; anyChanged; {
 		visited := make(map[Node]struct{})
 
@@ -103,7 +117,7 @@ func (p *heuristicPlanner) Plan(ctx context.Context, inputPlan *Spec) (*Spec, er
 			_, alreadyVisited := visited[node]
 
 			if !alreadyVisited {
-				newNode, changed, err := p.matchRules(ctx, node)
+				newNode, changed, err := match(ctx, inputPlan, node)
 				if err != nil {
 					return nil, err
 				}
@@ -43,7 +43,12 @@ func NewPhysicalPlanner(options ...PhysicalOption) PhysicalPlanner {
 }
 
 func (pp *physicalPlanner) Plan(ctx context.Context, spec *Spec) (*Spec, error) {
-	transformedSpec, err := pp.heuristicPlanner.Plan(ctx, spec)
+	var planner PhysicalPlanner = pp.heuristicPlanner
+	if pp.costBased {
+		planner = newCostBasedPlanner(pp.heuristicPlanner)
+	}
+
+	transformedSpec, err := planner.Plan(ctx, spec)
 	if err != nil {
 		return nil, err
 	}
@@ -76,9 +81,9 @@ func (pp *physicalPlanner) Plan(ctx context.Context, spec *Spec) (*Spec, error)
 		transformedSpec.Resources.MemoryBytesQuota = pp.defaultMemoryLimit
 	}
 
-	// Update concurrency quota
-	if transformedSpec.Resources.ConcurrencyQuota == 0 {
-		transformedSpec.Resources.ConcurrencyQuota = len(transformedSpec.Roots)
+	// Update concurrency quota and dispatcher throughput
+	if err := updateResources(transformedSpec, pp.resources); err != nil {
+		return nil, err
 	}
 
 	return transformedSpec, nil
@@ -105,6 +110,8 @@ type physicalPlanner struct {
 	*heuristicPlanner
 	defaultMemoryLimit int64
 	disableValidation  bool
+	costBased          bool
+	resources          Resources
 }
 
 // PhysicalOption is an option to configure the behavior of the physical plan.
@@ -143,6 +150,16 @@ func RemovePhysicalRules(rules ...string) PhysicalOption {
 	})
 }
 
+// WithCostBasedPlanning enables the cost-based search mode of the physical planner.
+// When more than one rule can rewrite the same node, the planner estimates
+// the cost of the plan produced by each of them and applies the cheapest.
+// Without this option, the rules are applied in the order they were added.
+func WithCostBasedPlanning() PhysicalOption {
+	return physicalOption(func(p *physicalPlanner) {
+		p.costBased = true
+	})
+}
+
 // DisableValidation disables validation in the physical planner.
 func DisableValidation() PhysicalOption {
 	return physicalOption(func(p *physicalPlanner) {
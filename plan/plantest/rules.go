@@ -159,6 +159,8 @@ type RuleTestCase struct {
 	After         *PlanSpec
 	NoChange      bool
 	ValidateError error
+	// Options are added to the options of the physical planner.
+	Options []plan.PhysicalOption
 }
 
 // PhysicalRuleTestHelper will run a rule test case.
@@ -180,6 +182,7 @@ func PhysicalRuleTestHelper(t *testing.T, tc *RuleTestCase) {
 		// Disable validation so that we can avoid having to push a range into every from
 		opts = append(opts, plan.DisableValidation())
 	}
+	opts = append(opts, tc.Options...)
 	physicalPlanner := plan.NewPhysicalPlanner(opts...)
 
 	ctx := tc.Context
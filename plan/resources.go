@@ -0,0 +1,120 @@
+package plan
+
+import (
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+const (
+	// DefaultDispatcherThroughput is the dispatcher throughput of a plan
+	// with at least one concurrency worker for each of its transformations.
+	DefaultDispatcherThroughput = 10
+
+	// MaxDispatcherThroughput is the largest dispatcher throughput
+	// that the planner derives from the shape of a plan.
+	MaxDispatcherThroughput = 100
+)
+
+// Resources overrides the execution resources that the physical planner
+// derives from the shape of the plan.
+// A zero value for a field leaves that resource to the planner.
+// Resources set explicitly on the plan spec take precedence over these.
+type Resources struct {
+	// ConcurrencyQuota is the number of concurrency workers that execute the plan.
+	ConcurrencyQuota int
+	// DispatcherThroughput is the maximum number of messages a worker processes
+	// for a single transformation each time the transformation is scheduled.
+	DispatcherThroughput int
+}
+
+// WithResources overrides the resources the physical planner assigns to plans.
+// When the option is given more than once, the non-zero fields
+// of the later options replace those of the earlier ones.
+func WithResources(r Resources) PhysicalOption {
+	return physicalOption(func(pp *physicalPlanner) {
+		if r.ConcurrencyQuota != 0 {
+			pp.resources.ConcurrencyQuota = r.ConcurrencyQuota
+		}
+		if r.DispatcherThroughput != 0 {
+			pp.resources.DispatcherThroughput = r.DispatcherThroughput
+		}
+	})
+}
+
+// updateResources sets the concurrency quota and dispatcher throughput of the plan
+// that were not set explicitly using the overrides or the shape of the plan.
+func updateResources(plan *Spec, overrides Resources) error {
+	if overrides.ConcurrencyQuota < 0 {
+		return errors.Newf(codes.Invalid, "concurrency quota must be positive, got %d", overrides.ConcurrencyQuota)
+	}
+	if overrides.DispatcherThroughput < 0 {
+		return errors.Newf(codes.Invalid, "dispatcher throughput must be positive, got %d", overrides.DispatcherThroughput)
+	}
+
+	concurrency, throughput, err := deriveResources(plan)
+	if err != nil {
+		return err
+	}
+	if overrides.ConcurrencyQuota != 0 {
+		concurrency = overrides.ConcurrencyQuota
+	}
+	if overrides.DispatcherThroughput != 0 {
+		throughput = overrides.DispatcherThroughput
+	}
+
+	if plan.Resources.ConcurrencyQuota == 0 {
+		plan.Resources.ConcurrencyQuota = concurrency
+	}
+	if plan.Resources.DispatcherThroughput == 0 {
+		plan.Resources.DispatcherThroughput = throughput
+	}
+	return nil
+}
+
+// deriveResources picks the concurrency quota and dispatcher throughput for a plan.
+//
+// Each source and each result may produce work independently of the others,
+// so the plan is given a worker for each of them, but never more workers than
+// there are transformations to schedule. When the workers are shared by more
+// than one transformation, the throughput is raised in proportion so that
+// each worker switches between transformations less often.
+func deriveResources(plan *Spec) (concurrency, throughput int, err error) {
+	var sources, transformations int
+	if err := plan.BottomUpWalk(func(node Node) error {
+		switch {
+		case len(node.Predecessors()) == 0:
+			sources++
+		case !isYield(node):
+			transformations++
+		}
+		return nil
+	}); err != nil {
+		return 0, 0, err
+	}
+
+	concurrency = len(plan.Roots)
+	if sources > concurrency {
+		concurrency = sources
+	}
+	if transformations < concurrency {
+		concurrency = transformations
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	perWorker := (transformations + concurrency - 1) / concurrency
+	if perWorker < 1 {
+		perWorker = 1
+	}
+	throughput = DefaultDispatcherThroughput * perWorker
+	if throughput > MaxDispatcherThroughput {
+		throughput = MaxDispatcherThroughput
+	}
+	return concurrency, throughput, nil
+}
+
+func isYield(node Node) bool {
+	_, ok := node.ProcedureSpec().(YieldProcedureSpec)
+	return ok
+}
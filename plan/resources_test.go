@@ -0,0 +1,125 @@
+package plan_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+)
+
+func TestPhysicalPlanner_Resources(t *testing.T) {
+	// chain is a single source with two transformations.
+	chain := func() *plantest.PlanSpec {
+		return &plantest.PlanSpec{
+			Nodes: []plan.Node{
+				plantest.CreatePhysicalMockNode("0"),
+				plantest.CreatePhysicalMockNode("1"),
+				plantest.CreatePhysicalMockNode("2"),
+			},
+			Edges: [][2]int{
+				{0, 1},
+				{1, 2},
+			},
+		}
+	}
+	// join is two sources merged by a single transformation.
+	join := func() *plantest.PlanSpec {
+		return &plantest.PlanSpec{
+			Nodes: []plan.Node{
+				plantest.CreatePhysicalMockNode("0"),
+				plantest.CreatePhysicalMockNode("1"),
+				plantest.CreatePhysicalMockNode("2"),
+				plantest.CreatePhysicalMockNode("3"),
+				plantest.CreatePhysicalMockNode("4"),
+			},
+			Edges: [][2]int{
+				{0, 1},
+				{2, 3},
+				{1, 4},
+				{3, 4},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		spec    *plantest.PlanSpec
+		options []plan.PhysicalOption
+		want    plan.Resources
+		wantErr string
+	}{
+		{
+			name: "single source",
+			spec: &plantest.PlanSpec{
+				Nodes: []plan.Node{plantest.CreatePhysicalMockNode("0")},
+			},
+			want: plan.Resources{ConcurrencyQuota: 1, DispatcherThroughput: 10},
+		},
+		{
+			name: "workers shared by transformations",
+			spec: chain(),
+			want: plan.Resources{ConcurrencyQuota: 1, DispatcherThroughput: 20},
+		},
+		{
+			name: "worker per source",
+			spec: join(),
+			want: plan.Resources{ConcurrencyQuota: 2, DispatcherThroughput: 20},
+		},
+		{
+			name: "override",
+			spec: join(),
+			options: []plan.PhysicalOption{
+				plan.WithResources(plan.Resources{ConcurrencyQuota: 8}),
+				plan.WithResources(plan.Resources{DispatcherThroughput: 5}),
+			},
+			want: plan.Resources{ConcurrencyQuota: 8, DispatcherThroughput: 5},
+		},
+		{
+			name: "spec takes precedence",
+			spec: func() *plantest.PlanSpec {
+				spec := chain()
+				spec.Resources = flux.ResourceManagement{ConcurrencyQuota: 3}
+				return spec
+			}(),
+			options: []plan.PhysicalOption{
+				plan.WithResources(plan.Resources{ConcurrencyQuota: 8, DispatcherThroughput: 5}),
+			},
+			want: plan.Resources{ConcurrencyQuota: 3, DispatcherThroughput: 5},
+		},
+		{
+			name: "negative override",
+			spec: chain(),
+			options: []plan.PhysicalOption{
+				plan.WithResources(plan.Resources{DispatcherThroughput: -1}),
+			},
+			wantErr: "dispatcher throughput must be positive, got -1",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			options := append([]plan.PhysicalOption{plan.DisableValidation()}, tc.options...)
+			pp, err := plan.NewPhysicalPlanner(options...).Plan(context.Background(), plantest.CreatePlanSpec(tc.spec))
+			if tc.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q, got none", tc.wantErr)
+				} else if got := err.Error(); got != tc.wantErr {
+					t.Fatalf("unexpected error -want/+got:\n\t- %s\n\t+ %s", tc.wantErr, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := plan.Resources{
+				ConcurrencyQuota:     pp.Resources.ConcurrencyQuota,
+				DispatcherThroughput: pp.Resources.DispatcherThroughput,
+			}
+			if got != tc.want {
+				t.Errorf("unexpected resources -want/+got:\n\t- %+v\n\t+ %+v", tc.want, got)
+			}
+		})
+	}
+}
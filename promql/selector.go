@@ -91,7 +91,7 @@ func (t *Transpiler) transpileInstantVectorSelector(v *promql.VectorSelector) *a
 
 	return buildPipeline(
 		// Select all Prometheus data.
-		call("from", map[string]ast.Expression{"bucket": &ast.StringLiteral{Value: t.Bucket}}),
+		t.fromCall(),
 		// Query entire graph range.
 		call("range", map[string]ast.Expression{
 			"start": &ast.DateTimeLiteral{Value: t.Start.Add(-5*time.Minute - v.Offset)},
@@ -129,7 +129,7 @@ func (t *Transpiler) transpileRangeVectorSelector(v *promql.MatrixSelector) *ast
 
 	return buildPipeline(
 		// Select all Prometheus data.
-		call("from", map[string]ast.Expression{"bucket": &ast.StringLiteral{Value: t.Bucket}}),
+		t.fromCall(),
 		// Query entire graph range.
 		call("range", map[string]ast.Expression{
 			"start": &ast.DateTimeLiteral{Value: t.Start.Add(-v.Range - v.Offset)},
@@ -9,6 +9,9 @@ func (t *Transpiler) transpileSubqueryExpr(sq *promql.SubqueryExpr) (ast.Express
 	// 1. Create new transpiler with boundaries and step of subquery.
 	sqt := &Transpiler{
 		Bucket:     t.Bucket,
+		Org:        t.Org,
+		Host:       t.Host,
+		Token:      t.Token,
 		Start:      t.Start.Add(-sq.Range - sq.Offset),
 		End:        t.End.Add(-sq.Offset),
 		Resolution: sq.Step,
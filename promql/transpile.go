@@ -2,6 +2,7 @@ package promql
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -58,6 +59,10 @@ func call(fn string, args map[string]ast.Expression) *ast.CallExpression {
 				Value: v,
 			})
 		}
+		// Sort the arguments so that formatting the transpiled file is deterministic.
+		sort.Slice(props, func(i, j int) bool {
+			return props[i].Key.Key() < props[j].Key.Key()
+		})
 
 		expr.Arguments = []ast.Expression{
 			&ast.ObjectExpression{
@@ -183,13 +188,36 @@ func yieldsTable(expr promql.Expr) bool {
 
 // A Transpiler allows transpiling a PromQL expression into a Flux file
 // according to a chosen evaluation time range.
+//
+// The data is selected with influxdb.from using the Bucket and,
+// when they are set, the Org, Host and Token of the transpiler.
 type Transpiler struct {
 	Bucket     string
+	Org        string
+	Host       string
+	Token      string
 	Start      time.Time
 	End        time.Time
 	Resolution time.Duration
 }
 
+// fromCall returns the influxdb.from call that selects all Prometheus data.
+func (t *Transpiler) fromCall() *ast.CallExpression {
+	args := map[string]ast.Expression{
+		"bucket": &ast.StringLiteral{Value: t.Bucket},
+	}
+	if t.Org != "" {
+		args["org"] = &ast.StringLiteral{Value: t.Org}
+	}
+	if t.Host != "" {
+		args["host"] = &ast.StringLiteral{Value: t.Host}
+	}
+	if t.Token != "" {
+		args["token"] = &ast.StringLiteral{Value: t.Token}
+	}
+	return call("influxdb.from", args)
+}
+
 // Transpile converts a PromQL expression with the time ranges set in the transpiler
 // into a Flux file. The resulting Flux file can be executed and the result needs to
 // be transformed using FluxResultToPromQLValue() (implemented in the InfluxDB repo)
@@ -235,6 +263,7 @@ func (t *Transpiler) Transpile(expr promql.Expr) (*ast.File, error) {
 		Imports: []*ast.ImportDeclaration{
 			{Path: &ast.StringLiteral{Value: "math"}},
 			{Path: &ast.StringLiteral{Value: "internal/promql"}},
+			{Path: &ast.StringLiteral{Value: "influxdata/influxdb"}},
 		},
 		Body: []ast.Statement{
 			&ast.ExpressionStatement{
@@ -0,0 +1,72 @@
+package promql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/promql/v2"
+)
+
+func TestTranspile(t *testing.T) {
+	end := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name       string
+		query      string
+		transpiler *Transpiler
+		want       string
+	}{
+		{
+			name:       "instant rate",
+			query:      `rate(http_requests_total{job="api"}[5m])`,
+			transpiler: &Transpiler{Bucket: "prometheus", Start: end, End: end},
+			want: `import "math"
+import "internal/promql"
+import "influxdata/influxdb"
+
+influxdb.from(bucket: "prometheus")
+	|> range(start: 2019-12-31T23:55:00Z, stop: 2020-01-01T00:00:00Z)
+	|> filter(fn: (r) =>
+		(r.job == "api" and r._field == "http_requests_total"))
+	|> timeShift(duration: 0ns)
+	|> drop(columns: ["_measurement"])
+	|> promql.extrapolatedRate(isCounter: true, isRate: true)
+	|> drop(columns: ["_field", "_time"])
+	|> duplicate(as: "_time", column: "_stop")`,
+		},
+		{
+			name:       "remote bucket",
+			query:      `up`,
+			transpiler: &Transpiler{Bucket: "prometheus", Org: "my-org", Host: "http://localhost:8086", Start: end, End: end},
+			want: `import "math"
+import "internal/promql"
+import "influxdata/influxdb"
+
+influxdb.from(bucket: "prometheus", host: "http://localhost:8086", org: "my-org")
+	|> range(start: 2019-12-31T23:55:00Z, stop: 2020-01-01T00:00:00Z)
+	|> filter(fn: (r) =>
+		(r._field == "up"))
+	|> last()
+	|> timeShift(duration: 0ns)
+	|> drop(columns: ["_measurement"])
+	|> duplicate(as: "_time", column: "_stop")`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := promql.ParseExpr(tc.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file, err := tc.transpiler.Transpile(expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ast.Format(file); tc.want != got {
+				t.Errorf("unexpected transpiled file -want/+got\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
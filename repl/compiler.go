@@ -14,10 +14,15 @@ const CompilerType = "REPL"
 // Compiler specific to the Flux REPL
 type Compiler struct {
 	Spec *flux.Spec `json:"spec"`
+
+	// PhysicalOptions are the options of the physical planner.
+	PhysicalOptions []plan.PhysicalOption `json:"-"`
 }
 
 func (c Compiler) Compile(ctx context.Context, runtime flux.Runtime) (flux.Program, error) {
-	planner := plan.PlannerBuilder{}.Build()
+	pb := plan.PlannerBuilder{}
+	pb.AddPhysicalOptions(c.PhysicalOptions...)
+	planner := pb.Build()
 	ps, err := planner.Plan(ctx, c.Spec)
 	if err != nil {
 		return nil, err
@@ -21,6 +21,7 @@ import (
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/libflux/go/libflux"
 	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values"
@@ -35,6 +36,9 @@ type REPL struct {
 	analyzer *libflux.Analyzer
 	importer interpreter.Importer
 
+	newAllocator func() *memory.Allocator
+	planOptions  []plan.PhysicalOption
+
 	cancelMu   sync.Mutex
 	cancelFunc context.CancelFunc
 }
@@ -44,24 +48,57 @@ var prelude = []string{
 	"influxdata/influxdb",
 }
 
-func New(ctx context.Context, deps flux.Dependencies) *REPL {
-	scope := values.NewScope()
-	importer := runtime.StdLib()
-	for _, p := range prelude {
-		pkg, err := importer.ImportPackageObject(p)
-		if err != nil {
-			panic(err)
-		}
-		pkg.Range(scope.Set)
+// Option configures a REPL.
+type Option func(r *REPL)
+
+// WithImporter sets the importer that resolves the packages
+// imported by the REPL. If the importer is a runtime.PackageAnalyzer,
+// it is also used to analyze each line.
+func WithImporter(importer interpreter.Importer) Option {
+	return func(r *REPL) {
+		r.importer = importer
+	}
+}
+
+// WithAllocator sets the function that creates the allocator
+// for each query, which may limit the memory of the query.
+func WithAllocator(newAllocator func() *memory.Allocator) Option {
+	return func(r *REPL) {
+		r.newAllocator = newAllocator
+	}
+}
+
+// WithPhysicalPlanOptions sets the options of the physical
+// planner that plans each query.
+func WithPhysicalPlanOptions(opts ...plan.PhysicalOption) Option {
+	return func(r *REPL) {
+		r.planOptions = append(r.planOptions, opts...)
 	}
-	return &REPL{
+}
+
+func New(ctx context.Context, deps flux.Dependencies, opts ...Option) *REPL {
+	r := &REPL{
 		ctx:      ctx,
 		deps:     deps,
-		scope:    scope,
+		scope:    values.NewScope(),
 		itrp:     interpreter.NewInterpreter(nil, &lang.ExecOptsConfig{}),
 		analyzer: libflux.NewAnalyzer(),
-		importer: importer,
+		importer: runtime.StdLib(),
+		newAllocator: func() *memory.Allocator {
+			return &memory.Allocator{}
+		},
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	for _, p := range prelude {
+		pkg, err := r.importer.ImportPackageObject(p)
+		if err != nil {
+			panic(err)
+		}
+		pkg.Range(r.scope.Set)
+	}
+	return r
 }
 
 func (r *REPL) Run() {
@@ -202,6 +239,10 @@ func (r *REPL) executeLine(t string) error {
 }
 
 func (r *REPL) analyzeLine(t string) (*semantic.Package, error) {
+	if a, ok := r.importer.(runtime.PackageAnalyzer); ok {
+		return a.Analyze(libflux.ParseString(t))
+	}
+
 	pkg, err := r.analyzer.Analyze(libflux.ParseString(t))
 	if err != nil {
 		return nil, err
@@ -222,14 +263,15 @@ func (r *REPL) doQuery(ctx context.Context, spec *flux.Spec, deps flux.Dependenc
 	defer r.clearCancel()
 
 	c := Compiler{
-		Spec: spec,
+		Spec:            spec,
+		PhysicalOptions: r.planOptions,
 	}
 
 	program, err := c.Compile(ctx, runtime.Default)
 	if err != nil {
 		return err
 	}
-	alloc := &memory.Allocator{}
+	alloc := r.newAllocator()
 
 	qry, err := program.Start(deps.Inject(ctx), alloc)
 	if err != nil {
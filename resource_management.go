@@ -21,6 +21,10 @@ type ResourceManagement struct {
 	// There is a small amount of overhead memory being consumed by a query that will not be counted towards this limit.
 	// A zero value indicates unlimited.
 	MemoryBytesQuota int64 `json:"memory_bytes_quota"`
+	// DispatcherThroughput is the maximum number of messages a concurrency worker
+	// processes for a single transformation each time the transformation is scheduled.
+	// A zero value indicates the planner can pick the optimal throughput.
+	DispatcherThroughput int `json:"dispatcher_throughput"`
 }
 
 // Priority is an integer that represents the query priority.
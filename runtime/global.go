@@ -32,11 +32,23 @@ func ReplacePackageValue(pkgpath, name string, value values.Value) {
 	}
 }
 
+// StdlibPackage returns the AST of the builtin package with
+// the import path or nil if no such package was registered.
+func StdlibPackage(path string) *ast.Package {
+	return Default.astPkgs[path]
+}
+
 // StdLib returns an importer for the Flux standard library.
 func StdLib() interpreter.Importer {
 	return Default.Stdlib()
 }
 
+// WithImporter returns a runtime that evaluates scripts with
+// the importer instead of an importer for the standard library.
+func WithImporter(importer interpreter.Importer) flux.Runtime {
+	return Default.WithImporter(importer)
+}
+
 // Prelude returns a scope object representing the Flux universe block
 func Prelude() values.Scope {
 	return Default.Prelude()
@@ -22,6 +22,11 @@ var (
 type importer struct {
 	r    *runtime
 	pkgs map[string]*interpreter.Package
+
+	// local holds the analyzed packages that are
+	// not part of the runtime, such as the packages
+	// loaded by a ModuleImporter.
+	local map[string]*semantic.Package
 }
 
 func (imp *importer) Import(path string) (semantic.MonoType, error) {
@@ -60,6 +65,9 @@ func (imp *importer) ImportPackageObject(path string) (*interpreter.Package, err
 	// Find the package for the given import path.
 	semPkg, ok := imp.r.pkgs[path]
 	if !ok {
+		semPkg, ok = imp.local[path]
+	}
+	if !ok || semPkg == nil {
 		return nil, errors.Newf(codes.Invalid, "invalid import path %s", path)
 	}
 
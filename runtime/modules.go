@@ -0,0 +1,225 @@
+package runtime
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/internal/token"
+	"github.com/influxdata/flux/libflux/go/libflux"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/semantic"
+)
+
+// FluxPathEnv is the environment variable that lists the directories
+// searched for packages that are not part of the standard library.
+// It uses the same separator as the PATH environment variable.
+const FluxPathEnv = "FLUXPATH"
+
+// FluxPath returns the directories listed in the FLUXPATH environment variable.
+func FluxPath() []string {
+	return SplitFluxPath(os.Getenv(FluxPathEnv))
+}
+
+// SplitFluxPath splits a list of directories in the format
+// of the FLUXPATH environment variable.
+func SplitFluxPath(s string) []string {
+	var dirs []string
+	for _, dir := range filepath.SplitList(s) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// PackageAnalyzer is implemented by importers that resolve packages
+// the runtime does not know about. The packages that import them must
+// be analyzed with the importer so their types are known.
+type PackageAnalyzer interface {
+	// Analyze performs semantic analysis of the package
+	// and of the packages it imports.
+	Analyze(astPkg flux.ASTHandle) (*semantic.Package, error)
+}
+
+// ModuleImporter resolves packages from the standard library and
+// from directories of .flux files within a search path.
+// The package with the import path "acme/alerts" is made of the
+// .flux files in the acme/alerts directory of the first directory
+// in the search path that contains it. Packages from the standard
+// library cannot be replaced.
+//
+// The packages are type checked when a package that imports them
+// is analyzed with Analyze. Analyzed and evaluated packages are cached,
+// so a ModuleImporter should only be used for a single program or
+// REPL session.
+type ModuleImporter struct {
+	importer
+	dirs     []string
+	analyzer *libflux.Analyzer
+}
+
+// NewModuleImporter creates an importer that resolves packages
+// from the given directories and the standard library.
+func NewModuleImporter(dirs []string) *ModuleImporter {
+	if !Default.finalized {
+		panic("builtins not finalized")
+	}
+	return &ModuleImporter{
+		importer: importer{
+			r:     Default,
+			local: make(map[string]*semantic.Package),
+		},
+		dirs:     dirs,
+		analyzer: libflux.NewAnalyzer(),
+	}
+}
+
+// Analyze loads the packages imported by the package and then
+// performs semantic analysis of the package with their types.
+//
+// Like AnalyzePackage, Analyze consumes the AST.
+func (m *ModuleImporter) Analyze(astPkg flux.ASTHandle) (*semantic.Package, error) {
+	hdl := astPkg.(*libflux.ASTPkg)
+	defer hdl.Free()
+
+	bs, err := hdl.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	node, err := ast.UnmarshalNode(bs)
+	if err != nil {
+		return nil, err
+	}
+	pkg, ok := node.(*ast.Package)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "expected a package, got %s", node.Type())
+	}
+	if err := m.load(importPaths(pkg)); err != nil {
+		return nil, err
+	}
+
+	sem, err := m.analyzer.Analyze(hdl)
+	if err != nil {
+		return nil, err
+	}
+	return deserializePackage(sem)
+}
+
+// load analyzes each of the local packages in paths
+// after the local packages that they import.
+func (m *ModuleImporter) load(paths []string) error {
+	for _, p := range paths {
+		if _, ok := m.r.pkgs[p]; ok {
+			continue
+		}
+		if pkg, ok := m.local[p]; ok {
+			if pkg == nil {
+				return errors.Newf(codes.Invalid, "detected cyclical import for package path %q", p)
+			}
+			continue
+		}
+
+		dir, ok := m.find(p)
+		if !ok {
+			// Leave reporting the unknown import to the analyzer.
+			continue
+		}
+
+		// Mark down that we are currently loading this package
+		// so that we can detect a circular import.
+		m.local[p] = nil
+		pkg, err := m.loadPackage(p, dir)
+		if err != nil {
+			delete(m.local, p)
+			return err
+		}
+		m.local[p] = pkg
+	}
+	return nil
+}
+
+func (m *ModuleImporter) loadPackage(importPath, dir string) (*semantic.Package, error) {
+	pkgs, err := parser.ParseDir(new(token.FileSet), dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, codes.Inherit, "failed to read package %q", importPath)
+	}
+	if len(pkgs) != 1 {
+		names := make([]string, 0, len(pkgs))
+		for name := range pkgs {
+			names = append(names, name)
+		}
+		return nil, errors.Newf(codes.Invalid, "package %q must contain exactly one package, found %v", importPath, names)
+	}
+
+	var pkg *ast.Package
+	for _, p := range pkgs {
+		pkg = p
+	}
+	if pkg.Package == "main" {
+		return nil, errors.Newf(codes.Invalid, "package %q must not be a main package", importPath)
+	}
+	if ast.Check(pkg) > 0 {
+		return nil, errors.Wrapf(ast.GetError(pkg), codes.Invalid, "failed to parse package %q", importPath)
+	}
+	pkg.Path = importPath
+
+	if err := m.load(importPaths(pkg)); err != nil {
+		return nil, err
+	}
+
+	bs, err := json.Marshal(pkg)
+	if err != nil {
+		return nil, err
+	}
+	hdl, err := libflux.ParseJSON(bs)
+	if err != nil {
+		return nil, err
+	}
+	sem, err := m.analyzer.AnalyzePackage(importPath, hdl)
+	if err != nil {
+		return nil, errors.Wrapf(err, codes.Inherit, "failed to analyze package %q", importPath)
+	}
+	return deserializePackage(sem)
+}
+
+// find returns the first directory in the search path
+// that contains the package with the import path.
+func (m *ModuleImporter) find(importPath string) (string, bool) {
+	if importPath == "" || path.Clean(importPath) != importPath ||
+		path.IsAbs(importPath) || strings.HasPrefix(importPath, ".") {
+		return "", false
+	}
+	for _, dir := range m.dirs {
+		dir = filepath.Join(dir, filepath.FromSlash(importPath))
+		if fi, err := os.Stat(dir); err == nil && fi.IsDir() {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+func importPaths(pkg *ast.Package) []string {
+	var paths []string
+	for _, f := range pkg.Files {
+		for _, imp := range f.Imports {
+			paths = append(paths, imp.Path.Value)
+		}
+	}
+	return paths
+}
+
+func deserializePackage(sem *libflux.SemanticPkg) (*semantic.Package, error) {
+	defer sem.Free()
+	bs, err := sem.MarshalFB()
+	if err != nil {
+		return nil, err
+	}
+	return semantic.DeserializeFromFlatBuffer(bs)
+}
@@ -0,0 +1,143 @@
+package runtime_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/flux/dependencies/dependenciestest"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/runtime"
+)
+
+// writeModules writes the files to a temporary directory
+// and returns the directory.
+func writeModules(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "fluxpath")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, src := range files {
+		fpath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fpath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(fpath, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestModuleImporter(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"acme/alerts/alerts.flux": `package alerts
+
+import "strings"
+import "acme/levels"
+
+crit = (name) => strings.toUpper(v: name) + ": " + levels.crit`,
+		"acme/levels/levels.flux": `package levels
+
+crit = "critical"`,
+	})
+	defer os.RemoveAll(dir)
+
+	src := `
+		import "acme/alerts"
+
+		msg = alerts.crit(name: "disk")`
+	h, err := parser.ParseToHandle([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := dependenciestest.Default().Inject(context.Background())
+	r := runtime.WithImporter(runtime.NewModuleImporter([]string{dir}))
+	_, scope, err := r.Eval(ctx, h, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, ok := scope.Lookup("msg")
+	if !ok {
+		t.Fatal("msg is not defined")
+	}
+	if want, got := "DISK: critical", msg.Str(); want != got {
+		t.Errorf("unexpected value -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+}
+
+func TestModuleImporter_Errors(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"cycle/a/a.flux": `package a
+
+import "cycle/b"
+
+x = b.x`,
+		"cycle/b/b.flux": `package b
+
+import "cycle/a"
+
+x = a.x`,
+		"bad/types/types.flux": `package types
+
+x = 1 + "a"`,
+		"bad/main/main.flux": `x = 1`,
+	})
+	defer os.RemoveAll(dir)
+
+	for _, tc := range []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "cycle",
+			src:  `import "cycle/a"`,
+			want: `detected cyclical import for package path "cycle/a"`,
+		},
+		{
+			name: "type error",
+			src:  `import "bad/types"`,
+			want: `failed to analyze package "bad/types"`,
+		},
+		{
+			name: "main package",
+			src:  `import "bad/main"`,
+			want: `package "bad/main" must not be a main package`,
+		},
+		{
+			name: "missing package",
+			src:  `import "acme/missing"`,
+			want: `invalid import path acme/missing`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := parser.ParseToHandle([]byte(tc.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := dependenciestest.Default().Inject(context.Background())
+			r := runtime.WithImporter(runtime.NewModuleImporter([]string{dir}))
+			if _, _, err := r.Eval(ctx, h, nil); err == nil {
+				t.Fatal("expected error")
+			} else if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected error to contain %q, got %q", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestSplitFluxPath(t *testing.T) {
+	sep := string(os.PathListSeparator)
+	got := runtime.SplitFluxPath("a" + sep + sep + "b/c")
+	if want := []string{"a", "b/c"}; strings.Join(want, ",") != strings.Join(got, ",") {
+		t.Errorf("unexpected directories -want/+got:\n\t- %v\n\t+ %v", want, got)
+	}
+}
@@ -118,13 +118,24 @@ func (r *runtime) Prelude() values.Scope {
 }
 
 func (r *runtime) Eval(ctx context.Context, astPkg flux.ASTHandle, es interpreter.ExecOptsConfig, opts ...flux.ScopeMutator) ([]interpreter.SideEffect, values.Scope, error) {
-	semPkg, err := AnalyzePackage(astPkg)
+	return r.eval(ctx, astPkg, es, &importer{r: r}, opts...)
+}
+
+func (r *runtime) eval(ctx context.Context, astPkg flux.ASTHandle, es interpreter.ExecOptsConfig, importer interpreter.Importer, opts ...flux.ScopeMutator) ([]interpreter.SideEffect, values.Scope, error) {
+	var (
+		semPkg *semantic.Package
+		err    error
+	)
+	if a, ok := importer.(PackageAnalyzer); ok {
+		semPkg, err = a.Analyze(astPkg)
+	} else {
+		semPkg, err = AnalyzePackage(astPkg)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
 
 	// Construct the initial scope for this package.
-	importer := &importer{r: r}
 	scope, err := r.newScopeFor("main", importer)
 	if err != nil {
 		return nil, nil, err
@@ -144,6 +155,17 @@ func (r *runtime) Eval(ctx context.Context, astPkg flux.ASTHandle, es interprete
 	return sideEffects, scope, nil
 }
 
+// importerRuntime is a runtime that evaluates
+// scripts with a specific importer.
+type importerRuntime struct {
+	*runtime
+	importer interpreter.Importer
+}
+
+func (r *importerRuntime) Eval(ctx context.Context, astPkg flux.ASTHandle, es interpreter.ExecOptsConfig, opts ...flux.ScopeMutator) ([]interpreter.SideEffect, values.Scope, error) {
+	return r.eval(ctx, astPkg, es, r.importer, opts...)
+}
+
 // newScopeFor constructs a new scope for the given package using the
 // passed in importer.
 func (r *runtime) newScopeFor(pkgpath string, imp interpreter.Importer) (values.Scope, error) {
@@ -180,6 +202,19 @@ func (r *runtime) Stdlib() interpreter.Importer {
 	return &importer{r: r}
 }
 
+// WithImporter returns a runtime that evaluates scripts with the importer
+// instead of an importer for the standard library. If the importer is
+// a PackageAnalyzer, it is also used to analyze the scripts.
+func (r *runtime) WithImporter(importer interpreter.Importer) flux.Runtime {
+	if !r.finalized {
+		panic("builtins not finalized")
+	}
+	return &importerRuntime{
+		runtime:  r,
+		importer: importer,
+	}
+}
+
 func (r *runtime) compilePackages() error {
 	pkgs := make(map[string]*semantic.Package)
 	for _, pkg := range r.astPkgs {
@@ -21,11 +21,11 @@ var pkgAST = &ast.Package{
 			Errors: nil,
 			Loc: &ast.SourceLocation{
 				End: ast.Position{
-					Column: 12,
-					Line:   5,
+					Column: 15,
+					Line:   16,
 				},
 				File:   "http.flux",
-				Source: "package http\n\n// Get submits an HTTP get request to the specified URL with headers\n// Returns HTTP status code and body as a byte array\nbuiltin get",
+				Source: "package http\n\n// Get submits an HTTP get request to the specified URL with headers\n// Returns HTTP status code and body as a byte array\n// The request fails if it does not complete within the timeout, 30s by default, unless noTimeout is true\nbuiltin get : (url: string, ?headers: A, ?timeout: duration, ?noTimeout: bool) => {statusCode: int , body: bytes , headers: B} where A: Record, B: Record\n\n// Request submits an HTTP request with the method to the specified URL with headers, query parameters and body\n// Returns HTTP status code, headers and body as a byte array\nbuiltin request : (method: string, url: string, ?headers: A, ?body: bytes, ?timeout: duration, ?noTimeout: bool, ?query: B) => {statusCode: int , body: bytes , headers: C} where A: Record, B: Record, C: Record\n\n// GetJSON submits an HTTP get request and decodes the JSON object, or array of objects, of the response into a table\nbuiltin getJSON : (url: string, ?headers: A, ?timeout: duration, ?noTimeout: bool, ?query: B) => [C] where A: Record, B: Record, C: Record\n\n// GetCSV submits an HTTP get request and decodes the annotated CSV of the response into tables\nbuiltin getCSV",
 				Start: ast.Position{
 					Column: 1,
 					Line:   1,
@@ -38,13 +38,13 @@ var pkgAST = &ast.Package{
 				Loc: &ast.SourceLocation{
 					End: ast.Position{
 						Column: 12,
-						Line:   5,
+						Line:   6,
 					},
 					File:   "http.flux",
 					Source: "builtin get",
 					Start: ast.Position{
 						Column: 1,
-						Line:   5,
+						Line:   6,
 					},
 				},
 			},
@@ -54,13 +54,13 @@ var pkgAST = &ast.Package{
 					Loc: &ast.SourceLocation{
 						End: ast.Position{
 							Column: 12,
-							Line:   5,
+							Line:   6,
 						},
 						File:   "http.flux",
 						Source: "get",
 						Start: ast.Position{
 							Column: 9,
-							Line:   5,
+							Line:   6,
 						},
 					},
 				},
@@ -71,14 +71,14 @@ var pkgAST = &ast.Package{
 					Errors: nil,
 					Loc: &ast.SourceLocation{
 						End: ast.Position{
-							Column: 136,
-							Line:   5,
+							Column: 154,
+							Line:   6,
 						},
 						File:   "http.flux",
-						Source: "(url: string, ?headers: A, ?timeout: duration) => {statusCode: int , body: bytes , headers: B} where A: Record, B: Record",
+						Source: "(url: string, ?headers: A, ?timeout: duration, ?noTimeout: bool) => {statusCode: int , body: bytes , headers: B} where A: Record, B: Record",
 						Start: ast.Position{
 							Column: 15,
-							Line:   5,
+							Line:   6,
 						},
 					},
 				},
@@ -87,14 +87,14 @@ var pkgAST = &ast.Package{
 						Errors: nil,
 						Loc: &ast.SourceLocation{
 							End: ast.Position{
-								Column: 125,
-								Line:   5,
+								Column: 143,
+								Line:   6,
 							},
 							File:   "http.flux",
 							Source: "A: Record",
 							Start: ast.Position{
-								Column: 116,
-								Line:   5,
+								Column: 134,
+								Line:   6,
 							},
 						},
 					},
@@ -103,14 +103,14 @@ var pkgAST = &ast.Package{
 							Errors: nil,
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
-									Column: 125,
-									Line:   5,
+									Column: 143,
+									Line:   6,
 								},
 								File:   "http.flux",
 								Source: "Record",
 								Start: ast.Position{
-									Column: 119,
-									Line:   5,
+									Column: 137,
+									Line:   6,
 								},
 							},
 						},
@@ -121,14 +121,14 @@ var pkgAST = &ast.Package{
 							Errors: nil,
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
-									Column: 117,
-									Line:   5,
+									Column: 135,
+									Line:   6,
 								},
 								File:   "http.flux",
 								Source: "A",
 								Start: ast.Position{
-									Column: 116,
-									Line:   5,
+									Column: 134,
+									Line:   6,
 								},
 							},
 						},
@@ -139,14 +139,14 @@ var pkgAST = &ast.Package{
 						Errors: nil,
 						Loc: &ast.SourceLocation{
 							End: ast.Position{
-								Column: 136,
-								Line:   5,
+								Column: 154,
+								Line:   6,
 							},
 							File:   "http.flux",
 							Source: "B: Record",
 							Start: ast.Position{
-								Column: 127,
-								Line:   5,
+								Column: 145,
+								Line:   6,
 							},
 						},
 					},
@@ -155,14 +155,14 @@ var pkgAST = &ast.Package{
 							Errors: nil,
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
-									Column: 136,
-									Line:   5,
+									Column: 154,
+									Line:   6,
 								},
 								File:   "http.flux",
 								Source: "Record",
 								Start: ast.Position{
-									Column: 130,
-									Line:   5,
+									Column: 148,
+									Line:   6,
 								},
 							},
 						},
@@ -173,14 +173,14 @@ var pkgAST = &ast.Package{
 							Errors: nil,
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
-									Column: 128,
-									Line:   5,
+									Column: 146,
+									Line:   6,
 								},
 								File:   "http.flux",
 								Source: "B",
 								Start: ast.Position{
-									Column: 127,
-									Line:   5,
+									Column: 145,
+									Line:   6,
 								},
 							},
 						},
@@ -192,14 +192,14 @@ var pkgAST = &ast.Package{
 						Errors: nil,
 						Loc: &ast.SourceLocation{
 							End: ast.Position{
-								Column: 109,
-								Line:   5,
+								Column: 127,
+								Line:   6,
 							},
 							File:   "http.flux",
-							Source: "(url: string, ?headers: A, ?timeout: duration) => {statusCode: int , body: bytes , headers: B}",
+							Source: "(url: string, ?headers: A, ?timeout: duration, ?noTimeout: bool) => {statusCode: int , body: bytes , headers: B}",
 							Start: ast.Position{
 								Column: 15,
-								Line:   5,
+								Line:   6,
 							},
 						},
 					},
@@ -209,13 +209,13 @@ var pkgAST = &ast.Package{
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
 									Column: 27,
-									Line:   5,
+									Line:   6,
 								},
 								File:   "http.flux",
 								Source: "url: string",
 								Start: ast.Position{
 									Column: 16,
-									Line:   5,
+									Line:   6,
 								},
 							},
 						},
@@ -226,13 +226,13 @@ var pkgAST = &ast.Package{
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
 										Column: 19,
-										Line:   5,
+										Line:   6,
 									},
 									File:   "http.flux",
 									Source: "url",
 									Start: ast.Position{
 										Column: 16,
-										Line:   5,
+										Line:   6,
 									},
 								},
 							},
@@ -244,13 +244,13 @@ var pkgAST = &ast.Package{
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
 										Column: 27,
-										Line:   5,
+										Line:   6,
 									},
 									File:   "http.flux",
 									Source: "string",
 									Start: ast.Position{
 										Column: 21,
-										Line:   5,
+										Line:   6,
 									},
 								},
 							},
@@ -260,13 +260,13 @@ var pkgAST = &ast.Package{
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
 											Column: 27,
-											Line:   5,
+											Line:   6,
 										},
 										File:   "http.flux",
 										Source: "string",
 										Start: ast.Position{
 											Column: 21,
-											Line:   5,
+											Line:   6,
 										},
 									},
 								},
@@ -279,13 +279,13 @@ var pkgAST = &ast.Package{
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
 									Column: 40,
-									Line:   5,
+									Line:   6,
 								},
 								File:   "http.flux",
 								Source: "?headers: A",
 								Start: ast.Position{
 									Column: 29,
-									Line:   5,
+									Line:   6,
 								},
 							},
 						},
@@ -296,13 +296,13 @@ var pkgAST = &ast.Package{
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
 										Column: 37,
-										Line:   5,
+										Line:   6,
 									},
 									File:   "http.flux",
 									Source: "headers",
 									Start: ast.Position{
 										Column: 30,
-										Line:   5,
+										Line:   6,
 									},
 								},
 							},
@@ -314,13 +314,13 @@ var pkgAST = &ast.Package{
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
 										Column: 40,
-										Line:   5,
+										Line:   6,
 									},
 									File:   "http.flux",
 									Source: "A",
 									Start: ast.Position{
 										Column: 39,
-										Line:   5,
+										Line:   6,
 									},
 								},
 							},
@@ -330,13 +330,13 @@ var pkgAST = &ast.Package{
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
 											Column: 40,
-											Line:   5,
+											Line:   6,
 										},
 										File:   "http.flux",
 										Source: "A",
 										Start: ast.Position{
 											Column: 39,
-											Line:   5,
+											Line:   6,
 										},
 									},
 								},
@@ -349,13 +349,13 @@ var pkgAST = &ast.Package{
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
 									Column: 60,
-									Line:   5,
+									Line:   6,
 								},
 								File:   "http.flux",
 								Source: "?timeout: duration",
 								Start: ast.Position{
 									Column: 42,
-									Line:   5,
+									Line:   6,
 								},
 							},
 						},
@@ -366,13 +366,13 @@ var pkgAST = &ast.Package{
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
 										Column: 50,
-										Line:   5,
+										Line:   6,
 									},
 									File:   "http.flux",
 									Source: "timeout",
 									Start: ast.Position{
 										Column: 43,
-										Line:   5,
+										Line:   6,
 									},
 								},
 							},
@@ -384,13 +384,13 @@ var pkgAST = &ast.Package{
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
 										Column: 60,
-										Line:   5,
+										Line:   6,
 									},
 									File:   "http.flux",
 									Source: "duration",
 									Start: ast.Position{
 										Column: 52,
-										Line:   5,
+										Line:   6,
 									},
 								},
 							},
@@ -400,33 +400,103 @@ var pkgAST = &ast.Package{
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
 											Column: 60,
-											Line:   5,
+											Line:   6,
 										},
 										File:   "http.flux",
 										Source: "duration",
 										Start: ast.Position{
 											Column: 52,
-											Line:   5,
+											Line:   6,
 										},
 									},
 								},
 								Name: "duration",
 							},
 						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 78,
+									Line:   6,
+								},
+								File:   "http.flux",
+								Source: "?noTimeout: bool",
+								Start: ast.Position{
+									Column: 62,
+									Line:   6,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 72,
+										Line:   6,
+									},
+									File:   "http.flux",
+									Source: "noTimeout",
+									Start: ast.Position{
+										Column: 63,
+										Line:   6,
+									},
+								},
+							},
+							Name: "noTimeout",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 78,
+										Line:   6,
+									},
+									File:   "http.flux",
+									Source: "bool",
+									Start: ast.Position{
+										Column: 74,
+										Line:   6,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 78,
+											Line:   6,
+										},
+										File:   "http.flux",
+										Source: "bool",
+										Start: ast.Position{
+											Column: 74,
+											Line:   6,
+										},
+									},
+								},
+								Name: "bool",
+							},
+						},
 					}},
 					Return: &ast.RecordType{
 						BaseNode: ast.BaseNode{
 							Errors: nil,
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
-									Column: 109,
-									Line:   5,
+									Column: 127,
+									Line:   6,
 								},
 								File:   "http.flux",
 								Source: "{statusCode: int , body: bytes , headers: B}",
 								Start: ast.Position{
-									Column: 65,
-									Line:   5,
+									Column: 83,
+									Line:   6,
 								},
 							},
 						},
@@ -435,14 +505,14 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 81,
-										Line:   5,
+										Column: 99,
+										Line:   6,
 									},
 									File:   "http.flux",
 									Source: "statusCode: int",
 									Start: ast.Position{
-										Column: 66,
-										Line:   5,
+										Column: 84,
+										Line:   6,
 									},
 								},
 							},
@@ -451,14 +521,14 @@ var pkgAST = &ast.Package{
 									Errors: nil,
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
-											Column: 76,
-											Line:   5,
+											Column: 94,
+											Line:   6,
 										},
 										File:   "http.flux",
 										Source: "statusCode",
 										Start: ast.Position{
-											Column: 66,
-											Line:   5,
+											Column: 84,
+											Line:   6,
 										},
 									},
 								},
@@ -469,14 +539,14 @@ var pkgAST = &ast.Package{
 									Errors: nil,
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
-											Column: 81,
-											Line:   5,
+											Column: 99,
+											Line:   6,
 										},
 										File:   "http.flux",
 										Source: "int",
 										Start: ast.Position{
-											Column: 78,
-											Line:   5,
+											Column: 96,
+											Line:   6,
 										},
 									},
 								},
@@ -485,14 +555,14 @@ var pkgAST = &ast.Package{
 										Errors: nil,
 										Loc: &ast.SourceLocation{
 											End: ast.Position{
-												Column: 81,
-												Line:   5,
+												Column: 99,
+												Line:   6,
 											},
 											File:   "http.flux",
 											Source: "int",
 											Start: ast.Position{
-												Column: 78,
-												Line:   5,
+												Column: 96,
+												Line:   6,
 											},
 										},
 									},
@@ -504,14 +574,14 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 95,
-										Line:   5,
+										Column: 113,
+										Line:   6,
 									},
 									File:   "http.flux",
 									Source: "body: bytes",
 									Start: ast.Position{
-										Column: 84,
-										Line:   5,
+										Column: 102,
+										Line:   6,
 									},
 								},
 							},
@@ -520,14 +590,14 @@ var pkgAST = &ast.Package{
 									Errors: nil,
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
-											Column: 88,
-											Line:   5,
+											Column: 106,
+											Line:   6,
 										},
 										File:   "http.flux",
 										Source: "body",
 										Start: ast.Position{
-											Column: 84,
-											Line:   5,
+											Column: 102,
+											Line:   6,
 										},
 									},
 								},
@@ -538,14 +608,14 @@ var pkgAST = &ast.Package{
 									Errors: nil,
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
-											Column: 95,
-											Line:   5,
+											Column: 113,
+											Line:   6,
 										},
 										File:   "http.flux",
 										Source: "bytes",
 										Start: ast.Position{
-											Column: 90,
-											Line:   5,
+											Column: 108,
+											Line:   6,
 										},
 									},
 								},
@@ -554,14 +624,14 @@ var pkgAST = &ast.Package{
 										Errors: nil,
 										Loc: &ast.SourceLocation{
 											End: ast.Position{
-												Column: 95,
-												Line:   5,
+												Column: 113,
+												Line:   6,
 											},
 											File:   "http.flux",
 											Source: "bytes",
 											Start: ast.Position{
-												Column: 90,
-												Line:   5,
+												Column: 108,
+												Line:   6,
 											},
 										},
 									},
@@ -573,14 +643,14 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 108,
-										Line:   5,
+										Column: 126,
+										Line:   6,
 									},
 									File:   "http.flux",
 									Source: "headers: B",
 									Start: ast.Position{
-										Column: 98,
-										Line:   5,
+										Column: 116,
+										Line:   6,
 									},
 								},
 							},
@@ -589,14 +659,14 @@ var pkgAST = &ast.Package{
 									Errors: nil,
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
-											Column: 105,
-											Line:   5,
+											Column: 123,
+											Line:   6,
 										},
 										File:   "http.flux",
 										Source: "headers",
 										Start: ast.Position{
-											Column: 98,
-											Line:   5,
+											Column: 116,
+											Line:   6,
 										},
 									},
 								},
@@ -607,14 +677,14 @@ var pkgAST = &ast.Package{
 									Errors: nil,
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
-											Column: 108,
-											Line:   5,
+											Column: 126,
+											Line:   6,
 										},
 										File:   "http.flux",
 										Source: "B",
 										Start: ast.Position{
-											Column: 107,
-											Line:   5,
+											Column: 125,
+											Line:   6,
 										},
 									},
 								},
@@ -623,14 +693,14 @@ var pkgAST = &ast.Package{
 										Errors: nil,
 										Loc: &ast.SourceLocation{
 											End: ast.Position{
-												Column: 108,
-												Line:   5,
+												Column: 126,
+												Line:   6,
 											},
 											File:   "http.flux",
 											Source: "B",
 											Start: ast.Position{
-												Column: 107,
-												Line:   5,
+												Column: 125,
+												Line:   6,
 											},
 										},
 									},
@@ -642,6 +712,2204 @@ var pkgAST = &ast.Package{
 					},
 				},
 			},
+		}, &ast.BuiltinStatement{
+			BaseNode: ast.BaseNode{
+				Errors: nil,
+				Loc: &ast.SourceLocation{
+					End: ast.Position{
+						Column: 16,
+						Line:   10,
+					},
+					File:   "http.flux",
+					Source: "builtin request",
+					Start: ast.Position{
+						Column: 1,
+						Line:   10,
+					},
+				},
+			},
+			ID: &ast.Identifier{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 16,
+							Line:   10,
+						},
+						File:   "http.flux",
+						Source: "request",
+						Start: ast.Position{
+							Column: 9,
+							Line:   10,
+						},
+					},
+				},
+				Name: "request",
+			},
+			Ty: ast.TypeExpression{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 210,
+							Line:   10,
+						},
+						File:   "http.flux",
+						Source: "(method: string, url: string, ?headers: A, ?body: bytes, ?timeout: duration, ?noTimeout: bool, ?query: B) => {statusCode: int , body: bytes , headers: C} where A: Record, B: Record, C: Record",
+						Start: ast.Position{
+							Column: 19,
+							Line:   10,
+						},
+					},
+				},
+				Constraints: []*ast.TypeConstraint{&ast.TypeConstraint{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 188,
+								Line:   10,
+							},
+							File:   "http.flux",
+							Source: "A: Record",
+							Start: ast.Position{
+								Column: 179,
+								Line:   10,
+							},
+						},
+					},
+					Kinds: []*ast.Identifier{&ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 188,
+									Line:   10,
+								},
+								File:   "http.flux",
+								Source: "Record",
+								Start: ast.Position{
+									Column: 182,
+									Line:   10,
+								},
+							},
+						},
+						Name: "Record",
+					}},
+					Tvar: &ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 180,
+									Line:   10,
+								},
+								File:   "http.flux",
+								Source: "A",
+								Start: ast.Position{
+									Column: 179,
+									Line:   10,
+								},
+							},
+						},
+						Name: "A",
+					},
+				}, &ast.TypeConstraint{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 199,
+								Line:   10,
+							},
+							File:   "http.flux",
+							Source: "B: Record",
+							Start: ast.Position{
+								Column: 190,
+								Line:   10,
+							},
+						},
+					},
+					Kinds: []*ast.Identifier{&ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 199,
+									Line:   10,
+								},
+								File:   "http.flux",
+								Source: "Record",
+								Start: ast.Position{
+									Column: 193,
+									Line:   10,
+								},
+							},
+						},
+						Name: "Record",
+					}},
+					Tvar: &ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 191,
+									Line:   10,
+								},
+								File:   "http.flux",
+								Source: "B",
+								Start: ast.Position{
+									Column: 190,
+									Line:   10,
+								},
+							},
+						},
+						Name: "B",
+					},
+				}, &ast.TypeConstraint{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 210,
+								Line:   10,
+							},
+							File:   "http.flux",
+							Source: "C: Record",
+							Start: ast.Position{
+								Column: 201,
+								Line:   10,
+							},
+						},
+					},
+					Kinds: []*ast.Identifier{&ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 210,
+									Line:   10,
+								},
+								File:   "http.flux",
+								Source: "Record",
+								Start: ast.Position{
+									Column: 204,
+									Line:   10,
+								},
+							},
+						},
+						Name: "Record",
+					}},
+					Tvar: &ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 202,
+									Line:   10,
+								},
+								File:   "http.flux",
+								Source: "C",
+								Start: ast.Position{
+									Column: 201,
+									Line:   10,
+								},
+							},
+						},
+						Name: "C",
+					},
+				}},
+				Ty: &ast.FunctionType{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 172,
+								Line:   10,
+							},
+							File:   "http.flux",
+							Source: "(method: string, url: string, ?headers: A, ?body: bytes, ?timeout: duration, ?noTimeout: bool, ?query: B) => {statusCode: int , body: bytes , headers: C}",
+							Start: ast.Position{
+								Column: 19,
+								Line:   10,
+							},
+						},
+					},
+					Parameters: []*ast.ParameterType{&ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 34,
+									Line:   10,
+								},
+								File:   "http.flux",
+								Source: "method: string",
+								Start: ast.Position{
+									Column: 20,
+									Line:   10,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 26,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "method",
+									Start: ast.Position{
+										Column: 20,
+										Line:   10,
+									},
+								},
+							},
+							Name: "method",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 34,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 28,
+										Line:   10,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 34,
+											Line:   10,
+										},
+										File:   "http.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 28,
+											Line:   10,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 47,
+									Line:   10,
+								},
+								File:   "http.flux",
+								Source: "url: string",
+								Start: ast.Position{
+									Column: 36,
+									Line:   10,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 39,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "url",
+									Start: ast.Position{
+										Column: 36,
+										Line:   10,
+									},
+								},
+							},
+							Name: "url",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 47,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 41,
+										Line:   10,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 47,
+											Line:   10,
+										},
+										File:   "http.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 41,
+											Line:   10,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 60,
+									Line:   10,
+								},
+								File:   "http.flux",
+								Source: "?headers: A",
+								Start: ast.Position{
+									Column: 49,
+									Line:   10,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 57,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "headers",
+									Start: ast.Position{
+										Column: 50,
+										Line:   10,
+									},
+								},
+							},
+							Name: "headers",
+						},
+						Ty: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 60,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "A",
+									Start: ast.Position{
+										Column: 59,
+										Line:   10,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 60,
+											Line:   10,
+										},
+										File:   "http.flux",
+										Source: "A",
+										Start: ast.Position{
+											Column: 59,
+											Line:   10,
+										},
+									},
+								},
+								Name: "A",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 74,
+									Line:   10,
+								},
+								File:   "http.flux",
+								Source: "?body: bytes",
+								Start: ast.Position{
+									Column: 62,
+									Line:   10,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 67,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "body",
+									Start: ast.Position{
+										Column: 63,
+										Line:   10,
+									},
+								},
+							},
+							Name: "body",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 74,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "bytes",
+									Start: ast.Position{
+										Column: 69,
+										Line:   10,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 74,
+											Line:   10,
+										},
+										File:   "http.flux",
+										Source: "bytes",
+										Start: ast.Position{
+											Column: 69,
+											Line:   10,
+										},
+									},
+								},
+								Name: "bytes",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 94,
+									Line:   10,
+								},
+								File:   "http.flux",
+								Source: "?timeout: duration",
+								Start: ast.Position{
+									Column: 76,
+									Line:   10,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 84,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "timeout",
+									Start: ast.Position{
+										Column: 77,
+										Line:   10,
+									},
+								},
+							},
+							Name: "timeout",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 94,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "duration",
+									Start: ast.Position{
+										Column: 86,
+										Line:   10,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 94,
+											Line:   10,
+										},
+										File:   "http.flux",
+										Source: "duration",
+										Start: ast.Position{
+											Column: 86,
+											Line:   10,
+										},
+									},
+								},
+								Name: "duration",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 112,
+									Line:   10,
+								},
+								File:   "http.flux",
+								Source: "?noTimeout: bool",
+								Start: ast.Position{
+									Column: 96,
+									Line:   10,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 106,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "noTimeout",
+									Start: ast.Position{
+										Column: 97,
+										Line:   10,
+									},
+								},
+							},
+							Name: "noTimeout",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 112,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "bool",
+									Start: ast.Position{
+										Column: 108,
+										Line:   10,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 112,
+											Line:   10,
+										},
+										File:   "http.flux",
+										Source: "bool",
+										Start: ast.Position{
+											Column: 108,
+											Line:   10,
+										},
+									},
+								},
+								Name: "bool",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 123,
+									Line:   10,
+								},
+								File:   "http.flux",
+								Source: "?query: B",
+								Start: ast.Position{
+									Column: 114,
+									Line:   10,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 120,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "query",
+									Start: ast.Position{
+										Column: 115,
+										Line:   10,
+									},
+								},
+							},
+							Name: "query",
+						},
+						Ty: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 123,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "B",
+									Start: ast.Position{
+										Column: 122,
+										Line:   10,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 123,
+											Line:   10,
+										},
+										File:   "http.flux",
+										Source: "B",
+										Start: ast.Position{
+											Column: 122,
+											Line:   10,
+										},
+									},
+								},
+								Name: "B",
+							},
+						},
+					}},
+					Return: &ast.RecordType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 172,
+									Line:   10,
+								},
+								File:   "http.flux",
+								Source: "{statusCode: int , body: bytes , headers: C}",
+								Start: ast.Position{
+									Column: 128,
+									Line:   10,
+								},
+							},
+						},
+						Properties: []*ast.PropertyType{&ast.PropertyType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 144,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "statusCode: int",
+									Start: ast.Position{
+										Column: 129,
+										Line:   10,
+									},
+								},
+							},
+							Name: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 139,
+											Line:   10,
+										},
+										File:   "http.flux",
+										Source: "statusCode",
+										Start: ast.Position{
+											Column: 129,
+											Line:   10,
+										},
+									},
+								},
+								Name: "statusCode",
+							},
+							Ty: &ast.NamedType{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 144,
+											Line:   10,
+										},
+										File:   "http.flux",
+										Source: "int",
+										Start: ast.Position{
+											Column: 141,
+											Line:   10,
+										},
+									},
+								},
+								ID: &ast.Identifier{
+									BaseNode: ast.BaseNode{
+										Errors: nil,
+										Loc: &ast.SourceLocation{
+											End: ast.Position{
+												Column: 144,
+												Line:   10,
+											},
+											File:   "http.flux",
+											Source: "int",
+											Start: ast.Position{
+												Column: 141,
+												Line:   10,
+											},
+										},
+									},
+									Name: "int",
+								},
+							},
+						}, &ast.PropertyType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 158,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "body: bytes",
+									Start: ast.Position{
+										Column: 147,
+										Line:   10,
+									},
+								},
+							},
+							Name: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 151,
+											Line:   10,
+										},
+										File:   "http.flux",
+										Source: "body",
+										Start: ast.Position{
+											Column: 147,
+											Line:   10,
+										},
+									},
+								},
+								Name: "body",
+							},
+							Ty: &ast.NamedType{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 158,
+											Line:   10,
+										},
+										File:   "http.flux",
+										Source: "bytes",
+										Start: ast.Position{
+											Column: 153,
+											Line:   10,
+										},
+									},
+								},
+								ID: &ast.Identifier{
+									BaseNode: ast.BaseNode{
+										Errors: nil,
+										Loc: &ast.SourceLocation{
+											End: ast.Position{
+												Column: 158,
+												Line:   10,
+											},
+											File:   "http.flux",
+											Source: "bytes",
+											Start: ast.Position{
+												Column: 153,
+												Line:   10,
+											},
+										},
+									},
+									Name: "bytes",
+								},
+							},
+						}, &ast.PropertyType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 171,
+										Line:   10,
+									},
+									File:   "http.flux",
+									Source: "headers: C",
+									Start: ast.Position{
+										Column: 161,
+										Line:   10,
+									},
+								},
+							},
+							Name: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 168,
+											Line:   10,
+										},
+										File:   "http.flux",
+										Source: "headers",
+										Start: ast.Position{
+											Column: 161,
+											Line:   10,
+										},
+									},
+								},
+								Name: "headers",
+							},
+							Ty: &ast.TvarType{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 171,
+											Line:   10,
+										},
+										File:   "http.flux",
+										Source: "C",
+										Start: ast.Position{
+											Column: 170,
+											Line:   10,
+										},
+									},
+								},
+								ID: &ast.Identifier{
+									BaseNode: ast.BaseNode{
+										Errors: nil,
+										Loc: &ast.SourceLocation{
+											End: ast.Position{
+												Column: 171,
+												Line:   10,
+											},
+											File:   "http.flux",
+											Source: "C",
+											Start: ast.Position{
+												Column: 170,
+												Line:   10,
+											},
+										},
+									},
+									Name: "C",
+								},
+							},
+						}},
+						Tvar: nil,
+					},
+				},
+			},
+		}, &ast.BuiltinStatement{
+			BaseNode: ast.BaseNode{
+				Errors: nil,
+				Loc: &ast.SourceLocation{
+					End: ast.Position{
+						Column: 16,
+						Line:   13,
+					},
+					File:   "http.flux",
+					Source: "builtin getJSON",
+					Start: ast.Position{
+						Column: 1,
+						Line:   13,
+					},
+				},
+			},
+			ID: &ast.Identifier{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 16,
+							Line:   13,
+						},
+						File:   "http.flux",
+						Source: "getJSON",
+						Start: ast.Position{
+							Column: 9,
+							Line:   13,
+						},
+					},
+				},
+				Name: "getJSON",
+			},
+			Ty: ast.TypeExpression{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 139,
+							Line:   13,
+						},
+						File:   "http.flux",
+						Source: "(url: string, ?headers: A, ?timeout: duration, ?noTimeout: bool, ?query: B) => [C] where A: Record, B: Record, C: Record",
+						Start: ast.Position{
+							Column: 19,
+							Line:   13,
+						},
+					},
+				},
+				Constraints: []*ast.TypeConstraint{&ast.TypeConstraint{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 117,
+								Line:   13,
+							},
+							File:   "http.flux",
+							Source: "A: Record",
+							Start: ast.Position{
+								Column: 108,
+								Line:   13,
+							},
+						},
+					},
+					Kinds: []*ast.Identifier{&ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 117,
+									Line:   13,
+								},
+								File:   "http.flux",
+								Source: "Record",
+								Start: ast.Position{
+									Column: 111,
+									Line:   13,
+								},
+							},
+						},
+						Name: "Record",
+					}},
+					Tvar: &ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 109,
+									Line:   13,
+								},
+								File:   "http.flux",
+								Source: "A",
+								Start: ast.Position{
+									Column: 108,
+									Line:   13,
+								},
+							},
+						},
+						Name: "A",
+					},
+				}, &ast.TypeConstraint{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 128,
+								Line:   13,
+							},
+							File:   "http.flux",
+							Source: "B: Record",
+							Start: ast.Position{
+								Column: 119,
+								Line:   13,
+							},
+						},
+					},
+					Kinds: []*ast.Identifier{&ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 128,
+									Line:   13,
+								},
+								File:   "http.flux",
+								Source: "Record",
+								Start: ast.Position{
+									Column: 122,
+									Line:   13,
+								},
+							},
+						},
+						Name: "Record",
+					}},
+					Tvar: &ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 120,
+									Line:   13,
+								},
+								File:   "http.flux",
+								Source: "B",
+								Start: ast.Position{
+									Column: 119,
+									Line:   13,
+								},
+							},
+						},
+						Name: "B",
+					},
+				}, &ast.TypeConstraint{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 139,
+								Line:   13,
+							},
+							File:   "http.flux",
+							Source: "C: Record",
+							Start: ast.Position{
+								Column: 130,
+								Line:   13,
+							},
+						},
+					},
+					Kinds: []*ast.Identifier{&ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 139,
+									Line:   13,
+								},
+								File:   "http.flux",
+								Source: "Record",
+								Start: ast.Position{
+									Column: 133,
+									Line:   13,
+								},
+							},
+						},
+						Name: "Record",
+					}},
+					Tvar: &ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 131,
+									Line:   13,
+								},
+								File:   "http.flux",
+								Source: "C",
+								Start: ast.Position{
+									Column: 130,
+									Line:   13,
+								},
+							},
+						},
+						Name: "C",
+					},
+				}},
+				Ty: &ast.FunctionType{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 101,
+								Line:   13,
+							},
+							File:   "http.flux",
+							Source: "(url: string, ?headers: A, ?timeout: duration, ?noTimeout: bool, ?query: B) => [C]",
+							Start: ast.Position{
+								Column: 19,
+								Line:   13,
+							},
+						},
+					},
+					Parameters: []*ast.ParameterType{&ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 31,
+									Line:   13,
+								},
+								File:   "http.flux",
+								Source: "url: string",
+								Start: ast.Position{
+									Column: 20,
+									Line:   13,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 23,
+										Line:   13,
+									},
+									File:   "http.flux",
+									Source: "url",
+									Start: ast.Position{
+										Column: 20,
+										Line:   13,
+									},
+								},
+							},
+							Name: "url",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 31,
+										Line:   13,
+									},
+									File:   "http.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 25,
+										Line:   13,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 31,
+											Line:   13,
+										},
+										File:   "http.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 25,
+											Line:   13,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 44,
+									Line:   13,
+								},
+								File:   "http.flux",
+								Source: "?headers: A",
+								Start: ast.Position{
+									Column: 33,
+									Line:   13,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 41,
+										Line:   13,
+									},
+									File:   "http.flux",
+									Source: "headers",
+									Start: ast.Position{
+										Column: 34,
+										Line:   13,
+									},
+								},
+							},
+							Name: "headers",
+						},
+						Ty: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 44,
+										Line:   13,
+									},
+									File:   "http.flux",
+									Source: "A",
+									Start: ast.Position{
+										Column: 43,
+										Line:   13,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 44,
+											Line:   13,
+										},
+										File:   "http.flux",
+										Source: "A",
+										Start: ast.Position{
+											Column: 43,
+											Line:   13,
+										},
+									},
+								},
+								Name: "A",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 64,
+									Line:   13,
+								},
+								File:   "http.flux",
+								Source: "?timeout: duration",
+								Start: ast.Position{
+									Column: 46,
+									Line:   13,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 54,
+										Line:   13,
+									},
+									File:   "http.flux",
+									Source: "timeout",
+									Start: ast.Position{
+										Column: 47,
+										Line:   13,
+									},
+								},
+							},
+							Name: "timeout",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 64,
+										Line:   13,
+									},
+									File:   "http.flux",
+									Source: "duration",
+									Start: ast.Position{
+										Column: 56,
+										Line:   13,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 64,
+											Line:   13,
+										},
+										File:   "http.flux",
+										Source: "duration",
+										Start: ast.Position{
+											Column: 56,
+											Line:   13,
+										},
+									},
+								},
+								Name: "duration",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 82,
+									Line:   13,
+								},
+								File:   "http.flux",
+								Source: "?noTimeout: bool",
+								Start: ast.Position{
+									Column: 66,
+									Line:   13,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 76,
+										Line:   13,
+									},
+									File:   "http.flux",
+									Source: "noTimeout",
+									Start: ast.Position{
+										Column: 67,
+										Line:   13,
+									},
+								},
+							},
+							Name: "noTimeout",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 82,
+										Line:   13,
+									},
+									File:   "http.flux",
+									Source: "bool",
+									Start: ast.Position{
+										Column: 78,
+										Line:   13,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 82,
+											Line:   13,
+										},
+										File:   "http.flux",
+										Source: "bool",
+										Start: ast.Position{
+											Column: 78,
+											Line:   13,
+										},
+									},
+								},
+								Name: "bool",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 93,
+									Line:   13,
+								},
+								File:   "http.flux",
+								Source: "?query: B",
+								Start: ast.Position{
+									Column: 84,
+									Line:   13,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 90,
+										Line:   13,
+									},
+									File:   "http.flux",
+									Source: "query",
+									Start: ast.Position{
+										Column: 85,
+										Line:   13,
+									},
+								},
+							},
+							Name: "query",
+						},
+						Ty: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 93,
+										Line:   13,
+									},
+									File:   "http.flux",
+									Source: "B",
+									Start: ast.Position{
+										Column: 92,
+										Line:   13,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 93,
+											Line:   13,
+										},
+										File:   "http.flux",
+										Source: "B",
+										Start: ast.Position{
+											Column: 92,
+											Line:   13,
+										},
+									},
+								},
+								Name: "B",
+							},
+						},
+					}},
+					Return: &ast.ArrayType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 101,
+									Line:   13,
+								},
+								File:   "http.flux",
+								Source: "[C]",
+								Start: ast.Position{
+									Column: 98,
+									Line:   13,
+								},
+							},
+						},
+						ElementType: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 100,
+										Line:   13,
+									},
+									File:   "http.flux",
+									Source: "C",
+									Start: ast.Position{
+										Column: 99,
+										Line:   13,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 100,
+											Line:   13,
+										},
+										File:   "http.flux",
+										Source: "C",
+										Start: ast.Position{
+											Column: 99,
+											Line:   13,
+										},
+									},
+								},
+								Name: "C",
+							},
+						},
+					},
+				},
+			},
+		}, &ast.BuiltinStatement{
+			BaseNode: ast.BaseNode{
+				Errors: nil,
+				Loc: &ast.SourceLocation{
+					End: ast.Position{
+						Column: 15,
+						Line:   16,
+					},
+					File:   "http.flux",
+					Source: "builtin getCSV",
+					Start: ast.Position{
+						Column: 1,
+						Line:   16,
+					},
+				},
+			},
+			ID: &ast.Identifier{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 15,
+							Line:   16,
+						},
+						File:   "http.flux",
+						Source: "getCSV",
+						Start: ast.Position{
+							Column: 9,
+							Line:   16,
+						},
+					},
+				},
+				Name: "getCSV",
+			},
+			Ty: ast.TypeExpression{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 138,
+							Line:   16,
+						},
+						File:   "http.flux",
+						Source: "(url: string, ?headers: A, ?timeout: duration, ?noTimeout: bool, ?query: B) => [C] where A: Record, B: Record, C: Record",
+						Start: ast.Position{
+							Column: 18,
+							Line:   16,
+						},
+					},
+				},
+				Constraints: []*ast.TypeConstraint{&ast.TypeConstraint{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 116,
+								Line:   16,
+							},
+							File:   "http.flux",
+							Source: "A: Record",
+							Start: ast.Position{
+								Column: 107,
+								Line:   16,
+							},
+						},
+					},
+					Kinds: []*ast.Identifier{&ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 116,
+									Line:   16,
+								},
+								File:   "http.flux",
+								Source: "Record",
+								Start: ast.Position{
+									Column: 110,
+									Line:   16,
+								},
+							},
+						},
+						Name: "Record",
+					}},
+					Tvar: &ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 108,
+									Line:   16,
+								},
+								File:   "http.flux",
+								Source: "A",
+								Start: ast.Position{
+									Column: 107,
+									Line:   16,
+								},
+							},
+						},
+						Name: "A",
+					},
+				}, &ast.TypeConstraint{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 127,
+								Line:   16,
+							},
+							File:   "http.flux",
+							Source: "B: Record",
+							Start: ast.Position{
+								Column: 118,
+								Line:   16,
+							},
+						},
+					},
+					Kinds: []*ast.Identifier{&ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 127,
+									Line:   16,
+								},
+								File:   "http.flux",
+								Source: "Record",
+								Start: ast.Position{
+									Column: 121,
+									Line:   16,
+								},
+							},
+						},
+						Name: "Record",
+					}},
+					Tvar: &ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 119,
+									Line:   16,
+								},
+								File:   "http.flux",
+								Source: "B",
+								Start: ast.Position{
+									Column: 118,
+									Line:   16,
+								},
+							},
+						},
+						Name: "B",
+					},
+				}, &ast.TypeConstraint{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 138,
+								Line:   16,
+							},
+							File:   "http.flux",
+							Source: "C: Record",
+							Start: ast.Position{
+								Column: 129,
+								Line:   16,
+							},
+						},
+					},
+					Kinds: []*ast.Identifier{&ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 138,
+									Line:   16,
+								},
+								File:   "http.flux",
+								Source: "Record",
+								Start: ast.Position{
+									Column: 132,
+									Line:   16,
+								},
+							},
+						},
+						Name: "Record",
+					}},
+					Tvar: &ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 130,
+									Line:   16,
+								},
+								File:   "http.flux",
+								Source: "C",
+								Start: ast.Position{
+									Column: 129,
+									Line:   16,
+								},
+							},
+						},
+						Name: "C",
+					},
+				}},
+				Ty: &ast.FunctionType{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 100,
+								Line:   16,
+							},
+							File:   "http.flux",
+							Source: "(url: string, ?headers: A, ?timeout: duration, ?noTimeout: bool, ?query: B) => [C]",
+							Start: ast.Position{
+								Column: 18,
+								Line:   16,
+							},
+						},
+					},
+					Parameters: []*ast.ParameterType{&ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 30,
+									Line:   16,
+								},
+								File:   "http.flux",
+								Source: "url: string",
+								Start: ast.Position{
+									Column: 19,
+									Line:   16,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 22,
+										Line:   16,
+									},
+									File:   "http.flux",
+									Source: "url",
+									Start: ast.Position{
+										Column: 19,
+										Line:   16,
+									},
+								},
+							},
+							Name: "url",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 30,
+										Line:   16,
+									},
+									File:   "http.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 24,
+										Line:   16,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 30,
+											Line:   16,
+										},
+										File:   "http.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 24,
+											Line:   16,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 43,
+									Line:   16,
+								},
+								File:   "http.flux",
+								Source: "?headers: A",
+								Start: ast.Position{
+									Column: 32,
+									Line:   16,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 40,
+										Line:   16,
+									},
+									File:   "http.flux",
+									Source: "headers",
+									Start: ast.Position{
+										Column: 33,
+										Line:   16,
+									},
+								},
+							},
+							Name: "headers",
+						},
+						Ty: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 43,
+										Line:   16,
+									},
+									File:   "http.flux",
+									Source: "A",
+									Start: ast.Position{
+										Column: 42,
+										Line:   16,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 43,
+											Line:   16,
+										},
+										File:   "http.flux",
+										Source: "A",
+										Start: ast.Position{
+											Column: 42,
+											Line:   16,
+										},
+									},
+								},
+								Name: "A",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 63,
+									Line:   16,
+								},
+								File:   "http.flux",
+								Source: "?timeout: duration",
+								Start: ast.Position{
+									Column: 45,
+									Line:   16,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 53,
+										Line:   16,
+									},
+									File:   "http.flux",
+									Source: "timeout",
+									Start: ast.Position{
+										Column: 46,
+										Line:   16,
+									},
+								},
+							},
+							Name: "timeout",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 63,
+										Line:   16,
+									},
+									File:   "http.flux",
+									Source: "duration",
+									Start: ast.Position{
+										Column: 55,
+										Line:   16,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 63,
+											Line:   16,
+										},
+										File:   "http.flux",
+										Source: "duration",
+										Start: ast.Position{
+											Column: 55,
+											Line:   16,
+										},
+									},
+								},
+								Name: "duration",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 81,
+									Line:   16,
+								},
+								File:   "http.flux",
+								Source: "?noTimeout: bool",
+								Start: ast.Position{
+									Column: 65,
+									Line:   16,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 75,
+										Line:   16,
+									},
+									File:   "http.flux",
+									Source: "noTimeout",
+									Start: ast.Position{
+										Column: 66,
+										Line:   16,
+									},
+								},
+							},
+							Name: "noTimeout",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 81,
+										Line:   16,
+									},
+									File:   "http.flux",
+									Source: "bool",
+									Start: ast.Position{
+										Column: 77,
+										Line:   16,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 81,
+											Line:   16,
+										},
+										File:   "http.flux",
+										Source: "bool",
+										Start: ast.Position{
+											Column: 77,
+											Line:   16,
+										},
+									},
+								},
+								Name: "bool",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 92,
+									Line:   16,
+								},
+								File:   "http.flux",
+								Source: "?query: B",
+								Start: ast.Position{
+									Column: 83,
+									Line:   16,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 89,
+										Line:   16,
+									},
+									File:   "http.flux",
+									Source: "query",
+									Start: ast.Position{
+										Column: 84,
+										Line:   16,
+									},
+								},
+							},
+							Name: "query",
+						},
+						Ty: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 92,
+										Line:   16,
+									},
+									File:   "http.flux",
+									Source: "B",
+									Start: ast.Position{
+										Column: 91,
+										Line:   16,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 92,
+											Line:   16,
+										},
+										File:   "http.flux",
+										Source: "B",
+										Start: ast.Position{
+											Column: 91,
+											Line:   16,
+										},
+									},
+								},
+								Name: "B",
+							},
+						},
+					}},
+					Return: &ast.ArrayType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 100,
+									Line:   16,
+								},
+								File:   "http.flux",
+								Source: "[C]",
+								Start: ast.Position{
+									Column: 97,
+									Line:   16,
+								},
+							},
+						},
+						ElementType: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 99,
+										Line:   16,
+									},
+									File:   "http.flux",
+									Source: "C",
+									Start: ast.Position{
+										Column: 98,
+										Line:   16,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 99,
+											Line:   16,
+										},
+										File:   "http.flux",
+										Source: "C",
+										Start: ast.Position{
+											Column: 98,
+											Line:   16,
+										},
+									},
+								},
+								Name: "C",
+							},
+						},
+					},
+				},
+			},
 		}},
 		Imports:  nil,
 		Metadata: "parser-type=rust",
@@ -117,3 +117,61 @@ resp = http.get(url:"%s/path/a/b/c", timeout: 10ms)
 		t.Errorf("unexpected cause of failure, got err: %v", err)
 	}
 }
+
+func TestGet_ZeroTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		w.WriteHeader(204)
+	}))
+	defer ts.Close()
+
+	script := fmt.Sprintf(`
+import "experimental/http"
+
+resp = http.get(url:"%s/path/a/b/c", timeout: 0s)
+`, ts.URL)
+
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	_, _, err := runtime.Eval(ctx, script)
+	if err == nil {
+		t.Fatal("expected timeout failure")
+	}
+	if !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Errorf("unexpected cause of failure, got err: %v", err)
+	}
+}
+
+func TestGet_NoTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(204)
+	}))
+	defer ts.Close()
+
+	script := fmt.Sprintf(`
+import "experimental/http"
+
+resp = http.get(url:"%s/path/a/b/c", noTimeout: true)
+`, ts.URL)
+
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	_, scope, err := runtime.Eval(ctx, script)
+	if err != nil {
+		t.Fatal("evaluation of http.get failed: ", err)
+	}
+	resp, ok := scope.Lookup("resp")
+	if !ok {
+		t.Fatal("missing response")
+	}
+	if v, _ := resp.Object().Get("statusCode"); v.Int() != 204 {
+		t.Errorf("unexpected status code want: 204 got: %d", v.Int())
+	}
+
+	script = fmt.Sprintf(`
+import "experimental/http"
+
+resp = http.get(url:"%s/path/a/b/c", timeout: 10ms, noTimeout: true)
+`, ts.URL)
+	if _, _, err := runtime.Eval(ctx, script); err == nil {
+		t.Error("expected an error when both timeout and noTimeout are set")
+	}
+}
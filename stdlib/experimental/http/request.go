@@ -0,0 +1,260 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	fluxurl "github.com/influxdata/flux/dependencies/url"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+// defaultTimeout matches the timeout of http.NewDefaultClient().
+const defaultTimeout = 30 * time.Second
+
+// request is an HTTP request made by the functions of this package.
+type request struct {
+	Method  string
+	URL     string
+	Query   map[string]string
+	Headers map[string]string
+	Body    []byte
+	Timeout time.Duration
+	// NoTimeout disables the timeout so that the request
+	// only ends when the query is canceled.
+	NoTimeout bool
+}
+
+// response is the response to a request.
+type response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// readRequest reads the url, headers, query, timeout and noTimeout arguments of a request.
+// A timeout that is not positive expires immediately, like that of http.get always has.
+func readRequest(args interpreter.Arguments, method string) (*request, error) {
+	r := &request{
+		Method:  method,
+		Timeout: defaultTimeout,
+	}
+	var err error
+	if r.URL, err = args.GetRequiredString("url"); err != nil {
+		return nil, err
+	}
+	if _, err := url.Parse(r.URL); err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "invalid url")
+	}
+	if r.Headers, err = readStrings(args, "headers"); err != nil {
+		return nil, err
+	}
+	if r.Query, err = readStrings(args, "query"); err != nil {
+		return nil, err
+	}
+	if v, ok := args.Get("timeout"); ok {
+		if v.Type().Nature() != semantic.Duration {
+			return nil, errors.Newf(codes.Invalid, "expected argument %q to be of type %v, got type %v", "timeout", semantic.Duration, v.Type().Nature())
+		}
+		r.Timeout = v.Duration().Duration()
+	}
+	if r.NoTimeout, _, err = args.GetBool("noTimeout"); err != nil {
+		return nil, err
+	}
+	if _, ok := args.Get("timeout"); ok && r.NoTimeout {
+		return nil, errors.New(codes.Invalid, "timeout cannot be set with noTimeout")
+	}
+	return r, nil
+}
+
+// readStrings reads a record argument whose values are all strings.
+func readStrings(args interpreter.Arguments, name string) (map[string]string, error) {
+	obj, ok, err := args.GetObject(name)
+	if err != nil || !ok || obj.IsNull() {
+		return nil, err
+	}
+	m := make(map[string]string, obj.Len())
+	var rangeErr error
+	obj.Range(func(k string, v values.Value) {
+		if rangeErr != nil {
+			return
+		}
+		if v.Type().Nature() != semantic.String {
+			rangeErr = errors.Newf(codes.Invalid, "%s value %q must be a string", strings.TrimSuffix(name, "s"), k)
+			return
+		}
+		m[k] = v.Str()
+	})
+	if rangeErr != nil {
+		return nil, rangeErr
+	}
+	return m, nil
+}
+
+// do sends the request with the HTTPClient dependency after it
+// is validated by the URLValidator dependency.
+// The name identifies the calling function in traces and errors.
+func (r *request) do(ctx context.Context, name string) (*response, error) {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "invalid url")
+	}
+	deps := flux.GetDependencies(ctx)
+	validator, err := deps.URLValidator()
+	if err != nil {
+		return nil, err
+	}
+	if err := fluxurl.ForFunction(validator, name).Validate(u); err != nil {
+		return nil, errors.New(codes.Invalid, "no such host")
+	}
+	if len(r.Query) > 0 {
+		q := u.Query()
+		for k, v := range r.Query {
+			q.Set(k, v)
+		}
+		u.RawQuery = q.Encode()
+	}
+
+	var body io.Reader
+	if r.Body != nil {
+		body = bytes.NewReader(r.Body)
+	}
+	req, err := http.NewRequest(r.Method, u.String(), body)
+	if err != nil {
+		return nil, errors.Wrapf(err, codes.Invalid, "invalid %s request", name)
+	}
+	keys := make([]string, 0, len(r.Headers))
+	for k := range r.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		req.Header.Set(k, r.Headers[k])
+	}
+
+	dc, err := deps.HTTPClient()
+	if err != nil {
+		return nil, errors.Wrapf(err, codes.Aborted, "missing client in %s", name)
+	}
+
+	s, cctx := opentracing.StartSpanFromContext(ctx, name)
+	s.SetTag("url", req.URL.String())
+	s.SetTag("method", req.Method)
+	defer s.Finish()
+
+	if !r.NoTimeout {
+		var cncl context.CancelFunc
+		cctx, cncl = context.WithTimeout(cctx, r.Timeout)
+		defer cncl()
+	}
+
+	resp, err := dc.Do(req.WithContext(fluxurl.WithFunction(cctx, name)))
+	if err != nil {
+		// Alias the DNS lookup error so as not to disclose the
+		// DNS server address. This error is private in the net/http
+		// package, so string matching is used.
+		if strings.HasSuffix(err.Error(), "no such host") {
+			return nil, errors.New(codes.Invalid, "no such host")
+		}
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	s.LogFields(
+		log.Int("statusCode", resp.StatusCode),
+		log.Int("responseSize", len(respBody)),
+	)
+	return &response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       respBody,
+	}, nil
+}
+
+// object returns the response as a record with the
+// statusCode, headers and body of the response.
+func (r *response) object() values.Object {
+	return values.NewObjectWithValues(map[string]values.Value{
+		"statusCode": values.NewInt(int64(r.StatusCode)),
+		"headers":    headerToObject(r.Header),
+		"body":       values.NewBytes(r.Body),
+	})
+}
+
+// checkStatus returns an error for responses without a 2xx status code.
+func (r *response) checkStatus(name string) error {
+	if r.StatusCode/100 == 2 {
+		return nil
+	}
+	code := codes.Unknown
+	switch r.StatusCode {
+	case http.StatusBadRequest:
+		code = codes.Invalid
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		code = codes.Unavailable
+	}
+	return errors.Newf(code, "%s failed with status %s", name, http.StatusText(r.StatusCode))
+}
+
+// http request makes a request with any method and returns the full response.
+var requestFunc = values.NewFunction(
+	"request",
+	runtime.MustLookupBuiltinType("experimental/http", "request"),
+	func(ctx context.Context, args values.Object) (values.Value, error) {
+		arguments := interpreter.NewArguments(args)
+		method, err := arguments.GetRequiredString("method")
+		if err != nil {
+			return nil, err
+		}
+		method = strings.ToUpper(method)
+		if method == "" {
+			return nil, errors.New(codes.Invalid, "method must not be empty")
+		}
+		r, err := readRequest(arguments, method)
+		if err != nil {
+			return nil, err
+		}
+		if v, ok := arguments.Get("body"); ok && !v.IsNull() {
+			if v.Type().Nature() != semantic.Bytes {
+				return nil, errors.Newf(codes.Invalid, "expected argument %q to be of type %v, got type %v", "body", semantic.Bytes, v.Type().Nature())
+			}
+			r.Body = v.Bytes()
+		}
+
+		resp, err := r.do(ctx, "http.request")
+		if err != nil {
+			return nil, err
+		}
+		return resp.object(), nil
+	},
+	true, // request has side-effects
+)
+
+func init() {
+	runtime.RegisterPackageValue("experimental/http", "request", requestFunc)
+}
@@ -0,0 +1,251 @@
+package http_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/runtime"
+)
+
+func TestRequest(t *testing.T) {
+	var (
+		req  *http.Request
+		body []byte
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		req = request
+		body, _ = ioutil.ReadAll(request.Body)
+		w.Header().Set("X-Request-Id", "42")
+		w.WriteHeader(201)
+		_, _ = w.Write([]byte(`created`))
+	}))
+	defer ts.Close()
+
+	script := fmt.Sprintf(`
+import "experimental/http"
+
+resp = http.request(method: "put", url:"%s/path?a=1", headers: {"Content-Type": "text/plain"}, query: {b: "2"}, body: bytes(v: "hello"))
+`, ts.URL)
+
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	_, scope, err := runtime.Eval(ctx, script)
+	if err != nil {
+		t.Fatal("evaluation of http.request failed: ", err)
+	}
+	if want, got := "PUT", req.Method; want != got {
+		t.Errorf("unexpected method want: %q got: %q", want, got)
+	}
+	if want, got := "a=1&b=2", req.URL.RawQuery; want != got {
+		t.Errorf("unexpected query want: %q got: %q", want, got)
+	}
+	if want, got := "text/plain", req.Header.Get("Content-Type"); want != got {
+		t.Errorf("unexpected content type want: %q got: %q", want, got)
+	}
+	if want, got := "hello", string(body); want != got {
+		t.Errorf("unexpected body want: %q got: %q", want, got)
+	}
+
+	resp, ok := scope.Lookup("resp")
+	if !ok {
+		t.Fatal("missing response")
+	}
+	if v, _ := resp.Object().Get("statusCode"); v.Int() != 201 {
+		t.Errorf("unexpected status code want: 201 got: %d", v.Int())
+	}
+	if v, _ := resp.Object().Get("body"); string(v.Bytes()) != "created" {
+		t.Errorf("unexpected response body want: %q got: %q", "created", string(v.Bytes()))
+	}
+	headers, _ := resp.Object().Get("headers")
+	if v, ok := headers.Object().Get("X-Request-Id"); !ok || v.Str() != "42" {
+		t.Errorf("unexpected response header want: %q got: %v", "42", v)
+	}
+}
+
+func TestRequest_InvalidHeader(t *testing.T) {
+	script := `
+import "experimental/http"
+
+http.request(method: "get", url:"http://localhost/path", headers: {x: 1})
+`
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	_, _, err := runtime.Eval(ctx, script)
+	if err == nil {
+		t.Fatal("expected failure")
+	}
+	if !strings.Contains(err.Error(), `header value "x" must be a string`) {
+		t.Errorf("unexpected cause of failure, got err: %v", err)
+	}
+}
+
+func runQuery(t *testing.T, query string) ([]*executetest.Table, error) {
+	t.Helper()
+
+	c := &lang.FluxCompiler{Query: query}
+	program, err := c.Compile(context.Background(), runtime.Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	q, err := program.Start(ctx, &memory.Allocator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Done()
+
+	var tables []*executetest.Table
+	for res := range q.Results() {
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			cp, err := executetest.ConvertTable(tbl)
+			if err != nil {
+				return err
+			}
+			tables = append(tables, cp)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	q.Done()
+	return tables, q.Err()
+}
+
+func TestGetJSON(t *testing.T) {
+	var req *http.Request
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		req = request
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"name": "a", "value": 1.5, "ok": true}, {"name": "b", "value": 2}]`))
+	}))
+	defer ts.Close()
+
+	got, err := runQuery(t, fmt.Sprintf(`
+import "experimental/http"
+
+http.getJSON(url: "%s/items", headers: {Accept: "application/json"}, query: {limit: "2"})
+`, ts.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, got := "limit=2", req.URL.RawQuery; want != got {
+		t.Errorf("unexpected query want: %q got: %q", want, got)
+	}
+
+	want := []*executetest.Table{{
+		ColMeta: []flux.ColMeta{
+			{Label: "name", Type: flux.TString},
+			{Label: "ok", Type: flux.TBool},
+			{Label: "value", Type: flux.TFloat},
+		},
+		Data: [][]interface{}{
+			{"a", true, 1.5},
+			{"b", nil, 2.0},
+		},
+	}}
+	executetest.NormalizeTables(want)
+	executetest.NormalizeTables(got)
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected tables -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestGetJSON_MultipleYields(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		_, _ = w.Write([]byte(`{"name": "a", "value": 1.5}`))
+	}))
+	defer ts.Close()
+
+	got, err := runQuery(t, fmt.Sprintf(`
+import "experimental/http"
+
+data = http.getJSON(url: "%s/items")
+data |> yield(name: "a")
+data |> yield(name: "b")
+`, ts.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := &executetest.Table{
+		ColMeta: []flux.ColMeta{
+			{Label: "name", Type: flux.TString},
+			{Label: "value", Type: flux.TFloat},
+		},
+		Data: [][]interface{}{
+			{"a", 1.5},
+		},
+	}
+	want := []*executetest.Table{table, table}
+	executetest.NormalizeTables(want)
+	executetest.NormalizeTables(got)
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected tables -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestGetCSV(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		_, _ = w.Write([]byte(`#datatype,string,long,string,double
+#group,false,false,true,false
+#default,_result,,,
+,result,table,host,_value
+,,0,a,1.5
+,,0,a,2.5
+`))
+	}))
+	defer ts.Close()
+
+	got, err := runQuery(t, fmt.Sprintf(`
+import "experimental/http"
+
+http.getCSV(url: "%s/export")
+`, ts.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*executetest.Table{{
+		KeyCols: []string{"host"},
+		ColMeta: []flux.ColMeta{
+			{Label: "host", Type: flux.TString},
+			{Label: "_value", Type: flux.TFloat},
+		},
+		Data: [][]interface{}{
+			{"a", 1.5},
+			{"a", 2.5},
+		},
+	}}
+	executetest.NormalizeTables(want)
+	executetest.NormalizeTables(got)
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected tables -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestGetCSV_StatusError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, request *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	_, err := runQuery(t, fmt.Sprintf(`
+import "experimental/http"
+
+http.getCSV(url: "%s/missing")
+`, ts.URL))
+	if err == nil {
+		t.Fatal("expected failure")
+	}
+	if !strings.Contains(err.Error(), "http.getCSV failed with status Not Found") {
+		t.Errorf("unexpected cause of failure, got err: %v", err)
+	}
+}
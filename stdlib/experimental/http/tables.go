@@ -0,0 +1,363 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+	fluxjson "github.com/influxdata/flux/stdlib/experimental/json"
+	"github.com/influxdata/flux/values"
+)
+
+const (
+	GetJSONKind = "getJSON"
+	GetCSVKind  = "getCSV"
+)
+
+func init() {
+	getJSONSignature := runtime.MustLookupBuiltinType("experimental/http", "getJSON")
+	runtime.RegisterPackageValue("experimental/http", "getJSON", flux.MustValue(flux.FunctionValue(GetJSONKind, createGetJSONOpSpec, getJSONSignature)))
+	flux.RegisterOpSpec(GetJSONKind, func() flux.OperationSpec { return &GetJSONOpSpec{} })
+	plan.RegisterProcedureSpec(GetJSONKind, newGetJSONProcedure, GetJSONKind)
+	execute.RegisterSource(GetJSONKind, createGetJSONSource)
+
+	getCSVSignature := runtime.MustLookupBuiltinType("experimental/http", "getCSV")
+	runtime.RegisterPackageValue("experimental/http", "getCSV", flux.MustValue(flux.FunctionValue(GetCSVKind, createGetCSVOpSpec, getCSVSignature)))
+	flux.RegisterOpSpec(GetCSVKind, func() flux.OperationSpec { return &GetCSVOpSpec{} })
+	plan.RegisterProcedureSpec(GetCSVKind, newGetCSVProcedure, GetCSVKind)
+	execute.RegisterSource(GetCSVKind, createGetCSVSource)
+}
+
+// GetOpSpec is the GET request whose response is decoded into tables.
+type GetOpSpec struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Query   map[string]string `json:"query,omitempty"`
+	Timeout time.Duration     `json:"timeout"`
+	// NoTimeout disables the timeout of the request.
+	NoTimeout bool `json:"noTimeout,omitempty"`
+}
+
+// ReadArgs loads a flux.Arguments into GetOpSpec.
+func (o *GetOpSpec) ReadArgs(args flux.Arguments) error {
+	r, err := readRequest(args.Arguments, http.MethodGet)
+	if err != nil {
+		return err
+	}
+	o.URL = r.URL
+	o.Headers = r.Headers
+	o.Query = r.Query
+	o.Timeout = r.Timeout
+	o.NoTimeout = r.NoTimeout
+	return nil
+}
+
+func (o *GetOpSpec) request() *request {
+	return &request{
+		Method:    http.MethodGet,
+		URL:       o.URL,
+		Query:     o.Query,
+		Headers:   o.Headers,
+		Timeout:   o.Timeout,
+		NoTimeout: o.NoTimeout,
+	}
+}
+
+func (o GetOpSpec) copy() GetOpSpec {
+	o.Headers = copyStrings(o.Headers)
+	o.Query = copyStrings(o.Query)
+	return o
+}
+
+func copyStrings(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	cp := make(map[string]string, len(m))
+	for k, v := range m {
+		cp[k] = v
+	}
+	return cp
+}
+
+// GetJSONOpSpec decodes a JSON response into a table.
+type GetJSONOpSpec struct {
+	GetOpSpec
+}
+
+func createGetJSONOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	s := new(GetJSONOpSpec)
+	if err := s.ReadArgs(args); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (GetJSONOpSpec) Kind() flux.OperationKind {
+	return GetJSONKind
+}
+
+// GetCSVOpSpec decodes an annotated CSV response into tables.
+type GetCSVOpSpec struct {
+	GetOpSpec
+}
+
+func createGetCSVOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	s := new(GetCSVOpSpec)
+	if err := s.ReadArgs(args); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (GetCSVOpSpec) Kind() flux.OperationKind {
+	return GetCSVKind
+}
+
+type GetJSONProcedureSpec struct {
+	plan.DefaultCost
+	GetOpSpec
+}
+
+func newGetJSONProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*GetJSONOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &GetJSONProcedureSpec{GetOpSpec: spec.GetOpSpec.copy()}, nil
+}
+
+func (s *GetJSONProcedureSpec) Kind() plan.ProcedureKind {
+	return GetJSONKind
+}
+
+func (s *GetJSONProcedureSpec) Copy() plan.ProcedureSpec {
+	return &GetJSONProcedureSpec{GetOpSpec: s.GetOpSpec.copy()}
+}
+
+type GetCSVProcedureSpec struct {
+	plan.DefaultCost
+	GetOpSpec
+}
+
+func newGetCSVProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*GetCSVOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &GetCSVProcedureSpec{GetOpSpec: spec.GetOpSpec.copy()}, nil
+}
+
+func (s *GetCSVProcedureSpec) Kind() plan.ProcedureKind {
+	return GetCSVKind
+}
+
+func (s *GetCSVProcedureSpec) Copy() plan.ProcedureSpec {
+	return &GetCSVProcedureSpec{GetOpSpec: s.GetOpSpec.copy()}
+}
+
+func createGetJSONSource(ps plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := ps.(*GetJSONProcedureSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", ps)
+	}
+	return &getSource{
+		id:   dsid,
+		name: "http.getJSON",
+		req:  spec.request(),
+		run: func(ctx context.Context, s *getSource, body []byte) error {
+			tbl, err := jsonTable(body, a.Allocator())
+			if err != nil {
+				return err
+			}
+			if len(s.ts) == 0 {
+				tbl.Done()
+				return nil
+			} else if len(s.ts) == 1 {
+				return s.ts[0].Process(s.id, tbl)
+			}
+
+			// Each transformation reads the table,
+			// so each of them gets its own copy.
+			bufTable, err := execute.CopyTable(tbl)
+			if err != nil {
+				return err
+			}
+			defer bufTable.Done()
+			for _, t := range s.ts {
+				if err := t.Process(s.id, bufTable.Copy()); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}, nil
+}
+
+func createGetCSVSource(ps plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := ps.(*GetCSVProcedureSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", ps)
+	}
+	return &getSource{
+		id:   dsid,
+		name: "http.getCSV",
+		req:  spec.request(),
+		run: func(ctx context.Context, s *getSource, body []byte) error {
+			for _, t := range s.ts {
+				// Tables decoded from csv contain mutable state,
+				// so each transformation gets its own decoder like csv.from.
+				decoder := csv.NewResultDecoder(csv.ResultDecoderConfig{
+					Allocator: a.Allocator(),
+					Context:   ctx,
+				})
+				result, err := decoder.Decode(bytes.NewReader(body))
+				if err != nil {
+					return err
+				}
+				if err := result.Tables().Do(func(tbl flux.Table) error {
+					return t.Process(s.id, tbl)
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}, nil
+}
+
+// getSource makes a request and decodes the body of the response
+// into the tables given to its transformations.
+type getSource struct {
+	execute.ExecutionNode
+	id   execute.DatasetID
+	name string
+	req  *request
+	run  func(ctx context.Context, s *getSource, body []byte) error
+	ts   []execute.Transformation
+}
+
+func (s *getSource) AddTransformation(t execute.Transformation) {
+	s.ts = append(s.ts, t)
+}
+
+func (s *getSource) Run(ctx context.Context) {
+	resp, err := s.req.do(ctx, s.name)
+	if err == nil {
+		err = resp.checkStatus(s.name)
+	}
+	if err == nil {
+		err = s.run(ctx, s, resp.Body)
+	}
+	for _, t := range s.ts {
+		t.Finish(s.id, err)
+	}
+}
+
+// jsonTable converts a JSON object, or an array of objects, into a table
+// with a row for each object and a column for each key of the objects.
+// The values are parsed like json.parse, so numbers become floats.
+// Keys that are missing from an object are null.
+func jsonTable(body []byte, alloc *memory.Allocator) (flux.Table, error) {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "invalid json response")
+	}
+	var objs []interface{}
+	switch data := data.(type) {
+	case []interface{}:
+		objs = data
+	case map[string]interface{}:
+		objs = []interface{}{data}
+	default:
+		return nil, errors.New(codes.Invalid, "json response must be an object or an array of objects")
+	}
+
+	rows := make([]values.Object, len(objs))
+	types := make(map[string]semantic.Nature)
+	for i, obj := range objs {
+		if _, ok := obj.(map[string]interface{}); !ok {
+			return nil, errors.Newf(codes.Invalid, "json array element %d is not an object", i)
+		}
+		v, err := fluxjson.ToValue(obj)
+		if err != nil {
+			return nil, err
+		}
+		rows[i] = v.Object()
+		var rangeErr error
+		rows[i].Range(func(k string, v values.Value) {
+			if rangeErr != nil {
+				return
+			}
+			if v.IsNull() {
+				// Columns that only hold null values are string columns.
+				if _, ok := types[k]; !ok {
+					types[k] = semantic.Invalid
+				}
+				return
+			}
+			n := v.Type().Nature()
+			switch n {
+			case semantic.String, semantic.Float, semantic.Bool:
+			default:
+				rangeErr = errors.Newf(codes.Invalid, "column %q has unsupported type %v", k, n)
+				return
+			}
+			if prev, ok := types[k]; ok && prev != semantic.Invalid && prev != n {
+				rangeErr = errors.Newf(codes.Invalid, "column %q has conflicting types %v and %v", k, prev, n)
+				return
+			}
+			types[k] = n
+		})
+		if rangeErr != nil {
+			return nil, rangeErr
+		}
+	}
+
+	labels := make([]string, 0, len(types))
+	for k := range types {
+		labels = append(labels, k)
+	}
+	sort.Strings(labels)
+
+	builder := execute.NewColListTableBuilder(execute.NewGroupKey(nil, nil), alloc)
+	for _, label := range labels {
+		typ := flux.TString
+		switch types[label] {
+		case semantic.Float:
+			typ = flux.TFloat
+		case semantic.Bool:
+			typ = flux.TBool
+		}
+		if _, err := builder.AddCol(flux.ColMeta{Label: label, Type: typ}); err != nil {
+			return nil, err
+		}
+	}
+	for _, row := range rows {
+		for j, label := range labels {
+			v, ok := row.Get(label)
+			if !ok || v.IsNull() {
+				if err := builder.AppendNil(j); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if err := builder.AppendValue(j, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return builder.Table()
+}
@@ -41,11 +41,11 @@ func unmarshalToValue(bytes []byte) (values.Value, error) {
 	if err != nil {
 		return nil, err
 	}
-	return toValue(i)
+	return ToValue(i)
 }
 
-// toValue converts a Go value that can be produced by json.Unmarshal into its corresponding Flux value.
-func toValue(i interface{}) (values.Value, error) {
+// ToValue converts a Go value that can be produced by json.Unmarshal into its corresponding Flux value.
+func ToValue(i interface{}) (values.Value, error) {
 	switch t := i.(type) {
 	case string:
 		return values.NewString(t), nil
@@ -57,7 +57,7 @@ func toValue(i interface{}) (values.Value, error) {
 		vals := make([]values.Value, len(t))
 		var elemTyp semantic.MonoType
 		for i, v := range t {
-			val, err := toValue(v)
+			val, err := ToValue(v)
 			if err != nil {
 				return nil, err
 			}
@@ -73,7 +73,7 @@ func toValue(i interface{}) (values.Value, error) {
 	case map[string]interface{}:
 		vals := make(map[string]values.Value, len(t))
 		for k, v := range t {
-			val, err := toValue(v)
+			val, err := ToValue(v)
 			if err != nil {
 				return nil, err
 			}
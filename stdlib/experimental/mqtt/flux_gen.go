@@ -21,11 +21,11 @@ var pkgAST = &ast.Package{
 			Errors: nil,
 			Loc: &ast.SourceLocation{
 				End: ast.Position{
-					Column: 11,
-					Line:   3,
+					Column: 13,
+					Line:   5,
 				},
 				File:   "mqtt.flux",
-				Source: "package mqtt\n\nbuiltin to",
+				Source: "package mqtt\n\nbuiltin to : ( <-tables: [A], broker: string, ?topic: string, ?message: string, ?qos: int, ?clientid: string, ?username: string, ?password: string, ?name: string, ?timeout: duration, ?timeColumn: string, ?tagColumns: [string], ?valueColumns: [string]) => [B] where A: Record, B: Record\n\nbuiltin from",
 				Start: ast.Position{
 					Column: 1,
 					Line:   1,
@@ -1219,6 +1219,880 @@ var pkgAST = &ast.Package{
 					},
 				},
 			},
+		}, &ast.BuiltinStatement{
+			BaseNode: ast.BaseNode{
+				Errors: nil,
+				Loc: &ast.SourceLocation{
+					End: ast.Position{
+						Column: 13,
+						Line:   5,
+					},
+					File:   "mqtt.flux",
+					Source: "builtin from",
+					Start: ast.Position{
+						Column: 1,
+						Line:   5,
+					},
+				},
+			},
+			ID: &ast.Identifier{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 13,
+							Line:   5,
+						},
+						File:   "mqtt.flux",
+						Source: "from",
+						Start: ast.Position{
+							Column: 9,
+							Line:   5,
+						},
+					},
+				},
+				Name: "from",
+			},
+			Ty: ast.TypeExpression{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 216,
+							Line:   5,
+						},
+						File:   "mqtt.flux",
+						Source: "(broker: string, topic: string, ?qos: int, ?duration: duration, ?maxMessages: int, ?decoder: string, ?clientid: string, ?username: string, ?password: string, ?timeout: duration) => [A] where A: Record",
+						Start: ast.Position{
+							Column: 16,
+							Line:   5,
+						},
+					},
+				},
+				Constraints: []*ast.TypeConstraint{&ast.TypeConstraint{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 216,
+								Line:   5,
+							},
+							File:   "mqtt.flux",
+							Source: "A: Record",
+							Start: ast.Position{
+								Column: 207,
+								Line:   5,
+							},
+						},
+					},
+					Kinds: []*ast.Identifier{&ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 216,
+									Line:   5,
+								},
+								File:   "mqtt.flux",
+								Source: "Record",
+								Start: ast.Position{
+									Column: 210,
+									Line:   5,
+								},
+							},
+						},
+						Name: "Record",
+					}},
+					Tvar: &ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 208,
+									Line:   5,
+								},
+								File:   "mqtt.flux",
+								Source: "A",
+								Start: ast.Position{
+									Column: 207,
+									Line:   5,
+								},
+							},
+						},
+						Name: "A",
+					},
+				}},
+				Ty: &ast.FunctionType{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 200,
+								Line:   5,
+							},
+							File:   "mqtt.flux",
+							Source: "(broker: string, topic: string, ?qos: int, ?duration: duration, ?maxMessages: int, ?decoder: string, ?clientid: string, ?username: string, ?password: string, ?timeout: duration) => [A]",
+							Start: ast.Position{
+								Column: 16,
+								Line:   5,
+							},
+						},
+					},
+					Parameters: []*ast.ParameterType{&ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 31,
+									Line:   5,
+								},
+								File:   "mqtt.flux",
+								Source: "broker: string",
+								Start: ast.Position{
+									Column: 17,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 23,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "broker",
+									Start: ast.Position{
+										Column: 17,
+										Line:   5,
+									},
+								},
+							},
+							Name: "broker",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 31,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 25,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 31,
+											Line:   5,
+										},
+										File:   "mqtt.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 25,
+											Line:   5,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 46,
+									Line:   5,
+								},
+								File:   "mqtt.flux",
+								Source: "topic: string",
+								Start: ast.Position{
+									Column: 33,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 38,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "topic",
+									Start: ast.Position{
+										Column: 33,
+										Line:   5,
+									},
+								},
+							},
+							Name: "topic",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 46,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 40,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 46,
+											Line:   5,
+										},
+										File:   "mqtt.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 40,
+											Line:   5,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 57,
+									Line:   5,
+								},
+								File:   "mqtt.flux",
+								Source: "?qos: int",
+								Start: ast.Position{
+									Column: 48,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 52,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "qos",
+									Start: ast.Position{
+										Column: 49,
+										Line:   5,
+									},
+								},
+							},
+							Name: "qos",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 57,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "int",
+									Start: ast.Position{
+										Column: 54,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 57,
+											Line:   5,
+										},
+										File:   "mqtt.flux",
+										Source: "int",
+										Start: ast.Position{
+											Column: 54,
+											Line:   5,
+										},
+									},
+								},
+								Name: "int",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 78,
+									Line:   5,
+								},
+								File:   "mqtt.flux",
+								Source: "?duration: duration",
+								Start: ast.Position{
+									Column: 59,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 68,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "duration",
+									Start: ast.Position{
+										Column: 60,
+										Line:   5,
+									},
+								},
+							},
+							Name: "duration",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 78,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "duration",
+									Start: ast.Position{
+										Column: 70,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 78,
+											Line:   5,
+										},
+										File:   "mqtt.flux",
+										Source: "duration",
+										Start: ast.Position{
+											Column: 70,
+											Line:   5,
+										},
+									},
+								},
+								Name: "duration",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 97,
+									Line:   5,
+								},
+								File:   "mqtt.flux",
+								Source: "?maxMessages: int",
+								Start: ast.Position{
+									Column: 80,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 92,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "maxMessages",
+									Start: ast.Position{
+										Column: 81,
+										Line:   5,
+									},
+								},
+							},
+							Name: "maxMessages",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 97,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "int",
+									Start: ast.Position{
+										Column: 94,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 97,
+											Line:   5,
+										},
+										File:   "mqtt.flux",
+										Source: "int",
+										Start: ast.Position{
+											Column: 94,
+											Line:   5,
+										},
+									},
+								},
+								Name: "int",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 115,
+									Line:   5,
+								},
+								File:   "mqtt.flux",
+								Source: "?decoder: string",
+								Start: ast.Position{
+									Column: 99,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 107,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "decoder",
+									Start: ast.Position{
+										Column: 100,
+										Line:   5,
+									},
+								},
+							},
+							Name: "decoder",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 115,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 109,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 115,
+											Line:   5,
+										},
+										File:   "mqtt.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 109,
+											Line:   5,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 134,
+									Line:   5,
+								},
+								File:   "mqtt.flux",
+								Source: "?clientid: string",
+								Start: ast.Position{
+									Column: 117,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 126,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "clientid",
+									Start: ast.Position{
+										Column: 118,
+										Line:   5,
+									},
+								},
+							},
+							Name: "clientid",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 134,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 128,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 134,
+											Line:   5,
+										},
+										File:   "mqtt.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 128,
+											Line:   5,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 153,
+									Line:   5,
+								},
+								File:   "mqtt.flux",
+								Source: "?username: string",
+								Start: ast.Position{
+									Column: 136,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 145,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "username",
+									Start: ast.Position{
+										Column: 137,
+										Line:   5,
+									},
+								},
+							},
+							Name: "username",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 153,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 147,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 153,
+											Line:   5,
+										},
+										File:   "mqtt.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 147,
+											Line:   5,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 172,
+									Line:   5,
+								},
+								File:   "mqtt.flux",
+								Source: "?password: string",
+								Start: ast.Position{
+									Column: 155,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 164,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "password",
+									Start: ast.Position{
+										Column: 156,
+										Line:   5,
+									},
+								},
+							},
+							Name: "password",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 172,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 166,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 172,
+											Line:   5,
+										},
+										File:   "mqtt.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 166,
+											Line:   5,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 192,
+									Line:   5,
+								},
+								File:   "mqtt.flux",
+								Source: "?timeout: duration",
+								Start: ast.Position{
+									Column: 174,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 182,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "timeout",
+									Start: ast.Position{
+										Column: 175,
+										Line:   5,
+									},
+								},
+							},
+							Name: "timeout",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 192,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "duration",
+									Start: ast.Position{
+										Column: 184,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 192,
+											Line:   5,
+										},
+										File:   "mqtt.flux",
+										Source: "duration",
+										Start: ast.Position{
+											Column: 184,
+											Line:   5,
+										},
+									},
+								},
+								Name: "duration",
+							},
+						},
+					}},
+					Return: &ast.ArrayType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 200,
+									Line:   5,
+								},
+								File:   "mqtt.flux",
+								Source: "[A]",
+								Start: ast.Position{
+									Column: 197,
+									Line:   5,
+								},
+							},
+						},
+						ElementType: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 199,
+										Line:   5,
+									},
+									File:   "mqtt.flux",
+									Source: "A",
+									Start: ast.Position{
+										Column: 198,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 199,
+											Line:   5,
+										},
+										File:   "mqtt.flux",
+										Source: "A",
+										Start: ast.Position{
+											Column: 198,
+											Line:   5,
+										},
+									},
+								},
+								Name: "A",
+							},
+						},
+					},
+				},
+			},
 		}},
 		Imports:  nil,
 		Metadata: "parser-type=rust",
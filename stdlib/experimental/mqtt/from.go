@@ -0,0 +1,440 @@
+package mqtt
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	fluxurl "github.com/influxdata/flux/dependencies/url"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/line"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/stdlib/socket"
+	"github.com/influxdata/flux/values"
+)
+
+const (
+	FromMQTTKind = "fromMQTT"
+
+	// defaultFromMQTTDecoder is used when mqtt.from is called without a decoder.
+	defaultFromMQTTDecoder = "lineprotocol"
+)
+
+func init() {
+	fromMQTTSignature := runtime.MustLookupBuiltinType("experimental/mqtt", "from")
+
+	runtime.RegisterPackageValue("experimental/mqtt", "from", flux.MustValue(flux.FunctionValue(FromMQTTKind, createFromMQTTOpSpec, fromMQTTSignature)))
+	flux.RegisterOpSpec(FromMQTTKind, func() flux.OperationSpec { return &FromMQTTOpSpec{} })
+	plan.RegisterProcedureSpec(FromMQTTKind, newFromMQTTProcedure, FromMQTTKind)
+	execute.RegisterSource(FromMQTTKind, createFromMQTTSource)
+}
+
+// FromMQTTOpSpec subscribes to a topic and decodes the messages
+// received until the duration elapses or maxMessages are received.
+type FromMQTTOpSpec struct {
+	Broker      string        `json:"broker"`
+	Topic       string        `json:"topic"`
+	QoS         int           `json:"qos"`
+	Duration    time.Duration `json:"duration"`
+	MaxMessages int64         `json:"maxMessages"`
+	Decoder     string        `json:"decoder"`
+	ClientID    string        `json:"clientid"`
+	Username    string        `json:"username"`
+	Password    string        `json:"password"`
+	Timeout     time.Duration `json:"timeout"`
+}
+
+// ReadArgs loads a flux.Arguments into FromMQTTOpSpec.
+// At least one of duration or maxMessages must be set so that the subscription ends.
+func (o *FromMQTTOpSpec) ReadArgs(args flux.Arguments) error {
+	var err error
+	if o.Broker, err = args.GetRequiredString("broker"); err != nil {
+		return err
+	}
+	u, err := url.ParseRequestURI(o.Broker)
+	if err != nil {
+		return errors.Wrap(err, codes.Invalid, "invalid broker url")
+	}
+	if !(u.Scheme == "tcp" || u.Scheme == "ws" || u.Scheme == "tls") {
+		return errors.Newf(codes.Invalid, "scheme must be tcp or ws or tls but was %s", u.Scheme)
+	}
+
+	if o.Topic, err = args.GetRequiredString("topic"); err != nil {
+		return err
+	}
+	if o.Topic == "" {
+		return errors.New(codes.Invalid, "invalid topic name")
+	}
+
+	if q, ok, err := args.GetInt("qos"); err != nil {
+		return err
+	} else if ok {
+		if q < 0 || q > 2 {
+			return errors.Newf(codes.Invalid, "qos must be 0, 1 or 2, got %d", q)
+		}
+		o.QoS = int(q)
+	}
+
+	if d, ok, err := args.GetDuration("duration"); err != nil {
+		return err
+	} else if ok {
+		o.Duration = values.Duration(d).Duration()
+		if o.Duration <= 0 {
+			return errors.Newf(codes.Invalid, "duration must be positive, got %v", o.Duration)
+		}
+	}
+
+	if n, ok, err := args.GetInt("maxMessages"); err != nil {
+		return err
+	} else if ok {
+		if n <= 0 {
+			return errors.Newf(codes.Invalid, "maxMessages must be positive, got %d", n)
+		}
+		o.MaxMessages = n
+	}
+	if o.Duration == 0 && o.MaxMessages == 0 {
+		return errors.New(codes.Invalid, "one of duration or maxMessages is required")
+	}
+
+	decoder, ok, err := args.GetString("decoder")
+	if err != nil {
+		return err
+	} else if !ok {
+		decoder = defaultFromMQTTDecoder
+	}
+	if _, ok := socket.LookupDecoder(decoder); !ok {
+		return errors.Newf(codes.Invalid, "invalid decoder %s, must be one of %v", decoder, socket.Decoders())
+	}
+	o.Decoder = decoder
+
+	o.ClientID, _, err = args.GetString("clientid")
+	if err != nil {
+		return err
+	}
+
+	o.Username, ok, err = args.GetString("username")
+	if err != nil {
+		return err
+	}
+	if ok {
+		o.Password, ok, err = args.GetString("password")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.Newf(codes.Invalid, "password required with username %s", o.Username)
+		}
+	}
+
+	timeout, ok, err := args.GetDuration("timeout")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		o.Timeout = DefaultToMQTTTimeout
+	} else {
+		o.Timeout = values.Duration(timeout).Duration()
+	}
+	return nil
+}
+
+func createFromMQTTOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	s := new(FromMQTTOpSpec)
+	if err := s.ReadArgs(args); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (FromMQTTOpSpec) Kind() flux.OperationKind {
+	return FromMQTTKind
+}
+
+type FromMQTTProcedureSpec struct {
+	plan.DefaultCost
+	Spec *FromMQTTOpSpec
+}
+
+func (o *FromMQTTProcedureSpec) Kind() plan.ProcedureKind {
+	return FromMQTTKind
+}
+
+func (o *FromMQTTProcedureSpec) Copy() plan.ProcedureSpec {
+	s := *o.Spec
+	return &FromMQTTProcedureSpec{Spec: &s}
+}
+
+func newFromMQTTProcedure(qs flux.OperationSpec, a plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*FromMQTTOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &FromMQTTProcedureSpec{Spec: spec}, nil
+}
+
+// clientID returns the id a client connects with. Without an id, every
+// connection gets its own since a broker disconnects the client that
+// holds an id when another client connects with it.
+func clientID(id string) string {
+	if id != "" {
+		return id
+	}
+	var b [6]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("flux-mqtt-%x", time.Now().UnixNano())
+	}
+	return "flux-mqtt-" + hex.EncodeToString(b[:])
+}
+
+// DefaultMQTTSubscriberFactory makes the MQTTSubscriber used by mqtt.from and is injectable for testing.
+var DefaultMQTTSubscriberFactory = func(opts *MQTT.ClientOptions) MQTTSubscriber {
+	return &clientSubscriber{client: MQTT.NewClient(opts)}
+}
+
+// MQTTSubscriber is an interface for what we need from DefaultMQTTSubscriberFactory.
+type MQTTSubscriber interface {
+	// Subscribe connects to the broker and calls handler with the payload
+	// of every message published to the topic until Close is called.
+	Subscribe(topic string, qos byte, timeout time.Duration, handler func(payload []byte)) error
+	// Close ends the subscription and disconnects from the broker.
+	Close()
+}
+
+// clientSubscriber subscribes with a paho client.
+type clientSubscriber struct {
+	client MQTT.Client
+	topic  string
+}
+
+func (s *clientSubscriber) Subscribe(topic string, qos byte, timeout time.Duration, handler func(payload []byte)) error {
+	if err := waitToken(s.client.Connect(), timeout); err != nil {
+		return errors.Wrap(err, codes.Unavailable, "failed to connect to mqtt broker")
+	}
+	s.topic = topic
+	token := s.client.Subscribe(topic, qos, func(_ MQTT.Client, msg MQTT.Message) {
+		handler(msg.Payload())
+	})
+	if err := waitToken(token, timeout); err != nil {
+		s.client.Disconnect(250)
+		return errors.Wrapf(err, codes.Unavailable, "failed to subscribe to mqtt topic %q", topic)
+	}
+	return nil
+}
+
+func (s *clientSubscriber) Close() {
+	if s.topic != "" {
+		s.client.Unsubscribe(s.topic).WaitTimeout(time.Second)
+	}
+	s.client.Disconnect(250)
+}
+
+// waitToken waits for the token to complete.
+// A timeout of zero waits without a limit.
+func waitToken(token MQTT.Token, timeout time.Duration) error {
+	if timeout <= 0 {
+		token.Wait()
+	} else if !token.WaitTimeout(timeout) {
+		return errors.Newf(codes.Unavailable, "timed out after %v", timeout)
+	}
+	return token.Error()
+}
+
+func createFromMQTTSource(ps plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := ps.(*FromMQTTProcedureSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", ps)
+	}
+
+	u, err := url.ParseRequestURI(spec.Spec.Broker)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "invalid broker url")
+	}
+	validator, err := flux.GetDependencies(a.Context()).URLValidator()
+	if err != nil {
+		return nil, err
+	}
+	if err := fluxurl.ForFunction(validator, "mqtt.from").Validate(u); err != nil {
+		return nil, errors.Newf(codes.Invalid, "mqtt broker url did not pass validation: %v", err)
+	}
+	return NewFromMQTTSource(spec, dsid, a.Allocator())
+}
+
+// NewFromMQTTSource creates a source that subscribes to the topic
+// with a subscriber made by DefaultMQTTSubscriberFactory.
+func NewFromMQTTSource(spec *FromMQTTProcedureSpec, dsid execute.DatasetID, alloc *memory.Allocator) (execute.Source, error) {
+	newDecoder, ok := socket.LookupDecoder(spec.Spec.Decoder)
+	if !ok {
+		return nil, errors.Newf(codes.Invalid, "unknown decoder type: %v", spec.Spec.Decoder)
+	}
+
+	opts := MQTT.NewClientOptions().AddBroker(spec.Spec.Broker)
+	opts.SetClientID(clientID(spec.Spec.ClientID))
+	if spec.Spec.Timeout > 0 {
+		opts.SetConnectTimeout(spec.Spec.Timeout)
+	}
+	if spec.Spec.Username != "" {
+		opts.SetUsername(spec.Spec.Username)
+	}
+	if spec.Spec.Password != "" {
+		opts.SetPassword(spec.Spec.Password)
+	}
+	if alloc == nil {
+		alloc = &memory.Allocator{}
+	}
+	return &fromMQTTSource{
+		id:         dsid,
+		spec:       spec.Spec,
+		subscriber: DefaultMQTTSubscriberFactory(opts),
+		decoder:    newDecoder(socket.DecoderConfig{TimeProvider: &line.NowTimeProvider{}, Allocator: alloc}),
+		alloc:      alloc,
+	}, nil
+}
+
+type fromMQTTSource struct {
+	execute.ExecutionNode
+	id         execute.DatasetID
+	spec       *FromMQTTOpSpec
+	subscriber MQTTSubscriber
+	decoder    flux.ResultDecoder
+	alloc      *memory.Allocator
+	ts         []execute.Transformation
+}
+
+func (s *fromMQTTSource) AddTransformation(t execute.Transformation) {
+	s.ts = append(s.ts, t)
+}
+
+func (s *fromMQTTSource) Run(ctx context.Context) {
+	err := s.run(ctx)
+	for _, t := range s.ts {
+		t.Finish(s.id, err)
+	}
+}
+
+func (s *fromMQTTSource) run(ctx context.Context) error {
+	payloads, err := s.collect(ctx)
+	// The payloads are accounted for by the allocator until they are decoded.
+	defer func() { _ = s.alloc.Account(-cap(payloads)) }()
+	if err != nil {
+		return err
+	}
+
+	result, err := s.decoder.Decode(bytes.NewReader(payloads))
+	if err != nil {
+		return errors.Wrap(err, codes.Inherit, "decode error")
+	}
+	return result.Tables().Do(s.processTable)
+}
+
+// processTable passes the table to each transformation.
+// When there is more than one transformation,
+// each of them is given its own copy of the table.
+func (s *fromMQTTSource) processTable(tbl flux.Table) error {
+	if len(s.ts) == 0 {
+		tbl.Done()
+		return nil
+	} else if len(s.ts) == 1 {
+		return s.ts[0].Process(s.id, tbl)
+	}
+
+	bufTable, err := execute.CopyTable(tbl)
+	if err != nil {
+		return err
+	}
+	defer bufTable.Done()
+
+	for _, t := range s.ts {
+		if err := t.Process(s.id, bufTable.Copy()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collect subscribes to the topic and returns the payloads of the messages,
+// one message per line, once the duration elapses or maxMessages are received.
+// The memory of the payloads is accounted for by the allocator of the source,
+// so collecting stops with an error when the memory limit of the query is reached.
+// The caller must account for the returned payloads once it no longer uses them.
+func (s *fromMQTTSource) collect(ctx context.Context) ([]byte, error) {
+	var (
+		mu       sync.Mutex
+		buf      []byte
+		received int64
+		closed   bool
+		err      error
+		full     = make(chan struct{})
+	)
+	handler := func(payload []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed || (s.spec.MaxMessages > 0 && received >= s.spec.MaxMessages) {
+			return
+		}
+		n := len(payload)
+		if n == 0 || payload[n-1] != '\n' {
+			n++
+		}
+		if len(buf)+n > cap(buf) {
+			// Grow the buffer through the allocator so that
+			// the query memory limit applies to it.
+			size := 2 * cap(buf)
+			if size < len(buf)+n {
+				size = len(buf) + n
+			}
+			if err = s.alloc.Account(size - cap(buf)); err != nil {
+				closed = true
+				close(full)
+				return
+			}
+			buf = append(make([]byte, 0, size), buf...)
+		}
+		buf = append(buf, payload...)
+		if n > len(payload) {
+			buf = append(buf, '\n')
+		}
+		if received++; received == s.spec.MaxMessages {
+			close(full)
+		}
+	}
+
+	if err := s.subscriber.Subscribe(s.spec.Topic, byte(s.spec.QoS), s.spec.Timeout, handler); err != nil {
+		return nil, err
+	}
+
+	var timeout <-chan time.Time
+	if s.spec.Duration > 0 {
+		timer := time.NewTimer(s.spec.Duration)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var canceled error
+	select {
+	case <-full:
+	case <-timeout:
+	case <-ctx.Done():
+		canceled = errors.Wrap(ctx.Err(), codes.Canceled, "mqtt source canceled")
+	}
+	s.subscriber.Close()
+
+	// Messages that are still delivered after the subscriber
+	// is closed are ignored so the buffer is no longer modified.
+	mu.Lock()
+	defer mu.Unlock()
+	closed = true
+	if canceled != nil {
+		return buf, canceled
+	}
+	return buf, err
+}
@@ -0,0 +1,325 @@
+package mqtt_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/querytest"
+	"github.com/influxdata/flux/stdlib/experimental/mqtt"
+)
+
+func TestFromMQTT_NewQuery(t *testing.T) {
+	tests := []querytest.NewQueryTestCase{
+		{
+			Name: "from with defaults",
+			Raw:  `import "experimental/mqtt" mqtt.from(broker: "tcp://iot.example.com:1883", topic: "telemetry", duration: 10s)`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromMQTT0",
+						Spec: &mqtt.FromMQTTOpSpec{
+							Broker:   "tcp://iot.example.com:1883",
+							Topic:    "telemetry",
+							Duration: 10 * time.Second,
+							Decoder:  "lineprotocol",
+							Timeout:  mqtt.DefaultToMQTTTimeout,
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "from with options",
+			Raw:  `import "experimental/mqtt" mqtt.from(broker: "tcp://iot.example.com:1883", topic: "telemetry/#", qos: 1, maxMessages: 100, decoder: "json", clientid: "reader", username: "user", password: "pass", timeout: 5s)`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromMQTT0",
+						Spec: &mqtt.FromMQTTOpSpec{
+							Broker:      "tcp://iot.example.com:1883",
+							Topic:       "telemetry/#",
+							QoS:         1,
+							MaxMessages: 100,
+							Decoder:     "json",
+							ClientID:    "reader",
+							Username:    "user",
+							Password:    "pass",
+							Timeout:     5 * time.Second,
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:    "without bound",
+			Raw:     `import "experimental/mqtt" mqtt.from(broker: "tcp://iot.example.com:1883", topic: "telemetry")`,
+			WantErr: true,
+		},
+		{
+			Name:    "invalid qos",
+			Raw:     `import "experimental/mqtt" mqtt.from(broker: "tcp://iot.example.com:1883", topic: "telemetry", qos: 3, duration: 10s)`,
+			WantErr: true,
+		},
+		{
+			Name:    "invalid scheme",
+			Raw:     `import "experimental/mqtt" mqtt.from(broker: "http://iot.example.com:1883", topic: "telemetry", duration: 10s)`,
+			WantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			querytest.NewQueryTestHelper(t, tc)
+		})
+	}
+}
+
+// subscriberMock publishes its payloads once subscribed.
+type subscriberMock struct {
+	payloads []string
+	err      error
+
+	mu     sync.Mutex
+	topic  string
+	qos    byte
+	closed bool
+}
+
+func (s *subscriberMock) Subscribe(topic string, qos byte, _ time.Duration, handler func(payload []byte)) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.mu.Lock()
+	s.topic, s.qos = topic, qos
+	s.mu.Unlock()
+	go func() {
+		for _, p := range s.payloads {
+			handler([]byte(p))
+		}
+	}()
+	return nil
+}
+
+func (s *subscriberMock) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+}
+
+func TestFromMQTTSource_Run(t *testing.T) {
+	testCases := []struct {
+		name     string
+		spec     *mqtt.FromMQTTOpSpec
+		payloads []string
+		err      error
+		want     []*executetest.Table
+		wantErr  error
+	}{
+		{
+			name: "max messages",
+			spec: &mqtt.FromMQTTOpSpec{
+				Topic:       "telemetry",
+				QoS:         1,
+				MaxMessages: 2,
+				Decoder:     "lineprotocol",
+			},
+			payloads: []string{
+				"temp,device=a value=21.5 10",
+				"temp,device=a value=22 20\n",
+				"temp,device=a value=23 30",
+			},
+			want: []*executetest.Table{{
+				KeyCols: []string{"_measurement", "device", "_field"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "device", Type: flux.TString},
+					{Label: "_field", Type: flux.TString},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(10), "temp", "a", "value", 21.5},
+					{execute.Time(20), "temp", "a", "value", 22.0},
+				},
+			}},
+		},
+		{
+			name: "duration",
+			spec: &mqtt.FromMQTTOpSpec{
+				Topic:    "telemetry",
+				Duration: 100 * time.Millisecond,
+				Decoder:  "json",
+			},
+			payloads: []string{
+				`{"_time": 1, "temp": 21.5}`,
+				`{"_time": 2, "temp": 22}`,
+			},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "temp", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), 21.5},
+					{execute.Time(2), 22.0},
+				},
+			}},
+		},
+		{
+			name: "subscribe error",
+			spec: &mqtt.FromMQTTOpSpec{
+				Topic:       "telemetry",
+				MaxMessages: 1,
+				Decoder:     "lineprotocol",
+			},
+			err:     errors.New("connection refused"),
+			wantErr: errors.New("connection refused"),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			subscriber := &subscriberMock{payloads: tc.payloads, err: tc.err}
+			mqtt.DefaultMQTTSubscriberFactory = func(*MQTT.ClientOptions) mqtt.MQTTSubscriber {
+				return subscriber
+			}
+
+			id := executetest.RandomDatasetID()
+			src, err := mqtt.NewFromMQTTSource(&mqtt.FromMQTTProcedureSpec{Spec: tc.spec}, id, executetest.UnlimitedAllocator)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Each of the transformations must receive every table.
+			datasets := make([]*executetest.Dataset, 2)
+			caches := make([]execute.DataCache, len(datasets))
+			for i := range datasets {
+				datasets[i] = executetest.NewDataset(id)
+				c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+				c.SetTriggerSpec(plan.DefaultTriggerSpec)
+				caches[i] = c
+				src.AddTransformation(executetest.NewYieldTransformation(datasets[i], c))
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			src.Run(ctx)
+
+			if tc.wantErr != nil {
+				for _, d := range datasets {
+					if d.FinishedErr == nil {
+						t.Fatalf("expected error %q", tc.wantErr)
+					}
+					if want, got := tc.wantErr.Error(), d.FinishedErr.Error(); want != got {
+						t.Fatalf("unexpected error -want/+got:\n- %q\n+ %q", want, got)
+					}
+				}
+				return
+			}
+			for _, d := range datasets {
+				if d.FinishedErr != nil {
+					t.Fatal(d.FinishedErr)
+				}
+			}
+
+			subscriber.mu.Lock()
+			if subscriber.topic != tc.spec.Topic || subscriber.qos != byte(tc.spec.QoS) {
+				t.Errorf("unexpected subscription to %q with qos %d", subscriber.topic, subscriber.qos)
+			}
+			if !subscriber.closed {
+				t.Error("expected the subscription to be closed")
+			}
+			subscriber.mu.Unlock()
+
+			executetest.NormalizeTables(tc.want)
+			for i, c := range caches {
+				got, err := executetest.TablesFromCache(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+				executetest.NormalizeTables(got)
+				if !cmp.Equal(tc.want, got) {
+					t.Errorf("unexpected tables of transformation %d -want/+got\n%s", i, cmp.Diff(tc.want, got))
+				}
+			}
+		})
+	}
+}
+
+func TestFromMQTTSource_Run_MemoryLimit(t *testing.T) {
+	mqtt.DefaultMQTTSubscriberFactory = func(*MQTT.ClientOptions) mqtt.MQTTSubscriber {
+		return &subscriberMock{payloads: []string{"cpu,host=a usage=0.5 10", "cpu,host=a usage=0.75 20"}}
+	}
+	spec := &mqtt.FromMQTTOpSpec{
+		Topic:       "telemetry",
+		MaxMessages: 2,
+		Decoder:     "lineprotocol",
+	}
+	limit := int64(16)
+	alloc := &memory.Allocator{Limit: &limit}
+	id := executetest.RandomDatasetID()
+	src, err := mqtt.NewFromMQTTSource(&mqtt.FromMQTTProcedureSpec{Spec: spec}, id, alloc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := executetest.NewDataset(id)
+	c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	c.SetTriggerSpec(plan.DefaultTriggerSpec)
+	src.AddTransformation(executetest.NewYieldTransformation(d, c))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src.Run(ctx)
+
+	if d.FinishedErr == nil {
+		t.Fatal("expected the payloads to exceed the memory limit")
+	}
+	if want, got := codes.ResourceExhausted, flux.ErrorCode(d.FinishedErr); want != got {
+		t.Errorf("unexpected error code -want/+got:\n\t- %v\n\t+ %v", want, got)
+	}
+	if got := alloc.Allocated(); got != 0 {
+		t.Errorf("expected the memory of the payloads to be released, got %d bytes", got)
+	}
+}
+
+func TestFromMQTTSource_ClientID(t *testing.T) {
+	var ids []string
+	mqtt.DefaultMQTTSubscriberFactory = func(opts *MQTT.ClientOptions) mqtt.MQTTSubscriber {
+		ids = append(ids, opts.ClientID)
+		return &subscriberMock{}
+	}
+	for _, id := range []string{"", "", "reader"} {
+		spec := &mqtt.FromMQTTOpSpec{
+			Topic:    "telemetry",
+			Decoder:  "lineprotocol",
+			ClientID: id,
+		}
+		if _, err := mqtt.NewFromMQTTSource(&mqtt.FromMQTTProcedureSpec{Spec: spec}, executetest.RandomDatasetID(), executetest.UnlimitedAllocator); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, id := range ids[:2] {
+		if !strings.HasPrefix(id, "flux-mqtt-") {
+			t.Errorf("expected a generated client id, got %q", id)
+		}
+	}
+	if ids[0] == ids[1] {
+		t.Errorf("expected a unique client id for every connection, got %q twice", ids[0])
+	}
+	if want, got := "reader", ids[2]; want != got {
+		t.Errorf("unexpected client id -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+}
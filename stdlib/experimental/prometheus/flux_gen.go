@@ -21,11 +21,11 @@ var pkgAST = &ast.Package{
 			Errors: nil,
 			Loc: &ast.SourceLocation{
 				End: ast.Position{
-					Column: 58,
-					Line:   17,
+					Column: 74,
+					Line:   28,
 				},
 				File:   "prometheus.flux",
-				Source: "package prometheus\nimport \"universe\" \n\n// scrape enables scraping of a prometheus metrics endpoint and converts \n// that input into flux tables. Each metric is put into an individual flux \n// table, including each histogram and summary value.  \nbuiltin scrape : (url: string) => [A] where A: Record\n\n// histogramQuantile enables the user to calculate quantiles on a set of given values\n// This function assumes that the given histogram data is being scraped or read from a \n// Prometheus source. \nhistogramQuantile = (tables=<-, quantile) => \n    tables\n        |> filter(fn: (r) => r._measurement == \"prometheus\")\n        |> group(mode: \"except\", columns: [\"le\", \"_value\", \"_time\"]) \n        |> map(fn:(r) => ({r with le: float(v:r.le)})) \n        |> universe.histogramQuantile(quantile: quantile)",
+				Source: "package prometheus\nimport \"universe\" \n\n// scrape enables scraping of a prometheus metrics endpoint and converts \n// that input into flux tables. Each metric is put into an individual flux \n// table, including each histogram and summary value.  \nbuiltin scrape : (url: string) => [A] where A: Record\n\n// histogramQuantile enables the user to calculate quantiles on a set of given values\n// This function assumes that the given histogram data is being scraped or read from a \n// Prometheus source. \nhistogramQuantile = (tables=<-, quantile) => \n    tables\n        |> filter(fn: (r) => r._measurement == \"prometheus\")\n        |> group(mode: \"except\", columns: [\"le\", \"_value\", \"_time\"]) \n        |> map(fn:(r) => ({r with le: float(v:r.le)})) \n        |> universe.histogramQuantile(quantile: quantile)\n\n// remoteRead reads the series that match all of the label matchers between\n// start and stop from a Prometheus remote read endpoint. The matchers are\n// written like the matchers of a PromQL selector, such as `job=\"api\"`.\n// Each series is put into its own table with the same shape as the tables of scrape.\nbuiltin remoteRead : (url: string, matchers: [string], start: A, ?stop: B) => [C] where C: Record\n\n// remoteWrite writes the tables to a Prometheus remote write endpoint.\n// The _field column is the metric name and the other string columns\n// of the group key, except for _measurement, _start and _stop, are its labels.\nbuiltin remoteWrite : (<-tables: [A], url: string) => [A] where A: Record",
 				Start: ast.Position{
 					Column: 1,
 					Line:   1,
@@ -1447,6 +1447,808 @@ var pkgAST = &ast.Package{
 					Value: nil,
 				}},
 			},
+		}, &ast.BuiltinStatement{
+			BaseNode: ast.BaseNode{
+				Errors: nil,
+				Loc: &ast.SourceLocation{
+					End: ast.Position{
+						Column: 19,
+						Line:   23,
+					},
+					File:   "prometheus.flux",
+					Source: "builtin remoteRead",
+					Start: ast.Position{
+						Column: 1,
+						Line:   23,
+					},
+				},
+			},
+			ID: &ast.Identifier{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 19,
+							Line:   23,
+						},
+						File:   "prometheus.flux",
+						Source: "remoteRead",
+						Start: ast.Position{
+							Column: 9,
+							Line:   23,
+						},
+					},
+				},
+				Name: "remoteRead",
+			},
+			Ty: ast.TypeExpression{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 98,
+							Line:   23,
+						},
+						File:   "prometheus.flux",
+						Source: "(url: string, matchers: [string], start: A, ?stop: B) => [C] where C: Record",
+						Start: ast.Position{
+							Column: 22,
+							Line:   23,
+						},
+					},
+				},
+				Constraints: []*ast.TypeConstraint{&ast.TypeConstraint{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 98,
+								Line:   23,
+							},
+							File:   "prometheus.flux",
+							Source: "C: Record",
+							Start: ast.Position{
+								Column: 89,
+								Line:   23,
+							},
+						},
+					},
+					Kinds: []*ast.Identifier{&ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 98,
+									Line:   23,
+								},
+								File:   "prometheus.flux",
+								Source: "Record",
+								Start: ast.Position{
+									Column: 92,
+									Line:   23,
+								},
+							},
+						},
+						Name: "Record",
+					}},
+					Tvar: &ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 90,
+									Line:   23,
+								},
+								File:   "prometheus.flux",
+								Source: "C",
+								Start: ast.Position{
+									Column: 89,
+									Line:   23,
+								},
+							},
+						},
+						Name: "C",
+					},
+				}},
+				Ty: &ast.FunctionType{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 82,
+								Line:   23,
+							},
+							File:   "prometheus.flux",
+							Source: "(url: string, matchers: [string], start: A, ?stop: B) => [C]",
+							Start: ast.Position{
+								Column: 22,
+								Line:   23,
+							},
+						},
+					},
+					Parameters: []*ast.ParameterType{&ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 34,
+									Line:   23,
+								},
+								File:   "prometheus.flux",
+								Source: "url: string",
+								Start: ast.Position{
+									Column: 23,
+									Line:   23,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 26,
+										Line:   23,
+									},
+									File:   "prometheus.flux",
+									Source: "url",
+									Start: ast.Position{
+										Column: 23,
+										Line:   23,
+									},
+								},
+							},
+							Name: "url",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 34,
+										Line:   23,
+									},
+									File:   "prometheus.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 28,
+										Line:   23,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 34,
+											Line:   23,
+										},
+										File:   "prometheus.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 28,
+											Line:   23,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 54,
+									Line:   23,
+								},
+								File:   "prometheus.flux",
+								Source: "matchers: [string]",
+								Start: ast.Position{
+									Column: 36,
+									Line:   23,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 44,
+										Line:   23,
+									},
+									File:   "prometheus.flux",
+									Source: "matchers",
+									Start: ast.Position{
+										Column: 36,
+										Line:   23,
+									},
+								},
+							},
+							Name: "matchers",
+						},
+						Ty: &ast.ArrayType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 54,
+										Line:   23,
+									},
+									File:   "prometheus.flux",
+									Source: "[string]",
+									Start: ast.Position{
+										Column: 46,
+										Line:   23,
+									},
+								},
+							},
+							ElementType: &ast.NamedType{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 53,
+											Line:   23,
+										},
+										File:   "prometheus.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 47,
+											Line:   23,
+										},
+									},
+								},
+								ID: &ast.Identifier{
+									BaseNode: ast.BaseNode{
+										Errors: nil,
+										Loc: &ast.SourceLocation{
+											End: ast.Position{
+												Column: 53,
+												Line:   23,
+											},
+											File:   "prometheus.flux",
+											Source: "string",
+											Start: ast.Position{
+												Column: 47,
+												Line:   23,
+											},
+										},
+									},
+									Name: "string",
+								},
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 64,
+									Line:   23,
+								},
+								File:   "prometheus.flux",
+								Source: "start: A",
+								Start: ast.Position{
+									Column: 56,
+									Line:   23,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 61,
+										Line:   23,
+									},
+									File:   "prometheus.flux",
+									Source: "start",
+									Start: ast.Position{
+										Column: 56,
+										Line:   23,
+									},
+								},
+							},
+							Name: "start",
+						},
+						Ty: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 64,
+										Line:   23,
+									},
+									File:   "prometheus.flux",
+									Source: "A",
+									Start: ast.Position{
+										Column: 63,
+										Line:   23,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 64,
+											Line:   23,
+										},
+										File:   "prometheus.flux",
+										Source: "A",
+										Start: ast.Position{
+											Column: 63,
+											Line:   23,
+										},
+									},
+								},
+								Name: "A",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 74,
+									Line:   23,
+								},
+								File:   "prometheus.flux",
+								Source: "?stop: B",
+								Start: ast.Position{
+									Column: 66,
+									Line:   23,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 71,
+										Line:   23,
+									},
+									File:   "prometheus.flux",
+									Source: "stop",
+									Start: ast.Position{
+										Column: 67,
+										Line:   23,
+									},
+								},
+							},
+							Name: "stop",
+						},
+						Ty: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 74,
+										Line:   23,
+									},
+									File:   "prometheus.flux",
+									Source: "B",
+									Start: ast.Position{
+										Column: 73,
+										Line:   23,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 74,
+											Line:   23,
+										},
+										File:   "prometheus.flux",
+										Source: "B",
+										Start: ast.Position{
+											Column: 73,
+											Line:   23,
+										},
+									},
+								},
+								Name: "B",
+							},
+						},
+					}},
+					Return: &ast.ArrayType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 82,
+									Line:   23,
+								},
+								File:   "prometheus.flux",
+								Source: "[C]",
+								Start: ast.Position{
+									Column: 79,
+									Line:   23,
+								},
+							},
+						},
+						ElementType: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 81,
+										Line:   23,
+									},
+									File:   "prometheus.flux",
+									Source: "C",
+									Start: ast.Position{
+										Column: 80,
+										Line:   23,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 81,
+											Line:   23,
+										},
+										File:   "prometheus.flux",
+										Source: "C",
+										Start: ast.Position{
+											Column: 80,
+											Line:   23,
+										},
+									},
+								},
+								Name: "C",
+							},
+						},
+					},
+				},
+			},
+		}, &ast.BuiltinStatement{
+			BaseNode: ast.BaseNode{
+				Errors: nil,
+				Loc: &ast.SourceLocation{
+					End: ast.Position{
+						Column: 20,
+						Line:   28,
+					},
+					File:   "prometheus.flux",
+					Source: "builtin remoteWrite",
+					Start: ast.Position{
+						Column: 1,
+						Line:   28,
+					},
+				},
+			},
+			ID: &ast.Identifier{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 20,
+							Line:   28,
+						},
+						File:   "prometheus.flux",
+						Source: "remoteWrite",
+						Start: ast.Position{
+							Column: 9,
+							Line:   28,
+						},
+					},
+				},
+				Name: "remoteWrite",
+			},
+			Ty: ast.TypeExpression{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 74,
+							Line:   28,
+						},
+						File:   "prometheus.flux",
+						Source: "(<-tables: [A], url: string) => [A] where A: Record",
+						Start: ast.Position{
+							Column: 23,
+							Line:   28,
+						},
+					},
+				},
+				Constraints: []*ast.TypeConstraint{&ast.TypeConstraint{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 74,
+								Line:   28,
+							},
+							File:   "prometheus.flux",
+							Source: "A: Record",
+							Start: ast.Position{
+								Column: 65,
+								Line:   28,
+							},
+						},
+					},
+					Kinds: []*ast.Identifier{&ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 74,
+									Line:   28,
+								},
+								File:   "prometheus.flux",
+								Source: "Record",
+								Start: ast.Position{
+									Column: 68,
+									Line:   28,
+								},
+							},
+						},
+						Name: "Record",
+					}},
+					Tvar: &ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 66,
+									Line:   28,
+								},
+								File:   "prometheus.flux",
+								Source: "A",
+								Start: ast.Position{
+									Column: 65,
+									Line:   28,
+								},
+							},
+						},
+						Name: "A",
+					},
+				}},
+				Ty: &ast.FunctionType{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 58,
+								Line:   28,
+							},
+							File:   "prometheus.flux",
+							Source: "(<-tables: [A], url: string) => [A]",
+							Start: ast.Position{
+								Column: 23,
+								Line:   28,
+							},
+						},
+					},
+					Parameters: []*ast.ParameterType{&ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 37,
+									Line:   28,
+								},
+								File:   "prometheus.flux",
+								Source: "<-tables: [A]",
+								Start: ast.Position{
+									Column: 24,
+									Line:   28,
+								},
+							},
+						},
+						Kind: "Pipe",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 32,
+										Line:   28,
+									},
+									File:   "prometheus.flux",
+									Source: "tables",
+									Start: ast.Position{
+										Column: 26,
+										Line:   28,
+									},
+								},
+							},
+							Name: "tables",
+						},
+						Ty: &ast.ArrayType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 37,
+										Line:   28,
+									},
+									File:   "prometheus.flux",
+									Source: "[A]",
+									Start: ast.Position{
+										Column: 34,
+										Line:   28,
+									},
+								},
+							},
+							ElementType: &ast.TvarType{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 36,
+											Line:   28,
+										},
+										File:   "prometheus.flux",
+										Source: "A",
+										Start: ast.Position{
+											Column: 35,
+											Line:   28,
+										},
+									},
+								},
+								ID: &ast.Identifier{
+									BaseNode: ast.BaseNode{
+										Errors: nil,
+										Loc: &ast.SourceLocation{
+											End: ast.Position{
+												Column: 36,
+												Line:   28,
+											},
+											File:   "prometheus.flux",
+											Source: "A",
+											Start: ast.Position{
+												Column: 35,
+												Line:   28,
+											},
+										},
+									},
+									Name: "A",
+								},
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 50,
+									Line:   28,
+								},
+								File:   "prometheus.flux",
+								Source: "url: string",
+								Start: ast.Position{
+									Column: 39,
+									Line:   28,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 42,
+										Line:   28,
+									},
+									File:   "prometheus.flux",
+									Source: "url",
+									Start: ast.Position{
+										Column: 39,
+										Line:   28,
+									},
+								},
+							},
+							Name: "url",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 50,
+										Line:   28,
+									},
+									File:   "prometheus.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 44,
+										Line:   28,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 50,
+											Line:   28,
+										},
+										File:   "prometheus.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 44,
+											Line:   28,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}},
+					Return: &ast.ArrayType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 58,
+									Line:   28,
+								},
+								File:   "prometheus.flux",
+								Source: "[A]",
+								Start: ast.Position{
+									Column: 55,
+									Line:   28,
+								},
+							},
+						},
+						ElementType: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 57,
+										Line:   28,
+									},
+									File:   "prometheus.flux",
+									Source: "A",
+									Start: ast.Position{
+										Column: 56,
+										Line:   28,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 57,
+											Line:   28,
+										},
+										File:   "prometheus.flux",
+										Source: "A",
+										Start: ast.Position{
+											Column: 56,
+											Line:   28,
+										},
+									},
+								},
+								Name: "A",
+							},
+						},
+					},
+				},
+			},
 		}},
 		Imports: []*ast.ImportDeclaration{&ast.ImportDeclaration{
 			As: nil,
@@ -9,7 +9,6 @@ import (
 	"time"
 
 	flux "github.com/influxdata/flux"
-	"github.com/influxdata/flux/dependencies/dependenciestest"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
 	"github.com/influxdata/flux/mock"
@@ -229,7 +228,8 @@ func testSourceDecoder(p *PrometheusIterator, t *testing.T) *executetest.Result
 	results := &executetest.Result{}
 	runOnce := true
 
-	ctx := dependenciestest.Default().Inject(context.Background())
+	// The metrics are scraped with the HTTP client of the dependencies.
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
 	err := p.Connect(ctx)
 	if err != nil {
 		t.Fatal(err)
@@ -0,0 +1,110 @@
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	fluxurl "github.com/influxdata/flux/dependencies/url"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/opentracing/opentracing-go"
+)
+
+// The versions of the remote read and write protocols that are spoken.
+const (
+	remoteReadVersion  = "0.1.0"
+	remoteWriteVersion = "0.1.0"
+)
+
+// maxErrorBody is the number of bytes of a response
+// body that are included in the error of a failed request.
+const maxErrorBody = 512
+
+// postRemote sends the snappy compressed protocol buffer message to a remote
+// read or write endpoint with the HTTPClient dependency and returns the
+// body of the response. The name identifies the function
+// that makes the request in traces, errors and URL validation.
+func postRemote(ctx context.Context, name, rawurl string, header http.Header, msg proto.Message) ([]byte, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "invalid url")
+	}
+	deps := flux.GetDependencies(ctx)
+	validator, err := deps.URLValidator()
+	if err != nil {
+		return nil, err
+	}
+	if err := fluxurl.ForFunction(validator, name).Validate(u); err != nil {
+		return nil, err
+	}
+	client, err := deps.HTTPClient()
+	if err != nil {
+		return nil, errors.Wrapf(err, codes.Aborted, "missing client in %s", name)
+	}
+
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Internal, "failed to encode request")
+	}
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(snappy.Encode(nil, data)))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	s, cctx := opentracing.StartSpanFromContext(ctx, name)
+	s.SetTag("url", u.String())
+	defer s.Finish()
+
+	resp, err := client.Do(req.WithContext(fluxurl.WithFunction(cctx, name)))
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		if len(body) > maxErrorBody {
+			body = body[:maxErrorBody]
+		}
+		msg := strings.TrimSpace(string(body))
+		if msg == "" {
+			msg = http.StatusText(resp.StatusCode)
+		}
+		return nil, errors.Newf(statusCode(resp.StatusCode), "%s failed with status %d: %s", name, resp.StatusCode, msg)
+	}
+	return body, nil
+}
+
+// statusCode returns the flux code for a failed HTTP status.
+func statusCode(status int) codes.Code {
+	switch status {
+	case http.StatusBadRequest:
+		return codes.Invalid
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Unknown
+	}
+}
@@ -0,0 +1,308 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/stdlib/experimental/prometheus/internal/prompb"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/promql/v2"
+	"github.com/influxdata/promql/v2/pkg/labels"
+)
+
+const RemoteReadPrometheusKind = "remoteReadPrometheus"
+
+// RemoteReadPrometheusOpSpec reads series from a Prometheus remote read endpoint.
+type RemoteReadPrometheusOpSpec struct {
+	URL      string    `json:"url"`
+	Matchers []string  `json:"matchers"`
+	Start    flux.Time `json:"start"`
+	Stop     flux.Time `json:"stop"`
+}
+
+func init() {
+	remoteReadSignature := runtime.MustLookupBuiltinType("experimental/prometheus", "remoteRead")
+	runtime.RegisterPackageValue("experimental/prometheus", "remoteRead", flux.MustValue(flux.FunctionValue(RemoteReadPrometheusKind, createRemoteReadPrometheusOpSpec, remoteReadSignature)))
+	flux.RegisterOpSpec(RemoteReadPrometheusKind, func() flux.OperationSpec { return new(RemoteReadPrometheusOpSpec) })
+	plan.RegisterProcedureSpec(RemoteReadPrometheusKind, newRemoteReadPrometheusProcedure, RemoteReadPrometheusKind)
+	execute.RegisterSource(RemoteReadPrometheusKind, createRemoteReadPrometheusSource)
+}
+
+func createRemoteReadPrometheusOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	spec := new(RemoteReadPrometheusOpSpec)
+	var err error
+	if spec.URL, err = args.GetRequiredString("url"); err != nil {
+		return nil, err
+	}
+	matchers, err := args.GetRequiredArray("matchers", semantic.String)
+	if err != nil {
+		return nil, err
+	}
+	spec.Matchers = make([]string, matchers.Len())
+	matchers.Range(func(i int, v values.Value) {
+		spec.Matchers[i] = v.Str()
+	})
+	if _, err := parseMatchers(spec.Matchers); err != nil {
+		return nil, err
+	}
+	if spec.Start, err = args.GetRequiredTime("start"); err != nil {
+		return nil, err
+	}
+	if stop, ok, err := args.GetTime("stop"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Stop = stop
+	} else {
+		spec.Stop = flux.Now
+	}
+	return spec, nil
+}
+
+func (s *RemoteReadPrometheusOpSpec) Kind() flux.OperationKind {
+	return RemoteReadPrometheusKind
+}
+
+// parseMatchers parses label matchers written like those of a PromQL
+// selector, such as `job="api"` or `__name__=~"http_.*"`.
+func parseMatchers(matchers []string) ([]*prompb.LabelMatcher, error) {
+	if len(matchers) == 0 {
+		return nil, errors.New(codes.Invalid, "at least one matcher is required")
+	}
+	ms, err := promql.ParseMetricSelector("{" + strings.Join(matchers, ",") + "}")
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "invalid matchers")
+	}
+	pms := make([]*prompb.LabelMatcher, len(ms))
+	for i, m := range ms {
+		pm := &prompb.LabelMatcher{Name: m.Name, Value: m.Value}
+		switch m.Type {
+		case labels.MatchEqual:
+			pm.Type = prompb.LabelMatcher_EQ
+		case labels.MatchNotEqual:
+			pm.Type = prompb.LabelMatcher_NEQ
+		case labels.MatchRegexp:
+			pm.Type = prompb.LabelMatcher_RE
+		case labels.MatchNotRegexp:
+			pm.Type = prompb.LabelMatcher_NRE
+		default:
+			return nil, errors.Newf(codes.Invalid, "unsupported matcher type %v", m.Type)
+		}
+		pms[i] = pm
+	}
+	return pms, nil
+}
+
+type RemoteReadPrometheusProcedureSpec struct {
+	plan.DefaultCost
+	URL      string
+	Matchers []string
+	Bounds   execute.Bounds
+}
+
+func newRemoteReadPrometheusProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*RemoteReadPrometheusOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	now := pa.Now()
+	return &RemoteReadPrometheusProcedureSpec{
+		URL:      spec.URL,
+		Matchers: spec.Matchers,
+		Bounds: execute.Bounds{
+			Start: values.ConvertTime(spec.Start.Time(now)),
+			Stop:  values.ConvertTime(spec.Stop.Time(now)),
+		},
+	}, nil
+}
+
+func (s *RemoteReadPrometheusProcedureSpec) Kind() plan.ProcedureKind {
+	return RemoteReadPrometheusKind
+}
+
+func (s *RemoteReadPrometheusProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	ns.Matchers = append([]string(nil), s.Matchers...)
+	return &ns
+}
+
+func createRemoteReadPrometheusSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := prSpec.(*RemoteReadPrometheusProcedureSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", prSpec)
+	}
+	return NewRemoteReadSource(spec, dsid, a)
+}
+
+// NewRemoteReadSource creates a source that reads the series of the spec
+// from a Prometheus remote read endpoint.
+func NewRemoteReadSource(spec *RemoteReadPrometheusProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	matchers, err := parseMatchers(spec.Matchers)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteReadSource{
+		id:       dsid,
+		spec:     spec,
+		matchers: matchers,
+		a:        a,
+	}, nil
+}
+
+type remoteReadSource struct {
+	execute.ExecutionNode
+	id       execute.DatasetID
+	spec     *RemoteReadPrometheusProcedureSpec
+	matchers []*prompb.LabelMatcher
+	a        execute.Administration
+	ts       []execute.Transformation
+}
+
+func (s *remoteReadSource) AddTransformation(t execute.Transformation) {
+	s.ts = append(s.ts, t)
+}
+
+func (s *remoteReadSource) Run(ctx context.Context) {
+	err := s.run(ctx)
+	for _, t := range s.ts {
+		t.Finish(s.id, err)
+	}
+}
+
+func (s *remoteReadSource) run(ctx context.Context) error {
+	req := &prompb.ReadRequest{
+		Queries: []*prompb.Query{{
+			StartTimestampMs: toMillis(s.spec.Bounds.Start),
+			EndTimestampMs:   endMillis(s.spec.Bounds.Stop),
+			Matchers:         s.matchers,
+		}},
+	}
+	header := http.Header{}
+	header.Set("X-Prometheus-Remote-Read-Version", remoteReadVersion)
+	body, err := postRemote(ctx, "prometheus.remoteRead", s.spec.URL, header, req)
+	if err != nil {
+		return err
+	}
+	data, err := snappy.Decode(nil, body)
+	if err != nil {
+		return errors.Wrap(err, codes.Invalid, "invalid remote read response")
+	}
+	var resp prompb.ReadResponse
+	if err := proto.Unmarshal(data, &resp); err != nil {
+		return errors.Wrap(err, codes.Invalid, "invalid remote read response")
+	}
+
+	for _, result := range resp.Results {
+		for _, series := range result.Timeseries {
+			tbl, err := s.seriesTable(series)
+			if err != nil {
+				return err
+			}
+			for _, t := range s.ts {
+				if err := t.Process(s.id, tbl); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// seriesTable converts a series into a table with the same shape
+// as the tables of scrape. The metric name is the _field and
+// the other labels are part of the group key. A label that has
+// the name of one of the other columns is an error.
+func (s *remoteReadSource) seriesTable(series *prompb.TimeSeries) (flux.Table, error) {
+	var name string
+	tags := make([]*prompb.Label, 0, len(series.Labels))
+	for _, l := range series.Labels {
+		switch l.Name {
+		case labels.MetricName:
+			name = l.Value
+			continue
+		case "_time", "_value", "_measurement", "_field", "url":
+			// These columns are added to every table,
+			// so a label with the same name cannot be stored.
+			return nil, errors.Newf(codes.FailedPrecondition, "series label %q conflicts with a column of the same name", l.Name)
+		}
+		tags = append(tags, l)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+
+	keyBuilder := execute.NewGroupKeyBuilder(nil)
+	keyBuilder.AddKeyValue("_measurement", values.NewString("prometheus"))
+	keyBuilder.AddKeyValue("_field", values.NewString(name))
+	for _, l := range tags {
+		keyBuilder.AddKeyValue(l.Name, values.NewString(l.Value))
+	}
+	key, err := keyBuilder.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	builder := execute.NewColListTableBuilder(key, s.a.Allocator())
+	for _, col := range []flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+		{Label: "_measurement", Type: flux.TString},
+		{Label: "_field", Type: flux.TString},
+		{Label: "url", Type: flux.TString},
+	} {
+		if _, err := builder.AddCol(col); err != nil {
+			return nil, err
+		}
+	}
+	for _, l := range tags {
+		if _, err := builder.AddCol(flux.ColMeta{Label: l.Name, Type: flux.TString}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, sample := range series.Samples {
+		if err := builder.AppendTime(0, values.ConvertTime(time.Unix(0, sample.Timestamp*int64(time.Millisecond)))); err != nil {
+			return nil, err
+		}
+		if err := builder.AppendFloat(1, sample.Value); err != nil {
+			return nil, err
+		}
+		if err := builder.AppendString(2, "prometheus"); err != nil {
+			return nil, err
+		}
+		if err := builder.AppendString(3, name); err != nil {
+			return nil, err
+		}
+		if err := builder.AppendString(4, s.spec.URL); err != nil {
+			return nil, err
+		}
+		for j, l := range tags {
+			if err := builder.AppendString(5+j, l.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return builder.Table()
+}
+
+// toMillis converts a time to milliseconds since the epoch.
+func toMillis(t values.Time) int64 {
+	return int64(t) / int64(time.Millisecond)
+}
+
+// endMillis converts the exclusive stop time of the bounds into the
+// inclusive end of a remote read query, the last millisecond before stop.
+func endMillis(stop values.Time) int64 {
+	return toMillis(stop - values.Time(time.Millisecond))
+}
@@ -0,0 +1,474 @@
+package prometheus
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/mock"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/stdlib/experimental/prometheus/internal/prompb"
+)
+
+// readRemoteRequest decodes a snappy compressed protocol buffer request.
+func readRemoteRequest(t *testing.T, r *http.Request, msg proto.Message) {
+	t.Helper()
+	if got := r.Header.Get("Content-Encoding"); got != "snappy" {
+		t.Errorf("unexpected content encoding: %q", got)
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := snappy.Decode(nil, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRemoteRead(t *testing.T) {
+	var req prompb.ReadRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Prometheus-Remote-Read-Version"); got != remoteReadVersion {
+			t.Errorf("unexpected remote read version: %q", got)
+		}
+		readRemoteRequest(t, r, &req)
+		data, err := proto.Marshal(&prompb.ReadResponse{
+			Results: []*prompb.QueryResult{{
+				Timeseries: []*prompb.TimeSeries{
+					{
+						Labels: []*prompb.Label{
+							{Name: "__name__", Value: "up"},
+							{Name: "job", Value: "api"},
+							{Name: "instance", Value: "a:9090"},
+						},
+						Samples: []*prompb.Sample{
+							{Value: 1, Timestamp: 1000},
+							{Value: 0, Timestamp: 2000},
+						},
+					},
+					{
+						Labels: []*prompb.Label{
+							{Name: "__name__", Value: "up"},
+							{Name: "job", Value: "api"},
+							{Name: "instance", Value: "b:9090"},
+						},
+						Samples: []*prompb.Sample{
+							{Value: 1, Timestamp: 1000},
+						},
+					},
+				},
+			}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Header().Set("Content-Encoding", "snappy")
+		_, _ = w.Write(snappy.Encode(nil, data))
+	}))
+	defer ts.Close()
+
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	id := executetest.RandomDatasetID()
+	d := executetest.NewDataset(id)
+	c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	c.SetTriggerSpec(plan.DefaultTriggerSpec)
+	src, err := NewRemoteReadSource(&RemoteReadPrometheusProcedureSpec{
+		URL:      ts.URL,
+		Matchers: []string{`__name__="up"`, `job=~"api|web"`},
+		Bounds: execute.Bounds{
+			Start: execute.Time(time.Second),
+			Stop:  execute.Time(time.Minute),
+		},
+	}, id, mock.AdministrationWithContext(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.AddTransformation(executetest.NewYieldTransformation(d, c))
+	src.Run(ctx)
+	if d.FinishedErr != nil {
+		t.Fatal(d.FinishedErr)
+	}
+
+	wantReq := prompb.ReadRequest{
+		Queries: []*prompb.Query{{
+			StartTimestampMs: 1000,
+			EndTimestampMs:   59999,
+			Matchers: []*prompb.LabelMatcher{
+				{Type: prompb.LabelMatcher_EQ, Name: "__name__", Value: "up"},
+				{Type: prompb.LabelMatcher_RE, Name: "job", Value: "api|web"},
+			},
+		}},
+	}
+	if !cmp.Equal(wantReq, req) {
+		t.Errorf("unexpected read request -want/+got\n%s", cmp.Diff(wantReq, req))
+	}
+
+	got, err := executetest.TablesFromCache(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*executetest.Table{
+		{
+			KeyCols: []string{"_measurement", "_field", "instance", "job"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "url", Type: flux.TString},
+				{Label: "instance", Type: flux.TString},
+				{Label: "job", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(time.Second), 1.0, "prometheus", "up", ts.URL, "a:9090", "api"},
+				{execute.Time(2 * time.Second), 0.0, "prometheus", "up", ts.URL, "a:9090", "api"},
+			},
+		},
+		{
+			KeyCols: []string{"_measurement", "_field", "instance", "job"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "url", Type: flux.TString},
+				{Label: "instance", Type: flux.TString},
+				{Label: "job", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(time.Second), 1.0, "prometheus", "up", ts.URL, "b:9090", "api"},
+			},
+		},
+	}
+	executetest.NormalizeTables(want)
+	executetest.NormalizeTables(got)
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected tables -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestRemoteRead_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid matcher", http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	id := executetest.RandomDatasetID()
+	d := executetest.NewDataset(id)
+	c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	src, err := NewRemoteReadSource(&RemoteReadPrometheusProcedureSpec{
+		URL:      ts.URL,
+		Matchers: []string{`job="api"`},
+	}, id, mock.AdministrationWithContext(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.AddTransformation(executetest.NewYieldTransformation(d, c))
+	src.Run(ctx)
+	if d.FinishedErr == nil {
+		t.Fatal("expected error")
+	}
+	if want, got := "prometheus.remoteRead failed with status 400: invalid matcher", d.FinishedErr.Error(); want != got {
+		t.Errorf("unexpected error -want/+got:\n- %q\n+ %q", want, got)
+	}
+}
+
+func TestRemoteRead_LabelConflict(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := proto.Marshal(&prompb.ReadResponse{
+			Results: []*prompb.QueryResult{{
+				Timeseries: []*prompb.TimeSeries{{
+					Labels: []*prompb.Label{
+						{Name: "__name__", Value: "probe_success"},
+						{Name: "url", Value: "http://example.com"},
+					},
+					Samples: []*prompb.Sample{{Value: 1, Timestamp: 1000}},
+				}},
+			}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Header().Set("Content-Encoding", "snappy")
+		_, _ = w.Write(snappy.Encode(nil, data))
+	}))
+	defer ts.Close()
+
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	id := executetest.RandomDatasetID()
+	d := executetest.NewDataset(id)
+	c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	src, err := NewRemoteReadSource(&RemoteReadPrometheusProcedureSpec{
+		URL:      ts.URL,
+		Matchers: []string{`__name__="probe_success"`},
+	}, id, mock.AdministrationWithContext(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.AddTransformation(executetest.NewYieldTransformation(d, c))
+	src.Run(ctx)
+	if d.FinishedErr == nil {
+		t.Fatal("expected error")
+	}
+	if want, got := codes.FailedPrecondition, errors.Code(d.FinishedErr); want != got {
+		t.Errorf("unexpected error code -want/+got:\n- %v\n+ %v", want, got)
+	}
+	if !strings.Contains(d.FinishedErr.Error(), `"url"`) {
+		t.Errorf("unexpected error: %v", d.FinishedErr)
+	}
+}
+
+func TestParseMatchers(t *testing.T) {
+	for _, matchers := range [][]string{
+		nil,
+		{`job`},
+		{`job=api`},
+		{`job=~"("`},
+		{`job!="api"`},
+	} {
+		if _, err := parseMatchers(matchers); err == nil {
+			t.Errorf("expected error for matchers %q", matchers)
+		}
+	}
+
+	got, err := parseMatchers([]string{`a="1"`, `b!="2"`, `c!~"3.*"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*prompb.LabelMatcher{
+		{Type: prompb.LabelMatcher_EQ, Name: "a", Value: "1"},
+		{Type: prompb.LabelMatcher_NEQ, Name: "b", Value: "2"},
+		{Type: prompb.LabelMatcher_NRE, Name: "c", Value: "3.*"},
+	}
+	if !cmp.Equal(want, got) {
+		t.Errorf("unexpected matchers -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestRemoteWrite(t *testing.T) {
+	var reqs []prompb.WriteRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Prometheus-Remote-Write-Version"); got != remoteWriteVersion {
+			t.Errorf("unexpected remote write version: %q", got)
+		}
+		var req prompb.WriteRequest
+		readRemoteRequest(t, r, &req)
+		reqs = append(reqs, req)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	id := executetest.RandomDatasetID()
+	tr, d := NewRemoteWriteTransformation(ctx, id, &RemoteWritePrometheusProcedureSpec{URL: ts.URL})
+	c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	c.SetTriggerSpec(plan.DefaultTriggerSpec)
+	d.SetTriggerSpec(plan.DefaultTriggerSpec)
+	store := executetest.NewDataStore()
+	d.AddTransformation(store)
+
+	tables := []*executetest.Table{
+		{
+			KeyCols: []string{"_measurement", "_field", "_start", "_stop", "host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_start", Type: flux.TTime},
+				{Label: "_stop", Type: flux.TTime},
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "host", Type: flux.TString},
+				{Label: "_value", Type: flux.TInt},
+			},
+			Data: [][]interface{}{
+				{execute.Time(0), execute.Time(time.Minute), execute.Time(2 * time.Second), "cpu", "usage", "a", int64(2)},
+				{execute.Time(0), execute.Time(time.Minute), execute.Time(time.Second), "cpu", "usage", "a", int64(1)},
+				{execute.Time(0), execute.Time(time.Minute), execute.Time(3 * time.Second), "cpu", "usage", "a", nil},
+			},
+		},
+		{
+			KeyCols: []string{"_measurement", "host"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "host", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(time.Second), "mem", "free", "b", 0.5},
+				{execute.Time(time.Second), "mem", "used", "b", 1.5},
+			},
+		},
+	}
+	for _, tbl := range tables {
+		if err := tr.Process(executetest.RandomDatasetID(), tbl); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tr.Finish(id, nil)
+	if err := store.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []prompb.WriteRequest{{
+		Timeseries: []*prompb.TimeSeries{
+			{
+				Labels: []*prompb.Label{
+					{Name: "__name__", Value: "usage"},
+					{Name: "host", Value: "a"},
+				},
+				Samples: []*prompb.Sample{
+					{Value: 1, Timestamp: 1000},
+					{Value: 2, Timestamp: 2000},
+				},
+			},
+			{
+				Labels: []*prompb.Label{
+					{Name: "__name__", Value: "free"},
+					{Name: "host", Value: "b"},
+				},
+				Samples: []*prompb.Sample{{Value: 0.5, Timestamp: 1000}},
+			},
+			{
+				Labels: []*prompb.Label{
+					{Name: "__name__", Value: "used"},
+					{Name: "host", Value: "b"},
+				},
+				Samples: []*prompb.Sample{{Value: 1.5, Timestamp: 1000}},
+			},
+		},
+	}}
+	if !cmp.Equal(want, reqs) {
+		t.Errorf("unexpected write requests -want/+got\n%s", cmp.Diff(want, reqs))
+	}
+}
+
+func TestRemoteWrite_SortLabels(t *testing.T) {
+	var req prompb.WriteRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		readRemoteRequest(t, r, &req)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	id := executetest.RandomDatasetID()
+	tr, d := NewRemoteWriteTransformation(ctx, id, &RemoteWritePrometheusProcedureSpec{URL: ts.URL})
+	store := executetest.NewDataStore()
+	d.AddTransformation(store)
+
+	if err := tr.Process(executetest.RandomDatasetID(), &executetest.Table{
+		KeyCols: []string{"_field", "host", "Region", "__name__"},
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_field", Type: flux.TString},
+			{Label: "host", Type: flux.TString},
+			{Label: "Region", Type: flux.TString},
+			{Label: "__name__", Type: flux.TString},
+			{Label: "_value", Type: flux.TFloat},
+		},
+		Data: [][]interface{}{
+			{execute.Time(time.Second), "up", "a", "eu", "ignored", 1.0},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tr.Finish(id, nil)
+	if err := store.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*prompb.Label{
+		{Name: "Region", Value: "eu"},
+		{Name: "__name__", Value: "up"},
+		{Name: "host", Value: "a"},
+	}
+	if len(req.Timeseries) != 1 {
+		t.Fatalf("expected one series, got %d", len(req.Timeseries))
+	}
+	if got := req.Timeseries[0].Labels; !cmp.Equal(want, got) {
+		t.Errorf("unexpected labels -want/+got\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestRemoteWrite_Error(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	id := executetest.RandomDatasetID()
+	tr, d := NewRemoteWriteTransformation(ctx, id, &RemoteWritePrometheusProcedureSpec{URL: ts.URL})
+	store := executetest.NewDataStore()
+	d.AddTransformation(store)
+
+	if err := tr.Process(executetest.RandomDatasetID(), &executetest.Table{
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_field", Type: flux.TString},
+			{Label: "_value", Type: flux.TFloat},
+		},
+		Data: [][]interface{}{
+			{execute.Time(time.Second), "up", 1.0},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tr.Finish(id, nil)
+	if err := store.Err(); err == nil || !strings.Contains(err.Error(), "out of order sample") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRemoteWrite_FinishWithError(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	id := executetest.RandomDatasetID()
+	tr, d := NewRemoteWriteTransformation(ctx, id, &RemoteWritePrometheusProcedureSpec{URL: ts.URL})
+	store := executetest.NewDataStore()
+	d.AddTransformation(store)
+
+	if err := tr.Process(executetest.RandomDatasetID(), &executetest.Table{
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_field", Type: flux.TString},
+			{Label: "_value", Type: flux.TFloat},
+		},
+		Data: [][]interface{}{
+			{execute.Time(time.Second), "up", 1.0},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tr.Finish(id, errors.New(codes.Internal, "query failed"))
+	if err := store.Err(); err == nil || !strings.Contains(err.Error(), "query failed") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected the pending series to be dropped, got %d requests", requests)
+	}
+}
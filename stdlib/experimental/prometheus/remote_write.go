@@ -0,0 +1,266 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/stdlib/experimental/prometheus/internal/prompb"
+	"github.com/influxdata/promql/v2/pkg/labels"
+)
+
+const RemoteWritePrometheusKind = "remoteWritePrometheus"
+
+// remoteWriteBatchSize is the number of samples
+// that are sent with each remote write request.
+const remoteWriteBatchSize = 1000
+
+// RemoteWritePrometheusOpSpec writes tables to a Prometheus remote write endpoint.
+type RemoteWritePrometheusOpSpec struct {
+	URL string `json:"url"`
+}
+
+func init() {
+	remoteWriteSignature := runtime.MustLookupBuiltinType("experimental/prometheus", "remoteWrite")
+	runtime.RegisterPackageValue("experimental/prometheus", "remoteWrite", flux.MustValue(flux.FunctionValueWithSideEffect(RemoteWritePrometheusKind, createRemoteWritePrometheusOpSpec, remoteWriteSignature)))
+	flux.RegisterOpSpec(RemoteWritePrometheusKind, func() flux.OperationSpec { return new(RemoteWritePrometheusOpSpec) })
+	plan.RegisterProcedureSpecWithSideEffect(RemoteWritePrometheusKind, newRemoteWritePrometheusProcedure, RemoteWritePrometheusKind)
+	execute.RegisterTransformation(RemoteWritePrometheusKind, createRemoteWritePrometheusTransformation)
+}
+
+func createRemoteWritePrometheusOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+	spec := new(RemoteWritePrometheusOpSpec)
+	var err error
+	if spec.URL, err = args.GetRequiredString("url"); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+func (s *RemoteWritePrometheusOpSpec) Kind() flux.OperationKind {
+	return RemoteWritePrometheusKind
+}
+
+type RemoteWritePrometheusProcedureSpec struct {
+	plan.DefaultCost
+	URL string
+}
+
+func newRemoteWritePrometheusProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*RemoteWritePrometheusOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &RemoteWritePrometheusProcedureSpec{URL: spec.URL}, nil
+}
+
+func (s *RemoteWritePrometheusProcedureSpec) Kind() plan.ProcedureKind {
+	return RemoteWritePrometheusKind
+}
+
+func (s *RemoteWritePrometheusProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+
+func createRemoteWritePrometheusTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*RemoteWritePrometheusProcedureSpec)
+	if !ok {
+		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
+	}
+	t, d := NewRemoteWriteTransformation(a.Context(), id, s)
+	return t, d, nil
+}
+
+// RemoteWriteTransformation writes each table to a Prometheus remote
+// write endpoint and passes the tables through unchanged.
+type RemoteWriteTransformation struct {
+	execute.ExecutionNode
+	ctx     context.Context
+	d       *execute.PassthroughDataset
+	spec    *RemoteWritePrometheusProcedureSpec
+	pending []*prompb.TimeSeries
+	samples int
+}
+
+// NewRemoteWriteTransformation creates a transformation that writes
+// to the remote write endpoint of the spec.
+func NewRemoteWriteTransformation(ctx context.Context, id execute.DatasetID, spec *RemoteWritePrometheusProcedureSpec) (*RemoteWriteTransformation, execute.Dataset) {
+	t := &RemoteWriteTransformation{
+		ctx:  ctx,
+		d:    execute.NewPassthroughDataset(id),
+		spec: spec,
+	}
+	return t, t.d
+}
+
+func (t *RemoteWriteTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *RemoteWriteTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	buffer, err := execute.CopyTable(tbl)
+	if err != nil {
+		return err
+	}
+	if err := t.writeTable(buffer.Copy()); err != nil {
+		buffer.Done()
+		return err
+	}
+	return t.d.Process(buffer)
+}
+
+// writeTable converts the rows of the table into samples.
+//
+// The metric name is read from the _field column, the timestamp from
+// the _time column and the value from the _value column.
+// The other string columns of the group key, except for _measurement,
+// _start, _stop and __name__, are the labels of the series. Rows with a null
+// _field or _value are skipped.
+func (t *RemoteWriteTransformation) writeTable(tbl flux.Table) error {
+	cols := tbl.Cols()
+	timeIdx := execute.ColIdx(execute.DefaultTimeColLabel, cols)
+	if timeIdx < 0 {
+		return errors.Newf(codes.FailedPrecondition, "table is missing the %q column", execute.DefaultTimeColLabel)
+	} else if cols[timeIdx].Type != flux.TTime {
+		return errors.Newf(codes.FailedPrecondition, "column %q must be of type %s", execute.DefaultTimeColLabel, flux.TTime)
+	}
+	fieldIdx := execute.ColIdx("_field", cols)
+	if fieldIdx < 0 {
+		return errors.Newf(codes.FailedPrecondition, "table is missing the %q column", "_field")
+	} else if cols[fieldIdx].Type != flux.TString {
+		return errors.Newf(codes.FailedPrecondition, "column %q must be of type %s", "_field", flux.TString)
+	}
+	valueIdx := execute.ColIdx(execute.DefaultValueColLabel, cols)
+	if valueIdx < 0 {
+		return errors.Newf(codes.FailedPrecondition, "table is missing the %q column", execute.DefaultValueColLabel)
+	}
+	switch typ := cols[valueIdx].Type; typ {
+	case flux.TFloat, flux.TInt, flux.TUInt:
+	default:
+		return errors.Newf(codes.FailedPrecondition, "column %q must be numeric, got %s", execute.DefaultValueColLabel, typ)
+	}
+
+	var tags []*prompb.Label
+	key := tbl.Key()
+	for j, c := range key.Cols() {
+		switch c.Label {
+		case "_measurement", "_field", execute.DefaultStartColLabel, execute.DefaultStopColLabel, labels.MetricName:
+			continue
+		}
+		if c.Type != flux.TString || key.IsNull(j) {
+			continue
+		}
+		tags = append(tags, &prompb.Label{Name: c.Label, Value: key.ValueString(j)})
+	}
+
+	// The _field column is usually part of the group key,
+	// but a table can also hold the samples of several series.
+	series := make(map[string]*prompb.TimeSeries)
+	var names []string
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		fields := cr.Strings(fieldIdx)
+		times := cr.Times(timeIdx)
+		for i, l := 0, cr.Len(); i < l; i++ {
+			if !fields.IsValid(i) {
+				continue
+			}
+			if !times.IsValid(i) {
+				return errors.Newf(codes.FailedPrecondition, "null value in the %q column", execute.DefaultTimeColLabel)
+			}
+			v := execute.ValueForRow(cr, i, valueIdx)
+			if v.IsNull() {
+				continue
+			}
+			var value float64
+			switch cols[valueIdx].Type {
+			case flux.TFloat:
+				value = v.Float()
+			case flux.TInt:
+				value = float64(v.Int())
+			case flux.TUInt:
+				value = float64(v.UInt())
+			}
+
+			name := fields.ValueString(i)
+			ts, ok := series[name]
+			if !ok {
+				ts = &prompb.TimeSeries{
+					Labels: append([]*prompb.Label{{Name: labels.MetricName, Value: name}}, tags...),
+				}
+				// The remote write protocol requires the labels to be sorted
+				// by name and __name__ does not sort before upper case names.
+				sort.Slice(ts.Labels, func(i, j int) bool {
+					return ts.Labels[i].Name < ts.Labels[j].Name
+				})
+				series[name] = ts
+				names = append(names, name)
+			}
+			ts.Samples = append(ts.Samples, &prompb.Sample{
+				Value:     value,
+				Timestamp: times.Value(i) / int64(time.Millisecond),
+			})
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		ts := series[name]
+		// Prometheus requires the samples of a series to be in order.
+		sort.SliceStable(ts.Samples, func(i, j int) bool {
+			return ts.Samples[i].Timestamp < ts.Samples[j].Timestamp
+		})
+		t.pending = append(t.pending, ts)
+		t.samples += len(ts.Samples)
+		if t.samples >= remoteWriteBatchSize {
+			if err := t.flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// flush sends the pending series to the remote write endpoint.
+func (t *RemoteWriteTransformation) flush() error {
+	if len(t.pending) == 0 {
+		return nil
+	}
+	req := &prompb.WriteRequest{Timeseries: t.pending}
+	t.pending, t.samples = nil, 0
+
+	header := http.Header{}
+	header.Set("X-Prometheus-Remote-Write-Version", remoteWriteVersion)
+	_, err := postRemote(t.ctx, "prometheus.remoteWrite", t.spec.URL, header, req)
+	return err
+}
+
+func (t *RemoteWriteTransformation) UpdateWatermark(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateWatermark(pt)
+}
+
+func (t *RemoteWriteTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+
+func (t *RemoteWriteTransformation) Finish(id execute.DatasetID, err error) {
+	// The pending series of a failed query are dropped because the
+	// last of them may be cut short. Earlier batches were already
+	// accepted by the endpoint and cannot be taken back.
+	if err == nil {
+		err = t.flush()
+	}
+	t.d.Finish(err)
+}
@@ -18,6 +18,7 @@ import (
 	// Flux packages
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
+	fluxurl "github.com/influxdata/flux/dependencies/url"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/plan"
@@ -150,12 +151,20 @@ func (p *PrometheusIterator) Connect(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	if err := validator.Validate(u); err != nil {
+	if err := fluxurl.ForFunction(validator, "prometheus.scrape").Validate(u); err != nil {
 		return err
 	}
 
 	// Get response
-	resp, err := http.Get(p.url)
+	client, err := deps.HTTPClient()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("GET", p.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req.WithContext(fluxurl.WithFunction(ctx, "prometheus.scrape")))
 	if err != nil {
 		return err
 	}
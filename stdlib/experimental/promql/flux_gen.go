@@ -0,0 +1,811 @@
+// DO NOT EDIT: This file is autogenerated via the builtin command.
+
+package promql
+
+import (
+	ast "github.com/influxdata/flux/ast"
+	runtime "github.com/influxdata/flux/runtime"
+)
+
+func init() {
+	runtime.RegisterPackage(pkgAST)
+}
+
+var pkgAST = &ast.Package{
+	BaseNode: ast.BaseNode{
+		Errors: nil,
+		Loc:    nil,
+	},
+	Files: []*ast.File{&ast.File{
+		BaseNode: ast.BaseNode{
+			Errors: nil,
+			Loc: &ast.SourceLocation{
+				End: ast.Position{
+					Column: 14,
+					Line:   7,
+				},
+				File:   "promql.flux",
+				Source: "package promql\n\n// query transpiles the PromQL expression expr into Flux and evaluates it\n// against the Prometheus data stored in an InfluxDB bucket.\n// The expression is evaluated at every step between start and end,\n// or only at end when step is zero.\nbuiltin query",
+				Start: ast.Position{
+					Column: 1,
+					Line:   1,
+				},
+			},
+		},
+		Body: []ast.Statement{&ast.BuiltinStatement{
+			BaseNode: ast.BaseNode{
+				Errors: nil,
+				Loc: &ast.SourceLocation{
+					End: ast.Position{
+						Column: 14,
+						Line:   7,
+					},
+					File:   "promql.flux",
+					Source: "builtin query",
+					Start: ast.Position{
+						Column: 1,
+						Line:   7,
+					},
+				},
+			},
+			ID: &ast.Identifier{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 14,
+							Line:   7,
+						},
+						File:   "promql.flux",
+						Source: "query",
+						Start: ast.Position{
+							Column: 9,
+							Line:   7,
+						},
+					},
+				},
+				Name: "query",
+			},
+			Ty: ast.TypeExpression{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 156,
+							Line:   7,
+						},
+						File:   "promql.flux",
+						Source: "(expr: string, bucket: string, start: time, end: time, ?step: duration, ?org: string, ?host: string, ?token: string) => [A] where A: Record",
+						Start: ast.Position{
+							Column: 17,
+							Line:   7,
+						},
+					},
+				},
+				Constraints: []*ast.TypeConstraint{&ast.TypeConstraint{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 156,
+								Line:   7,
+							},
+							File:   "promql.flux",
+							Source: "A: Record",
+							Start: ast.Position{
+								Column: 147,
+								Line:   7,
+							},
+						},
+					},
+					Kinds: []*ast.Identifier{&ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 156,
+									Line:   7,
+								},
+								File:   "promql.flux",
+								Source: "Record",
+								Start: ast.Position{
+									Column: 150,
+									Line:   7,
+								},
+							},
+						},
+						Name: "Record",
+					}},
+					Tvar: &ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 148,
+									Line:   7,
+								},
+								File:   "promql.flux",
+								Source: "A",
+								Start: ast.Position{
+									Column: 147,
+									Line:   7,
+								},
+							},
+						},
+						Name: "A",
+					},
+				}},
+				Ty: &ast.FunctionType{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 140,
+								Line:   7,
+							},
+							File:   "promql.flux",
+							Source: "(expr: string, bucket: string, start: time, end: time, ?step: duration, ?org: string, ?host: string, ?token: string) => [A]",
+							Start: ast.Position{
+								Column: 17,
+								Line:   7,
+							},
+						},
+					},
+					Parameters: []*ast.ParameterType{&ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 30,
+									Line:   7,
+								},
+								File:   "promql.flux",
+								Source: "expr: string",
+								Start: ast.Position{
+									Column: 18,
+									Line:   7,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 22,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "expr",
+									Start: ast.Position{
+										Column: 18,
+										Line:   7,
+									},
+								},
+							},
+							Name: "expr",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 30,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 24,
+										Line:   7,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 30,
+											Line:   7,
+										},
+										File:   "promql.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 24,
+											Line:   7,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 46,
+									Line:   7,
+								},
+								File:   "promql.flux",
+								Source: "bucket: string",
+								Start: ast.Position{
+									Column: 32,
+									Line:   7,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 38,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "bucket",
+									Start: ast.Position{
+										Column: 32,
+										Line:   7,
+									},
+								},
+							},
+							Name: "bucket",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 46,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 40,
+										Line:   7,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 46,
+											Line:   7,
+										},
+										File:   "promql.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 40,
+											Line:   7,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 59,
+									Line:   7,
+								},
+								File:   "promql.flux",
+								Source: "start: time",
+								Start: ast.Position{
+									Column: 48,
+									Line:   7,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 53,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "start",
+									Start: ast.Position{
+										Column: 48,
+										Line:   7,
+									},
+								},
+							},
+							Name: "start",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 59,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "time",
+									Start: ast.Position{
+										Column: 55,
+										Line:   7,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 59,
+											Line:   7,
+										},
+										File:   "promql.flux",
+										Source: "time",
+										Start: ast.Position{
+											Column: 55,
+											Line:   7,
+										},
+									},
+								},
+								Name: "time",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 70,
+									Line:   7,
+								},
+								File:   "promql.flux",
+								Source: "end: time",
+								Start: ast.Position{
+									Column: 61,
+									Line:   7,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 64,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "end",
+									Start: ast.Position{
+										Column: 61,
+										Line:   7,
+									},
+								},
+							},
+							Name: "end",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 70,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "time",
+									Start: ast.Position{
+										Column: 66,
+										Line:   7,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 70,
+											Line:   7,
+										},
+										File:   "promql.flux",
+										Source: "time",
+										Start: ast.Position{
+											Column: 66,
+											Line:   7,
+										},
+									},
+								},
+								Name: "time",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 87,
+									Line:   7,
+								},
+								File:   "promql.flux",
+								Source: "?step: duration",
+								Start: ast.Position{
+									Column: 72,
+									Line:   7,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 77,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "step",
+									Start: ast.Position{
+										Column: 73,
+										Line:   7,
+									},
+								},
+							},
+							Name: "step",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 87,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "duration",
+									Start: ast.Position{
+										Column: 79,
+										Line:   7,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 87,
+											Line:   7,
+										},
+										File:   "promql.flux",
+										Source: "duration",
+										Start: ast.Position{
+											Column: 79,
+											Line:   7,
+										},
+									},
+								},
+								Name: "duration",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 101,
+									Line:   7,
+								},
+								File:   "promql.flux",
+								Source: "?org: string",
+								Start: ast.Position{
+									Column: 89,
+									Line:   7,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 93,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "org",
+									Start: ast.Position{
+										Column: 90,
+										Line:   7,
+									},
+								},
+							},
+							Name: "org",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 101,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 95,
+										Line:   7,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 101,
+											Line:   7,
+										},
+										File:   "promql.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 95,
+											Line:   7,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 116,
+									Line:   7,
+								},
+								File:   "promql.flux",
+								Source: "?host: string",
+								Start: ast.Position{
+									Column: 103,
+									Line:   7,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 108,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "host",
+									Start: ast.Position{
+										Column: 104,
+										Line:   7,
+									},
+								},
+							},
+							Name: "host",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 116,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 110,
+										Line:   7,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 116,
+											Line:   7,
+										},
+										File:   "promql.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 110,
+											Line:   7,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 132,
+									Line:   7,
+								},
+								File:   "promql.flux",
+								Source: "?token: string",
+								Start: ast.Position{
+									Column: 118,
+									Line:   7,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 124,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "token",
+									Start: ast.Position{
+										Column: 119,
+										Line:   7,
+									},
+								},
+							},
+							Name: "token",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 132,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 126,
+										Line:   7,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 132,
+											Line:   7,
+										},
+										File:   "promql.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 126,
+											Line:   7,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}},
+					Return: &ast.ArrayType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 140,
+									Line:   7,
+								},
+								File:   "promql.flux",
+								Source: "[A]",
+								Start: ast.Position{
+									Column: 137,
+									Line:   7,
+								},
+							},
+						},
+						ElementType: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 139,
+										Line:   7,
+									},
+									File:   "promql.flux",
+									Source: "A",
+									Start: ast.Position{
+										Column: 138,
+										Line:   7,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 139,
+											Line:   7,
+										},
+										File:   "promql.flux",
+										Source: "A",
+										Start: ast.Position{
+											Column: 138,
+											Line:   7,
+										},
+									},
+								},
+								Name: "A",
+							},
+						},
+					},
+				},
+			},
+		}},
+		Imports:  nil,
+		Metadata: "parser-type=rust",
+		Name:     "promql.flux",
+		Package: &ast.PackageClause{
+			BaseNode: ast.BaseNode{
+				Errors: nil,
+				Loc: &ast.SourceLocation{
+					End: ast.Position{
+						Column: 15,
+						Line:   1,
+					},
+					File:   "promql.flux",
+					Source: "package promql",
+					Start: ast.Position{
+						Column: 1,
+						Line:   1,
+					},
+				},
+			},
+			Name: &ast.Identifier{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 15,
+							Line:   1,
+						},
+						File:   "promql.flux",
+						Source: "promql",
+						Start: ast.Position{
+							Column: 9,
+							Line:   1,
+						},
+					},
+				},
+				Name: "promql",
+			},
+		},
+	}},
+	Package: "promql",
+	Path:    "experimental/promql",
+}
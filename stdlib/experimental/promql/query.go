@@ -0,0 +1,124 @@
+// Package promql implements functions that evaluate PromQL
+// expressions by transpiling them into Flux.
+package promql
+
+import (
+	"context"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/interpreter"
+	fluxpromql "github.com/influxdata/flux/promql"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/promql/v2"
+)
+
+const pkgpath = "experimental/promql"
+
+func init() {
+	runtime.RegisterPackageValue(pkgpath, "query", queryFunc)
+}
+
+// query transpiles a PromQL expression and evaluates the resulting
+// Flux inline, so the tables it returns can be used like any other.
+var queryFunc = values.NewFunction(
+	"query",
+	runtime.MustLookupBuiltinType(pkgpath, "query"),
+	func(ctx context.Context, args values.Object) (values.Value, error) {
+		t, expr, err := readTranspiler(interpreter.NewArguments(args))
+		if err != nil {
+			return nil, err
+		}
+		pkg, err := transpile(t, expr)
+		if err != nil {
+			return nil, err
+		}
+		sideEffects, _, err := runtime.EvalAST(ctx, pkg)
+		if err != nil {
+			return nil, errors.Wrap(err, codes.Inherit, "failed to evaluate transpiled promql query")
+		}
+		for i := len(sideEffects) - 1; i >= 0; i-- {
+			if to, ok := sideEffects[i].Value.(*flux.TableObject); ok {
+				return to, nil
+			}
+		}
+		return nil, errors.New(codes.Internal, "transpiled promql query did not produce any tables")
+	},
+	false,
+)
+
+// readTranspiler reads the arguments of query into a transpiler
+// and the PromQL expression it transpiles.
+func readTranspiler(args interpreter.Arguments) (*fluxpromql.Transpiler, string, error) {
+	expr, err := args.GetRequiredString("expr")
+	if err != nil {
+		return nil, "", err
+	}
+	t := new(fluxpromql.Transpiler)
+	if t.Bucket, err = args.GetRequiredString("bucket"); err != nil {
+		return nil, "", err
+	}
+	if t.Start, err = getRequiredTime(args, "start"); err != nil {
+		return nil, "", err
+	}
+	if t.End, err = getRequiredTime(args, "end"); err != nil {
+		return nil, "", err
+	}
+	if v, ok := args.Get("step"); ok {
+		if v.Type().Nature() != semantic.Duration {
+			return nil, "", errors.Newf(codes.Invalid, "expected argument %q to be of type %v, got type %v", "step", semantic.Duration, v.Type().Nature())
+		}
+		t.Resolution = v.Duration().Duration()
+	}
+	for name, dst := range map[string]*string{"org": &t.Org, "host": &t.Host, "token": &t.Token} {
+		if *dst, _, err = args.GetString(name); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if t.Resolution < 0 {
+		return nil, "", errors.New(codes.Invalid, "step must not be negative")
+	}
+	if t.Start.After(t.End) {
+		return nil, "", errors.New(codes.Invalid, "start must not be after end")
+	}
+	if t.Resolution == 0 {
+		// Instant queries are evaluated at the end of the range.
+		t.Start = t.End
+	}
+	return t, expr, nil
+}
+
+func getRequiredTime(args interpreter.Arguments, name string) (time.Time, error) {
+	v, err := args.GetRequired(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if v.Type().Nature() != semantic.Time {
+		return time.Time{}, errors.Newf(codes.Invalid, "expected argument %q to be of type %v, got type %v", name, semantic.Time, v.Type().Nature())
+	}
+	return v.Time().Time(), nil
+}
+
+// transpile parses the PromQL expression and transpiles it
+// into the main package of a Flux program.
+func transpile(t *fluxpromql.Transpiler, expr string) (*ast.Package, error) {
+	e, err := promql.ParseExpr(expr)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "invalid promql expression")
+	}
+	file, err := t.Transpile(e)
+	if err != nil {
+		return nil, errors.Wrap(err, codes.Invalid, "failed to transpile promql expression")
+	}
+	file.Name = "promql.flux"
+	return &ast.Package{
+		Package: "main",
+		Files:   []*ast.File{file},
+	}, nil
+}
@@ -0,0 +1,134 @@
+package promql
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/values"
+)
+
+func TestTranspile(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	testCases := []struct {
+		name    string
+		args    map[string]values.Value
+		want    string
+		wantErr string
+	}{
+		{
+			name: "instant query",
+			args: map[string]values.Value{
+				"expr":   values.NewString(`up{job="api"}`),
+				"bucket": values.NewString("prometheus"),
+				"start":  values.NewTime(values.ConvertTime(start)),
+				"end":    values.NewTime(values.ConvertTime(end)),
+				"host":   values.NewString("http://localhost:8086"),
+			},
+			want: `import "math"
+import "internal/promql"
+import "influxdata/influxdb"
+
+influxdb.from(bucket: "prometheus", host: "http://localhost:8086")
+	|> range(start: 2020-01-01T00:55:00Z, stop: 2020-01-01T01:00:00Z)
+	|> filter(fn: (r) =>
+		(r.job == "api" and r._field == "up"))
+	|> last()
+	|> timeShift(duration: 0ns)
+	|> drop(columns: ["_measurement"])
+	|> duplicate(as: "_time", column: "_stop")`,
+		},
+		{
+			name: "range query",
+			args: map[string]values.Value{
+				"expr":   values.NewString(`sum(up)`),
+				"bucket": values.NewString("prometheus"),
+				"start":  values.NewTime(values.ConvertTime(start)),
+				"end":    values.NewTime(values.ConvertTime(end)),
+				"step":   values.NewDuration(values.ConvertDurationNsecs(time.Minute)),
+			},
+			want: `import "math"
+import "internal/promql"
+import "influxdata/influxdb"
+
+influxdb.from(bucket: "prometheus")
+	|> range(start: 2019-12-31T23:55:00Z, stop: 2020-01-01T01:00:00Z)
+	|> filter(fn: (r) =>
+		(r._field == "up"))
+	|> window(every: 60000000000ns, offset: 0ns, period: 5m)
+	|> filter(fn: (r) =>
+		(r._stop >= 2020-01-01T00:00:00Z and r._start <= 2020-01-01T00:55:00Z))
+	|> last()
+	|> timeShift(duration: 0ns)
+	|> drop(columns: ["_measurement"])
+	|> group(columns: ["_start", "_stop"], mode: "by")
+	|> sum()
+	|> drop(columns: ["_field", "_time"])
+	|> duplicate(as: "_time", column: "_stop")`,
+		},
+		{
+			name: "invalid expression",
+			args: map[string]values.Value{
+				"expr":   values.NewString(`sum(`),
+				"bucket": values.NewString("prometheus"),
+				"start":  values.NewTime(values.ConvertTime(start)),
+				"end":    values.NewTime(values.ConvertTime(end)),
+			},
+			wantErr: "invalid promql expression",
+		},
+		{
+			name: "start after end",
+			args: map[string]values.Value{
+				"expr":   values.NewString(`up`),
+				"bucket": values.NewString("prometheus"),
+				"start":  values.NewTime(values.ConvertTime(end)),
+				"end":    values.NewTime(values.ConvertTime(start)),
+			},
+			wantErr: "start must not be after end",
+		},
+		{
+			name: "negative step",
+			args: map[string]values.Value{
+				"expr":   values.NewString(`up`),
+				"bucket": values.NewString("prometheus"),
+				"start":  values.NewTime(values.ConvertTime(start)),
+				"end":    values.NewTime(values.ConvertTime(end)),
+				"step":   values.NewDuration(values.ConvertDurationNsecs(-time.Minute)),
+			},
+			wantErr: "step must not be negative",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			args := interpreter.NewArguments(values.NewObjectWithValues(tc.args))
+			tr, expr, err := readTranspiler(args)
+			var pkg *ast.Package
+			if err == nil {
+				pkg, err = transpile(tr, expr)
+			}
+			if tc.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q", tc.wantErr)
+				}
+				if !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("unexpected error, want: %q got: %v", tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want, got := "main", pkg.Package; want != got {
+				t.Errorf("unexpected package want: %q got: %q", want, got)
+			}
+			if got := ast.Format(pkg.Files[0]); tc.want != got {
+				t.Errorf("unexpected transpiled file -want/+got\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
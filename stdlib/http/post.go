@@ -10,6 +10,7 @@ import (
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
+	fluxurl "github.com/influxdata/flux/dependencies/url"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/iocounter"
 	"github.com/influxdata/flux/runtime"
@@ -38,7 +39,7 @@ func init() {
 			if err != nil {
 				return nil, err
 			}
-			if err := validator.Validate(u); err != nil {
+			if err := fluxurl.ForFunction(validator, "http.post").Validate(u); err != nil {
 				return nil, err
 			}
 
@@ -82,7 +83,7 @@ func init() {
 				s.SetTag("url", req.URL.String())
 				defer s.Finish()
 
-				req = req.WithContext(cctx)
+				req = req.WithContext(fluxurl.WithFunction(cctx, "http.post"))
 				response, err := dc.Do(req)
 				if err != nil {
 					return 0, err
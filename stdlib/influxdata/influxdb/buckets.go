@@ -139,7 +139,7 @@ func (s *BucketsRemoteProcedureSpec) PostPhysicalValidate(id plan.NodeID) error
 
 func createBucketsSource(ps plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
 	spec := ps.(*BucketsRemoteProcedureSpec)
-	return CreateSource(id, spec, a)
+	return CreateSourceForFunction(id, spec, a, "influxdb.buckets")
 }
 
 func (s *BucketsRemoteProcedureSpec) BuildQuery() *ast.File {
@@ -157,3 +157,12 @@ func TestBuckets_HTTPClient(t *testing.T) {
 		},
 	})
 }
+
+func TestBuckets_FunctionURLValidator(t *testing.T) {
+	testutil.RunSourceFunctionURLValidatorTestHelper(t, &influxdb.BucketsRemoteProcedureSpec{
+		BucketsProcedureSpec: &influxdb.BucketsProcedureSpec{
+			Org:   &influxdb.NameOrID{Name: "influxdata"},
+			Token: stringPtr("mytoken"),
+		},
+	}, "influxdb.buckets")
+}
@@ -5,6 +5,8 @@
 package influxdb
 
 import (
+	"context"
+
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/dependencies/influxdb"
@@ -12,6 +14,7 @@ import (
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/stdlib/universe"
 	"github.com/influxdata/flux/values"
 )
 
@@ -54,6 +57,8 @@ func init() {
 		FromRemoteRule{},
 		MergeRemoteRangeRule{},
 		MergeRemoteFilterRule{},
+		MergeRemoteWindowAggregateRule{},
+		MergeRemoteGroupAggregateRule{},
 	)
 }
 
@@ -116,8 +121,6 @@ func (s *FromOpSpec) Kind() flux.OperationKind {
 var _ ProcedureSpec = (*FromProcedureSpec)(nil)
 
 type FromProcedureSpec struct {
-	plan.DefaultCost
-
 	Org    *NameOrID
 	Bucket NameOrID
 	Host   *string
@@ -148,6 +151,13 @@ func (s *FromProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// Cost reads the default amount of data from storage
+// since nothing is known about the bucket.
+func (s *FromProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	out := plan.DefaultSourceStatistics
+	return plan.Cost{NET: out.Cardinality}, out
+}
+
 func (s *FromProcedureSpec) SetOrg(org *NameOrID)   { s.Org = org }
 func (s *FromProcedureSpec) SetHost(host *string)   { s.Host = host }
 func (s *FromProcedureSpec) SetToken(token *string) { s.Token = token }
@@ -169,10 +179,16 @@ func (s *FromProcedureSpec) PostPhysicalValidate(id plan.NodeID) error {
 }
 
 type FromRemoteProcedureSpec struct {
-	plan.DefaultCost
 	influxdb.Config
 	Bounds       flux.Bounds
 	PredicateSet influxdb.PredicateSet
+
+	// Aggregate is applied by the remote instance after
+	// the series are split by the Window or combined by the Group.
+	// At most one of Window or Group is set with an Aggregate.
+	Aggregate influxdb.Aggregate
+	Window    *influxdb.Window
+	Group     *influxdb.Group
 }
 
 func (s *FromRemoteProcedureSpec) Kind() plan.ProcedureKind {
@@ -198,9 +214,65 @@ func (s *FromRemoteProcedureSpec) Copy() plan.ProcedureSpec {
 	ns := new(FromRemoteProcedureSpec)
 	*ns = *s
 	ns.PredicateSet = s.PredicateSet.Copy()
+	if s.Window != nil {
+		window := *s.Window
+		ns.Window = &window
+	}
+	if s.Group != nil {
+		group := s.Group.Copy()
+		ns.Group = &group
+	}
 	return ns
 }
 
+// Cost assumes that the bounds and each predicate that was pushed
+// into the remote query reduce the data transferred over the network
+// by the same amount as the equivalent range and filter.
+// An aggregate that was pushed into the remote query transfers
+// a single row for each group, or for each window of each series.
+func (s *FromRemoteProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	out := plan.DefaultSourceStatistics
+	if !s.Bounds.IsEmpty() {
+		out = out.Scale(plan.DefaultRangeSelectivity)
+	}
+	// The remote instance evaluates each predicate for the rows
+	// that pass the predicates before it.
+	var cpu int64
+	for _, p := range s.PredicateSet {
+		cpu += out.Cardinality
+		if !universe.IsTrivialPredicate(p.Fn) {
+			out = out.Scale(plan.DefaultFilterSelectivity)
+		}
+	}
+	if s.Aggregate != "" {
+		if s.Window != nil {
+			if n := s.windowCount(); n > 0 && out.GroupCardinality*n < out.Cardinality {
+				out.GroupCardinality *= n
+				out.Cardinality = out.GroupCardinality
+			}
+		} else {
+			out.Cardinality = out.GroupCardinality
+		}
+	}
+	return plan.Cost{CPU: cpu, NET: out.Cardinality}, out
+}
+
+// windowCount returns the number of windows within the bounds
+// or zero if the number of windows cannot be determined.
+func (s *FromRemoteProcedureSpec) windowCount() int64 {
+	every := s.Window.Every
+	if s.Bounds.IsEmpty() || !every.NanoOnly() || !every.IsPositive() {
+		return 0
+	}
+	start := s.Bounds.Start.Time(s.Bounds.Now)
+	stop := s.Bounds.Stop.Time(s.Bounds.Now)
+	d := stop.Sub(start).Nanoseconds()
+	if d <= 0 {
+		return 0
+	}
+	return (d + every.Nanoseconds() - 1) / every.Nanoseconds()
+}
+
 func (s *FromRemoteProcedureSpec) PostPhysicalValidate(id plan.NodeID) error {
 	if s.Bounds.IsEmpty() {
 		var bucket string
@@ -220,8 +292,7 @@ func createFromSource(ps plan.ProcedureSpec, id execute.DatasetID, a execute.Adm
 		return nil, errors.Newf(codes.Invalid, "bounds must be set")
 	}
 
-	provider := influxdb.GetProvider(a.Context())
-	reader, err := provider.ReaderFor(a.Context(), spec.Config, spec.Bounds, spec.PredicateSet)
+	reader, err := spec.readerFor(a.Context())
 	if err != nil {
 		return nil, err
 	}
@@ -232,3 +303,19 @@ func createFromSource(ps plan.ProcedureSpec, id execute.DatasetID, a execute.Adm
 	}
 	return execute.CreateSourceFromIterator(itr, id)
 }
+
+// readerFor returns the Reader from the Provider that
+// performs every operation that was pushed into the spec.
+func (s *FromRemoteProcedureSpec) readerFor(ctx context.Context) (influxdb.Reader, error) {
+	if s.Aggregate == "" || (s.Window == nil && s.Group == nil) {
+		return influxdb.GetProvider(ctx).ReaderFor(ctx, s.Config, s.Bounds, s.PredicateSet)
+	}
+	provider, err := influxdb.GetAggregateProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.Window != nil {
+		return provider.WindowAggregateReaderFor(ctx, s.Config, s.Bounds, s.PredicateSet, *s.Window, s.Aggregate)
+	}
+	return provider.GroupAggregateReaderFor(ctx, s.Config, s.Bounds, s.PredicateSet, *s.Group, s.Aggregate)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/influxdata/flux"
+	influxdeps "github.com/influxdata/flux/dependencies/influxdb"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
 	"github.com/influxdata/flux/interpreter"
@@ -387,6 +388,89 @@ from(bucket: "telegraf")
 				Tables: defaultTablesFn,
 			},
 		},
+		{
+			name: "window aggregate",
+			spec: &influxdb.FromRemoteProcedureSpec{
+				Config: influxdb.Config{
+					Org:    influxdb.NameOrID{Name: "influxdata"},
+					Bucket: influxdb.NameOrID{Name: "telegraf"},
+					Token:  "mytoken",
+				},
+				Bounds: flux.Bounds{
+					Start: flux.Time{
+						IsRelative: true,
+						Relative:   -time.Minute,
+					},
+					Stop: flux.Time{
+						IsRelative: true,
+					},
+					Now: now,
+				},
+				Aggregate: influxdeps.MeanAggregate,
+				Window: &influxdeps.Window{
+					Every:       flux.ConvertDuration(10 * time.Second),
+					Period:      flux.ConvertDuration(20 * time.Second),
+					Offset:      flux.ConvertDuration(-5 * time.Second),
+					CreateEmpty: true,
+				},
+			},
+			want: testutil.Want{
+				Params: url.Values{
+					"org": []string{"influxdata"},
+				},
+				Query: `package main
+
+
+from(bucket: "telegraf")
+	|> range(start: 2020-10-22T09:29:00Z, stop: 2020-10-22T09:30:00Z)
+	|> window(
+		every: 10s,
+		period: 20s,
+		offset: -5s,
+		createEmpty: true,
+	)
+	|> mean()`,
+				Tables: defaultTablesFn,
+			},
+		},
+		{
+			name: "group aggregate",
+			spec: &influxdb.FromRemoteProcedureSpec{
+				Config: influxdb.Config{
+					Org:    influxdb.NameOrID{Name: "influxdata"},
+					Bucket: influxdb.NameOrID{Name: "telegraf"},
+					Token:  "mytoken",
+				},
+				Bounds: flux.Bounds{
+					Start: flux.Time{
+						IsRelative: true,
+						Relative:   -time.Minute,
+					},
+					Stop: flux.Time{
+						IsRelative: true,
+					},
+					Now: now,
+				},
+				Aggregate: influxdeps.MaxAggregate,
+				Group: &influxdeps.Group{
+					Mode:    flux.GroupModeBy,
+					Columns: []string{"_measurement", "_field"},
+				},
+			},
+			want: testutil.Want{
+				Params: url.Values{
+					"org": []string{"influxdata"},
+				},
+				Query: `package main
+
+
+from(bucket: "telegraf")
+	|> range(start: 2020-10-22T09:29:00Z, stop: 2020-10-22T09:30:00Z)
+	|> group(columns: ["_measurement", "_field"], mode: "by")
+	|> max()`,
+				Tables: defaultTablesFn,
+			},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			testutil.RunSourceTestHelper(t, tt.spec, tt.want)
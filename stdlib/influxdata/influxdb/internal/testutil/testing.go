@@ -3,6 +3,7 @@ package testutil
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"math"
 	"net/http"
@@ -256,6 +257,58 @@ func RunSourceURLValidatorTestHelper(t *testing.T, spec plan.PhysicalProcedureSp
 	ExecuteSourceTestHelper(t, ctx, spec, want)
 }
 
+// RunSourceFunctionURLValidatorTestHelper checks that the host of the source
+// is validated with the rules of the URL validator for the named function.
+func RunSourceFunctionURLValidatorTestHelper(t *testing.T, spec plan.PhysicalProcedureSpec, function string) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("received unexpected request")
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator, err := urldeps.NewPolicyValidator(urldeps.Policy{
+		Functions: map[string]urldeps.Rules{
+			function: {DenyHosts: []string{u.Hostname()}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Want{
+		Err: &flux.Error{
+			Msg: "failed to initialize execute state",
+			Err: &flux.Error{
+				Code: codes.Invalid,
+				Msg:  fmt.Sprintf("url is not valid, host %q is denied", u.Hostname()),
+			},
+		},
+	}
+
+	if ps, ok := spec.(influxdb.ProcedureSpec); ok {
+		ps.SetHost(&server.URL)
+	}
+
+	provider := influxdeps.Dependency{
+		Provider: influxdeps.HttpProvider{
+			DefaultConfig: influxdeps.Config{
+				Host: server.URL,
+			},
+		},
+	}
+
+	deps := flux.NewDefaultDependencies()
+	deps.Deps.URLValidator = validator
+	ctx := deps.Inject(context.Background())
+	ctx = provider.Inject(ctx)
+	ExecuteSourceTestHelper(t, ctx, spec, want)
+}
+
 func RunSourceHTTPClientTestHelper(t *testing.T, spec plan.PhysicalProcedureSpec) {
 	t.Helper()
 
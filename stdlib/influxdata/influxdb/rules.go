@@ -2,8 +2,11 @@ package influxdb
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/influxdata/flux/dependencies/influxdb"
+	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/stdlib/universe"
 )
@@ -83,7 +86,10 @@ func (p MergeRemoteFilterRule) Rewrite(ctx context.Context, node plan.Node) (pla
 	fromNode := node.Predecessors()[0]
 	fromSpec := fromNode.ProcedureSpec().(*FromRemoteProcedureSpec)
 	if fromSpec.Bounds.IsEmpty() {
-		return node, false, nil
+		return plan.Decline(ctx, node, "%s is not bounded by a range", fromNode.ID())
+	} else if fromSpec.Aggregate != "" {
+		// A filter cannot be pushed past an aggregate.
+		return plan.Decline(ctx, node, "%s applies the %s aggregate before the filter", fromNode.ID(), fromSpec.Aggregate)
 	}
 	filterSpec := node.ProcedureSpec().(*universe.FilterProcedureSpec)
 
@@ -101,7 +107,7 @@ func (p MergeRemoteFilterRule) Rewrite(ctx context.Context, node plan.Node) (pla
 		// a predicate and this is done in influxdb. Update this section
 		// to also try and split the predicate into multiple sets
 		// so we can partially push down a filter.
-		return node, false, nil
+		return plan.Decline(ctx, node, "the remote instance cannot apply the predicate: %v", err)
 	}
 
 	n, err := plan.MergeToPhysicalNode(node, fromNode, fromSpec)
@@ -111,6 +117,170 @@ func (p MergeRemoteFilterRule) Rewrite(ctx context.Context, node plan.Node) (pla
 	return n, true, nil
 }
 
+// remoteAggregateKinds are the procedure kinds
+// that may be applied by the remote instance.
+var remoteAggregateKinds = []plan.ProcedureKind{
+	universe.MeanKind,
+	universe.SumKind,
+	universe.CountKind,
+	universe.MinKind,
+	universe.MaxKind,
+	universe.FirstKind,
+	universe.LastKind,
+}
+
+// remoteAggregate returns the aggregate that the remote instance applies
+// for the procedure spec. Only aggregates of the _value column can be
+// applied remotely.
+func remoteAggregate(spec plan.ProcedureSpec) (influxdb.Aggregate, bool) {
+	var (
+		aggregate influxdb.Aggregate
+		columns   []string
+	)
+	switch spec := spec.(type) {
+	case *universe.MeanProcedureSpec:
+		aggregate, columns = influxdb.MeanAggregate, spec.Columns
+	case *universe.SumProcedureSpec:
+		aggregate, columns = influxdb.SumAggregate, spec.Columns
+	case *universe.CountProcedureSpec:
+		aggregate, columns = influxdb.CountAggregate, spec.Columns
+	case *universe.MinProcedureSpec:
+		aggregate, columns = influxdb.MinAggregate, []string{spec.Column}
+	case *universe.MaxProcedureSpec:
+		aggregate, columns = influxdb.MaxAggregate, []string{spec.Column}
+	case *universe.FirstProcedureSpec:
+		aggregate, columns = influxdb.FirstAggregate, []string{spec.Column}
+	case *universe.LastProcedureSpec:
+		aggregate, columns = influxdb.LastAggregate, []string{spec.Column}
+	default:
+		return "", false
+	}
+	if len(columns) != 1 || columns[0] != execute.DefaultValueColLabel {
+		return "", false
+	}
+	return aggregate, true
+}
+
+// whyNotMergeRemoteAggregate returns the reason that the aggregate node and
+// the node between it and the remote from node cannot be merged into the
+// remote from node, or an empty string if they can be merged.
+func whyNotMergeRemoteAggregate(fromNode, midNode plan.Node) string {
+	for _, n := range []plan.Node{fromNode, midNode} {
+		if len(n.Successors()) != 1 {
+			return fmt.Sprintf("%s is read by more than one node", n.ID())
+		}
+	}
+	fromSpec := fromNode.ProcedureSpec().(*FromRemoteProcedureSpec)
+	if fromSpec.Bounds.IsEmpty() {
+		return fmt.Sprintf("%s is not bounded by a range", fromNode.ID())
+	} else if fromSpec.Aggregate != "" {
+		return fmt.Sprintf("%s already applies the %s aggregate", fromNode.ID(), fromSpec.Aggregate)
+	}
+	return ""
+}
+
+// mergeRemoteAggregate creates the node that replaces the remote from node,
+// the node in the middle, and the aggregate node.
+func mergeRemoteAggregate(fromNode, midNode, aggNode plan.Node, spec *FromRemoteProcedureSpec) plan.Node {
+	trim := func(id plan.NodeID) string {
+		return strings.TrimPrefix(string(id), "merged_")
+	}
+	id := plan.NodeID("merged_" + trim(fromNode.ID()) + "_" + trim(midNode.ID()) + "_" + trim(aggNode.ID()))
+	return plan.CreatePhysicalNode(id, spec)
+}
+
+// MergeRemoteWindowAggregateRule pushes a window and the aggregate
+// that follows it into the remote from so that the remote instance
+// only returns the aggregate of each window.
+type MergeRemoteWindowAggregateRule struct{}
+
+func (p MergeRemoteWindowAggregateRule) Name() string {
+	return "influxdata/influxdb.MergeRemoteWindowAggregateRule"
+}
+
+func (p MergeRemoteWindowAggregateRule) Pattern() plan.Pattern {
+	return plan.OneOf(remoteAggregateKinds, plan.Pat(universe.WindowKind, plan.Pat(FromRemoteKind)))
+}
+
+func (p MergeRemoteWindowAggregateRule) Rewrite(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+	windowNode := node.Predecessors()[0]
+	fromNode := windowNode.Predecessors()[0]
+	if reason := whyNotMergeRemoteAggregate(fromNode, windowNode); reason != "" {
+		return plan.Decline(ctx, node, "%s", reason)
+	}
+	aggregate, ok := remoteAggregate(node.ProcedureSpec())
+	if !ok {
+		return plan.Decline(ctx, node, "only aggregates of the %s column are applied remotely", execute.DefaultValueColLabel)
+	}
+
+	windowSpec := windowNode.ProcedureSpec().(*universe.WindowProcedureSpec)
+	if windowSpec.TimeColumn != execute.DefaultTimeColLabel ||
+		windowSpec.StartColumn != execute.DefaultStartColLabel ||
+		windowSpec.StopColumn != execute.DefaultStopColLabel {
+		return plan.Decline(ctx, node, "%s does not use the default time columns", windowNode.ID())
+	}
+
+	fromSpec := fromNode.ProcedureSpec().Copy().(*FromRemoteProcedureSpec)
+	fromSpec.Aggregate = aggregate
+	fromSpec.Window = &influxdb.Window{
+		Every:       windowSpec.Window.Every,
+		Period:      windowSpec.Window.Period,
+		Offset:      windowSpec.Window.Offset,
+		CreateEmpty: windowSpec.CreateEmpty,
+	}
+
+	provider, err := influxdb.GetAggregateProvider(ctx)
+	if err != nil {
+		return plan.Decline(ctx, node, "the remote instance cannot apply aggregates: %v", err)
+	}
+	if _, err := provider.WindowAggregateReaderFor(ctx, fromSpec.Config, fromSpec.Bounds, fromSpec.PredicateSet, *fromSpec.Window, fromSpec.Aggregate); err != nil {
+		return plan.Decline(ctx, node, "the remote instance cannot apply the aggregate: %v", err)
+	}
+	return mergeRemoteAggregate(fromNode, windowNode, node, fromSpec), true, nil
+}
+
+// MergeRemoteGroupAggregateRule pushes a group and the aggregate
+// that follows it into the remote from so that the remote instance
+// only returns the aggregate of each group.
+type MergeRemoteGroupAggregateRule struct{}
+
+func (p MergeRemoteGroupAggregateRule) Name() string {
+	return "influxdata/influxdb.MergeRemoteGroupAggregateRule"
+}
+
+func (p MergeRemoteGroupAggregateRule) Pattern() plan.Pattern {
+	return plan.OneOf(remoteAggregateKinds, plan.Pat(universe.GroupKind, plan.Pat(FromRemoteKind)))
+}
+
+func (p MergeRemoteGroupAggregateRule) Rewrite(ctx context.Context, node plan.Node) (plan.Node, bool, error) {
+	groupNode := node.Predecessors()[0]
+	fromNode := groupNode.Predecessors()[0]
+	if reason := whyNotMergeRemoteAggregate(fromNode, groupNode); reason != "" {
+		return plan.Decline(ctx, node, "%s", reason)
+	}
+	aggregate, ok := remoteAggregate(node.ProcedureSpec())
+	if !ok {
+		return plan.Decline(ctx, node, "only aggregates of the %s column are applied remotely", execute.DefaultValueColLabel)
+	}
+
+	groupSpec := groupNode.ProcedureSpec().(*universe.GroupProcedureSpec)
+	fromSpec := fromNode.ProcedureSpec().Copy().(*FromRemoteProcedureSpec)
+	fromSpec.Aggregate = aggregate
+	fromSpec.Group = &influxdb.Group{
+		Mode:    groupSpec.GroupMode,
+		Columns: append([]string(nil), groupSpec.GroupKeys...),
+	}
+
+	provider, err := influxdb.GetAggregateProvider(ctx)
+	if err != nil {
+		return plan.Decline(ctx, node, "the remote instance cannot apply aggregates: %v", err)
+	}
+	if _, err := provider.GroupAggregateReaderFor(ctx, fromSpec.Config, fromSpec.Bounds, fromSpec.PredicateSet, *fromSpec.Group, fromSpec.Aggregate); err != nil {
+		return plan.Decline(ctx, node, "the remote instance cannot apply the aggregate: %v", err)
+	}
+	return mergeRemoteAggregate(fromNode, groupNode, node, fromSpec), true, nil
+}
+
 type BucketsRemoteRule struct{}
 
 func (p BucketsRemoteRule) Name() string {
@@ -8,6 +8,7 @@ import (
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
 	influxdeps "github.com/influxdata/flux/dependencies/influxdb"
+	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/interpreter"
@@ -349,6 +350,347 @@ func TestMergeRemoteFilterRule(t *testing.T) {
 	plantest.PhysicalRuleTestHelper(t, &tc)
 }
 
+func TestMergeRemoteFilterRule_CostBased(t *testing.T) {
+	deps := flux.NewDefaultDependencies()
+	ctx := deps.Inject(context.Background())
+	ctx = influxdeps.Dependency{
+		Provider: influxdeps.HttpProvider{},
+	}.Inject(ctx)
+
+	fromSpec := influxdb.FromRemoteProcedureSpec{
+		Config: influxdb.Config{
+			Bucket: influxdb.NameOrID{Name: "telegraf"},
+			Host:   "http://localhost:9999",
+		},
+		Bounds: flux.Bounds{
+			Start: flux.Time{
+				IsRelative: true,
+				Relative:   -time.Minute,
+			},
+			Stop: flux.Time{
+				IsRelative: true,
+			},
+		},
+	}
+	filterSpec := universe.FilterProcedureSpec{
+		Fn: interpreter.ResolvedFunction{
+			Fn:    executetest.FunctionExpression(t, `(r) => true`),
+			Scope: valuestest.Scope(),
+		},
+	}
+	before := func() *plantest.PlanSpec {
+		return &plantest.PlanSpec{
+			Nodes: []plan.Node{
+				plan.CreatePhysicalNode("fromRemote", fromSpec.Copy().(*influxdb.FromRemoteProcedureSpec)),
+				plan.CreateLogicalNode("filter", &filterSpec),
+			},
+			Edges: [][2]int{
+				{0, 1},
+			},
+		}
+	}
+	// Both rules rewrite the filter. The heuristic planner applies
+	// the rule that comes first, which pushes a predicate that does
+	// not filter anything to the remote instance.
+	rules := []plan.Rule{
+		influxdb.MergeRemoteFilterRule{},
+		universe.RemoveTrivialFilterRule{},
+	}
+
+	tcs := []plantest.RuleTestCase{
+		{
+			Name:    "Heuristic",
+			Context: ctx,
+			Rules:   rules,
+			Before:  before(),
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("merged_fromRemote_filter", &influxdb.FromRemoteProcedureSpec{
+						Config: fromSpec.Config,
+						Bounds: fromSpec.Bounds,
+						PredicateSet: influxdb.PredicateSet{{
+							ResolvedFunction: filterSpec.Fn,
+						}},
+					}),
+				},
+			},
+		},
+		{
+			Name:    "CostBased",
+			Context: ctx,
+			Rules:   rules,
+			Options: []plan.PhysicalOption{plan.WithCostBasedPlanning()},
+			Before:  before(),
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("fromRemote", &fromSpec),
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
+
+func TestMergeRemoteWindowAggregateRule(t *testing.T) {
+	deps := flux.NewDefaultDependencies()
+	ctx := deps.Inject(context.Background())
+	ctx = influxdeps.Dependency{
+		Provider: influxdeps.HttpProvider{},
+	}.Inject(ctx)
+
+	fromSpec := influxdb.FromProcedureSpec{
+		Bucket: influxdb.NameOrID{Name: "telegraf"},
+		Host:   stringPtr("http://localhost:9999"),
+	}
+	rangeSpec := universe.RangeProcedureSpec{
+		Bounds: flux.Bounds{
+			Start: flux.Time{
+				IsRelative: true,
+				Relative:   -time.Hour,
+			},
+			Stop: flux.Time{
+				IsRelative: true,
+			},
+		},
+	}
+	windowSpec := universe.WindowProcedureSpec{
+		Window: plan.WindowSpec{
+			Every:  flux.ConvertDuration(time.Minute),
+			Period: flux.ConvertDuration(time.Minute),
+		},
+		TimeColumn:  "_time",
+		StartColumn: "_start",
+		StopColumn:  "_stop",
+	}
+	filterSpec := universe.FilterProcedureSpec{
+		Fn: interpreter.ResolvedFunction{
+			Fn:    executetest.FunctionExpression(t, `(r) => r._value > 0.0`),
+			Scope: valuestest.Scope(),
+		},
+	}
+	rules := []plan.Rule{
+		influxdb.FromRemoteRule{},
+		influxdb.MergeRemoteRangeRule{},
+		influxdb.MergeRemoteFilterRule{},
+		influxdb.MergeRemoteWindowAggregateRule{},
+	}
+
+	for _, tc := range []plantest.RuleTestCase{
+		{
+			Name:    "window mean",
+			Context: ctx,
+			Rules:   rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreateLogicalNode("from", &fromSpec),
+					plan.CreateLogicalNode("range", &rangeSpec),
+					plan.CreateLogicalNode("window", &windowSpec),
+					plan.CreateLogicalNode("mean", &universe.MeanProcedureSpec{
+						AggregateConfig: execute.DefaultAggregateConfig,
+					}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{2, 3},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("merged_fromRemote_range_window_mean", &influxdb.FromRemoteProcedureSpec{
+						Config: influxdb.Config{
+							Bucket: fromSpec.Bucket,
+							Host:   *fromSpec.Host,
+						},
+						Bounds:    rangeSpec.Bounds,
+						Aggregate: influxdeps.MeanAggregate,
+						Window: &influxdeps.Window{
+							Every:  windowSpec.Window.Every,
+							Period: windowSpec.Window.Period,
+						},
+					}),
+				},
+			},
+		},
+		{
+			Name:    "filter after window last",
+			Context: ctx,
+			Rules:   rules,
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreateLogicalNode("from", &fromSpec),
+					plan.CreateLogicalNode("range", &rangeSpec),
+					plan.CreateLogicalNode("window", &windowSpec),
+					plan.CreateLogicalNode("last", &universe.LastProcedureSpec{
+						SelectorConfig: execute.DefaultSelectorConfig,
+					}),
+					plan.CreateLogicalNode("filter", &filterSpec),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{2, 3},
+					{3, 4},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("merged_fromRemote_range_window_last", &influxdb.FromRemoteProcedureSpec{
+						Config: influxdb.Config{
+							Bucket: fromSpec.Bucket,
+							Host:   *fromSpec.Host,
+						},
+						Bounds:    rangeSpec.Bounds,
+						Aggregate: influxdeps.LastAggregate,
+						Window: &influxdeps.Window{
+							Every:  windowSpec.Window.Every,
+							Period: windowSpec.Window.Period,
+						},
+					}),
+					plan.CreatePhysicalNode("filter", &filterSpec),
+				},
+				Edges: [][2]int{
+					{0, 1},
+				},
+			},
+		},
+		{
+			Name:    "aggregate of another column",
+			Context: ctx,
+			Rules:   []plan.Rule{influxdb.MergeRemoteWindowAggregateRule{}},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("fromRemote", &influxdb.FromRemoteProcedureSpec{
+						Config: influxdb.Config{
+							Bucket: fromSpec.Bucket,
+							Host:   *fromSpec.Host,
+						},
+						Bounds: rangeSpec.Bounds,
+					}),
+					plan.CreatePhysicalNode("window", &windowSpec),
+					plan.CreatePhysicalNode("sum", &universe.SumProcedureSpec{
+						AggregateConfig: execute.AggregateConfig{
+							Columns: []string{"count"},
+						},
+					}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+				},
+			},
+			NoChange: true,
+		},
+		{
+			Name: "provider without aggregates",
+			Context: influxdeps.Dependency{
+				Provider: influxdeps.UnimplementedProvider{},
+			}.Inject(deps.Inject(context.Background())),
+			Rules: []plan.Rule{influxdb.MergeRemoteWindowAggregateRule{}},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("fromRemote", &influxdb.FromRemoteProcedureSpec{
+						Config: influxdb.Config{
+							Bucket: fromSpec.Bucket,
+							Host:   *fromSpec.Host,
+						},
+						Bounds: rangeSpec.Bounds,
+					}),
+					plan.CreatePhysicalNode("window", &windowSpec),
+					plan.CreatePhysicalNode("sum", &universe.SumProcedureSpec{
+						AggregateConfig: execute.DefaultAggregateConfig,
+					}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+				},
+			},
+			NoChange: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
+
+func TestMergeRemoteGroupAggregateRule(t *testing.T) {
+	deps := flux.NewDefaultDependencies()
+	ctx := deps.Inject(context.Background())
+	ctx = influxdeps.Dependency{
+		Provider: influxdeps.HttpProvider{},
+	}.Inject(ctx)
+
+	fromSpec := influxdb.FromProcedureSpec{
+		Bucket: influxdb.NameOrID{Name: "telegraf"},
+		Host:   stringPtr("http://localhost:9999"),
+	}
+	rangeSpec := universe.RangeProcedureSpec{
+		Bounds: flux.Bounds{
+			Start: flux.Time{
+				IsRelative: true,
+				Relative:   -time.Hour,
+			},
+			Stop: flux.Time{
+				IsRelative: true,
+			},
+		},
+	}
+	groupSpec := universe.GroupProcedureSpec{
+		GroupMode: flux.GroupModeBy,
+		GroupKeys: []string{"host"},
+	}
+
+	tc := plantest.RuleTestCase{
+		Name:    "MergeRemoteGroupAggregate",
+		Context: ctx,
+		Rules: []plan.Rule{
+			influxdb.FromRemoteRule{},
+			influxdb.MergeRemoteRangeRule{},
+			influxdb.MergeRemoteGroupAggregateRule{},
+		},
+		Before: &plantest.PlanSpec{
+			Nodes: []plan.Node{
+				plan.CreateLogicalNode("from", &fromSpec),
+				plan.CreateLogicalNode("range", &rangeSpec),
+				plan.CreateLogicalNode("group", &groupSpec),
+				plan.CreateLogicalNode("count", &universe.CountProcedureSpec{
+					AggregateConfig: execute.DefaultAggregateConfig,
+				}),
+			},
+			Edges: [][2]int{
+				{0, 1},
+				{1, 2},
+				{2, 3},
+			},
+		},
+		After: &plantest.PlanSpec{
+			Nodes: []plan.Node{
+				plan.CreatePhysicalNode("merged_fromRemote_range_group_count", &influxdb.FromRemoteProcedureSpec{
+					Config: influxdb.Config{
+						Bucket: fromSpec.Bucket,
+						Host:   *fromSpec.Host,
+					},
+					Bounds:    rangeSpec.Bounds,
+					Aggregate: influxdeps.CountAggregate,
+					Group: &influxdeps.Group{
+						Mode:    flux.GroupModeBy,
+						Columns: []string{"host"},
+					},
+				}),
+			},
+		},
+	}
+	plantest.PhysicalRuleTestHelper(t, &tc)
+}
+
 func TestDefaultFromAttributes(t *testing.T) {
 	for _, tc := range []plantest.RuleTestCase{
 		{
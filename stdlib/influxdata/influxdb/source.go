@@ -14,6 +14,7 @@ import (
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/csv"
 	"github.com/influxdata/flux/dependencies/influxdb"
+	fluxurl "github.com/influxdata/flux/dependencies/url"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/memory"
@@ -38,20 +39,32 @@ type RemoteProcedureSpec interface {
 
 type source struct {
 	execute.ExecutionNode
-	id   execute.DatasetID
-	spec RemoteProcedureSpec
-	deps flux.Dependencies
-	mem  *memory.Allocator
-	ts   execute.TransformationSet
+	id       execute.DatasetID
+	spec     RemoteProcedureSpec
+	function string
+	deps     flux.Dependencies
+	mem      *memory.Allocator
+	ts       execute.TransformationSet
 }
 
+// CreateSource creates a source that runs the query of the spec
+// on the remote host. The host is validated with the rules
+// of the URL validator that do not depend on a function.
 func CreateSource(id execute.DatasetID, spec RemoteProcedureSpec, a execute.Administration) (execute.Source, error) {
+	return CreateSourceForFunction(id, spec, a, "")
+}
+
+// CreateSourceForFunction creates a source like CreateSource,
+// but validates the host with the rules of the URL validator
+// for the named function, such as "influxdb.buckets".
+func CreateSourceForFunction(id execute.DatasetID, spec RemoteProcedureSpec, a execute.Administration, function string) (execute.Source, error) {
 	deps := flux.GetDependencies(a.Context())
 	s := &source{
-		id:   id,
-		spec: spec,
-		deps: deps,
-		mem:  a.Allocator(),
+		id:       id,
+		spec:     spec,
+		function: function,
+		deps:     deps,
+		mem:      a.Allocator(),
 	}
 
 	if err := s.validateHost(*spec.GetHost()); err != nil {
@@ -103,7 +116,7 @@ func (s *source) validateHost(host string) error {
 	if err != nil {
 		return err
 	}
-	return validator.Validate(u)
+	return fluxurl.ForFunction(validator, s.function).Validate(u)
 }
 
 func (s *source) newRequest(ctx context.Context) (*http.Request, error) {
@@ -130,7 +143,7 @@ func (s *source) newRequest(ctx context.Context) (*http.Request, error) {
 		return nil, err
 	}
 
-	if err := urlv.Validate(u); err != nil {
+	if err := fluxurl.ForFunction(urlv, s.function).Validate(u); err != nil {
 		return nil, err
 	}
 
@@ -147,7 +160,7 @@ func (s *source) newRequest(ctx context.Context) (*http.Request, error) {
 		req.Header.Set("Authorization", "Token "+*token)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	return req.WithContext(ctx), nil
+	return req.WithContext(fluxurl.WithFunction(ctx, s.function)), nil
 }
 
 func (s *source) newRequestBody() ([]byte, error) {
@@ -1,15 +1,425 @@
 package influxdb
 
 import (
+	"context"
+	"sort"
+	"time"
+
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/compiler"
+	"github.com/influxdata/flux/dependencies/influxdb"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+	protocol "github.com/influxdata/line-protocol"
 )
 
-// ToKind is the kind for the `to` flux function
-const ToKind = "to"
+const (
+	// ToKind is the kind for the `to` flux function
+	ToKind = "to"
+
+	// ToRemoteKind is the kind of the operation that writes
+	// to an influxdb instance using the influxdb dependency.
+	ToRemoteKind = "influxdata/influxdb.toRemote"
+
+	defaultMeasurementColumn = "_measurement"
+	defaultFieldColumn       = "_field"
+)
 
 var ToSignature = runtime.MustLookupBuiltinType("influxdata/influxdb", "to")
 
+// ToOpSpec writes tables to an influxdb instance.
+type ToOpSpec struct {
+	Config
+	TimeColumn        string
+	MeasurementColumn string
+	TagColumns        []string
+	FieldFn           interpreter.ResolvedFunction
+}
+
 func init() {
-	runtime.RegisterPackageValue("influxdata/influxdb", ToKind, flux.MustValue(flux.FunctionValueWithSideEffect(ToKind, nil, ToSignature)))
+	runtime.RegisterPackageValue("influxdata/influxdb", ToKind, flux.MustValue(flux.FunctionValueWithSideEffect(ToKind, createToOpSpec, ToSignature)))
+	flux.RegisterOpSpec(ToRemoteKind, func() flux.OperationSpec { return &ToOpSpec{} })
+	plan.RegisterProcedureSpecWithSideEffect(ToRemoteKind, newToProcedure, ToRemoteKind)
+	execute.RegisterTransformation(ToRemoteKind, createToTransformation)
+}
+
+func createToOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+	s := new(ToOpSpec)
+	if err := s.ReadArgs(args); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ReadArgs reads the arguments of `to` into the ToOpSpec.
+// The time and measurement columns default to _time and _measurement.
+// The tag columns are sorted.
+func (s *ToOpSpec) ReadArgs(args flux.Arguments) error {
+	if b, ok, err := GetNameOrID(args, "bucket", "bucketID"); err != nil {
+		return err
+	} else if !ok {
+		return errors.New(codes.Invalid, "one of bucket or bucketID is required")
+	} else {
+		s.Bucket = b
+	}
+
+	if o, ok, err := GetNameOrID(args, "org", "orgID"); err != nil {
+		return err
+	} else if ok {
+		s.Org = o
+	}
+
+	if h, ok, err := args.GetString("host"); err != nil {
+		return err
+	} else if ok {
+		s.Host = h
+	}
+
+	if token, ok, err := args.GetString("token"); err != nil {
+		return err
+	} else if ok {
+		s.Token = token
+	}
+
+	if col, ok, err := args.GetString("timeColumn"); err != nil {
+		return err
+	} else if ok {
+		s.TimeColumn = col
+	} else {
+		s.TimeColumn = execute.DefaultTimeColLabel
+	}
+
+	if col, ok, err := args.GetString("measurementColumn"); err != nil {
+		return err
+	} else if ok {
+		s.MeasurementColumn = col
+	} else {
+		s.MeasurementColumn = defaultMeasurementColumn
+	}
+
+	if tagColumns, ok, err := args.GetArray("tagColumns", semantic.String); err != nil {
+		return err
+	} else if ok {
+		s.TagColumns = make([]string, tagColumns.Len())
+		tagColumns.Range(func(i int, v values.Value) {
+			s.TagColumns[i] = v.Str()
+		})
+		sort.Strings(s.TagColumns)
+	}
+
+	if f, ok, err := args.GetFunction("fieldFn"); err != nil {
+		return err
+	} else if ok {
+		fn, err := interpreter.ResolveFunction(f)
+		if err != nil {
+			return err
+		}
+		s.FieldFn = fn
+	}
+	return nil
+}
+
+func (s *ToOpSpec) Kind() flux.OperationKind {
+	return ToRemoteKind
+}
+
+type ToProcedureSpec struct {
+	plan.DefaultCost
+	Spec *ToOpSpec
+}
+
+func newToProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*ToOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	return &ToProcedureSpec{Spec: spec}, nil
+}
+
+func (s *ToProcedureSpec) Kind() plan.ProcedureKind {
+	return ToRemoteKind
+}
+
+func (s *ToProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s.Spec
+	ns.TagColumns = append([]string(nil), s.Spec.TagColumns...)
+	ns.FieldFn = s.Spec.FieldFn.Copy()
+	return &ToProcedureSpec{Spec: &ns}
+}
+
+func createToTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*ToProcedureSpec)
+	if !ok {
+		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
+	}
+	return NewToTransformation(a.Context(), id, s)
+}
+
+// ToTransformation writes each table to influxdb
+// and passes the tables through unchanged.
+type ToTransformation struct {
+	execute.ExecutionNode
+	ctx    context.Context
+	d      *execute.PassthroughDataset
+	spec   *ToOpSpec
+	fn     *execute.RowMapFn
+	writer influxdb.Writer
+}
+
+// NewToTransformation creates a transformation that writes to the
+// influxdb instance of the Provider within the context.
+func NewToTransformation(ctx context.Context, id execute.DatasetID, spec *ToProcedureSpec) (*ToTransformation, execute.Dataset, error) {
+	provider, err := influxdb.GetWriterProvider(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	writer, err := provider.WriterFor(ctx, spec.Spec.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+	t := &ToTransformation{
+		ctx:    ctx,
+		d:      execute.NewPassthroughDataset(id),
+		spec:   spec.Spec,
+		writer: writer,
+	}
+	if fn := spec.Spec.FieldFn; fn.Fn != nil {
+		t.fn = execute.NewRowMapFn(fn.Fn, compiler.ToScope(fn.Scope))
+	}
+	return t, t.d, nil
+}
+
+func (t *ToTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *ToTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	buffer, err := execute.CopyTable(tbl)
+	if err != nil {
+		return err
+	}
+	if err := t.writeTable(buffer.Copy()); err != nil {
+		buffer.Done()
+		return err
+	}
+	return t.d.Process(buffer)
+}
+
+// writeTable converts each row of the table into a metric and writes it.
+//
+// The measurement is read from the measurement column and the timestamp from the time column.
+// Without a fieldFn, each row is a single field named by the _field column
+// with the value of the _value column. With a fieldFn, the properties of
+// the record returned for each row are the fields.
+// When no tag columns are specified, every other string column becomes a tag.
+// Null tags and fields are omitted and rows without any fields are skipped.
+func (t *ToTransformation) writeTable(tbl flux.Table) error {
+	cols := tbl.Cols()
+	measurementIdx := execute.ColIdx(t.spec.MeasurementColumn, cols)
+	if measurementIdx < 0 {
+		return errors.Newf(codes.FailedPrecondition, "table is missing the %q column", t.spec.MeasurementColumn)
+	} else if cols[measurementIdx].Type != flux.TString {
+		return errors.Newf(codes.FailedPrecondition, "column %q must be of type %s", t.spec.MeasurementColumn, flux.TString)
+	}
+	timeIdx := execute.ColIdx(t.spec.TimeColumn, cols)
+	if timeIdx < 0 {
+		return errors.Newf(codes.FailedPrecondition, "table is missing the %q column", t.spec.TimeColumn)
+	} else if cols[timeIdx].Type != flux.TTime {
+		return errors.Newf(codes.FailedPrecondition, "column %q must be of type %s", t.spec.TimeColumn, flux.TTime)
+	}
+
+	// The columns that hold field names or values are never tags.
+	notTags := map[string]bool{t.spec.MeasurementColumn: true}
+	fieldIdx, valueIdx := -1, -1
+	var fn *execute.RowMapPreparedFn
+	if t.fn == nil {
+		fieldIdx = execute.ColIdx(defaultFieldColumn, cols)
+		if fieldIdx < 0 {
+			return errors.Newf(codes.FailedPrecondition, "table is missing the %q column and no fieldFn was specified", defaultFieldColumn)
+		} else if cols[fieldIdx].Type != flux.TString {
+			return errors.Newf(codes.FailedPrecondition, "column %q must be of type %s", defaultFieldColumn, flux.TString)
+		}
+		valueIdx = execute.ColIdx(execute.DefaultValueColLabel, cols)
+		if valueIdx < 0 {
+			return errors.Newf(codes.FailedPrecondition, "table is missing the %q column and no fieldFn was specified", execute.DefaultValueColLabel)
+		}
+		notTags[defaultFieldColumn] = true
+		notTags[execute.DefaultValueColLabel] = true
+	} else {
+		var err error
+		if fn, err = t.fn.Prepare(cols); err != nil {
+			return err
+		}
+		n, err := fn.Type().NumProperties()
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			p, err := fn.Type().RecordProperty(i)
+			if err != nil {
+				return err
+			}
+			notTags[p.Name()] = true
+		}
+	}
+
+	var tagIdxs []int
+	if len(t.spec.TagColumns) > 0 {
+		for _, label := range t.spec.TagColumns {
+			j := execute.ColIdx(label, cols)
+			if j < 0 {
+				return errors.Newf(codes.FailedPrecondition, "table is missing the tag column %q", label)
+			} else if cols[j].Type != flux.TString {
+				return errors.Newf(codes.FailedPrecondition, "tag column %q must be of type %s", label, flux.TString)
+			}
+			tagIdxs = append(tagIdxs, j)
+		}
+	} else {
+		for j, c := range cols {
+			if c.Type == flux.TString && !notTags[c.Label] {
+				tagIdxs = append(tagIdxs, j)
+			}
+		}
+		sort.Slice(tagIdxs, func(i, j int) bool {
+			return cols[tagIdxs[i]].Label < cols[tagIdxs[j]].Label
+		})
+	}
+
+	m := &metric{}
+	return tbl.Do(func(cr flux.ColReader) error {
+		for i, l := 0, cr.Len(); i < l; i++ {
+			m.tags = m.tags[:0]
+			m.fields = m.fields[:0]
+
+			measurements := cr.Strings(measurementIdx)
+			if !measurements.IsValid(i) {
+				return errors.Newf(codes.FailedPrecondition, "null value in the %q column", t.spec.MeasurementColumn)
+			}
+			m.name = measurements.ValueString(i)
+
+			times := cr.Times(timeIdx)
+			if !times.IsValid(i) {
+				return errors.Newf(codes.FailedPrecondition, "null value in the %q column", t.spec.TimeColumn)
+			}
+			m.t = execute.Time(times.Value(i)).Time()
+
+			for _, j := range tagIdxs {
+				if vs := cr.Strings(j); vs.IsValid(i) {
+					m.tags = append(m.tags, &protocol.Tag{Key: cols[j].Label, Value: vs.ValueString(i)})
+				}
+			}
+
+			if fn == nil {
+				if fields := cr.Strings(fieldIdx); fields.IsValid(i) {
+					v := execute.ValueForRow(cr, i, valueIdx)
+					if !v.IsNull() {
+						fv, err := fieldValue(v)
+						if err != nil {
+							return errors.Wrapf(err, codes.FailedPrecondition, "invalid value for field %q", fields.ValueString(i))
+						}
+						m.fields = append(m.fields, &protocol.Field{Key: fields.ValueString(i), Value: fv})
+					}
+				}
+			} else {
+				obj, err := fn.Eval(t.ctx, i, cr)
+				if err != nil {
+					return err
+				}
+				obj.Range(func(k string, v values.Value) {
+					if err != nil || v.IsNull() {
+						return
+					}
+					var fv interface{}
+					if fv, err = fieldValue(v); err != nil {
+						err = errors.Wrapf(err, codes.FailedPrecondition, "invalid value for field %q", k)
+						return
+					}
+					m.fields = append(m.fields, &protocol.Field{Key: k, Value: fv})
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			if len(m.fields) == 0 {
+				continue
+			}
+			if err := t.writer.Write(m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// fieldValue returns the value as a type
+// supported by the line protocol encoder.
+func fieldValue(v values.Value) (interface{}, error) {
+	switch n := v.Type().Nature(); n {
+	case semantic.Float:
+		return v.Float(), nil
+	case semantic.Int:
+		return v.Int(), nil
+	case semantic.UInt:
+		return v.UInt(), nil
+	case semantic.String:
+		return v.Str(), nil
+	case semantic.Bool:
+		return v.Bool(), nil
+	default:
+		return nil, errors.Newf(codes.Invalid, "unsupported field type %v", n)
+	}
+}
+
+func (t *ToTransformation) UpdateWatermark(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateWatermark(pt)
+}
+
+func (t *ToTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+
+func (t *ToTransformation) Finish(id execute.DatasetID, err error) {
+	// The remaining metrics are only written when the query succeeded.
+	if err == nil {
+		err = t.writer.Flush()
+	}
+	if cerr := t.writer.Close(); err == nil {
+		err = cerr
+	}
+	t.d.Finish(err)
+}
+
+// metric is a single row of a table
+// encoded as a line protocol metric.
+type metric struct {
+	name   string
+	tags   []*protocol.Tag
+	fields []*protocol.Field
+	t      time.Time
+}
+
+func (m *metric) Name() string {
+	return m.name
+}
+
+func (m *metric) TagList() []*protocol.Tag {
+	return m.tags
+}
+
+func (m *metric) FieldList() []*protocol.Field {
+	return m.fields
+}
+
+func (m *metric) Time() time.Time {
+	return m.t
 }
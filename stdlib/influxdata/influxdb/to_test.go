@@ -0,0 +1,419 @@
+package influxdb_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/querytest"
+	"github.com/influxdata/flux/stdlib/influxdata/influxdb"
+	"github.com/influxdata/flux/values/valuestest"
+
+	influxdeps "github.com/influxdata/flux/dependencies/influxdb"
+)
+
+func TestTo_NewQuery(t *testing.T) {
+	tests := []querytest.NewQueryTestCase{
+		{
+			Name:    "to without bucket",
+			Raw:     `import "influxdata/influxdb" from(bucket: "mydb") |> influxdb.to(org: "influxdata")`,
+			WantErr: true,
+		},
+		{
+			Name:    "to with bucket and bucket id",
+			Raw:     `import "influxdata/influxdb" from(bucket: "mydb") |> influxdb.to(bucket: "a", bucketID: "1e01ac57da723035")`,
+			WantErr: true,
+		},
+		{
+			Name: "to with defaults",
+			Raw:  `import "influxdata/influxdb" from(bucket: "mydb") |> influxdb.to(bucket: "mybucket", org: "influxdata", host: "http://localhost:8086", token: "mytoken")`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "from0",
+						Spec: &influxdb.FromOpSpec{
+							Bucket: influxdb.NameOrID{Name: "mydb"},
+						},
+					},
+					{
+						ID: "to1",
+						Spec: &influxdb.ToOpSpec{
+							Config: influxdb.Config{
+								Org:    influxdb.NameOrID{Name: "influxdata"},
+								Bucket: influxdb.NameOrID{Name: "mybucket"},
+								Host:   "http://localhost:8086",
+								Token:  "mytoken",
+							},
+							TimeColumn:        "_time",
+							MeasurementColumn: "_measurement",
+						},
+					},
+				},
+				Edges: []flux.Edge{
+					{Parent: "from0", Child: "to1"},
+				},
+			},
+		},
+		{
+			Name: "to with ids and columns",
+			Raw:  `import "influxdata/influxdb" from(bucket: "mydb") |> influxdb.to(bucketID: "1e01ac57da723035", orgID: "97aa81cc0e247dc4", timeColumn: "time", measurementColumn: "name", tagColumns: ["region", "host"])`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "from0",
+						Spec: &influxdb.FromOpSpec{
+							Bucket: influxdb.NameOrID{Name: "mydb"},
+						},
+					},
+					{
+						ID: "to1",
+						Spec: &influxdb.ToOpSpec{
+							Config: influxdb.Config{
+								Org:    influxdb.NameOrID{ID: "97aa81cc0e247dc4"},
+								Bucket: influxdb.NameOrID{ID: "1e01ac57da723035"},
+							},
+							TimeColumn:        "time",
+							MeasurementColumn: "name",
+							TagColumns:        []string{"host", "region"},
+						},
+					},
+				},
+				Edges: []flux.Edge{
+					{Parent: "from0", Child: "to1"},
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			querytest.NewQueryTestHelper(t, tc)
+		})
+	}
+}
+
+func TestTo_Process(t *testing.T) {
+	testCases := []struct {
+		name       string
+		spec       *influxdb.ToOpSpec
+		data       []flux.Table
+		statusCode int
+		response   string
+		wantParams url.Values
+		wantBody   string
+		wantErr    error
+	}{
+		{
+			name: "default fields",
+			spec: &influxdb.ToOpSpec{
+				Config: influxdb.Config{
+					Org:    influxdb.NameOrID{Name: "influxdata"},
+					Bucket: influxdb.NameOrID{Name: "telegraf"},
+					Token:  "mytoken",
+				},
+				TimeColumn:        "_time",
+				MeasurementColumn: "_measurement",
+			},
+			data: []flux.Table{&executetest.Table{
+				KeyCols: []string{"_measurement", "_field", "host"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_start", Type: flux.TTime},
+					{Label: "_stop", Type: flux.TTime},
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "_field", Type: flux.TString},
+					{Label: "host", Type: flux.TString},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(0), execute.Time(100), execute.Time(11), "cpu", "usage", "a", 2.5},
+					{execute.Time(0), execute.Time(100), execute.Time(21), "cpu", "usage", "a", nil},
+					{execute.Time(0), execute.Time(100), execute.Time(31), "cpu", "usage", "a", 3.0},
+				},
+			}},
+			wantParams: url.Values{
+				"org":       []string{"influxdata"},
+				"bucket":    []string{"telegraf"},
+				"precision": []string{"ns"},
+			},
+			wantBody: "cpu,host=a usage=2.5 11\ncpu,host=a usage=3 31\n",
+		},
+		{
+			name: "tag columns",
+			spec: &influxdb.ToOpSpec{
+				Config: influxdb.Config{
+					Org:    influxdb.NameOrID{ID: "97aa81cc0e247dc4"},
+					Bucket: influxdb.NameOrID{ID: "1e01ac57da723035"},
+					Token:  "mytoken",
+				},
+				TimeColumn:        "time",
+				MeasurementColumn: "name",
+				TagColumns:        []string{"region"},
+			},
+			data: []flux.Table{&executetest.Table{
+				KeyCols: []string{"name", "_field"},
+				ColMeta: []flux.ColMeta{
+					{Label: "time", Type: flux.TTime},
+					{Label: "name", Type: flux.TString},
+					{Label: "_field", Type: flux.TString},
+					{Label: "host", Type: flux.TString},
+					{Label: "region", Type: flux.TString},
+					{Label: "_value", Type: flux.TInt},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), "mem", "free", "a", "west", int64(10)},
+					{execute.Time(2), "mem", "free", "b", nil, int64(20)},
+				},
+			}},
+			wantParams: url.Values{
+				"orgID":     []string{"97aa81cc0e247dc4"},
+				"bucketID":  []string{"1e01ac57da723035"},
+				"precision": []string{"ns"},
+			},
+			wantBody: "mem,region=west free=10i 1\nmem free=20i 2\n",
+		},
+		{
+			name: "field function",
+			spec: &influxdb.ToOpSpec{
+				Config: influxdb.Config{
+					Org:    influxdb.NameOrID{Name: "influxdata"},
+					Bucket: influxdb.NameOrID{Name: "telegraf"},
+					Token:  "mytoken",
+				},
+				TimeColumn:        "_time",
+				MeasurementColumn: "_measurement",
+				FieldFn: interpreter.ResolvedFunction{
+					Fn:    executetest.FunctionExpression(t, `(r) => ({usage: r.usage, idle: r.idle})`),
+					Scope: valuestest.Scope(),
+				},
+			},
+			data: []flux.Table{&executetest.Table{
+				KeyCols: []string{"_measurement", "host"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "host", Type: flux.TString},
+					{Label: "usage", Type: flux.TFloat},
+					{Label: "idle", Type: flux.TBool},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), "cpu", "a", 0.5, false},
+					{execute.Time(2), "cpu", "a", nil, true},
+				},
+			}},
+			wantParams: url.Values{
+				"org":       []string{"influxdata"},
+				"bucket":    []string{"telegraf"},
+				"precision": []string{"ns"},
+			},
+			wantBody: "cpu,host=a idle=false,usage=0.5 1\ncpu,host=a idle=true 2\n",
+		},
+		{
+			name: "missing field column",
+			spec: &influxdb.ToOpSpec{
+				Config: influxdb.Config{
+					Bucket: influxdb.NameOrID{Name: "telegraf"},
+				},
+				TimeColumn:        "_time",
+				MeasurementColumn: "_measurement",
+			},
+			data: []flux.Table{&executetest.Table{
+				KeyCols: []string{"_measurement"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), "cpu", 1.0},
+				},
+			}},
+			wantErr: errors.New(`table is missing the "_field" column and no fieldFn was specified`),
+		},
+		{
+			name: "write error",
+			spec: &influxdb.ToOpSpec{
+				Config: influxdb.Config{
+					Bucket: influxdb.NameOrID{Name: "telegraf"},
+				},
+				TimeColumn:        "_time",
+				MeasurementColumn: "_measurement",
+			},
+			data: []flux.Table{&executetest.Table{
+				KeyCols: []string{"_measurement", "_field"},
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_measurement", Type: flux.TString},
+					{Label: "_field", Type: flux.TString},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), "cpu", "usage", 1.0},
+				},
+			}},
+			statusCode: http.StatusNotFound,
+			response:   `{"code":"not found","message":"bucket \"telegraf\" not found"}`,
+			wantErr:    errors.New(`bucket "telegraf" not found`),
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				gotParams url.Values
+				gotBody   string
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if want, got := "/api/v2/write", r.URL.Path; want != got {
+					t.Errorf("unexpected write path -want/+got:\n- %q\n+ %q", want, got)
+				}
+				if want, got := "Token mytoken", r.Header.Get("Authorization"); want != got {
+					t.Errorf("unexpected authorization header -want/+got:\n- %q\n+ %q", want, got)
+				}
+				body, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("error reading request body: %s", err)
+				}
+				gotParams = r.URL.Query()
+				gotBody += string(body)
+				if tc.statusCode != 0 {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(tc.statusCode)
+					_, _ = w.Write([]byte(tc.response))
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			provider := influxdeps.Dependency{
+				Provider: influxdeps.HttpProvider{
+					DefaultConfig: influxdeps.Config{
+						Host:  server.URL,
+						Token: "mytoken",
+					},
+				},
+			}
+			ctx := flux.NewDefaultDependencies().Inject(context.Background())
+			ctx = provider.Inject(ctx)
+
+			want := make([]*executetest.Table, 0, len(tc.data))
+			if tc.wantErr == nil {
+				// Copy the tables before they are consumed.
+				for _, tbl := range tc.data {
+					cp := *tbl.(*executetest.Table)
+					want = append(want, &cp)
+				}
+			}
+			executetest.ProcessTestHelper2(
+				t,
+				tc.data,
+				want,
+				tc.wantErr,
+				func(id execute.DatasetID, alloc *memory.Allocator) (execute.Transformation, execute.Dataset) {
+					tr, d, err := influxdb.NewToTransformation(ctx, id, &influxdb.ToProcedureSpec{Spec: tc.spec})
+					if err != nil {
+						t.Fatal(err)
+					}
+					return tr, d
+				},
+			)
+			if tc.wantErr != nil {
+				return
+			}
+
+			if !cmp.Equal(tc.wantParams, gotParams) {
+				t.Errorf("unexpected write params -want/+got:\n%s", cmp.Diff(tc.wantParams, gotParams))
+			}
+			if !cmp.Equal(tc.wantBody, gotBody) {
+				t.Errorf("unexpected write body -want/+got:\n%s", cmp.Diff(tc.wantBody, gotBody))
+			}
+		})
+	}
+}
+
+func TestTo_FinishWithError(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	provider := influxdeps.Dependency{
+		Provider: influxdeps.HttpProvider{
+			DefaultConfig: influxdeps.Config{
+				Host:  server.URL,
+				Token: "mytoken",
+			},
+		},
+	}
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	ctx = provider.Inject(ctx)
+
+	spec := &influxdb.ToOpSpec{
+		Config: influxdb.Config{
+			Bucket: influxdb.NameOrID{Name: "telegraf"},
+		},
+		TimeColumn:        "_time",
+		MeasurementColumn: "_measurement",
+	}
+	tr, _, err := influxdb.NewToTransformation(ctx, executetest.RandomDatasetID(), &influxdb.ToProcedureSpec{Spec: spec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parentID := executetest.RandomDatasetID()
+	tbl := &executetest.Table{
+		KeyCols: []string{"_measurement", "_field"},
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_measurement", Type: flux.TString},
+			{Label: "_field", Type: flux.TString},
+			{Label: "_value", Type: flux.TFloat},
+		},
+		Data: [][]interface{}{
+			{execute.Time(1), "cpu", "usage", 1.0},
+		},
+	}
+	if err := tr.Process(parentID, tbl); err != nil {
+		t.Fatal(err)
+	}
+	tr.Finish(parentID, errors.New("expected error"))
+
+	if requests != 0 {
+		t.Errorf("expected no write after the query failed, got %d requests", requests)
+	}
+}
+
+func TestTo_WithoutWriter(t *testing.T) {
+	provider := influxdeps.Dependency{
+		Provider: influxdeps.UnimplementedProvider{},
+	}
+	ctx := flux.NewDefaultDependencies().Inject(context.Background())
+	ctx = provider.Inject(ctx)
+
+	spec := &influxdb.ToOpSpec{
+		Config: influxdb.Config{
+			Bucket: influxdb.NameOrID{Name: "telegraf"},
+		},
+	}
+	_, _, err := influxdb.NewToTransformation(ctx, executetest.RandomDatasetID(), &influxdb.ToProcedureSpec{Spec: spec})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if want, got := codes.Unimplemented, flux.ErrorCode(err); want != got {
+		t.Errorf("unexpected error code -want/+got:\n\t- %v\n\t+ %v", want, got)
+	}
+}
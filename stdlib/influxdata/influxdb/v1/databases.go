@@ -139,7 +139,7 @@ func (s *DatabasesRemoteProcedureSpec) PostPhysicalValidate(id plan.NodeID) erro
 
 func createDatabasesSource(ps plan.ProcedureSpec, id execute.DatasetID, a execute.Administration) (execute.Source, error) {
 	spec := ps.(*DatabasesRemoteProcedureSpec)
-	return influxdb.CreateSource(id, spec, a)
+	return influxdb.CreateSourceForFunction(id, spec, a, "v1.databases")
 }
 
 func (s *DatabasesRemoteProcedureSpec) BuildQuery() *ast.File {
@@ -21,11 +21,11 @@ var pkgAST = &ast.Package{
 			Errors: nil,
 			Loc: &ast.SourceLocation{
 				End: ast.Position{
-					Column: 11,
-					Line:   3,
+					Column: 13,
+					Line:   5,
 				},
 				File:   "kafka.flux",
-				Source: "package kafka\n\nbuiltin to",
+				Source: "package kafka\n\nbuiltin to : (<-tables: [A], brokers: [string], topic: string, ?balancer: string, ?name: string, ?nameColumn: string, ?timeColumn: string, ?tagColumns: [string], ?valueColumns: [string]) => [A] where A: Record\n\nbuiltin from",
 				Start: ast.Position{
 					Column: 1,
 					Line:   1,
@@ -904,6 +904,757 @@ var pkgAST = &ast.Package{
 					},
 				},
 			},
+		}, &ast.BuiltinStatement{
+			BaseNode: ast.BaseNode{
+				Errors: nil,
+				Loc: &ast.SourceLocation{
+					End: ast.Position{
+						Column: 13,
+						Line:   5,
+					},
+					File:   "kafka.flux",
+					Source: "builtin from",
+					Start: ast.Position{
+						Column: 1,
+						Line:   5,
+					},
+				},
+			},
+			ID: &ast.Identifier{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 13,
+							Line:   5,
+						},
+						File:   "kafka.flux",
+						Source: "from",
+						Start: ast.Position{
+							Column: 9,
+							Line:   5,
+						},
+					},
+				},
+				Name: "from",
+			},
+			Ty: ast.TypeExpression{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 175,
+							Line:   5,
+						},
+						File:   "kafka.flux",
+						Source: "(brokers: [string], topic: string, ?groupID: string, ?partition: int, ?startOffset: int, ?endOffset: int, ?stop: time, ?decoder: string) => [A] where A: Record",
+						Start: ast.Position{
+							Column: 16,
+							Line:   5,
+						},
+					},
+				},
+				Constraints: []*ast.TypeConstraint{&ast.TypeConstraint{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 175,
+								Line:   5,
+							},
+							File:   "kafka.flux",
+							Source: "A: Record",
+							Start: ast.Position{
+								Column: 166,
+								Line:   5,
+							},
+						},
+					},
+					Kinds: []*ast.Identifier{&ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 175,
+									Line:   5,
+								},
+								File:   "kafka.flux",
+								Source: "Record",
+								Start: ast.Position{
+									Column: 169,
+									Line:   5,
+								},
+							},
+						},
+						Name: "Record",
+					}},
+					Tvar: &ast.Identifier{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 167,
+									Line:   5,
+								},
+								File:   "kafka.flux",
+								Source: "A",
+								Start: ast.Position{
+									Column: 166,
+									Line:   5,
+								},
+							},
+						},
+						Name: "A",
+					},
+				}},
+				Ty: &ast.FunctionType{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 159,
+								Line:   5,
+							},
+							File:   "kafka.flux",
+							Source: "(brokers: [string], topic: string, ?groupID: string, ?partition: int, ?startOffset: int, ?endOffset: int, ?stop: time, ?decoder: string) => [A]",
+							Start: ast.Position{
+								Column: 16,
+								Line:   5,
+							},
+						},
+					},
+					Parameters: []*ast.ParameterType{&ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 34,
+									Line:   5,
+								},
+								File:   "kafka.flux",
+								Source: "brokers: [string]",
+								Start: ast.Position{
+									Column: 17,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 24,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "brokers",
+									Start: ast.Position{
+										Column: 17,
+										Line:   5,
+									},
+								},
+							},
+							Name: "brokers",
+						},
+						Ty: &ast.ArrayType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 34,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "[string]",
+									Start: ast.Position{
+										Column: 26,
+										Line:   5,
+									},
+								},
+							},
+							ElementType: &ast.NamedType{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 33,
+											Line:   5,
+										},
+										File:   "kafka.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 27,
+											Line:   5,
+										},
+									},
+								},
+								ID: &ast.Identifier{
+									BaseNode: ast.BaseNode{
+										Errors: nil,
+										Loc: &ast.SourceLocation{
+											End: ast.Position{
+												Column: 33,
+												Line:   5,
+											},
+											File:   "kafka.flux",
+											Source: "string",
+											Start: ast.Position{
+												Column: 27,
+												Line:   5,
+											},
+										},
+									},
+									Name: "string",
+								},
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 49,
+									Line:   5,
+								},
+								File:   "kafka.flux",
+								Source: "topic: string",
+								Start: ast.Position{
+									Column: 36,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 41,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "topic",
+									Start: ast.Position{
+										Column: 36,
+										Line:   5,
+									},
+								},
+							},
+							Name: "topic",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 49,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 43,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 49,
+											Line:   5,
+										},
+										File:   "kafka.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 43,
+											Line:   5,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 67,
+									Line:   5,
+								},
+								File:   "kafka.flux",
+								Source: "?groupID: string",
+								Start: ast.Position{
+									Column: 51,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 59,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "groupID",
+									Start: ast.Position{
+										Column: 52,
+										Line:   5,
+									},
+								},
+							},
+							Name: "groupID",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 67,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 61,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 67,
+											Line:   5,
+										},
+										File:   "kafka.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 61,
+											Line:   5,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 84,
+									Line:   5,
+								},
+								File:   "kafka.flux",
+								Source: "?partition: int",
+								Start: ast.Position{
+									Column: 69,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 79,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "partition",
+									Start: ast.Position{
+										Column: 70,
+										Line:   5,
+									},
+								},
+							},
+							Name: "partition",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 84,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "int",
+									Start: ast.Position{
+										Column: 81,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 84,
+											Line:   5,
+										},
+										File:   "kafka.flux",
+										Source: "int",
+										Start: ast.Position{
+											Column: 81,
+											Line:   5,
+										},
+									},
+								},
+								Name: "int",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 103,
+									Line:   5,
+								},
+								File:   "kafka.flux",
+								Source: "?startOffset: int",
+								Start: ast.Position{
+									Column: 86,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 98,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "startOffset",
+									Start: ast.Position{
+										Column: 87,
+										Line:   5,
+									},
+								},
+							},
+							Name: "startOffset",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 103,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "int",
+									Start: ast.Position{
+										Column: 100,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 103,
+											Line:   5,
+										},
+										File:   "kafka.flux",
+										Source: "int",
+										Start: ast.Position{
+											Column: 100,
+											Line:   5,
+										},
+									},
+								},
+								Name: "int",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 120,
+									Line:   5,
+								},
+								File:   "kafka.flux",
+								Source: "?endOffset: int",
+								Start: ast.Position{
+									Column: 105,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 115,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "endOffset",
+									Start: ast.Position{
+										Column: 106,
+										Line:   5,
+									},
+								},
+							},
+							Name: "endOffset",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 120,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "int",
+									Start: ast.Position{
+										Column: 117,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 120,
+											Line:   5,
+										},
+										File:   "kafka.flux",
+										Source: "int",
+										Start: ast.Position{
+											Column: 117,
+											Line:   5,
+										},
+									},
+								},
+								Name: "int",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 133,
+									Line:   5,
+								},
+								File:   "kafka.flux",
+								Source: "?stop: time",
+								Start: ast.Position{
+									Column: 122,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 127,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "stop",
+									Start: ast.Position{
+										Column: 123,
+										Line:   5,
+									},
+								},
+							},
+							Name: "stop",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 133,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "time",
+									Start: ast.Position{
+										Column: 129,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 133,
+											Line:   5,
+										},
+										File:   "kafka.flux",
+										Source: "time",
+										Start: ast.Position{
+											Column: 129,
+											Line:   5,
+										},
+									},
+								},
+								Name: "time",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 151,
+									Line:   5,
+								},
+								File:   "kafka.flux",
+								Source: "?decoder: string",
+								Start: ast.Position{
+									Column: 135,
+									Line:   5,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 143,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "decoder",
+									Start: ast.Position{
+										Column: 136,
+										Line:   5,
+									},
+								},
+							},
+							Name: "decoder",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 151,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 145,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 151,
+											Line:   5,
+										},
+										File:   "kafka.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 145,
+											Line:   5,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}},
+					Return: &ast.ArrayType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 159,
+									Line:   5,
+								},
+								File:   "kafka.flux",
+								Source: "[A]",
+								Start: ast.Position{
+									Column: 156,
+									Line:   5,
+								},
+							},
+						},
+						ElementType: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 158,
+										Line:   5,
+									},
+									File:   "kafka.flux",
+									Source: "A",
+									Start: ast.Position{
+										Column: 157,
+										Line:   5,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 158,
+											Line:   5,
+										},
+										File:   "kafka.flux",
+										Source: "A",
+										Start: ast.Position{
+											Column: 157,
+											Line:   5,
+										},
+									},
+								},
+								Name: "A",
+							},
+						},
+					},
+				},
+			},
 		}},
 		Imports:  nil,
 		Metadata: "parser-type=rust",
@@ -0,0 +1,442 @@
+package kafka
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	fluxurl "github.com/influxdata/flux/dependencies/url"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/line"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/stdlib/socket"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	// FromKafkaKind is the Kind for the FromKafka Flux function
+	FromKafkaKind = "fromKafka"
+
+	// FirstOffset starts reading a partition from its first available message.
+	FirstOffset = -1
+	// NoEndOffset reads a partition without an end offset.
+	NoEndOffset = -1
+
+	// defaultFromKafkaDecoder is used when kafka.from is called without a decoder.
+	defaultFromKafkaDecoder = "lineprotocol"
+)
+
+// FromKafkaOpSpec reads the messages of a topic and decodes their values into tables.
+type FromKafkaOpSpec struct {
+	Brokers     []string  `json:"brokers"`
+	Topic       string    `json:"topic"`
+	GroupID     string    `json:"groupID,omitempty"`
+	Partition   int       `json:"partition"`
+	StartOffset int64     `json:"startOffset"`
+	EndOffset   int64     `json:"endOffset"`
+	Stop        flux.Time `json:"stop"`
+	Decoder     string    `json:"decoder"`
+}
+
+func init() {
+	fromKafkaSignature := runtime.MustLookupBuiltinType("kafka", "from")
+	runtime.RegisterPackageValue("kafka", "from", flux.MustValue(flux.FunctionValue(FromKafkaKind, createFromKafkaOpSpec, fromKafkaSignature)))
+	flux.RegisterOpSpec(FromKafkaKind, func() flux.OperationSpec { return &FromKafkaOpSpec{} })
+	plan.RegisterProcedureSpec(FromKafkaKind, newFromKafkaProcedure, FromKafkaKind)
+	execute.RegisterSource(FromKafkaKind, createFromKafkaSource)
+}
+
+// DefaultKafkaReaderFactory makes the KafkaReader used by kafka.from and is injectable for testing
+var DefaultKafkaReaderFactory = func(conf kafka.ReaderConfig) KafkaReader {
+	return kafka.NewReader(conf)
+}
+
+// FetchTimeout bounds the wait for the next message of a group.
+// Reading with a group stops once no message arrives within it,
+// since the lag of the partitions of a group cannot be read.
+var FetchTimeout = 10 * time.Second
+
+// KafkaReader is an interface for what we need from DefaultKafkaReaderFactory
+type KafkaReader interface {
+	io.Closer
+	FetchMessage(context.Context) (kafka.Message, error)
+	CommitMessages(context.Context, ...kafka.Message) error
+	SetOffset(offset int64) error
+	ReadLag(context.Context) (int64, error)
+	Lag() int64
+}
+
+// ReadArgs loads a flux.Arguments into FromKafkaOpSpec.
+// Without a groupID, a single partition is read from the startOffset, which defaults to the first offset.
+// With a groupID, the committed offsets of the group are used and either an endOffset or a stop time is required.
+func (o *FromKafkaOpSpec) ReadArgs(args flux.Arguments) error {
+	brokers, err := args.GetRequiredArray("brokers", semantic.String)
+	if err != nil {
+		return err
+	}
+	if brokers.Len() < 1 {
+		return errors.New(codes.Invalid, "at least one broker is required")
+	}
+	o.Brokers = make([]string, brokers.Len())
+	for i := range o.Brokers {
+		o.Brokers[i] = brokers.Get(i).Str()
+	}
+
+	if o.Topic, err = args.GetRequiredString("topic"); err != nil {
+		return err
+	}
+	if len(o.Topic) == 0 {
+		return errors.New(codes.Invalid, "invalid topic name")
+	}
+
+	if o.GroupID, _, err = args.GetString("groupID"); err != nil {
+		return err
+	}
+
+	partition, hasPartition, err := args.GetInt("partition")
+	if err != nil {
+		return err
+	}
+	if partition < 0 {
+		return errors.Newf(codes.Invalid, "partition must be non-negative, got %d", partition)
+	}
+	o.Partition = int(partition)
+
+	o.StartOffset = FirstOffset
+	startOffset, hasStartOffset, err := args.GetInt("startOffset")
+	if err != nil {
+		return err
+	} else if hasStartOffset {
+		if startOffset < 0 {
+			return errors.Newf(codes.Invalid, "startOffset must be non-negative, got %d", startOffset)
+		}
+		o.StartOffset = startOffset
+	}
+
+	o.EndOffset = NoEndOffset
+	if endOffset, ok, err := args.GetInt("endOffset"); err != nil {
+		return err
+	} else if ok {
+		if endOffset < 0 {
+			return errors.Newf(codes.Invalid, "endOffset must be non-negative, got %d", endOffset)
+		}
+		if hasStartOffset && endOffset < startOffset {
+			return errors.Newf(codes.Invalid, "endOffset %d is before startOffset %d", endOffset, startOffset)
+		}
+		o.EndOffset = endOffset
+	}
+
+	if stop, ok, err := args.GetTime("stop"); err != nil {
+		return err
+	} else if ok {
+		o.Stop = stop
+	}
+
+	if o.GroupID != "" {
+		if hasPartition || hasStartOffset {
+			return errors.New(codes.Invalid, "partition and startOffset cannot be used with a groupID")
+		}
+		if o.EndOffset == NoEndOffset && o.Stop.IsZero() {
+			return errors.New(codes.Invalid, "reading with a groupID requires an endOffset or a stop time")
+		}
+	}
+
+	decoder, ok, err := args.GetString("decoder")
+	if err != nil {
+		return err
+	} else if !ok {
+		decoder = defaultFromKafkaDecoder
+	}
+	if _, ok := socket.LookupDecoder(decoder); !ok {
+		return errors.Newf(codes.Invalid, "invalid decoder %s, must be one of %v", decoder, socket.Decoders())
+	}
+	o.Decoder = decoder
+	return nil
+}
+
+func createFromKafkaOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	s := new(FromKafkaOpSpec)
+	if err := s.ReadArgs(args); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (FromKafkaOpSpec) Kind() flux.OperationKind {
+	return FromKafkaKind
+}
+
+type FromKafkaProcedureSpec struct {
+	plan.DefaultCost
+	Brokers     []string
+	Topic       string
+	GroupID     string
+	Partition   int
+	StartOffset int64
+	EndOffset   int64
+	// Stop is the time of the first message that is not read.
+	// It is zero when messages are read without a time bound.
+	Stop    time.Time
+	Decoder string
+}
+
+func newFromKafkaProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*FromKafkaOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+	ps := &FromKafkaProcedureSpec{
+		Brokers:     spec.Brokers,
+		Topic:       spec.Topic,
+		GroupID:     spec.GroupID,
+		Partition:   spec.Partition,
+		StartOffset: spec.StartOffset,
+		EndOffset:   spec.EndOffset,
+		Decoder:     spec.Decoder,
+	}
+	if !spec.Stop.IsZero() {
+		ps.Stop = spec.Stop.Time(pa.Now())
+	}
+	return ps, nil
+}
+
+func (s *FromKafkaProcedureSpec) Kind() plan.ProcedureKind {
+	return FromKafkaKind
+}
+
+func (s *FromKafkaProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	ns.Brokers = append([]string(nil), s.Brokers...)
+	return &ns
+}
+
+func createFromKafkaSource(ps plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := ps.(*FromKafkaProcedureSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", ps)
+	}
+
+	deps := flux.GetDependencies(a.Context())
+	validator, err := deps.URLValidator()
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range spec.Brokers {
+		u, err := url.Parse(b)
+		if err != nil {
+			return nil, errors.Newf(codes.Invalid, "invalid kafka broker url: %v", err)
+		}
+		if err := fluxurl.ForFunction(validator, "kafka.from").Validate(u); err != nil {
+			return nil, errors.Newf(codes.Invalid, "kafka broker url did not pass validation: %v", err)
+		}
+	}
+	return NewFromKafkaSource(spec, dsid, a.Allocator())
+}
+
+// NewFromKafkaSource creates a source that reads the messages of the topic
+// with a reader made by DefaultKafkaReaderFactory.
+func NewFromKafkaSource(spec *FromKafkaProcedureSpec, dsid execute.DatasetID, alloc *memory.Allocator) (execute.Source, error) {
+	newDecoder, ok := socket.LookupDecoder(spec.Decoder)
+	if !ok {
+		return nil, errors.Newf(codes.Invalid, "unknown decoder type: %v", spec.Decoder)
+	}
+
+	conf := kafka.ReaderConfig{
+		Brokers: spec.Brokers,
+		Topic:   spec.Topic,
+		GroupID: spec.GroupID,
+	}
+	if spec.GroupID == "" {
+		conf.Partition = spec.Partition
+	}
+	return &fromKafkaSource{
+		id:      dsid,
+		spec:    spec,
+		reader:  DefaultKafkaReaderFactory(conf),
+		decoder: newDecoder(socket.DecoderConfig{TimeProvider: &line.NowTimeProvider{}, Allocator: alloc}),
+	}, nil
+}
+
+type fromKafkaSource struct {
+	execute.ExecutionNode
+	id      execute.DatasetID
+	spec    *FromKafkaProcedureSpec
+	reader  KafkaReader
+	decoder flux.ResultDecoder
+	ts      []execute.Transformation
+}
+
+func (s *fromKafkaSource) AddTransformation(t execute.Transformation) {
+	s.ts = append(s.ts, t)
+}
+
+func (s *fromKafkaSource) Run(ctx context.Context) {
+	defer s.reader.Close()
+
+	// The message values are streamed to the decoder, one message per line.
+	readCtx, cancel := context.WithCancel(ctx)
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	var read []kafka.Message
+	go func() {
+		defer close(done)
+		var err error
+		read, err = s.readMessages(readCtx, pw)
+		_ = pw.CloseWithError(err)
+	}()
+
+	result, err := s.decoder.Decode(pr)
+	if err != nil {
+		err = errors.Wrap(err, codes.Inherit, "decode error")
+	} else {
+		err = result.Tables().Do(s.processTable)
+	}
+
+	if err != nil && ctx.Err() != nil {
+		err = errors.Wrap(ctx.Err(), codes.Canceled, "kafka source canceled")
+	}
+
+	// Stop reading messages when the decoder returns early.
+	cancel()
+	_ = pr.Close()
+	<-done
+
+	// The messages of a group are only committed once every
+	// transformation has processed the tables decoded from them.
+	if err == nil && s.spec.GroupID != "" && len(read) > 0 {
+		if cerr := s.reader.CommitMessages(ctx, read...); cerr != nil {
+			err = errors.Wrap(cerr, codes.Unavailable, "failed to commit kafka message")
+		}
+	}
+
+	for _, t := range s.ts {
+		t.Finish(s.id, err)
+	}
+}
+
+// processTable passes the table to each transformation.
+// When there is more than one transformation,
+// each of them is given its own copy of the table.
+func (s *fromKafkaSource) processTable(tbl flux.Table) error {
+	if len(s.ts) == 0 {
+		tbl.Done()
+		return nil
+	} else if len(s.ts) == 1 {
+		return s.ts[0].Process(s.id, tbl)
+	}
+
+	bufTable, err := execute.CopyTable(tbl)
+	if err != nil {
+		return err
+	}
+	defer bufTable.Done()
+
+	for _, t := range s.ts {
+		if err := t.Process(s.id, bufTable.Copy()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readMessages writes the values of the messages to w until the end offset,
+// the stop time or the end of the partition is reached.
+// The end of the partition is where it was when the query started
+// or, with a group, where no message arrives within the FetchTimeout.
+// It returns the last message read from each partition
+// so that the messages of a group can be committed once they are processed.
+func (s *fromKafkaSource) readMessages(ctx context.Context, w io.Writer) ([]kafka.Message, error) {
+	// read holds the last message read from each partition
+	// and last is the index of that message within read.
+	var read []kafka.Message
+	last := make(map[int]int)
+	// remaining is the number of messages to read until the end of the partition.
+	remaining := int64(-1)
+	if s.spec.GroupID == "" {
+		if s.spec.StartOffset != FirstOffset {
+			if err := s.reader.SetOffset(s.spec.StartOffset); err != nil {
+				return nil, errors.Wrap(err, codes.Invalid, "failed to set kafka offset")
+			}
+		}
+		lag, err := s.reader.ReadLag(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, codes.Unavailable, "failed to read kafka lag")
+		}
+		if lag <= 0 {
+			return nil, nil
+		}
+		remaining = lag
+	}
+	if s.spec.EndOffset != NoEndOffset && s.spec.StartOffset >= s.spec.EndOffset {
+		return nil, nil
+	}
+
+	for {
+		m, err := s.fetchMessage(ctx)
+		if err == errCaughtUp {
+			return read, nil
+		} else if err != nil {
+			return nil, errors.Wrap(err, codes.Unavailable, "failed to read kafka message")
+		}
+		if s.spec.EndOffset != NoEndOffset && m.Offset >= s.spec.EndOffset {
+			return read, nil
+		}
+		if !s.spec.Stop.IsZero() && !m.Time.Before(s.spec.Stop) {
+			return read, nil
+		}
+
+		if _, err := w.Write(m.Value); err != nil {
+			return nil, err
+		}
+		if len(m.Value) == 0 || m.Value[len(m.Value)-1] != '\n' {
+			if _, err := w.Write([]byte{'\n'}); err != nil {
+				return nil, err
+			}
+		}
+		// Only the position of the message is needed to commit it.
+		pos := kafka.Message{Topic: m.Topic, Partition: m.Partition, Offset: m.Offset}
+		if i, ok := last[m.Partition]; ok {
+			read[i] = pos
+		} else {
+			last[m.Partition] = len(read)
+			read = append(read, pos)
+		}
+
+		if s.spec.EndOffset != NoEndOffset && m.Offset+1 >= s.spec.EndOffset {
+			return read, nil
+		}
+		if remaining > 0 {
+			// Stop once the reader has caught up with the partition
+			// as it was when the query started.
+			if remaining--; remaining == 0 || s.reader.Lag() <= 0 {
+				return read, nil
+			}
+		}
+	}
+}
+
+// errCaughtUp is returned by fetchMessage when no message
+// of the group arrives within the FetchTimeout.
+var errCaughtUp = errors.New(codes.Unavailable, "no kafka message within the fetch timeout")
+
+// fetchMessage fetches the next message. With a group, the fetch is bounded
+// by the FetchTimeout so that a caught up group does not wait forever.
+func (s *fromKafkaSource) fetchMessage(ctx context.Context) (kafka.Message, error) {
+	if s.spec.GroupID == "" {
+		return s.reader.FetchMessage(ctx)
+	}
+	fetchCtx, cancel := context.WithTimeout(ctx, FetchTimeout)
+	defer cancel()
+	m, err := s.reader.FetchMessage(fetchCtx)
+	if err != nil && ctx.Err() == nil && fetchCtx.Err() == context.DeadlineExceeded {
+		return kafka.Message{}, errCaughtUp
+	}
+	return m, err
+}
@@ -0,0 +1,461 @@
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/querytest"
+	fkafka "github.com/influxdata/flux/stdlib/kafka"
+	"github.com/segmentio/kafka-go"
+)
+
+func TestFromKafka_NewQuery(t *testing.T) {
+	tests := []querytest.NewQueryTestCase{
+		{
+			Name: "from with defaults",
+			Raw:  `import "kafka" kafka.from(brokers: ["brokerurl:8989"], topic: "telegraf")`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromKafka0",
+						Spec: &fkafka.FromKafkaOpSpec{
+							Brokers:     []string{"brokerurl:8989"},
+							Topic:       "telegraf",
+							StartOffset: fkafka.FirstOffset,
+							EndOffset:   fkafka.NoEndOffset,
+							Decoder:     "lineprotocol",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "from with partition and offsets",
+			Raw:  `import "kafka" kafka.from(brokers: ["brokerurl:8989"], topic: "telegraf", partition: 2, startOffset: 10, endOffset: 20, decoder: "json")`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromKafka0",
+						Spec: &fkafka.FromKafkaOpSpec{
+							Brokers:     []string{"brokerurl:8989"},
+							Topic:       "telegraf",
+							Partition:   2,
+							StartOffset: 10,
+							EndOffset:   20,
+							Decoder:     "json",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "from with group",
+			Raw:  `import "kafka" kafka.from(brokers: ["brokerurl:8989"], topic: "telegraf", groupID: "flux", stop: 2020-01-01T00:00:00Z, decoder: "csv")`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromKafka0",
+						Spec: &fkafka.FromKafkaOpSpec{
+							Brokers:     []string{"brokerurl:8989"},
+							Topic:       "telegraf",
+							GroupID:     "flux",
+							StartOffset: fkafka.FirstOffset,
+							EndOffset:   fkafka.NoEndOffset,
+							Stop: flux.Time{
+								Absolute: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+							},
+							Decoder: "csv",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:    "group without end",
+			Raw:     `import "kafka" kafka.from(brokers: ["brokerurl:8989"], topic: "telegraf", groupID: "flux")`,
+			WantErr: true,
+		},
+		{
+			Name:    "group with partition",
+			Raw:     `import "kafka" kafka.from(brokers: ["brokerurl:8989"], topic: "telegraf", groupID: "flux", partition: 1, endOffset: 10)`,
+			WantErr: true,
+		},
+		{
+			Name:    "end before start",
+			Raw:     `import "kafka" kafka.from(brokers: ["brokerurl:8989"], topic: "telegraf", startOffset: 10, endOffset: 5)`,
+			WantErr: true,
+		},
+		{
+			Name:    "unknown decoder",
+			Raw:     `import "kafka" kafka.from(brokers: ["brokerurl:8989"], topic: "telegraf", decoder: "avro")`,
+			WantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			querytest.NewQueryTestHelper(t, tc)
+		})
+	}
+}
+
+// kafkaReaderMock serves the messages of a single partition.
+type kafkaReaderMock struct {
+	messages  []kafka.Message
+	offset    int64
+	committed []int64
+	lag       int64
+	err       error
+}
+
+func (k *kafkaReaderMock) Close() error { return nil }
+
+func (k *kafkaReaderMock) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if k.err != nil {
+		return kafka.Message{}, k.err
+	}
+	for _, m := range k.messages {
+		if m.Offset >= k.offset {
+			k.offset = m.Offset + 1
+			k.lag = k.messages[len(k.messages)-1].Offset + 1 - k.offset
+			return m, nil
+		}
+	}
+	// Block like a reader waiting for new messages.
+	<-ctx.Done()
+	return kafka.Message{}, ctx.Err()
+}
+
+func (k *kafkaReaderMock) CommitMessages(_ context.Context, msgs ...kafka.Message) error {
+	for _, m := range msgs {
+		k.committed = append(k.committed, m.Offset)
+	}
+	return nil
+}
+
+func (k *kafkaReaderMock) SetOffset(offset int64) error {
+	k.offset = offset
+	return nil
+}
+
+func (k *kafkaReaderMock) ReadLag(context.Context) (int64, error) {
+	var lag int64
+	for _, m := range k.messages {
+		if m.Offset >= k.offset {
+			lag++
+		}
+	}
+	return lag, nil
+}
+
+func (k *kafkaReaderMock) Lag() int64 { return k.lag }
+
+func TestFromKafkaSource_Run(t *testing.T) {
+	messages := []kafka.Message{
+		{Offset: 0, Time: time.Unix(0, 10).UTC(), Value: []byte("cpu,host=a usage=0.5 10")},
+		{Offset: 1, Time: time.Unix(0, 20).UTC(), Value: []byte("cpu,host=a usage=0.75 20\ncpu,host=b usage=1 20\n")},
+		{Offset: 2, Time: time.Unix(0, 30).UTC(), Value: []byte("cpu,host=a usage=0.25 30")},
+	}
+	lineTable := func(rows ...[]interface{}) *executetest.Table {
+		return &executetest.Table{
+			KeyCols: []string{"_measurement", "host", "_field"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "host", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: rows,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		spec          *fkafka.FromKafkaProcedureSpec
+		messages      []kafka.Message
+		err           error
+		want          []*executetest.Table
+		wantCommitted []int64
+		wantErr       error
+	}{
+		{
+			name: "end of partition",
+			spec: &fkafka.FromKafkaProcedureSpec{
+				StartOffset: fkafka.FirstOffset,
+				EndOffset:   fkafka.NoEndOffset,
+				Decoder:     "lineprotocol",
+			},
+			messages: messages,
+			want: []*executetest.Table{
+				lineTable(
+					[]interface{}{execute.Time(10), "cpu", "a", "usage", 0.5},
+					[]interface{}{execute.Time(20), "cpu", "a", "usage", 0.75},
+					[]interface{}{execute.Time(30), "cpu", "a", "usage", 0.25},
+				),
+				lineTable(
+					[]interface{}{execute.Time(20), "cpu", "b", "usage", 1.0},
+				),
+			},
+		},
+		{
+			name: "offsets",
+			spec: &fkafka.FromKafkaProcedureSpec{
+				StartOffset: 1,
+				EndOffset:   2,
+				Decoder:     "lineprotocol",
+			},
+			messages: messages,
+			want: []*executetest.Table{
+				lineTable(
+					[]interface{}{execute.Time(20), "cpu", "a", "usage", 0.75},
+				),
+				lineTable(
+					[]interface{}{execute.Time(20), "cpu", "b", "usage", 1.0},
+				),
+			},
+		},
+		{
+			name: "group with stop",
+			spec: &fkafka.FromKafkaProcedureSpec{
+				GroupID:     "flux",
+				StartOffset: fkafka.FirstOffset,
+				EndOffset:   fkafka.NoEndOffset,
+				Stop:        time.Unix(0, 30).UTC(),
+				Decoder:     "lineprotocol",
+			},
+			messages: messages,
+			want: []*executetest.Table{
+				lineTable(
+					[]interface{}{execute.Time(10), "cpu", "a", "usage", 0.5},
+					[]interface{}{execute.Time(20), "cpu", "a", "usage", 0.75},
+				),
+				lineTable(
+					[]interface{}{execute.Time(20), "cpu", "b", "usage", 1.0},
+				),
+			},
+			// Only the last message of the partition is committed.
+			wantCommitted: []int64{1},
+		},
+		{
+			name: "json",
+			spec: &fkafka.FromKafkaProcedureSpec{
+				StartOffset: fkafka.FirstOffset,
+				EndOffset:   fkafka.NoEndOffset,
+				Decoder:     "json",
+			},
+			messages: []kafka.Message{
+				{Offset: 0, Value: []byte(`{"_time": 1, "temp": 21.5}`)},
+				{Offset: 1, Value: []byte(`{"_time": 2, "temp": 22}`)},
+			},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "temp", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), 21.5},
+					{execute.Time(2), 22.0},
+				},
+			}},
+		},
+		{
+			name: "stop after the end of partition",
+			spec: &fkafka.FromKafkaProcedureSpec{
+				StartOffset: fkafka.FirstOffset,
+				EndOffset:   fkafka.NoEndOffset,
+				Stop:        time.Unix(0, 100).UTC(),
+				Decoder:     "lineprotocol",
+			},
+			messages: messages,
+			want: []*executetest.Table{
+				lineTable(
+					[]interface{}{execute.Time(10), "cpu", "a", "usage", 0.5},
+					[]interface{}{execute.Time(20), "cpu", "a", "usage", 0.75},
+					[]interface{}{execute.Time(30), "cpu", "a", "usage", 0.25},
+				),
+				lineTable(
+					[]interface{}{execute.Time(20), "cpu", "b", "usage", 1.0},
+				),
+			},
+		},
+		{
+			name: "end offset after the end of partition",
+			spec: &fkafka.FromKafkaProcedureSpec{
+				StartOffset: 2,
+				EndOffset:   10,
+				Decoder:     "lineprotocol",
+			},
+			messages: messages,
+			want: []*executetest.Table{
+				lineTable(
+					[]interface{}{execute.Time(30), "cpu", "a", "usage", 0.25},
+				),
+			},
+		},
+		{
+			name: "group caught up",
+			spec: &fkafka.FromKafkaProcedureSpec{
+				GroupID:     "flux",
+				StartOffset: fkafka.FirstOffset,
+				EndOffset:   fkafka.NoEndOffset,
+				Stop:        time.Unix(0, 100).UTC(),
+				Decoder:     "lineprotocol",
+			},
+			messages: messages[2:],
+			want: []*executetest.Table{
+				lineTable(
+					[]interface{}{execute.Time(30), "cpu", "a", "usage", 0.25},
+				),
+			},
+			wantCommitted: []int64{2},
+		},
+		{
+			name: "read error",
+			spec: &fkafka.FromKafkaProcedureSpec{
+				GroupID:     "flux",
+				StartOffset: fkafka.FirstOffset,
+				EndOffset:   10,
+				Decoder:     "lineprotocol",
+			},
+			err:     errors.New("broker unavailable"),
+			wantErr: errors.New("failed to read kafka message: broker unavailable"),
+		},
+	}
+
+	// A caught up group waits for the fetch timeout before it stops reading.
+	defer func(timeout time.Duration) { fkafka.FetchTimeout = timeout }(fkafka.FetchTimeout)
+	fkafka.FetchTimeout = 10 * time.Millisecond
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			reader := &kafkaReaderMock{messages: tc.messages, err: tc.err}
+			fkafka.DefaultKafkaReaderFactory = func(kafka.ReaderConfig) fkafka.KafkaReader {
+				return reader
+			}
+
+			id := executetest.RandomDatasetID()
+			src, err := fkafka.NewFromKafkaSource(tc.spec, id, executetest.UnlimitedAllocator)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Each of the transformations must receive every table.
+			datasets := make([]*executetest.Dataset, 2)
+			caches := make([]execute.DataCache, len(datasets))
+			for i := range datasets {
+				datasets[i] = executetest.NewDataset(id)
+				c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+				c.SetTriggerSpec(plan.DefaultTriggerSpec)
+				caches[i] = c
+				src.AddTransformation(executetest.NewYieldTransformation(datasets[i], c))
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			src.Run(ctx)
+
+			executetest.NormalizeTables(tc.want)
+			for i, d := range datasets {
+				if tc.wantErr != nil {
+					if d.FinishedErr == nil {
+						t.Fatalf("expected error %q", tc.wantErr)
+					}
+					if want, got := tc.wantErr.Error(), d.FinishedErr.Error(); want != got {
+						t.Fatalf("unexpected error -want/+got:\n- %q\n+ %q", want, got)
+					}
+					continue
+				}
+				if d.FinishedErr != nil {
+					t.Fatal(d.FinishedErr)
+				}
+
+				got, err := executetest.TablesFromCache(caches[i])
+				if err != nil {
+					t.Fatal(err)
+				}
+				executetest.NormalizeTables(got)
+				if !cmp.Equal(tc.want, got) {
+					t.Errorf("unexpected tables of transformation %d -want/+got\n%s", i, cmp.Diff(tc.want, got))
+				}
+			}
+			if tc.wantErr != nil {
+				return
+			}
+			if !cmp.Equal(tc.wantCommitted, reader.committed) {
+				t.Errorf("unexpected committed offsets -want/+got\n%s", cmp.Diff(tc.wantCommitted, reader.committed))
+			}
+		})
+	}
+}
+
+// failingTransformation returns an error for every table it processes.
+type failingTransformation struct {
+	execute.ExecutionNode
+	err error
+}
+
+func (t *failingTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return nil
+}
+func (t *failingTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	tbl.Done()
+	return errors.New("process failed")
+}
+func (t *failingTransformation) UpdateWatermark(id execute.DatasetID, ts execute.Time) error {
+	return nil
+}
+func (t *failingTransformation) UpdateProcessingTime(id execute.DatasetID, ts execute.Time) error {
+	return nil
+}
+func (t *failingTransformation) Finish(id execute.DatasetID, err error) {
+	t.err = err
+}
+
+func TestFromKafkaSource_Run_CommitAfterProcess(t *testing.T) {
+	reader := &kafkaReaderMock{
+		messages: []kafka.Message{
+			{Offset: 0, Time: time.Unix(0, 10).UTC(), Value: []byte("cpu,host=a usage=0.5 10")},
+		},
+	}
+	defer func(factory func(kafka.ReaderConfig) fkafka.KafkaReader) {
+		fkafka.DefaultKafkaReaderFactory = factory
+	}(fkafka.DefaultKafkaReaderFactory)
+	fkafka.DefaultKafkaReaderFactory = func(kafka.ReaderConfig) fkafka.KafkaReader {
+		return reader
+	}
+	defer func(timeout time.Duration) { fkafka.FetchTimeout = timeout }(fkafka.FetchTimeout)
+	fkafka.FetchTimeout = 10 * time.Millisecond
+
+	spec := &fkafka.FromKafkaProcedureSpec{
+		GroupID:     "flux",
+		StartOffset: fkafka.FirstOffset,
+		EndOffset:   fkafka.NoEndOffset,
+		Decoder:     "lineprotocol",
+	}
+	src, err := fkafka.NewFromKafkaSource(spec, executetest.RandomDatasetID(), executetest.UnlimitedAllocator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := &failingTransformation{}
+	src.AddTransformation(tr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	src.Run(ctx)
+
+	if tr.err == nil {
+		t.Fatal("expected the error of the transformation")
+	}
+	if len(reader.committed) > 0 {
+		t.Errorf("expected no message to be committed when processing failed, got offsets %v", reader.committed)
+	}
+}
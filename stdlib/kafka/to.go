@@ -12,6 +12,7 @@ import (
 	"github.com/cespare/xxhash"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
+	fluxurl "github.com/influxdata/flux/dependencies/url"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/internal/pkg/syncutil"
@@ -238,7 +239,7 @@ func NewToKafkaTransformation(d execute.Dataset, deps flux.Dependencies, cache e
 		if err != nil {
 			return nil, errors.Newf(codes.Invalid, "invalid kafka broker url: %v", err)
 		}
-		if err := validator.Validate(u); err != nil {
+		if err := fluxurl.ForFunction(validator, "kafka.to").Validate(u); err != nil {
 			return nil, errors.Newf(codes.Invalid, "kafka broker url did not pass validation: %v", err)
 		}
 	}
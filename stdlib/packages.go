@@ -29,6 +29,7 @@ import (
 	_ "github.com/influxdata/flux/stdlib/experimental/json"
 	_ "github.com/influxdata/flux/stdlib/experimental/mqtt"
 	_ "github.com/influxdata/flux/stdlib/experimental/prometheus"
+	_ "github.com/influxdata/flux/stdlib/experimental/promql"
 	_ "github.com/influxdata/flux/stdlib/experimental/query"
 	_ "github.com/influxdata/flux/stdlib/generate"
 	_ "github.com/influxdata/flux/stdlib/http"
@@ -21,11 +21,11 @@ var pkgAST = &ast.Package{
 			Errors: nil,
 			Loc: &ast.SourceLocation{
 				End: ast.Position{
-					Column: 35,
-					Line:   4,
+					Column: 32,
+					Line:   6,
 				},
 				File:   "planner.flux",
-				Source: "package planner\n\noption disableLogicalRules = [\"\"]\noption disablePhysicalRules = [\"\"]",
+				Source: "package planner\n\noption disableLogicalRules = [\"\"]\noption disablePhysicalRules = [\"\"]\noption concurrencyQuota = 0\noption dispatcherThroughput = 0",
 				Start: ast.Position{
 					Column: 1,
 					Line:   1,
@@ -204,6 +204,144 @@ var pkgAST = &ast.Package{
 					},
 				},
 			},
+		}, &ast.OptionStatement{
+			Assignment: &ast.VariableAssignment{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 28,
+							Line:   5,
+						},
+						File:   "planner.flux",
+						Source: "concurrencyQuota = 0",
+						Start: ast.Position{
+							Column: 8,
+							Line:   5,
+						},
+					},
+				},
+				ID: &ast.Identifier{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 24,
+								Line:   5,
+							},
+							File:   "planner.flux",
+							Source: "concurrencyQuota",
+							Start: ast.Position{
+								Column: 8,
+								Line:   5,
+							},
+						},
+					},
+					Name: "concurrencyQuota",
+				},
+				Init: &ast.IntegerLiteral{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 28,
+								Line:   5,
+							},
+							File:   "planner.flux",
+							Source: "0",
+							Start: ast.Position{
+								Column: 27,
+								Line:   5,
+							},
+						},
+					},
+					Value: int64(0),
+				},
+			},
+			BaseNode: ast.BaseNode{
+				Errors: nil,
+				Loc: &ast.SourceLocation{
+					End: ast.Position{
+						Column: 28,
+						Line:   5,
+					},
+					File:   "planner.flux",
+					Source: "option concurrencyQuota = 0",
+					Start: ast.Position{
+						Column: 1,
+						Line:   5,
+					},
+				},
+			},
+		}, &ast.OptionStatement{
+			Assignment: &ast.VariableAssignment{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 32,
+							Line:   6,
+						},
+						File:   "planner.flux",
+						Source: "dispatcherThroughput = 0",
+						Start: ast.Position{
+							Column: 8,
+							Line:   6,
+						},
+					},
+				},
+				ID: &ast.Identifier{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 28,
+								Line:   6,
+							},
+							File:   "planner.flux",
+							Source: "dispatcherThroughput",
+							Start: ast.Position{
+								Column: 8,
+								Line:   6,
+							},
+						},
+					},
+					Name: "dispatcherThroughput",
+				},
+				Init: &ast.IntegerLiteral{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 32,
+								Line:   6,
+							},
+							File:   "planner.flux",
+							Source: "0",
+							Start: ast.Position{
+								Column: 31,
+								Line:   6,
+							},
+						},
+					},
+					Value: int64(0),
+				},
+			},
+			BaseNode: ast.BaseNode{
+				Errors: nil,
+				Loc: &ast.SourceLocation{
+					End: ast.Position{
+						Column: 32,
+						Line:   6,
+					},
+					File:   "planner.flux",
+					Source: "option dispatcherThroughput = 0",
+					Start: ast.Position{
+						Column: 1,
+						Line:   6,
+					},
+				},
+			},
 		}},
 		Imports:  nil,
 		Metadata: "parser-type=rust",
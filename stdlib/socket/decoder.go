@@ -0,0 +1,83 @@
+package socket
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/line"
+	"github.com/influxdata/flux/lineprotocol"
+	"github.com/influxdata/flux/memory"
+)
+
+// DecoderConfig is the configuration given to a decoder
+// when it is created for a socket source.
+type DecoderConfig struct {
+	// TimeProvider gives the time at which the data was read
+	// to decoders that timestamp their input.
+	TimeProvider line.TimeProvider
+	// Allocator is used for the memory of the tables.
+	// The memory is not tracked when it is nil.
+	Allocator *memory.Allocator
+}
+
+func (c DecoderConfig) allocator() *memory.Allocator {
+	if c.Allocator == nil {
+		return &memory.Allocator{}
+	}
+	return c.Allocator
+}
+
+// NewDecoderFunc creates a decoder that produces tables
+// from the data read from a socket.
+type NewDecoderFunc func(config DecoderConfig) flux.ResultDecoder
+
+// defaultDecoder is used when socket.from is called without a decoder.
+const defaultDecoder = "csv"
+
+var decoderRegistry = make(map[string]NewDecoderFunc)
+
+// RegisterDecoder makes a decoder available to socket.from with the given name.
+// It is meant to be called from init functions and
+// panics when two decoders are registered with the same name.
+func RegisterDecoder(name string, fn NewDecoderFunc) {
+	if _, ok := decoderRegistry[name]; ok {
+		panic(fmt.Errorf("duplicate registration for socket decoder %q", name))
+	}
+	decoderRegistry[name] = fn
+}
+
+// LookupDecoder returns the decoder registered with the given name.
+func LookupDecoder(name string) (NewDecoderFunc, bool) {
+	fn, ok := decoderRegistry[name]
+	return fn, ok
+}
+
+// Decoders returns the names of the registered decoders in sorted order.
+func Decoders() []string {
+	names := make([]string, 0, len(decoderRegistry))
+	for name := range decoderRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	RegisterDecoder("csv", func(config DecoderConfig) flux.ResultDecoder {
+		return csv.NewResultDecoder(csv.ResultDecoderConfig{Allocator: config.allocator()})
+	})
+	RegisterDecoder("line", func(config DecoderConfig) flux.ResultDecoder {
+		return line.NewResultDecoder(&line.ResultDecoderConfig{
+			Separator:    '\n',
+			TimeProvider: config.TimeProvider,
+			Allocator:    config.allocator(),
+		})
+	})
+	RegisterDecoder("lineprotocol", func(config DecoderConfig) flux.ResultDecoder {
+		return lineprotocol.NewResultDecoder(config.TimeProvider, config.allocator())
+	})
+	RegisterDecoder("json", newJSONDecoder)
+	RegisterDecoder("prometheus", newPrometheusDecoder)
+}
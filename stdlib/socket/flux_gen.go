@@ -71,11 +71,11 @@ var pkgAST = &ast.Package{
 					Errors: nil,
 					Loc: &ast.SourceLocation{
 						End: ast.Position{
-							Column: 54,
+							Column: 88,
 							Line:   3,
 						},
 						File:   "socket.flux",
-						Source: "(url: string, ?decoder: string) => [A]",
+						Source: "(url: string, ?decoder: string, ?listen: bool, ?connections: int) => [A]",
 						Start: ast.Position{
 							Column: 16,
 							Line:   3,
@@ -88,11 +88,11 @@ var pkgAST = &ast.Package{
 						Errors: nil,
 						Loc: &ast.SourceLocation{
 							End: ast.Position{
-								Column: 54,
+								Column: 88,
 								Line:   3,
 							},
 							File:   "socket.flux",
-							Source: "(url: string, ?decoder: string) => [A]",
+							Source: "(url: string, ?decoder: string, ?listen: bool, ?connections: int) => [A]",
 							Start: ast.Position{
 								Column: 16,
 								Line:   3,
@@ -239,19 +239,159 @@ var pkgAST = &ast.Package{
 								Name: "string",
 							},
 						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 61,
+									Line:   3,
+								},
+								File:   "socket.flux",
+								Source: "?listen: bool",
+								Start: ast.Position{
+									Column: 48,
+									Line:   3,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 55,
+										Line:   3,
+									},
+									File:   "socket.flux",
+									Source: "listen",
+									Start: ast.Position{
+										Column: 49,
+										Line:   3,
+									},
+								},
+							},
+							Name: "listen",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 61,
+										Line:   3,
+									},
+									File:   "socket.flux",
+									Source: "bool",
+									Start: ast.Position{
+										Column: 57,
+										Line:   3,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 61,
+											Line:   3,
+										},
+										File:   "socket.flux",
+										Source: "bool",
+										Start: ast.Position{
+											Column: 57,
+											Line:   3,
+										},
+									},
+								},
+								Name: "bool",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 80,
+									Line:   3,
+								},
+								File:   "socket.flux",
+								Source: "?connections: int",
+								Start: ast.Position{
+									Column: 63,
+									Line:   3,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 75,
+										Line:   3,
+									},
+									File:   "socket.flux",
+									Source: "connections",
+									Start: ast.Position{
+										Column: 64,
+										Line:   3,
+									},
+								},
+							},
+							Name: "connections",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 80,
+										Line:   3,
+									},
+									File:   "socket.flux",
+									Source: "int",
+									Start: ast.Position{
+										Column: 77,
+										Line:   3,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 80,
+											Line:   3,
+										},
+										File:   "socket.flux",
+										Source: "int",
+										Start: ast.Position{
+											Column: 77,
+											Line:   3,
+										},
+									},
+								},
+								Name: "int",
+							},
+						},
 					}},
 					Return: &ast.ArrayType{
 						BaseNode: ast.BaseNode{
 							Errors: nil,
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
-									Column: 54,
+									Column: 88,
 									Line:   3,
 								},
 								File:   "socket.flux",
 								Source: "[A]",
 								Start: ast.Position{
-									Column: 51,
+									Column: 85,
 									Line:   3,
 								},
 							},
@@ -261,13 +401,13 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 53,
+										Column: 87,
 										Line:   3,
 									},
 									File:   "socket.flux",
 									Source: "A",
 									Start: ast.Position{
-										Column: 52,
+										Column: 86,
 										Line:   3,
 									},
 								},
@@ -277,13 +417,13 @@ var pkgAST = &ast.Package{
 									Errors: nil,
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
-											Column: 53,
+											Column: 87,
 											Line:   3,
 										},
 										File:   "socket.flux",
 										Source: "A",
 										Start: ast.Position{
-											Column: 52,
+											Column: 86,
 											Line:   3,
 										},
 									},
@@ -1,6 +1,8 @@
 // Package socket implements a source that gets input from a socket connection and produces tables given a decoder.
 // This is a good candidate for streaming use cases. For now, it produces a single table for everything
 // that it receives from the start to the end of the connection.
+// The source either dials the url or listens on it and reads the connections it accepts.
+// Decoders are looked up by name in a registry that other packages can extend with RegisterDecoder.
 package socket
 
 import (
@@ -9,24 +11,25 @@ import (
 	"net"
 	neturl "net/url"
 	"strings"
-	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
-	"github.com/influxdata/flux/csv"
+	fluxurl "github.com/influxdata/flux/dependencies/url"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/line"
+	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
-	"github.com/influxdata/flux/values"
 )
 
 const FromSocketKind = "fromSocket"
 
 type FromSocketOpSpec struct {
-	URL     string `json:"url"`
-	Decoder string `json:"decoder"`
+	URL         string `json:"url"`
+	Decoder     string `json:"decoder"`
+	Listen      bool   `json:"listen,omitempty"`
+	Connections int    `json:"connections,omitempty"`
 }
 
 func init() {
@@ -38,17 +41,7 @@ func init() {
 	execute.RegisterSource(FromSocketKind, createFromSocketSource)
 }
 
-// nowTimeProvider provides wall clock time.
-type nowTimeProvider struct{}
-
-func (a *nowTimeProvider) CurrentTime() values.Time {
-	return values.ConvertTime(time.Now())
-}
-
-var (
-	decoders = []string{"csv", "line"}
-	schemes  = []string{"tcp", "unix"}
-)
+var schemes = []string{"tcp", "udp", "unix"}
 
 func contains(ss []string, s string) bool {
 	for _, st := range ss {
@@ -73,11 +66,31 @@ func createFromSocketOpSpec(args flux.Arguments, a *flux.Administration) (flux.O
 	} else if ok {
 		spec.Decoder = d
 	} else {
-		spec.Decoder = decoders[0]
+		spec.Decoder = defaultDecoder
+	}
+
+	if _, ok := decoderRegistry[spec.Decoder]; !ok {
+		return nil, errors.Newf(codes.Invalid, "invalid decoder %s, must be one of %v", spec.Decoder, Decoders())
+	}
+
+	if l, ok, err := args.GetBool("listen"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.Listen = l
 	}
 
-	if !contains(decoders, spec.Decoder) {
-		return nil, errors.Newf(codes.Invalid, "invalid decoder %s, must be one of %v", spec.Decoder, decoders)
+	if n, ok, err := args.GetInt("connections"); err != nil {
+		return nil, err
+	} else if ok {
+		if !spec.Listen {
+			return nil, errors.New(codes.Invalid, "connections can only be set when listening")
+		}
+		if n <= 0 {
+			return nil, errors.Newf(codes.Invalid, "connections must be positive, got %d", n)
+		}
+		spec.Connections = int(n)
+	} else if spec.Listen {
+		spec.Connections = 1
 	}
 
 	return spec, nil
@@ -95,6 +108,10 @@ type FromSocketProcedureSpec struct {
 	plan.DefaultCost
 	URL     string
 	Decoder string
+	// Listen is set when the source accepts connections on the url instead of dialing it.
+	Listen bool
+	// Connections is the number of connections to accept when listening.
+	Connections int
 }
 
 func newFromSocketProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
@@ -104,8 +121,10 @@ func newFromSocketProcedure(qs flux.OperationSpec, pa plan.Administration) (plan
 	}
 
 	return &FromSocketProcedureSpec{
-		URL:     spec.URL,
-		Decoder: spec.Decoder,
+		URL:         spec.URL,
+		Decoder:     spec.Decoder,
+		Listen:      spec.Listen,
+		Connections: spec.Connections,
 	}, nil
 }
 
@@ -117,6 +136,8 @@ func (s *FromSocketProcedureSpec) Copy() plan.ProcedureSpec {
 	ns := new(FromSocketProcedureSpec)
 	ns.URL = s.URL
 	ns.Decoder = s.Decoder
+	ns.Listen = s.Listen
+	ns.Connections = s.Connections
 	return ns
 }
 
@@ -141,7 +162,7 @@ func createFromSocketSource(s plan.ProcedureSpec, dsid execute.DatasetID, a exec
 	if err != nil {
 		return nil, err
 	}
-	if err := validator.Validate(url); err != nil {
+	if err := fluxurl.ForFunction(validator, "socket.from").Validate(url); err != nil {
 		return nil, errors.Newf(codes.Invalid, "url did not pass validation: %v", err)
 	}
 	scheme = url.Scheme
@@ -149,35 +170,52 @@ func createFromSocketSource(s plan.ProcedureSpec, dsid execute.DatasetID, a exec
 	if !contains(schemes, scheme) {
 		return nil, errors.Newf(codes.Invalid, "invalid scheme %s, must be one of %v", scheme, schemes)
 	}
+	if scheme == "unix" && address == "" {
+		// unix:///path/to/socket
+		address = url.Path
+	}
 
+	if spec.Listen {
+		n := spec.Connections
+		if n <= 0 {
+			n = 1
+		}
+		rc, err := listen(scheme, address, n)
+		if err != nil {
+			return nil, errors.Wrap(err, codes.Inherit, "error in creating socket source")
+		}
+		return NewSocketSourceWithAllocator(spec, rc, &line.NowTimeProvider{}, dsid, a.Allocator())
+	}
+
+	if scheme == "udp" {
+		return nil, errors.New(codes.Invalid, "scheme udp is only supported when listening")
+	}
 	conn, err := net.Dial(scheme, address)
 	if err != nil {
 		return nil, errors.Wrap(err, codes.Inherit, "error in creating socket source")
 	}
 
-	return NewSocketSource(spec, conn, &nowTimeProvider{}, dsid)
+	return NewSocketSourceWithAllocator(spec, conn, &line.NowTimeProvider{}, dsid, a.Allocator())
 }
 
+// NewSocketSource creates a source that decodes the tables read from rc.
+// The memory of the tables is not tracked by a query allocator.
 func NewSocketSource(spec *FromSocketProcedureSpec, rc io.ReadCloser, tp line.TimeProvider, dsid execute.DatasetID) (execute.Source, error) {
-	var decoder flux.ResultDecoder
-	switch spec.Decoder {
-	case "csv":
-		decoder = csv.NewResultDecoder(csv.ResultDecoderConfig{})
-	case "line":
-		decoder = line.NewResultDecoder(&line.ResultDecoderConfig{
-			Separator:    '\n',
-			TimeProvider: tp,
-		})
-	}
+	return NewSocketSourceWithAllocator(spec, rc, tp, dsid, nil)
+}
 
-	if decoder == nil {
+// NewSocketSourceWithAllocator creates a source that decodes the tables
+// read from rc into memory from the allocator.
+func NewSocketSourceWithAllocator(spec *FromSocketProcedureSpec, rc io.ReadCloser, tp line.TimeProvider, dsid execute.DatasetID, alloc *memory.Allocator) (execute.Source, error) {
+	newDecoder, ok := decoderRegistry[spec.Decoder]
+	if !ok {
 		return nil, errors.Newf(codes.Invalid, "unknown decoder type: %v", spec.Decoder)
 	}
 
 	return &socketSource{
 		d:       dsid,
 		rc:      rc,
-		decoder: decoder,
+		decoder: newDecoder(DecoderConfig{TimeProvider: tp, Allocator: alloc}),
 	}, nil
 }
 
@@ -195,6 +233,19 @@ func (ss *socketSource) AddTransformation(t execute.Transformation) {
 
 func (ss *socketSource) Run(ctx context.Context) {
 	defer ss.rc.Close()
+
+	// Close the connection when the query is canceled
+	// to interrupt reads that are waiting for data.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			ss.rc.Close()
+		case <-done:
+		}
+	}()
+
 	result, err := ss.decoder.Decode(ss.rc)
 	if err != nil {
 		err = errors.Wrap(err, codes.Inherit, "decode error")
@@ -208,6 +259,9 @@ func (ss *socketSource) Run(ctx context.Context) {
 			return nil
 		})
 	}
+	if err != nil && ctx.Err() != nil {
+		err = errors.Wrap(ctx.Err(), codes.Canceled, "socket source canceled")
+	}
 
 	for _, t := range ss.ts {
 		t.Finish(ss.d, err)
@@ -1,6 +1,12 @@
 package socket
 
 import (
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/influxdata/flux/dependencies/url"
@@ -23,6 +29,13 @@ func TestFromSocketUrlValidation(t *testing.T) {
 				Decoder: "csv",
 			},
 			ErrMsg: "connection refused",
+		}, {
+			Name: "udp without listen",
+			Spec: &FromSocketProcedureSpec{
+				URL:     "udp://localhost:12345",
+				Decoder: "csv",
+			},
+			ErrMsg: "scheme udp is only supported when listening",
 		}, {
 			Name: "validation failed",
 			Spec: &FromSocketProcedureSpec{
@@ -45,3 +58,114 @@ func TestFromSocketUrlValidation(t *testing.T) {
 	}
 	testCases.Run(t, createFromSocketSource)
 }
+
+func TestListen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "socket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	address := filepath.Join(dir, "from.sock")
+
+	rc, err := listen("unix", address, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	errC := make(chan error, 1)
+	go func() {
+		for _, msg := range []string{"first\n", "second", "third"} {
+			conn, err := net.Dial("unix", address)
+			if err != nil {
+				errC <- err
+				return
+			}
+			if _, err := conn.Write([]byte(msg)); err != nil {
+				errC <- err
+				return
+			}
+			if err := conn.Close(); err != nil {
+				errC <- err
+				return
+			}
+		}
+		errC <- nil
+	}()
+
+	got, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errC; err != nil {
+		t.Fatal(err)
+	}
+	if want := "first\nsecond\nthird\n"; string(got) != want {
+		t.Errorf("unexpected data -want/+got:\n\t- %q\n\t+ %q", want, string(got))
+	}
+}
+
+func TestListen_UDP(t *testing.T) {
+	rc, err := listen("udp", "127.0.0.1:0", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	address := rc.(*acceptReader).listener.(net.PacketConn).LocalAddr().String()
+
+	errC := make(chan error, 1)
+	go func() {
+		conn, err := net.Dial("udp", address)
+		if err != nil {
+			errC <- err
+			return
+		}
+		defer conn.Close()
+		for _, msg := range []string{"first", "second\n"} {
+			if _, err := conn.Write([]byte(msg)); err != nil {
+				errC <- err
+				return
+			}
+		}
+		errC <- nil
+	}()
+
+	got, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errC; err != nil {
+		t.Fatal(err)
+	}
+	if want := "first\nsecond\n"; string(got) != want {
+		t.Errorf("unexpected data -want/+got:\n\t- %q\n\t+ %q", want, string(got))
+	}
+}
+
+func TestAcceptReader_ShortBuffer(t *testing.T) {
+	conns := []string{"ab", "cd\n", "", "e"}
+	r := &acceptReader{
+		accept: func() (io.ReadCloser, error) {
+			conn := conns[0]
+			conns = conns[1:]
+			return ioutil.NopCloser(strings.NewReader(conn)), nil
+		},
+		listener:  ioutil.NopCloser(nil),
+		remaining: len(conns),
+	}
+
+	var got []byte
+	p := make([]byte, 1)
+	for {
+		n, err := r.Read(p)
+		got = append(got, p[:n]...)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if want := "ab\ncd\ne\n"; string(got) != want {
+		t.Errorf("unexpected data -want/+got:\n\t- %q\n\t+ %q", want, string(got))
+	}
+}
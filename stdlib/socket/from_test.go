@@ -35,6 +35,30 @@ socket.from()`,
 socket.from(url: "url", decoder: "wrong")`,
 			WantErr: true,
 		},
+		{
+			Name: "from connections without listen",
+			Raw: `import "socket"
+socket.from(url: "url", connections: 2)`,
+			WantErr: true,
+		},
+		{
+			Name: "from listen",
+			Raw: `import "socket"
+socket.from(url: "udp://:8089", decoder: "json", listen: true, connections: 10)`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromSocket0",
+						Spec: &socket.FromSocketOpSpec{
+							URL:         "udp://:8089",
+							Decoder:     "json",
+							Listen:      true,
+							Connections: 10,
+						},
+					},
+				},
+			},
+		},
 		{
 			Name: "from ok",
 			Raw: `import "socket"
@@ -205,6 +229,121 @@ source
 				},
 			},
 		},
+		{
+			name: "json",
+			spec: &socket.FromSocketProcedureSpec{Decoder: "json"},
+			input: `{"host": "a", "load": 0.5, "up": true}
+{"host": "b", "load": 1, "tags": {"dc": "west"}}
+{"_time": "2020-01-01T00:00:00Z", "host": null, "up": false}
+`,
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "host", Type: flux.TString},
+					{Label: "load", Type: flux.TFloat},
+					{Label: "tags", Type: flux.TString},
+					{Label: "up", Type: flux.TBool},
+				},
+				Data: [][]interface{}{
+					{execute.Time(0), "a", 0.5, nil, true},
+					{execute.Time(1), "b", 1.0, `{"dc":"west"}`, nil},
+					{execute.Time(1577836800000000000), nil, nil, nil, false},
+				},
+			}},
+		},
+		{
+			name: "prometheus",
+			spec: &socket.FromSocketProcedureSpec{Decoder: "prometheus"},
+			input: `# TYPE http_requests_total counter
+http_requests_total{code="200",method="post"} 1027 1395066363000
+http_requests_total{code="400",method="post"} 3 1395066363000
+# TYPE temperature gauge
+temperature 21.5
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.5"} 0.05
+rpc_duration_seconds_sum 17.5
+rpc_duration_seconds_count 100
+`,
+			want: []*executetest.Table{
+				{
+					KeyCols: []string{"_measurement", "_field", "code", "method"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "_field", Type: flux.TString},
+						{Label: "code", Type: flux.TString},
+						{Label: "method", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1395066363000000000), 1027.0, "prometheus", "http_requests_total", "200", "post"},
+					},
+				},
+				{
+					KeyCols: []string{"_measurement", "_field", "code", "method"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "_field", Type: flux.TString},
+						{Label: "code", Type: flux.TString},
+						{Label: "method", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1395066363000000000), 3.0, "prometheus", "http_requests_total", "400", "post"},
+					},
+				},
+				{
+					KeyCols: []string{"_measurement", "_field"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "_field", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(0), 100.0, "prometheus", "rpc_duration_seconds_count"},
+					},
+				},
+				{
+					KeyCols: []string{"_measurement", "_field"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "_field", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(0), 17.5, "prometheus", "rpc_duration_seconds_sum"},
+					},
+				},
+				{
+					KeyCols: []string{"_measurement", "_field", "quantile"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "_field", Type: flux.TString},
+						{Label: "quantile", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(0), 0.05, "prometheus", "rpc_duration_seconds", "0.5"},
+					},
+				},
+				{
+					KeyCols: []string{"_measurement", "_field"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_time", Type: flux.TTime},
+						{Label: "_value", Type: flux.TFloat},
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "_field", Type: flux.TString},
+					},
+					Data: [][]interface{}{
+						{execute.Time(1), 21.5, "prometheus", "temperature"},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -215,7 +354,7 @@ source
 			c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
 			c.SetTriggerSpec(plan.DefaultTriggerSpec)
 			r := ioutil.NopCloser(bytes.NewReader([]byte(tc.input)))
-			ss, err := socket.NewSocketSource(tc.spec, r, &mock.AscendingTimeProvider{}, id)
+			ss, err := socket.NewSocketSourceWithAllocator(tc.spec, r, &mock.AscendingTimeProvider{}, id, executetest.UnlimitedAllocator)
 			if err != nil {
 				t.Fatal(err)
 			}
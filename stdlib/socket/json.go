@@ -0,0 +1,174 @@
+package socket
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/line"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
+)
+
+// jsonDecoder decodes a stream of JSON objects, usually one per line, into a single table.
+// Each key of an object is a column of the table and each object is a row.
+// Strings, numbers and booleans become string, float and boolean columns.
+// Nested objects and arrays are kept as JSON encoded strings.
+// The _time column holds the RFC3339 time or the nanoseconds since the epoch
+// found under the _time key of an object, or the time the object was read otherwise.
+type jsonDecoder struct {
+	r     io.Reader
+	tp    line.TimeProvider
+	alloc *memory.Allocator
+}
+
+func newJSONDecoder(config DecoderConfig) flux.ResultDecoder {
+	return &jsonDecoder{tp: config.TimeProvider, alloc: config.allocator()}
+}
+
+func (d *jsonDecoder) Decode(r io.Reader) (flux.Result, error) {
+	d.r = r
+	return d, nil
+}
+
+func (*jsonDecoder) Name() string {
+	return "_result"
+}
+
+func (d *jsonDecoder) Tables() flux.TableIterator {
+	return d
+}
+
+func (d *jsonDecoder) Do(f func(flux.Table) error) error {
+	// Columns that only hold null values are string columns.
+	labels := make(map[string]bool)
+	types := map[string]flux.ColType{
+		execute.DefaultTimeColLabel: flux.TTime,
+	}
+	var rows []map[string]values.Value
+
+	dec := json.NewDecoder(d.r)
+	dec.UseNumber()
+	for {
+		var obj map[string]interface{}
+		if err := dec.Decode(&obj); err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.Wrap(err, codes.Invalid, "invalid json object")
+		}
+
+		row := make(map[string]values.Value, len(obj)+1)
+		for k, v := range obj {
+			value, err := d.convert(k, v)
+			if err != nil {
+				return err
+			}
+			labels[k] = true
+			row[k] = value
+			if value.IsNull() {
+				continue
+			}
+			typ := flux.ColumnType(value.Type())
+			if prev, ok := types[k]; ok && prev != typ {
+				return errors.Newf(codes.Invalid, "column %q has conflicting types %v and %v", k, prev, typ)
+			}
+			types[k] = typ
+		}
+		if v, ok := row[execute.DefaultTimeColLabel]; !ok || v.IsNull() {
+			row[execute.DefaultTimeColLabel] = values.NewTime(d.tp.CurrentTime())
+		}
+		rows = append(rows, row)
+	}
+
+	cols := []flux.ColMeta{{Label: execute.DefaultTimeColLabel, Type: flux.TTime}}
+	for label := range labels {
+		if label == execute.DefaultTimeColLabel {
+			continue
+		}
+		typ, ok := types[label]
+		if !ok {
+			typ = flux.TString
+		}
+		cols = append(cols, flux.ColMeta{Label: label, Type: typ})
+	}
+	sort.Slice(cols[1:], func(i, j int) bool {
+		return cols[i+1].Label < cols[j+1].Label
+	})
+
+	builder := execute.NewColListTableBuilder(execute.NewGroupKey(nil, nil), d.alloc)
+	for _, col := range cols {
+		if _, err := builder.AddCol(col); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		for j, col := range cols {
+			v, ok := row[col.Label]
+			if !ok || v.IsNull() {
+				if err := builder.AppendNil(j); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := builder.AppendValue(j, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	tbl, err := builder.Table()
+	if err != nil {
+		return err
+	}
+	return f(tbl)
+}
+
+// convert converts a decoded JSON value into a flux value.
+func (d *jsonDecoder) convert(key string, v interface{}) (values.Value, error) {
+	if key == execute.DefaultTimeColLabel {
+		switch v := v.(type) {
+		case string:
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return nil, errors.Wrapf(err, codes.Invalid, "invalid %s value %q", key, v)
+			}
+			return values.NewTime(values.ConvertTime(t)), nil
+		case nil:
+			return values.Null, nil
+		case json.Number:
+			ns, err := v.Int64()
+			if err != nil {
+				return nil, errors.Wrapf(err, codes.Invalid, "invalid %s value %s", key, v)
+			}
+			return values.NewTime(values.Time(ns)), nil
+		}
+		return nil, errors.Newf(codes.Invalid, "invalid %s value %v, must be a string or an integer", key, v)
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return values.Null, nil
+	case string:
+		return values.NewString(v), nil
+	case bool:
+		return values.NewBool(v), nil
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return nil, errors.Wrapf(err, codes.Invalid, "invalid number %s for %q", v, key)
+		}
+		return values.NewFloat(f), nil
+	default:
+		// Nested objects and arrays are kept as JSON.
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, codes.Invalid, "invalid value for %q", key)
+		}
+		return values.NewString(string(b)), nil
+	}
+}
@@ -0,0 +1,162 @@
+package socket
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+)
+
+// maxDatagramSize is the largest UDP datagram that can be received.
+const maxDatagramSize = 64 * 1024
+
+// listen binds to the address and returns a reader over the data received
+// by the first n connections accepted on it, one connection after the other.
+// Each UDP datagram counts as a connection. The data of every connection ends
+// with a newline so that the last message of one connection is not joined
+// with the first message of the next.
+func listen(network, address string, n int) (io.ReadCloser, error) {
+	if network == "udp" {
+		pc, err := net.ListenPacket(network, address)
+		if err != nil {
+			return nil, err
+		}
+		return &acceptReader{
+			accept: func() (io.ReadCloser, error) {
+				buf := make([]byte, maxDatagramSize)
+				n, _, err := pc.ReadFrom(buf)
+				if err != nil {
+					return nil, err
+				}
+				return ioutil.NopCloser(bytes.NewReader(buf[:n])), nil
+			},
+			listener:  pc,
+			remaining: n,
+		}, nil
+	}
+
+	l, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return &acceptReader{
+		accept: func() (io.ReadCloser, error) {
+			return l.Accept()
+		},
+		listener:  l,
+		remaining: n,
+	}, nil
+}
+
+// acceptReader reads from accepted connections as if they were a single stream.
+// It adds a newline after a connection whose data does not end with one and
+// returns io.EOF once the last connection it accepts is closed by the peer.
+type acceptReader struct {
+	accept    func() (io.ReadCloser, error)
+	listener  io.Closer
+	remaining int
+
+	// unterminated reports whether the data read so far from the current
+	// connection does not end with a newline.
+	unterminated bool
+	// newline reports whether a newline is owed for the previous connection.
+	newline bool
+
+	mu     sync.Mutex
+	conn   io.ReadCloser
+	closed bool
+}
+
+func (r *acceptReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if r.newline {
+		r.newline = false
+		p[0] = '\n'
+		return 1, nil
+	}
+	for {
+		conn, err := r.current()
+		if err != nil || conn == nil {
+			return 0, err
+		}
+		n, err := conn.Read(p)
+		if n > 0 {
+			r.unterminated = p[n-1] != '\n'
+		}
+		if err == io.EOF {
+			r.next()
+			if r.unterminated {
+				r.unterminated = false
+				if n < len(p) {
+					p[n] = '\n'
+					n++
+				} else {
+					r.newline = true
+				}
+			}
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+// current returns the connection to read from, accepting a new one when needed.
+// It returns a nil connection and io.EOF once every connection has been read.
+func (r *acceptReader) current() (io.ReadCloser, error) {
+	r.mu.Lock()
+	if r.conn != nil {
+		defer r.mu.Unlock()
+		return r.conn, nil
+	}
+	if r.remaining == 0 {
+		r.mu.Unlock()
+		return nil, io.EOF
+	}
+	r.mu.Unlock()
+
+	// Accept without holding the lock so Close can interrupt it.
+	conn, err := r.accept()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		conn.Close()
+		return nil, io.ErrClosedPipe
+	}
+	r.conn = conn
+	r.remaining--
+	return conn, nil
+}
+
+// next closes the current connection so that the following read accepts a new one.
+func (r *acceptReader) next() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conn != nil {
+		r.conn.Close()
+		r.conn = nil
+	}
+}
+
+func (r *acceptReader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	if r.conn != nil {
+		r.conn.Close()
+		r.conn = nil
+	}
+	return r.listener.Close()
+}
@@ -0,0 +1,209 @@
+package socket
+
+import (
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/line"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/values"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// prometheusDecoder decodes metrics in the Prometheus text exposition format.
+// It produces the same tables as prometheus.scrape: a table for each series
+// with the _measurement "prometheus", the metric name as the _field and
+// every label as a column of the group key.
+// Summaries and histograms are split into their _count, _sum
+// and quantile or bucket series.
+// Samples without a timestamp are given the time they were read.
+type prometheusDecoder struct {
+	r     io.Reader
+	tp    line.TimeProvider
+	alloc *memory.Allocator
+}
+
+func newPrometheusDecoder(config DecoderConfig) flux.ResultDecoder {
+	return &prometheusDecoder{tp: config.TimeProvider, alloc: config.allocator()}
+}
+
+func (d *prometheusDecoder) Decode(r io.Reader) (flux.Result, error) {
+	d.r = r
+	return d, nil
+}
+
+func (*prometheusDecoder) Name() string {
+	return "_result"
+}
+
+func (d *prometheusDecoder) Tables() flux.TableIterator {
+	return d
+}
+
+// promSample is a single value of a series.
+type promSample struct {
+	name   string
+	labels map[string]string
+	value  float64
+	time   values.Time
+}
+
+func (d *prometheusDecoder) Do(f func(flux.Table) error) error {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(d.r)
+	if err != nil {
+		return errors.Wrap(err, codes.Invalid, "invalid prometheus text format")
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var (
+		keys     []flux.GroupKey
+		builders = execute.NewGroupLookup()
+	)
+	for _, name := range names {
+		for _, s := range d.samples(families[name]) {
+			key, err := promGroupKey(s)
+			if err != nil {
+				return err
+			}
+			b, ok := builders.Lookup(key)
+			if !ok {
+				b, err = newPromTableBuilder(key, d.alloc)
+				if err != nil {
+					return err
+				}
+				builders.Set(key, b)
+				keys = append(keys, key)
+			}
+			if err := appendPromSample(b.(*execute.ColListTableBuilder), s); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, key := range keys {
+		b, _ := builders.Lookup(key)
+		tbl, err := b.(*execute.ColListTableBuilder).Table()
+		if err != nil {
+			return err
+		}
+		if err := f(tbl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// samples expands the metrics of a family into the samples of each series.
+func (d *prometheusDecoder) samples(family *dto.MetricFamily) []promSample {
+	name := family.GetName()
+	var samples []promSample
+	for _, m := range family.Metric {
+		labels := make(map[string]string, len(m.Label))
+		for _, l := range m.Label {
+			labels[l.GetName()] = l.GetValue()
+		}
+		var t values.Time
+		if m.TimestampMs != nil && *m.TimestampMs > 0 {
+			t = values.Time(*m.TimestampMs * 1e6)
+		} else {
+			t = d.tp.CurrentTime()
+		}
+		sample := func(name string, value float64, extra ...string) promSample {
+			ls := labels
+			if len(extra) > 0 {
+				ls = make(map[string]string, len(labels)+1)
+				for k, v := range labels {
+					ls[k] = v
+				}
+				ls[extra[0]] = extra[1]
+			}
+			return promSample{name: name, labels: ls, value: value, time: t}
+		}
+
+		switch family.GetType() {
+		case dto.MetricType_SUMMARY:
+			summary := m.GetSummary()
+			samples = append(samples,
+				sample(name+"_count", float64(summary.GetSampleCount())),
+				sample(name+"_sum", summary.GetSampleSum()),
+			)
+			for _, q := range summary.Quantile {
+				if math.IsNaN(q.GetValue()) {
+					continue
+				}
+				quantile := strconv.FormatFloat(q.GetQuantile(), 'g', -1, 64)
+				samples = append(samples, sample(name, q.GetValue(), "quantile", quantile))
+			}
+		case dto.MetricType_HISTOGRAM:
+			histogram := m.GetHistogram()
+			samples = append(samples,
+				sample(name+"_count", float64(histogram.GetSampleCount())),
+				sample(name+"_sum", histogram.GetSampleSum()),
+			)
+			for _, b := range histogram.Bucket {
+				le := strconv.FormatFloat(b.GetUpperBound(), 'g', -1, 64)
+				samples = append(samples, sample(name, float64(b.GetCumulativeCount()), "le", le))
+			}
+		case dto.MetricType_COUNTER:
+			samples = append(samples, sample(name, m.GetCounter().GetValue()))
+		case dto.MetricType_GAUGE:
+			samples = append(samples, sample(name, m.GetGauge().GetValue()))
+		default:
+			samples = append(samples, sample(name, m.GetUntyped().GetValue()))
+		}
+	}
+	return samples
+}
+
+func promGroupKey(s promSample) (flux.GroupKey, error) {
+	labels := make([]string, 0, len(s.labels))
+	for label := range s.labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	kb := execute.NewGroupKeyBuilder(nil)
+	kb.AddKeyValue("_measurement", values.NewString("prometheus"))
+	kb.AddKeyValue("_field", values.NewString(s.name))
+	for _, label := range labels {
+		kb.AddKeyValue(label, values.NewString(s.labels[label]))
+	}
+	return kb.Build()
+}
+
+func newPromTableBuilder(key flux.GroupKey, alloc *memory.Allocator) (*execute.ColListTableBuilder, error) {
+	b := execute.NewColListTableBuilder(key, alloc)
+	cols := append([]flux.ColMeta{
+		{Label: "_time", Type: flux.TTime},
+		{Label: "_value", Type: flux.TFloat},
+	}, key.Cols()...)
+	for _, col := range cols {
+		if _, err := b.AddCol(col); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func appendPromSample(b *execute.ColListTableBuilder, s promSample) error {
+	if err := b.AppendTime(0, s.time); err != nil {
+		return err
+	}
+	if err := b.AppendFloat(1, s.value); err != nil {
+		return err
+	}
+	return execute.AppendKeyValues(b.Key(), b)
+}
@@ -21,11 +21,11 @@ var pkgAST = &ast.Package{
 			Errors: nil,
 			Loc: &ast.SourceLocation{
 				End: ast.Position{
-					Column: 11,
-					Line:   4,
+					Column: 16,
+					Line:   19,
 				},
 				File:   "sql.flux",
-				Source: "package sql\n\nbuiltin from : (driverName: string, dataSourceName: string, query: string) => [A]\nbuiltin to",
+				Source: "package sql\n\n// from returns the rows of a query as a table. The params are bound in order\n// to the placeholders of the query. The elements of a Flux array all have the\n// same type, so params of different types are passed as strings and converted\n// by the query, for example with CAST(? AS INTEGER).\nbuiltin from : (driverName: string, dataSourceName: string, query: string, ?params: [B]) => [A]\nbuiltin to : (\n    <-tables: [A],\n    driverName: string,\n    dataSourceName: string,\n    table: string,\n    ?batchSize: int,\n    ?mode: string,\n    ?keyColumns: [string],\n    ?createTable: bool\n) => [A]\nbuiltin tables : (driverName: string, dataSourceName: string) => [A]\nbuiltin columns",
 				Start: ast.Position{
 					Column: 1,
 					Line:   1,
@@ -38,13 +38,13 @@ var pkgAST = &ast.Package{
 				Loc: &ast.SourceLocation{
 					End: ast.Position{
 						Column: 13,
-						Line:   3,
+						Line:   7,
 					},
 					File:   "sql.flux",
 					Source: "builtin from",
 					Start: ast.Position{
 						Column: 1,
-						Line:   3,
+						Line:   7,
 					},
 				},
 			},
@@ -54,13 +54,13 @@ var pkgAST = &ast.Package{
 					Loc: &ast.SourceLocation{
 						End: ast.Position{
 							Column: 13,
-							Line:   3,
+							Line:   7,
 						},
 						File:   "sql.flux",
 						Source: "from",
 						Start: ast.Position{
 							Column: 9,
-							Line:   3,
+							Line:   7,
 						},
 					},
 				},
@@ -71,14 +71,14 @@ var pkgAST = &ast.Package{
 					Errors: nil,
 					Loc: &ast.SourceLocation{
 						End: ast.Position{
-							Column: 82,
-							Line:   3,
+							Column: 96,
+							Line:   7,
 						},
 						File:   "sql.flux",
-						Source: "(driverName: string, dataSourceName: string, query: string) => [A]",
+						Source: "(driverName: string, dataSourceName: string, query: string, ?params: [B]) => [A]",
 						Start: ast.Position{
 							Column: 16,
-							Line:   3,
+							Line:   7,
 						},
 					},
 				},
@@ -88,14 +88,14 @@ var pkgAST = &ast.Package{
 						Errors: nil,
 						Loc: &ast.SourceLocation{
 							End: ast.Position{
-								Column: 82,
-								Line:   3,
+								Column: 96,
+								Line:   7,
 							},
 							File:   "sql.flux",
-							Source: "(driverName: string, dataSourceName: string, query: string) => [A]",
+							Source: "(driverName: string, dataSourceName: string, query: string, ?params: [B]) => [A]",
 							Start: ast.Position{
 								Column: 16,
-								Line:   3,
+								Line:   7,
 							},
 						},
 					},
@@ -105,13 +105,13 @@ var pkgAST = &ast.Package{
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
 									Column: 35,
-									Line:   3,
+									Line:   7,
 								},
 								File:   "sql.flux",
 								Source: "driverName: string",
 								Start: ast.Position{
 									Column: 17,
-									Line:   3,
+									Line:   7,
 								},
 							},
 						},
@@ -122,13 +122,13 @@ var pkgAST = &ast.Package{
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
 										Column: 27,
-										Line:   3,
+										Line:   7,
 									},
 									File:   "sql.flux",
 									Source: "driverName",
 									Start: ast.Position{
 										Column: 17,
-										Line:   3,
+										Line:   7,
 									},
 								},
 							},
@@ -140,13 +140,13 @@ var pkgAST = &ast.Package{
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
 										Column: 35,
-										Line:   3,
+										Line:   7,
 									},
 									File:   "sql.flux",
 									Source: "string",
 									Start: ast.Position{
 										Column: 29,
-										Line:   3,
+										Line:   7,
 									},
 								},
 							},
@@ -156,13 +156,13 @@ var pkgAST = &ast.Package{
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
 											Column: 35,
-											Line:   3,
+											Line:   7,
 										},
 										File:   "sql.flux",
 										Source: "string",
 										Start: ast.Position{
 											Column: 29,
-											Line:   3,
+											Line:   7,
 										},
 									},
 								},
@@ -175,13 +175,13 @@ var pkgAST = &ast.Package{
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
 									Column: 59,
-									Line:   3,
+									Line:   7,
 								},
 								File:   "sql.flux",
 								Source: "dataSourceName: string",
 								Start: ast.Position{
 									Column: 37,
-									Line:   3,
+									Line:   7,
 								},
 							},
 						},
@@ -192,13 +192,13 @@ var pkgAST = &ast.Package{
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
 										Column: 51,
-										Line:   3,
+										Line:   7,
 									},
 									File:   "sql.flux",
 									Source: "dataSourceName",
 									Start: ast.Position{
 										Column: 37,
-										Line:   3,
+										Line:   7,
 									},
 								},
 							},
@@ -210,13 +210,13 @@ var pkgAST = &ast.Package{
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
 										Column: 59,
-										Line:   3,
+										Line:   7,
 									},
 									File:   "sql.flux",
 									Source: "string",
 									Start: ast.Position{
 										Column: 53,
-										Line:   3,
+										Line:   7,
 									},
 								},
 							},
@@ -226,13 +226,13 @@ var pkgAST = &ast.Package{
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
 											Column: 59,
-											Line:   3,
+											Line:   7,
 										},
 										File:   "sql.flux",
 										Source: "string",
 										Start: ast.Position{
 											Column: 53,
-											Line:   3,
+											Line:   7,
 										},
 									},
 								},
@@ -245,13 +245,13 @@ var pkgAST = &ast.Package{
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
 									Column: 74,
-									Line:   3,
+									Line:   7,
 								},
 								File:   "sql.flux",
 								Source: "query: string",
 								Start: ast.Position{
 									Column: 61,
-									Line:   3,
+									Line:   7,
 								},
 							},
 						},
@@ -262,13 +262,13 @@ var pkgAST = &ast.Package{
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
 										Column: 66,
-										Line:   3,
+										Line:   7,
 									},
 									File:   "sql.flux",
 									Source: "query",
 									Start: ast.Position{
 										Column: 61,
-										Line:   3,
+										Line:   7,
 									},
 								},
 							},
@@ -280,13 +280,13 @@ var pkgAST = &ast.Package{
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
 										Column: 74,
-										Line:   3,
+										Line:   7,
 									},
 									File:   "sql.flux",
 									Source: "string",
 									Start: ast.Position{
 										Column: 68,
-										Line:   3,
+										Line:   7,
 									},
 								},
 							},
@@ -296,33 +296,120 @@ var pkgAST = &ast.Package{
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
 											Column: 74,
-											Line:   3,
+											Line:   7,
 										},
 										File:   "sql.flux",
 										Source: "string",
 										Start: ast.Position{
 											Column: 68,
-											Line:   3,
+											Line:   7,
 										},
 									},
 								},
 								Name: "string",
 							},
 						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 88,
+									Line:   7,
+								},
+								File:   "sql.flux",
+								Source: "?params: [B]",
+								Start: ast.Position{
+									Column: 76,
+									Line:   7,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 83,
+										Line:   7,
+									},
+									File:   "sql.flux",
+									Source: "params",
+									Start: ast.Position{
+										Column: 77,
+										Line:   7,
+									},
+								},
+							},
+							Name: "params",
+						},
+						Ty: &ast.ArrayType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 88,
+										Line:   7,
+									},
+									File:   "sql.flux",
+									Source: "[B]",
+									Start: ast.Position{
+										Column: 85,
+										Line:   7,
+									},
+								},
+							},
+							ElementType: &ast.TvarType{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 87,
+											Line:   7,
+										},
+										File:   "sql.flux",
+										Source: "B",
+										Start: ast.Position{
+											Column: 86,
+											Line:   7,
+										},
+									},
+								},
+								ID: &ast.Identifier{
+									BaseNode: ast.BaseNode{
+										Errors: nil,
+										Loc: &ast.SourceLocation{
+											End: ast.Position{
+												Column: 87,
+												Line:   7,
+											},
+											File:   "sql.flux",
+											Source: "B",
+											Start: ast.Position{
+												Column: 86,
+												Line:   7,
+											},
+										},
+									},
+									Name: "B",
+								},
+							},
+						},
 					}},
 					Return: &ast.ArrayType{
 						BaseNode: ast.BaseNode{
 							Errors: nil,
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
-									Column: 82,
-									Line:   3,
+									Column: 96,
+									Line:   7,
 								},
 								File:   "sql.flux",
 								Source: "[A]",
 								Start: ast.Position{
-									Column: 79,
-									Line:   3,
+									Column: 93,
+									Line:   7,
 								},
 							},
 						},
@@ -331,14 +418,14 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 81,
-										Line:   3,
+										Column: 95,
+										Line:   7,
 									},
 									File:   "sql.flux",
 									Source: "A",
 									Start: ast.Position{
-										Column: 80,
-										Line:   3,
+										Column: 94,
+										Line:   7,
 									},
 								},
 							},
@@ -347,14 +434,14 @@ var pkgAST = &ast.Package{
 									Errors: nil,
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
-											Column: 81,
-											Line:   3,
+											Column: 95,
+											Line:   7,
 										},
 										File:   "sql.flux",
 										Source: "A",
 										Start: ast.Position{
-											Column: 80,
-											Line:   3,
+											Column: 94,
+											Line:   7,
 										},
 									},
 								},
@@ -370,13 +457,13 @@ var pkgAST = &ast.Package{
 				Loc: &ast.SourceLocation{
 					End: ast.Position{
 						Column: 11,
-						Line:   4,
+						Line:   8,
 					},
 					File:   "sql.flux",
 					Source: "builtin to",
 					Start: ast.Position{
 						Column: 1,
-						Line:   4,
+						Line:   8,
 					},
 				},
 			},
@@ -386,13 +473,13 @@ var pkgAST = &ast.Package{
 					Loc: &ast.SourceLocation{
 						End: ast.Position{
 							Column: 11,
-							Line:   4,
+							Line:   8,
 						},
 						File:   "sql.flux",
 						Source: "to",
 						Start: ast.Position{
 							Column: 9,
-							Line:   4,
+							Line:   8,
 						},
 					},
 				},
@@ -403,14 +490,14 @@ var pkgAST = &ast.Package{
 					Errors: nil,
 					Loc: &ast.SourceLocation{
 						End: ast.Position{
-							Column: 112,
-							Line:   4,
+							Column: 9,
+							Line:   17,
 						},
 						File:   "sql.flux",
-						Source: "(<-tables: [A], driverName: string, dataSourceName: string, table: string, ?batchSize: int) => [A]",
+						Source: "(\n    <-tables: [A],\n    driverName: string,\n    dataSourceName: string,\n    table: string,\n    ?batchSize: int,\n    ?mode: string,\n    ?keyColumns: [string],\n    ?createTable: bool\n) => [A]",
 						Start: ast.Position{
 							Column: 14,
-							Line:   4,
+							Line:   8,
 						},
 					},
 				},
@@ -420,14 +507,14 @@ var pkgAST = &ast.Package{
 						Errors: nil,
 						Loc: &ast.SourceLocation{
 							End: ast.Position{
-								Column: 112,
-								Line:   4,
+								Column: 9,
+								Line:   17,
 							},
 							File:   "sql.flux",
-							Source: "(<-tables: [A], driverName: string, dataSourceName: string, table: string, ?batchSize: int) => [A]",
+							Source: "(\n    <-tables: [A],\n    driverName: string,\n    dataSourceName: string,\n    table: string,\n    ?batchSize: int,\n    ?mode: string,\n    ?keyColumns: [string],\n    ?createTable: bool\n) => [A]",
 							Start: ast.Position{
 								Column: 14,
-								Line:   4,
+								Line:   8,
 							},
 						},
 					},
@@ -436,14 +523,14 @@ var pkgAST = &ast.Package{
 							Errors: nil,
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
-									Column: 28,
-									Line:   4,
+									Column: 18,
+									Line:   9,
 								},
 								File:   "sql.flux",
 								Source: "<-tables: [A]",
 								Start: ast.Position{
-									Column: 15,
-									Line:   4,
+									Column: 5,
+									Line:   9,
 								},
 							},
 						},
@@ -453,14 +540,14 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 23,
-										Line:   4,
+										Column: 13,
+										Line:   9,
 									},
 									File:   "sql.flux",
 									Source: "tables",
 									Start: ast.Position{
-										Column: 17,
-										Line:   4,
+										Column: 7,
+										Line:   9,
 									},
 								},
 							},
@@ -471,14 +558,14 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 28,
-										Line:   4,
+										Column: 18,
+										Line:   9,
 									},
 									File:   "sql.flux",
 									Source: "[A]",
 									Start: ast.Position{
-										Column: 25,
-										Line:   4,
+										Column: 15,
+										Line:   9,
 									},
 								},
 							},
@@ -487,14 +574,14 @@ var pkgAST = &ast.Package{
 									Errors: nil,
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
-											Column: 27,
-											Line:   4,
+											Column: 17,
+											Line:   9,
 										},
 										File:   "sql.flux",
 										Source: "A",
 										Start: ast.Position{
-											Column: 26,
-											Line:   4,
+											Column: 16,
+											Line:   9,
 										},
 									},
 								},
@@ -503,14 +590,14 @@ var pkgAST = &ast.Package{
 										Errors: nil,
 										Loc: &ast.SourceLocation{
 											End: ast.Position{
-												Column: 27,
-												Line:   4,
+												Column: 17,
+												Line:   9,
 											},
 											File:   "sql.flux",
 											Source: "A",
 											Start: ast.Position{
-												Column: 26,
-												Line:   4,
+												Column: 16,
+												Line:   9,
 											},
 										},
 									},
@@ -523,14 +610,14 @@ var pkgAST = &ast.Package{
 							Errors: nil,
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
-									Column: 48,
-									Line:   4,
+									Column: 23,
+									Line:   10,
 								},
 								File:   "sql.flux",
 								Source: "driverName: string",
 								Start: ast.Position{
-									Column: 30,
-									Line:   4,
+									Column: 5,
+									Line:   10,
 								},
 							},
 						},
@@ -540,14 +627,14 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 40,
-										Line:   4,
+										Column: 15,
+										Line:   10,
 									},
 									File:   "sql.flux",
 									Source: "driverName",
 									Start: ast.Position{
-										Column: 30,
-										Line:   4,
+										Column: 5,
+										Line:   10,
 									},
 								},
 							},
@@ -558,14 +645,14 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 48,
-										Line:   4,
+										Column: 23,
+										Line:   10,
 									},
 									File:   "sql.flux",
 									Source: "string",
 									Start: ast.Position{
-										Column: 42,
-										Line:   4,
+										Column: 17,
+										Line:   10,
 									},
 								},
 							},
@@ -574,14 +661,14 @@ var pkgAST = &ast.Package{
 									Errors: nil,
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
-											Column: 48,
-											Line:   4,
+											Column: 23,
+											Line:   10,
 										},
 										File:   "sql.flux",
 										Source: "string",
 										Start: ast.Position{
-											Column: 42,
-											Line:   4,
+											Column: 17,
+											Line:   10,
 										},
 									},
 								},
@@ -593,14 +680,14 @@ var pkgAST = &ast.Package{
 							Errors: nil,
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
-									Column: 72,
-									Line:   4,
+									Column: 27,
+									Line:   11,
 								},
 								File:   "sql.flux",
 								Source: "dataSourceName: string",
 								Start: ast.Position{
-									Column: 50,
-									Line:   4,
+									Column: 5,
+									Line:   11,
 								},
 							},
 						},
@@ -610,14 +697,14 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 64,
-										Line:   4,
+										Column: 19,
+										Line:   11,
 									},
 									File:   "sql.flux",
 									Source: "dataSourceName",
 									Start: ast.Position{
-										Column: 50,
-										Line:   4,
+										Column: 5,
+										Line:   11,
 									},
 								},
 							},
@@ -628,14 +715,14 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 72,
-										Line:   4,
+										Column: 27,
+										Line:   11,
 									},
 									File:   "sql.flux",
 									Source: "string",
 									Start: ast.Position{
-										Column: 66,
-										Line:   4,
+										Column: 21,
+										Line:   11,
 									},
 								},
 							},
@@ -644,14 +731,14 @@ var pkgAST = &ast.Package{
 									Errors: nil,
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
-											Column: 72,
-											Line:   4,
+											Column: 27,
+											Line:   11,
 										},
 										File:   "sql.flux",
 										Source: "string",
 										Start: ast.Position{
-											Column: 66,
-											Line:   4,
+											Column: 21,
+											Line:   11,
 										},
 									},
 								},
@@ -663,14 +750,14 @@ var pkgAST = &ast.Package{
 							Errors: nil,
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
-									Column: 87,
-									Line:   4,
+									Column: 18,
+									Line:   12,
 								},
 								File:   "sql.flux",
 								Source: "table: string",
 								Start: ast.Position{
-									Column: 74,
-									Line:   4,
+									Column: 5,
+									Line:   12,
 								},
 							},
 						},
@@ -680,14 +767,14 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 79,
-										Line:   4,
+										Column: 10,
+										Line:   12,
 									},
 									File:   "sql.flux",
 									Source: "table",
 									Start: ast.Position{
-										Column: 74,
-										Line:   4,
+										Column: 5,
+										Line:   12,
 									},
 								},
 							},
@@ -698,14 +785,14 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 87,
-										Line:   4,
+										Column: 18,
+										Line:   12,
 									},
 									File:   "sql.flux",
 									Source: "string",
 									Start: ast.Position{
-										Column: 81,
-										Line:   4,
+										Column: 12,
+										Line:   12,
 									},
 								},
 							},
@@ -714,14 +801,14 @@ var pkgAST = &ast.Package{
 									Errors: nil,
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
-											Column: 87,
-											Line:   4,
+											Column: 18,
+											Line:   12,
 										},
 										File:   "sql.flux",
 										Source: "string",
 										Start: ast.Position{
-											Column: 81,
-											Line:   4,
+											Column: 12,
+											Line:   12,
 										},
 									},
 								},
@@ -733,14 +820,14 @@ var pkgAST = &ast.Package{
 							Errors: nil,
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
-									Column: 104,
-									Line:   4,
+									Column: 20,
+									Line:   13,
 								},
 								File:   "sql.flux",
 								Source: "?batchSize: int",
 								Start: ast.Position{
-									Column: 89,
-									Line:   4,
+									Column: 5,
+									Line:   13,
 								},
 							},
 						},
@@ -750,14 +837,14 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 99,
-										Line:   4,
+										Column: 15,
+										Line:   13,
 									},
 									File:   "sql.flux",
 									Source: "batchSize",
 									Start: ast.Position{
-										Column: 90,
-										Line:   4,
+										Column: 6,
+										Line:   13,
 									},
 								},
 							},
@@ -768,14 +855,14 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 104,
-										Line:   4,
+										Column: 20,
+										Line:   13,
 									},
 									File:   "sql.flux",
 									Source: "int",
 									Start: ast.Position{
-										Column: 101,
-										Line:   4,
+										Column: 17,
+										Line:   13,
 									},
 								},
 							},
@@ -784,34 +871,855 @@ var pkgAST = &ast.Package{
 									Errors: nil,
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
-											Column: 104,
-											Line:   4,
+											Column: 20,
+											Line:   13,
 										},
 										File:   "sql.flux",
 										Source: "int",
 										Start: ast.Position{
-											Column: 101,
-											Line:   4,
+											Column: 17,
+											Line:   13,
 										},
 									},
 								},
 								Name: "int",
 							},
 						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 18,
+									Line:   14,
+								},
+								File:   "sql.flux",
+								Source: "?mode: string",
+								Start: ast.Position{
+									Column: 5,
+									Line:   14,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 10,
+										Line:   14,
+									},
+									File:   "sql.flux",
+									Source: "mode",
+									Start: ast.Position{
+										Column: 6,
+										Line:   14,
+									},
+								},
+							},
+							Name: "mode",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 18,
+										Line:   14,
+									},
+									File:   "sql.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 12,
+										Line:   14,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 18,
+											Line:   14,
+										},
+										File:   "sql.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 12,
+											Line:   14,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 26,
+									Line:   15,
+								},
+								File:   "sql.flux",
+								Source: "?keyColumns: [string]",
+								Start: ast.Position{
+									Column: 5,
+									Line:   15,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 16,
+										Line:   15,
+									},
+									File:   "sql.flux",
+									Source: "keyColumns",
+									Start: ast.Position{
+										Column: 6,
+										Line:   15,
+									},
+								},
+							},
+							Name: "keyColumns",
+						},
+						Ty: &ast.ArrayType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 26,
+										Line:   15,
+									},
+									File:   "sql.flux",
+									Source: "[string]",
+									Start: ast.Position{
+										Column: 18,
+										Line:   15,
+									},
+								},
+							},
+							ElementType: &ast.NamedType{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 25,
+											Line:   15,
+										},
+										File:   "sql.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 19,
+											Line:   15,
+										},
+									},
+								},
+								ID: &ast.Identifier{
+									BaseNode: ast.BaseNode{
+										Errors: nil,
+										Loc: &ast.SourceLocation{
+											End: ast.Position{
+												Column: 25,
+												Line:   15,
+											},
+											File:   "sql.flux",
+											Source: "string",
+											Start: ast.Position{
+												Column: 19,
+												Line:   15,
+											},
+										},
+									},
+									Name: "string",
+								},
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 23,
+									Line:   16,
+								},
+								File:   "sql.flux",
+								Source: "?createTable: bool",
+								Start: ast.Position{
+									Column: 5,
+									Line:   16,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 17,
+										Line:   16,
+									},
+									File:   "sql.flux",
+									Source: "createTable",
+									Start: ast.Position{
+										Column: 6,
+										Line:   16,
+									},
+								},
+							},
+							Name: "createTable",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 23,
+										Line:   16,
+									},
+									File:   "sql.flux",
+									Source: "bool",
+									Start: ast.Position{
+										Column: 19,
+										Line:   16,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 23,
+											Line:   16,
+										},
+										File:   "sql.flux",
+										Source: "bool",
+										Start: ast.Position{
+											Column: 19,
+											Line:   16,
+										},
+									},
+								},
+								Name: "bool",
+							},
+						},
+					}},
+					Return: &ast.ArrayType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 9,
+									Line:   17,
+								},
+								File:   "sql.flux",
+								Source: "[A]",
+								Start: ast.Position{
+									Column: 6,
+									Line:   17,
+								},
+							},
+						},
+						ElementType: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 8,
+										Line:   17,
+									},
+									File:   "sql.flux",
+									Source: "A",
+									Start: ast.Position{
+										Column: 7,
+										Line:   17,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 8,
+											Line:   17,
+										},
+										File:   "sql.flux",
+										Source: "A",
+										Start: ast.Position{
+											Column: 7,
+											Line:   17,
+										},
+									},
+								},
+								Name: "A",
+							},
+						},
+					},
+				},
+			},
+		}, &ast.BuiltinStatement{
+			BaseNode: ast.BaseNode{
+				Errors: nil,
+				Loc: &ast.SourceLocation{
+					End: ast.Position{
+						Column: 15,
+						Line:   18,
+					},
+					File:   "sql.flux",
+					Source: "builtin tables",
+					Start: ast.Position{
+						Column: 1,
+						Line:   18,
+					},
+				},
+			},
+			ID: &ast.Identifier{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 15,
+							Line:   18,
+						},
+						File:   "sql.flux",
+						Source: "tables",
+						Start: ast.Position{
+							Column: 9,
+							Line:   18,
+						},
+					},
+				},
+				Name: "tables",
+			},
+			Ty: ast.TypeExpression{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 69,
+							Line:   18,
+						},
+						File:   "sql.flux",
+						Source: "(driverName: string, dataSourceName: string) => [A]",
+						Start: ast.Position{
+							Column: 18,
+							Line:   18,
+						},
+					},
+				},
+				Constraints: []*ast.TypeConstraint{},
+				Ty: &ast.FunctionType{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 69,
+								Line:   18,
+							},
+							File:   "sql.flux",
+							Source: "(driverName: string, dataSourceName: string) => [A]",
+							Start: ast.Position{
+								Column: 18,
+								Line:   18,
+							},
+						},
+					},
+					Parameters: []*ast.ParameterType{&ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 37,
+									Line:   18,
+								},
+								File:   "sql.flux",
+								Source: "driverName: string",
+								Start: ast.Position{
+									Column: 19,
+									Line:   18,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 29,
+										Line:   18,
+									},
+									File:   "sql.flux",
+									Source: "driverName",
+									Start: ast.Position{
+										Column: 19,
+										Line:   18,
+									},
+								},
+							},
+							Name: "driverName",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 37,
+										Line:   18,
+									},
+									File:   "sql.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 31,
+										Line:   18,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 37,
+											Line:   18,
+										},
+										File:   "sql.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 31,
+											Line:   18,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 61,
+									Line:   18,
+								},
+								File:   "sql.flux",
+								Source: "dataSourceName: string",
+								Start: ast.Position{
+									Column: 39,
+									Line:   18,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 53,
+										Line:   18,
+									},
+									File:   "sql.flux",
+									Source: "dataSourceName",
+									Start: ast.Position{
+										Column: 39,
+										Line:   18,
+									},
+								},
+							},
+							Name: "dataSourceName",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 61,
+										Line:   18,
+									},
+									File:   "sql.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 55,
+										Line:   18,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 61,
+											Line:   18,
+										},
+										File:   "sql.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 55,
+											Line:   18,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}},
+					Return: &ast.ArrayType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 69,
+									Line:   18,
+								},
+								File:   "sql.flux",
+								Source: "[A]",
+								Start: ast.Position{
+									Column: 66,
+									Line:   18,
+								},
+							},
+						},
+						ElementType: &ast.TvarType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 68,
+										Line:   18,
+									},
+									File:   "sql.flux",
+									Source: "A",
+									Start: ast.Position{
+										Column: 67,
+										Line:   18,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 68,
+											Line:   18,
+										},
+										File:   "sql.flux",
+										Source: "A",
+										Start: ast.Position{
+											Column: 67,
+											Line:   18,
+										},
+									},
+								},
+								Name: "A",
+							},
+						},
+					},
+				},
+			},
+		}, &ast.BuiltinStatement{
+			BaseNode: ast.BaseNode{
+				Errors: nil,
+				Loc: &ast.SourceLocation{
+					End: ast.Position{
+						Column: 16,
+						Line:   19,
+					},
+					File:   "sql.flux",
+					Source: "builtin columns",
+					Start: ast.Position{
+						Column: 1,
+						Line:   19,
+					},
+				},
+			},
+			ID: &ast.Identifier{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 16,
+							Line:   19,
+						},
+						File:   "sql.flux",
+						Source: "columns",
+						Start: ast.Position{
+							Column: 9,
+							Line:   19,
+						},
+					},
+				},
+				Name: "columns",
+			},
+			Ty: ast.TypeExpression{
+				BaseNode: ast.BaseNode{
+					Errors: nil,
+					Loc: &ast.SourceLocation{
+						End: ast.Position{
+							Column: 86,
+							Line:   19,
+						},
+						File:   "sql.flux",
+						Source: "(driverName: string, dataSourceName: string, ?table: string) => [A]",
+						Start: ast.Position{
+							Column: 19,
+							Line:   19,
+						},
+					},
+				},
+				Constraints: []*ast.TypeConstraint{},
+				Ty: &ast.FunctionType{
+					BaseNode: ast.BaseNode{
+						Errors: nil,
+						Loc: &ast.SourceLocation{
+							End: ast.Position{
+								Column: 86,
+								Line:   19,
+							},
+							File:   "sql.flux",
+							Source: "(driverName: string, dataSourceName: string, ?table: string) => [A]",
+							Start: ast.Position{
+								Column: 19,
+								Line:   19,
+							},
+						},
+					},
+					Parameters: []*ast.ParameterType{&ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 38,
+									Line:   19,
+								},
+								File:   "sql.flux",
+								Source: "driverName: string",
+								Start: ast.Position{
+									Column: 20,
+									Line:   19,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 30,
+										Line:   19,
+									},
+									File:   "sql.flux",
+									Source: "driverName",
+									Start: ast.Position{
+										Column: 20,
+										Line:   19,
+									},
+								},
+							},
+							Name: "driverName",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 38,
+										Line:   19,
+									},
+									File:   "sql.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 32,
+										Line:   19,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 38,
+											Line:   19,
+										},
+										File:   "sql.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 32,
+											Line:   19,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 62,
+									Line:   19,
+								},
+								File:   "sql.flux",
+								Source: "dataSourceName: string",
+								Start: ast.Position{
+									Column: 40,
+									Line:   19,
+								},
+							},
+						},
+						Kind: "Required",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 54,
+										Line:   19,
+									},
+									File:   "sql.flux",
+									Source: "dataSourceName",
+									Start: ast.Position{
+										Column: 40,
+										Line:   19,
+									},
+								},
+							},
+							Name: "dataSourceName",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 62,
+										Line:   19,
+									},
+									File:   "sql.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 56,
+										Line:   19,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 62,
+											Line:   19,
+										},
+										File:   "sql.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 56,
+											Line:   19,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
+					}, &ast.ParameterType{
+						BaseNode: ast.BaseNode{
+							Errors: nil,
+							Loc: &ast.SourceLocation{
+								End: ast.Position{
+									Column: 78,
+									Line:   19,
+								},
+								File:   "sql.flux",
+								Source: "?table: string",
+								Start: ast.Position{
+									Column: 64,
+									Line:   19,
+								},
+							},
+						},
+						Kind: "Optional",
+						Name: &ast.Identifier{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 70,
+										Line:   19,
+									},
+									File:   "sql.flux",
+									Source: "table",
+									Start: ast.Position{
+										Column: 65,
+										Line:   19,
+									},
+								},
+							},
+							Name: "table",
+						},
+						Ty: &ast.NamedType{
+							BaseNode: ast.BaseNode{
+								Errors: nil,
+								Loc: &ast.SourceLocation{
+									End: ast.Position{
+										Column: 78,
+										Line:   19,
+									},
+									File:   "sql.flux",
+									Source: "string",
+									Start: ast.Position{
+										Column: 72,
+										Line:   19,
+									},
+								},
+							},
+							ID: &ast.Identifier{
+								BaseNode: ast.BaseNode{
+									Errors: nil,
+									Loc: &ast.SourceLocation{
+										End: ast.Position{
+											Column: 78,
+											Line:   19,
+										},
+										File:   "sql.flux",
+										Source: "string",
+										Start: ast.Position{
+											Column: 72,
+											Line:   19,
+										},
+									},
+								},
+								Name: "string",
+							},
+						},
 					}},
 					Return: &ast.ArrayType{
 						BaseNode: ast.BaseNode{
 							Errors: nil,
 							Loc: &ast.SourceLocation{
 								End: ast.Position{
-									Column: 112,
-									Line:   4,
+									Column: 86,
+									Line:   19,
 								},
 								File:   "sql.flux",
 								Source: "[A]",
 								Start: ast.Position{
-									Column: 109,
-									Line:   4,
+									Column: 83,
+									Line:   19,
 								},
 							},
 						},
@@ -820,14 +1728,14 @@ var pkgAST = &ast.Package{
 								Errors: nil,
 								Loc: &ast.SourceLocation{
 									End: ast.Position{
-										Column: 111,
-										Line:   4,
+										Column: 85,
+										Line:   19,
 									},
 									File:   "sql.flux",
 									Source: "A",
 									Start: ast.Position{
-										Column: 110,
-										Line:   4,
+										Column: 84,
+										Line:   19,
 									},
 								},
 							},
@@ -836,14 +1744,14 @@ var pkgAST = &ast.Package{
 									Errors: nil,
 									Loc: &ast.SourceLocation{
 										End: ast.Position{
-											Column: 111,
-											Line:   4,
+											Column: 85,
+											Line:   19,
 										},
 										File:   "sql.flux",
 										Source: "A",
 										Start: ast.Position{
-											Column: 110,
-											Line:   4,
+											Column: 84,
+											Line:   19,
 										},
 									},
 								},
@@ -6,11 +6,13 @@ import (
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/url"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
 	_ "github.com/lib/pq"
 )
 
@@ -20,9 +22,10 @@ const FromSQLKind = "fromSQL"
 const layout = "2006-01-02 15:04:05.999999999"
 
 type FromSQLOpSpec struct {
-	DriverName     string `json:"driverName,omitempty"`
-	DataSourceName string `json:"dataSourceName,omitempty"`
-	Query          string `json:"query,omitempty"`
+	DriverName     string        `json:"driverName,omitempty"`
+	DataSourceName string        `json:"dataSourceName,omitempty"`
+	Query          string        `json:"query,omitempty"`
+	Params         []interface{} `json:"params,omitempty"`
 }
 
 func init() {
@@ -51,6 +54,16 @@ func createFromSQLOpSpec(args flux.Arguments, administration *flux.Administratio
 	} else {
 		spec.Query = query
 	}
+	if v, ok := args.Get("params"); ok {
+		if v.Type().Nature() != semantic.Array {
+			return nil, errors.Newf(codes.Invalid, "keyword argument %q should be an array, but got %v", "params", v.Type())
+		}
+		params, err := queryParams(v.Array())
+		if err != nil {
+			return nil, err
+		}
+		spec.Params = params
+	}
 	return spec, nil
 }
 
@@ -67,6 +80,7 @@ type FromSQLProcedureSpec struct {
 	DriverName     string
 	DataSourceName string
 	Query          string
+	Params         []interface{}
 }
 
 func newFromSQLProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
@@ -79,6 +93,7 @@ func newFromSQLProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.Pr
 		DriverName:     spec.DriverName,
 		DataSourceName: spec.DataSourceName,
 		Query:          spec.Query,
+		Params:         spec.Params,
 	}, nil
 }
 
@@ -91,6 +106,10 @@ func (s *FromSQLProcedureSpec) Copy() plan.ProcedureSpec {
 	ns.DriverName = s.DriverName
 	ns.DataSourceName = s.DataSourceName
 	ns.Query = s.Query
+	if s.Params != nil {
+		ns.Params = make([]interface{}, len(s.Params))
+		copy(ns.Params, s.Params)
+	}
 	return ns
 }
 
@@ -106,7 +125,12 @@ func createFromSQLSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a ex
 	if err != nil {
 		return nil, err
 	}
-	if err := validateDataSource(validator, spec.DriverName, spec.DataSourceName); err != nil {
+	if err := validateDataSource(url.ForFunction(validator, "sql.from"), spec.DriverName, spec.DataSourceName); err != nil {
+		return nil, err
+	}
+
+	query, err := bindParams(spec.DriverName, spec.Query, spec.Params)
+	if err != nil {
 		return nil, err
 	}
 
@@ -141,7 +165,7 @@ func createFromSQLSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a ex
 		}
 		return read(ctx, reader, a.Allocator())
 	}
-	iterator := &sqlIterator{spec: spec, id: dsid, read: readFn}
+	iterator := &sqlIterator{spec: spec, id: dsid, query: query, read: readFn}
 	return execute.CreateSourceFromIterator(iterator, dsid)
 }
 
@@ -150,7 +174,9 @@ var _ execute.SourceIterator = (*sqlIterator)(nil)
 type sqlIterator struct {
 	spec *FromSQLProcedureSpec
 	id   execute.DatasetID
-	read func(ctx context.Context, rows *sql.Rows) (flux.Table, error)
+	// query is the query of the spec prepared for its params.
+	query string
+	read  func(ctx context.Context, rows *sql.Rows) (flux.Table, error)
 }
 
 func (c *sqlIterator) connect(ctx context.Context) (*sql.DB, error) {
@@ -173,7 +199,7 @@ func (c *sqlIterator) Do(ctx context.Context, f func(flux.Table) error) error {
 	}
 	defer func() { _ = db.Close() }()
 
-	rows, err := db.QueryContext(ctx, c.spec.Query)
+	rows, err := db.QueryContext(ctx, c.query, c.spec.Params...)
 	if err != nil {
 		return err
 	}
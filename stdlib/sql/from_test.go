@@ -0,0 +1,188 @@
+package sql_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/dependenciestest"
+	"github.com/influxdata/flux/execute"
+	_ "github.com/influxdata/flux/fluxinit/static" // We need to init flux for the tests to work.
+	"github.com/influxdata/flux/querytest"
+	"github.com/influxdata/flux/runtime"
+	fsql "github.com/influxdata/flux/stdlib/sql"
+	"github.com/influxdata/flux/values"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestFromSQL_NewQuery(t *testing.T) {
+	tests := []querytest.NewQueryTestCase{
+		{
+			Name: "from with params",
+			Raw: `import "sql"
+sql.from(driverName: "postgres", dataSourceName: "postgres://localhost/db", query: "SELECT * FROM t WHERE host = $1 OR host = $2", params: ["a", "b"])`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "fromSQL0",
+						Spec: &fsql.FromSQLOpSpec{
+							DriverName:     "postgres",
+							DataSourceName: "postgres://localhost/db",
+							Query:          "SELECT * FROM t WHERE host = $1 OR host = $2",
+							Params:         []interface{}{"a", "b"},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "from with params not an array",
+			Raw: `import "sql"
+sql.from(driverName: "postgres", dataSourceName: "postgres://localhost/db", query: "SELECT * FROM t WHERE id = $1", params: 1)`,
+			WantErr: true,
+		},
+		{
+			Name: "tables",
+			Raw: `import "sql"
+sql.tables(driverName: "mysql", dataSourceName: "root@/db")`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "sqlSchema0",
+						Spec: &fsql.SchemaSQLOpSpec{
+							DriverName:     "mysql",
+							DataSourceName: "root@/db",
+							Object:         "tables",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "columns of table",
+			Raw: `import "sql"
+sql.columns(driverName: "mysql", dataSourceName: "root@/db", table: "cpu")`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "sqlSchema0",
+						Spec: &fsql.SchemaSQLOpSpec{
+							DriverName:     "mysql",
+							DataSourceName: "root@/db",
+							Object:         "columns",
+							Table:          "cpu",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "columns of empty table",
+			Raw: `import "sql"
+sql.columns(driverName: "mysql", dataSourceName: "root@/db", table: "")`,
+			WantErr: true,
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			querytest.NewQueryTestHelper(t, tc)
+		})
+	}
+}
+
+func TestFromSQL_Params(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dsn := filepath.Join(dir, "params.db")
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`CREATE TABLE hosts (name TEXT, cpus INTEGER);
+INSERT INTO hosts (name, cpus) VALUES ('a', 2), ('b', 4), ('c', 8);`); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		query   string
+		params  string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "strings",
+			query:  "SELECT name FROM hosts WHERE name = ? OR name = ? ORDER BY name",
+			params: `["a", "c"]`,
+			want:   []string{"a", "c"},
+		},
+		{
+			name:   "integers",
+			query:  "SELECT name FROM hosts WHERE cpus >= ? AND cpus <= ? ORDER BY name",
+			params: `[4, 8]`,
+			want:   []string{"b", "c"},
+		},
+		{
+			// The elements of an array must have the same type.
+			name:    "mixed types",
+			query:   "SELECT name FROM hosts WHERE name = ? OR cpus = ? ORDER BY name",
+			params:  `["a", 8]`,
+			wantErr: true,
+		},
+		{
+			name:   "mixed types as strings",
+			query:  "SELECT name FROM hosts WHERE name = ? OR cpus = CAST(? AS INTEGER) ORDER BY name",
+			params: `["a", "8"]`,
+			want:   []string{"a", "c"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			script := fmt.Sprintf(`import "sql"
+
+names = sql.from(driverName: "sqlite3", dataSourceName: %q, query: %q, params: %s)
+	|> tableFind(fn: (key) => true)
+	|> getColumn(column: "name")
+`, dsn, tc.query, tc.params)
+
+			ctx := dependenciestest.Default().Inject(context.Background())
+			ctx = execute.DefaultExecutionDependencies().Inject(ctx)
+			_, scope, err := runtime.Eval(ctx, script)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			v, ok := scope.Lookup("names")
+			if !ok {
+				t.Fatal("names is not defined")
+			}
+			var got []string
+			v.Array().Range(func(i int, v values.Value) {
+				got = append(got, v.Str())
+			})
+			if !cmp.Equal(tc.want, got) {
+				t.Errorf("unexpected names -want/+got:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package sql
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+// placeholderStyle is the syntax a driver uses for positional query parameters.
+type placeholderStyle int
+
+const (
+	// questionPlaceholders are written as ?
+	questionPlaceholders placeholderStyle = iota
+	// dollarPlaceholders are written as $1, $2, ...
+	dollarPlaceholders
+	// atPPlaceholders are written as @p1, @p2, ...
+	atPPlaceholders
+)
+
+func placeholderStyleFor(driverName string) placeholderStyle {
+	switch driverName {
+	case "postgres":
+		return dollarPlaceholders
+	case "mssql", "sqlserver":
+		return atPPlaceholders
+	default:
+		return questionPlaceholders
+	}
+}
+
+// format returns the placeholder for the parameter at position n, starting at 1.
+func (s placeholderStyle) format(n int) string {
+	switch s {
+	case dollarPlaceholders:
+		return "$" + strconv.Itoa(n)
+	case atPPlaceholders:
+		return "@p" + strconv.Itoa(n)
+	default:
+		return "?"
+	}
+}
+
+// placeholder is the position of a placeholder within a query.
+type placeholder struct {
+	start, end int
+	// n is the parameter number of a numbered placeholder
+	// and zero for a ? placeholder.
+	n int
+}
+
+// findPlaceholders returns the ? placeholders and the numbered placeholders
+// of the style found in the query. Placeholders within quoted strings,
+// quoted identifiers and comments are ignored.
+func findPlaceholders(query string, style placeholderStyle) (questions, numbered []placeholder) {
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			if end := strings.IndexByte(query[i+1:], c); end >= 0 {
+				i += end + 1
+			} else {
+				i = len(query)
+			}
+		case strings.HasPrefix(query[i:], "--"):
+			if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(query)
+			}
+		case strings.HasPrefix(query[i:], "/*"):
+			if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(query)
+			}
+		case c == '?':
+			questions = append(questions, placeholder{start: i, end: i + 1})
+		case c == '$' && style == dollarPlaceholders,
+			c == '@' && style == atPPlaceholders && i+1 < len(query) && (query[i+1] == 'p' || query[i+1] == 'P'):
+			start := i + 1
+			if c == '@' {
+				start++
+			}
+			end := start
+			for end < len(query) && query[end] >= '0' && query[end] <= '9' {
+				end++
+			}
+			if end == start {
+				continue
+			}
+			n, _ := strconv.Atoi(query[start:end])
+			numbered = append(numbered, placeholder{start: i, end: end, n: n})
+			i = end - 1
+		}
+	}
+	return questions, numbered
+}
+
+// bindParams prepares the query to be executed with the parameters by the driver.
+//
+// Queries may be written with the placeholders of the driver or with ? placeholders,
+// which are rewritten into the placeholders of the driver. The ? placeholders are
+// only rewritten when the query does not already use the placeholders of the driver
+// so that operators such as the ? operator of postgres continue to work.
+// The number of placeholders must match the number of parameters.
+// The query is not changed when there are no parameters.
+func bindParams(driverName, query string, params []interface{}) (string, error) {
+	if len(params) == 0 {
+		return query, nil
+	}
+
+	style := placeholderStyleFor(driverName)
+	questions, numbered := findPlaceholders(query, style)
+	if style == questionPlaceholders || len(numbered) == 0 {
+		if len(questions) != len(params) {
+			return "", errors.Newf(codes.Invalid, "query has %d placeholders but %d params were provided", len(questions), len(params))
+		}
+		if style == questionPlaceholders {
+			return query, nil
+		}
+
+		var b strings.Builder
+		last := 0
+		for i, p := range questions {
+			b.WriteString(query[last:p.start])
+			b.WriteString(style.format(i + 1))
+			last = p.end
+		}
+		b.WriteString(query[last:])
+		return b.String(), nil
+	}
+
+	// Each parameter must be referenced by the numbered placeholders,
+	// but a parameter may be referenced more than once.
+	used := make([]bool, len(params))
+	for _, p := range numbered {
+		if p.n < 1 || p.n > len(params) {
+			return "", errors.Newf(codes.Invalid, "query placeholder %s does not match any of the %d params", query[p.start:p.end], len(params))
+		}
+		used[p.n-1] = true
+	}
+	for i, ok := range used {
+		if !ok {
+			return "", errors.Newf(codes.Invalid, "param %d is not used by the query; expected placeholder %s", i+1, style.format(i+1))
+		}
+	}
+	return query, nil
+}
+
+// queryParams converts an array of Flux values into the values
+// that are passed to the driver for the placeholders of a query.
+func queryParams(arr values.Array) ([]interface{}, error) {
+	params := make([]interface{}, 0, arr.Len())
+	var err error
+	arr.Range(func(i int, v values.Value) {
+		if err != nil {
+			return
+		}
+		var p interface{}
+		if p, err = queryParam(v); err != nil {
+			err = errors.Wrapf(err, codes.Invalid, "invalid param at index %d", i)
+			return
+		}
+		params = append(params, p)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+func queryParam(v values.Value) (interface{}, error) {
+	if v.IsNull() {
+		return nil, nil
+	}
+	switch n := v.Type().Nature(); n {
+	case semantic.String:
+		return v.Str(), nil
+	case semantic.Int:
+		return v.Int(), nil
+	case semantic.UInt:
+		return v.UInt(), nil
+	case semantic.Float:
+		return v.Float(), nil
+	case semantic.Bool:
+		return v.Bool(), nil
+	case semantic.Time:
+		return v.Time().Time(), nil
+	default:
+		return nil, errors.Newf(codes.Invalid, "unsupported param type %v", n)
+	}
+}
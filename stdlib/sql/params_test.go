@@ -0,0 +1,148 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+)
+
+func TestBindParams(t *testing.T) {
+	testCases := []struct {
+		name       string
+		driverName string
+		query      string
+		params     []interface{}
+		want       string
+		wantErr    string
+	}{
+		{
+			name:       "no params",
+			driverName: "postgres",
+			query:      "SELECT * FROM t WHERE data ? 'key'",
+			want:       "SELECT * FROM t WHERE data ? 'key'",
+		},
+		{
+			name:       "mysql",
+			driverName: "mysql",
+			query:      "SELECT * FROM t WHERE a = ? AND b = ?",
+			params:     []interface{}{"a", int64(1)},
+			want:       "SELECT * FROM t WHERE a = ? AND b = ?",
+		},
+		{
+			name:       "postgres native",
+			driverName: "postgres",
+			query:      "SELECT * FROM t WHERE a = $2 AND b = $1 AND data ? 'key'",
+			params:     []interface{}{"a", "b"},
+			want:       "SELECT * FROM t WHERE a = $2 AND b = $1 AND data ? 'key'",
+		},
+		{
+			name:       "postgres rewritten",
+			driverName: "postgres",
+			query:      "SELECT * FROM t WHERE a = ? AND b = ? AND c = '?'",
+			params:     []interface{}{"a", "b"},
+			want:       "SELECT * FROM t WHERE a = $1 AND b = $2 AND c = '?'",
+		},
+		{
+			name:       "mssql rewritten",
+			driverName: "sqlserver",
+			query:      "SELECT * FROM t /* a = ? */ WHERE a = ? -- and b = ?\nAND b = ?",
+			params:     []interface{}{"a", "b"},
+			want:       "SELECT * FROM t /* a = ? */ WHERE a = @p1 -- and b = ?\nAND b = @p2",
+		},
+		{
+			name:       "mssql native",
+			driverName: "mssql",
+			query:      "SELECT * FROM t WHERE a = @p1 AND b = @P1",
+			params:     []interface{}{"a"},
+			want:       "SELECT * FROM t WHERE a = @p1 AND b = @P1",
+		},
+		{
+			name:       "too few placeholders",
+			driverName: "sqlite3",
+			query:      "SELECT * FROM t WHERE a = ? AND b = '?'",
+			params:     []interface{}{"a", "b"},
+			wantErr:    "query has 1 placeholders but 2 params were provided",
+		},
+		{
+			name:       "placeholder out of range",
+			driverName: "postgres",
+			query:      "SELECT * FROM t WHERE a = $1 AND b = $3",
+			params:     []interface{}{"a", "b"},
+			wantErr:    "query placeholder $3 does not match any of the 2 params",
+		},
+		{
+			name:       "unused param",
+			driverName: "postgres",
+			query:      "SELECT * FROM t WHERE a = $2",
+			params:     []interface{}{"a", "b"},
+			wantErr:    "param 1 is not used by the query; expected placeholder $1",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := bindParams(tc.driverName, tc.query, tc.params)
+			if tc.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q", tc.wantErr)
+				}
+				if got := err.Error(); got != tc.wantErr {
+					t.Fatalf("unexpected error -want/+got:\n\t- %q\n\t+ %q", tc.wantErr, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("unexpected query -want/+got:\n\t- %q\n\t+ %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestQueryParams(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	arr := values.NewArrayWithBacking(semantic.NewArrayType(semantic.BasicInt), []values.Value{
+		values.NewInt(1),
+		values.NewInt(-2),
+	})
+	got, err := queryParams(arr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{int64(1), int64(-2)}; !cmp.Equal(want, got) {
+		t.Errorf("unexpected params -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	for _, tc := range []struct {
+		v    values.Value
+		want interface{}
+	}{
+		{v: values.NewString("a"), want: "a"},
+		{v: values.NewUInt(2), want: uint64(2)},
+		{v: values.NewFloat(1.5), want: 1.5},
+		{v: values.NewBool(true), want: true},
+		{v: values.NewTime(values.ConvertTime(now)), want: now},
+	} {
+		got, err := queryParam(tc.v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(tc.want, got) {
+			t.Errorf("unexpected param -want/+got:\n%s", cmp.Diff(tc.want, got))
+		}
+	}
+
+	arr = values.NewArrayWithBacking(semantic.NewArrayType(semantic.BasicDuration), []values.Value{
+		values.NewDuration(values.ConvertDurationNsecs(time.Second)),
+	})
+	if _, err := queryParams(arr); err == nil {
+		t.Error("expected error for duration param")
+	} else if want, got := "invalid param at index 0: unsupported param type duration", err.Error(); want != got {
+		t.Errorf("unexpected error -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+}
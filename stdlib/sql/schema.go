@@ -0,0 +1,320 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/url"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/runtime"
+)
+
+const SchemaSQLKind = "sqlSchema"
+
+// The kinds of schema objects that can be listed.
+const (
+	schemaTables  = "tables"
+	schemaColumns = "columns"
+)
+
+// SchemaSQLOpSpec lists the tables or the columns of a database.
+type SchemaSQLOpSpec struct {
+	DriverName     string `json:"driverName,omitempty"`
+	DataSourceName string `json:"dataSourceName,omitempty"`
+	// Object is the kind of schema object to list, either tables or columns.
+	Object string `json:"object"`
+	// Table limits the columns that are listed to those of a single table.
+	Table string `json:"table,omitempty"`
+}
+
+func init() {
+	tablesSignature := runtime.MustLookupBuiltinType("sql", "tables")
+	runtime.RegisterPackageValue("sql", "tables", flux.MustValue(flux.FunctionValue(SchemaSQLKind, createTablesOpSpec, tablesSignature)))
+	columnsSignature := runtime.MustLookupBuiltinType("sql", "columns")
+	runtime.RegisterPackageValue("sql", "columns", flux.MustValue(flux.FunctionValue(SchemaSQLKind, createColumnsOpSpec, columnsSignature)))
+	flux.RegisterOpSpec(SchemaSQLKind, newSchemaSQLOp)
+	plan.RegisterProcedureSpec(SchemaSQLKind, newSchemaSQLProcedure, SchemaSQLKind)
+	execute.RegisterSource(SchemaSQLKind, createSchemaSQLSource)
+}
+
+func readSchemaArgs(args flux.Arguments, object string) (*SchemaSQLOpSpec, error) {
+	spec := &SchemaSQLOpSpec{Object: object}
+	if driverName, err := args.GetRequiredString("driverName"); err != nil {
+		return nil, err
+	} else {
+		spec.DriverName = driverName
+	}
+	if dataSourceName, err := args.GetRequiredString("dataSourceName"); err != nil {
+		return nil, err
+	} else {
+		spec.DataSourceName = dataSourceName
+	}
+	return spec, nil
+}
+
+func createTablesOpSpec(args flux.Arguments, administration *flux.Administration) (flux.OperationSpec, error) {
+	return readSchemaArgs(args, schemaTables)
+}
+
+func createColumnsOpSpec(args flux.Arguments, administration *flux.Administration) (flux.OperationSpec, error) {
+	spec, err := readSchemaArgs(args, schemaColumns)
+	if err != nil {
+		return nil, err
+	}
+	if table, ok, err := args.GetString("table"); err != nil {
+		return nil, err
+	} else if ok {
+		if table == "" {
+			return nil, errors.New(codes.Invalid, "table must not be empty")
+		}
+		spec.Table = table
+	}
+	return spec, nil
+}
+
+func newSchemaSQLOp() flux.OperationSpec {
+	return new(SchemaSQLOpSpec)
+}
+
+func (s *SchemaSQLOpSpec) Kind() flux.OperationKind {
+	return SchemaSQLKind
+}
+
+type SchemaSQLProcedureSpec struct {
+	plan.DefaultCost
+	DriverName     string
+	DataSourceName string
+	Object         string
+	Table          string
+}
+
+func newSchemaSQLProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*SchemaSQLOpSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", qs)
+	}
+
+	return &SchemaSQLProcedureSpec{
+		DriverName:     spec.DriverName,
+		DataSourceName: spec.DataSourceName,
+		Object:         spec.Object,
+		Table:          spec.Table,
+	}, nil
+}
+
+func (s *SchemaSQLProcedureSpec) Kind() plan.ProcedureKind {
+	return SchemaSQLKind
+}
+
+func (s *SchemaSQLProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := *s
+	return &ns
+}
+
+// schemaColumn is a column of the tables produced by sql.tables and sql.columns.
+type schemaColumn struct {
+	flux.ColMeta
+	// scan returns the destination of the column for rows.Scan
+	// and a function that appends the scanned value to the builder.
+	scan func() (interface{}, func(b execute.TableBuilder, j int) error)
+}
+
+func stringSchemaColumn(label string) schemaColumn {
+	return schemaColumn{
+		ColMeta: flux.ColMeta{Label: label, Type: flux.TString},
+		scan: func() (interface{}, func(b execute.TableBuilder, j int) error) {
+			var s sql.NullString
+			return &s, func(b execute.TableBuilder, j int) error {
+				if !s.Valid {
+					return b.AppendNil(j)
+				}
+				return b.AppendString(j, s.String)
+			}
+		},
+	}
+}
+
+func intSchemaColumn(label string) schemaColumn {
+	return schemaColumn{
+		ColMeta: flux.ColMeta{Label: label, Type: flux.TInt},
+		scan: func() (interface{}, func(b execute.TableBuilder, j int) error) {
+			var i sql.NullInt64
+			return &i, func(b execute.TableBuilder, j int) error {
+				if !i.Valid {
+					return b.AppendNil(j)
+				}
+				return b.AppendInt(j, i.Int64)
+			}
+		},
+	}
+}
+
+// boolSchemaColumn scans a column that reports YES or TRUE for true
+// as the nullability of columns is reported as a string by most databases.
+func boolSchemaColumn(label string) schemaColumn {
+	return schemaColumn{
+		ColMeta: flux.ColMeta{Label: label, Type: flux.TBool},
+		scan: func() (interface{}, func(b execute.TableBuilder, j int) error) {
+			var s sql.NullString
+			return &s, func(b execute.TableBuilder, j int) error {
+				if !s.Valid {
+					return b.AppendNil(j)
+				}
+				switch strings.ToUpper(strings.TrimSpace(s.String)) {
+				case "YES", "TRUE", "1":
+					return b.AppendBool(j, true)
+				default:
+					return b.AppendBool(j, false)
+				}
+			}
+		},
+	}
+}
+
+var (
+	tablesSchema = []schemaColumn{
+		stringSchemaColumn("table_schema"),
+		stringSchemaColumn("table_name"),
+		stringSchemaColumn("table_type"),
+	}
+	columnsSchema = []schemaColumn{
+		stringSchemaColumn("table_schema"),
+		stringSchemaColumn("table_name"),
+		stringSchemaColumn("column_name"),
+		intSchemaColumn("ordinal_position"),
+		stringSchemaColumn("data_type"),
+		boolSchemaColumn("is_nullable"),
+	}
+)
+
+// informationSchemaFilter excludes the schemas that describe the database itself.
+const informationSchemaFilter = `table_schema NOT IN ('information_schema', 'INFORMATION_SCHEMA', 'pg_catalog', 'mysql', 'performance_schema', 'sys')`
+
+// schemaQuery returns the query that lists the schema objects with the driver.
+// The query uses ? placeholders for its params.
+func schemaQuery(driverName, object, table string) (string, []interface{}, error) {
+	// The columns query is split around the filter for the table.
+	var tablesQuery, columnsQuery, tableFilter, columnsOrder string
+	switch driverName {
+	case "postgres", "mysql", "snowflake", "mssql", "sqlserver", "awsathena":
+		tablesQuery = `SELECT table_schema, table_name, table_type FROM information_schema.tables` +
+			` WHERE ` + informationSchemaFilter + ` ORDER BY table_schema, table_name`
+		columnsQuery = `SELECT table_schema, table_name, column_name, ordinal_position, data_type, is_nullable` +
+			` FROM information_schema.columns WHERE ` + informationSchemaFilter
+		tableFilter = ` AND table_name = ?`
+		columnsOrder = ` ORDER BY table_schema, table_name, ordinal_position`
+	case "sqlite3":
+		tablesQuery = `SELECT 'main', name, CASE type WHEN 'view' THEN 'VIEW' ELSE 'BASE TABLE' END FROM sqlite_master` +
+			` WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name`
+		columnsQuery = `SELECT 'main', m.name, p.name, p.cid + 1, p.type, CASE p."notnull" WHEN 0 THEN 'YES' ELSE 'NO' END` +
+			` FROM sqlite_master AS m JOIN pragma_table_info(m.name) AS p` +
+			` WHERE m.type IN ('table', 'view') AND m.name NOT LIKE 'sqlite_%'`
+		tableFilter = ` AND m.name = ?`
+		columnsOrder = ` ORDER BY m.name, p.cid`
+	case "hdb":
+		tablesQuery = `SELECT SCHEMA_NAME, TABLE_NAME, 'BASE TABLE' FROM SYS.TABLES WHERE SCHEMA_NAME = CURRENT_SCHEMA` +
+			` UNION ALL SELECT SCHEMA_NAME, VIEW_NAME, 'VIEW' FROM SYS.VIEWS WHERE SCHEMA_NAME = CURRENT_SCHEMA ORDER BY 2`
+		columnsQuery = `SELECT SCHEMA_NAME, TABLE_NAME, COLUMN_NAME, POSITION, DATA_TYPE_NAME, IS_NULLABLE` +
+			` FROM SYS.TABLE_COLUMNS WHERE SCHEMA_NAME = CURRENT_SCHEMA`
+		tableFilter = ` AND TABLE_NAME = ?`
+		columnsOrder = ` ORDER BY TABLE_NAME, POSITION`
+	case "bigquery":
+		return "", nil, errors.Newf(codes.Unimplemented, "listing %s is not supported for %s", object, driverName)
+	default:
+		return "", nil, errors.Newf(codes.Invalid, "sql driver %s not supported", driverName)
+	}
+
+	switch object {
+	case schemaTables:
+		return tablesQuery, nil, nil
+	case schemaColumns:
+		if table == "" {
+			return columnsQuery + columnsOrder, nil, nil
+		}
+		return columnsQuery + tableFilter + columnsOrder, []interface{}{table}, nil
+	default:
+		return "", nil, errors.Newf(codes.Internal, "unknown schema object %q", object)
+	}
+}
+
+func createSchemaSQLSource(prSpec plan.ProcedureSpec, dsid execute.DatasetID, a execute.Administration) (execute.Source, error) {
+	spec, ok := prSpec.(*SchemaSQLProcedureSpec)
+	if !ok {
+		return nil, errors.Newf(codes.Internal, "invalid spec type %T", prSpec)
+	}
+
+	// validate the data driver name and source name.
+	deps := flux.GetDependencies(a.Context())
+	validator, err := deps.URLValidator()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDataSource(url.ForFunction(validator, "sql."+spec.Object), spec.DriverName, spec.DataSourceName); err != nil {
+		return nil, err
+	}
+
+	query, params, err := schemaQuery(spec.DriverName, spec.Object, spec.Table)
+	if err != nil {
+		return nil, err
+	}
+	if query, err = bindParams(spec.DriverName, query, params); err != nil {
+		return nil, err
+	}
+
+	schema := tablesSchema
+	if spec.Object == schemaColumns {
+		schema = columnsSchema
+	}
+	readFn := func(ctx context.Context, rows *sql.Rows) (flux.Table, error) {
+		return readSchema(rows, schema, a.Allocator())
+	}
+	iterator := &sqlIterator{
+		spec: &FromSQLProcedureSpec{
+			DriverName:     spec.DriverName,
+			DataSourceName: spec.DataSourceName,
+			Query:          query,
+			Params:         params,
+		},
+		id:    dsid,
+		query: query,
+		read:  readFn,
+	}
+	return execute.CreateSourceFromIterator(iterator, dsid)
+}
+
+// readSchema reads the rows into a table with the schema.
+// The columns of the rows must be in the same order as the schema.
+func readSchema(rows *sql.Rows, schema []schemaColumn, alloc *memory.Allocator) (flux.Table, error) {
+	builder := execute.NewColListTableBuilder(execute.NewGroupKey(nil, nil), alloc)
+	for _, col := range schema {
+		if _, err := builder.AddCol(col.ColMeta); err != nil {
+			return nil, err
+		}
+	}
+
+	dest := make([]interface{}, len(schema))
+	appendFns := make([]func(b execute.TableBuilder, j int) error, len(schema))
+	for j, col := range schema {
+		dest[j], appendFns[j] = col.scan()
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for j, appendFn := range appendFns {
+			if err := appendFn(builder, j); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return builder.Table()
+}
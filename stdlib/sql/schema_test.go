@@ -0,0 +1,169 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute/executetest"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestSchema_SQLite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dsn := filepath.Join(dir, "test.db")
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []string{
+		"CREATE TABLE cpu (host TEXT NOT NULL, usage REAL)",
+		"CREATE VIEW busy AS SELECT host FROM cpu WHERE usage > 90",
+		"INSERT INTO cpu (host, usage) VALUES ('a', 95.5), ('b', 10), ('c', 99)",
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name   string
+		object string
+		table  string
+		want   *executetest.Table
+	}{
+		{
+			name:   "tables",
+			object: schemaTables,
+			want: &executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "table_schema", Type: flux.TString},
+					{Label: "table_name", Type: flux.TString},
+					{Label: "table_type", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{"main", "busy", "VIEW"},
+					{"main", "cpu", "BASE TABLE"},
+				},
+			},
+		},
+		{
+			name:   "columns",
+			object: schemaColumns,
+			table:  "cpu",
+			want: &executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "table_schema", Type: flux.TString},
+					{Label: "table_name", Type: flux.TString},
+					{Label: "column_name", Type: flux.TString},
+					{Label: "ordinal_position", Type: flux.TInt},
+					{Label: "data_type", Type: flux.TString},
+					{Label: "is_nullable", Type: flux.TBool},
+				},
+				Data: [][]interface{}{
+					{"main", "cpu", "host", int64(1), "TEXT", false},
+					{"main", "cpu", "usage", int64(2), "REAL", true},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			query, params, err := schemaQuery("sqlite3", tc.object, tc.table)
+			if err != nil {
+				t.Fatal(err)
+			}
+			schema := tablesSchema
+			if tc.object == schemaColumns {
+				schema = columnsSchema
+			}
+			iterator := &sqlIterator{
+				spec: &FromSQLProcedureSpec{
+					DriverName:     "sqlite3",
+					DataSourceName: dsn,
+					Params:         params,
+				},
+				query: query,
+				read: func(ctx context.Context, rows *sql.Rows) (flux.Table, error) {
+					return readSchema(rows, schema, executetest.UnlimitedAllocator)
+				},
+			}
+
+			var got []*executetest.Table
+			if err := iterator.Do(context.Background(), func(tbl flux.Table) error {
+				t, err := executetest.ConvertTable(tbl)
+				if err != nil {
+					return err
+				}
+				got = append(got, t)
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+			want := []*executetest.Table{tc.want}
+			executetest.NormalizeTables(want)
+			executetest.NormalizeTables(got)
+			if !cmp.Equal(want, got) {
+				t.Errorf("unexpected tables -want/+got:\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+
+	t.Run("from with params", func(t *testing.T) {
+		params := []interface{}{90.0, "c"}
+		query, err := bindParams("sqlite3", "SELECT host FROM cpu WHERE usage > ? AND host != ? ORDER BY host", params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		iterator := &sqlIterator{
+			spec: &FromSQLProcedureSpec{
+				DriverName:     "sqlite3",
+				DataSourceName: dsn,
+				Params:         params,
+			},
+			query: query,
+			read: func(ctx context.Context, rows *sql.Rows) (flux.Table, error) {
+				reader, err := NewSqliteRowReader(rows)
+				if err != nil {
+					return nil, err
+				}
+				return read(ctx, reader, executetest.UnlimitedAllocator)
+			},
+		}
+
+		var got []*executetest.Table
+		if err := iterator.Do(context.Background(), func(tbl flux.Table) error {
+			t, err := executetest.ConvertTable(tbl)
+			if err != nil {
+				return err
+			}
+			got = append(got, t)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		want := []*executetest.Table{{
+			ColMeta: []flux.ColMeta{{Label: "host", Type: flux.TString}},
+			Data:    [][]interface{}{{"a"}},
+		}}
+		executetest.NormalizeTables(want)
+		executetest.NormalizeTables(got)
+		if !cmp.Equal(want, got) {
+			t.Errorf("unexpected tables -want/+got:\n%s", cmp.Diff(want, got))
+		}
+	})
+}
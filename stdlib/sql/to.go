@@ -7,10 +7,12 @@ import (
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/url"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/flux/values"
 )
 
@@ -19,11 +21,24 @@ const (
 	DefaultBatchSize = 10000 //TODO: decide if this should be kept low enough for the lowest (SQLite), or not.
 )
 
+// The modes used by sql.to to write rows that conflict with existing rows.
+const (
+	// InsertMode writes every row with a plain INSERT.
+	InsertMode = "insert"
+	// UpsertMode updates the columns of existing rows with the same key.
+	UpsertMode = "upsert"
+	// ReplaceMode replaces existing rows with the same key.
+	ReplaceMode = "replace"
+)
+
 type ToSQLOpSpec struct {
-	DriverName     string `json:"driverName,omitempty"`
-	DataSourceName string `json:"dataSourcename,omitempty"`
-	Table          string `json:"table,omitempty"`
-	BatchSize      int    `json:"batchSize,omitempty"`
+	DriverName     string   `json:"driverName,omitempty"`
+	DataSourceName string   `json:"dataSourcename,omitempty"`
+	Table          string   `json:"table,omitempty"`
+	BatchSize      int      `json:"batchSize,omitempty"`
+	Mode           string   `json:"mode,omitempty"`
+	KeyColumns     []string `json:"keyColumns,omitempty"`
+	CreateTable    bool     `json:"createTable,omitempty"`
 }
 
 func init() {
@@ -72,7 +87,37 @@ func (o *ToSQLOpSpec) ReadArgs(args flux.Arguments) error {
 		o.BatchSize = int(b)
 	}
 
-	return err
+	o.Mode = InsertMode
+	if mode, ok, err := args.GetString("mode"); err != nil {
+		return err
+	} else if ok {
+		switch mode {
+		case InsertMode, UpsertMode, ReplaceMode:
+			o.Mode = mode
+		default:
+			return errors.Newf(codes.Invalid, "invalid mode %q, must be one of %q, %q or %q", mode, InsertMode, UpsertMode, ReplaceMode)
+		}
+	}
+
+	if keyColumns, ok, err := args.GetArray("keyColumns", semantic.String); err != nil {
+		return err
+	} else if ok {
+		o.KeyColumns = make([]string, keyColumns.Len())
+		keyColumns.Range(func(i int, v values.Value) {
+			o.KeyColumns[i] = v.Str()
+		})
+	}
+	if o.Mode != InsertMode && len(o.KeyColumns) == 0 {
+		return errors.Newf(codes.Invalid, "keyColumns are required when mode is %q", o.Mode)
+	}
+
+	o.CreateTable = true
+	if createTable, ok, err := args.GetBool("createTable"); err != nil {
+		return err
+	} else if ok {
+		o.CreateTable = createTable
+	}
+	return nil
 }
 
 func createToSQLOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
@@ -107,6 +152,9 @@ func (o *ToSQLProcedureSpec) Copy() plan.ProcedureSpec {
 			DataSourceName: s.DataSourceName,
 			Table:          s.Table,
 			BatchSize:      s.BatchSize,
+			Mode:           s.Mode,
+			KeyColumns:     append([]string(nil), s.KeyColumns...),
+			CreateTable:    s.CreateTable,
 		},
 	}
 	return res
@@ -153,7 +201,10 @@ func NewToSQLTransformation(d execute.Dataset, deps flux.Dependencies, cache exe
 	if err != nil {
 		return nil, err
 	}
-	if err := validateDataSource(validator, spec.Spec.DriverName, spec.Spec.DataSourceName); err != nil {
+	if err := validateDataSource(url.ForFunction(validator, "sql.to"), spec.Spec.DriverName, spec.Spec.DataSourceName); err != nil {
+		return nil, err
+	}
+	if err := validateMode(spec.Spec.DriverName, spec.Spec.Mode); err != nil {
 		return nil, err
 	}
 
@@ -310,11 +361,43 @@ func CreateInsertComponents(t *ToSQLTransformation, tbl flux.Table) (colNames []
 			if err != nil {
 				return nil, nil, nil, err
 			}
+			if keyType, ok := keyColumnTypes[driverName][col.Type]; ok && contains(t.spec.Spec.KeyColumns, col.Label) {
+				// the type of the column cannot be used within a primary key
+				v = col.Label + " " + keyType
+			}
 			newSQLTableCols = append(newSQLTableCols, v)
 		default:
 			return nil, nil, nil, errors.Newf(codes.Internal, "invalid type for column %s", col.Label)
 		}
 	}
+	for _, key := range t.spec.Spec.KeyColumns {
+		if _, ok := labels[key]; !ok {
+			return nil, nil, nil, errors.Newf(codes.Invalid, "key column %q is not a column of the table", key)
+		}
+	}
+	if len(t.spec.Spec.KeyColumns) > 0 {
+		newSQLTableCols = append(newSQLTableCols, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(t.spec.Spec.KeyColumns, ",")))
+	}
+
+	if t.spec.Spec.CreateTable && t.spec.Spec.DriverName != "sqlmock" {
+		var q string
+		if isMssqlDriver(t.spec.Spec.DriverName) { // SQL Server does not support IF NOT EXIST
+			q = fmt.Sprintf("IF OBJECT_ID('%s', 'U') IS NULL BEGIN CREATE TABLE %s (%s) END", t.spec.Spec.Table, t.spec.Spec.Table, strings.Join(newSQLTableCols, ","))
+		} else if t.spec.Spec.DriverName == "hdb" { // SAP HANA does not support IF NOT EXIST
+			// wrap CREATE TABLE statement with HDB-specific "if not exists" SQLScript check
+			q = fmt.Sprintf("CREATE TABLE %s (%s)", hdbEscapeName(t.spec.Spec.Table, true), strings.Join(newSQLTableCols, ","))
+			q = hdbAddIfNotExist(t.spec.Spec.Table, q)
+		} else {
+			q = fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", t.spec.Spec.Table, strings.Join(newSQLTableCols, ","))
+		}
+		if _, err := t.tx.Exec(q); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	if t.spec.Spec.DriverName == "hdb" {
+		// SAP HANA does not support INSERT/UPDATE batching via a single SQL command
+		batchSize = 1
+	}
 
 	// Creates the placeholders for values in the query
 	// eg: (?,?)
@@ -335,25 +418,6 @@ func CreateInsertComponents(t *ToSQLTransformation, tbl flux.Table) (colNames []
 		// valueArgs holds all the values to pass into the query
 		valueArgs := make([]interface{}, 0, l*len(cols))
 
-		if t.spec.Spec.DriverName != "sqlmock" {
-			var q string
-			if isMssqlDriver(t.spec.Spec.DriverName) { // SQL Server does not support IF NOT EXIST
-				q = fmt.Sprintf("IF OBJECT_ID('%s', 'U') IS NULL BEGIN CREATE TABLE %s (%s) END", t.spec.Spec.Table, t.spec.Spec.Table, strings.Join(newSQLTableCols, ","))
-			} else if t.spec.Spec.DriverName == "hdb" { // SAP HANA does not support IF NOT EXIST
-				// wrap CREATE TABLE statement with HDB-specific "if not exists" SQLScript check
-				q = fmt.Sprintf("CREATE TABLE %s (%s)", hdbEscapeName(t.spec.Spec.Table, true), strings.Join(newSQLTableCols, ","))
-				q = hdbAddIfNotExist(t.spec.Spec.Table, q)
-				// SAP HANA does not support INSERT/UPDATE batching via a single SQL command
-				batchSize = 1
-			} else {
-				q = fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", t.spec.Spec.Table, strings.Join(newSQLTableCols, ","))
-			}
-			_, err = t.tx.Exec(q)
-			if err != nil {
-				return err
-			}
-		}
-
 		for i := 0; i < l; i++ {
 			valueStrings = append(valueStrings, valuePlaceHolders)
 			for j, col := range er.Cols() {
@@ -424,30 +488,15 @@ func CreateInsertComponents(t *ToSQLTransformation, tbl flux.Table) (colNames []
 }
 
 func ExecuteQueries(tx *sql.Tx, s *ToSQLOpSpec, colNames []string, valueStrings *[]string, valueArgs *[]interface{}) (err error) {
-	concatValueStrings := strings.Join(*valueStrings, ",")
-
-	// PostgreSQL uses $n instead of ? for placeholders
-	if s.DriverName == "postgres" {
-		for pqCounter := 1; strings.Contains(concatValueStrings, "?"); pqCounter++ {
-			concatValueStrings = strings.Replace(concatValueStrings, "?", fmt.Sprintf("$%v", pqCounter), 1)
-		}
-	}
-	// SQLServer uses @p instead of ? for placeholders
-	if isMssqlDriver(s.DriverName) {
-		for pqCounter := 1; strings.Contains(concatValueStrings, "?"); pqCounter++ {
-			concatValueStrings = strings.Replace(concatValueStrings, "?", fmt.Sprintf("@p%v", pqCounter), 1)
-		}
+	queries, args, err := writeQueries(s, colNames, *valueStrings, *valueArgs)
+	if err != nil {
+		return err
 	}
-
-	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", s.Table, strings.Join(colNames, ","), concatValueStrings)
-	if isMssqlDriver(s.DriverName) && mssqlCheckParameter(s.DataSourceName, mssqlIdentityInsertEnabled) {
-		prologue := fmt.Sprintf("DECLARE @tableHasIdentity INT = OBJECTPROPERTY(OBJECT_ID('%s'), 'TableHasIdentity'); IF @tableHasIdentity = 1 BEGIN SET IDENTITY_INSERT %s ON END", s.Table, s.Table)
-		epilogue := fmt.Sprintf("IF @tableHasIdentity = 1 BEGIN SET IDENTITY_INSERT %s OFF END", s.Table)
-		query = strings.Join([]string{prologue, query, epilogue}, "; ")
+	if s.DriverName == "sqlmock" {
+		return nil
 	}
-	if s.DriverName != "sqlmock" {
-		_, err := tx.Exec(query, *valueArgs...)
-		if err != nil {
+	for i, query := range queries {
+		if _, err := tx.Exec(query, args[i]...); err != nil {
 			// this err which is extremely helpful as it comes from the SQL driver should be
 			// bubbled up further up the stack so user can see the issue
 			if rbErr := tx.Rollback(); rbErr != nil {
@@ -456,5 +505,33 @@ func ExecuteQueries(tx *sql.Tx, s *ToSQLOpSpec, colNames []string, valueStrings
 			return err
 		}
 	}
-	return err
+	return nil
+}
+
+// numberPlaceholders replaces the ? placeholders with the numbered placeholders of the driver.
+func numberPlaceholders(driverName string, s string) string {
+	// PostgreSQL uses $n instead of ? for placeholders
+	if driverName == "postgres" {
+		for pqCounter := 1; strings.Contains(s, "?"); pqCounter++ {
+			s = strings.Replace(s, "?", fmt.Sprintf("$%v", pqCounter), 1)
+		}
+	}
+	// SQLServer uses @p instead of ? for placeholders
+	if isMssqlDriver(driverName) {
+		for pqCounter := 1; strings.Contains(s, "?"); pqCounter++ {
+			s = strings.Replace(s, "?", fmt.Sprintf("@p%v", pqCounter), 1)
+		}
+	}
+	return s
+}
+
+// withIdentityInsert allows SQL Server to write the identity column of the table
+// when requested by the data source name.
+func withIdentityInsert(s *ToSQLOpSpec, query string) string {
+	if !isMssqlDriver(s.DriverName) || !mssqlCheckParameter(s.DataSourceName, mssqlIdentityInsertEnabled) {
+		return query
+	}
+	prologue := fmt.Sprintf("DECLARE @tableHasIdentity INT = OBJECTPROPERTY(OBJECT_ID('%s'), 'TableHasIdentity'); IF @tableHasIdentity = 1 BEGIN SET IDENTITY_INSERT %s ON END", s.Table, s.Table)
+	epilogue := fmt.Sprintf("IF @tableHasIdentity = 1 BEGIN SET IDENTITY_INSERT %s OFF END", s.Table)
+	return strings.Join([]string{prologue, query, epilogue}, "; ")
 }
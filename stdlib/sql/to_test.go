@@ -40,6 +40,8 @@ func TestSqlTo(t *testing.T) {
 							DataSourceName: "root@/db",
 							Table:          "TestTable",
 							BatchSize:      fsql.DefaultBatchSize,
+							Mode:           fsql.InsertMode,
+							CreateTable:    true,
 						},
 					},
 				},
@@ -48,6 +50,44 @@ func TestSqlTo(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "upsert",
+			Raw:  `import "sql" from(bucket: "mybucket") |> sql.to(driverName:"postgres", dataSourceName:"postgres://localhost/db", table:"TestTable", mode:"upsert", keyColumns:["_time", "host"], createTable:false)`,
+			Want: &flux.Spec{
+				Operations: []*flux.Operation{
+					{
+						ID: "from0",
+						Spec: &influxdb.FromOpSpec{
+							Bucket: influxdb.NameOrID{Name: "mybucket"},
+						},
+					},
+					{
+						ID: "toSQL1",
+						Spec: &fsql.ToSQLOpSpec{
+							DriverName:     "postgres",
+							DataSourceName: "postgres://localhost/db",
+							Table:          "TestTable",
+							BatchSize:      fsql.DefaultBatchSize,
+							Mode:           fsql.UpsertMode,
+							KeyColumns:     []string{"_time", "host"},
+						},
+					},
+				},
+				Edges: []flux.Edge{
+					{Parent: "from0", Child: "toSQL1"},
+				},
+			},
+		},
+		{
+			Name:    "upsert without key columns",
+			Raw:     `import "sql" from(bucket: "mybucket") |> sql.to(driverName:"postgres", dataSourceName:"postgres://localhost/db", table:"TestTable", mode:"upsert")`,
+			WantErr: true,
+		},
+		{
+			Name:    "invalid mode",
+			Raw:     `import "sql" from(bucket: "mybucket") |> sql.to(driverName:"postgres", dataSourceName:"postgres://localhost/db", table:"TestTable", mode:"merge", keyColumns:["host"])`,
+			WantErr: true,
+		},
 	}
 	for _, tc := range tests {
 		tc := tc
@@ -352,6 +392,17 @@ func TestToSql_NewTransformation(t *testing.T) {
 					},
 				},
 				WantErr: "sql driver voltdb not supported",
+			}, {
+				Name: "upsert not supported",
+				Spec: &fsql.ToSQLProcedureSpec{
+					Spec: &fsql.ToSQLOpSpec{
+						DriverName:     "snowflake",
+						DataSourceName: "username:password@accountname.us-east-1/dbname",
+						Mode:           fsql.UpsertMode,
+						KeyColumns:     []string{"host"},
+					},
+				},
+				WantErr: `mode "upsert" is not supported for snowflake`,
 			}, {
 				Name: "no such host",
 				Spec: &fsql.ToSQLProcedureSpec{
@@ -396,6 +447,8 @@ func TestSqlite3To(t *testing.T) {
 							DataSourceName: "file::memory:",
 							Table:          "TestTable",
 							BatchSize:      10000,
+							Mode:           fsql.InsertMode,
+							CreateTable:    true,
 						},
 					},
 				},
@@ -0,0 +1,193 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+)
+
+// keyColumnTypes overrides the column types of key columns for the drivers
+// whose default column type of a Flux type cannot be used within a primary key.
+var keyColumnTypes = map[string]map[flux.ColType]string{
+	"mysql":     {flux.TString: "VARCHAR(255)"},
+	"mssql":     {flux.TString: "VARCHAR(900)"},
+	"sqlserver": {flux.TString: "VARCHAR(900)"},
+}
+
+// validateMode checks that the driver can write rows with the mode.
+func validateMode(driverName, mode string) error {
+	switch mode {
+	case "", InsertMode:
+		return nil
+	case UpsertMode, ReplaceMode:
+		switch driverName {
+		case "postgres", "sqlite3", "mysql", "mssql", "sqlserver":
+			return nil
+		}
+		return errors.Newf(codes.Unimplemented, "mode %q is not supported for %s", mode, driverName)
+	default:
+		return errors.Newf(codes.Invalid, "invalid mode %q", mode)
+	}
+}
+
+// writeQueries returns the queries that write a batch of rows to the table
+// along with the arguments of each query.
+// The valueStrings hold the placeholders of each row and the valueArgs
+// hold the values of every row.
+func writeQueries(s *ToSQLOpSpec, colNames []string, valueStrings []string, valueArgs []interface{}) ([]string, [][]interface{}, error) {
+	if err := validateMode(s.DriverName, s.Mode); err != nil {
+		return nil, nil, err
+	}
+	keys := make([]int, len(s.KeyColumns))
+	for i, key := range s.KeyColumns {
+		keys[i] = indexOf(colNames, key)
+		if keys[i] < 0 {
+			return nil, nil, errors.Newf(codes.Invalid, "key column %q is not a column of the table", key)
+		}
+	}
+	if s.Mode == UpsertMode || s.Mode == ReplaceMode {
+		// A statement cannot affect the same row twice,
+		// so only the last row of each key is written.
+		valueStrings, valueArgs = lastRowPerKey(keys, len(colNames), valueStrings, valueArgs)
+	}
+	var updates []string
+	for _, col := range colNames {
+		if !contains(s.KeyColumns, col) {
+			updates = append(updates, col)
+		}
+	}
+
+	values := numberPlaceholders(s.DriverName, strings.Join(valueStrings, ","))
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", s.Table, strings.Join(colNames, ","), values)
+
+	switch s.Mode {
+	case UpsertMode:
+		var query string
+		switch {
+		case isMssqlDriver(s.DriverName):
+			query = mssqlMerge(s.Table, colNames, s.KeyColumns, updates, values)
+		case s.DriverName == "mysql":
+			// MySQL uses the primary key and unique indexes of the table to find conflicts.
+			set := make([]string, len(updates))
+			for i, col := range updates {
+				set[i] = fmt.Sprintf("%s = VALUES(%s)", col, col)
+			}
+			if len(set) == 0 {
+				set = []string{fmt.Sprintf("%s = %s", s.KeyColumns[0], s.KeyColumns[0])}
+			}
+			query = fmt.Sprintf("%s ON DUPLICATE KEY UPDATE %s", insert, strings.Join(set, ", "))
+		default:
+			action := "DO NOTHING"
+			if len(updates) > 0 {
+				set := make([]string, len(updates))
+				for i, col := range updates {
+					set[i] = fmt.Sprintf("%s = excluded.%s", col, col)
+				}
+				action = "DO UPDATE SET " + strings.Join(set, ", ")
+			}
+			query = fmt.Sprintf("%s ON CONFLICT (%s) %s", insert, strings.Join(s.KeyColumns, ","), action)
+		}
+		return []string{withIdentityInsert(s, query)}, [][]interface{}{valueArgs}, nil
+	case ReplaceMode:
+		switch s.DriverName {
+		case "sqlite3":
+			return []string{"INSERT OR REPLACE" + strings.TrimPrefix(insert, "INSERT")}, [][]interface{}{valueArgs}, nil
+		case "mysql":
+			return []string{"REPLACE" + strings.TrimPrefix(insert, "INSERT")}, [][]interface{}{valueArgs}, nil
+		}
+		// Delete the rows with the same keys before inserting the new rows.
+		deleteQuery, deleteArgs := deleteKeys(s, keys, len(colNames), len(valueStrings), valueArgs)
+		return []string{deleteQuery, withIdentityInsert(s, insert)}, [][]interface{}{deleteArgs, valueArgs}, nil
+	default:
+		return []string{withIdentityInsert(s, insert)}, [][]interface{}{valueArgs}, nil
+	}
+}
+
+// lastRowPerKey removes the rows of the batch whose keys are repeated
+// by a later row of the batch. The remaining rows keep their order.
+func lastRowPerKey(keys []int, width int, valueStrings []string, valueArgs []interface{}) ([]string, []interface{}) {
+	rows := len(valueStrings)
+	last := make(map[string]int, rows)
+	rowKeys := make([]string, rows)
+	for i := 0; i < rows; i++ {
+		var b strings.Builder
+		for _, key := range keys {
+			fmt.Fprintf(&b, "%T:%v\x00", valueArgs[i*width+key], valueArgs[i*width+key])
+		}
+		rowKeys[i] = b.String()
+		last[rowKeys[i]] = i
+	}
+	if len(last) == rows {
+		return valueStrings, valueArgs
+	}
+
+	strs := make([]string, 0, len(last))
+	args := make([]interface{}, 0, len(last)*width)
+	for i := 0; i < rows; i++ {
+		if last[rowKeys[i]] != i {
+			continue
+		}
+		strs = append(strs, valueStrings[i])
+		args = append(args, valueArgs[i*width:(i+1)*width]...)
+	}
+	return strs, args
+}
+
+// mssqlMerge returns a MERGE statement that updates the rows of the table
+// with the same keys as the values and inserts the remaining values.
+func mssqlMerge(table string, colNames, keyColumns, updates []string, values string) string {
+	on := make([]string, len(keyColumns))
+	for i, key := range keyColumns {
+		on[i] = fmt.Sprintf("target.%s = source.%s", key, key)
+	}
+	sourceCols := make([]string, len(colNames))
+	for i, col := range colNames {
+		sourceCols[i] = "source." + col
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "MERGE INTO %s AS target USING (VALUES %s) AS source (%s) ON %s",
+		table, values, strings.Join(colNames, ","), strings.Join(on, " AND "))
+	if len(updates) > 0 {
+		set := make([]string, len(updates))
+		for i, col := range updates {
+			set[i] = fmt.Sprintf("target.%s = source.%s", col, col)
+		}
+		fmt.Fprintf(&b, " WHEN MATCHED THEN UPDATE SET %s", strings.Join(set, ", "))
+	}
+	fmt.Fprintf(&b, " WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s);", strings.Join(colNames, ","), strings.Join(sourceCols, ","))
+	return b.String()
+}
+
+// deleteKeys returns a DELETE statement that removes the rows
+// with the same keys as any of the rows of the batch.
+func deleteKeys(s *ToSQLOpSpec, keys []int, width, rows int, valueArgs []interface{}) (string, []interface{}) {
+	conditions := make([]string, rows)
+	args := make([]interface{}, 0, rows*len(keys))
+	for i := 0; i < rows; i++ {
+		eq := make([]string, len(keys))
+		for j, key := range keys {
+			eq[j] = s.KeyColumns[j] + " = ?"
+			args = append(args, valueArgs[i*width+key])
+		}
+		conditions[i] = "(" + strings.Join(eq, " AND ") + ")"
+	}
+	where := numberPlaceholders(s.DriverName, strings.Join(conditions, " OR "))
+	return fmt.Sprintf("DELETE FROM %s WHERE %s", s.Table, where), args
+}
+
+func indexOf(ss []string, s string) int {
+	for i, v := range ss {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func contains(ss []string, s string) bool {
+	return indexOf(ss, s) >= 0
+}
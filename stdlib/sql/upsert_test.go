@@ -0,0 +1,329 @@
+package sql
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/dependenciestest"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/plan"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestWriteQueries(t *testing.T) {
+	colNames := []string{"host", "region", "value"}
+	valueStrings := []string{"(?,?,?)", "(?,?,?)"}
+	valueArgs := []interface{}{"a", "west", 1.0, "b", "east", 2.0}
+	testCases := []struct {
+		name        string
+		spec        *ToSQLOpSpec
+		wantQueries []string
+		wantArgs    [][]interface{}
+		wantErr     string
+	}{
+		{
+			name: "insert postgres",
+			spec: &ToSQLOpSpec{DriverName: "postgres", Table: "t", Mode: InsertMode},
+			wantQueries: []string{
+				"INSERT INTO t (host,region,value) VALUES ($1,$2,$3),($4,$5,$6)",
+			},
+			wantArgs: [][]interface{}{valueArgs},
+		},
+		{
+			name: "upsert postgres",
+			spec: &ToSQLOpSpec{DriverName: "postgres", Table: "t", Mode: UpsertMode, KeyColumns: []string{"host", "region"}},
+			wantQueries: []string{
+				"INSERT INTO t (host,region,value) VALUES ($1,$2,$3),($4,$5,$6) ON CONFLICT (host,region) DO UPDATE SET value = excluded.value",
+			},
+			wantArgs: [][]interface{}{valueArgs},
+		},
+		{
+			name: "upsert sqlite only keys",
+			spec: &ToSQLOpSpec{DriverName: "sqlite3", Table: "t", Mode: UpsertMode, KeyColumns: []string{"host", "region", "value"}},
+			wantQueries: []string{
+				"INSERT INTO t (host,region,value) VALUES (?,?,?),(?,?,?) ON CONFLICT (host,region,value) DO NOTHING",
+			},
+			wantArgs: [][]interface{}{valueArgs},
+		},
+		{
+			name: "upsert mysql",
+			spec: &ToSQLOpSpec{DriverName: "mysql", Table: "t", Mode: UpsertMode, KeyColumns: []string{"host"}},
+			wantQueries: []string{
+				"INSERT INTO t (host,region,value) VALUES (?,?,?),(?,?,?) ON DUPLICATE KEY UPDATE region = VALUES(region), value = VALUES(value)",
+			},
+			wantArgs: [][]interface{}{valueArgs},
+		},
+		{
+			name: "upsert mssql",
+			spec: &ToSQLOpSpec{DriverName: "sqlserver", Table: "t", Mode: UpsertMode, KeyColumns: []string{"host"}},
+			wantQueries: []string{
+				"MERGE INTO t AS target USING (VALUES (@p1,@p2,@p3),(@p4,@p5,@p6)) AS source (host,region,value) ON target.host = source.host" +
+					" WHEN MATCHED THEN UPDATE SET target.region = source.region, target.value = source.value" +
+					" WHEN NOT MATCHED THEN INSERT (host,region,value) VALUES (source.host,source.region,source.value);",
+			},
+			wantArgs: [][]interface{}{valueArgs},
+		},
+		{
+			name: "replace sqlite",
+			spec: &ToSQLOpSpec{DriverName: "sqlite3", Table: "t", Mode: ReplaceMode, KeyColumns: []string{"host"}},
+			wantQueries: []string{
+				"INSERT OR REPLACE INTO t (host,region,value) VALUES (?,?,?),(?,?,?)",
+			},
+			wantArgs: [][]interface{}{valueArgs},
+		},
+		{
+			name: "replace mysql",
+			spec: &ToSQLOpSpec{DriverName: "mysql", Table: "t", Mode: ReplaceMode, KeyColumns: []string{"host"}},
+			wantQueries: []string{
+				"REPLACE INTO t (host,region,value) VALUES (?,?,?),(?,?,?)",
+			},
+			wantArgs: [][]interface{}{valueArgs},
+		},
+		{
+			name: "replace postgres",
+			spec: &ToSQLOpSpec{DriverName: "postgres", Table: "t", Mode: ReplaceMode, KeyColumns: []string{"host", "region"}},
+			wantQueries: []string{
+				"DELETE FROM t WHERE (host = $1 AND region = $2) OR (host = $3 AND region = $4)",
+				"INSERT INTO t (host,region,value) VALUES ($1,$2,$3),($4,$5,$6)",
+			},
+			wantArgs: [][]interface{}{{"a", "west", "b", "east"}, valueArgs},
+		},
+		{
+			name:    "unknown key column",
+			spec:    &ToSQLOpSpec{DriverName: "postgres", Table: "t", Mode: UpsertMode, KeyColumns: []string{"_time"}},
+			wantErr: `key column "_time" is not a column of the table`,
+		},
+		{
+			name:    "unsupported driver",
+			spec:    &ToSQLOpSpec{DriverName: "bigquery", Table: "t", Mode: ReplaceMode, KeyColumns: []string{"host"}},
+			wantErr: `mode "replace" is not supported for bigquery`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			queries, args, err := writeQueries(tc.spec, colNames, valueStrings, valueArgs)
+			if tc.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q", tc.wantErr)
+				}
+				if got := err.Error(); got != tc.wantErr {
+					t.Fatalf("unexpected error -want/+got:\n\t- %q\n\t+ %q", tc.wantErr, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tc.wantQueries, queries) {
+				t.Errorf("unexpected queries -want/+got:\n%s", cmp.Diff(tc.wantQueries, queries))
+			}
+			if !cmp.Equal(tc.wantArgs, args) {
+				t.Errorf("unexpected args -want/+got:\n%s", cmp.Diff(tc.wantArgs, args))
+			}
+		})
+	}
+}
+
+func TestWriteQueries_DuplicateKeys(t *testing.T) {
+	colNames := []string{"host", "region", "value"}
+	valueStrings := []string{"(?,?,?)", "(?,?,?)", "(?,?,?)"}
+	valueArgs := []interface{}{"a", "west", 1.0, "b", "east", 2.0, "a", "west", 3.0}
+	testCases := []struct {
+		name        string
+		spec        *ToSQLOpSpec
+		wantQueries []string
+		wantArgs    [][]interface{}
+	}{
+		{
+			name: "insert postgres",
+			spec: &ToSQLOpSpec{DriverName: "postgres", Table: "t", Mode: InsertMode},
+			wantQueries: []string{
+				"INSERT INTO t (host,region,value) VALUES ($1,$2,$3),($4,$5,$6),($7,$8,$9)",
+			},
+			wantArgs: [][]interface{}{valueArgs},
+		},
+		{
+			name: "upsert postgres",
+			spec: &ToSQLOpSpec{DriverName: "postgres", Table: "t", Mode: UpsertMode, KeyColumns: []string{"host", "region"}},
+			wantQueries: []string{
+				"INSERT INTO t (host,region,value) VALUES ($1,$2,$3),($4,$5,$6) ON CONFLICT (host,region) DO UPDATE SET value = excluded.value",
+			},
+			wantArgs: [][]interface{}{{"b", "east", 2.0, "a", "west", 3.0}},
+		},
+		{
+			name: "upsert mssql",
+			spec: &ToSQLOpSpec{DriverName: "sqlserver", Table: "t", Mode: UpsertMode, KeyColumns: []string{"host"}},
+			wantQueries: []string{
+				"MERGE INTO t AS target USING (VALUES (@p1,@p2,@p3),(@p4,@p5,@p6)) AS source (host,region,value) ON target.host = source.host" +
+					" WHEN MATCHED THEN UPDATE SET target.region = source.region, target.value = source.value" +
+					" WHEN NOT MATCHED THEN INSERT (host,region,value) VALUES (source.host,source.region,source.value);",
+			},
+			wantArgs: [][]interface{}{{"b", "east", 2.0, "a", "west", 3.0}},
+		},
+		{
+			name: "upsert postgres distinct keys",
+			spec: &ToSQLOpSpec{DriverName: "postgres", Table: "t", Mode: UpsertMode, KeyColumns: []string{"value"}},
+			wantQueries: []string{
+				"INSERT INTO t (host,region,value) VALUES ($1,$2,$3),($4,$5,$6),($7,$8,$9) ON CONFLICT (value) DO UPDATE SET host = excluded.host, region = excluded.region",
+			},
+			wantArgs: [][]interface{}{valueArgs},
+		},
+		{
+			name: "replace postgres",
+			spec: &ToSQLOpSpec{DriverName: "postgres", Table: "t", Mode: ReplaceMode, KeyColumns: []string{"host"}},
+			wantQueries: []string{
+				"DELETE FROM t WHERE (host = $1) OR (host = $2)",
+				"INSERT INTO t (host,region,value) VALUES ($1,$2,$3),($4,$5,$6)",
+			},
+			wantArgs: [][]interface{}{{"b", "a"}, {"b", "east", 2.0, "a", "west", 3.0}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			queries, args, err := writeQueries(tc.spec, colNames, valueStrings, valueArgs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tc.wantQueries, queries) {
+				t.Errorf("unexpected queries -want/+got:\n%s", cmp.Diff(tc.wantQueries, queries))
+			}
+			if !cmp.Equal(tc.wantArgs, args) {
+				t.Errorf("unexpected args -want/+got:\n%s", cmp.Diff(tc.wantArgs, args))
+			}
+		})
+	}
+}
+
+func TestToSQLite3_Modes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dsn := filepath.Join(dir, "test.db")
+
+	cols := []flux.ColMeta{
+		{Label: "host", Type: flux.TString},
+		{Label: "region", Type: flux.TString},
+		{Label: "value", Type: flux.TFloat},
+	}
+	write := func(t *testing.T, mode string, createTable bool, cols []flux.ColMeta, data [][]interface{}) {
+		t.Helper()
+		d := executetest.NewDataset(executetest.RandomDatasetID())
+		c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+		c.SetTriggerSpec(plan.DefaultTriggerSpec)
+		spec := &ToSQLProcedureSpec{
+			Spec: &ToSQLOpSpec{
+				DriverName:     "sqlite3",
+				DataSourceName: dsn,
+				Table:          "cpu",
+				BatchSize:      DefaultBatchSize,
+				Mode:           mode,
+				KeyColumns:     []string{"host"},
+				CreateTable:    createTable,
+			},
+		}
+		transformation, err := NewToSQLTransformation(d, dependenciestest.Default(), c, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tbl := executetest.MustCopyTable(&executetest.Table{ColMeta: cols, Data: data})
+		err = transformation.Process(executetest.RandomDatasetID(), tbl)
+		transformation.Finish(executetest.RandomDatasetID(), err)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func(t *testing.T) [][]interface{} {
+		t.Helper()
+		db, err := sql.Open("sqlite3", dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		rows, err := db.Query("SELECT host, region, value FROM cpu ORDER BY host")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		var got [][]interface{}
+		for rows.Next() {
+			var host string
+			var region sql.NullString
+			var value sql.NullFloat64
+			if err := rows.Scan(&host, &region, &value); err != nil {
+				t.Fatal(err)
+			}
+			row := []interface{}{host, nil, nil}
+			if region.Valid {
+				row[1] = region.String
+			}
+			if value.Valid {
+				row[2] = value.Float64
+			}
+			got = append(got, row)
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	write(t, InsertMode, true, cols, [][]interface{}{
+		{"a", "west", 1.0},
+		{"b", "east", 2.0},
+	})
+	write(t, UpsertMode, false, []flux.ColMeta{cols[0], cols[2]}, [][]interface{}{
+		{"a", 3.0},
+		{"c", 4.0},
+	})
+	want := [][]interface{}{
+		{"a", "west", 3.0},
+		{"b", "east", 2.0},
+		{"c", nil, 4.0},
+	}
+	if got := read(t); !cmp.Equal(want, got) {
+		t.Fatalf("unexpected rows after upsert -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	write(t, ReplaceMode, false, []flux.ColMeta{cols[0], cols[2]}, [][]interface{}{
+		{"b", 5.0},
+	})
+	want = [][]interface{}{
+		{"a", "west", 3.0},
+		{"b", nil, 5.0},
+		{"c", nil, 4.0},
+	}
+	if got := read(t); !cmp.Equal(want, got) {
+		t.Fatalf("unexpected rows after replace -want/+got:\n%s", cmp.Diff(want, got))
+	}
+
+	// Inserting a row with an existing key fails because of the primary key.
+	d := executetest.NewDataset(executetest.RandomDatasetID())
+	c := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	c.SetTriggerSpec(plan.DefaultTriggerSpec)
+	transformation, err := NewToSQLTransformation(d, dependenciestest.Default(), c, &ToSQLProcedureSpec{
+		Spec: &ToSQLOpSpec{
+			DriverName:     "sqlite3",
+			DataSourceName: dsn,
+			Table:          "cpu",
+			BatchSize:      DefaultBatchSize,
+			Mode:           InsertMode,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tbl := executetest.MustCopyTable(&executetest.Table{ColMeta: cols, Data: [][]interface{}{{"a", "west", 6.0}}})
+	if err := transformation.Process(executetest.RandomDatasetID(), tbl); err == nil {
+		t.Fatal("expected insert of an existing key to fail")
+	}
+}
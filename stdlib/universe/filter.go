@@ -83,7 +83,6 @@ func (s *FilterOpSpec) Kind() flux.OperationKind {
 }
 
 type FilterProcedureSpec struct {
-	plan.DefaultCost
 	Fn              interpreter.ResolvedFunction
 	KeepEmptyTables bool
 }
@@ -115,6 +114,15 @@ func (s *FilterProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// Cost assumes that the predicate keeps a fixed fraction of the input.
+func (s *FilterProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	in := plan.CombineStatistics(inStats...)
+	if IsTrivialPredicate(s.Fn.Fn) {
+		return plan.Cost{CPU: in.Cardinality}, in
+	}
+	return plan.Cost{CPU: in.Cardinality}, in.Scale(plan.DefaultFilterSelectivity)
+}
+
 // TriggerSpec implements plan.TriggerAwareProcedureSpec
 func (s *FilterProcedureSpec) TriggerSpec() plan.TriggerSpec {
 	return plan.NarrowTransformationTriggerSpec{}
@@ -304,6 +312,15 @@ func (t *filterTransformation) filterTable(fn *execute.RowPredicatePreparedFn, i
 }
 
 func (t *filterTransformation) filter(fn *execute.RowPredicatePreparedFn, cr flux.ColReader, record values.Object, indices []int) (*arrowmem.Buffer, error) {
+	if fn.Vectorized() {
+		// Evaluate the whole column reader at once. If the kernels
+		// fail, fall back to evaluating each row so the error
+		// is the same one the row evaluator would report.
+		if bitset, err := t.filterBatch(fn, cr); err == nil {
+			return bitset, nil
+		}
+	}
+
 	cols, l := cr.Cols(), cr.Len()
 	bitset := arrowmem.NewResizableBuffer(t.alloc)
 	bitset.Resize(l)
@@ -322,6 +339,22 @@ func (t *filterTransformation) filter(fn *execute.RowPredicatePreparedFn, cr flu
 	return bitset, nil
 }
 
+func (t *filterTransformation) filterBatch(fn *execute.RowPredicatePreparedFn, cr flux.ColReader) (*arrowmem.Buffer, error) {
+	vs, err := fn.EvalBatch(t.ctx, cr, t.alloc)
+	if err != nil {
+		return nil, err
+	}
+	defer vs.Release()
+
+	l := vs.Len()
+	bitset := arrowmem.NewResizableBuffer(t.alloc)
+	bitset.Resize(l)
+	for i := 0; i < l; i++ {
+		bitutil.SetBitTo(bitset.Buf(), i, vs.IsValid(i) && vs.Value(i))
+	}
+	return bitset, nil
+}
+
 func (t *filterTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
 	return t.d.UpdateWatermark(mark)
 }
@@ -345,22 +378,30 @@ func (RemoveTrivialFilterRule) Pattern() plan.Pattern {
 
 func (RemoveTrivialFilterRule) Rewrite(ctx context.Context, filterNode plan.Node) (plan.Node, bool, error) {
 	filterSpec := filterNode.ProcedureSpec().(*FilterProcedureSpec)
-	if filterSpec.Fn.Fn == nil ||
-		filterSpec.Fn.Fn.Block == nil ||
-		filterSpec.Fn.Fn.Block.Body == nil {
+	if !IsTrivialPredicate(filterSpec.Fn.Fn) {
 		return filterNode, false, nil
 	}
 
-	if bodyExpr, ok := filterSpec.Fn.Fn.GetFunctionBodyExpression(); !ok {
+	anyNode := filterNode.Predecessors()[0]
+	return anyNode, true, nil
+}
+
+// IsTrivialPredicate reports whether the predicate of a filter always evaluates to true.
+func IsTrivialPredicate(fn *semantic.FunctionExpression) bool {
+	if fn == nil ||
+		fn.Block == nil ||
+		fn.Block.Body == nil {
+		return false
+	}
+
+	if bodyExpr, ok := fn.GetFunctionBodyExpression(); !ok {
 		// Not an expression.
-		return filterNode, false, nil
+		return false
 	} else if expr, ok := bodyExpr.(*semantic.BooleanLiteral); !ok || !expr.Value {
 		// Either not a boolean at all, or evaluates to false.
-		return filterNode, false, nil
+		return false
 	}
-
-	anyNode := filterNode.Predecessors()[0]
-	return anyNode, true, nil
+	return true
 }
 
 // MergeFiltersRule merges Filter nodes whose body is a single return to create one Filter node.
@@ -7,6 +7,7 @@ import (
 	"github.com/apache/arrow/go/arrow/array"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/internal/execute/table"
@@ -135,6 +136,10 @@ func createGroupTransformation(id execute.DatasetID, mode execute.AccumulationMo
 	if !ok {
 		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
 	}
+	if fs, dir, ok := spillLocation(a.Context(), a.Allocator()); ok {
+		t, d := NewGroupSpillTransformation(s, id, a.Allocator(), fs, dir)
+		return t, d, nil
+	}
 	t, d := NewGroupTransformation(s, id, a.Allocator())
 	return t, d, nil
 }
@@ -145,11 +150,28 @@ type groupTransformation struct {
 	cache table.BuilderCache
 	mem   *memory.Allocator
 
+	// fs and dir are where the buffered tables are spilled
+	// when the allocator goes over its memory limit.
+	// Nothing is spilled if fs is nil.
+	fs  filesystem.Service
+	dir string
+
 	mode flux.GroupMode
 	keys []string
 }
 
 func NewGroupTransformation(spec *GroupProcedureSpec, id execute.DatasetID, mem *memory.Allocator) (execute.Transformation, execute.Dataset) {
+	return newGroupTransformation(spec, id, mem, nil, "")
+}
+
+// NewGroupSpillTransformation constructs a group transformation that
+// writes the buffered tables to dir within the filesystem service
+// when the allocator goes over its memory limit.
+func NewGroupSpillTransformation(spec *GroupProcedureSpec, id execute.DatasetID, mem *memory.Allocator, fs filesystem.Service, dir string) (execute.Transformation, execute.Dataset) {
+	return newGroupTransformation(spec, id, mem, fs, dir)
+}
+
+func newGroupTransformation(spec *GroupProcedureSpec, id execute.DatasetID, mem *memory.Allocator, fs filesystem.Service, dir string) (execute.Transformation, execute.Dataset) {
 	t := &groupTransformation{
 		cache: table.BuilderCache{
 			New: func(key flux.GroupKey) table.Builder {
@@ -157,6 +179,8 @@ func NewGroupTransformation(spec *GroupProcedureSpec, id execute.DatasetID, mem
 			},
 		},
 		mem:  mem,
+		fs:   fs,
+		dir:  dir,
 		mode: spec.GroupMode,
 		keys: spec.GroupKeys,
 	}
@@ -170,6 +194,13 @@ func (t *groupTransformation) RetractTable(id execute.DatasetID, key flux.GroupK
 }
 
 func (t *groupTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	if err := t.process(tbl); err != nil {
+		return err
+	}
+	return t.spill()
+}
+
+func (t *groupTransformation) process(tbl flux.Table) error {
 	// Determine the group key of this table if the grouped columns
 	// are all part of the group key.
 	if key, ok, err := t.getTableKey(tbl); err != nil {
@@ -186,6 +217,21 @@ func (t *groupTransformation) Process(id execute.DatasetID, tbl flux.Table) erro
 	return t.groupByRow(tbl)
 }
 
+// spill writes the buffered tables to disk if the
+// allocator has gone over its memory limit.
+func (t *groupTransformation) spill() error {
+	if t.fs == nil || !t.mem.ShouldSpill() {
+		return nil
+	}
+	if err := t.cache.ForEach(func(key flux.GroupKey, builder table.Builder) error {
+		return builder.(*table.BufferedBuilder).Spill(t.fs, t.dir)
+	}); err != nil {
+		return err
+	}
+	t.mem.ReturnMemory()
+	return nil
+}
+
 // getTableKey returns the table key if the entire table matches
 // the same table key. If the entire table does not match the key,
 // this will return false and no key will be returned.
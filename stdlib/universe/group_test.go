@@ -3,10 +3,13 @@ package universe_test
 import (
 	"context"
 	"errors"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/filesystem"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
 	"github.com/influxdata/flux/internal/gen"
@@ -781,6 +784,108 @@ func TestGroup_Process(t *testing.T) {
 	}
 }
 
+func TestGroup_Process_Spill(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flux-group-spill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	spec := &universe.GroupProcedureSpec{
+		GroupMode: flux.GroupModeBy,
+		GroupKeys: []string{"t0"},
+	}
+	data := []flux.Table{
+		&executetest.RowWiseTable{Table: &executetest.Table{
+			KeyCols: []string{"t0", "t1"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "t0", Type: flux.TString},
+				{Label: "t1", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), 1.0, "a", "x"},
+				{execute.Time(2), 2.0, "a", "x"},
+			},
+		}},
+		&executetest.RowWiseTable{Table: &executetest.Table{
+			KeyCols: []string{"t0", "t1"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "t0", Type: flux.TString},
+				{Label: "t1", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), 3.0, "a", "y"},
+				{execute.Time(2), 4.0, "a", "y"},
+			},
+		}},
+		&executetest.RowWiseTable{Table: &executetest.Table{
+			KeyCols: []string{"t0", "t1"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "t0", Type: flux.TString},
+				{Label: "t1", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), 5.0, "b", "x"},
+			},
+		}},
+	}
+	want := []*executetest.Table{
+		{
+			KeyCols: []string{"t0"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "t0", Type: flux.TString},
+				{Label: "t1", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), 1.0, "a", "x"},
+				{execute.Time(2), 2.0, "a", "x"},
+				{execute.Time(1), 3.0, "a", "y"},
+				{execute.Time(2), 4.0, "a", "y"},
+			},
+		},
+		{
+			KeyCols: []string{"t0"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_value", Type: flux.TFloat},
+				{Label: "t0", Type: flux.TString},
+				{Label: "t1", Type: flux.TString},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), 5.0, "b", "x"},
+			},
+		},
+	}
+	executetest.ProcessTestHelper2(
+		t,
+		data,
+		want,
+		nil,
+		func(id execute.DatasetID, alloc *memory.Allocator) (execute.Transformation, execute.Dataset) {
+			// Use a limit that is always exceeded so
+			// every buffer is spilled to disk.
+			limit := int64(1)
+			alloc.Limit = &limit
+			alloc.Manager = memory.NewSpillManager(1<<20, dir)
+			return universe.NewGroupSpillTransformation(spec, id, alloc, filesystem.SystemFS, dir)
+		},
+	)
+
+	if files, err := ioutil.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(files) != 0 {
+		t.Errorf("expected spill files to be removed, found %d", len(files))
+	}
+}
+
 func TestMergeGroupRule(t *testing.T) {
 	var (
 		from      = &influxdb.FromProcedureSpec{}
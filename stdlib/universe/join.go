@@ -33,6 +33,9 @@ func init() {
 // All supported join types in Flux
 var methods = map[string]bool{
 	"inner": true,
+	"left":  true,
+	"right": true,
+	"full":  true,
 }
 
 // JoinOpSpec specifies a particular join operation
@@ -160,9 +163,9 @@ func (s *JoinOpSpec) Kind() flux.OperationKind {
 }
 
 type MergeJoinProcedureSpec struct {
-	plan.DefaultCost
 	TableNames []string `json:"table_names"`
 	On         []string `json:"keys"`
+	Method     string   `json:"method"`
 }
 
 func newMergeJoinProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
@@ -189,6 +192,7 @@ func newMergeJoinProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.
 	return &MergeJoinProcedureSpec{
 		On:         on,
 		TableNames: tableNames,
+		Method:     spec.Method,
 	}, nil
 }
 
@@ -201,9 +205,39 @@ func (s *MergeJoinProcedureSpec) Copy() plan.ProcedureSpec {
 	ns.On = make([]string, len(s.On))
 	copy(ns.On, s.On)
 
+	ns.TableNames = make([]string, len(s.TableNames))
+	copy(ns.TableNames, s.TableNames)
+
+	ns.Method = s.Method
+
 	return ns
 }
 
+// Cost accounts for buffering both inputs in memory.
+// Each row of an inner join is assumed to match at most one row
+// from the other side, and a full join keeps every row.
+func (s *MergeJoinProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	in := plan.CombineStatistics(inStats...)
+	cost := plan.Cost{
+		CPU: in.Cardinality,
+		MEM: in.Cardinality,
+	}
+	if s.Method == "full" {
+		return cost, in
+	}
+
+	var out plan.Statistics
+	for _, stats := range inStats {
+		if stats.Cardinality > out.Cardinality {
+			out.Cardinality = stats.Cardinality
+		}
+		if stats.GroupCardinality > out.GroupCardinality {
+			out.GroupCardinality = stats.GroupCardinality
+		}
+	}
+	return cost, out
+}
+
 func createMergeJoinTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
 	s, ok := spec.(*MergeJoinProcedureSpec)
 	if !ok {
@@ -239,7 +273,8 @@ type mergeJoinTransformation struct {
 	parentState map[execute.DatasetID]*mergeJoinParentState
 	err         error
 
-	keys []string
+	keys   []string
+	method string
 }
 
 func NewMergeJoinTransformation(d execute.Dataset, cache *MergeJoinCache, spec *MergeJoinProcedureSpec, parents []execute.DatasetID, tableNames map[execute.DatasetID]string) *mergeJoinTransformation {
@@ -247,6 +282,7 @@ func NewMergeJoinTransformation(d execute.Dataset, cache *MergeJoinCache, spec *
 		d:         d,
 		cache:     cache,
 		keys:      spec.On,
+		method:    spec.Method,
 		leftID:    parents[0],
 		rightID:   parents[1],
 		leftName:  tableNames[parents[0]],
@@ -269,9 +305,29 @@ func (t *mergeJoinTransformation) RetractTable(id execute.DatasetID, key flux.Gr
 	panic("not implemented")
 }
 
+// isOuter reports whether the join keeps the unmatched rows of either input.
+func (t *mergeJoinTransformation) isOuter() bool {
+	return t.method == "left" || t.method == "right" || t.method == "full"
+}
+
+// preserves reports whether the unmatched rows from the input
+// associated with id are part of the output.
+func (t *mergeJoinTransformation) preserves(id execute.DatasetID) bool {
+	switch id {
+	case t.leftID:
+		return t.method == "left" || t.method == "full"
+	case t.rightID:
+		return t.method == "right" || t.method == "full"
+	}
+	return false
+}
+
 // Process processes a table from an incoming data stream.
 // It adds the table to an internal buffer and stores any output
 // group keys that can be constructed as a result of the new addition.
+//
+// Outer joins cannot produce any output until every row of the opposing
+// stream has been seen, so their output is computed when the join finishes.
 func (t *mergeJoinTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -291,10 +347,14 @@ func (t *mergeJoinTransformation) Process(id execute.DatasetID, tbl flux.Table)
 		return nil
 	}
 
-	if err := t.cache.insertIntoBuffer(id, tbl); err != nil {
+	if err := t.cache.insertIntoBuffer(id, tbl, t.preserves(id)); err != nil {
 		return err
 	}
 
+	if t.isOuter() {
+		return nil
+	}
+
 	// Check if enough data sources have been seen to produce an output schema
 	if !t.cache.isBufferEmpty(t.leftID) && !t.cache.isBufferEmpty(t.rightID) && !t.cache.postJoinSchemaBuilt() {
 		t.cache.buildPostJoinSchema()
@@ -351,6 +411,9 @@ func (t *mergeJoinTransformation) Finish(id execute.DatasetID, err error) {
 	}
 
 	if finished {
+		if t.err == nil && t.isOuter() {
+			t.err = t.cache.joinOuter(t.preserves(t.leftID), t.preserves(t.rightID))
+		}
 		t.d.Finish(t.err)
 	}
 }
@@ -552,6 +615,10 @@ func NewMergeJoinCache(alloc *memory.Allocator, datasetIDs []execute.DatasetID,
 
 // Table joins the two tables associated with a single output group key and returns the resulting table
 func (c *MergeJoinCache) Table(key flux.GroupKey) (flux.Table, error) {
+	if table, ok := c.tables[key]; ok {
+		return table, nil
+	}
+
 	preJoinGroupKeys, ok := c.reverseLookup[key]
 
 	if !ok {
@@ -612,7 +679,20 @@ func (c *MergeJoinCache) ForEachWithContext(f func(flux.GroupKey, execute.Trigge
 
 	c.postJoinKeys.Range(func(key flux.GroupKey, value interface{}) {
 
-		preJoinGroupKeys := c.reverseLookup[key]
+		preJoinGroupKeys, ok := c.reverseLookup[key]
+		if !ok {
+			// The table was produced by an outer join
+			// and has already been materialized.
+			table, ok := c.tables[key]
+			if !ok {
+				return
+			}
+			f(key, trigger, execute.TableContext{
+				Key:   key,
+				Count: table.(flux.ColReader).Len(),
+			})
+			return
+		}
 
 		leftKey := preJoinGroupKeys.left
 		rightKey := preJoinGroupKeys.right
@@ -659,7 +739,11 @@ func (c *MergeJoinCache) ExpireTable(key flux.GroupKey) {
 	delete(c.tables, key)
 
 	// Clear any stale data
-	preJoinGroupKeys := c.reverseLookup[key]
+	preJoinGroupKeys, ok := c.reverseLookup[key]
+	if !ok {
+		// Tables produced by an outer join do not reference the buffers.
+		return
+	}
 
 	leftBuffer := c.buffers[c.leftID]
 	rightBuffer := c.buffers[c.rightID]
@@ -695,8 +779,10 @@ func (c *MergeJoinCache) canEvictTables() bool {
 		leftKey[0].Label == rightKey[0].Label && c.on[leftKey[0].Label]
 }
 
-// insertIntoBuffer adds the rows of an incoming table to one of the Join's internal buffers
-func (c *MergeJoinCache) insertIntoBuffer(id execute.DatasetID, tbl flux.Table) error {
+// insertIntoBuffer adds the rows of an incoming table to one of the Join's internal buffers.
+// When preserve is set the table is kept even if it cannot match any rows
+// from the other stream, since its rows are part of the output of an outer join.
+func (c *MergeJoinCache) insertIntoBuffer(id execute.DatasetID, tbl flux.Table, preserve bool) error {
 	// Initialize schema if tbl is first from its stream
 	if _, ok := c.schemas[id]; !ok {
 
@@ -725,7 +811,7 @@ func (c *MergeJoinCache) insertIntoBuffer(id execute.DatasetID, tbl flux.Table)
 	// since null != null for joining purposes.
 	k := tbl.Key()
 	for j, col := range k.Cols() {
-		if c.on[col.Label] {
+		if c.on[col.Label] && !preserve {
 			if k.IsNull(j) {
 				// Discard the table and return.  Note: we need to iterate over the
 				// table at least once:
@@ -843,6 +929,21 @@ func (c *MergeJoinCache) buildPostJoinSchema() {
 	}
 }
 
+// inferMissingSchema gives a stream that did not produce any tables the
+// schema of the other stream. The columns of a stream are only known from
+// its tables, so without this the columns of the other stream would not be
+// renamed and the output schema would depend on whether a stream is empty.
+// The columns that come from the empty stream are null.
+func (c *MergeJoinCache) inferMissingSchema() {
+	left, hasLeft := c.schemas[c.leftID]
+	right, hasRight := c.schemas[c.rightID]
+	if hasLeft && !hasRight {
+		c.schemas[c.rightID] = left
+	} else if hasRight && !hasLeft {
+		c.schemas[c.leftID] = right
+	}
+}
+
 // equalJoinKeys compares two keys for equality.
 // Null values are not considered equal when joining (unlike when grouping).
 func equalJoinkeys(left, right flux.GroupKey) bool {
@@ -861,12 +962,6 @@ func (c *MergeJoinCache) join(left, right *execute.ColListTableBuilder) (flux.Ta
 	left.Sort(c.order, false)
 	right.Sort(c.order, false)
 
-	var leftSet, rightSet subset
-	var leftKey, rightKey flux.GroupKey
-
-	leftSet, leftKey = c.advance(leftSet.Stop, left)
-	rightSet, rightKey = c.advance(rightSet.Stop, right)
-
 	keys := map[execute.DatasetID]flux.GroupKey{
 		c.leftID:  left.Key(),
 		c.rightID: right.Key(),
@@ -883,12 +978,32 @@ func (c *MergeJoinCache) join(left, right *execute.ColListTableBuilder) (flux.Ta
 		}
 	}
 
+	c.mergeJoin(left, right, builder, nil, nil)
+	return builder.Table()
+}
+
+// mergeJoin appends the rows produced by joining two sorted tables to the builder.
+// If leftMatched or rightMatched are non-nil, the index of every row
+// from the corresponding table that was joined is marked.
+func (c *MergeJoinCache) mergeJoin(left, right, builder *execute.ColListTableBuilder, leftMatched, rightMatched []bool) {
+	var leftSet, rightSet subset
+	var leftKey, rightKey flux.GroupKey
+
+	leftSet, leftKey = c.advance(leftSet.Stop, left)
+	rightSet, rightKey = c.advance(rightSet.Stop, right)
+
 	// Perform sort merge join
 	for !leftSet.Empty() && !rightSet.Empty() {
 		if equalJoinkeys(leftKey, rightKey) {
 
 			for l := leftSet.Start; l < leftSet.Stop; l++ {
+				if leftMatched != nil {
+					leftMatched[l] = true
+				}
 				for r := rightSet.Start; r < rightSet.Stop; r++ {
+					if rightMatched != nil {
+						rightMatched[r] = true
+					}
 
 					leftRecord := left.GetRow(l)
 					rightRecord := right.GetRow(r)
@@ -928,7 +1043,202 @@ func (c *MergeJoinCache) join(left, right *execute.ColListTableBuilder) (flux.Ta
 		}
 	}
 
-	return builder.Table()
+}
+
+// joinOuter joins every table in the buffers once both streams have finished.
+// Rows that are not matched by any row from the opposing stream are added
+// to the output when their stream is preserved, with nulls in the columns
+// that come from the opposing stream. The output tables are stored in the
+// cache and indexed by their post-join group key.
+func (c *MergeJoinCache) joinOuter(preserveLeft, preserveRight bool) error {
+	if !c.postJoinSchemaBuilt() {
+		c.inferMissingSchema()
+		c.buildPostJoinSchema()
+	}
+
+	leftKeys := c.sortedBufferKeys(c.leftID)
+	rightKeys := c.sortedBufferKeys(c.rightID)
+
+	leftMatched := make(map[flux.GroupKey][]bool, len(leftKeys))
+	for _, key := range leftKeys {
+		left := c.buffers[c.leftID].table(key)
+		left.Sort(c.order, false)
+		leftMatched[key] = make([]bool, left.NRows())
+	}
+	rightMatched := make(map[flux.GroupKey][]bool, len(rightKeys))
+	for _, key := range rightKeys {
+		right := c.buffers[c.rightID].table(key)
+		right.Sort(c.order, false)
+		rightMatched[key] = make([]bool, right.NRows())
+	}
+
+	builders := execute.NewGroupLookup()
+	for _, leftKey := range leftKeys {
+		for _, rightKey := range rightKeys {
+			if !c.joinable(leftKey, rightKey) {
+				continue
+			}
+			key := c.postJoinGroupKey(map[execute.DatasetID]flux.GroupKey{
+				c.leftID:  leftKey,
+				c.rightID: rightKey,
+			})
+			builder, err := c.outputBuilder(builders, key)
+			if err != nil {
+				return err
+			}
+			left := c.buffers[c.leftID].table(leftKey)
+			right := c.buffers[c.rightID].table(rightKey)
+			c.mergeJoin(left, right, builder, leftMatched[leftKey], rightMatched[rightKey])
+		}
+	}
+
+	if preserveLeft {
+		if err := c.appendUnmatched(c.leftID, c.rightID, leftKeys, leftMatched, builders); err != nil {
+			return err
+		}
+	}
+	if preserveRight {
+		if err := c.appendUnmatched(c.rightID, c.leftID, rightKeys, rightMatched, builders); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	var empty struct{}
+	builders.Range(func(key flux.GroupKey, value interface{}) {
+		builder := value.(*execute.ColListTableBuilder)
+		if err != nil || builder.NRows() == 0 {
+			return
+		}
+		builder.Sort(c.order, false)
+
+		var table flux.Table
+		table, err = builder.Table()
+		if err != nil {
+			return
+		}
+		c.tables[key] = table
+		c.postJoinKeys.Set(key, empty)
+	})
+	return err
+}
+
+// sortedBufferKeys returns the group keys of the tables
+// in the buffer for id in a consistent order.
+func (c *MergeJoinCache) sortedBufferKeys(id execute.DatasetID) []flux.GroupKey {
+	keys := make([]flux.GroupKey, 0, len(c.buffers[id].data))
+	c.buffers[id].iterate(func(key flux.GroupKey) {
+		keys = append(keys, key)
+	})
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Less(keys[j])
+	})
+	return keys
+}
+
+// joinable reports whether the tables with the given group keys
+// may contain rows that join with each other.
+func (c *MergeJoinCache) joinable(left, right flux.GroupKey) bool {
+	for k := range c.intersection {
+		if !left.LabelValue(k).Equal(right.LabelValue(k)) {
+			return false
+		}
+	}
+	return true
+}
+
+// outputBuilder returns the builder for the output table with the given
+// group key, creating it if it does not exist.
+func (c *MergeJoinCache) outputBuilder(builders *execute.GroupLookup, key flux.GroupKey) (*execute.ColListTableBuilder, error) {
+	if value, ok := builders.Lookup(key); ok {
+		return value.(*execute.ColListTableBuilder), nil
+	}
+	builder := execute.NewColListTableBuilder(key, c.alloc)
+	for _, column := range c.schema.columns {
+		if _, err := builder.AddCol(column); err != nil {
+			return nil, err
+		}
+	}
+	builders.Set(key, builder)
+	return builder, nil
+}
+
+// appendUnmatched appends the rows from the buffer for id that did not
+// join with any row from the other stream. The columns that come from the
+// other stream are null, including its group key columns.
+func (c *MergeJoinCache) appendUnmatched(id, other execute.DatasetID, keys []flux.GroupKey, matched map[flux.GroupKey][]bool, builders *execute.GroupLookup) error {
+	// The group key columns of the other stream that are not
+	// join columns have null values for the unmatched rows.
+	var nullCols []flux.ColMeta
+	var nullVals []values.Value
+	for _, col := range c.schemas[other].key {
+		if c.on[col.Label] {
+			continue
+		}
+		nullCols = append(nullCols, col)
+		nullVals = append(nullVals, values.NewNull(flux.SemanticType(col.Type)))
+	}
+	nullKey := execute.NewGroupKey(nullCols, nullVals)
+
+	for _, key := range keys {
+		tbl := c.buffers[id].table(key)
+		var builder *execute.ColListTableBuilder
+		for i, ok := range matched[key] {
+			if ok {
+				continue
+			}
+			if builder == nil {
+				outputKey := c.postJoinGroupKey(map[execute.DatasetID]flux.GroupKey{
+					id:    key,
+					other: nullKey,
+				})
+				b, err := c.outputBuilder(builders, outputKey)
+				if err != nil {
+					return err
+				}
+				builder = b
+			}
+			if err := c.appendUnmatchedRow(id, tbl, i, builder); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// appendUnmatchedRow appends a single row from the buffer for id
+// to the builder and fills every other column with null.
+func (c *MergeJoinCache) appendUnmatchedRow(id execute.DatasetID, tbl *execute.ColListTableBuilder, i int, builder *execute.ColListTableBuilder) error {
+	var err error
+	appended := make([]bool, len(c.schema.columns))
+	tbl.GetRow(i).Range(func(columnName string, columnVal values.Value) {
+		if err != nil {
+			return
+		}
+		column, ok := c.schemaMap[tableCol{
+			table: c.names[id],
+			col:   columnName,
+		}]
+		if !ok {
+			return
+		}
+		j := c.colIndex[column]
+		if err = builder.AppendValue(j, columnVal); err == nil {
+			appended[j] = true
+		}
+	})
+	if err != nil {
+		return err
+	}
+	for j, ok := range appended {
+		if ok {
+			continue
+		}
+		if err := builder.AppendNil(j); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // postJoinGroupKey produces a new group key value from a left and a right group key value
@@ -4,12 +4,14 @@ import (
 	"context"
 	"sort"
 
+	"github.com/apache/arrow/go/arrow/array"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
 	"github.com/influxdata/flux/compiler"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
 	"github.com/influxdata/flux/semantic"
@@ -103,7 +105,7 @@ func createMapTransformation(id execute.DatasetID, mode execute.AccumulationMode
 	}
 	cache := execute.NewTableBuilderCache(a.Allocator())
 	d := execute.NewDataset(id, mode, cache)
-	t, err := NewMapTransformation(a.Context(), s, d, cache)
+	t, err := NewMapTransformationWithAllocator(a.Context(), s, d, cache, a.Allocator())
 
 	if err != nil {
 		return nil, nil, err
@@ -118,9 +120,16 @@ type mapTransformation struct {
 	ctx      context.Context
 	fn       *execute.RowMapFn
 	mergeKey bool
+	alloc    *memory.Allocator
 }
 
 func NewMapTransformation(ctx context.Context, spec *MapProcedureSpec, d execute.Dataset, cache execute.TableBuilderCache) (*mapTransformation, error) {
+	return NewMapTransformationWithAllocator(ctx, spec, d, cache, &memory.Allocator{})
+}
+
+// NewMapTransformationWithAllocator creates a map transformation
+// that accounts the memory of the vectorized function with alloc.
+func NewMapTransformationWithAllocator(ctx context.Context, spec *MapProcedureSpec, d execute.Dataset, cache execute.TableBuilderCache, alloc *memory.Allocator) (*mapTransformation, error) {
 	fn := execute.NewRowMapFn(spec.Fn.Fn, compiler.ToScope(spec.Fn.Scope))
 	return &mapTransformation{
 		d:        d,
@@ -128,6 +137,7 @@ func NewMapTransformation(ctx context.Context, spec *MapProcedureSpec, d execute
 		fn:       fn,
 		ctx:      ctx,
 		mergeKey: spec.MergeKey,
+		alloc:    alloc,
 	}, nil
 }
 
@@ -150,47 +160,205 @@ func (t *mapTransformation) Process(id execute.DatasetID, tbl flux.Table) error
 
 	var on map[string]bool
 	return tbl.Do(func(cr flux.ColReader) error {
+		if fn.Vectorized() {
+			// Evaluate the whole column reader at once. If the kernels
+			// fail, fall back to evaluating each row so the error
+			// is the same one the row evaluator would report.
+			if vec, err := fn.EvalBatch(t.ctx, cr, t.alloc); err == nil {
+				defer vec.Release()
+				return t.appendVector(tbl.Key(), fn, cr, vec, &on)
+			}
+		}
+
 		l := cr.Len()
 		for i := 0; i < l; i++ {
 			m, err := fn.Eval(t.ctx, i, cr)
 			if err != nil {
 				return errors.Wrap(err, codes.Inherit, "failed to evaluate map function")
 			}
+			if err := t.appendRow(tbl.Key(), fn, cr, i, m, &on); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
 
-			// If we haven't determined the columns to group on, do that now.
-			if on == nil {
-				var err error
-				on, err = t.groupOn(tbl.Key(), m.Type())
-				if err != nil {
-					return err
-				}
+// appendRow appends the object returned by the map function
+// for row i to the table builder for its group key.
+func (t *mapTransformation) appendRow(key flux.GroupKey, fn *execute.RowMapPreparedFn, cr flux.ColReader, i int, m values.Object, on *map[string]bool) error {
+	// If we haven't determined the columns to group on, do that now.
+	if *on == nil {
+		var err error
+		*on, err = t.groupOn(key, m.Type())
+		if err != nil {
+			return err
+		}
+	}
+
+	builder, created := t.cache.TableBuilder(groupKeyForObject(i, cr, m, *on))
+	if created {
+		if err := t.createSchema(fn, builder, m); err != nil {
+			return err
+		}
+	}
+
+	for j, c := range builder.Cols() {
+		v, ok := m.Get(c.Label)
+		if !ok {
+			if idx := execute.ColIdx(c.Label, key.Cols()); t.mergeKey && idx >= 0 {
+				v = key.Value(idx)
+			} else {
+				// This should be unreachable
+				return errors.Newf(codes.Internal, "could not find value for column %q", c.Label)
 			}
+		}
+		if err := builder.AppendValue(j, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			key := groupKeyForObject(i, cr, m, on)
-			builder, created := t.cache.TableBuilder(key)
-			if created {
-				if err := t.createSchema(fn, builder, m); err != nil {
+// appendVector appends the vector returned by the vectorized map function.
+// When every row belongs to the same group key, the columns of the vector
+// are appended to the table builder as whole arrays.
+func (t *mapTransformation) appendVector(key flux.GroupKey, fn *execute.RowMapPreparedFn, cr flux.ColReader, vec *compiler.Vector, on *map[string]bool) error {
+	l := vec.Len()
+	if l == 0 {
+		return nil
+	}
+
+	m := vec.Value(0).Object()
+	if *on == nil {
+		var err error
+		*on, err = t.groupOn(key, m.Type())
+		if err != nil {
+			return err
+		}
+	}
+
+	columns := make(map[string]*compiler.Vector, len(vec.Labels))
+	for i, label := range vec.Labels {
+		columns[label] = vec.Columns[i]
+		if (*on)[label] && !isConstant(vec.Columns[i].Values) {
+			// The rows may belong to different tables.
+			for i := 0; i < l; i++ {
+				if err := t.appendRow(key, fn, cr, i, vec.Value(i).Object(), on); err != nil {
 					return err
 				}
 			}
+			return nil
+		}
+	}
 
-			for j, c := range builder.Cols() {
-				v, ok := m.Get(c.Label)
-				if !ok {
-					if idx := execute.ColIdx(c.Label, tbl.Key().Cols()); t.mergeKey && idx >= 0 {
-						v = tbl.Key().Value(idx)
-					} else {
-						// This should be unreachable
-						return errors.Newf(codes.Internal, "could not find value for column %q", c.Label)
-					}
-				}
-				if err := builder.AppendValue(j, v); err != nil {
+	builder, created := t.cache.TableBuilder(groupKeyForObject(0, cr, m, *on))
+	if created {
+		if err := t.createSchema(fn, builder, m); err != nil {
+			return err
+		}
+	}
+
+	for j, c := range builder.Cols() {
+		col, ok := columns[c.Label]
+		if !ok {
+			idx := execute.ColIdx(c.Label, key.Cols())
+			if !t.mergeKey || idx < 0 {
+				// This should be unreachable
+				return errors.Newf(codes.Internal, "could not find value for column %q", c.Label)
+			}
+			for i := 0; i < l; i++ {
+				if err := builder.AppendValue(j, key.Value(idx)); err != nil {
 					return err
 				}
 			}
+			continue
 		}
-		return nil
-	})
+
+		if ok, err := appendArray(builder, j, c.Type, col); err != nil {
+			return err
+		} else if ok {
+			continue
+		}
+		for i := 0; i < l; i++ {
+			if err := builder.AppendValue(j, col.Value(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// appendArray appends the values of the vector to column j
+// of the table builder if the vector matches the column type.
+func appendArray(b execute.TableBuilder, j int, typ flux.ColType, vec *compiler.Vector) (bool, error) {
+	if execute.ConvertFromKind(vec.Type.Nature()) != typ {
+		return false, nil
+	}
+	switch typ {
+	case flux.TBool:
+		return true, b.AppendBools(j, vec.Values.(*array.Boolean))
+	case flux.TInt:
+		return true, b.AppendInts(j, vec.Values.(*array.Int64))
+	case flux.TUInt:
+		return true, b.AppendUInts(j, vec.Values.(*array.Uint64))
+	case flux.TFloat:
+		return true, b.AppendFloats(j, vec.Values.(*array.Float64))
+	case flux.TString:
+		return true, b.AppendStrings(j, vec.Values.(*array.Binary))
+	case flux.TTime:
+		return true, b.AppendTimes(j, vec.Values.(*array.Int64))
+	default:
+		return false, nil
+	}
+}
+
+// isConstant reports whether every value in the array is the same.
+func isConstant(arr array.Interface) bool {
+	l := arr.Len()
+	if l == 0 {
+		return true
+	}
+	if n := arr.NullN(); n == l {
+		return true
+	} else if n > 0 {
+		return false
+	}
+	switch arr := arr.(type) {
+	case *array.Boolean:
+		for i := 1; i < l; i++ {
+			if arr.Value(i) != arr.Value(0) {
+				return false
+			}
+		}
+	case *array.Int64:
+		for i := 1; i < l; i++ {
+			if arr.Value(i) != arr.Value(0) {
+				return false
+			}
+		}
+	case *array.Uint64:
+		for i := 1; i < l; i++ {
+			if arr.Value(i) != arr.Value(0) {
+				return false
+			}
+		}
+	case *array.Float64:
+		for i := 1; i < l; i++ {
+			if arr.Value(i) != arr.Value(0) {
+				return false
+			}
+		}
+	case *array.Binary:
+		for i := 1; i < l; i++ {
+			if arr.ValueString(i) != arr.ValueString(0) {
+				return false
+			}
+		}
+	default:
+		return false
+	}
+	return true
 }
 
 func (t *mapTransformation) groupOn(key flux.GroupKey, m semantic.MonoType) (map[string]bool, error) {
@@ -3,14 +3,17 @@ package universe
 import (
 	"context"
 	"fmt"
+	"io"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/apache/arrow/go/arrow/array"
 	arrowmemory "github.com/apache/arrow/go/arrow/memory"
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/arrow"
 	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
 	"github.com/influxdata/flux/internal/execute/table"
@@ -172,6 +175,10 @@ func createPivotTransformation(id execute.DatasetID, mode execute.AccumulationMo
 	if t, d, err := newPivotTransformation2(a.Context(), *s, id, a.Allocator()); err == nil || flux.ErrorCode(err) != codes.Unimplemented {
 		return t, d, err
 	}
+	if fs, dir, ok := spillLocation(a.Context(), a.Allocator()); ok {
+		t, d := NewPivotSpillTransformation(a.Context(), s, id, a.Allocator(), fs, dir)
+		return t, d, nil
+	}
 
 	cache := execute.NewTableBuilderCache(a.Allocator())
 	d := execute.NewDataset(id, mode, cache)
@@ -211,69 +218,123 @@ func (t *pivotTransformation) RetractTable(id execute.DatasetID, key flux.GroupK
 	return t.d.RetractTable(key)
 }
 
-func (t *pivotTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
-	rowKeyIndex := make(map[string]int)
-	for _, v := range t.spec.RowKey {
+// pivotColumns locates the columns of a pivot within a table.
+type pivotColumns struct {
+	// rowKey and colKey hold the index of each column
+	// of the row key and the column key in the table.
+	rowKey []int
+	colKey []int
+
+	// value is the index of the value column.
+	value     int
+	valueType flux.ColType
+
+	// cols are the columns that are copied to the output table,
+	// which are the row key columns and the group key columns
+	// that are not part of the column key, and colMap holds
+	// their indices in the table.
+	cols   []flux.ColMeta
+	colMap []int
+
+	// key is the group key of the output table.
+	key flux.GroupKey
+}
+
+// pivotColumnsFor locates the columns of the pivot within the table.
+func (s *PivotProcedureSpec) pivotColumnsFor(tbl flux.Table) (*pivotColumns, error) {
+	pc := &pivotColumns{
+		rowKey: make([]int, len(s.RowKey)),
+		colKey: make([]int, len(s.ColumnKey)),
+		value:  -1,
+	}
+	for i, v := range s.RowKey {
 		idx := execute.ColIdx(v, tbl.Cols())
 		if idx < 0 {
-			return errors.Newf(codes.Invalid, "specified row key column does not exist in table: %v", v)
+			return nil, errors.Newf(codes.Invalid, "specified row key column does not exist in table: %v", v)
 		}
-		rowKeyIndex[v] = idx
+		pc.rowKey[i] = idx
 	}
 
 	// different from above because we'll get the column indices below when we
 	// determine the initial column schema
 	colKeyIndex := make(map[string]int)
-	valueColIndex := -1
-	var valueColType flux.ColType
-	for _, v := range t.spec.ColumnKey {
+	for _, v := range s.ColumnKey {
 		colKeyIndex[v] = -1
 	}
 
-	cols := make([]flux.ColMeta, 0, len(tbl.Cols()))
+	pc.cols = make([]flux.ColMeta, 0, len(tbl.Cols()))
+	pc.colMap = make([]int, 0, len(tbl.Cols()))
 	keyCols := make([]flux.ColMeta, 0, len(tbl.Key().Cols()))
 	keyValues := make([]values.Value, 0, len(tbl.Key().Cols()))
-	newIDX := 0
-	colMap := make([]int, len(tbl.Cols()))
-
 	for colIDX, v := range tbl.Cols() {
-		if _, ok := colKeyIndex[v.Label]; !ok && v.Label != t.spec.ValueColumn {
+		if _, ok := colKeyIndex[v.Label]; !ok && v.Label != s.ValueColumn {
 			// the columns we keep are: group key columns not in the column key and row key columns
 			if tbl.Key().HasCol(v.Label) {
-				colMap[newIDX] = colIDX
-				newIDX++
-				keyCols = append(keyCols, tbl.Cols()[colIDX])
-				cols = append(cols, tbl.Cols()[colIDX])
+				pc.colMap = append(pc.colMap, colIDX)
+				keyCols = append(keyCols, v)
+				pc.cols = append(pc.cols, v)
 				keyValues = append(keyValues, tbl.Key().LabelValue(v.Label))
-			} else if _, ok := rowKeyIndex[v.Label]; ok {
-				cols = append(cols, tbl.Cols()[colIDX])
-				colMap[newIDX] = colIDX
-				newIDX++
-			}
-		} else if v.Label == t.spec.ValueColumn {
-			valueColIndex = colIDX
-			valueColType = tbl.Cols()[colIDX].Type
+			} else if execute.ContainsStr(s.RowKey, v.Label) {
+				pc.cols = append(pc.cols, v)
+				pc.colMap = append(pc.colMap, colIDX)
+			}
+		} else if v.Label == s.ValueColumn {
+			pc.value = colIDX
+			pc.valueType = v.Type
 		} else {
 			// we need the location of the colKey columns in the original table
 			colKeyIndex[v.Label] = colIDX
 		}
 	}
 
-	if valueColIndex < 0 {
-		return errors.Newf(codes.Invalid, "specified value column does not exist in table: %v", t.spec.ValueColumn)
+	if pc.value < 0 {
+		return nil, errors.Newf(codes.Invalid, "specified value column does not exist in table: %v", s.ValueColumn)
 	}
 
-	for k, v := range colKeyIndex {
-		if v < 0 {
-			return errors.Newf(codes.Invalid, "specified column does not exist in table: %v", k)
+	for i, v := range s.ColumnKey {
+		idx := colKeyIndex[v]
+		if idx < 0 {
+			return nil, errors.Newf(codes.Invalid, "specified column does not exist in table: %v", v)
 		}
+		pc.colKey[i] = idx
+	}
+	pc.key = execute.NewGroupKey(keyCols, keyValues)
+	return pc, nil
+}
+
+// rowKeyFor returns the row key of a row as a string.
+func (pc *pivotColumns) rowKeyFor(cr flux.ColReader, row int) string {
+	rowKey := ""
+	for _, j := range pc.rowKey {
+		rowKey += valueToStr(cr, cr.Cols()[j], row, j)
 	}
+	return rowKey
+}
 
-	newGroupKey := execute.NewGroupKey(keyCols, keyValues)
+// colKeyFor returns the column key of a row, which
+// is the label of the column it is pivoted into.
+func (pc *pivotColumns) colKeyFor(cr flux.ColReader, row int) string {
+	colKey := ""
+	for _, j := range pc.colKey {
+		if colKey == "" {
+			colKey = valueToStr(cr, cr.Cols()[j], row, j)
+		} else {
+			colKey = colKey + "_" + valueToStr(cr, cr.Cols()[j], row, j)
+		}
+	}
+	return colKey
+}
+
+func (t *pivotTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	pc, err := t.spec.pivotColumnsFor(tbl)
+	if err != nil {
+		return err
+	}
+	newGroupKey := pc.key
 	builder, created := t.cache.TableBuilder(newGroupKey)
 	groupKeyString := newGroupKey.String()
 	if created {
-		for _, c := range cols {
+		for _, c := range pc.cols {
 			_, err := builder.AddCol(c)
 			if err != nil {
 				return err
@@ -282,28 +343,13 @@ func (t *pivotTransformation) Process(id execute.DatasetID, tbl flux.Table) erro
 		}
 		t.colKeyMaps[groupKeyString] = make(map[string]int)
 		t.rowKeyMaps[groupKeyString] = make(map[string]int)
-		t.nextRowCol[groupKeyString] = rowCol{nextCol: len(cols), nextRow: 0}
+		t.nextRowCol[groupKeyString] = rowCol{nextCol: len(pc.cols), nextRow: 0}
 	}
 
 	return tbl.Do(func(cr flux.ColReader) error {
 		for row := 0; row < cr.Len(); row++ {
-			rowKey := ""
-			colKey := ""
-			for _, rk := range t.spec.RowKey {
-				j := rowKeyIndex[rk]
-				c := cr.Cols()[j]
-				rowKey += valueToStr(cr, c, row, j)
-			}
-
-			for _, ck := range t.spec.ColumnKey {
-				j := colKeyIndex[ck]
-				c := cr.Cols()[j]
-				if colKey == "" {
-					colKey = valueToStr(cr, c, row, j)
-				} else {
-					colKey = colKey + "_" + valueToStr(cr, c, row, j)
-				}
-			}
+			rowKey := pc.rowKeyFor(cr, row)
+			colKey := pc.colKeyFor(cr, row)
 
 			// we have columns for the copy-over in place;
 			// we know the row key;
@@ -312,7 +358,7 @@ func (t *pivotTransformation) Process(id execute.DatasetID, tbl flux.Table) erro
 			if _, ok := t.colKeyMaps[groupKeyString][colKey]; !ok {
 				newCol := flux.ColMeta{
 					Label: colKey,
-					Type:  valueColType,
+					Type:  pc.valueType,
 				}
 				nextCol, err := builder.AddCol(newCol)
 				if err != nil {
@@ -324,8 +370,8 @@ func (t *pivotTransformation) Process(id execute.DatasetID, tbl flux.Table) erro
 			//  existing columns, as well as zero values for the pivoted columns.
 			if _, ok := t.rowKeyMaps[groupKeyString][rowKey]; !ok {
 				// rowkey U groupKey cols
-				for cidx := range cols {
-					if err := builder.AppendValue(cidx, execute.ValueForRow(cr, row, colMap[cidx])); err != nil {
+				for cidx := range pc.cols {
+					if err := builder.AppendValue(cidx, execute.ValueForRow(cr, row, pc.colMap[cidx])); err != nil {
 						return err
 					}
 				}
@@ -346,7 +392,7 @@ func (t *pivotTransformation) Process(id execute.DatasetID, tbl flux.Table) erro
 			// if we found a new row key, we added a new row with zeroes set for all the value columns
 			// so in all cases we know the row exists, and the column exists.  we need to grab the
 			// value from valueCol and assign it to its pivoted position.
-			if err := builder.SetValue(t.rowKeyMaps[groupKeyString][rowKey], t.colKeyMaps[groupKeyString][colKey], execute.ValueForRow(cr, row, valueColIndex)); err != nil {
+			if err := builder.SetValue(t.rowKeyMaps[groupKeyString][rowKey], t.colKeyMaps[groupKeyString][colKey], execute.ValueForRow(cr, row, pc.value)); err != nil {
 				return err
 			}
 
@@ -424,6 +470,338 @@ func (t *pivotTransformation) Finish(id execute.DatasetID, err error) {
 	t.d.Finish(err)
 }
 
+// The columns of the rows that are buffered by pivotSpillTransformation.
+// The columns that are copied to the output table follow them.
+const (
+	pivotSpillRowIdx = iota
+	pivotSpillColIdx
+	pivotSpillSeqIdx
+	pivotSpillValueIdx
+	pivotSpillCopyIdx
+)
+
+// pivotSpillSortCols are the columns that the buffered rows
+// of pivotSpillTransformation are sorted by.
+var pivotSpillSortCols = []string{"row", "col", "seq"}
+
+// pivotSpillTransformation is the general pivot for when buffered data
+// may be spilled to disk. It is used when pivotTransformation2 cannot be.
+//
+// Each row of the input is buffered along with the index of its row key,
+// which numbers the row keys in the order they were first seen, the index
+// of its column key and its position within the group negated. The rows are
+// sorted by these with the external merge sort of sortRunBuilder, so the rows
+// of each output row are read together and the last value of each cell comes
+// first. The output table is streamed one buffer at a time. Only the indices of
+// the row and column keys of each group are kept in memory throughout.
+type pivotSpillTransformation struct {
+	execute.ExecutionNode
+	d      *execute.PassthroughDataset
+	ctx    context.Context
+	mem    *memory.Allocator
+	spec   PivotProcedureSpec
+	groups *execute.GroupLookup
+
+	fs  filesystem.Service
+	dir string
+
+	watermark  execute.Time
+	processing execute.Time
+}
+
+// NewPivotSpillTransformation constructs a pivot transformation that
+// writes the buffered rows to dir within the filesystem service when
+// the allocator goes over its memory limit.
+func NewPivotSpillTransformation(ctx context.Context, spec *PivotProcedureSpec, id execute.DatasetID, mem *memory.Allocator, fs filesystem.Service, dir string) (execute.Transformation, execute.Dataset) {
+	t := &pivotSpillTransformation{
+		d:      execute.NewPassthroughDataset(id),
+		ctx:    ctx,
+		mem:    mem,
+		spec:   *spec,
+		groups: execute.NewGroupLookup(),
+		fs:     fs,
+		dir:    dir,
+	}
+	return t, t.d
+}
+
+// pivotSpillGroup holds the buffered rows of an output table.
+type pivotSpillGroup struct {
+	// cols are the columns copied from the input and
+	// pivotCols are the columns that values are pivoted into.
+	cols      []flux.ColMeta
+	pivotCols []flux.ColMeta
+	valueType flux.ColType
+
+	rowKeys map[string]int64
+	colKeys map[string]int64
+	n       int64
+	rows    *sortRunBuilder
+}
+
+func (t *pivotSpillTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *pivotSpillTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	pc, err := t.spec.pivotColumnsFor(tbl)
+	if err != nil {
+		return err
+	}
+
+	var gr *pivotSpillGroup
+	if v, ok := t.groups.Lookup(pc.key); ok {
+		gr = v.(*pivotSpillGroup)
+	} else {
+		if gr, err = t.newGroup(pc); err != nil {
+			return err
+		}
+		t.groups.Set(pc.key, gr)
+	}
+	if gr.valueType != pc.valueType {
+		return errors.Newf(codes.FailedPrecondition, "value columns in the same group have different types: %s and %s", gr.valueType, pc.valueType)
+	}
+
+	b := gr.rows
+	return tbl.Do(func(cr flux.ColReader) error {
+		for i := 0; i < cr.Len(); i++ {
+			colKey := pc.colKeyFor(cr, i)
+			col, ok := gr.colKeys[colKey]
+			if !ok {
+				col = int64(len(gr.pivotCols))
+				gr.colKeys[colKey] = col
+				gr.pivotCols = append(gr.pivotCols, flux.ColMeta{
+					Label: colKey,
+					Type:  pc.valueType,
+				})
+			}
+			rowKey := pc.rowKeyFor(cr, i)
+			row, ok := gr.rowKeys[rowKey]
+			if !ok {
+				row = int64(len(gr.rowKeys))
+				gr.rowKeys[rowKey] = row
+			}
+			gr.n++
+
+			if err := b.run.AppendInt(pivotSpillRowIdx, row); err != nil {
+				return err
+			}
+			if err := b.run.AppendInt(pivotSpillColIdx, col); err != nil {
+				return err
+			}
+			if err := b.run.AppendInt(pivotSpillSeqIdx, -gr.n); err != nil {
+				return err
+			}
+			if err := b.run.AppendValue(pivotSpillValueIdx, execute.ValueForRow(cr, i, pc.value)); err != nil {
+				return err
+			}
+			for j, idx := range pc.colMap {
+				if err := b.run.AppendValue(pivotSpillCopyIdx+j, execute.ValueForRow(cr, i, idx)); err != nil {
+					return err
+				}
+			}
+		}
+		return t.spill()
+	})
+}
+
+// newGroup creates the group for the output table of the input
+// tables with the pivot columns.
+func (t *pivotSpillTransformation) newGroup(pc *pivotColumns) (*pivotSpillGroup, error) {
+	cols := make([]flux.ColMeta, pivotSpillCopyIdx, pivotSpillCopyIdx+len(pc.cols))
+	cols[pivotSpillRowIdx] = flux.ColMeta{Label: "row", Type: flux.TInt}
+	cols[pivotSpillColIdx] = flux.ColMeta{Label: "col", Type: flux.TInt}
+	cols[pivotSpillSeqIdx] = flux.ColMeta{Label: "seq", Type: flux.TInt}
+	cols[pivotSpillValueIdx] = flux.ColMeta{Label: "value", Type: pc.valueType}
+	for j, c := range pc.cols {
+		cols = append(cols, flux.ColMeta{
+			Label: fmt.Sprintf("copy%d", j),
+			Type:  c.Type,
+		})
+	}
+
+	run := execute.NewColListTableBuilder(pc.key, t.mem)
+	for _, c := range cols {
+		if _, err := run.AddCol(c); err != nil {
+			run.Release()
+			return nil, err
+		}
+	}
+	return &pivotSpillGroup{
+		cols:      pc.cols,
+		valueType: pc.valueType,
+		rowKeys:   make(map[string]int64),
+		colKeys:   make(map[string]int64),
+		rows: &sortRunBuilder{
+			run:  run,
+			fs:   t.fs,
+			dir:  t.dir,
+			cols: pivotSpillSortCols,
+			mem:  t.mem,
+		},
+	}, nil
+}
+
+// spill writes the buffered rows to disk if the
+// allocator has gone over its memory limit.
+func (t *pivotSpillTransformation) spill() error {
+	if !t.mem.ShouldSpill() {
+		return nil
+	}
+
+	var err error
+	t.groups.Range(func(key flux.GroupKey, value interface{}) {
+		if err != nil {
+			return
+		}
+		err = value.(*pivotSpillGroup).rows.Spill()
+	})
+	if err != nil {
+		return err
+	}
+	t.mem.ReturnMemory()
+	return nil
+}
+
+func (t *pivotSpillTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	t.watermark = mark
+	return nil
+}
+
+func (t *pivotSpillTransformation) UpdateProcessingTime(id execute.DatasetID, mark execute.Time) error {
+	t.processing = mark
+	return nil
+}
+
+func (t *pivotSpillTransformation) Finish(id execute.DatasetID, err error) {
+	// Wrap this in a function so that we do not capture the err variable.
+	defer func() { t.d.Finish(err) }()
+
+	t.groups.Range(func(key flux.GroupKey, value interface{}) {
+		gr := value.(*pivotSpillGroup)
+		if err != nil {
+			gr.rows.Release()
+			return
+		}
+
+		var tbl flux.Table
+		if tbl, err = gr.doPivot(t.ctx, key, t.mem); err != nil {
+			gr.rows.Release()
+			return
+		}
+		err = t.d.Process(tbl)
+	})
+	t.groups.Clear()
+
+	if err = t.d.UpdateWatermark(t.watermark); err != nil {
+		return
+	}
+	if err = t.d.UpdateProcessingTime(t.processing); err != nil {
+		return
+	}
+}
+
+// doPivot streams the output table from the sorted rows of the group.
+func (gr *pivotSpillGroup) doPivot(ctx context.Context, key flux.GroupKey, mem *memory.Allocator) (flux.Table, error) {
+	sorted, err := gr.rows.Table()
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]flux.ColMeta, 0, len(gr.cols)+len(gr.pivotCols))
+	cols = append(cols, gr.cols...)
+	cols = append(cols, gr.pivotCols...)
+	return table.StreamWithContext(ctx, key, cols, func(ctx context.Context, w *table.StreamWriter) error {
+		defer sorted.Done()
+
+		builders := make([]array.Builder, len(cols))
+		defer func() {
+			for _, b := range builders {
+				if b != nil {
+					b.Release()
+				}
+			}
+		}()
+		newBuffer := func() {
+			for j, c := range cols {
+				builders[j] = arrow.NewBuilder(c.Type, mem)
+				builders[j].Reserve(sortMergeBufferSize)
+			}
+		}
+		flush := func() error {
+			vs := make([]array.Interface, len(cols))
+			for j, b := range builders {
+				vs[j] = b.NewArray()
+				b.Release()
+				builders[j] = nil
+			}
+			return w.Write(vs)
+		}
+
+		// row and col are the indices of the current cell.
+		// The pivoted columns of the output row are appended
+		// in order and the ones without a value are null.
+		var (
+			row, col = int64(-1), int64(-1)
+			ncopied  = len(gr.cols)
+			npivoted = int64(len(gr.pivotCols))
+			n        int
+		)
+		endRow := func() error {
+			for ; col+1 < npivoted; col++ {
+				builders[ncopied+int(col)+1].AppendNull()
+			}
+			if n++; n == sortMergeBufferSize {
+				if err := flush(); err != nil {
+					return err
+				}
+				newBuffer()
+				n = 0
+			}
+			return nil
+		}
+
+		newBuffer()
+		if err := sorted.Do(func(cr flux.ColReader) error {
+			rows, cs := cr.Ints(pivotSpillRowIdx), cr.Ints(pivotSpillColIdx)
+			vs := table.Values(cr, pivotSpillValueIdx)
+			for i := 0; i < cr.Len(); i++ {
+				if r := rows.Value(i); r != row {
+					if row >= 0 {
+						if err := endRow(); err != nil {
+							return err
+						}
+					}
+					row, col = r, -1
+					for j := 0; j < ncopied; j++ {
+						appendSortValue(builders[j], table.Values(cr, pivotSpillCopyIdx+j), i)
+					}
+				} else if cs.Value(i) == col {
+					// The last value of the cell was already appended.
+					continue
+				}
+				for ; col+1 < cs.Value(i); col++ {
+					builders[ncopied+int(col)+1].AppendNull()
+				}
+				col = cs.Value(i)
+				appendSortValue(builders[ncopied+int(col)], vs, i)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		if row >= 0 {
+			if err := endRow(); err != nil {
+				return err
+			}
+		}
+		if n > 0 {
+			return flush()
+		}
+		return nil
+	})
+}
+
 // pivotTransformation2 is an optimized version of pivot.
 // It can only be used when there is a single row and column key
 // and it can only be used if the row key is sorted without
@@ -436,6 +814,12 @@ type pivotTransformation2 struct {
 	spec   PivotProcedureSpec
 	groups *execute.GroupLookup
 
+	// fs and dir are where the buffered arrays are spilled
+	// when the allocator goes over its memory limit.
+	// Nothing is spilled if fs is nil.
+	fs  filesystem.Service
+	dir string
+
 	watermark  execute.Time
 	processing execute.Time
 }
@@ -458,6 +842,7 @@ func newPivotTransformation2(ctx context.Context, spec PivotProcedureSpec, id ex
 		spec:   spec,
 		groups: execute.NewGroupLookup(),
 	}
+	t.fs, t.dir, _ = spillLocation(ctx, alloc)
 	return t, t.d, nil
 }
 
@@ -499,7 +884,7 @@ func (t *pivotTransformation2) Process(id execute.DatasetID, tbl flux.Table) err
 
 	// Read the table and insert each of the column readers
 	// into the table group.
-	return tbl.Do(func(cr flux.ColReader) error {
+	if err := tbl.Do(func(cr flux.ColReader) error {
 		colKey := t.spec.ColumnKey[0]
 		key := cr.Key().LabelValue(colKey)
 		if key == nil {
@@ -532,7 +917,38 @@ func (t *pivotTransformation2) Process(id execute.DatasetID, tbl flux.Table) err
 		k, v := t.getColumn(cr, rowIndex), t.getColumn(cr, valueIndex)
 		buf.Insert(k, v)
 		return nil
+	}); err != nil {
+		return err
+	}
+	return t.spill()
+}
+
+// spill writes the buffered arrays of every group to disk
+// if the allocator has gone over its memory limit.
+// The arrays are read back in order when the group is pivoted
+// and merged one buffer at a time.
+func (t *pivotTransformation2) spill() error {
+	if t.fs == nil || !t.alloc.ShouldSpill() {
+		return nil
+	}
+
+	var err error
+	t.groups.Range(func(key flux.GroupKey, value interface{}) {
+		if err != nil {
+			return
+		}
+		gr := value.(*pivotTableGroup)
+		for _, buf := range gr.buffers {
+			if err = buf.Spill(t.fs, t.dir, gr.rowCol.Type, t.alloc); err != nil {
+				return
+			}
+		}
 	})
+	if err != nil {
+		return err
+	}
+	t.alloc.ReturnMemory()
+	return nil
 }
 
 func (t *pivotTransformation2) validateTable(tbl flux.Table) error {
@@ -634,7 +1050,11 @@ func (t *pivotTransformation2) Finish(id execute.DatasetID, err error) {
 
 		var tbl flux.Table
 		gr := value.(*pivotTableGroup)
-		tbl, err = gr.doPivot(key, t.alloc)
+		if gr.hasSpilled() {
+			tbl, err = gr.doPivotSpilled(t.ctx, key, t.alloc)
+		} else {
+			tbl, err = gr.doPivot(key, t.alloc)
+		}
 		if err != nil {
 			return
 		}
@@ -656,6 +1076,11 @@ type pivotTableBuffer struct {
 	keys      []array.Interface
 	valueType flux.ColType
 	values    []array.Interface
+
+	// spilled holds the keys and values that were
+	// written to disk. They precede the keys and values
+	// that are still in memory.
+	spilled []*table.SpillFile
 }
 
 func (b *pivotTableBuffer) Insert(k, v array.Interface) {
@@ -665,6 +1090,36 @@ func (b *pivotTableBuffer) Insert(k, v array.Interface) {
 	b.values = append(b.values, v)
 }
 
+// Spill writes the keys and values in memory to a file within dir.
+func (b *pivotTableBuffer) Spill(fs filesystem.Service, dir string, keyType flux.ColType, mem arrowmemory.Allocator) error {
+	if len(b.keys) == 0 {
+		return nil
+	}
+
+	cols := []flux.ColMeta{
+		{Label: "key", Type: keyType},
+		{Label: "value", Type: b.valueType},
+	}
+	buffers := make([]flux.ColReader, len(b.keys))
+	for i := range b.keys {
+		buffers[i] = &arrow.TableBuffer{
+			Columns: cols,
+			Values:  []array.Interface{b.keys[i], b.values[i]},
+		}
+	}
+	f, err := table.WriteSpillFile(fs, dir, cols, buffers, mem)
+	if err != nil {
+		return err
+	}
+	b.spilled = append(b.spilled, f)
+
+	for _, cr := range buffers {
+		cr.Release()
+	}
+	b.keys, b.values = nil, nil
+	return nil
+}
+
 func (b *pivotTableBuffer) Release() {
 	for _, k := range b.keys {
 		k.Release()
@@ -672,6 +1127,9 @@ func (b *pivotTableBuffer) Release() {
 	for _, v := range b.values {
 		v.Release()
 	}
+	for _, f := range b.spilled {
+		_ = f.Remove()
+	}
 }
 
 type pivotTableGroup struct {
@@ -723,3 +1181,315 @@ func (gr *pivotTableGroup) doPivot(key flux.GroupKey, mem arrowmemory.Allocator)
 	}
 	return table.FromBuffer(tb), nil
 }
+
+// hasSpilled reports whether any buffer of the group was spilled to disk.
+func (gr *pivotTableGroup) hasSpilled() bool {
+	for _, buf := range gr.buffers {
+		if len(buf.spilled) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// doPivotSpilled pivots a group that has buffers on disk.
+// The buffers are read back in order and merged in windows
+// of row keys so only the arrays within the current window
+// need to be in memory. Each window is a buffer of the output table.
+func (gr *pivotTableGroup) doPivotSpilled(ctx context.Context, key flux.GroupKey, mem arrowmemory.Allocator) (flux.Table, error) {
+	labels := make([]string, 0, len(gr.buffers))
+	for label := range gr.buffers {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	cols := make([]flux.ColMeta, 0, len(key.Cols())+len(labels)+1)
+	cols = append(cols, gr.rowCol)
+	cols = append(cols, key.Cols()...)
+	cursors := make([]*pivotTableCursor, len(labels))
+	for i, label := range labels {
+		buf := gr.buffers[label]
+		cols = append(cols, flux.ColMeta{
+			Label: label,
+			Type:  buf.valueType,
+		})
+		cursors[i] = &pivotTableCursor{label: label, buf: buf, mem: mem}
+	}
+
+	return table.StreamWithContext(ctx, key, cols, func(ctx context.Context, w *table.StreamWriter) error {
+		defer func() {
+			for _, c := range cursors {
+				c.Release()
+			}
+		}()
+
+		for {
+			window, ok, err := gr.nextWindow(cursors)
+			if err != nil {
+				return err
+			} else if !ok {
+				return nil
+			}
+
+			keys := window.mergeKeys(mem)
+			vs := make([]array.Interface, 0, len(cols))
+			vs = append(vs, keys)
+			for j := range key.Cols() {
+				vs = append(vs, arrow.Repeat(key.Value(j), keys.Len(), mem))
+			}
+			for _, label := range labels {
+				buf := window.buffers[label]
+				vs = append(vs, window.buildColumn(keys, buf, mem))
+				buf.Release()
+			}
+			if err := w.Write(vs); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// nextWindow removes the keys and values before the smallest key that
+// any cursor may still read and returns them as a group with the same
+// buffers. No cursor will read a key from the window again so the window
+// can be pivoted on its own. It returns false when the cursors are done.
+func (gr *pivotTableGroup) nextWindow(cursors []*pivotTableCursor) (*pivotTableGroup, bool, error) {
+	type position struct {
+		n, row int
+	}
+	positions := make([]position, len(cursors))
+	for {
+		for _, c := range cursors {
+			if len(c.keys) == 0 {
+				if err := c.load(); err != nil {
+					return nil, false, err
+				}
+			}
+		}
+
+		// The bound is the smallest of the last keys of the
+		// cursors that are not done. If all of the cursors
+		// are done, everything that was loaded is in the window.
+		var bound *pivotTableCursor
+		for _, c := range cursors {
+			if c.done {
+				continue
+			}
+			if bound == nil || c.compareLast(bound) < 0 {
+				bound = c
+			}
+		}
+
+		var n int
+		for i, c := range cursors {
+			if bound == nil {
+				positions[i] = position{n: len(c.keys)}
+			} else {
+				bk, bi := bound.lastKey()
+				positions[i].n, positions[i].row = c.search(bk, bi)
+			}
+			for _, k := range c.keys[:positions[i].n] {
+				n += k.Len()
+			}
+			n += positions[i].row
+		}
+
+		if n > 0 {
+			window := &pivotTableGroup{
+				rowCol:  gr.rowCol,
+				buffers: make(map[string]*pivotTableBuffer, len(cursors)),
+			}
+			for i, c := range cursors {
+				keys, values := c.take(positions[i].n, positions[i].row)
+				window.buffers[c.label] = &pivotTableBuffer{
+					keys:      keys,
+					valueType: c.buf.valueType,
+					values:    values,
+				}
+			}
+			return window, true, nil
+		} else if bound == nil {
+			return nil, false, nil
+		}
+
+		// Every key that was loaded is the same as the bound.
+		// Load more for the cursors that end with the bound
+		// until one of them reads a larger key or is done.
+		var ends []*pivotTableCursor
+		for _, c := range cursors {
+			if !c.done && c.compareLast(bound) == 0 {
+				ends = append(ends, c)
+			}
+		}
+		for _, c := range ends {
+			if err := c.load(); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+}
+
+// pivotTableCursor reads the keys and values of a pivotTableBuffer
+// in order, first from its spilled files and then from memory.
+type pivotTableCursor struct {
+	label string
+	buf   *pivotTableBuffer
+	mem   arrowmemory.Allocator
+	r     *table.SpillReader
+
+	// keys and values have been loaded
+	// but are not part of a window yet.
+	keys   []array.Interface
+	values []array.Interface
+	done   bool
+}
+
+// load reads the next keys and values of the buffer.
+// The cursor is done when nothing is left to read.
+func (c *pivotTableCursor) load() error {
+	for !c.done {
+		var k, v array.Interface
+		if len(c.buf.spilled) > 0 {
+			if c.r == nil {
+				r, err := c.buf.spilled[0].Open(nil, c.mem)
+				if err != nil {
+					return err
+				}
+				c.r = r
+			}
+			tb, err := c.r.Read()
+			if err == io.EOF {
+				// The file has been read so it is no longer needed.
+				_ = c.r.Close()
+				c.r = nil
+				_ = c.buf.spilled[0].Remove()
+				c.buf.spilled = c.buf.spilled[1:]
+				continue
+			} else if err != nil {
+				return err
+			}
+			k, v = tb.Values[0], tb.Values[1]
+		} else if len(c.buf.keys) > 0 {
+			k, v = c.buf.keys[0], c.buf.values[0]
+			c.buf.keys, c.buf.values = c.buf.keys[1:], c.buf.values[1:]
+		} else {
+			c.done = true
+			return nil
+		}
+
+		if k.Len() == 0 {
+			k.Release()
+			v.Release()
+			continue
+		}
+		c.keys = append(c.keys, k)
+		c.values = append(c.values, v)
+		return nil
+	}
+	return nil
+}
+
+// lastKey returns the array and index of the last key that was loaded.
+func (c *pivotTableCursor) lastKey() (array.Interface, int) {
+	k := c.keys[len(c.keys)-1]
+	return k, k.Len() - 1
+}
+
+// compareLast compares the last keys that were loaded by the cursors.
+func (c *pivotTableCursor) compareLast(o *pivotTableCursor) int {
+	a, i := c.lastKey()
+	b, j := o.lastKey()
+	return compareKeys(a, i, b, j)
+}
+
+// search returns the array and row of the first loaded key
+// that is not before the key at i within arr.
+func (c *pivotTableCursor) search(arr array.Interface, i int) (int, int) {
+	for n, k := range c.keys {
+		row := sort.Search(k.Len(), func(j int) bool {
+			return compareKeys(k, j, arr, i) >= 0
+		})
+		if row < k.Len() {
+			return n, row
+		}
+	}
+	return len(c.keys), 0
+}
+
+// take removes the loaded keys and values before
+// the array and row and returns them.
+func (c *pivotTableCursor) take(n, row int) (keys, values []array.Interface) {
+	keys, values = c.keys[:n:n], c.values[:n:n]
+	c.keys, c.values = c.keys[n:], c.values[n:]
+	if row > 0 {
+		k, v := c.keys[0], c.values[0]
+		keys = append(keys, array.NewSlice(k, 0, int64(row)))
+		values = append(values, array.NewSlice(v, 0, int64(row)))
+		c.keys[0] = array.NewSlice(k, int64(row), int64(k.Len()))
+		c.values[0] = array.NewSlice(v, int64(row), int64(v.Len()))
+		k.Release()
+		v.Release()
+	}
+	return keys, values
+}
+
+func (c *pivotTableCursor) Release() {
+	for _, k := range c.keys {
+		k.Release()
+	}
+	for _, v := range c.values {
+		v.Release()
+	}
+	c.keys, c.values = nil, nil
+	if c.r != nil {
+		_ = c.r.Close()
+		c.r = nil
+	}
+	c.buf.Release()
+}
+
+// compareKeys compares the row key at i within a to the row key
+// at j within b. Null keys are ordered after all other keys.
+func compareKeys(a array.Interface, i int, b array.Interface, j int) int {
+	if an, bn := a.IsNull(i), b.IsNull(j); an || bn {
+		switch {
+		case an && bn:
+			return 0
+		case an:
+			return 1
+		default:
+			return -1
+		}
+	}
+
+	switch a := a.(type) {
+	case *array.Int64:
+		x, y := a.Value(i), b.(*array.Int64).Value(j)
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+		return 0
+	case *array.Uint64:
+		x, y := a.Value(i), b.(*array.Uint64).Value(j)
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+		return 0
+	case *array.Float64:
+		x, y := a.Value(i), b.(*array.Float64).Value(j)
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+		return 0
+	case *array.Binary:
+		return strings.Compare(a.ValueString(i), b.(*array.Binary).ValueString(j))
+	default:
+		panic(errors.Newf(codes.Unimplemented, "row column merge not implemented for %T", a))
+	}
+}
@@ -2,11 +2,14 @@ package universe_test
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
 	"github.com/influxdata/flux/internal/errors"
@@ -783,6 +786,41 @@ func TestPivot_Process(t *testing.T) {
 				},
 			)
 		})
+		t.Run(tc.name+" with spilling", func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "flux-pivot-spill")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			// The tables were read by the previous test so read copies of them.
+			data := make([]flux.Table, len(tc.data))
+			for i, tbl := range tc.data {
+				cp := *tbl.(*executetest.Table)
+				cp.IsDone = false
+				data[i] = &cp
+			}
+			executetest.ProcessTestHelper2(
+				t,
+				data,
+				tc.want,
+				tc.wantErr,
+				func(id execute.DatasetID, alloc *memory.Allocator) (execute.Transformation, execute.Dataset) {
+					// Use a limit that is always exceeded so
+					// every buffer is spilled to disk.
+					limit := int64(1)
+					alloc.Limit = &limit
+					alloc.Manager = memory.NewSpillManager(1<<20, dir)
+					return universe.NewPivotSpillTransformation(context.Background(), tc.spec, id, alloc, filesystem.SystemFS, dir)
+				},
+			)
+
+			if files, err := ioutil.ReadDir(dir); err != nil {
+				t.Fatal(err)
+			} else if len(files) != 0 {
+				t.Errorf("expected spill files to be removed, found %d", len(files))
+			}
+		})
 	}
 }
 
@@ -1370,6 +1408,100 @@ func TestPivot2_Process(t *testing.T) {
 	}
 }
 
+func TestPivot2_Process_Spill(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flux-pivot-spill")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	spec := universe.PivotProcedureSpec{
+		RowKey:      []string{"_time"},
+		ColumnKey:   []string{"_field"},
+		ValueColumn: "_value",
+		IsKeyColumnFunc: func(label string) bool {
+			return true
+		},
+		IsSortedByFunc: func(cols []string, desc bool) bool {
+			return true
+		},
+	}
+	data := []flux.Table{
+		&executetest.RowWiseTable{Table: &executetest.Table{
+			KeyCols: []string{"_measurement", "_field"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), "m0", "f0", 1.0},
+				{execute.Time(2), "m0", "f0", 2.0},
+				{execute.Time(3), "m0", "f0", 3.0},
+				{execute.Time(5), "m0", "f0", 5.0},
+			},
+		}},
+		&executetest.RowWiseTable{Table: &executetest.Table{
+			KeyCols: []string{"_measurement", "_field"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "_field", Type: flux.TString},
+				{Label: "_value", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(2), "m0", "f1", 20.0},
+				{execute.Time(3), "m0", "f1", 30.0},
+				{execute.Time(4), "m0", "f1", 40.0},
+			},
+		}},
+	}
+	want := []*executetest.Table{
+		{
+			KeyCols: []string{"_measurement"},
+			ColMeta: []flux.ColMeta{
+				{Label: "_time", Type: flux.TTime},
+				{Label: "_measurement", Type: flux.TString},
+				{Label: "f0", Type: flux.TFloat},
+				{Label: "f1", Type: flux.TFloat},
+			},
+			Data: [][]interface{}{
+				{execute.Time(1), "m0", 1.0, nil},
+				{execute.Time(2), "m0", 2.0, 20.0},
+				{execute.Time(3), "m0", 3.0, 30.0},
+				{execute.Time(4), "m0", nil, 40.0},
+				{execute.Time(5), "m0", 5.0, nil},
+			},
+		},
+	}
+	ctx := executetest.NewTestExecuteDependencies().Inject(context.Background())
+	executetest.ProcessTestHelper2(
+		t,
+		data,
+		want,
+		nil,
+		func(id execute.DatasetID, alloc *memory.Allocator) (execute.Transformation, execute.Dataset) {
+			// Use a limit that is always exceeded so
+			// every buffer is spilled to disk.
+			limit := int64(1)
+			alloc.Limit = &limit
+			alloc.Manager = memory.NewSpillManager(1<<20, dir)
+			tr, d, err := universe.NewPivotTransformation2(ctx, spec, id, alloc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return tr, d
+		},
+	)
+
+	if files, err := ioutil.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(files) != 0 {
+		t.Errorf("expected spill files to be removed, found %d", len(files))
+	}
+}
+
 func TestPivot2_Process_VariousSchemas(t *testing.T) {
 	spec := universe.PivotProcedureSpec{
 		RowKey:      []string{"_time"},
@@ -67,7 +67,6 @@ func (s *RangeOpSpec) Kind() flux.OperationKind {
 }
 
 type RangeProcedureSpec struct {
-	plan.DefaultCost
 	Bounds      flux.Bounds
 	TimeColumn  string
 	StartColumn string
@@ -131,6 +130,12 @@ func (s *RangeProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// Cost assumes that range keeps a fixed fraction of its input.
+func (s *RangeProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	in := plan.CombineStatistics(inStats...)
+	return plan.Cost{CPU: in.Cardinality}, in.Scale(plan.DefaultRangeSelectivity)
+}
+
 // TriggerSpec implements plan.TriggerAwareProcedureSpec
 func (s *RangeProcedureSpec) TriggerSpec() plan.TriggerSpec {
 	return plan.NarrowTransformationTriggerSpec{}
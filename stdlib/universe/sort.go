@@ -1,11 +1,23 @@
 package universe
 
 import (
+	"container/heap"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/apache/arrow/go/arrow/array"
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/arrow"
 	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/dependencies/filesystem"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/internal/execute/table"
 	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/plan"
 	"github.com/influxdata/flux/runtime"
 	"github.com/influxdata/flux/semantic"
@@ -105,12 +117,40 @@ func createSortTransformation(id execute.DatasetID, mode execute.AccumulationMod
 	if !ok {
 		return nil, nil, errors.Newf(codes.Internal, "invalid spec type %T", spec)
 	}
+	if fs, dir, ok := spillLocation(a.Context(), a.Allocator()); ok {
+		t, d := NewSortSpillTransformation(id, s, a.Allocator(), fs, dir)
+		return t, d, nil
+	}
 	cache := execute.NewTableBuilderCache(a.Allocator())
 	d := execute.NewDataset(id, mode, cache)
 	t := NewSortTransformation(d, cache, s)
 	return t, d, nil
 }
 
+// spillLocation returns the filesystem service of the query and the
+// directory where buffered data is spilled when the allocator goes over
+// its memory limit. The directory for temporary files of the service is
+// used when the allocator does not name one. It reports false when
+// spilling is not enabled for the allocator or the query does not have
+// a filesystem service with such a directory, which keeps spilled data
+// within the files that the query may access.
+func spillLocation(ctx context.Context, mem *memory.Allocator) (filesystem.Service, string, bool) {
+	dir, ok := mem.SpillDir()
+	if !ok {
+		return nil, "", false
+	}
+	fs, err := flux.GetDependencies(ctx).FilesystemService()
+	if err != nil {
+		return nil, "", false
+	}
+	if dir == "" {
+		if dir, ok = filesystem.TempDir(fs); !ok {
+			return nil, "", false
+		}
+	}
+	return fs, dir, true
+}
+
 type sortTransformation struct {
 	execute.ExecutionNode
 	d     execute.Dataset
@@ -134,14 +174,7 @@ func (t *sortTransformation) RetractTable(id execute.DatasetID, key flux.GroupKe
 }
 
 func (t *sortTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
-	key := tbl.Key()
-	for _, label := range t.cols {
-		if key.HasCol(label) {
-			key = t.sortedKey(key)
-			break
-		}
-	}
-
+	key := sortedKey(tbl.Key(), t.cols)
 	builder, created := t.cache.TableBuilder(key)
 	if !created {
 		return errors.Newf(codes.FailedPrecondition, "sort found duplicate table with key: %v", tbl.Key())
@@ -167,11 +200,24 @@ func (t *sortTransformation) Finish(id execute.DatasetID, err error) {
 	t.d.Finish(err)
 }
 
-func (t *sortTransformation) sortedKey(key flux.GroupKey) flux.GroupKey {
+// sortedKey reorders the columns of the group key so the
+// columns that are being sorted come first.
+func sortedKey(key flux.GroupKey, sortCols []string) flux.GroupKey {
+	found := false
+	for _, label := range sortCols {
+		if key.HasCol(label) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return key
+	}
+
 	cols := make([]flux.ColMeta, len(key.Cols()))
 	vs := make([]values.Value, len(key.Cols()))
 	j := 0
-	for _, label := range t.cols {
+	for _, label := range sortCols {
 		idx := execute.ColIdx(label, key.Cols())
 		if idx >= 0 {
 			cols[j] = key.Cols()[idx]
@@ -180,7 +226,7 @@ func (t *sortTransformation) sortedKey(key flux.GroupKey) flux.GroupKey {
 		}
 	}
 	for idx, c := range key.Cols() {
-		if !execute.ContainsStr(t.cols, c.Label) {
+		if !execute.ContainsStr(sortCols, c.Label) {
 			cols[j] = c
 			vs[j] = key.Value(idx)
 			j++
@@ -188,3 +234,457 @@ func (t *sortTransformation) sortedKey(key flux.GroupKey) flux.GroupKey {
 	}
 	return execute.NewGroupKey(cols, vs)
 }
+
+// sortSpillTransformation sorts each table with an external merge sort.
+// The rows of a table are accumulated into a run that is sorted
+// and written to disk whenever the allocator goes over its memory limit.
+// When the table is finished, the sorted runs are merged together
+// so only a single buffer from each run needs to be in memory.
+type sortSpillTransformation struct {
+	execute.ExecutionNode
+	d     execute.Dataset
+	cache table.BuilderCache
+	mem   *memory.Allocator
+
+	cols []string
+	desc bool
+}
+
+// NewSortSpillTransformation constructs a sort transformation that writes
+// sorted runs to dir within the filesystem service when the allocator
+// goes over its memory limit.
+func NewSortSpillTransformation(id execute.DatasetID, spec *SortProcedureSpec, mem *memory.Allocator, fs filesystem.Service, dir string) (execute.Transformation, execute.Dataset) {
+	t := &sortSpillTransformation{
+		mem:  mem,
+		cols: spec.Columns,
+		desc: spec.Desc,
+	}
+	t.cache = table.BuilderCache{
+		New: func(key flux.GroupKey) table.Builder {
+			return &sortRunBuilder{
+				run:  execute.NewColListTableBuilder(key, mem),
+				fs:   fs,
+				dir:  dir,
+				cols: spec.Columns,
+				desc: spec.Desc,
+				mem:  mem,
+			}
+		},
+	}
+	t.d = table.NewDataset(id, &t.cache)
+	return t, t.d
+}
+
+func (t *sortSpillTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *sortSpillTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	key := sortedKey(tbl.Key(), t.cols)
+	var b *sortRunBuilder
+	if created := t.cache.Get(key, &b); !created {
+		return errors.Newf(codes.FailedPrecondition, "sort found duplicate table with key: %v", tbl.Key())
+	}
+	if err := execute.AddTableCols(tbl, b.run); err != nil {
+		return err
+	}
+	return tbl.Do(func(cr flux.ColReader) error {
+		if err := execute.AppendCols(cr, b.run); err != nil {
+			return err
+		}
+		return t.spill()
+	})
+}
+
+// spill writes the buffered runs to disk if the
+// allocator has gone over its memory limit.
+func (t *sortSpillTransformation) spill() error {
+	if !t.mem.ShouldSpill() {
+		return nil
+	}
+	if err := t.cache.ForEach(func(key flux.GroupKey, builder table.Builder) error {
+		return builder.(*sortRunBuilder).Spill()
+	}); err != nil {
+		return err
+	}
+	t.mem.ReturnMemory()
+	return nil
+}
+
+func (t *sortSpillTransformation) UpdateWatermark(id execute.DatasetID, mark execute.Time) error {
+	return t.d.UpdateWatermark(mark)
+}
+
+func (t *sortSpillTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+
+func (t *sortSpillTransformation) Finish(id execute.DatasetID, err error) {
+	t.d.Finish(err)
+}
+
+// sortRunBuilder buffers the rows of a table in memory
+// and keeps track of the sorted runs that were spilled to disk.
+type sortRunBuilder struct {
+	run  *execute.ColListTableBuilder
+	runs []*table.SpillFile
+	fs   filesystem.Service
+	dir  string
+	cols []string
+	desc bool
+	mem  *memory.Allocator
+}
+
+// Spill sorts the rows in memory and writes them to disk as a new run.
+func (b *sortRunBuilder) Spill() error {
+	if b.run.NRows() == 0 {
+		return nil
+	}
+
+	b.run.Sort(b.cols, b.desc)
+	tbl, err := b.run.Table()
+	if err != nil {
+		return err
+	}
+	var f *table.SpillFile
+	if err := tbl.Do(func(cr flux.ColReader) error {
+		f, err = table.WriteSpillFile(b.fs, b.dir, cr.Cols(), []flux.ColReader{cr}, b.mem)
+		return err
+	}); err != nil {
+		return err
+	}
+	b.runs = append(b.runs, f)
+
+	// Replace the run with an empty one so the
+	// memory used by the previous one is freed.
+	run := execute.NewColListTableBuilder(b.run.Key(), b.mem)
+	for _, c := range b.run.Cols() {
+		if _, err := run.AddCol(c); err != nil {
+			return err
+		}
+	}
+	b.run.Release()
+	b.run = run
+	return nil
+}
+
+func (b *sortRunBuilder) Table() (flux.Table, error) {
+	if len(b.runs) == 0 {
+		b.run.Sort(b.cols, b.desc)
+		tbl, err := b.run.Table()
+		b.run.Release()
+		return tbl, err
+	}
+
+	if err := b.Spill(); err != nil {
+		return nil, err
+	}
+	b.run.Release()
+
+	sortCols := make([]int, 0, len(b.cols))
+	for _, label := range b.cols {
+		if j := execute.ColIdx(label, b.run.Cols()); j >= 0 {
+			sortCols = append(sortCols, j)
+		}
+	}
+	runs := b.runs
+	b.runs = nil
+	return &sortMergeTable{
+		key:      b.run.Key(),
+		cols:     b.run.Cols(),
+		runs:     runs,
+		sortCols: sortCols,
+		desc:     b.desc,
+		mem:      b.mem,
+	}, nil
+}
+
+func (b *sortRunBuilder) Release() {
+	b.run.Release()
+	for _, f := range b.runs {
+		_ = f.Remove()
+	}
+	b.runs = nil
+}
+
+// sortMergeBufferSize is the number of rows in each
+// buffer produced by a sortMergeTable.
+const sortMergeBufferSize = 1024
+
+// sortMergeTable merges the sorted runs of a table into a single sorted table.
+type sortMergeTable struct {
+	used     int32
+	key      flux.GroupKey
+	cols     []flux.ColMeta
+	runs     []*table.SpillFile
+	sortCols []int
+	desc     bool
+	mem      *memory.Allocator
+}
+
+func (t *sortMergeTable) Key() flux.GroupKey {
+	return t.key
+}
+
+func (t *sortMergeTable) Cols() []flux.ColMeta {
+	return t.cols
+}
+
+func (t *sortMergeTable) Empty() bool {
+	for _, run := range t.runs {
+		if run.Len() > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *sortMergeTable) Done() {
+	if atomic.CompareAndSwapInt32(&t.used, 0, 1) {
+		t.release()
+	}
+}
+
+func (t *sortMergeTable) release() {
+	for _, run := range t.runs {
+		_ = run.Remove()
+	}
+}
+
+func (t *sortMergeTable) Do(f func(flux.ColReader) error) error {
+	if !atomic.CompareAndSwapInt32(&t.used, 0, 1) {
+		return errors.New(codes.Internal, "table already read")
+	}
+	defer t.release()
+
+	h := &sortMergeHeap{
+		sortCols: t.sortCols,
+		desc:     t.desc,
+	}
+	defer func() {
+		for _, c := range h.cursors {
+			c.close()
+		}
+	}()
+	for i, run := range t.runs {
+		r, err := run.Open(t.key, t.mem)
+		if err != nil {
+			return err
+		}
+		c := &sortMergeCursor{r: r, run: i}
+		if ok, err := c.next(); err != nil {
+			c.close()
+			return err
+		} else if !ok {
+			c.close()
+			continue
+		}
+		h.cursors = append(h.cursors, c)
+	}
+	heap.Init(h)
+
+	// The builders of the current buffer are released
+	// when it is flushed or when the merge fails.
+	builders := make([]array.Builder, len(t.cols))
+	defer func() {
+		for _, b := range builders {
+			if b != nil {
+				b.Release()
+			}
+		}
+	}()
+	newBuffer := func() {
+		for j, c := range t.cols {
+			builders[j] = arrow.NewBuilder(c.Type, t.mem)
+			builders[j].Reserve(sortMergeBufferSize)
+		}
+	}
+	flush := func() error {
+		buf := &arrow.TableBuffer{
+			GroupKey: t.key,
+			Columns:  t.cols,
+			Values:   make([]array.Interface, len(t.cols)),
+		}
+		for j, b := range builders {
+			buf.Values[j] = b.NewArray()
+			b.Release()
+			builders[j] = nil
+		}
+		err := f(buf)
+		buf.Release()
+		return err
+	}
+
+	newBuffer()
+	n := 0
+	for h.Len() > 0 {
+		c := h.cursors[0]
+		for j := range t.cols {
+			appendSortValue(builders[j], c.buf.Values[j], c.i)
+		}
+		if n++; n == sortMergeBufferSize {
+			if err := flush(); err != nil {
+				return err
+			}
+			newBuffer()
+			n = 0
+		}
+
+		if ok, err := c.next(); err != nil {
+			return err
+		} else if ok {
+			heap.Fix(h, 0)
+		} else {
+			c.close()
+			heap.Pop(h)
+		}
+	}
+	if n > 0 {
+		return flush()
+	}
+	return nil
+}
+
+// sortMergeCursor points to the current row of a sorted run.
+type sortMergeCursor struct {
+	r   *table.SpillReader
+	run int
+	buf *arrow.TableBuffer
+	i   int
+}
+
+// next advances the cursor to the next row and
+// reports whether there is another row in the run.
+func (c *sortMergeCursor) next() (bool, error) {
+	if c.buf != nil {
+		if c.i++; c.i < c.buf.Len() {
+			return true, nil
+		}
+		c.buf.Release()
+		c.buf = nil
+	}
+
+	for {
+		buf, err := c.r.Read()
+		if err == io.EOF {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		if buf.Len() > 0 {
+			c.buf, c.i = buf, 0
+			return true, nil
+		}
+		buf.Release()
+	}
+}
+
+func (c *sortMergeCursor) close() {
+	if c.buf != nil {
+		c.buf.Release()
+		c.buf = nil
+	}
+	if c.r != nil {
+		_ = c.r.Close()
+		c.r = nil
+	}
+}
+
+// sortMergeHeap orders the cursors by their current row.
+// Rows are ordered the same way ColListTableBuilder.Sort orders them
+// so null values come first regardless of the sort direction.
+// Equal rows are ordered by the run they came from.
+type sortMergeHeap struct {
+	cursors  []*sortMergeCursor
+	sortCols []int
+	desc     bool
+}
+
+func (h *sortMergeHeap) Len() int {
+	return len(h.cursors)
+}
+
+func (h *sortMergeHeap) Less(i, j int) bool {
+	x, y := h.cursors[i], h.cursors[j]
+	for _, col := range h.sortCols {
+		xarr, yarr := x.buf.Values[col], y.buf.Values[col]
+		if xnull, ynull := xarr.IsNull(x.i), yarr.IsNull(y.i); xnull || ynull {
+			if xnull && ynull {
+				continue
+			}
+			return xnull
+		}
+		if cmp := compareSortValues(xarr, x.i, yarr, y.i); cmp != 0 {
+			if h.desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+	}
+	return x.run < y.run
+}
+
+func (h *sortMergeHeap) Swap(i, j int) {
+	h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i]
+}
+
+func (h *sortMergeHeap) Push(x interface{}) {
+	h.cursors = append(h.cursors, x.(*sortMergeCursor))
+}
+
+func (h *sortMergeHeap) Pop() interface{} {
+	n := len(h.cursors) - 1
+	c := h.cursors[n]
+	h.cursors = h.cursors[:n]
+	return c
+}
+
+// compareSortValues compares two valid values within arrays of the same type.
+func compareSortValues(x array.Interface, i int, y array.Interface, j int) int {
+	switch x := x.(type) {
+	case *array.Int64:
+		return compareOrdered(x.Value(i) < y.(*array.Int64).Value(j), x.Value(i) > y.(*array.Int64).Value(j))
+	case *array.Uint64:
+		return compareOrdered(x.Value(i) < y.(*array.Uint64).Value(j), x.Value(i) > y.(*array.Uint64).Value(j))
+	case *array.Float64:
+		return compareOrdered(x.Value(i) < y.(*array.Float64).Value(j), x.Value(i) > y.(*array.Float64).Value(j))
+	case *array.Binary:
+		return strings.Compare(x.ValueString(i), y.(*array.Binary).ValueString(j))
+	case *array.Boolean:
+		xv, yv := x.Value(i), y.(*array.Boolean).Value(j)
+		return compareOrdered(!xv && yv, xv && !yv)
+	default:
+		panic(fmt.Sprintf("unexpected array type: %T", x))
+	}
+}
+
+func compareOrdered(less, greater bool) int {
+	if less {
+		return -1
+	} else if greater {
+		return 1
+	}
+	return 0
+}
+
+// appendSortValue appends the value at index i of the array to the builder.
+func appendSortValue(b array.Builder, arr array.Interface, i int) {
+	if arr.IsNull(i) {
+		b.AppendNull()
+		return
+	}
+	switch arr := arr.(type) {
+	case *array.Int64:
+		b.(*array.Int64Builder).Append(arr.Value(i))
+	case *array.Uint64:
+		b.(*array.Uint64Builder).Append(arr.Value(i))
+	case *array.Float64:
+		b.(*array.Float64Builder).Append(arr.Value(i))
+	case *array.Binary:
+		b.(*array.BinaryBuilder).Append(arr.Value(i))
+	case *array.Boolean:
+		b.(*array.BooleanBuilder).Append(arr.Value(i))
+	default:
+		panic(fmt.Sprintf("unexpected array type: %T", arr))
+	}
+}
@@ -1,11 +1,15 @@
 package universe_test
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/dependencies/filesystem"
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/memory"
 	"github.com/influxdata/flux/querytest"
 	"github.com/influxdata/flux/stdlib/universe"
 )
@@ -406,3 +410,171 @@ func TestSort_Process(t *testing.T) {
 		})
 	}
 }
+
+func TestSort_Process_Spill(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec *universe.SortProcedureSpec
+		data []flux.Table
+		want []*executetest.Table
+	}{
+		{
+			name: "ascending with nulls",
+			spec: &universe.SortProcedureSpec{
+				Columns: []string{"_value"},
+			},
+			data: []flux.Table{&executetest.RowWiseTable{Table: &executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), 4.0},
+					{execute.Time(2), nil},
+					{execute.Time(3), 1.0},
+					{execute.Time(4), 3.0},
+					{execute.Time(5), 2.0},
+				},
+			}}},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TFloat},
+				},
+				Data: [][]interface{}{
+					{execute.Time(2), nil},
+					{execute.Time(3), 1.0},
+					{execute.Time(5), 2.0},
+					{execute.Time(4), 3.0},
+					{execute.Time(1), 4.0},
+				},
+			}},
+		},
+		{
+			name: "descending with nulls",
+			spec: &universe.SortProcedureSpec{
+				Columns: []string{"_value"},
+				Desc:    true,
+			},
+			data: []flux.Table{&executetest.RowWiseTable{Table: &executetest.Table{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{execute.Time(1), "b"},
+					{execute.Time(2), "d"},
+					{execute.Time(3), nil},
+					{execute.Time(4), "a"},
+					{execute.Time(5), "c"},
+				},
+			}}},
+			want: []*executetest.Table{{
+				ColMeta: []flux.ColMeta{
+					{Label: "_time", Type: flux.TTime},
+					{Label: "_value", Type: flux.TString},
+				},
+				Data: [][]interface{}{
+					{execute.Time(3), nil},
+					{execute.Time(2), "d"},
+					{execute.Time(5), "c"},
+					{execute.Time(1), "b"},
+					{execute.Time(4), "a"},
+				},
+			}},
+		},
+		{
+			name: "multiple columns and tables",
+			spec: &universe.SortProcedureSpec{
+				Columns: []string{"host", "_value"},
+			},
+			data: []flux.Table{
+				&executetest.RowWiseTable{Table: &executetest.Table{
+					KeyCols: []string{"_measurement"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+						{Label: "_value", Type: flux.TInt},
+					},
+					Data: [][]interface{}{
+						{"m0", "b", int64(2)},
+						{"m0", "a", int64(3)},
+						{"m0", "b", int64(1)},
+						{"m0", "a", int64(1)},
+					},
+				}},
+				&executetest.RowWiseTable{Table: &executetest.Table{
+					KeyCols: []string{"_measurement"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+						{Label: "_value", Type: flux.TInt},
+					},
+					Data: [][]interface{}{
+						{"m1", "c", int64(5)},
+						{"m1", "a", int64(4)},
+					},
+				}},
+			},
+			want: []*executetest.Table{
+				{
+					KeyCols: []string{"_measurement"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+						{Label: "_value", Type: flux.TInt},
+					},
+					Data: [][]interface{}{
+						{"m0", "a", int64(1)},
+						{"m0", "a", int64(3)},
+						{"m0", "b", int64(1)},
+						{"m0", "b", int64(2)},
+					},
+				},
+				{
+					KeyCols: []string{"_measurement"},
+					ColMeta: []flux.ColMeta{
+						{Label: "_measurement", Type: flux.TString},
+						{Label: "host", Type: flux.TString},
+						{Label: "_value", Type: flux.TInt},
+					},
+					Data: [][]interface{}{
+						{"m1", "a", int64(4)},
+						{"m1", "c", int64(5)},
+					},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "flux-sort-spill")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			executetest.ProcessTestHelper2(
+				t,
+				tc.data,
+				tc.want,
+				nil,
+				func(id execute.DatasetID, alloc *memory.Allocator) (execute.Transformation, execute.Dataset) {
+					// Use a limit that is always exceeded so
+					// every buffer is spilled to disk.
+					limit := int64(1)
+					alloc.Limit = &limit
+					alloc.Manager = memory.NewSpillManager(1<<20, dir)
+					return universe.NewSortSpillTransformation(id, tc.spec, alloc, filesystem.SystemFS, dir)
+				},
+			)
+
+			if files, err := ioutil.ReadDir(dir); err != nil {
+				t.Fatal(err)
+			} else if len(files) != 0 {
+				t.Errorf("expected spill files to be removed, found %d", len(files))
+			}
+		})
+	}
+}
@@ -133,7 +133,6 @@ func (s *WindowOpSpec) Kind() flux.OperationKind {
 }
 
 type WindowProcedureSpec struct {
-	plan.DefaultCost
 	Window plan.WindowSpec
 	TimeColumn,
 	StartColumn,
@@ -173,6 +172,15 @@ func (s *WindowProcedureSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+// Cost estimates that every row may be placed in a new window,
+// so the number of groups is at most the number of rows.
+func (s *WindowProcedureSpec) Cost(inStats []plan.Statistics) (plan.Cost, plan.Statistics) {
+	in := plan.CombineStatistics(inStats...)
+	out := in
+	out.GroupCardinality = in.Cardinality
+	return plan.Cost{CPU: in.Cardinality}, out
+}
+
 func createWindowTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
 	s, ok := spec.(*WindowProcedureSpec)
 	if !ok {
@@ -0,0 +1,207 @@
+package vet
+
+import (
+	"fmt"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/stdlib/influxdata/influxdb"
+	"github.com/influxdata/flux/stdlib/universe"
+)
+
+func init() {
+	RegisterRules(
+		DeprecatedImplementationRule{},
+		FilterPushdownRule{},
+		FromWithoutRangeRule{},
+	)
+}
+
+// DeprecatedImplementationRule reports procedures that run with their
+// deprecated implementation, such as the deprecated implementations of
+// fill, drop, keep, rename and duplicate. A script cannot choose these
+// implementations. The application that runs Flux selects them with a
+// logical planner rule that calls universe.UseDeprecatedImpl, so the
+// rule only reports issues when such a planner rule is registered.
+type DeprecatedImplementationRule struct{}
+
+func (DeprecatedImplementationRule) Name() string {
+	return "deprecated-implementation"
+}
+
+func (DeprecatedImplementationRule) Doc() string {
+	return "reports functions that are planned to run with their deprecated implementation"
+}
+
+func (r DeprecatedImplementationRule) CheckPlan(spec *plan.Spec) []Issue {
+	var issues []Issue
+	walkPlan(spec, func(node plan.Node) {
+		s, ok := node.ProcedureSpec().(*universe.DualImplProcedureSpec)
+		if !ok || !s.UseDeprecated {
+			return
+		}
+		name := functionName(node)
+		issues = append(issues, Issue{
+			Rule:     r.Name(),
+			Location: location(node),
+			Message:  fmt.Sprintf("%s runs with its deprecated implementation", name),
+			Fix:      fmt.Sprintf("check that the results do not depend on the deprecated behavior of %s", name),
+		})
+	})
+	return issues
+}
+
+// FilterPushdownRule reports filters that follow a pivot or a map
+// applied to data read from InfluxDB. A filter may only be pushed
+// down to the storage source when it directly follows the source,
+// as MergeRemoteFilterRule requires.
+type FilterPushdownRule struct{}
+
+func (FilterPushdownRule) Name() string {
+	return "filter-pushdown"
+}
+
+func (FilterPushdownRule) Doc() string {
+	return "reports filters after pivot or map that cannot be pushed down to from"
+}
+
+// pushdownBlockers are the procedure kinds that stop
+// a later filter from being pushed down to the source.
+var pushdownBlockers = map[plan.ProcedureKind]bool{
+	universe.PivotKind: true,
+	universe.MapKind:   true,
+}
+
+// pushdownTransparent are the procedure kinds that a filter may
+// follow and still be pushed down once they are merged into the source.
+var pushdownTransparent = map[plan.ProcedureKind]bool{
+	universe.RangeKind:  true,
+	universe.FilterKind: true,
+}
+
+func (r FilterPushdownRule) CheckPlan(spec *plan.Spec) []Issue {
+	var issues []Issue
+	walkPlan(spec, func(node plan.Node) {
+		if node.Kind() != universe.FilterKind {
+			return
+		}
+		// Find the nearest procedure before the filter
+		// that keeps it from being merged into the source.
+		var blocker plan.Node
+		pred := predecessor(node)
+		for pred != nil && !isFrom(pred) {
+			if pushdownBlockers[pred.Kind()] {
+				if blocker == nil {
+					blocker = pred
+				}
+			} else if !pushdownTransparent[pred.Kind()] {
+				return
+			}
+			pred = predecessor(pred)
+		}
+		if pred == nil || blocker == nil {
+			return
+		}
+		name := functionName(blocker)
+		issues = append(issues, Issue{
+			Rule:     r.Name(),
+			Location: location(node),
+			Message:  fmt.Sprintf("filter after %s cannot be pushed down to from", name),
+			Fix:      fmt.Sprintf("move the filter before %s if its predicate only uses columns read by from", name),
+		})
+	})
+	return issues
+}
+
+// FromWithoutRangeRule reports reads from InfluxDB that are not
+// bounded by a range, which read all of the data in the bucket or fail.
+type FromWithoutRangeRule struct{}
+
+func (FromWithoutRangeRule) Name() string {
+	return "from-without-range"
+}
+
+func (FromWithoutRangeRule) Doc() string {
+	return "reports calls to from that are not followed by range before other transformations"
+}
+
+func (r FromWithoutRangeRule) CheckPlan(spec *plan.Spec) []Issue {
+	var issues []Issue
+	walkPlan(spec, func(node plan.Node) {
+		if !isFrom(node) {
+			return
+		}
+		if s, ok := node.ProcedureSpec().(*influxdb.FromRemoteProcedureSpec); ok && !s.Bounds.IsEmpty() {
+			return
+		}
+		if !boundedByRange(node) {
+			issues = append(issues, Issue{
+				Rule:     r.Name(),
+				Location: location(node),
+				Message:  "from is not followed by range",
+				Fix:      "add a range after from, before any transformation other than filter, to bound the data that is read",
+			})
+		}
+	})
+	return issues
+}
+
+// boundedByRange reports whether every path from the node reaches
+// a range before a transformation that keeps the range from being
+// merged into from. Only a range that can be merged bounds the read.
+func boundedByRange(node plan.Node) bool {
+	succs := node.Successors()
+	if len(succs) == 0 {
+		return false
+	}
+	for _, succ := range succs {
+		switch {
+		case succ.Kind() == universe.RangeKind:
+		case pushdownTransparent[succ.Kind()] && boundedByRange(succ):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// walkPlan calls f for each node of the plan.
+func walkPlan(spec *plan.Spec, f func(node plan.Node)) {
+	_ = spec.TopDownWalk(func(node plan.Node) error {
+		f(node)
+		return nil
+	})
+}
+
+// predecessor returns the only predecessor of the node,
+// or nil if the node has none or more than one.
+func predecessor(node plan.Node) plan.Node {
+	if preds := node.Predecessors(); len(preds) == 1 {
+		return preds[0]
+	}
+	return nil
+}
+
+// isFrom reports whether the node reads from InfluxDB.
+func isFrom(node plan.Node) bool {
+	kind := node.Kind()
+	return kind == influxdb.FromKind || kind == influxdb.FromRemoteKind
+}
+
+// location returns the location of the call within the script that
+// created the node. When the node was created by a function of the
+// standard library, this is the location of the call to that function.
+func location(node plan.Node) ast.SourceLocation {
+	if stack := node.CallStack(); len(stack) > 0 {
+		return stack[len(stack)-1].Location
+	}
+	return ast.SourceLocation{}
+}
+
+// functionName returns the name of the function that created the node.
+func functionName(node plan.Node) string {
+	if stack := node.CallStack(); len(stack) > 0 && stack[0].FunctionName != "" {
+		return stack[0].FunctionName
+	}
+	return string(node.Kind())
+}
@@ -0,0 +1,290 @@
+package vet
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/semantic"
+)
+
+func init() {
+	RegisterRules(
+		UnusedBindingRule{},
+		DuplicateYieldRule{},
+		DeprecatedParameterRule{},
+	)
+}
+
+// UnusedBindingRule reports imports and variables that are never
+// referenced. The top-level bindings of a package other than main
+// are exported, so only those of the main package are reported.
+type UnusedBindingRule struct{}
+
+func (UnusedBindingRule) Name() string {
+	return "unused-binding"
+}
+
+func (UnusedBindingRule) Doc() string {
+	return "reports imports and variables that are never used"
+}
+
+func (r UnusedBindingRule) CheckSemantic(pkg *semantic.Package) []Issue {
+	v := &bindingVisitor{main: pkg.Package == "main"}
+	// The package scope holds the top-level
+	// bindings, which are shared by all files.
+	v.push()
+	semantic.Walk(v, pkg)
+	v.pop()
+	return v.issues
+}
+
+// binding is an import or a variable declared within a scope.
+type binding struct {
+	name   string
+	loc    ast.SourceLocation
+	isPkg  bool
+	used   bool
+	report bool
+}
+
+// bindingVisitor resolves the identifiers to the bindings
+// that are visible where they are used.
+type bindingVisitor struct {
+	main   bool
+	scopes [][]*binding
+	// params are the parameters of the functions
+	// keyed by the blocks that are their bodies.
+	params map[*semantic.Block][]*binding
+	// options are the assignments of option statements,
+	// which may be used outside of the script.
+	options map[semantic.Node]bool
+	issues  []Issue
+}
+
+func (v *bindingVisitor) push(bindings ...*binding) {
+	v.scopes = append(v.scopes, bindings)
+}
+
+func (v *bindingVisitor) pop() {
+	scope := v.scopes[len(v.scopes)-1]
+	v.scopes = v.scopes[:len(v.scopes)-1]
+	for _, b := range scope {
+		if b.used || !b.report {
+			continue
+		}
+		issue := Issue{
+			Rule:     UnusedBindingRule{}.Name(),
+			Location: b.loc,
+			Message:  fmt.Sprintf("%s is declared but never used", b.name),
+			Fix:      fmt.Sprintf("remove %s or use it", b.name),
+		}
+		if b.isPkg {
+			issue.Message = fmt.Sprintf("package %s is imported but never used", b.name)
+			issue.Fix = "remove the import"
+		}
+		v.issues = append(v.issues, issue)
+	}
+}
+
+// declare adds the binding to the innermost scope. The top-level
+// variables of a file are added to the package scope instead.
+func (v *bindingVisitor) declare(b *binding) {
+	i := len(v.scopes) - 1
+	if i == 1 && !b.isPkg {
+		i = 0
+	}
+	v.scopes[i] = append(v.scopes[i], b)
+}
+
+// use marks the innermost binding with the name as used.
+func (v *bindingVisitor) use(name string) {
+	for i := len(v.scopes) - 1; i >= 0; i-- {
+		scope := v.scopes[i]
+		for j := len(scope) - 1; j >= 0; j-- {
+			if scope[j].name == name {
+				scope[j].used = true
+				return
+			}
+		}
+	}
+}
+
+func (v *bindingVisitor) Visit(node semantic.Node) semantic.Visitor {
+	switch n := node.(type) {
+	case *semantic.File:
+		// Imports are only visible within their file.
+		v.push()
+		for _, imp := range n.Imports {
+			v.declare(&binding{
+				name:   importName(imp),
+				loc:    imp.Location(),
+				isPkg:  true,
+				report: true,
+			})
+		}
+	case *semantic.OptionStatement:
+		if v.options == nil {
+			v.options = make(map[semantic.Node]bool)
+		}
+		v.options[n.Assignment] = true
+	case *semantic.FunctionExpression:
+		var params []*binding
+		if n.Parameters != nil {
+			for _, p := range n.Parameters.List {
+				params = append(params, &binding{name: p.Key.Name})
+			}
+			if n.Parameters.Pipe != nil {
+				params = append(params, &binding{name: n.Parameters.Pipe.Name})
+			}
+		}
+		if v.params == nil {
+			v.params = make(map[*semantic.Block][]*binding)
+		}
+		v.params[n.Block] = params
+	case *semantic.Block:
+		// Blocks are the bodies of functions, which
+		// are evaluated in the scope of the parameters.
+		v.push(v.params[n]...)
+		delete(v.params, n)
+	case *semantic.IdentifierExpression:
+		v.use(n.Name)
+	}
+	return v
+}
+
+func (v *bindingVisitor) Done(node semantic.Node) {
+	switch n := node.(type) {
+	case *semantic.File, *semantic.Block:
+		v.pop()
+	case *semantic.NativeVariableAssignment:
+		// The variable is declared after its initializer
+		// so that the initializer uses any outer binding.
+		if v.options[n] {
+			return
+		}
+		v.declare(&binding{
+			name:   n.Identifier.Name,
+			loc:    n.Identifier.Location(),
+			report: v.main || len(v.scopes) > 2,
+		})
+	}
+}
+
+// importName returns the name that the package of the import is bound to.
+func importName(imp *semantic.ImportDeclaration) string {
+	if imp.As != nil && imp.As.Name != "" {
+		return imp.As.Name
+	}
+	return path.Base(imp.Path.Value)
+}
+
+// DuplicateYieldRule reports calls to yield with a name that
+// an earlier call to yield already used. The query fails to
+// plan when two results have the same name.
+type DuplicateYieldRule struct{}
+
+func (DuplicateYieldRule) Name() string {
+	return "duplicate-yield"
+}
+
+func (DuplicateYieldRule) Doc() string {
+	return "reports calls to yield that reuse the name of another result"
+}
+
+func (r DuplicateYieldRule) CheckSemantic(pkg *semantic.Package) []Issue {
+	var issues []Issue
+	names := make(map[string]ast.SourceLocation)
+	semantic.Walk(semantic.CreateVisitor(func(node semantic.Node) {
+		call, ok := node.(*semantic.CallExpression)
+		if !ok || calleeName(call) != "yield" {
+			return
+		}
+		name := "_result"
+		if v, ok := argument(call, "name").(*semantic.StringLiteral); ok {
+			name = v.Value
+		} else if argument(call, "name") != nil {
+			// The name is not known until the script is evaluated.
+			return
+		}
+		if first, ok := names[name]; ok {
+			issues = append(issues, Issue{
+				Rule:     r.Name(),
+				Location: call.Location(),
+				Message:  fmt.Sprintf("yield name %q is already used at %v", name, first.Start),
+				Fix:      "give each call to yield a unique name",
+			})
+			return
+		}
+		names[name] = call.Location()
+	}), pkg)
+	return issues
+}
+
+// DeprecatedParameterRule reports arguments for deprecated
+// parameters of builtin functions.
+type DeprecatedParameterRule struct{}
+
+func (DeprecatedParameterRule) Name() string {
+	return "deprecated-parameter"
+}
+
+func (DeprecatedParameterRule) Doc() string {
+	return "reports arguments for deprecated parameters of builtin functions"
+}
+
+// deprecatedParameters maps the builtin functions to their deprecated
+// parameters and the suggested fix for each of them.
+var deprecatedParameters = map[string]map[string]string{
+	"map": {
+		"mergeKey": "remove mergeKey and group the output of map if it is needed",
+	},
+}
+
+func (r DeprecatedParameterRule) CheckSemantic(pkg *semantic.Package) []Issue {
+	var issues []Issue
+	semantic.Walk(semantic.CreateVisitor(func(node semantic.Node) {
+		call, ok := node.(*semantic.CallExpression)
+		if !ok || call.Arguments == nil {
+			return
+		}
+		fn := calleeName(call)
+		params, ok := deprecatedParameters[fn]
+		if !ok {
+			return
+		}
+		for _, p := range call.Arguments.Properties {
+			if fix, ok := params[p.Key.Key()]; ok {
+				issues = append(issues, Issue{
+					Rule:     r.Name(),
+					Location: p.Location(),
+					Message:  fmt.Sprintf("the %s parameter of %s is deprecated", p.Key.Key(), fn),
+					Fix:      fix,
+				})
+			}
+		}
+	}), pkg)
+	return issues
+}
+
+// calleeName returns the name of the function called
+// by an identifier, or an empty string otherwise.
+func calleeName(call *semantic.CallExpression) string {
+	if id, ok := call.Callee.(*semantic.IdentifierExpression); ok {
+		return id.Name
+	}
+	return ""
+}
+
+// argument returns the value of the named argument of the call, if any.
+func argument(call *semantic.CallExpression, name string) semantic.Expression {
+	if call.Arguments == nil {
+		return nil
+	}
+	for _, p := range call.Arguments.Properties {
+		if p.Key.Key() == name {
+			return p.Value
+		}
+	}
+	return nil
+}
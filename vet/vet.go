@@ -0,0 +1,199 @@
+// Package vet reports likely mistakes in Flux scripts.
+//
+// A Checker runs two kinds of rules. Semantic rules inspect the
+// semantic graph of a script, such as bindings that are never used.
+// Plan rules inspect the plan of a query, such as a filter that
+// cannot be pushed down to the storage source. Each issue names
+// the rule that reported it, so rules can be enabled or disabled.
+//
+// The deprecated implementations of fill, drop, keep, rename and
+// duplicate are not called by name from a script. The application
+// that runs Flux selects them with a planner rule, so the
+// deprecated-implementation rule only reports them when the
+// plan is created with such a rule registered.
+package vet
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/internal/errors"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+)
+
+// Issue is a likely mistake found in a script.
+type Issue struct {
+	// Rule is the name of the rule that reported the issue.
+	Rule string
+	// Location is the location of the mistake within the script.
+	// It is not valid if the location is unknown.
+	Location ast.SourceLocation
+	// Message describes the mistake.
+	Message string
+	// Fix suggests how to fix the mistake.
+	Fix string
+}
+
+func (i Issue) String() string {
+	if !i.Location.IsValid() {
+		return fmt.Sprintf("%s (%s)", i.Message, i.Rule)
+	}
+	return fmt.Sprintf("%v: %s (%s)", i.Location, i.Message, i.Rule)
+}
+
+// Rule is a check for a kind of mistake.
+// It is either a SemanticRule or a PlanRule.
+type Rule interface {
+	// Name returns the name that identifies the rule.
+	Name() string
+	// Doc describes the mistake that the rule reports.
+	Doc() string
+}
+
+// SemanticRule is a rule that checks the semantic graph of a script.
+type SemanticRule interface {
+	Rule
+	CheckSemantic(pkg *semantic.Package) []Issue
+}
+
+// PlanRule is a rule that checks the plan of a query.
+type PlanRule interface {
+	Rule
+	CheckPlan(spec *plan.Spec) []Issue
+}
+
+var registeredRules = make(map[string]Rule)
+
+// RegisterRules registers the rules so that checkers run them by default.
+func RegisterRules(rules ...Rule) {
+	for _, rule := range rules {
+		if _, ok := registeredRules[rule.Name()]; ok {
+			panic(fmt.Errorf("duplicate registration for vet rule %q", rule.Name()))
+		}
+		switch rule.(type) {
+		case SemanticRule, PlanRule:
+		default:
+			panic(fmt.Errorf("vet rule %q is neither a semantic nor a plan rule", rule.Name()))
+		}
+		registeredRules[rule.Name()] = rule
+	}
+}
+
+// Rules returns the registered rules sorted by name.
+func Rules() []Rule {
+	rules := make([]Rule, 0, len(registeredRules))
+	for _, rule := range registeredRules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Name() < rules[j].Name()
+	})
+	return rules
+}
+
+// Option configures a Checker.
+type Option interface {
+	apply(*Checker)
+}
+
+type option func(*Checker)
+
+func (opt option) apply(c *Checker) {
+	opt(c)
+}
+
+// OnlyRules runs only the named rules.
+func OnlyRules(names ...string) Option {
+	return option(func(c *Checker) {
+		c.only = append(c.only, names...)
+	})
+}
+
+// DisableRules disables the named rules.
+func DisableRules(names ...string) Option {
+	return option(func(c *Checker) {
+		c.disabled = append(c.disabled, names...)
+	})
+}
+
+// Checker checks scripts with a set of rules.
+type Checker struct {
+	rules []Rule
+
+	only     []string
+	disabled []string
+}
+
+// New creates a checker that runs the registered rules.
+// It returns an error if an option names an unknown rule.
+func New(opts ...Option) (*Checker, error) {
+	c := new(Checker)
+	for _, opt := range opts {
+		opt.apply(c)
+	}
+	for _, name := range append(c.only, c.disabled...) {
+		if _, ok := registeredRules[name]; !ok {
+			return nil, errors.Newf(codes.Invalid, "unknown vet rule %q", name)
+		}
+	}
+
+	enabled := make(map[string]bool, len(registeredRules))
+	for name := range registeredRules {
+		enabled[name] = len(c.only) == 0
+	}
+	for _, name := range c.only {
+		enabled[name] = true
+	}
+	for _, name := range c.disabled {
+		enabled[name] = false
+	}
+	for _, rule := range Rules() {
+		if enabled[rule.Name()] {
+			c.rules = append(c.rules, rule)
+		}
+	}
+	return c, nil
+}
+
+// Rules returns the rules run by the checker.
+func (c *Checker) Rules() []Rule {
+	return c.rules
+}
+
+// CheckSemantic runs the semantic rules on the package.
+// The issues are sorted by their location.
+func (c *Checker) CheckSemantic(pkg *semantic.Package) []Issue {
+	var issues []Issue
+	for _, rule := range c.rules {
+		if r, ok := rule.(SemanticRule); ok {
+			issues = append(issues, r.CheckSemantic(pkg)...)
+		}
+	}
+	sortIssues(issues)
+	return issues
+}
+
+// CheckPlan runs the plan rules on the plan.
+// The issues are sorted by their location.
+func (c *Checker) CheckPlan(spec *plan.Spec) []Issue {
+	var issues []Issue
+	for _, rule := range c.rules {
+		if r, ok := rule.(PlanRule); ok {
+			issues = append(issues, r.CheckPlan(spec)...)
+		}
+	}
+	sortIssues(issues)
+	return issues
+}
+
+func sortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Location == issues[j].Location {
+			return issues[i].Rule < issues[j].Rule
+		}
+		return issues[i].Location.Less(issues[j].Location)
+	})
+}
@@ -0,0 +1,244 @@
+package vet_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/flux/execute/executetest"
+	_ "github.com/influxdata/flux/fluxinit/static"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/flux/memory"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/plan/plantest"
+	"github.com/influxdata/flux/runtime"
+	"github.com/influxdata/flux/stdlib/universe"
+	"github.com/influxdata/flux/vet"
+)
+
+// issue describes an issue by its rule and the line where it was found.
+type issue struct {
+	Rule string
+	Line int
+}
+
+func summarize(issues []vet.Issue) []issue {
+	var got []issue
+	for _, i := range issues {
+		got = append(got, issue{Rule: i.Rule, Line: i.Location.Start.Line})
+	}
+	return got
+}
+
+func TestChecker_CheckSemantic(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		want []issue
+	}{
+		{
+			name: "unused bindings",
+			src: `import "strings"
+import "math"
+
+a = 1
+b = a + 1
+f = (x, y) => {
+	z = x
+	return y
+}
+f(x: math.pi, y: 2)`,
+			want: []issue{
+				{Rule: "unused-binding", Line: 1},
+				{Rule: "unused-binding", Line: 5},
+				{Rule: "unused-binding", Line: 7},
+			},
+		},
+		{
+			name: "shadowed binding",
+			src: `x = 1
+f = (x) => x
+f(x: 2)`,
+			want: []issue{
+				{Rule: "unused-binding", Line: 1},
+			},
+		},
+		{
+			name: "options are used",
+			src: `option now = () => 2020-01-01T00:00:00Z
+a = 1
+a`,
+		},
+		{
+			name: "duplicate yields",
+			src: `import "array"
+
+x = array.from(rows: [{v: 1}])
+x |> yield(name: "a")
+x |> yield(name: "b")
+x |> yield(name: "a")
+x |> yield()
+x |> yield(name: "_result")`,
+			want: []issue{
+				{Rule: "duplicate-yield", Line: 6},
+				{Rule: "duplicate-yield", Line: 8},
+			},
+		},
+		{
+			name: "deprecated parameter",
+			src: `import "array"
+array.from(rows: [{v: 1}])
+	|> map(fn: (r) => ({r with v: r.v + 1}), mergeKey: true)`,
+			want: []issue{
+				{Rule: "deprecated-parameter", Line: 3},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			pkg, err := runtime.AnalyzeSource(tc.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, err := vet.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := summarize(c.CheckSemantic(pkg)); !cmp.Equal(tc.want, got) {
+				t.Errorf("unexpected issues -want/+got:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestChecker_CheckPlan(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		want []issue
+	}{
+		{
+			name: "filter after range",
+			src: `from(bucket: "telegraf")
+	|> range(start: -5m)
+	|> filter(fn: (r) => r._measurement == "cpu")`,
+		},
+		{
+			name: "filter after pivot",
+			src: `from(bucket: "telegraf")
+	|> range(start: -5m)
+	|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+	|> filter(fn: (r) => r._measurement == "cpu")`,
+			want: []issue{
+				{Rule: "filter-pushdown", Line: 4},
+			},
+		},
+		{
+			name: "filter after map",
+			src: `from(bucket: "telegraf")
+	|> range(start: -5m)
+	|> map(fn: (r) => ({r with _value: r._value * 2.0}))
+	|> filter(fn: (r) => r._value > 1.0)`,
+			want: []issue{
+				{Rule: "filter-pushdown", Line: 4},
+			},
+		},
+		{
+			name: "from without range",
+			src: `from(bucket: "telegraf")
+	|> filter(fn: (r) => r._measurement == "cpu")`,
+			want: []issue{
+				{Rule: "from-without-range", Line: 1},
+			},
+		},
+		{
+			name: "range after filter",
+			src: `from(bucket: "telegraf")
+	|> filter(fn: (r) => r._measurement == "cpu")
+	|> range(start: -5m)`,
+		},
+		{
+			name: "range after map",
+			src: `from(bucket: "telegraf")
+	|> map(fn: (r) => ({r with _value: r._value * 2.0}))
+	|> range(start: -5m)`,
+			want: []issue{
+				{Rule: "from-without-range", Line: 1},
+			},
+		},
+		{
+			name: "branch without range",
+			src: `data = from(bucket: "telegraf")
+data |> range(start: -5m) |> yield(name: "recent")
+data |> count() |> yield(name: "count")`,
+			want: []issue{
+				{Rule: "from-without-range", Line: 1},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			program, err := lang.Compile(tc.src, runtime.Default, time.Unix(0, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := executetest.NewTestExecuteDependencies().Inject(context.Background())
+			ps, err := program.LogicalPlan(ctx, &memory.Allocator{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, err := vet.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := summarize(c.CheckPlan(ps)); !cmp.Equal(tc.want, got) {
+				t.Errorf("unexpected issues -want/+got:\n%s", cmp.Diff(tc.want, got))
+			}
+		})
+	}
+}
+
+func TestDeprecatedImplementationRule(t *testing.T) {
+	fill := &universe.DualImplProcedureSpec{
+		ProcedureSpec: &universe.FillProcedureSpec{Column: "_value"},
+	}
+	universe.UseDeprecatedImpl(fill)
+	ps := plantest.CreatePlanSpec(&plantest.PlanSpec{
+		Nodes: []plan.Node{
+			plan.CreateLogicalNode("fill", fill),
+			plan.CreateLogicalNode("keep", &universe.DualImplProcedureSpec{
+				ProcedureSpec: &universe.SchemaMutationProcedureSpec{},
+			}),
+		},
+		Edges: [][2]int{{0, 1}},
+	})
+
+	issues := vet.DeprecatedImplementationRule{}.CheckPlan(ps)
+	if len(issues) != 1 {
+		t.Fatalf("expected one issue, got %v", issues)
+	}
+	if want, got := "fill runs with its deprecated implementation", issues[0].Message; want != got {
+		t.Errorf("unexpected message -want/+got:\n\t- %q\n\t+ %q", want, got)
+	}
+}
+
+func TestNew(t *testing.T) {
+	c, err := vet.New(vet.OnlyRules("unused-binding", "duplicate-yield"), vet.DisableRules("duplicate-yield"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, rule := range c.Rules() {
+		names = append(names, rule.Name())
+	}
+	if want := []string{"unused-binding"}; !cmp.Equal(want, names) {
+		t.Errorf("unexpected rules -want/+got:\n%s", cmp.Diff(want, names))
+	}
+
+	if _, err := vet.New(vet.DisableRules("no-such-rule")); err == nil {
+		t.Error("expected an error for an unknown rule")
+	}
+}